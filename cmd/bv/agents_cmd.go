@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/agents"
+)
+
+// Agent-file blurb states reported by `bv agents check`.
+const (
+	agentsStatusCurrent  = "current"
+	agentsStatusMissing  = "missing"
+	agentsStatusOutdated = "outdated"
+	agentsStatusDrifted  = "drifted"
+)
+
+// agentsCheckResult is the --json output of `bv agents check`.
+type agentsCheckResult struct {
+	File    string `json:"file,omitempty"` // Empty when no agent file exists
+	Status  string `json:"status"`
+	Problem string `json:"problem,omitempty"`
+}
+
+// runAgentsCommand implements `bv agents check|update`: the non-interactive
+// counterpart of the AGENTS.md prompt, for CI and scripts. Returns the
+// process exit code.
+func runAgentsCommand(args []string, stdout, stderr io.Writer) int {
+	usage := func() {
+		fmt.Fprintln(stderr, "Usage: bv agents check [--json] | bv agents update")
+		fmt.Fprintln(stderr, "\ncheck   exit 1 if the bv blurb in AGENTS.md (or CLAUDE.md) is missing, outdated or drifted")
+		fmt.Fprintln(stderr, "update  add or rewrite the blurb in place, creating AGENTS.md if there is no agent file")
+	}
+	if len(args) == 0 {
+		usage()
+		return 2
+	}
+	switch args[0] {
+	case "check":
+		return runAgentsCheck(args[1:], stdout, stderr)
+	case "update":
+		return runAgentsUpdate(args[1:], stdout, stderr)
+	case "-h", "-help", "--help":
+		usage()
+		return 0
+	default:
+		fmt.Fprintf(stderr, "bv agents: unknown subcommand %q\n", args[0])
+		usage()
+		return 2
+	}
+}
+
+func runAgentsCheck(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bv agents check", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	jsonOut := fs.Bool("json", false, "Print the result as JSON")
+	if code, ok := parseAgentsFlags(fs, args, stderr); !ok {
+		return code
+	}
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	result := classifyAgentFile(agents.DetectAgentFile(workDir))
+
+	if *jsonOut {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			fmt.Fprintf(stderr, "Error encoding JSON: %v\n", err)
+			return 1
+		}
+	} else if result.Status == agentsStatusCurrent {
+		fmt.Fprintf(stdout, "%s: bv blurb is current (v%d)\n", result.File, agents.BlurbVersion)
+	} else if result.File == "" {
+		fmt.Fprintf(stdout, "%s; run `bv agents update`\n", result.Problem)
+	} else {
+		fmt.Fprintf(stdout, "%s: %s; run `bv agents update`\n", result.File, result.Problem)
+	}
+	if result.Status != agentsStatusCurrent {
+		return 1
+	}
+	return 0
+}
+
+// classifyAgentFile classifies a detected agent file's blurb.
+func classifyAgentFile(d agents.AgentFileDetection) agentsCheckResult {
+	r := agentsCheckResult{File: d.FilePath, Status: agentsStatusCurrent}
+	switch {
+	case !d.Found():
+		r.Status, r.Problem = agentsStatusMissing, "no AGENTS.md or CLAUDE.md found"
+	case d.NeedsBlurb():
+		r.Status, r.Problem = agentsStatusMissing, "bv blurb is missing"
+	case d.HasLegacyBlurb:
+		r.Status, r.Problem = agentsStatusOutdated, fmt.Sprintf("bv blurb uses the legacy format (current is v%d)", agents.BlurbVersion)
+	case agents.NeedsUpdate(d.Content):
+		r.Status, r.Problem = agentsStatusOutdated, fmt.Sprintf("bv blurb is v%d (current is v%d)", d.BlurbVersion, agents.BlurbVersion)
+	case agents.BlurbDrift(d.Content):
+		r.Status, r.Problem = agentsStatusDrifted, fmt.Sprintf("bv blurb v%d was edited and no longer matches this bv", agents.BlurbVersion)
+	}
+	return r
+}
+
+func runAgentsUpdate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bv agents update", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	if code, ok := parseAgentsFlags(fs, args, stderr); !ok {
+		return code
+	}
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	d := agents.DetectAgentFile(workDir)
+	switch result := classifyAgentFile(d); {
+	case !d.Found():
+		path := agents.GetPreferredAgentFilePath(workDir)
+		err = agents.CreateAgentFile(path)
+		if err == nil {
+			fmt.Fprintf(stdout, "%s: created with the bv blurb\n", path)
+		}
+	case result.Status == agentsStatusCurrent:
+		fmt.Fprintf(stdout, "%s: bv blurb is already current\n", d.FilePath)
+	case d.NeedsBlurb():
+		err = agents.AppendBlurbToFile(d.FilePath)
+		if err == nil {
+			fmt.Fprintf(stdout, "%s: added the bv blurb\n", d.FilePath)
+		}
+	case agents.ContainsBlurb(d.Content) && !strings.Contains(d.Content, agents.BlurbEndMarker):
+		// UpdateBlurb cannot tell where the old blurb ends and would add a second one
+		err = fmt.Errorf("%s: bv blurb has no %s line; remove the blurb by hand, then rerun", d.FilePath, agents.BlurbEndMarker)
+	default:
+		err = agents.UpdateBlurbInFile(d.FilePath)
+		if err == nil {
+			fmt.Fprintf(stdout, "%s: updated the %s bv blurb to v%d\n", d.FilePath, result.Status, agents.BlurbVersion)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// parseAgentsFlags parses a subcommand's flags, rejecting positional
+// arguments. ok is false when the caller should exit with code.
+func parseAgentsFlags(fs *flag.FlagSet, args []string, stderr io.Writer) (code int, ok bool) {
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0, false
+		}
+		return 2, false
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "%s: unexpected argument %q\n", fs.Name(), fs.Arg(0))
+		return 2, false
+	}
+	return 0, true
+}
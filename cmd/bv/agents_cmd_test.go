@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/agents"
+)
+
+// writeAgentsTestRepo creates AGENTS.md with content (none if empty) in a
+// fresh working directory.
+func writeAgentsTestRepo(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	path := filepath.Join(dir, "AGENTS.md")
+	if content != "" {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+func runAgents(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := runAgentsCommand(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRunAgentsCheck_States(t *testing.T) {
+	current := agents.AppendBlurb("# Project\n")
+	tests := []struct {
+		name     string
+		content  string
+		wantCode int
+		status   string
+		problem  string
+	}{
+		{"no agent file", "", 1, agentsStatusMissing, "no AGENTS.md or CLAUDE.md found"},
+		{"no blurb", "# Project\n", 1, agentsStatusMissing, "bv blurb is missing"},
+		{"current", current, 0, agentsStatusCurrent, ""},
+		{"outdated version", "# Project\n\n<!-- bv-agent-instructions-v0 -->\nold\n" + agents.BlurbEndMarker + "\n", 1, agentsStatusOutdated, "bv blurb is v0"},
+		{"legacy", "# Project\n\n### Using bv as an AI sidecar\n\nUse --robot-insights and --robot-plan.\nbv already computes the hard parts.\n", 1, agentsStatusOutdated, "legacy format"},
+		{"drifted", strings.Replace(current, "bd ready ", "bd next ", 1), 1, agentsStatusDrifted, "no longer matches"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeAgentsTestRepo(t, tt.content)
+
+			code, stdout, stderr := runAgents(t, "check", "--json")
+			if code != tt.wantCode {
+				t.Fatalf("exit %d, want %d (stdout %q, stderr %q)", code, tt.wantCode, stdout, stderr)
+			}
+			var result agentsCheckResult
+			if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+				t.Fatalf("invalid JSON %q: %v", stdout, err)
+			}
+			if result.Status != tt.status || !strings.Contains(result.Problem, tt.problem) {
+				t.Errorf("result = %+v, want status %s and problem containing %q", result, tt.status, tt.problem)
+			}
+			if tt.content != "" && filepath.Base(result.File) != "AGENTS.md" {
+				t.Errorf("file = %q, want AGENTS.md", result.File)
+			}
+
+			// Plain output names the file and the fix
+			_, stdout, _ = runAgents(t, "check")
+			if tt.wantCode != 0 && !strings.Contains(stdout, "run `bv agents update`") {
+				t.Errorf("plain output %q should point to bv agents update", stdout)
+			}
+		})
+	}
+}
+
+func TestRunAgentsUpdate_FixesEveryState(t *testing.T) {
+	current := agents.AppendBlurb("# Project\n")
+	for name, content := range map[string]string{
+		"no agent file": "",
+		"no blurb":      "# Project\n",
+		"outdated":      "# Project\n\n<!-- bv-agent-instructions-v0 -->\nold\n" + agents.BlurbEndMarker + "\n",
+		"drifted":       strings.Replace(current, "bd ready ", "bd next ", 1),
+		"current":       current,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := writeAgentsTestRepo(t, content)
+			if code, _, stderr := runAgents(t, "update"); code != 0 {
+				t.Fatalf("update exit %d: %s", code, stderr)
+			}
+			if code, stdout, _ := runAgents(t, "check"); code != 0 {
+				t.Fatalf("check after update exit %d: %s", code, stdout)
+			}
+			data, _ := os.ReadFile(path)
+			if n := strings.Count(string(data), agents.BlurbStartMarker); n != 1 {
+				t.Errorf("AGENTS.md has %d blurbs, want 1", n)
+			}
+			if content != "" && !strings.HasPrefix(string(data), "# Project\n") {
+				t.Errorf("update should keep the rest of the file:\n%s", data)
+			}
+		})
+	}
+}
+
+func TestRunAgentsUpdate_RefusesBlurbWithoutEndMarker(t *testing.T) {
+	content := strings.Replace(agents.AppendBlurb("# Project\n"), agents.BlurbEndMarker, "", 1)
+	path := writeAgentsTestRepo(t, content)
+
+	code, _, stderr := runAgents(t, "update")
+	if code != 1 || !strings.Contains(stderr, "no "+agents.BlurbEndMarker) {
+		t.Fatalf("update exit %d, stderr %q; want 1 and a missing end marker error", code, stderr)
+	}
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Error("update should leave the file untouched")
+	}
+}
+
+func TestRunAgentsCommand_Usage(t *testing.T) {
+	for _, args := range [][]string{nil, {"lint"}, {"check", "extra"}} {
+		if code, _, _ := runAgents(t, args...); code != 2 {
+			t.Errorf("bv agents %v: exit %d, want 2", args, code)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// defaultApplyMinConfidence is the default of
+// --apply-priorities-min-confidence.
+const defaultApplyMinConfidence = 0.7
+
+// priorityChangesToApply picks the recommendations --apply-priorities acts on:
+// actual priority changes with confidence at or above minConfidence.
+func priorityChangesToApply(recs []analysis.PriorityRecommendation, minConfidence float64) []analysis.PriorityRecommendation {
+	var picked []analysis.PriorityRecommendation
+	for _, rec := range recs {
+		if rec.SuggestedPriority == rec.CurrentPriority || rec.Confidence < minConfidence {
+			continue
+		}
+		picked = append(picked, rec)
+	}
+	return picked
+}
+
+// runApplyPriorities writes the recommended priorities to the beads JSONL at
+// beadsPath and prints a summary to w. With dryRun it prints the line diff
+// instead and leaves the file untouched.
+func runApplyPriorities(w io.Writer, issues []model.Issue, beadsPath string, minConfidence float64, dryRun bool) error {
+	if beadsPath == "" {
+		return fmt.Errorf("--apply-priorities needs a JSONL beads file (not --as-of, --workspace or a SQLite database)")
+	}
+
+	analyzer := analysis.NewAnalyzer(issues)
+	cfg := analysis.ConfigForSize(len(issues), countEdges(issues))
+	analyzer.SetConfig(&cfg)
+	picked := priorityChangesToApply(analyzer.GenerateRecommendations(), minConfidence)
+	if len(picked) == 0 {
+		fmt.Fprintf(w, "No priority recommendations with confidence >= %.2f; nothing to change.\n", minConfidence)
+		return nil
+	}
+
+	priorities := make(map[string]int, len(picked))
+	byID := make(map[string]analysis.PriorityRecommendation, len(picked))
+	for _, rec := range picked {
+		priorities[rec.IssueID] = rec.SuggestedPriority
+		byID[rec.IssueID] = rec
+	}
+	updates, err := loader.UpdatePriorities(beadsPath, priorities, time.Now(), dryRun)
+	if err != nil {
+		return fmt.Errorf("applying priorities: %w", err)
+	}
+
+	if dryRun {
+		fmt.Fprintf(w, "--- %s\n+++ %s (dry run: not written)\n", beadsPath, beadsPath)
+		for _, u := range updates {
+			fmt.Fprintf(w, "@@ line %d: %s P%d -> P%d @@\n-%s\n+%s\n", u.Line, u.ID, u.From, u.To, u.OldLine, u.NewLine)
+		}
+		fmt.Fprintf(w, "Dry run: %d priority change(s) with confidence >= %.2f would be written.\n", len(updates), minConfidence)
+		return nil
+	}
+
+	fmt.Fprintf(w, "Applied %d priority change(s) with confidence >= %.2f to %s:\n", len(updates), minConfidence, beadsPath)
+	for _, u := range updates {
+		rec := byID[u.ID]
+		fmt.Fprintf(w, "  %s: P%d -> P%d (%s, confidence %.2f)\n", u.ID, u.From, u.To, rec.Direction, rec.Confidence)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+)
+
+func TestPriorityChangesToApply_OnlyHighConfidence(t *testing.T) {
+	recs := []analysis.PriorityRecommendation{
+		{IssueID: "A", CurrentPriority: 3, SuggestedPriority: 1, Confidence: 0.9},
+		{IssueID: "B", CurrentPriority: 0, SuggestedPriority: 3, Confidence: 0.5},
+		{IssueID: "C", CurrentPriority: 2, SuggestedPriority: 2, Confidence: 0.95},
+		{IssueID: "D", CurrentPriority: 1, SuggestedPriority: 2, Confidence: 0.7},
+	}
+	picked := priorityChangesToApply(recs, 0.7)
+	if len(picked) != 2 || picked[0].IssueID != "A" || picked[1].IssueID != "D" {
+		t.Fatalf("expected A and D, got %+v", picked)
+	}
+}
+
+func TestRunApplyPriorities_WritesOnlyHighConfidenceChanges(t *testing.T) {
+	// core is P3 but everything waits on it (promotion, confidence 1.0); leaf is
+	// a P0 nothing depends on (demotion, confidence ~0.75).
+	jsonl := `{"id":"core","title":"Core","status":"open","priority":3,"issue_type":"task","labels":["urgent"],"dependencies":[{"issue_id":"core","depends_on_id":"setup","type":"blocks"}]}
+{"id":"setup","title":"Setup","status":"open","priority":2,"issue_type":"task"}
+{"id":"leaf","title":"Leaf","status":"open","priority":0,"issue_type":"task","dependencies":[{"issue_id":"leaf","depends_on_id":"core","type":"blocks"}]}
+{"id":"o1","title":"O1","status":"open","priority":2,"issue_type":"task","updated_at":"2099-01-01T00:00:00Z","dependencies":[{"issue_id":"o1","depends_on_id":"core","type":"blocks"}]}
+{"id":"o2","title":"O2","status":"open","priority":2,"issue_type":"task","updated_at":"2099-01-01T00:00:00Z","dependencies":[{"issue_id":"o2","depends_on_id":"core","type":"blocks"}]}
+`
+	path := filepath.Join(t.TempDir(), "issues.jsonl")
+	if err := os.WriteFile(path, []byte(jsonl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	issues, err := loader.LoadIssuesFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runApplyPriorities(&out, issues, path, 0.9, true); err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != jsonl {
+		t.Fatal("dry run must not write")
+	}
+	if !strings.Contains(out.String(), "-{\"id\":\"core\"") || !strings.Contains(out.String(), "+{\"id\":\"core\",\"title\":\"Core\",\"status\":\"open\",\"priority\":0") {
+		t.Errorf("expected a diff for core, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := runApplyPriorities(&out, issues, path, 0.9, false); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	applied, err := loader.LoadIssuesFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]int{}
+	for _, iss := range applied {
+		got[iss.ID] = iss.Priority
+	}
+	if got["core"] != 0 {
+		t.Errorf("expected core promoted to P0, got P%d\n%s", got["core"], out.String())
+	}
+	if got["leaf"] != 0 {
+		t.Errorf("leaf's demotion is below 0.9 confidence and must not be applied, got P%d", got["leaf"])
+	}
+	if !strings.Contains(out.String(), "core: P3 -> P0") || strings.Contains(out.String(), "leaf:") {
+		t.Errorf("unexpected summary:\n%s", out.String())
+	}
+
+	if err := runApplyPriorities(&out, issues, "", 0.9, false); err == nil {
+		t.Error("expected an error without a JSONL beads file")
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/logging"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
+)
+
+// indexCommandResult is the --json output of `bv index`.
+type indexCommandResult struct {
+	IndexPath string                `json:"index_path"`
+	ModelID   string                `json:"model_id"`
+	Rebuilt   bool                  `json:"rebuilt"`
+	Fallback  bool                  `json:"fallback,omitempty"` // Hash embedder used because the configured one is unavailable
+	Stats     search.IndexSyncStats `json:"stats"`
+}
+
+// runIndexCommand implements `bv index [--rebuild] [--allow-fallback] [--json]`:
+// it builds or updates the on-disk semantic index for the current repo so CI
+// or cron can warm it before agents search. Returns the process exit code.
+func runIndexCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bv index", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	rebuild := fs.Bool("rebuild", false, "Discard the existing index and embed every bead again")
+	allowFallback := fs.Bool("allow-fallback", false, "Use the deterministic hash embedder when the configured embedder is unavailable")
+	jsonOut := fs.Bool("json", false, "Print the result as JSON")
+	dbPath := fs.String("db", "", "Read beads from a bd SQLite database instead of the JSONL export")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: bv index [--rebuild] [--allow-fallback] [--json] [--db <path>]")
+		fmt.Fprintln(stderr, "\nBuild or update the semantic search index under .bv/semantic.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "bv index: unexpected argument %q\n", fs.Arg(0))
+		fs.Usage()
+		return 2
+	}
+
+	issues, err := loadIndexIssues(*dbPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error loading beads: %v\n", err)
+		return 1
+	}
+
+	cfg := search.EmbeddingConfigFromEnv()
+	embedder, err := search.NewEmbedderFromConfig(cfg)
+	fallback := false
+	if err != nil {
+		if !*allowFallback {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		logging.Warn("semantic embedder unavailable; falling back", "error", err, "provider", search.ProviderHash)
+		cfg.Provider, cfg.Model = search.ProviderHash, ""
+		if embedder, err = search.NewEmbedderFromConfig(cfg); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		fallback = true
+	}
+
+	projectDir := projectRoot()
+	indexPath := search.DefaultIndexPath(projectDir, cfg)
+
+	var idx *search.VectorIndex
+	loaded := false
+	if *rebuild {
+		idx = search.NewVectorIndex(embedder.Dim())
+	} else if idx, loaded, err = search.LoadOrNewVectorIndex(indexPath, embedder.Dim()); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	stats, err := search.SyncVectorIndexWithOptions(ctx, idx, embedder, search.DocumentsFromIssues(issues), search.IndexSyncOptions{
+		BatchSize: 64,
+		ModelID:   cfg.ModelID(),
+		MaxAge:    cfg.MaxAge,
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "Error building semantic index: %v\n", err)
+		return 1
+	}
+	if !loaded || stats.Changed() {
+		if err := idx.Save(indexPath); err != nil {
+			fmt.Fprintf(stderr, "Error saving semantic index: %v\n", err)
+			return 1
+		}
+	}
+
+	result := indexCommandResult{
+		IndexPath: indexPath,
+		ModelID:   idx.ModelID,
+		Rebuilt:   !loaded,
+		Fallback:  fallback,
+		Stats:     stats,
+	}
+	if *jsonOut {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			fmt.Fprintf(stderr, "Error encoding index result: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	displayPath := indexPath
+	if rel, err := filepath.Rel(projectDir, indexPath); err == nil {
+		displayPath = rel
+	}
+	verb := "Updated"
+	if result.Rebuilt {
+		verb = "Built"
+	}
+	fmt.Fprintf(stdout, "%s semantic index %s (%s)\n", verb, displayPath, result.ModelID)
+	fmt.Fprintf(stdout, "  %d beads: %d added, %d updated, %d deleted, %d re-embedded, %d unchanged\n",
+		stats.Total, stats.Added, stats.Updated, stats.Removed, stats.Reembedded, stats.Skipped)
+	if stats.ThrottleWaits > 0 {
+		fmt.Fprintf(stdout, "  throttled %d times (%dms) to stay within rate limits\n", stats.ThrottleWaits, stats.ThrottleWaitMs)
+	}
+	return 0
+}
+
+// loadIndexIssues loads the current repo's beads the way the main command
+// does; a project without beads yields an empty index rather than an error.
+func loadIndexIssues(dbPath string) ([]model.Issue, error) {
+	opts := loader.ParseOptions{WarningHandler: func(string) {}}
+	if dbPath != "" {
+		return loader.LoadIssuesFromDB(dbPath, opts)
+	}
+	issues, err := loader.LoadIssuesWithOptions("", opts)
+	if err != nil && loader.IsNoBeadsData(err) {
+		return nil, nil
+	}
+	return issues, err
+}
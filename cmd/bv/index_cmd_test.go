@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeIndexTestRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	beadsDir := filepath.Join(dir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	jsonl := `{"id":"A","title":"Fix login flow","status":"open","priority":1,"issue_type":"bug"}
+{"id":"B","title":"Write docs","status":"open","priority":2,"issue_type":"task"}
+`
+	if err := os.WriteFile(filepath.Join(beadsDir, "beads.jsonl"), []byte(jsonl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+	return dir
+}
+
+func runIndexJSON(t *testing.T, args ...string) (indexCommandResult, int, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := runIndexCommand(append([]string{"--json"}, args...), &stdout, &stderr)
+	var result indexCommandResult
+	if code == 0 {
+		if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+			t.Fatalf("invalid JSON %q: %v", stdout.String(), err)
+		}
+	}
+	return result, code, stderr.String()
+}
+
+func TestRunIndexCommand_BuildsThenUpdates(t *testing.T) {
+	t.Setenv("BV_SEMANTIC_EMBEDDER", "hash")
+	writeIndexTestRepo(t)
+
+	first, code, stderr := runIndexJSON(t)
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	if !first.Rebuilt || first.Stats.Added != 2 || first.Stats.Total != 2 {
+		t.Fatalf("unexpected first run: %+v", first)
+	}
+	if _, err := os.Stat(first.IndexPath); err != nil {
+		t.Fatalf("index not written: %v", err)
+	}
+
+	second, code, stderr := runIndexJSON(t)
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	if second.Rebuilt || second.Stats.Changed() || second.Stats.Skipped != 2 {
+		t.Fatalf("expected an up-to-date index, got %+v", second)
+	}
+
+	rebuilt, code, stderr := runIndexJSON(t, "--rebuild")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	if !rebuilt.Rebuilt || rebuilt.Stats.Added != 2 {
+		t.Fatalf("expected --rebuild to embed everything, got %+v", rebuilt)
+	}
+}
+
+func TestRunIndexCommand_EmbedderUnavailable(t *testing.T) {
+	t.Setenv("BV_SEMANTIC_EMBEDDER", "openai")
+	writeIndexTestRepo(t)
+
+	if _, code, stderr := runIndexJSON(t); code != 1 || !strings.Contains(stderr, "openai") {
+		t.Fatalf("expected exit 1 naming the embedder, got %d: %s", code, stderr)
+	}
+
+	result, code, stderr := runIndexJSON(t, "--allow-fallback")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	if !result.Fallback || !strings.HasPrefix(result.ModelID, "hash") || result.Stats.Added != 2 {
+		t.Fatalf("expected a hash fallback index, got %+v", result)
+	}
+}
@@ -14,6 +14,8 @@ import (
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -30,6 +32,7 @@ import (
 	"github.com/Dicklesworthstone/beads_viewer/pkg/export"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/hooks"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/logging"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
@@ -42,6 +45,16 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "index" {
+		os.Exit(runIndexCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		os.Exit(runServeCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "agents" {
+		os.Exit(runAgentsCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	help := flag.Bool("help", false, "Show help")
 	versionFlag := flag.Bool("version", false, "Show version")
 	// Update flags (bv-182)
@@ -50,14 +63,19 @@ func main() {
 	rollbackFlag := flag.Bool("rollback", false, "Rollback to the previous version (from backup)")
 	yesFlag := flag.Bool("yes", false, "Skip confirmation prompts (use with --update)")
 	exportFile := flag.String("export-md", "", "Export issues to a Markdown file (e.g., report.md)")
+	anonymize := flag.Bool("anonymize", false, "Replace titles with placeholders, strip free text and commit messages, and hash people in all output")
 	robotHelp := flag.Bool("robot-help", false, "Show AI agent help")
+	robotVersion := flag.Bool("robot-version", false, "Output bv version, git commit and build date as JSON")
 	robotInsights := flag.Bool("robot-insights", false, "Output graph analysis and insights as JSON for AI agents")
+	healthWeightsSpec := flag.String("health-weights", "", "Bead health score weights as key=value list, e.g. staleness=0.4,blockers=0.4,priority_mismatch=0.2 (keys: staleness, blockers, critical_path, priority_mismatch; unspecified = 0; affects --robot-insights and --export-graph)")
+	zombieDays := flag.Int("zombie-days", 14, "Days without activity (updates or correlated commits) before an in-progress bead is reported as a zombie in --robot-insights")
 	robotPlan := flag.Bool("robot-plan", false, "Output dependency-respecting execution plan as JSON for AI agents")
 	robotPriority := flag.Bool("robot-priority", false, "Output priority recommendations as JSON for AI agents")
 	robotTriage := flag.Bool("robot-triage", false, "Output unified triage as JSON (the mega-command for AI agents)")
 	robotTriageByTrack := flag.Bool("robot-triage-by-track", false, "Group triage recommendations by execution track (bv-87)")
 	robotTriageByLabel := flag.Bool("robot-triage-by-label", false, "Group triage recommendations by label (bv-87)")
-	robotNext := flag.Bool("robot-next", false, "Output only the top pick recommendation as JSON (minimal triage)")
+	robotNext := flag.Bool("robot-next", false, "Output the single bead to work on next as JSON, ranked without centrality metrics (may differ from the --robot-triage top pick)")
+	robotReady := flag.Bool("robot-ready", false, "Alias for --robot-next, matching the `bd ready` vocabulary")
 	robotDiff := flag.Bool("robot-diff", false, "Output diff as JSON (use with --diff-since)")
 	robotRecipes := flag.Bool("robot-recipes", false, "Output available recipes as JSON for AI agents")
 	robotLabelHealth := flag.Bool("robot-label-health", false, "Output label health metrics as JSON for AI agents")
@@ -70,40 +88,78 @@ func main() {
 	suggestType := flag.String("suggest-type", "", "Filter suggestions by type: duplicate, dependency, label, cycle")
 	suggestConfidence := flag.Float64("suggest-confidence", 0.0, "Minimum confidence for suggestions (0.0-1.0)")
 	suggestBead := flag.String("suggest-bead", "", "Filter suggestions for specific bead ID")
+	suggestLang := flag.String("suggest-lang", "", "Language of bead text for --robot-suggest keyword matching: "+strings.Join(analysis.StopWordLanguages(), ", ")+" (English stop words always apply)")
+	// Structural lint
+	robotLint := flag.Bool("robot-lint", false, "Output structural graph lint findings (cycles, dangling refs, orphans) as JSON")
+	robotSummary := flag.Bool("robot-summary", false, "Output a single-line JSON heartbeat (counts and data_hash); skips centrality metrics")
 	// Graph export (bv-136)
 	robotGraph := flag.Bool("robot-graph", false, "Output dependency graph as JSON/DOT/Mermaid for AI agents")
-	graphFormat := flag.String("graph-format", "json", "Graph output format: json, dot, mermaid")
+	graphFormat := flag.String("graph-format", "json", "Graph output format: json, dot, mermaid, dsm, prometheus")
+	robotMetrics := flag.Bool("robot-metrics", false, "Output backlog health gauges in Prometheus text format (same as --robot-graph --graph-format=prometheus)")
 	graphRoot := flag.String("graph-root", "", "Subgraph from specific root issue ID")
 	graphDepth := flag.Int("graph-depth", 0, "Max depth for subgraph (0 = unlimited)")
+	clusterBy := flag.String("cluster-by", "", "DOT graph clustering: type, status, component (use with --graph-format=dot)")
 	// Graph snapshot export (bv-94)
 	exportGraph := flag.String("export-graph", "", "Export graph: .html for interactive, .png/.svg for static (auto-names if empty)")
+	exportGraphDir := flag.String("export-dir", "", "Export the interactive graph as separate files (index.html, app.js, styles.css, data.json) into a directory")
+	exportGraphJSON := flag.String("export-json", "", "Export the interactive graph's data (nodes, metrics, links, triage, summary) as standalone JSON")
+	maxNodes := flag.Int("max-nodes", 0, "Keep only the N most important beads (and the edges between them) in --export-graph/--export-dir/--export-json; 0 = no limit")
+	maxNodesBy := flag.String("max-nodes-by", "pagerank", "Importance metric for --max-nodes: pagerank, betweenness, critical, indegree, or impact")
 	graphPreset := flag.String("graph-preset", "compact", "Graph layout preset: compact (default) or roomy")
 	graphTitle := flag.String("graph-title", "", "Title for graph export (default: project name)")
+	noAnimation := flag.Bool("no-animation", false, "Disable link particles and animations by default in --export-graph HTML")
+	noSanitize := flag.Bool("no-sanitize", false, "Render raw HTML in bead markdown as written in --export-graph HTML (default: sanitize with DOMPurify; only for trusted repos)")
+	graphTheme := flag.String("theme", "dark", "Default color theme for --export-graph HTML: light, dark, or auto (follows OS)")
+	graphPalette := flag.String("palette", "default", "Color palette for --export-graph HTML: default or cb-safe (colorblind-safe)")
+	criticalColor := flag.String("critical-color", "", "Critical-path edge/node color for --export-graph HTML, e.g. #0ea5e9 (default: pink edges, node's own color halo)")
+	articulationColor := flag.String("articulation-color", "", "Articulation-point glow color for --export-graph HTML (default: #ec4899)")
+	commitURLTemplate := flag.String("commit-url-template", "", "Link related commits in --export-graph HTML, e.g. https://gitlab.example.com/g/p/-/commit/{sha} ({sha}, {short_sha})")
+	issueURLTemplate := flag.String("issue-url-template", "", "Link bead IDs in --export-graph HTML to an external tracker, e.g. https://jira.example.com/browse/{id}")
+	avatarURLTemplate := flag.String("avatar-url-template", "", "Draw assignee avatars on --export-graph HTML nodes, e.g. https://github.com/{assignee}.png (default: initials)")
+	graphTypeConfig := flag.String("type-config", "", "YAML/JSON file registering shape and color per issue type for --export-graph HTML")
+	compressData := flag.Bool("compress-data", false, "Gzip the data embedded in --export-graph HTML (inflated in the browser; smaller files for large graphs)")
 	// Robot output filters (bv-84)
 	robotMinConf := flag.Float64("robot-min-confidence", 0.0, "Filter robot outputs by minimum confidence (0.0-1.0)")
 	robotMaxResults := flag.Int("robot-max-results", 0, "Limit robot output count (0 = use defaults)")
 	robotByLabel := flag.String("robot-by-label", "", "Filter robot outputs by label (exact match)")
+	robotOutput := flag.String("output", "json", "Robot output encoding: json or md (markdown tables for pasting into PRs and docs)")
+	robotFields := flag.String("fields", "", "Project robot JSON to comma-separated dotted paths (e.g. plan.tracks[].items[].id,data_hash)")
 	robotByAssignee := flag.String("robot-by-assignee", "", "Filter robot outputs by assignee (exact match)")
+	wipLimit := flag.Int("wip-limit", 0, "Max in-progress beads before --robot-next/--robot-triage prefer finishing over starting (per assignee with --robot-by-assignee)")
 	// Label subgraph scoping (bv-122)
-	labelScope := flag.String("label", "", "Scope analysis to label's subgraph (affects --robot-insights, --robot-plan, --robot-priority)")
+	labelScope := flag.String("label", "", "Scope analysis to label's subgraph; accepts a glob (team/*) or /regex/ (affects --robot-insights, --robot-plan, --robot-priority)")
+	closedWindowSpec := flag.String("closed-window", analysis.DefaultRecentlyClosedWindow, "How far back --robot-triage's recently_closed section looks: duration (72h, 14d) or date (2024-01-01)")
+	priorityMin := flag.Int("priority-min", -1, "Only report beads with priority >= N, e.g. 1 skips P0 (affects --robot-triage, --robot-next, --robot-priority)")
+	priorityMax := flag.Int("priority-max", -1, "Only report beads with priority <= N, e.g. 1 for P0/P1 only (affects --robot-triage, --robot-next, --robot-priority)")
+	sinceWindowSpec := flag.String("since", "", "Only report beads created/updated within window: duration (72h, 3d) or date (2024-01-01) (affects --robot-triage, --robot-next, --robot-priority)")
 	alertSeverity := flag.String("severity", "", "Filter robot alerts by severity (info|warning|critical)")
 	alertType := flag.String("alert-type", "", "Filter robot alerts by alert type (e.g., stale_issue)")
 	alertLabel := flag.String("alert-label", "", "Filter robot alerts by label match")
 	recipeName := flag.String("recipe", "", "Apply named recipe (e.g., triage, actionable, high-impact)")
 	recipeShort := flag.String("r", "", "Shorthand for --recipe")
+	recipeHistory := flag.Bool("recipe-history", false, "List recent recipe runs from $XDG_CONFIG_HOME/bv/recipe-runs.jsonl (filter with --recipe NAME)")
+	recipeExplain := flag.String("recipe-explain", "", "Show which sources (builtin, user, project) define a recipe and which one wins")
+	recipeHistoryLimit := flag.Int("recipe-history-limit", 20, "Max runs listed by --recipe-history (0 = all)")
 	semanticQuery := flag.String("search", "", "Semantic search query (vector-based; builds/updates index on first run)")
 	robotSearch := flag.Bool("robot-search", false, "Output semantic search results as JSON for AI agents (use with --search)")
 	searchLimit := flag.Int("search-limit", 10, "Max results for --search/--robot-search")
 	searchMode := flag.String("search-mode", "", "Search ranking mode: text or hybrid (default: BV_SEARCH_MODE or text)")
 	searchPreset := flag.String("search-preset", "", "Hybrid preset name (default: BV_SEARCH_PRESET or default)")
+	searchFusion := flag.String("fusion", "", "Combine lexical and semantic rankings: score or rrf (default: BV_SEARCH_FUSION or score)")
 	searchWeights := flag.String("search-weights", "", "Hybrid weights JSON (overrides preset; keys: text,pagerank,status,impact,priority,recency)")
+	weightsSpec := flag.String("weights", "", "Hybrid weights as key=value list, e.g. text=0.5,pagerank=0.2 (unspecified = 0; normalized; overrides preset)")
 	diffSince := flag.String("diff-since", "", "Show changes since historical point (commit SHA, branch, tag, or date)")
+	gitRef := flag.String("git-ref", "", "Diff the working copy's beads against the beads file at a git ref, e.g. main (missing file = empty)")
 	asOf := flag.String("as-of", "", "View state at point in time (commit SHA, branch, tag, or date)")
 	forceFullAnalysis := flag.Bool("force-full-analysis", false, "Compute all metrics regardless of graph size (may be slow for large graphs)")
+	printConfig := flag.Bool("print-config", false, "Print the effective configuration (.bv/config.yaml merged with environment and flags) and exit")
 	profileStartup := flag.Bool("profile-startup", false, "Output detailed startup timing profile for diagnostics")
-	profileJSON := flag.Bool("profile-json", false, "Output profile in JSON format (use with --profile-startup)")
+	profileJSON := flag.Bool("profile-json", false, "Output profile in JSON format (use with --profile-startup or --profile)")
+	profileRun := flag.Bool("profile", false, "Print the analysis timing profile to stderr, then run the command as usual")
 	noHooks := flag.Bool("no-hooks", false, "Skip running hooks during export")
 	workspaceConfig := flag.String("workspace", "", "Load issues from workspace config file (.bv/workspace.yaml)")
+	recursive := flag.Bool("recursive", false, "Aggregate the beads of every .beads directory under the current one (monorepos), tagging each bead with its project path")
+	liveOnly := flag.Bool("live-only", false, "Drop dependencies on closed beads before analysis, so plans and critical paths reflect only live work")
 	repoFilter := flag.String("repo", "", "Filter issues by repository prefix (e.g., 'api-' or 'api')")
 	saveBaseline := flag.String("save-baseline", "", "Save current metrics as baseline with optional description")
 	baselineInfo := flag.Bool("baseline-info", false, "Show information about the current baseline")
@@ -114,6 +170,9 @@ func main() {
 	historySince := flag.String("history-since", "", "Limit history to commits after this date/ref (e.g., '30 days ago', '2024-01-01')")
 	historyLimit := flag.Int("history-limit", 500, "Max commits to analyze (0 = unlimited)")
 	minConfidence := flag.Float64("min-confidence", 0.0, "Filter correlations by minimum confidence (0.0-1.0)")
+	applyPriorities := flag.Bool("apply-priorities", false, "Write recommended priorities (confidence >= --apply-priorities-min-confidence) to the beads JSONL and print a summary")
+	applyMinConfidence := flag.Float64("apply-priorities-min-confidence", defaultApplyMinConfidence, "Minimum suggestion confidence (0.0-1.0) for --apply-priorities")
+	applyDryRun := flag.Bool("apply-priorities-dry-run", false, "With --apply-priorities: print the diff of the beads JSONL without writing it")
 	// Correlation audit flags (bv-e1u6)
 	robotExplainCorrelation := flag.String("robot-explain-correlation", "", "Explain why a commit is linked to a bead (format: SHA:beadID)")
 	robotConfirmCorrelation := flag.String("robot-confirm-correlation", "", "Confirm a correlation is correct (format: SHA:beadID)")
@@ -172,6 +231,7 @@ func main() {
 	feedbackShow := flag.Bool("feedback-show", false, "Show current feedback status and weight adjustments")
 	// Priority brief export (bv-96)
 	priorityBrief := flag.String("priority-brief", "", "Export priority brief to Markdown file (e.g., brief.md)")
+	exportTodo := flag.String("export-todo", "", "Export triage recommendations as a Markdown checklist (e.g., todo.md)")
 	// Agent brief bundle (bv-131)
 	agentBrief := flag.String("agent-brief", "", "Export agent brief bundle to directory (includes triage.json, insights.json, brief.md, helpers.md)")
 	// Static pages export flags (bv-73f)
@@ -181,6 +241,8 @@ func main() {
 	pagesIncludeHistory := flag.Bool("pages-include-history", true, "Include git history for time-travel (default: true)")
 	previewPages := flag.String("preview-pages", "", "Preview existing static site bundle")
 	pagesWizard := flag.Bool("pages", false, "Launch interactive Pages deployment wizard")
+	strictLoad := flag.Bool("strict", false, "Fail on duplicate bead IDs instead of warning (by default the last occurrence wins)")
+	dbPath := flag.String("db", "", "Load beads from a bd SQLite database instead of the JSONL export (default: .beads/beads.db when there is no JSONL)")
 	// Debug rendering flag (for diagnosing TUI issues)
 	debugRender := flag.String("debug-render", "", "Render a view and output to file (views: insights, board)")
 	debugWidth := flag.Int("debug-width", 180, "Width for debug render")
@@ -189,6 +251,45 @@ func main() {
 	backgroundMode := flag.Bool("background-mode", false, "Enable experimental background snapshot loading (TUI only)")
 	noBackgroundMode := flag.Bool("no-background-mode", false, "Disable experimental background snapshot loading (TUI only)")
 	flag.Parse()
+	if *robotReady {
+		*robotNext = true
+	}
+
+	// Per-project defaults from .bv/config.yaml; explicit flags win
+	projectCfg := loadProjectConfig("")
+	projectCfgApplied := applyProjectConfig(flag.CommandLine, &projectCfg)
+	for _, w := range projectCfg.Warnings {
+		logging.Warn(w)
+	}
+	if *printConfig {
+		if err := printProjectConfig(os.Stdout, flag.CommandLine, projectCfg, projectCfgApplied); err != nil {
+			fmt.Fprintf(os.Stderr, "Error printing config: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	robotFieldPaths = parseFieldsFlag(*robotFields)
+	labelMatcher, err := analysis.ParseLabelPattern(*labelScope)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --label: %v\n", err)
+		os.Exit(1)
+	}
+	robotOutputFormat = strings.ToLower(strings.TrimSpace(*robotOutput))
+	if robotOutputFormat != "json" && robotOutputFormat != "md" {
+		fmt.Fprintf(os.Stderr, "Error: invalid --output %q (valid: %s)\n", *robotOutput, strings.Join(robotOutputFormats, ", "))
+		os.Exit(1)
+	}
+
+	healthWeights := analysis.DefaultHealthWeights()
+	if *healthWeightsSpec != "" {
+		w, err := analysis.ParseHealthWeights(*healthWeightsSpec)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --health-weights: %v\n", err)
+			os.Exit(1)
+		}
+		healthWeights = w
+	}
 
 	// Ensure static export flags are retained even when build tags strip features in some environments.
 	_ = exportPages
@@ -215,6 +316,7 @@ func main() {
 
 	robotMode := envRobot ||
 		*robotHelp ||
+		*robotVersion ||
 		*robotInsights ||
 		*robotPlan ||
 		*robotPriority ||
@@ -229,7 +331,10 @@ func main() {
 		*robotLabelAttention ||
 		*robotAlerts ||
 		*robotSuggest ||
+		*robotLint ||
+		*robotSummary ||
 		*robotGraph ||
+		*robotMetrics ||
 		*robotSearch ||
 		*robotDriftCheck ||
 		*robotHistory ||
@@ -250,13 +355,16 @@ func main() {
 		*robotCapacity ||
 		// When stdout is non-TTY, --diff-since auto-enables JSON output. Mark this
 		// as robot mode early so parsers keep stdout JSON clean.
-		(*diffSince != "" && !stdoutIsTTY)
+		((*diffSince != "" || *gitRef != "") && !stdoutIsTTY)
 
 	// Mark robot mode for downstream packages (e.g., parsers) to keep stdout JSON clean.
 	if robotMode && !envRobot {
 		_ = os.Setenv("BV_ROBOT", "1")
 		envRobot = true
 	}
+	if robotOutputFormat != "json" && !robotMode {
+		logging.Warn("--output only applies to robot commands (--robot-*); ignoring it")
+	}
 
 	// Handle -r shorthand
 	if *recipeShort != "" && *recipeName == "" {
@@ -265,6 +373,9 @@ func main() {
 
 	if *help {
 		fmt.Println("Usage: bv [options]")
+		fmt.Println("       bv index [--rebuild] [--allow-fallback] [--json]   Build/update the semantic search index")
+		fmt.Println("       bv serve [--port N] [--host H]                     Serve robot JSON over HTTP")
+		fmt.Println("       bv agents check [--json] | bv agents update         Validate/fix the bv blurb in AGENTS.md")
 		fmt.Println("\nA TUI viewer for beads issue tracker.")
 		flag.PrintDefaults()
 		os.Exit(0)
@@ -285,6 +396,8 @@ func main() {
 		fmt.Println("      - items: Actionable issues sorted by priority within each track")
 		fmt.Println("      - unblocks: Issues that become actionable when this item is done")
 		fmt.Println("      - summary: Highlights highest-impact item to work on first")
+		fmt.Println("      - slack: Per-item longest-path slack (0 = critical path)")
+		fmt.Println("      - schedule: do_first (zero slack), deferrable (has slack), parallel_safe")
 		fmt.Println("")
 		fmt.Println("  --robot-insights")
 		fmt.Println("      Outputs a JSON object containing deep graph analysis.")
@@ -294,15 +407,32 @@ func main() {
 		fmt.Println("      - CriticalPathScore: Heuristic for depth. High score = Blocking a long chain of work.")
 		fmt.Println("      - Hubs/Authorities: HITS algorithm scores for dependency relationships.")
 		fmt.Println("      - Cycles: Lists of circular dependencies (unhealthy state).")
+		fmt.Println("      - zombies: In-progress beads with no update or correlated commit for --zombie-days")
+		fmt.Println("        (default 14), with days_since_activity and the last commit.")
 		fmt.Println("")
 		fmt.Println("  --robot-priority")
 		fmt.Println("      Outputs priority recommendations as JSON.")
 		fmt.Println("      Compares impact scores to current priorities and suggests adjustments.")
 		fmt.Println("      Key fields:")
-		fmt.Println("      - recommendations: Sorted by confidence, then impact score")
+		fmt.Println("      - recommendations: Promotions first, then demotions, then impact-only entries;")
+		fmt.Println("        by impact score within each group")
 		fmt.Println("      - confidence: 0-1 score indicating strength of recommendation")
 		fmt.Println("      - reasoning: Human-readable explanations for the suggestion")
-		fmt.Println("      - direction: 'increase' or 'decrease' priority")
+		fmt.Println("      - reasons: Machine-readable codes with numeric evidence, e.g.")
+		fmt.Println("        {code: LOW_PRIORITY_HIGH_IMPACT, evidence: {dependent_count: 4, impact_score: 0.8, ...}}")
+		fmt.Println("      - direction: 'increase' or 'decrease' priority ('none' = impact-only). P0/P1 beads that")
+		fmt.Println("        nothing depends on and that have low centrality are demoted (NO_DEPENDENTS, LOW_CENTRALITY)")
+		fmt.Println("")
+		fmt.Println("  --apply-priorities [--apply-priorities-min-confidence 0.7] [--apply-priorities-dry-run]")
+		fmt.Println("      Writes the --robot-priority suggestions with at least that confidence into the beads")
+		fmt.Println("      JSONL (priority and updated_at only; other lines untouched) and prints what changed.")
+		fmt.Println("      The file is replaced atomically and only if nothing else changed it meanwhile.")
+		fmt.Println("      --apply-priorities-dry-run prints the line diff instead of writing.")
+		fmt.Println("")
+		fmt.Println("  --print-config")
+		fmt.Println("      Prints the effective defaults: .bv/config.yaml (search mode/preset/weights/fusion, graph")
+		fmt.Println("      format/preset/theme/palette, analysis.force_full, wip_limit) merged with env and flags,")
+		fmt.Println("      each annotated with its source. Precedence: flags > env > config > built-in defaults.")
 		fmt.Println("")
 		fmt.Println("  --robot-triage")
 		fmt.Println("      THE MEGA-COMMAND: Unified triage output combining all analysis.")
@@ -316,19 +446,46 @@ func main() {
 		fmt.Println("      - project_health: Counts, graph metrics, overall status")
 		fmt.Println("      - commands: Copy-paste commands for common next steps")
 		fmt.Println("")
-		fmt.Println("  --robot-next")
-		fmt.Println("      Minimal triage: returns only the single top recommendation.")
-		fmt.Println("      Output includes: id, title, score, reasons, claim_command, show_command")
+		fmt.Println("  --robot-next (alias: --robot-ready)")
+		fmt.Println("      Returns the single bead to work on next. It is ranked on priority, blockers,")
+		fmt.Println("      unblocks, age and critical path without the centrality metrics, so it is fast")
+		fmt.Println("      but can differ from the --robot-triage top pick. bv serve's /next matches it.")
+		fmt.Println("      Output includes: id, title, score, reasons, claim_command, show_command, usage_hints")
 		fmt.Println("      Use when you just need to know \"what should I work on next?\"")
+		fmt.Println("      --wip-limit N: once N beads are in progress (per assignee with --robot-by-assignee),")
+		fmt.Println("        in-progress beads are recommended before new ones; adds wip_current, wip_limit,")
+		fmt.Println("        wip_limit_reached, wip_limit_exceeded and wip_note to the output.")
 		fmt.Println("")
 		fmt.Println("  --search \"query\" [--robot-search]")
 		fmt.Println("      Semantic vector search over issue titles/descriptions.")
 		fmt.Println("      Builds/updates a local on-disk vector index on first run.")
 		fmt.Println("      Use --robot-search to emit JSON for automation.")
+		fmt.Println("      Exact-match operators: \"token refresh\" (contiguous phrase), +term (required),")
+		fmt.Println("      -term (excluded); non-matching beads are dropped before ranking.")
 		fmt.Println("      Optional hybrid re-ranking:")
 		fmt.Println("      - --search-mode=text|hybrid (default: BV_SEARCH_MODE or text)")
 		fmt.Println("      - --search-preset=default|bug-hunting|sprint-planning|impact-first|text-only")
 		fmt.Println("      - --search-weights='{\"text\":0.4,\"pagerank\":0.2,\"status\":0.15,\"impact\":0.1,\"priority\":0.1,\"recency\":0.05}'")
+		fmt.Println("      - --weights=text=0.5,pagerank=0.2,status=0.1 (unspecified components = 0, normalized)")
+		fmt.Println("      --fusion=rrf: rank by reciprocal-rank fusion of a BM25 lexical ranking and the")
+		fmt.Println("      semantic ranking instead of the semantic score (default: BV_SEARCH_FUSION or score).")
+		fmt.Println("")
+		fmt.Println("  bv index [--rebuild] [--allow-fallback] [--json]")
+		fmt.Println("      Builds/updates the on-disk semantic index without searching, e.g. from CI or cron.")
+		fmt.Println("      Prints added/updated/deleted/re-embedded counts (--json for the full IndexSyncStats).")
+		fmt.Println("      Exits 1 if the configured embedder is unavailable unless --allow-fallback is set.")
+		fmt.Println("")
+		fmt.Println("  bv serve [--port N] [--host H] [--db <path>] [--health-weights spec]")
+		fmt.Println("      Keeps the beads loaded and serves /triage, /next, /plan, /insights, /search?q=")
+		fmt.Println("      and /path?from=&to= as JSON, re-analyzing only when the beads change. /healthz for probes.")
+		fmt.Println("      /events streams server-sent update events (changed bead IDs, new data hash, summary")
+		fmt.Println("      counts) and replays missed updates for clients reconnecting with Last-Event-ID.")
+		fmt.Println("      --health-weights (or analysis.health_weights in .bv/config.yaml) weighs bead_health.")
+		fmt.Println("")
+		fmt.Println("  bv agents check [--json] | bv agents update")
+		fmt.Println("      check exits 1 and names the file when the bv blurb in AGENTS.md (or CLAUDE.md) is")
+		fmt.Println("      missing, outdated, or edited so it no longer matches (drifted); use it in CI.")
+		fmt.Println("      update adds or rewrites the blurb in place without prompting.")
 		fmt.Println("")
 		fmt.Println("  --emit-script [--script-limit=N]")
 		fmt.Println("      Emits a shell script for top-N recommendations (default: 5).")
@@ -493,6 +650,17 @@ func main() {
 		fmt.Println("      Generates a readable status report with Mermaid.js visualizations.")
 		fmt.Println("      Runs pre-export and post-export hooks if configured in .bv/hooks.yaml")
 		fmt.Println("")
+		fmt.Println("  --anonymize")
+		fmt.Println("      Scrubs beads before any export or robot output: titles become placeholders")
+		fmt.Println("      (\"Task bv-12\"), descriptions/notes/comments and commit messages are dropped, and")
+		fmt.Println("      assignees/authors become pseudonyms keyed per run (set BV_ANONYMIZE_SALT to")
+		fmt.Println("      keep them stable across runs). IDs, statuses, types, dependencies and metrics")
+		fmt.Println("      are kept; data_hash is computed from the anonymized beads.")
+		fmt.Println("")
+		fmt.Println("  --export-todo <file>")
+		fmt.Println("      Writes triage recommendations as a Markdown checklist (- [ ] **id** title — reason)")
+		fmt.Println("      in triage score order, with the data hash as a footer. Paste into a PR description.")
+		fmt.Println("")
 		fmt.Println("  --no-hooks")
 		fmt.Println("      Skip running hooks during export. Useful for CI or quick exports.")
 		fmt.Println("")
@@ -521,9 +689,56 @@ func main() {
 		fmt.Println("      Robot outputs include 'as_of' and 'as_of_commit' metadata fields.")
 		fmt.Println("      Examples: --as-of HEAD~30, --as-of v1.0.0, --as-of '2024-01-01'")
 		fmt.Println("")
+		fmt.Println("  --since <duration|date>")
+		fmt.Println("      Report only beads created or updated within the window (--robot-triage, --robot-next, --robot-priority).")
+		fmt.Println("      Blocker relationships and scores still use the full graph.")
+		fmt.Println("      Outputs include since{spec,cutoff,included,excluded}.")
+		fmt.Println("      Examples: --since 72h, --since 3d, --since 2024-01-01")
+		fmt.Println("")
+		fmt.Println("  --closed-window <duration|date>")
+		fmt.Println("      How far back --robot-triage's recently_closed section looks (default 7d).")
+		fmt.Println("      Lists beads closed in the window by closed_at, with counts by type and total estimated minutes.")
+		fmt.Println("      Examples: --closed-window 14d, --closed-window 2024-06-01")
+		fmt.Println("")
+		fmt.Println("  --priority-min N / --priority-max N")
+		fmt.Println("      Report only beads whose priority is within the range (--robot-triage, --robot-next, --robot-priority).")
+		fmt.Println("      Like --since, metrics are still computed on the full graph; combine both to narrow further.")
+		fmt.Println("      Outputs include priority_range{min,max,included,excluded}.")
+		fmt.Println("      Example: --robot-next --priority-max 1   # only P0/P1")
+		fmt.Println("")
+		fmt.Println("  --fields <path,...>")
+		fmt.Println("      Project any robot JSON output to the listed dotted paths to cut token cost.")
+		fmt.Println("      Use [] to select a field from every array element; unresolved paths are listed in invalid_fields.")
+		fmt.Println("      Example: bv --robot-plan --fields 'plan.tracks[].items[].id,data_hash'")
+		fmt.Println("")
+		fmt.Println("  --output json|md")
+		fmt.Println("      Encoding for robot output (default json). md renders markdown for PRs and docs:")
+		fmt.Println("      lists of objects become aligned tables, scalars become bullets, cycles and paths")
+		fmt.Println("      become lists, multi-line strings (DOT, Mermaid) become code blocks, and nested objects")
+		fmt.Println("      become sections named by their dotted path. Ignored, with a warning, without --robot-*.")
+		fmt.Println("      Example: bv --robot-triage --output md")
+		fmt.Println("")
+		fmt.Println("  --strict")
+		fmt.Println("      Treat duplicate bead IDs as a load error. Without it the last line for an ID wins and")
+		fmt.Println("      robot outputs carry a top-level warnings list naming each duplicated ID and its count.")
+		fmt.Println("      Applies to --as-of and --workspace too; with --workspace any repo failing to load is fatal.")
+		fmt.Println("")
+		fmt.Println("  --db <path>")
+		fmt.Println("      Read beads from a bd SQLite database (issues, dependencies, labels, comments)")
+		fmt.Println("      instead of the JSONL export. Without it, .beads/beads.db is used when .beads has no JSONL.")
+		fmt.Println("      Cannot be combined with --as-of, --workspace or --recursive.")
+		fmt.Println("")
+		fmt.Println("  --robot-version")
+		fmt.Println("      Build info as JSON: bv_version, bv_commit, bv_build_date, go_version, os, arch.")
+		fmt.Println("      Every robot payload also carries bv_version, bv_commit and bv_build_date in its header,")
+		fmt.Println("      right after data_hash, so outputs can be traced to the exact binary.")
+		fmt.Println("")
 		fmt.Println("  --robot-diff")
-		fmt.Println("      Output diff as JSON (use with --diff-since).")
+		fmt.Println("      Output diff as JSON (use with --diff-since or --git-ref).")
 		fmt.Println("      Fields: generated_at, resolved_revision, from_data_hash, to_data_hash, diff{...}")
+		fmt.Println("      --git-ref <ref>: diff the working copy against the beads file at a git ref")
+		fmt.Println("        (e.g. --robot-diff --git-ref main to review how a branch changes the backlog);")
+		fmt.Println("        a ref without a beads file counts as empty and sets from_missing.")
 		fmt.Println("      Diff payload includes metric deltas, cycles introduced/resolved, and modified issues.")
 		fmt.Println("")
 		fmt.Println("  --robot-recipes")
@@ -553,19 +768,45 @@ func main() {
 		fmt.Println("      Filters: --severity=<info|warning|critical>, --alert-type=<type>, --alert-label=<label>")
 		fmt.Println("      Fields: type, severity, message, issue_id, label, detected_at, details[].")
 		fmt.Println("")
-		fmt.Println("  --robot-graph [--graph-format=json|dot|mermaid] [--graph-root=ID] [--graph-depth=N]")
+		fmt.Println("  --robot-summary")
+		fmt.Println("      Cheapest heartbeat: one line of JSON {nodes, edges, actionable, blocked, critical, cycles, data_hash}.")
+		fmt.Println("      Only cycles and slack are computed (critical = zero-slack beads); no betweenness/PageRank.")
+		fmt.Println("")
+		fmt.Println("  --robot-lint")
+		fmt.Println("      Checks the bead graph for structural smells and outputs findings as JSON.")
+		fmt.Println("      Codes: cycle, self_dependency (error); dangling_reference, blocked_by_closed_only,")
+		fmt.Println("             p0_many_blockers (warning); epic_without_children, orphaned_high_priority (info).")
+		fmt.Println("      Fields: summary{total,errors,warnings,info,by_code}, findings[]{code,severity,issue_ids,message,suggested_fix}.")
+		fmt.Println("      cycle_risks{total,truncated,risks[]{from,to,existing_path,cycle_length,avoid}}: near-cycles,")
+		fmt.Println("        i.e. dependency additions between open beads that would close a cycle (shortest first).")
+		fmt.Println("")
+		fmt.Println("  --robot-graph [--graph-format=json|dot|mermaid|dsm|prometheus] [--graph-root=ID] [--graph-depth=N] [--cluster-by=type|status|component]")
 		fmt.Println("      Outputs dependency graph in specified format (default: JSON adjacency).")
 		fmt.Println("      Formats:")
 		fmt.Println("        - json: Adjacency list with nodes[], edges[], metadata")
 		fmt.Println("        - dot: Graphviz DOT format (render with: dot -Tpng file.dot -o graph.png)")
 		fmt.Println("        - mermaid: Mermaid diagram format (paste into GitHub/markdown)")
+		fmt.Println("        - dsm: Dependency structure matrix, topologically ordered so dependencies fall below")
+		fmt.Println("          the diagonal; sparse matrix{order,ordering,cells[{row,col,type}],back_edges},")
+		fmt.Println("          graph holds the cells as CSV (row,col,bead,depends_on,type)")
+		fmt.Println("        - prometheus: backlog health gauges as plain text; see --robot-metrics")
 		fmt.Println("      Options:")
-		fmt.Println("        --label LABEL: Filter to issues with specific label")
+		fmt.Println("        --label LABEL: Filter to issues with a label (or glob team/*, or /regex/)")
 		fmt.Println("        --graph-root ID: Extract subgraph starting from root issue")
 		fmt.Println("        --graph-depth N: Limit subgraph depth (0 = unlimited)")
-		fmt.Println("      Fields: format, graph (string for dot/mermaid), nodes, edges, filters_applied, explanation")
+		fmt.Println("        --cluster-by MODE: DOT only (an error with other formats); group nodes into labelled")
+		fmt.Println("          subgraph clusters by type, status, or connected component")
+		fmt.Println("      Fields: format, graph (string for dot/mermaid/dsm), nodes, edges, filters_applied, explanation")
 		fmt.Println("      Example: bv --robot-graph --graph-format=dot --label=api > api-deps.dot")
 		fmt.Println("")
+		fmt.Println("  --robot-metrics")
+		fmt.Println("      Outputs backlog health in the Prometheus text exposition format (not JSON), so a cron")
+		fmt.Println("      job can feed the node_exporter textfile collector. Every series has a project label")
+		fmt.Println("      (the working directory name).")
+		fmt.Println("      Gauges: bv_beads_total, bv_beads_by_status{status}, bv_beads_blocked, bv_beads_ready,")
+		fmt.Println("        bv_dependencies_total, bv_cycles_total, bv_critical_path_length")
+		fmt.Println("      Example: bv --robot-metrics > /var/lib/node_exporter/textfile/bv.prom")
+		fmt.Println("")
 		fmt.Println("  --export-graph <path.png|path.svg> [--graph-style=force|grid] [--graph-preset=compact|roomy]")
 		fmt.Println("      Export dependency graph as PNG or SVG image (pure Go, no external dependencies).")
 		fmt.Println("      Format is inferred from file extension (.png or .svg).")
@@ -583,9 +824,27 @@ func main() {
 		fmt.Println("          - Light theme with pastel colors")
 		fmt.Println("")
 		fmt.Println("      Options:")
-		fmt.Println("        --label LABEL: Filter to issues with specific label")
+		fmt.Println("        --label LABEL: Filter to issues with a label (or glob team/*, or /regex/)")
 		fmt.Println("        --graph-preset: Layout spacing - 'compact' (default) or 'roomy'")
 		fmt.Println("        --graph-title: Custom title for the graph header")
+		fmt.Println("        --no-animation: (.html only) Start with link particles and animations off")
+		fmt.Println("        --no-sanitize: (.html only) Keep raw HTML in descriptions/notes as written; by default DOMPurify")
+		fmt.Println("                  strips scripts and event handlers. Only use for repos you trust.")
+		fmt.Println("        --theme light|dark|auto: (.html only) Default color theme; auto follows the OS setting")
+		fmt.Println("        --palette default|cb-safe: (.html only) cb-safe uses blue/orange statuses and a viridis heatmap")
+		fmt.Println("        --commit-url-template <url>: (.html only) Embed related commits and link each SHA; any forge works,")
+		fmt.Println("            e.g. https://gitea.example.com/o/r/commit/{sha} or https://bitbucket.org/o/r/commits/{sha}")
+		fmt.Println("        --issue-url-template <url>: (.html only) Link bead IDs to an external tracker via {id}")
+		fmt.Println("        --avatar-url-template <url>: (.html only) Owner badges show the avatar at {assignee} instead of initials")
+		fmt.Println("        --critical-color, --articulation-color <#hex>: (.html only) Override the pink critical-path and")
+		fmt.Println("                  articulation-point highlights, e.g. to match a brand palette or the cb-safe scheme")
+		fmt.Println("        --type-config <file>: (.html only) Shape/color per type, e.g. question: {shape: star, color: \"#14b8a6\"}")
+		fmt.Println("                  Shapes: circle, square, triangle, diamond, hexagon, star. Unregistered types use a grey hexagon.")
+		fmt.Println("        --compress-data: (.html only) Embed the bead data gzipped; decoded in-page, so file:// still works")
+		fmt.Println("        --export-dir <dir>: Write the interactive graph as index.html, app.js, styles.css, data.json and vendor/")
+		fmt.Println("                  libraries for static hosting (relative paths; needs a web server, not file://)")
+		fmt.Println("        --export-json <file>: Write the data the HTML viewer embeds (nodes with all metrics, links with")
+		fmt.Println("                  critical flags, triage, summary) as standalone JSON for external dashboards")
 		fmt.Println("")
 		fmt.Println("      Example: bv --export-graph deps.svg --label=api --graph-title='API Dependencies'")
 		fmt.Println("      Example: bv --export-graph full.png --graph-style=force --graph-preset=roomy")
@@ -614,13 +873,18 @@ func main() {
 		fmt.Println("  --robot-plan")
 		fmt.Println("      Execution tracks grouped for parallel work. Includes data_hash, analysis_config, status.")
 		fmt.Println("      plan.tracks[].items[].unblocks shows what completes next; summary.highest_impact surfaces best unblocker.")
+		fmt.Println("      plan.schedule splits items by slack: do_first, deferrable, and parallel_safe (no path to other ready items).")
 		fmt.Println("")
 		fmt.Println("  --robot-priority")
 		fmt.Println("      Priority recommendations with explanations. Includes data_hash, analysis_config, status.")
-		fmt.Println("      recommendation fields: id, current_priority, suggested_priority, impact_score, confidence, reasoning[].")
+		fmt.Println("      recommendation fields: id, current_priority, suggested_priority, impact_score, confidence, reasoning[],")
+		fmt.Println("      reasons[] ({code, evidence}: HIGH_DEPENDENT_COUNT, ON_CRITICAL_PATH, LOW_PRIORITY_HIGH_IMPACT, ...).")
 		fmt.Println("      explanation.what_if: impact of completing (direct_unblocks, transitive_unblocks, estimated_days_saved).")
 		fmt.Println("      explanation.top_reasons: top 3 factors (pagerank, betweenness, blockers, staleness, etc.).")
 		fmt.Println("")
+		fmt.Println("  --apply-priorities [--apply-priorities-min-confidence 0.7] [--apply-priorities-dry-run]")
+		fmt.Println("      Applies those recommendations to the beads JSONL (atomic; the dry run prints the diff).")
+		fmt.Println("")
 		fmt.Println("  Robot Output Filters (bv-84):")
 		fmt.Println("      --robot-min-confidence 0.6    Filter by minimum confidence (0.0-1.0)")
 		fmt.Println("      --robot-max-results 5         Limit to top N results")
@@ -632,7 +896,10 @@ func main() {
 		fmt.Println("      Affects: --robot-insights, --robot-plan, --robot-priority")
 		fmt.Println("      Filters issues to those with the label, then runs analysis on subgraph.")
 		fmt.Println("      Includes label_scope and label_context in output with health metrics.")
+		fmt.Println("      LABEL may be a glob (team/* matches team/infra and team/web; * also crosses /,")
+		fmt.Println("      case is ignored) or a /regex/ (RE2, as written). label_context needs an exact label.")
 		fmt.Println("      Example: bv --robot-insights --label api")
+		fmt.Println("      Example: bv --robot-plan --label 'team/*'")
 		fmt.Println("")
 		fmt.Println("  --robot-triage / --robot-next")
 		fmt.Println("      Unified triage (mega command) or single top pick. QuickRef includes top picks, quick_wins, blockers_to_clear.")
@@ -642,6 +909,16 @@ func main() {
 		fmt.Println("      Example: bv --recipe actionable")
 		fmt.Println("      Built-in recipes: default, actionable, recent, blocked, high-impact, stale,")
 		fmt.Println("                        triage, closed, release-cut, quick-wins, bottlenecks")
+		fmt.Println("      Each run (params, steps, outcomes, duration) is appended to")
+		fmt.Println("      $XDG_CONFIG_HOME/bv/recipe-runs.jsonl (usually ~/.config/bv/).")
+		fmt.Println("")
+		fmt.Println("  --recipe-explain NAME")
+		fmt.Println("      Shows every source that defines a recipe (builtin, ~/.config/bv/recipes.yaml,")
+		fmt.Println("      .bv/recipes.yaml), which one wins, and how it differs from the builtin.")
+		fmt.Println("")
+		fmt.Println("  --recipe-history [--recipe NAME] [--recipe-history-limit N]")
+		fmt.Println("      Lists recent recipe runs, newest first, with each step's outcome and error.")
+		fmt.Println("      Example: bv --recipe-history -r stale")
 		fmt.Println("")
 		fmt.Println("  --profile-startup")
 		fmt.Println("      Outputs detailed startup timing profile for diagnostics.")
@@ -649,18 +926,39 @@ func main() {
 		fmt.Println("      Provides recommendations based on timing analysis.")
 		fmt.Println("      Use with --profile-json for machine-readable output.")
 		fmt.Println("")
+		fmt.Println("  --profile")
+		fmt.Println("      Prints the same timing profile (node/edge counts, per-phase and per-metric timings,")
+		fmt.Println("      timeouts, whether betweenness was approximated) to stderr, then runs the requested")
+		fmt.Println("      command normally, so robot JSON on stdout is unaffected.")
+		fmt.Println("      Use with --profile-json for a JSON profile on stderr.")
+		fmt.Println("      Example: bv --robot-insights --profile > insights.json")
+		fmt.Println("")
 		fmt.Println("  --workspace CONFIG")
 		fmt.Println("      Load issues from workspace configuration file.")
 		fmt.Println("      Path: typically .bv/workspace.yaml")
 		fmt.Println("      Aggregates issues from multiple repositories with namespaced IDs.")
 		fmt.Println("      Example: bv --workspace .bv/workspace.yaml")
 		fmt.Println("")
+		fmt.Println("  --recursive")
+		fmt.Println("      Load every .beads directory under the current one, e.g. one per monorepo subproject,")
+		fmt.Println("      skipping gitignored, hidden, node_modules and vendor directories. Each bead's")
+		fmt.Println("      ID is prefixed with its project's name, as in a workspace (services/api's bv-1 becomes")
+		fmt.Println("      api-bv-1), and its source_repo is the project path; robot output adds a \"projects\"")
+		fmt.Println("      map (bead ID -> project path).")
+		fmt.Println("      --repo services/api narrows to exactly that project. No live reload.")
+		fmt.Println("      Without it, bv uses .beads in the current directory or its nearest parent.")
+		fmt.Println("")
 		fmt.Println("  --repo PREFIX")
 		fmt.Println("      Filter issues by repository prefix.")
 		fmt.Println("      Use with --workspace to focus on one repo in a multi-repo view.")
 		fmt.Println("      Matches ID prefixes like 'api-', 'web-', or partial 'api'.")
 		fmt.Println("      Example: bv --workspace .bv/workspace.yaml --repo api")
 		fmt.Println("")
+		fmt.Println("  --live-only")
+		fmt.Println("      Forward planning: blocking dependencies on closed beads are dropped before")
+		fmt.Println("      analysis, so the critical path, slack, centrality and --robot-plan tracks")
+		fmt.Println("      reflect only live work. By default all edges are kept for historical analysis.")
+		fmt.Println("")
 		fmt.Println("  --save-baseline \"description\"")
 		fmt.Println("      Save current metrics as a baseline snapshot.")
 		fmt.Println("      Stores graph stats, top metrics, and cycle info in .bv/baseline.json.")
@@ -715,7 +1013,27 @@ func main() {
 	}
 
 	if *versionFlag {
-		fmt.Printf("bv %s\n", version.Version)
+		fmt.Println(version.Info())
+		os.Exit(0)
+	}
+
+	if *robotVersion {
+		// The encoder adds bv_version, bv_commit and bv_build_date itself.
+		output := struct {
+			GoVersion string `json:"go_version"`
+			OS        string `json:"os"`
+			Arch      string `json:"arch"`
+		}{
+			GoVersion: runtime.Version(),
+			OS:        runtime.GOOS,
+			Arch:      runtime.GOARCH,
+		}
+		encoder := newRobotEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding version: %v\n", err)
+			os.Exit(1)
+		}
 		os.Exit(0)
 	}
 
@@ -881,14 +1199,33 @@ func main() {
 	}
 
 	// Load recipes (needed for both --robot-recipes and --recipe)
-	recipeLoader, err := recipe.LoadDefault()
-	if err != nil {
+	recipeLoader := recipe.NewLoader(recipe.WithProjectDir(projectRoot()))
+	if err := recipeLoader.Load(); err != nil {
 		if !envRobot {
-			fmt.Fprintf(os.Stderr, "Warning: Error loading recipes: %v\n", err)
+			logging.Warn("Error loading recipes", "error", err)
 		}
 		// Create empty loader to continue
 		recipeLoader = recipe.NewLoader()
 	}
+	if !robotMode {
+		for _, w := range recipeLoader.Warnings() {
+			logging.Warn("recipes: " + w)
+		}
+		for _, w := range unseenRecipeShadows(recipeLoader.Shadows(), filepath.Join(projectRoot(), ".bv", recipeShadowsSeenFile)) {
+			logging.Warn("recipes: " + w)
+		}
+	}
+
+	// Handle --recipe-explain (before loading issues)
+	if *recipeExplain != "" {
+		explanation, ok := recipeLoader.Explain(*recipeExplain)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: no builtin, user, or project config defines recipe '%s'\n", *recipeExplain)
+			os.Exit(1)
+		}
+		fmt.Print(explanation)
+		os.Exit(0)
+	}
 
 	// Handle --robot-recipes (before loading issues)
 	if *robotRecipes {
@@ -904,7 +1241,7 @@ func main() {
 			Recipes: summaries,
 		}
 
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(output); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding recipes: %v\n", err)
@@ -913,8 +1250,19 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Handle --recipe-history (before validating --recipe, so runs of removed recipes still show)
+	if *recipeHistory {
+		runs, err := recipe.LoadRuns(recipe.DefaultHistoryPath(), *recipeName, *recipeHistoryLimit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading recipe history: %v\n", err)
+			os.Exit(1)
+		}
+		printRecipeHistory(os.Stdout, runs)
+		os.Exit(0)
+	}
+
 	// Get project directory for baseline operations (moved up to allow info check without loading issues)
-	projectDir, _ := os.Getwd()
+	projectDir := projectRoot()
 	baselinePath := baseline.DefaultPath(projectDir)
 
 	// Handle --baseline-info
@@ -946,6 +1294,31 @@ func main() {
 			}
 			os.Exit(1)
 		}
+		if err := activeRecipe.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// --db names one project's database; the other sources would ignore it
+	if *dbPath != "" {
+		switch {
+		case *asOf != "":
+			fmt.Fprintln(os.Stderr, "Error: --db cannot be combined with --as-of")
+			os.Exit(1)
+		case *workspaceConfig != "":
+			fmt.Fprintln(os.Stderr, "Error: --db cannot be combined with --workspace")
+			os.Exit(1)
+		case *recursive:
+			fmt.Fprintln(os.Stderr, "Error: --db cannot be combined with --recursive")
+			os.Exit(1)
+		}
+	}
+
+	// --cluster-by groups DOT subgraphs; the other graph formats have no clusters
+	if *clusterBy != "" && !strings.EqualFold(*graphFormat, string(export.GraphFormatDOT)) {
+		fmt.Fprintf(os.Stderr, "Error: --cluster-by only applies to --graph-format=dot (got %s)\n", *graphFormat)
+		os.Exit(1)
 	}
 
 	// Load issues from current directory or workspace (with timing for profile)
@@ -954,12 +1327,24 @@ func main() {
 	var beadsPath string
 	var workspaceInfo *workspace.LoadSummary
 	var asOfResolved string // Resolved commit SHA when using --as-of (for robot output metadata)
+	var asOfTime time.Time  // The moment --as-of stands for; ages are measured from it
+	// Data problems found while loading (robot "warnings", TUI status bar)
+	var loadWarnings []string
+	// Every load path parses with these, so --strict and the type default
+	// priorities hold for --as-of and --workspace too
+	parseOpts := loader.ParseOptions{
+		Strict: *strictLoad,
+		DuplicateHandler: func(dups []loader.DuplicateID) {
+			loadWarnings = append(loadWarnings, "duplicate bead IDs (last occurrence kept): "+loader.FormatDuplicateIDs(dups))
+		},
+		DefaultPriorities: projectCfg.DefaultPriorities,
+	}
 
 	if *asOf != "" {
 		// Time-travel mode: load historical issues from git
 		// Note: --as-of takes precedence over --workspace (can't combine historical + multi-repo)
 		if *workspaceConfig != "" {
-			fmt.Fprintf(os.Stderr, "Warning: --workspace is ignored when --as-of is specified\n")
+			logging.Warn("--workspace is ignored when --as-of is specified")
 		}
 		cwd, err := os.Getwd()
 		if err != nil {
@@ -967,13 +1352,16 @@ func main() {
 			os.Exit(1)
 		}
 		gitLoader := loader.NewGitLoader(cwd)
-		issues, err = gitLoader.LoadAt(*asOf)
+		issues, err = gitLoader.LoadAtWithOptions(*asOf, parseOpts)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading issues at %s: %v\n", *asOf, err)
 			os.Exit(1)
 		}
 		// Resolve to commit SHA for metadata
 		asOfResolved, _ = gitLoader.ResolveRevision(*asOf)
+		if asOfTime, err = gitLoader.RevisionTime(*asOf); err != nil {
+			logging.Warn("measuring ages from now: no time for --as-of revision", "revision", *asOf, "error", err)
+		}
 		// No live reload for historical view
 		beadsPath = ""
 		if !envRobot {
@@ -985,21 +1373,29 @@ func main() {
 		}
 	} else if *workspaceConfig != "" {
 		// Load from workspace configuration
-		loadedIssues, results, err := workspace.LoadAllFromConfig(context.Background(), *workspaceConfig)
+		loadedIssues, results, err := workspace.LoadAllFromConfigWithOptions(context.Background(), *workspaceConfig, parseOpts)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading workspace: %v\n", err)
 			os.Exit(1)
 		}
+		if *strictLoad {
+			// A repo rejected by --strict must not be skipped like a missing one
+			for _, r := range results {
+				if r.Error != nil {
+					fmt.Fprintf(os.Stderr, "Error loading workspace repo %s: %v\n", r.RepoName, r.Error)
+					os.Exit(1)
+				}
+			}
+		}
 		issues = loadedIssues
 		summary := workspace.Summarize(results)
 		workspaceInfo = &summary
 
 		// Print workspace loading summary
-		if summary.FailedRepos > 0 {
-			if !envRobot {
-				fmt.Fprintf(os.Stderr, "Warning: %d repos failed to load\n", summary.FailedRepos)
-				for _, name := range summary.FailedRepoNames {
-					fmt.Fprintf(os.Stderr, "  - %s\n", name)
+		if summary.FailedRepos > 0 && !envRobot {
+			for _, r := range results {
+				if r.Error != nil {
+					logging.Warn("workspace repo failed to load", "repo", r.RepoName, "error", r.Error)
 				}
 			}
 		}
@@ -1010,31 +1406,107 @@ func main() {
 		// Workspace config is typically at .bv/workspace.yaml, so project root is two levels up
 		workspaceRoot := filepath.Dir(filepath.Dir(*workspaceConfig))
 		_ = loader.EnsureBVInGitignore(workspaceRoot)
+	} else if *recursive {
+		// Aggregate nested .beads directories (monorepo subprojects)
+		if os.Getenv(loader.BeadsDirEnvVar) != "" && !envRobot {
+			logging.Warn(loader.BeadsDirEnvVar + " is ignored with --recursive")
+		}
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+		recursiveOpts := parseOpts
+		recursiveOpts.WarningHandler = func(msg string) {
+			loadWarnings = append(loadWarnings, msg)
+		}
+		loadedIssues, projects, err := workspace.LoadRecursive(cwd, recursiveOpts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading beads: %v\n", err)
+			os.Exit(1)
+		}
+		issues = loadedIssues
+		if len(projects) == 0 {
+			robotUsageHints = emptyProjectHints(true)
+		} else if !envRobot {
+			logging.Info("loaded nested projects", "beads", len(issues), "projects", len(projects))
+		}
+		// No live reload across several beads files
+		beadsPath = ""
 	} else {
 		// Load from single repo (original behavior)
 		var err error
-		issues, err = loader.LoadIssues("")
+		if *dbPath != "" {
+			issues, err = loader.LoadIssuesFromDB(*dbPath, parseOpts)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading beads database: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			issues, err = loader.LoadIssuesWithOptions("", parseOpts)
+		}
+		noBeadsData := false
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading beads: %v\n", err)
-			fmt.Fprintln(os.Stderr, "Make sure you are in a project initialized with 'bd init'.")
-			os.Exit(1)
+			if !loader.IsNoBeadsData(err) {
+				fmt.Fprintf(os.Stderr, "Error loading beads: %v\n", err)
+				fmt.Fprintln(os.Stderr, "Make sure you are in a project initialized with 'bd init'.")
+				os.Exit(1)
+			}
+			// No .beads (or no JSONL in it) yet: carry on as an empty project so
+			// robot commands still emit valid payloads and the TUI an empty state.
+			noBeadsData = true
+			issues = nil
+		}
+		if len(issues) == 0 {
+			robotUsageHints = emptyProjectHints(noBeadsData)
 		}
 		// Get beads file path for live reload (respects BEADS_DIR env var)
+		// Live reload only follows JSONL files, so it is off when reading SQLite.
 		beadsDir, _ := loader.GetBeadsDir("")
-		beadsPath, _ = loader.FindJSONLPath(beadsDir)
+		if *dbPath == "" {
+			if path, isDB, err := loader.FindBeadsSource(beadsDir); err == nil && !isDB {
+				beadsPath = path
+			}
+		}
 
 		// Automatically ensure .bv/ is in .gitignore to prevent polluting git
 		// with search indexes, baselines, and other bv-specific files.
-		// This is done silently and only in single-repo mode.
-		projectDir := filepath.Dir(beadsDir)
-		_ = loader.EnsureBVInGitignore(projectDir)
+		// This is done silently and only in single-repo mode, and only for
+		// actual beads projects.
+		if !noBeadsData {
+			projectDir := filepath.Dir(beadsDir)
+			_ = loader.EnsureBVInGitignore(projectDir)
+		}
 	}
 	loadDuration := time.Since(loadStart)
+	loadWarnings = append(loadWarnings, loader.CheckTypePriorities(issues, projectCfg.DefaultPriorities)...)
+	robotLoadWarnings = loadWarnings
 
 	// Apply --repo filter if specified
-	if *repoFilter != "" {
+	if *repoFilter != "" && *recursive {
+		issues = filterByProject(issues, *repoFilter)
+	} else if *repoFilter != "" {
 		issues = filterByRepo(issues, *repoFilter)
 	}
+	if *recursive {
+		robotBeadProjects = make(map[string]string, len(issues))
+		for _, iss := range issues {
+			robotBeadProjects[iss.ID] = iss.SourceRepo
+		}
+	}
+	if *liveOnly {
+		issues = analysis.LiveOnly(issues)
+	}
+
+	// --anonymize: scrub before anything is hashed, analyzed or exported so
+	// every output (and its data_hash) reflects only the shareable structure.
+	// One Anonymizer serves the whole run so people get the same pseudonyms
+	// in every output; BV_ANONYMIZE_SALT keeps them stable across runs.
+	var anonymizer *export.Anonymizer
+	if *anonymize {
+		anonymizer = export.NewAnonymizer(os.Getenv("BV_ANONYMIZE_SALT"))
+		issues = anonymizer.Issues(issues)
+	}
 
 	issuesForSearch := issues
 
@@ -1046,10 +1518,10 @@ func main() {
 	// This includes label health context in the output.
 	var labelScopeContext *analysis.LabelHealth
 	if *labelScope != "" {
-		sg := analysis.ComputeLabelSubgraph(issues, *labelScope)
+		sg := analysis.ComputeLabelSubgraphMatching(issues, labelMatcher)
 		if sg.IssueCount == 0 {
 			if !envRobot {
-				fmt.Fprintf(os.Stderr, "Warning: No issues found with label %q\n", *labelScope)
+				logging.Warn(fmt.Sprintf("No issues found with label matching %q", *labelScope))
 			}
 		} else {
 			// Replace issues with the subgraph issues
@@ -1064,7 +1536,8 @@ func main() {
 			cfg := analysis.DefaultLabelHealthConfig()
 			allHealth := analysis.ComputeAllLabelHealth(issues, cfg, time.Now().UTC(), nil)
 			for i := range allHealth.Labels {
-				if allHealth.Labels[i].Label == *labelScope {
+				// A pattern spans several labels, so only an exact label gets health context
+				if !labelMatcher.IsPattern() && allHealth.Labels[i].Label == *labelScope {
 					labelScopeContext = &allHealth.Labels[i]
 					break
 				}
@@ -1072,6 +1545,43 @@ func main() {
 		}
 	}
 
+	// --since window: restrict reported beads while analysis still uses the full graph
+	var sinceWindow *analysis.SinceWindow
+	var sinceIDs map[string]bool
+	if *sinceWindowSpec != "" {
+		window, ids, err := analysis.ComputeSinceWindow(issues, *sinceWindowSpec, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		sinceWindow = &window
+		sinceIDs = ids
+	}
+
+	// --priority-min/--priority-max: same idea, restricting reported beads to a priority band
+	var priorityRange *analysis.PriorityRange
+	var priorityIDs map[string]bool
+	if *priorityMin >= 0 || *priorityMax >= 0 {
+		pr, ids, err := analysis.ComputePriorityRange(issues, *priorityMin, *priorityMax)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		priorityRange = &pr
+		priorityIDs = ids
+	}
+	scopeIDs := analysis.IntersectScopes(sinceIDs, priorityIDs)
+
+	if _, err := analysis.ParseWindow("--closed-window", *closedWindowSpec, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Handle --profile: report analysis timing on stderr, then carry on
+	if *profileRun {
+		writeProfile(os.Stderr, issues, loadDuration, *profileJSON, *forceFullAnalysis)
+	}
+
 	// Handle semantic search CLI (bv-9gf.3)
 	if *robotSearch && *semanticQuery == "" {
 		fmt.Fprintln(os.Stderr, "Error: --robot-search requires --search \"query\"")
@@ -1084,11 +1594,17 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-		searchCfg, err = applySearchConfigOverrides(searchCfg, *searchMode, *searchPreset, *searchWeights)
+		searchCfg, err = applySearchConfigOverrides(searchCfg, *searchMode, *searchPreset, *searchWeights, *weightsSpec)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
+		if *searchFusion != "" {
+			if searchCfg.Fusion, err = search.ParseFusionMode(*searchFusion); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid --fusion: %v\n", err)
+				os.Exit(1)
+			}
+		}
 
 		embedder, err := search.NewEmbedderFromConfig(embedCfg)
 		if err != nil {
@@ -1096,12 +1612,7 @@ func main() {
 			os.Exit(1)
 		}
 
-		projectDir, err := os.Getwd()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
-		}
-		indexPath := search.DefaultIndexPath(projectDir, embedCfg)
+		indexPath := search.DefaultIndexPath(projectRoot(), embedCfg)
 		idx, loaded, err := search.LoadOrNewVectorIndex(indexPath, embedder.Dim())
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -1116,7 +1627,11 @@ func main() {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		syncStats, err := search.SyncVectorIndex(ctx, idx, embedder, docs, 64)
+		syncStats, err := search.SyncVectorIndexWithOptions(ctx, idx, embedder, docs, search.IndexSyncOptions{
+			BatchSize: 64,
+			ModelID:   embedCfg.ModelID(),
+			MaxAge:    embedCfg.MaxAge,
+		})
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error building semantic index: %v\n", err)
 			os.Exit(1)
@@ -1128,7 +1643,15 @@ func main() {
 			}
 		}
 
-		qvecs, err := embedder.Embed(ctx, []string{*semanticQuery})
+		// Phrase and +/- operators filter candidates lexically before any
+		// ranking; only the remaining free text is embedded.
+		queryOps := search.ParseQueryOperators(*semanticQuery)
+		queryText := queryOps.Text
+		if strings.TrimSpace(queryText) == "" {
+			queryText = *semanticQuery
+		}
+
+		qvecs, err := embedder.Embed(ctx, []string{queryText})
 		if err != nil || len(qvecs) != 1 {
 			if err == nil {
 				err = fmt.Errorf("embedder returned %d vectors for query", len(qvecs))
@@ -1143,16 +1666,33 @@ func main() {
 		}
 		fetchLimit := limit
 		if searchCfg.Mode == search.SearchModeHybrid {
-			fetchLimit = search.HybridCandidateLimit(limit, len(issuesForSearch), *semanticQuery)
+			fetchLimit = search.HybridCandidateLimit(limit, len(issuesForSearch), queryText)
+		}
+		candidateLimit := fetchLimit
+		if queryOps.HasOperators() || searchCfg.Fusion == search.FusionRRF {
+			// Rank everything so filtering and fusion see the full lists.
+			fetchLimit = len(docs)
 		}
 		results, err := idx.SearchTopK(qvecs[0], fetchLimit)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error searching index: %v\n", err)
 			os.Exit(1)
 		}
-		results = search.ApplyShortQueryLexicalBoost(results, *semanticQuery, docs)
-		if isLikelyIssueID(*semanticQuery) {
-			results = promoteExactSearchResult(*semanticQuery, results)
+		results = queryOps.FilterResults(results, docs)
+		if searchCfg.Fusion == search.FusionRRF {
+			lexical := queryOps.FilterResults(search.LexicalSearch(queryText, docs), docs)
+			results = search.FuseRRF(search.DefaultRRFK, lexical, results)
+		} else {
+			results = search.ApplyShortQueryLexicalBoost(results, queryText, docs)
+		}
+		if isLikelyIssueID(queryText) {
+			results = promoteExactSearchResult(queryText, results)
+		}
+		if searchCfg.Mode != search.SearchModeHybrid {
+			candidateLimit = limit
+		}
+		if len(results) > candidateLimit {
+			results = results[:candidateLimit]
 		}
 
 		titleByID := make(map[string]string, len(issuesForSearch))
@@ -1170,11 +1710,19 @@ func main() {
 				os.Exit(1)
 			}
 			weights = weights.Normalize()
-			weights = search.AdjustWeightsForQuery(weights, *semanticQuery)
+			weights = search.AdjustWeightsForQuery(weights, queryText)
 			resolvedPreset = presetName
 			resolvedWeights = &weights
 
-			cache := search.NewMetricsCache(search.NewAnalyzerMetricsLoader(issuesForSearch))
+			// Persist metrics under .beads/.cache so repeated robot invocations
+			// against unchanged data skip graph analysis.
+			metricsLoader := search.NewAnalyzerMetricsLoader(issuesForSearch)
+			var cache search.MetricsCache
+			if beadsDir, err := loader.GetBeadsDir(""); err == nil && *asOf == "" && *workspaceConfig == "" {
+				cache = search.NewPersistentMetricsCache(metricsLoader, search.DefaultMetricsCacheDir(beadsDir))
+			} else {
+				cache = search.NewMetricsCache(metricsLoader)
+			}
 			if err := cache.Refresh(); err != nil {
 				fmt.Fprintf(os.Stderr, "Error computing hybrid metrics: %v\n", err)
 				os.Exit(1)
@@ -1186,8 +1734,8 @@ func main() {
 				fmt.Fprintf(os.Stderr, "Error scoring hybrid results: %v\n", err)
 				os.Exit(1)
 			}
-			if isLikelyIssueID(*semanticQuery) {
-				hybridResults = promoteExactHybridResult(*semanticQuery, hybridResults)
+			if isLikelyIssueID(queryText) {
+				hybridResults = promoteExactHybridResult(queryText, hybridResults)
 			}
 			if len(hybridResults) > limit {
 				hybridResults = hybridResults[:limit]
@@ -1208,6 +1756,12 @@ func main() {
 				Limit:       limit,
 				Mode:        searchCfg.Mode,
 			}
+			if searchCfg.Fusion == search.FusionRRF {
+				out.Fusion = searchCfg.Fusion
+			}
+			if queryOps.HasOperators() {
+				out.Operators = &queryOps
+			}
 			if searchCfg.Mode == search.SearchModeHybrid {
 				out.Preset = resolvedPreset
 				out.Weights = resolvedWeights
@@ -1302,10 +1856,9 @@ func main() {
 		}
 
 		// Load and run pre-export hooks (bv-qjc.3)
-		cwd, _ := os.Getwd()
 		var pagesExecutor *hooks.Executor
 		if !*noHooks {
-			hookLoader := hooks.NewLoader(hooks.WithProjectDir(cwd))
+			hookLoader := hooks.NewLoader(hooks.WithProjectDir(projectRoot()))
 			if err := hookLoader.Load(); err != nil {
 				fmt.Printf("  → Warning: failed to load hooks: %v\n", err)
 			} else if hookLoader.HasHooks() {
@@ -1388,6 +1941,11 @@ func main() {
 		if *pagesIncludeHistory {
 			fmt.Println("  → Generating time-travel history data...")
 			if historyReport, err := generateHistoryForExport(issues); err == nil && historyReport != nil {
+				if *anonymize {
+					for i := range historyReport.Commits {
+						historyReport.Commits[i].Message = ""
+					}
+				}
 				historyPath := filepath.Join(*exportPages, "data", "history.json")
 				if historyJSON, err := json.MarshalIndent(historyReport, "", "  "); err == nil {
 					if err := os.WriteFile(historyPath, historyJSON, 0644); err != nil {
@@ -1450,7 +2008,7 @@ func main() {
 				"jq '.results.attention_needed' - Labels needing attention",
 			},
 		}
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(output); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding label health: %v\n", err)
@@ -1480,7 +2038,7 @@ func main() {
 				"jq '.flow.flow_matrix' - raw matrix (row=from, col=to, align with .flow.labels)",
 			},
 		}
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(output); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding label flow: %v\n", err)
@@ -1565,7 +2123,7 @@ func main() {
 			})
 		}
 
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(output); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding label attention: %v\n", err)
@@ -1574,6 +2132,22 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Handle --robot-metrics (also --robot-graph --graph-format=prometheus).
+	// Prometheus text is written as-is, not wrapped in JSON, so it can be scraped.
+	if *robotMetrics || (*robotGraph && strings.EqualFold(*graphFormat, string(export.GraphFormatPrometheus))) {
+		// Label by the project that owns the beads, not the directory bv runs in
+		project, _ := os.Getwd()
+		if beadsDir, err := loader.GetBeadsDir(""); err == nil {
+			project = filepath.Dir(beadsDir)
+		}
+		metrics := export.ComputeBacklogMetrics(issues, filepath.Base(project))
+		if err := export.WritePrometheusMetrics(os.Stdout, metrics); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing metrics: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Handle --robot-graph (bv-136)
 	if *robotGraph {
 		analyzer := analysis.NewAnalyzer(issues)
@@ -1586,16 +2160,25 @@ func main() {
 			format = export.GraphFormatDOT
 		case "mermaid":
 			format = export.GraphFormatMermaid
+		case "dsm":
+			format = export.GraphFormatDSM
 		default:
 			format = export.GraphFormatJSON
 		}
 
+		cluster, err := export.ParseGraphClusterBy(*clusterBy)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
 		config := export.GraphExportConfig{
-			Format:   format,
-			Label:    *labelScope,
-			Root:     *graphRoot,
-			Depth:    *graphDepth,
-			DataHash: dataHash,
+			Format:    format,
+			Label:     *labelScope,
+			Root:      *graphRoot,
+			Depth:     *graphDepth,
+			ClusterBy: cluster,
+			DataHash:  dataHash,
 		}
 
 		result, err := export.ExportGraph(issues, &stats, config)
@@ -1604,7 +2187,7 @@ func main() {
 			os.Exit(1)
 		}
 
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(result); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding graph: %v\n", err)
@@ -1614,7 +2197,7 @@ func main() {
 	}
 
 	// Handle --export-graph (bv-94) - PNG/SVG/HTML export
-	if *exportGraph != "" {
+	if *exportGraph != "" || *exportGraphDir != "" || *exportGraphJSON != "" {
 		analyzer := analysis.NewAnalyzer(issues)
 		stats := analyzer.Analyze()
 
@@ -1623,11 +2206,8 @@ func main() {
 		if *labelScope != "" {
 			var filtered []model.Issue
 			for _, iss := range issues {
-				for _, lbl := range iss.Labels {
-					if strings.EqualFold(lbl, *labelScope) {
-						filtered = append(filtered, iss)
-						break
-					}
+				if labelMatcher.MatchAny(iss.Labels) {
+					filtered = append(filtered, iss)
 				}
 			}
 			exportIssues = filtered
@@ -1643,7 +2223,7 @@ func main() {
 		projectName := filepath.Base(cwd)
 
 		// Check if HTML export requested (interactive graph)
-		if strings.HasSuffix(strings.ToLower(*exportGraph), ".html") || *exportGraph == "html" || *exportGraph == "interactive" {
+		if *exportGraphDir != "" || *exportGraphJSON != "" || strings.HasSuffix(strings.ToLower(*exportGraph), ".html") || *exportGraph == "html" || *exportGraph == "interactive" {
 			title := *graphTitle
 			if title == "" {
 				title = projectName
@@ -1653,6 +2233,15 @@ func main() {
 			triageOpts := analysis.TriageOptions{WaitForPhase2: true}
 			triage := analysis.ComputeTriageWithOptions(exportIssues, triageOpts)
 
+			var typeStyles map[string]export.GraphTypeStyle
+			if *graphTypeConfig != "" {
+				var err error
+				if typeStyles, err = export.LoadGraphTypeStyles(*graphTypeConfig); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+			}
+
 			opts := export.InteractiveGraphOptions{
 				Issues:      exportIssues,
 				Stats:       &stats,
@@ -1661,6 +2250,68 @@ func main() {
 				DataHash:    dataHash,
 				Path:        *exportGraph,
 				ProjectName: projectName,
+				NoAnimation: *noAnimation,
+				NoSanitize:  *noSanitize,
+				Theme:       *graphTheme,
+				Palette:     *graphPalette,
+				TypeStyles:  typeStyles,
+
+				CriticalColor:     *criticalColor,
+				ArticulationColor: *articulationColor,
+
+				CommitURLTemplate: *commitURLTemplate,
+				IssueURLTemplate:  *issueURLTemplate,
+				AvatarURLTemplate: *avatarURLTemplate,
+
+				MaxNodes:      *maxNodes,
+				SampleMetric:  *maxNodesBy,
+				HealthWeights: healthWeights,
+
+				CompressData: *compressData,
+			}
+			// Commit links need commits to point at: embed the correlated
+			// git history when a commit URL template is given.
+			if *commitURLTemplate != "" {
+				if report, err := generateExportHistoryReport(exportIssues); err == nil {
+					if *anonymize {
+						anonymizer.HistoryReport(report, exportIssues)
+					}
+					opts.History = report
+				} else {
+					logging.Warn("no commit history for --commit-url-template", "error", err)
+				}
+			}
+			var payload export.GraphPayloadStats
+			opts.PayloadStats = &payload
+			noteSampling := func() {
+				if *maxNodes > 0 && len(exportIssues) > *maxNodes {
+					fmt.Printf("  Kept the %d most important beads by %s (--max-nodes); %d omitted\n", *maxNodes, *maxNodesBy, len(exportIssues)-*maxNodes)
+				}
+			}
+			if *exportGraphJSON != "" {
+				opts.Path = *exportGraphJSON
+				if err := export.GenerateInteractiveGraphJSON(opts); err != nil {
+					fmt.Fprintf(os.Stderr, "Error exporting graph JSON: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Printf("✓ Graph data exported to %s (%d nodes, %d edges)\n", *exportGraphJSON, len(exportIssues), stats.EdgeCount)
+				noteSampling()
+				os.Exit(0)
+			}
+			if *exportGraphDir != "" {
+				if *compressData {
+					fmt.Fprintf(os.Stderr, "Error: --compress-data applies to single-file exports; let the web server compress --export-dir output\n")
+					os.Exit(1)
+				}
+				indexPath, err := export.GenerateInteractiveGraphDir(opts, *exportGraphDir)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error exporting interactive graph: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Printf("✓ Interactive graph exported to %s (%d nodes, %d edges)\n", indexPath, len(exportIssues), stats.EdgeCount)
+				noteSampling()
+				fmt.Printf("  Serve it with: python3 -m http.server --directory %s\n", *exportGraphDir)
+				os.Exit(0)
 			}
 			// Auto-generate filename if just "html" or "interactive"
 			if *exportGraph == "html" || *exportGraph == "interactive" {
@@ -1672,6 +2323,11 @@ func main() {
 				os.Exit(1)
 			}
 			fmt.Printf("✓ Interactive graph exported to %s (%d nodes, %d edges)\n", outputPath, len(exportIssues), stats.EdgeCount)
+			noteSampling()
+			if payload.Compressed {
+				fmt.Printf("  Data payload gzipped: %.1f KB → %.1f KB (%.0f%% smaller)\n",
+					float64(payload.RawBytes)/1024, float64(payload.EmbeddedBytes)/1024, payload.SavedPercentage)
+			}
 			os.Exit(0)
 		}
 
@@ -1697,7 +2353,6 @@ func main() {
 
 	// Handle --robot-alerts (drift + proactive)
 	if *robotAlerts {
-		projectDir, _ := os.Getwd()
 		driftConfig, err := drift.LoadConfig(projectDir)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading drift config: %v\n", err)
@@ -1793,7 +2448,7 @@ func main() {
 			output.Summary.Total++
 		}
 
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(output); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding alerts: %v\n", err)
@@ -1807,6 +2462,15 @@ func main() {
 		config := analysis.DefaultSuggestAllConfig()
 		config.MinConfidence = *suggestConfidence
 		config.FilterBead = *suggestBead
+		if *suggestLang != "" {
+			if !slices.Contains(analysis.StopWordLanguages(), strings.ToLower(*suggestLang)) {
+				fmt.Fprintf(os.Stderr, "Invalid suggest-lang: %s (use: %s)\n", *suggestLang, strings.Join(analysis.StopWordLanguages(), ", "))
+				os.Exit(1)
+			}
+			stop := analysis.StopWordsFor(*suggestLang)
+			config.Duplicates.StopWords = stop
+			config.Dependencies.StopWords = stop
+		}
 
 		// Parse filter type
 		switch *suggestType {
@@ -1827,7 +2491,7 @@ func main() {
 
 		output := analysis.GenerateRobotSuggestOutput(issues, config, dataHash)
 
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(output); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding suggestions: %v\n", err)
@@ -1836,6 +2500,29 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Handle --robot-summary: single-line heartbeat, intentionally unindented
+	if *robotSummary {
+		summary, _ := analysis.ComputeRobotSummary(issues, dataHash)
+		if err := newRobotEncoder(os.Stdout).Encode(summary); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding summary: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Handle --robot-lint
+	if *robotLint {
+		output := analysis.GenerateRobotLintOutput(issues, analysis.DefaultLintConfig(), dataHash)
+
+		encoder := newRobotEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding lint findings: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Handle --profile-startup
 	if *profileStartup {
 		runProfileStartup(issues, loadDuration, *profileJSON, *forceFullAnalysis)
@@ -1965,7 +2652,7 @@ func main() {
 		driftConfig, err := drift.LoadConfig(projectDir)
 		if err != nil {
 			if !envRobot {
-				fmt.Fprintf(os.Stderr, "Warning: Error loading drift config: %v\n", err)
+				logging.Warn("Error loading drift config", "error", err)
 			}
 			driftConfig = drift.DefaultConfig()
 		}
@@ -2001,7 +2688,7 @@ func main() {
 			output.Baseline.CreatedAt = bl.CreatedAt.Format(time.RFC3339)
 			output.Baseline.CommitSHA = bl.CommitSHA
 
-			encoder := json.NewEncoder(os.Stdout)
+			encoder := newRobotEncoder(os.Stdout)
 			encoder.SetIndent("", "  ")
 			if err := encoder.Encode(output); err != nil {
 				fmt.Fprintf(os.Stderr, "Error encoding drift result: %v\n", err)
@@ -2015,160 +2702,21 @@ func main() {
 		os.Exit(result.ExitCode())
 	}
 
+	insightsOpts := robotInsightsOptions{
+		ForceFull:     *forceFullAnalysis,
+		DataHash:      dataHash,
+		AsOf:          *asOf,
+		AsOfCommit:    asOfResolved,
+		LabelScope:    *labelScope,
+		LabelContext:  labelScopeContext,
+		AsOfTime:      asOfTime,
+		ZombieAge:     time.Duration(*zombieDays) * 24 * time.Hour,
+		HealthWeights: healthWeights,
+	}
 	if *robotInsights {
-		analyzer := analysis.NewAnalyzer(issues)
-		if *forceFullAnalysis {
-			cfg := analysis.FullAnalysisConfig()
-			analyzer.SetConfig(&cfg)
-		}
-		stats := analyzer.Analyze()
-		// Generate top 50 lists for summary, but full stats are included in the struct
-		insights := stats.GenerateInsights(50)
-
-		// Add project-level velocity snapshot (using dedicated helper for efficiency)
-		if v := analysis.ComputeProjectVelocity(issues, time.Now(), 8); v != nil {
-			snap := &analysis.VelocitySnapshot{
-				Closed7:   v.ClosedLast7Days,
-				Closed30:  v.ClosedLast30Days,
-				AvgDays:   v.AvgDaysToClose,
-				Estimated: v.Estimated,
-			}
-			if len(v.Weekly) > 0 {
-				snap.Weekly = make([]int, len(v.Weekly))
-				for i := range v.Weekly {
-					snap.Weekly[i] = v.Weekly[i].Closed
-				}
-			}
-			insights.Velocity = snap
-		}
-
-		// Optional cap for metric maps to avoid overload
-		limitMaps := func(m map[string]float64, limit int) map[string]float64 {
-			if limit <= 0 || limit >= len(m) {
-				return m
-			}
-			type kv struct {
-				k string
-				v float64
-			}
-			var items []kv
-			for k, v := range m {
-				items = append(items, kv{k, v})
-			}
-			sort.Slice(items, func(i, j int) bool {
-				if items[i].v == items[j].v {
-					return items[i].k < items[j].k
-				}
-				return items[i].v > items[j].v
-			})
-			trim := make(map[string]float64, limit)
-			for i := 0; i < limit; i++ {
-				trim[items[i].k] = items[i].v
-			}
-			return trim
-		}
-
-		limitMapInt := func(m map[string]int, limit int) map[string]int {
-			if limit <= 0 || len(m) <= limit {
-				return m
-			}
-			trim := make(map[string]int, limit)
-			count := 0
-			for k, v := range m {
-				trim[k] = v
-				count++
-				if count >= limit {
-					break
-				}
-			}
-			return trim
-		}
-
-		limitSlice := func(s []string, limit int) []string {
-			if limit <= 0 || len(s) <= limit {
-				return s
-			}
-			return s[:limit]
-		}
-
-		// Default cap to keep payload small; allow override via env
-		mapLimit := 200
-		if v := os.Getenv("BV_INSIGHTS_MAP_LIMIT"); v != "" {
-			if n, err := strconv.Atoi(v); err == nil && n > 0 {
-				mapLimit = n
-			}
-		}
-
-		fullStats := struct {
-			PageRank          map[string]float64 `json:"pagerank"`
-			Betweenness       map[string]float64 `json:"betweenness"`
-			Eigenvector       map[string]float64 `json:"eigenvector"`
-			Hubs              map[string]float64 `json:"hubs"`
-			Authorities       map[string]float64 `json:"authorities"`
-			CriticalPathScore map[string]float64 `json:"critical_path_score"`
-			CoreNumber        map[string]int     `json:"core_number"`
-			Slack             map[string]float64 `json:"slack"`
-			Articulation      []string           `json:"articulation_points"`
-		}{
-			PageRank:          limitMaps(stats.PageRank(), mapLimit),
-			Betweenness:       limitMaps(stats.Betweenness(), mapLimit),
-			Eigenvector:       limitMaps(stats.Eigenvector(), mapLimit),
-			Hubs:              limitMaps(stats.Hubs(), mapLimit),
-			Authorities:       limitMaps(stats.Authorities(), mapLimit),
-			CriticalPathScore: limitMaps(stats.CriticalPathScore(), mapLimit),
-			CoreNumber:        limitMapInt(stats.CoreNumber(), mapLimit),
-			Slack:             limitMaps(stats.Slack(), mapLimit),
-			Articulation:      limitSlice(stats.ArticulationPoints(), mapLimit),
-		}
-
-		// Get top what-if deltas for issues with highest downstream impact (bv-83)
-		topWhatIfs := analyzer.TopWhatIfDeltas(10)
-
-		// Generate advanced insights with canonical structure (bv-181)
-		advancedInsights := analyzer.GenerateAdvancedInsights(analysis.DefaultAdvancedInsightsConfig())
+		output := buildRobotInsights(issues, insightsOpts)
 
-		output := struct {
-			GeneratedAt    string                  `json:"generated_at"`
-			DataHash       string                  `json:"data_hash"`
-			AsOf           string                  `json:"as_of,omitempty"`        // Historical snapshot ref
-			AsOfCommit     string                  `json:"as_of_commit,omitempty"` // Resolved commit SHA
-			AnalysisConfig analysis.AnalysisConfig `json:"analysis_config"`
-			Status         analysis.MetricStatus   `json:"status"`
-			LabelScope     string                  `json:"label_scope,omitempty"`   // bv-122: Label filter applied
-			LabelContext   *analysis.LabelHealth   `json:"label_context,omitempty"` // bv-122: Health context for scoped label
-			analysis.Insights
-			FullStats        interface{}                `json:"full_stats"`
-			TopWhatIfs       []analysis.WhatIfEntry     `json:"top_what_ifs,omitempty"`      // Issues with highest downstream impact (bv-83)
-			AdvancedInsights *analysis.AdvancedInsights `json:"advanced_insights,omitempty"` // bv-181: Canonical advanced features
-			UsageHints       []string                   `json:"usage_hints"`                 // bv-84: Agent-friendly hints
-		}{
-			GeneratedAt:      time.Now().UTC().Format(time.RFC3339),
-			DataHash:         dataHash,
-			AsOf:             *asOf,
-			AsOfCommit:       asOfResolved,
-			AnalysisConfig:   stats.Config,
-			Status:           stats.Status(),
-			LabelScope:       *labelScope,
-			LabelContext:     labelScopeContext,
-			Insights:         insights,
-			FullStats:        fullStats,
-			TopWhatIfs:       topWhatIfs,
-			AdvancedInsights: advancedInsights,
-			UsageHints: []string{
-				"jq '.Bottlenecks[:5] | map(.ID)' - Top 5 bottleneck IDs",
-				"jq '.CriticalPath[:3]' - Top 3 critical path items",
-				"jq '.top_what_ifs[] | select(.delta.direct_unblocks > 2)' - High-impact items",
-				"jq '.full_stats.pagerank | to_entries | sort_by(-.value)[:5]' - Top PageRank",
-				"jq '.full_stats.core_number | to_entries | sort_by(-.value)[:5]' - Strongly embedded nodes (k-core)",
-				"jq '.full_stats.articulation_points' - Structural cut points",
-				"jq '.Slack[:5]' - Nodes with slack (good parallel work candidates)",
-				"jq '.Cycles | length' - Count of detected cycles",
-				"jq '.advanced_insights.cycle_break' - Cycle break suggestions (bv-181)",
-				"BV_INSIGHTS_MAP_LIMIT=50 bv --robot-insights - Reduce map sizes",
-			},
-		}
-
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(output); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding insights: %v\n", err)
@@ -2178,34 +2726,7 @@ func main() {
 	}
 
 	if *robotPlan {
-		analyzer := analysis.NewAnalyzer(issues)
-		// For --robot-plan we primarily need Phase 1 metrics (degree/topo/density).
-		// However, we still emit a stable status contract for agents. If the user
-		// explicitly asks for full analysis, honor it; otherwise, skip expensive
-		// centrality metrics and record the skip reasons deterministically.
-		cfg := analysis.ConfigForSize(len(issues), countEdges(issues))
-		if *forceFullAnalysis {
-			cfg = analysis.FullAnalysisConfig()
-		} else {
-			const skipReason = "not computed for --robot-plan"
-			cfg.ComputePageRank = false
-			cfg.PageRankSkipReason = skipReason
-			cfg.ComputeBetweenness = false
-			cfg.BetweennessMode = analysis.BetweennessSkip
-			cfg.BetweennessSkipReason = skipReason
-			cfg.ComputeHITS = false
-			cfg.HITSSkipReason = skipReason
-			cfg.ComputeEigenvector = false
-			cfg.ComputeCriticalPath = false
-			cfg.ComputeCycles = false
-			cfg.CyclesSkipReason = skipReason
-		}
-
-		plan := analyzer.GetExecutionPlan()
-
-		stats := analyzer.AnalyzeAsyncWithConfig(context.Background(), cfg)
-		stats.WaitForPhase2()
-		status := stats.Status()
+		plan, cfg, status := computeRobotPlan(issues, *forceFullAnalysis)
 
 		// Wrap with metadata
 		output := struct {
@@ -2235,10 +2756,12 @@ func main() {
 				"jq '.plan.tracks[].items[] | select(.unblocks | length > 0)' - Items that unblock others",
 				"jq '.plan.summary' - High-level execution summary",
 				"jq '[.plan.tracks[].items[]] | length' - Total items across all tracks",
+				"jq '.plan.schedule.do_first' - Zero-slack (critical path) items to start first",
+				"jq '.plan.schedule.parallel_safe' - Items safe to hand to separate agents concurrently",
 			},
 		}
 
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(output); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding execution plan: %v\n", err)
@@ -2247,6 +2770,14 @@ func main() {
 		os.Exit(0)
 	}
 
+	if *applyPriorities {
+		if err := runApplyPriorities(os.Stdout, issues, beadsPath, *applyMinConfidence, *applyDryRun); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	if *robotPriority {
 		analyzer := analysis.NewAnalyzer(issues)
 		cfg := analysis.ConfigForSize(len(issues), countEdges(issues))
@@ -2272,6 +2803,10 @@ func main() {
 			if *robotMinConf > 0 && rec.Confidence < *robotMinConf {
 				continue
 			}
+			// Filter by --since window and priority range
+			if scopeIDs != nil && !scopeIDs[rec.IssueID] {
+				continue
+			}
 			// Filter by label
 			if *robotByLabel != "" {
 				if iss, ok := issueMap[rec.IssueID]; ok {
@@ -2328,8 +2863,10 @@ func main() {
 			AsOfCommit        string                                    `json:"as_of_commit,omitempty"` // Resolved commit SHA
 			AnalysisConfig    analysis.AnalysisConfig                   `json:"analysis_config"`
 			Status            analysis.MetricStatus                     `json:"status"`
-			LabelScope        string                                    `json:"label_scope,omitempty"`   // bv-122: Label filter applied
-			LabelContext      *analysis.LabelHealth                     `json:"label_context,omitempty"` // bv-122: Health context for scoped label
+			LabelScope        string                                    `json:"label_scope,omitempty"`    // bv-122: Label filter applied
+			LabelContext      *analysis.LabelHealth                     `json:"label_context,omitempty"`  // bv-122: Health context for scoped label
+			Since             *analysis.SinceWindow                     `json:"since,omitempty"`          // --since window and excluded counts
+			PriorityRange     *analysis.PriorityRange                   `json:"priority_range,omitempty"` // --priority-min/--priority-max and excluded counts
 			Recommendations   []analysis.EnhancedPriorityRecommendation `json:"recommendations"`
 			FieldDescriptions map[string]string                         `json:"field_descriptions"`
 			Filters           struct {
@@ -2353,6 +2890,8 @@ func main() {
 			Status:            status,
 			LabelScope:        *labelScope,
 			LabelContext:      labelScopeContext,
+			Since:             sinceWindow,
+			PriorityRange:     priorityRange,
 			Recommendations:   recommendations,
 			FieldDescriptions: analysis.DefaultFieldDescriptions(),
 			Usage: []string{
@@ -2373,7 +2912,7 @@ func main() {
 		output.Summary.Recommendations = len(recommendations)
 		output.Summary.HighConfidence = highConfidence
 
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(output); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding priority recommendations: %v\n", err)
@@ -2382,77 +2921,83 @@ func main() {
 		os.Exit(0)
 	}
 
+	// bv-87: Support track/label-aware grouping for multi-agent coordination
+	triageOpts := analysis.TriageOptions{
+		GroupByTrack:  *robotTriageByTrack,
+		GroupByLabel:  *robotTriageByLabel,
+		WaitForPhase2: true, // Triage needs full graph metrics
+		ScopeIDs:      scopeIDs,
+		WIPLimit:      *wipLimit,
+		WIPAssignee:   *robotByAssignee,
+
+		RecentlyClosedWindow: *closedWindowSpec,
+	}
 	if *robotTriage || *robotNext || *robotTriageByTrack || *robotTriageByLabel {
-		// bv-87: Support track/label-aware grouping for multi-agent coordination
-		opts := analysis.TriageOptions{
-			GroupByTrack:  *robotTriageByTrack,
-			GroupByLabel:  *robotTriageByLabel,
-			WaitForPhase2: true, // Triage needs full graph metrics
+		// --robot-next with no open beads has nothing to rank: answer from the
+		// degree-only pass instead of waiting on PageRank and betweenness
+		writeNoNextPick := func(wip *analysis.WIPStatus) {
+			output := struct {
+				GeneratedAt string                  `json:"generated_at"`
+				DataHash    string                  `json:"data_hash"`
+				AsOf        string                  `json:"as_of,omitempty"`
+				AsOfCommit  string                  `json:"as_of_commit,omitempty"`
+				Since       *analysis.SinceWindow   `json:"since,omitempty"`
+				Priority    *analysis.PriorityRange `json:"priority_range,omitempty"`
+				Message     string                  `json:"message"`
+				*analysis.WIPStatus
+			}{
+				GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+				DataHash:    dataHash,
+				AsOf:        *asOf,
+				AsOfCommit:  asOfResolved,
+				Since:       sinceWindow,
+				Priority:    priorityRange,
+				WIPStatus:   wip,
+				Message:     "No actionable items available",
+			}
+			encoder := newRobotEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(output); err != nil {
+				fmt.Fprintf(os.Stderr, "Error encoding robot-next: %v\n", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
 		}
-		triage := analysis.ComputeTriageWithOptions(issues, opts)
-
-		// bv-90: Load feedback data for output
-		var feedbackInfo *analysis.FeedbackJSON
-		if robotTriageBeadsDir, err := loader.GetBeadsDir(""); err == nil {
-			if feedbackData, err := analysis.LoadFeedback(robotTriageBeadsDir); err == nil && len(feedbackData.Events) > 0 {
-				info := feedbackData.ToJSON()
-				feedbackInfo = &info
+		if *robotNext && analysis.NewAnalyzer(issues).DegreeStats().Open == 0 {
+			var wip *analysis.WIPStatus
+			if *wipLimit > 0 {
+				status := analysis.ComputeWIPStatus(issues, *wipLimit, *robotByAssignee)
+				wip = &status
 			}
+			writeNoNextPick(wip)
 		}
 
 		if *robotNext {
-			// Minimal output: just the top pick
-			if len(triage.QuickRef.TopPicks) == 0 {
-				output := struct {
-					GeneratedAt string `json:"generated_at"`
-					DataHash    string `json:"data_hash"`
-					AsOf        string `json:"as_of,omitempty"`
-					AsOfCommit  string `json:"as_of_commit,omitempty"`
-					Message     string `json:"message"`
-				}{
-					GeneratedAt: time.Now().UTC().Format(time.RFC3339),
-					DataHash:    dataHash,
-					AsOf:        *asOf,
-					AsOfCommit:  asOfResolved,
-					Message:     "No actionable items available",
-				}
-				encoder := json.NewEncoder(os.Stdout)
-				encoder.SetIndent("", "  ")
-				if err := encoder.Encode(output); err != nil {
-					fmt.Fprintf(os.Stderr, "Error encoding robot-next: %v\n", err)
-					os.Exit(1)
-				}
-				os.Exit(0)
+			pick, triage := computeNextPick(issues, triageOpts)
+			if pick == nil {
+				writeNoNextPick(triage.WIP)
 			}
-
-			top := triage.QuickRef.TopPicks[0]
 			output := struct {
-				GeneratedAt string   `json:"generated_at"`
-				DataHash    string   `json:"data_hash"`
-				AsOf        string   `json:"as_of,omitempty"`
-				AsOfCommit  string   `json:"as_of_commit,omitempty"`
-				ID          string   `json:"id"`
-				Title       string   `json:"title"`
-				Score       float64  `json:"score"`
-				Reasons     []string `json:"reasons"`
-				Unblocks    int      `json:"unblocks"`
-				ClaimCmd    string   `json:"claim_command"`
-				ShowCmd     string   `json:"show_command"`
+				GeneratedAt string                  `json:"generated_at"`
+				DataHash    string                  `json:"data_hash"`
+				AsOf        string                  `json:"as_of,omitempty"`
+				AsOfCommit  string                  `json:"as_of_commit,omitempty"`
+				Since       *analysis.SinceWindow   `json:"since,omitempty"`
+				Priority    *analysis.PriorityRange `json:"priority_range,omitempty"`
+				nextPick
+				*analysis.WIPStatus
 			}{
 				GeneratedAt: time.Now().UTC().Format(time.RFC3339),
 				DataHash:    dataHash,
 				AsOf:        *asOf,
 				AsOfCommit:  asOfResolved,
-				ID:          top.ID,
-				Title:       top.Title,
-				Score:       top.Score,
-				Reasons:     top.Reasons,
-				Unblocks:    top.Unblocks,
-				ClaimCmd:    fmt.Sprintf("bd update %s --status=in_progress", top.ID),
-				ShowCmd:     fmt.Sprintf("bd show %s", top.ID),
+				Since:       sinceWindow,
+				Priority:    priorityRange,
+				nextPick:    *pick,
+				WIPStatus:   triage.WIP,
 			}
 
-			encoder := json.NewEncoder(os.Stdout)
+			encoder := newRobotEncoder(os.Stdout)
 			encoder.SetIndent("", "  ")
 			if err := encoder.Encode(output); err != nil {
 				fmt.Fprintf(os.Stderr, "Error encoding robot-next: %v\n", err)
@@ -2461,20 +3006,35 @@ func main() {
 			os.Exit(0)
 		}
 
+		triage := analysis.ComputeTriageWithOptions(issues, triageOpts)
+
+		// bv-90: Load feedback data for output
+		var feedbackInfo *analysis.FeedbackJSON
+		if robotTriageBeadsDir, err := loader.GetBeadsDir(""); err == nil {
+			if feedbackData, err := analysis.LoadFeedback(robotTriageBeadsDir); err == nil && len(feedbackData.Events) > 0 {
+				info := feedbackData.ToJSON()
+				feedbackInfo = &info
+			}
+		}
+
 		// Full triage output with usage hints
 		output := struct {
-			GeneratedAt string                 `json:"generated_at"`
-			DataHash    string                 `json:"data_hash"`
-			AsOf        string                 `json:"as_of,omitempty"`        // Historical snapshot ref (e.g., HEAD~30)
-			AsOfCommit  string                 `json:"as_of_commit,omitempty"` // Resolved commit SHA
-			Triage      analysis.TriageResult  `json:"triage"`
-			Feedback    *analysis.FeedbackJSON `json:"feedback,omitempty"` // bv-90: Feedback loop state
-			UsageHints  []string               `json:"usage_hints"`        // bv-84: Agent-friendly hints
+			GeneratedAt string                  `json:"generated_at"`
+			DataHash    string                  `json:"data_hash"`
+			AsOf        string                  `json:"as_of,omitempty"`          // Historical snapshot ref (e.g., HEAD~30)
+			AsOfCommit  string                  `json:"as_of_commit,omitempty"`   // Resolved commit SHA
+			Since       *analysis.SinceWindow   `json:"since,omitempty"`          // --since window and excluded counts
+			Priority    *analysis.PriorityRange `json:"priority_range,omitempty"` // --priority-min/--priority-max and excluded counts
+			Triage      analysis.TriageResult   `json:"triage"`
+			Feedback    *analysis.FeedbackJSON  `json:"feedback,omitempty"` // bv-90: Feedback loop state
+			UsageHints  []string                `json:"usage_hints"`        // bv-84: Agent-friendly hints
 		}{
 			GeneratedAt: time.Now().UTC().Format(time.RFC3339),
 			DataHash:    dataHash,
 			AsOf:        *asOf,
 			AsOfCommit:  asOfResolved,
+			Since:       sinceWindow,
+			Priority:    priorityRange,
 			Triage:      triage,
 			Feedback:    feedbackInfo,
 			UsageHints: []string{
@@ -2483,8 +3043,11 @@ func main() {
 				"jq '.triage.blockers_to_clear | map(.id)' - High-impact blockers to clear",
 				"jq '.triage.recommendations[] | select(.type == \"bug\")' - Bug-focused recommendations",
 				"jq '.triage.quick_ref.top_picks[] | select(.unblocks > 2)' - High-impact picks",
+				"jq '.triage.recommendations | sort_by(-.unblock_impact)[:3]' - Highest leverage, counting cascading unblocks",
 				"jq '.triage.quick_wins' - Low-effort, high-impact items",
-				"--robot-next - Get only the single top recommendation",
+				"jq '.triage.recently_closed | {count, by_type}' - What got done recently (window: --closed-window)",
+				"--robot-next - Get the next bead to work on (ranked without centrality metrics)",
+				"--robot-ready - Alias for --robot-next, matching `bd ready` (actionable, unblocked work)",
 				"--robot-triage-by-track - Group by execution track for multi-agent coordination",
 				"--robot-triage-by-label - Group by label for area-focused agents",
 				"jq '.triage.recommendations_by_track[].top_pick' - Top pick per track",
@@ -2492,7 +3055,7 @@ func main() {
 				"jq '.feedback.weight_adjustments' - View feedback-adjusted weights (bv-90)",
 			},
 		}
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(output); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding robot-triage: %v\n", err)
@@ -2532,6 +3095,18 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Handle --export-todo flag
+	if *exportTodo != "" {
+		triage := analysis.ComputeTriage(issues)
+		checklist := export.GenerateTodoChecklist(triage.Recommendations, dataHash)
+		if err := os.WriteFile(*exportTodo, []byte(checklist), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing todo checklist: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Done! Triage checklist (%d items) saved to %s\n", len(triage.Recommendations), *exportTodo)
+		os.Exit(0)
+	}
+
 	// Handle --agent-brief flag (bv-131)
 	if *agentBrief != "" {
 		fmt.Printf("Generating agent brief bundle to %s/...\n", *agentBrief)
@@ -2775,8 +3350,12 @@ func main() {
 			}
 		}
 
+		if *anonymize {
+			anonymizer.HistoryReport(report, issues)
+		}
+
 		// Output JSON
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(report); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding history report: %v\n", err)
@@ -2802,7 +3381,7 @@ func main() {
 		// Handle --robot-correlation-stats
 		if *robotCorrelationStats {
 			stats := feedbackStore.GetStats()
-			encoder := json.NewEncoder(os.Stdout)
+			encoder := newRobotEncoder(os.Stdout)
 			encoder.SetIndent("", "  ")
 			if err := encoder.Encode(stats); err != nil {
 				fmt.Fprintf(os.Stderr, "Error encoding stats: %v\n", err)
@@ -2886,7 +3465,7 @@ func main() {
 				explanation.Recommendation = fmt.Sprintf("Already has feedback: %s", fb.Type)
 			}
 
-			encoder := json.NewEncoder(os.Stdout)
+			encoder := newRobotEncoder(os.Stdout)
 			encoder.SetIndent("", "  ")
 			if err := encoder.Encode(explanation); err != nil {
 				fmt.Fprintf(os.Stderr, "Error encoding explanation: %v\n", err)
@@ -2957,7 +3536,7 @@ func main() {
 				"reason":    *correlationFeedbackReason,
 				"orig_conf": originalConf,
 			}
-			encoder := json.NewEncoder(os.Stdout)
+			encoder := newRobotEncoder(os.Stdout)
 			encoder.SetIndent("", "  ")
 			if err := encoder.Encode(result); err != nil {
 				fmt.Fprintf(os.Stderr, "Error encoding result: %v\n", err)
@@ -3028,7 +3607,7 @@ func main() {
 				"reason":    *correlationFeedbackReason,
 				"orig_conf": originalConf,
 			}
-			encoder := json.NewEncoder(os.Stdout)
+			encoder := newRobotEncoder(os.Stdout)
 			encoder.SetIndent("", "  ")
 			if err := encoder.Encode(result); err != nil {
 				fmt.Fprintf(os.Stderr, "Error encoding result: %v\n", err)
@@ -3116,7 +3695,7 @@ func main() {
 			orphanReport.Stats.AvgSuspicion = float64(totalSuspicion) / float64(len(filteredCandidates))
 		}
 
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(orphanReport); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding orphan report: %v\n", err)
@@ -3174,7 +3753,7 @@ func main() {
 		// Create file lookup
 		fileLookup := correlation.NewFileLookup(report)
 
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 
 		if *fileHotspots {
@@ -3305,7 +3884,7 @@ func main() {
 			AffectedBeads: impactResult.AffectedBeads,
 		}
 
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(output); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding impact analysis: %v\n", err)
@@ -3383,7 +3962,7 @@ func main() {
 			RelatedFiles: result.RelatedFiles,
 		}
 
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(output); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding file relations: %v\n", err)
@@ -3474,7 +4053,7 @@ func main() {
 			DataHash:          report.DataHash,
 		}
 
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(output); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding related work: %v\n", err)
@@ -3520,7 +4099,7 @@ func main() {
 			Result:      result,
 		}
 
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(output); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding blocker chain: %v\n", err)
@@ -3599,7 +4178,7 @@ func main() {
 		// Generate result
 		result := network.ToResult(beadID, depth)
 
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(result); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding impact network: %v\n", err)
@@ -3673,7 +4252,7 @@ func main() {
 			os.Exit(1)
 		}
 
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(result); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding causality result: %v\n", err)
@@ -3710,7 +4289,7 @@ func main() {
 				os.Exit(1)
 			}
 			// Output single sprint as JSON
-			encoder := json.NewEncoder(os.Stdout)
+			encoder := newRobotEncoder(os.Stdout)
 			encoder.SetIndent("", "  ")
 			if err := encoder.Encode(found); err != nil {
 				fmt.Fprintf(os.Stderr, "Error encoding sprint: %v\n", err)
@@ -3727,7 +4306,7 @@ func main() {
 				SprintCount: len(sprints),
 				Sprints:     sprints,
 			}
-			encoder := json.NewEncoder(os.Stdout)
+			encoder := newRobotEncoder(os.Stdout)
 			encoder.SetIndent("", "  ")
 			if err := encoder.Encode(output); err != nil {
 				fmt.Fprintf(os.Stderr, "Error encoding sprints: %v\n", err)
@@ -3790,7 +4369,7 @@ func main() {
 			burndown.ScopeChanges = scopeChanges
 		}
 
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(burndown); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding burndown: %v\n", err)
@@ -3947,7 +4526,7 @@ func main() {
 			output.Filters = filters
 		}
 
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if outputErr = encoder.Encode(output); outputErr != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding forecast: %v\n", outputErr)
@@ -4105,7 +4684,10 @@ func main() {
 		}
 		// Sort by blocks count descending
 		sort.Slice(bottlenecks, func(i, j int) bool {
-			return bottlenecks[i].BlocksCount > bottlenecks[j].BlocksCount
+			if bottlenecks[i].BlocksCount != bottlenecks[j].BlocksCount {
+				return bottlenecks[i].BlocksCount > bottlenecks[j].BlocksCount
+			}
+			return bottlenecks[i].ID < bottlenecks[j].ID
 		})
 		if len(bottlenecks) > 5 {
 			bottlenecks = bottlenecks[:5]
@@ -4153,7 +4735,7 @@ func main() {
 		// Suppress unused variable warning
 		_ = medianMinutes
 
-		encoder := json.NewEncoder(os.Stdout)
+		encoder := newRobotEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		if err := encoder.Encode(output); err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding capacity: %v\n", err)
@@ -4162,8 +4744,12 @@ func main() {
 		os.Exit(0)
 	}
 
-	// Handle --diff-since flag
-	if *diffSince != "" {
+	// Handle --diff-since and --git-ref flags
+	if *diffSince != "" && *gitRef != "" {
+		fmt.Fprintln(os.Stderr, "Error: --diff-since and --git-ref are mutually exclusive")
+		os.Exit(2)
+	}
+	if *diffSince != "" || *gitRef != "" {
 		// Auto-enable robot diff for non-interactive/agent contexts
 		if !*robotDiff && (envRobot || !stdoutIsTTY) {
 			*robotDiff = true
@@ -4177,17 +4763,30 @@ func main() {
 
 		gitLoader := loader.NewGitLoader(cwd)
 
-		// Load historical issues
-		historicalIssues, err := gitLoader.LoadAt(*diffSince)
+		// Load historical issues; with --git-ref a ref lacking beads counts as empty
+		since := *diffSince
+		var historicalIssues []model.Issue
+		fromMissing := false
+		if *gitRef != "" {
+			since = *gitRef
+			var found bool
+			historicalIssues, found, err = gitLoader.LoadAtRef(*gitRef)
+			fromMissing = !found
+		} else {
+			historicalIssues, err = gitLoader.LoadAt(*diffSince)
+		}
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading issues at %s: %v\n", *diffSince, err)
+			fmt.Fprintf(os.Stderr, "Error loading issues at %s: %v\n", since, err)
 			os.Exit(1)
 		}
+		if *anonymize {
+			historicalIssues = anonymizer.Issues(historicalIssues)
+		}
 
 		// Get revision info for timestamp
-		revision, err := gitLoader.ResolveRevision(*diffSince)
+		revision, err := gitLoader.ResolveRevision(since)
 		if err != nil {
-			revision = *diffSince
+			revision = since
 		}
 
 		// Create snapshots
@@ -4202,6 +4801,8 @@ func main() {
 			output := struct {
 				GeneratedAt      string                 `json:"generated_at"`
 				ResolvedRevision string                 `json:"resolved_revision"`
+				GitRef           string                 `json:"git_ref,omitempty"`      // --git-ref compared against the working copy
+				FromMissing      bool                   `json:"from_missing,omitempty"` // No beads file at --git-ref
 				AsOf             string                 `json:"as_of,omitempty"`        // "to" snapshot ref (if --as-of used)
 				AsOfCommit       string                 `json:"as_of_commit,omitempty"` // Resolved commit SHA for "to"
 				FromDataHash     string                 `json:"from_data_hash"`
@@ -4210,6 +4811,8 @@ func main() {
 			}{
 				GeneratedAt:      time.Now().UTC().Format(time.RFC3339),
 				ResolvedRevision: revision,
+				GitRef:           *gitRef,
+				FromMissing:      fromMissing,
 				AsOf:             *asOf,
 				AsOfCommit:       asOfResolved,
 				FromDataHash:     analysis.ComputeDataHash(historicalIssues),
@@ -4217,7 +4820,7 @@ func main() {
 				Diff:             diff,
 			}
 
-			encoder := json.NewEncoder(os.Stdout)
+			encoder := newRobotEncoder(os.Stdout)
 			encoder.SetIndent("", "  ")
 			if err := encoder.Encode(output); err != nil {
 				fmt.Fprintf(os.Stderr, "Error encoding diff: %v\n", err)
@@ -4225,7 +4828,7 @@ func main() {
 			}
 		} else {
 			// Human-readable output
-			printDiffSummary(diff, *diffSince)
+			printDiffSummary(diff, since)
 		}
 		os.Exit(0)
 	}
@@ -4251,10 +4854,9 @@ func main() {
 		fmt.Printf("Exporting to %s...\n", *exportFile)
 
 		// Load and run pre-export hooks
-		cwd, _ := os.Getwd()
 		var executor *hooks.Executor
 		if !*noHooks {
-			hookLoader := hooks.NewLoader(hooks.WithProjectDir(cwd))
+			hookLoader := hooks.NewLoader(hooks.WithProjectDir(projectRoot()))
 			if err := hookLoader.Load(); err != nil {
 				fmt.Printf("Warning: failed to load hooks: %v\n", err)
 			} else if hookLoader.HasHooks() {
@@ -4297,15 +4899,23 @@ func main() {
 		os.Exit(0)
 	}
 
-	if len(issues) == 0 {
-		fmt.Println("No issues found. Create some with 'bd create'!")
-		os.Exit(0)
-	}
-
 	// Apply recipe filters and sorting if specified
 	if activeRecipe != nil {
-		issues = applyRecipeFilters(issues, activeRecipe)
-		issues = applyRecipeSort(issues, activeRecipe)
+		var run recipe.RunRecord
+		builders := recipeAnalysisBuilders{
+			"triage":   func(all []model.Issue) any { return analysis.ComputeTriageWithOptions(all, triageOpts) },
+			"insights": func(all []model.Issue) any { return buildRobotInsights(all, insightsOpts) },
+			"plan": func(all []model.Issue) any {
+				plan, _, _ := computeRobotPlan(all, *forceFullAnalysis)
+				return plan
+			},
+		}
+		issues, run = runRecipe(issues, activeRecipe, recipeLoader.Source(activeRecipe.Name), builders, time.Now())
+		if path := recipe.DefaultHistoryPath(); path != "" {
+			if err := recipe.AppendRun(path, run); err != nil {
+				logging.Warn("could not record recipe run", "error", err)
+			}
+		}
 	}
 
 	// Background mode rollout (bv-o11l):
@@ -4332,6 +4942,9 @@ func main() {
 	// Initial Model with live reload support
 	m := ui.NewModel(issues, activeRecipe, beadsPath)
 	defer m.Stop() // Clean up file watcher
+	if len(loadWarnings) > 0 {
+		m.ShowLoadWarnings(loadWarnings)
+	}
 
 	// Enable workspace mode if loading from workspace config
 	if workspaceInfo != nil {
@@ -4427,6 +5040,22 @@ func runTUIProgram(m ui.Model) error {
 	return err
 }
 
+// emptyProjectHints are the usage hints robot output carries when there are no
+// beads, telling agents how to get started. noBeadsData means the .beads
+// directory (or the JSONL inside it) does not exist yet.
+func emptyProjectHints(noBeadsData bool) []string {
+	hints := []string{
+		"bd create --title=\"...\" --type=task --priority=2 - Create the first bead",
+		"bd dep add <issue> <depends-on> - Record a blocking dependency between beads",
+	}
+	if noBeadsData {
+		hints = append([]string{
+			"No .beads directory found: run 'bd init' in the project root (or set BEADS_DIR) to start tracking beads",
+		}, hints...)
+	}
+	return hints
+}
+
 // countEdges counts blocking dependencies for config sizing
 func countEdges(issues []model.Issue) int {
 	count := 0
@@ -4798,6 +5427,11 @@ func applyRecipeFilters(issues []model.Issue, r *recipe.Recipe) []model.Issue {
 			}
 		}
 
+		// IDs filter
+		if len(f.IDs) > 0 && !slices.Contains(f.IDs, issue.ID) {
+			continue
+		}
+
 		result = append(result, issue)
 	}
 
@@ -4852,15 +5486,284 @@ func applyRecipeSort(issues []model.Issue, r *recipe.Recipe) []model.Issue {
 	return issues
 }
 
+// recipeSortFields are the fields applyRecipeSort knows how to order by
+var recipeSortFields = map[string]bool{
+	"priority": true, "created": true, "updated": true, "title": true, "id": true, "status": true,
+}
+
+// recipeAnalysisBuilders builds the document each analyze: step name binds
+// against, from all loaded issues; main wires in the robot commands' builders
+// so a recipe sees the same JSON as --robot-triage, --robot-insights and
+// --robot-plan.
+type recipeAnalysisBuilders map[string]func([]model.Issue) any
+
+// runRecipe applies a recipe's filter and sort steps, then its extra steps
+// (skipping those whose when: condition is false), and returns the result
+// along with a run record for the recipe history
+func runRecipe(issues []model.Issue, r *recipe.Recipe, source string, builders recipeAnalysisBuilders, now time.Time) ([]model.Issue, recipe.RunRecord) {
+	all := issues
+	rec := recipe.RunRecord{
+		Recipe:    r.Name,
+		Source:    source,
+		StartedAt: now,
+		Params:    make(map[string]string),
+	}
+	f := r.Filters
+	if len(f.Status) > 0 {
+		rec.Params["filters.status"] = strings.Join(f.Status, ",")
+	}
+	if len(f.Priority) > 0 {
+		ps := make([]string, len(f.Priority))
+		for i, p := range f.Priority {
+			ps[i] = strconv.Itoa(p)
+		}
+		rec.Params["filters.priority"] = strings.Join(ps, ",")
+	}
+	if len(f.Tags) > 0 {
+		rec.Params["filters.tags"] = strings.Join(f.Tags, ",")
+	}
+	if len(f.ExcludeTags) > 0 {
+		rec.Params["filters.exclude_tags"] = strings.Join(f.ExcludeTags, ",")
+	}
+	if f.HasBlockers != nil {
+		rec.Params["filters.has_blockers"] = strconv.FormatBool(*f.HasBlockers)
+	}
+	if f.Actionable != nil {
+		rec.Params["filters.actionable"] = strconv.FormatBool(*f.Actionable)
+	}
+	if f.TitleContains != "" {
+		rec.Params["filters.title_contains"] = f.TitleContains
+	}
+	if f.IDPrefix != "" {
+		rec.Params["filters.id_prefix"] = f.IDPrefix
+	}
+	if len(f.IDs) > 0 {
+		rec.Params["filters.ids"] = strings.Join(f.IDs, ",")
+	}
+
+	// Resolve relative times so the record shows the cutoffs actually used
+	var timeErrs []string
+	for _, tf := range []struct{ key, value string }{
+		{"filters.created_after", f.CreatedAfter},
+		{"filters.created_before", f.CreatedBefore},
+		{"filters.updated_after", f.UpdatedAfter},
+		{"filters.updated_before", f.UpdatedBefore},
+	} {
+		if tf.value == "" {
+			continue
+		}
+		t, err := recipe.ParseRelativeTime(tf.value, now)
+		if err != nil {
+			rec.Params[tf.key] = tf.value
+			timeErrs = append(timeErrs, fmt.Sprintf("%s: %v", tf.key, err))
+			continue
+		}
+		rec.Params[tf.key] = t.Format(time.RFC3339)
+	}
+
+	filter := recipe.RunStep{Name: "filter", Input: len(issues), Outcome: recipe.OutcomeOK}
+	issues = applyRecipeFilters(issues, r)
+	filter.Output = len(issues)
+	if len(timeErrs) > 0 {
+		// applyRecipeFilters ignores unparseable times; the run still records them
+		filter.Outcome = recipe.OutcomeFailed
+		filter.Error = strings.Join(timeErrs, "; ")
+	}
+	rec.Steps = append(rec.Steps, filter)
+
+	sortStep := recipe.RunStep{Name: "sort", Input: len(issues), Output: len(issues), Outcome: recipe.OutcomeOK}
+	switch {
+	case r.Sort.Field == "":
+		sortStep.Outcome = recipe.OutcomeSkipped
+		sortStep.Detail = "no sort field"
+	case !recipeSortFields[r.Sort.Field]:
+		sortStep.Outcome = recipe.OutcomeFailed
+		sortStep.Error = fmt.Sprintf("unknown sort field %q", r.Sort.Field)
+	default:
+		rec.Params["sort.field"] = r.Sort.Field
+		if r.Sort.Direction != "" {
+			rec.Params["sort.direction"] = r.Sort.Direction
+		}
+		issues = applyRecipeSort(issues, r)
+	}
+	rec.Steps = append(rec.Steps, sortStep)
+
+	var state map[string]float64
+	vars := make(map[string]string)
+	analyses := make(map[string]any)
+	for i, step := range r.Steps {
+		rs := recipe.RunStep{Name: r.StepName(i), Input: len(issues), Output: len(issues), Outcome: recipe.OutcomeOK}
+		if step.When != "" {
+			cond, err := recipe.ParseCondition(step.When)
+			if err != nil {
+				rs.Outcome = recipe.OutcomeFailed
+				rs.Error = err.Error()
+				rec.Steps = append(rec.Steps, rs)
+				continue
+			}
+			if state == nil {
+				state = recipeConditionState(all)
+			}
+			state["count"] = float64(len(issues))
+			if !cond.Eval(state) {
+				rs.Outcome = recipe.OutcomeSkipped
+				rs.Detail = "when: " + cond.String() + " is false"
+				rec.Steps = append(rec.Steps, rs)
+				continue
+			}
+		}
+		if step.Analyze != "" {
+			bindRecipeAnalysis(all, step, builders, analyses, vars, &rs)
+			rec.Steps = append(rec.Steps, rs)
+			continue
+		}
+		var unbound []string
+		for _, ref := range step.Filters.VarRefs() {
+			if _, ok := vars[ref]; !ok {
+				unbound = append(unbound, "${"+ref+"}")
+			}
+		}
+		if len(unbound) > 0 {
+			rs.Outcome = recipe.OutcomeSkipped
+			rs.Detail = "unbound " + strings.Join(unbound, ", ")
+			rec.Steps = append(rec.Steps, rs)
+			continue
+		}
+		stepRecipe := &recipe.Recipe{Filters: step.Filters.Expand(vars), Sort: step.Sort}
+		issues = applyRecipeFilters(issues, stepRecipe)
+		if step.Sort.Field != "" {
+			if recipeSortFields[step.Sort.Field] {
+				issues = applyRecipeSort(issues, stepRecipe)
+			} else {
+				rs.Outcome = recipe.OutcomeFailed
+				rs.Error = fmt.Sprintf("unknown sort field %q", step.Sort.Field)
+			}
+		}
+		rs.Output = len(issues)
+		rec.Steps = append(rec.Steps, rs)
+	}
+
+	if len(vars) > 0 {
+		rec.Vars = vars
+	}
+	rec.Finish(time.Now())
+	return issues, rec
+}
+
+// bindRecipeAnalysis runs an analyze step: it builds the named robot
+// analysis over all issues (once per run, cached in analyses), then binds
+// each variable to the value at its path. A path that is missing or empty
+// (say, no top picks) leaves the variable unbound, and later steps that use
+// it are skipped.
+func bindRecipeAnalysis(issues []model.Issue, step recipe.Step, builders recipeAnalysisBuilders, analyses map[string]any, vars map[string]string, rs *recipe.RunStep) {
+	doc, ok := analyses[step.Analyze]
+	if !ok {
+		build, known := builders[step.Analyze]
+		if !known {
+			rs.Outcome = recipe.OutcomeFailed
+			rs.Error = fmt.Sprintf("unknown analysis %q", step.Analyze)
+			return
+		}
+		payload := build(issues)
+		data, err := json.Marshal(payload)
+		if err == nil {
+			err = json.Unmarshal(data, &doc)
+		}
+		if err != nil {
+			rs.Outcome = recipe.OutcomeFailed
+			rs.Error = fmt.Sprintf("encoding %s: %v", step.Analyze, err)
+			return
+		}
+		analyses[step.Analyze] = doc
+	}
+
+	var unbound []string
+	for _, name := range step.BindNames() {
+		if v, ok := recipe.LookupPath(doc, step.Bind[name]); ok && v != "" {
+			vars[name] = v
+		} else {
+			delete(vars, name)
+			unbound = append(unbound, name)
+		}
+	}
+	if len(unbound) > 0 {
+		rs.Detail = "unbound (no value in " + step.Analyze + "): " + strings.Join(unbound, ", ")
+	}
+}
+
+// recipeConditionState computes the graph-state variables that recipe step
+// conditions can reference (see recipe.ConditionVars); "count" is set per step
+func recipeConditionState(issues []model.Issue) map[string]float64 {
+	state := map[string]float64{"issue_count": float64(len(issues))}
+	open := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		if !issue.Status.IsClosed() {
+			open[issue.ID] = true
+		}
+	}
+	for _, issue := range issues {
+		switch {
+		case issue.Status.IsClosed():
+			state["closed_count"]++
+			continue
+		case issue.Status == model.StatusOpen:
+			state["open_count"]++
+		case issue.Status == model.StatusInProgress:
+			state["in_progress_count"]++
+		}
+		blocked := false
+		for _, dep := range issue.Dependencies {
+			if dep != nil && dep.Type.IsBlocking() && open[dep.DependsOnID] && dep.DependsOnID != issue.ID {
+				blocked = true
+				break
+			}
+		}
+		if blocked {
+			state["blocked_count"]++
+		} else {
+			state["actionable_count"]++
+		}
+	}
+	_, inCycle := analysis.NewAnalyzer(issues).TopologicalLayers()
+	state["cycle_beads"] = float64(len(inCycle))
+	if len(inCycle) > 0 {
+		state["has_cycles"] = 1
+	}
+	return state
+}
+
+// printRecipeHistory lists recent recipe runs, newest first
+func printRecipeHistory(w io.Writer, runs []recipe.RunRecord) {
+	if len(runs) == 0 {
+		fmt.Fprintln(w, "No recipe runs recorded.")
+		return
+	}
+	for _, run := range runs {
+		fmt.Fprintf(w, "%s  %-15s %-7s %.1fms", run.StartedAt.Local().Format("2006-01-02 15:04:05"), run.Recipe, run.Outcome, run.DurationMs)
+		if run.Source != "" {
+			fmt.Fprintf(w, "  (%s)", run.Source)
+		}
+		fmt.Fprintln(w)
+		for _, step := range run.Steps {
+			fmt.Fprintf(w, "    %-8s %-7s %d -> %d", step.Name, step.Outcome, step.Input, step.Output)
+			if step.Error != "" {
+				fmt.Fprintf(w, "  error: %s", step.Error)
+			} else if step.Detail != "" {
+				fmt.Fprintf(w, "  (%s)", step.Detail)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+}
+
 // runProfileStartup runs profiled startup analysis and outputs results
 func runProfileStartup(issues []model.Issue, loadDuration time.Duration, jsonOutput bool, forceFullAnalysis bool) {
-	// Get actual beads path (respects BEADS_DIR)
-	beadsDir, _ := loader.GetBeadsDir("")
-	dataPath, _ := loader.FindJSONLPath(beadsDir)
-	if dataPath == "" {
-		dataPath = beadsDir // fallback
-	}
+	writeProfile(os.Stdout, issues, loadDuration, jsonOutput, forceFullAnalysis)
+}
 
+// profileAnalysis runs a synchronous, timed analysis of issues using the same
+// size-based config as normal startup (or the full config when forced).
+func profileAnalysis(issues []model.Issue, forceFullAnalysis bool) *analysis.StartupProfile {
 	// Time analyzer construction
 	buildStart := time.Now()
 	analyzer := analysis.NewAnalyzer(issues)
@@ -4883,8 +5786,22 @@ func runProfileStartup(issues []model.Issue, loadDuration time.Duration, jsonOut
 	// Run profiled analysis
 	_, profile := analyzer.AnalyzeWithProfile(config)
 
-	// Add load and build durations to profile
+	// Add build duration to profile
 	profile.BuildGraph = buildDuration
+	return profile
+}
+
+// writeProfile profiles the analysis of issues and writes the report to w,
+// as JSON or human-readable text.
+func writeProfile(w io.Writer, issues []model.Issue, loadDuration time.Duration, jsonOutput bool, forceFullAnalysis bool) {
+	// Get actual beads path (respects BEADS_DIR)
+	beadsDir, _ := loader.GetBeadsDir("")
+	dataPath, _ := loader.FindJSONLPath(beadsDir)
+	if dataPath == "" {
+		dataPath = beadsDir // fallback
+	}
+
+	profile := profileAnalysis(issues, forceFullAnalysis)
 
 	// Calculate total including load
 	totalWithLoad := loadDuration + profile.Total
@@ -4907,88 +5824,104 @@ func runProfileStartup(issues []model.Issue, loadDuration time.Duration, jsonOut
 			Recommendations: generateProfileRecommendations(profile, loadDuration, totalWithLoad),
 		}
 
-		encoder := json.NewEncoder(os.Stdout)
-		encoder.SetIndent("", "  ")
-		if err := encoder.Encode(output); err != nil {
+		// Only stdout is robot output; a stderr profile must not pick up --fields
+		var err error
+		if w == os.Stdout {
+			encoder := newRobotEncoder(w)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(output)
+		} else {
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(output)
+		}
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error encoding profile: %v\n", err)
 			os.Exit(1)
 		}
 	} else {
 		// Human-readable output
-		printProfileReport(profile, loadDuration, totalWithLoad)
+		printProfileReport(w, profile, loadDuration, totalWithLoad)
 	}
 }
 
 // printProfileReport outputs a human-readable startup profile
-func printProfileReport(profile *analysis.StartupProfile, loadDuration, totalWithLoad time.Duration) {
-	fmt.Println("Startup Profile")
-	fmt.Println("===============")
-	fmt.Printf("Data: %d issues, %d dependencies, density=%.4f\n\n",
+func printProfileReport(w io.Writer, profile *analysis.StartupProfile, loadDuration, totalWithLoad time.Duration) {
+	fmt.Fprintln(w, "Startup Profile")
+	fmt.Fprintln(w, "===============")
+	fmt.Fprintf(w, "Data: %d issues, %d dependencies, density=%.4f\n\n",
 		profile.NodeCount, profile.EdgeCount, profile.Density)
 
 	// Phase 1
-	fmt.Println("Phase 1 (blocking):")
-	fmt.Printf("  Load JSONL:      %v\n", formatDuration(loadDuration))
-	fmt.Printf("  Build graph:     %v\n", formatDuration(profile.BuildGraph))
-	fmt.Printf("  Degree:          %v\n", formatDuration(profile.Degree))
-	fmt.Printf("  TopoSort:        %v\n", formatDuration(profile.TopoSort))
-	fmt.Printf("  Total Phase 1:   %v\n\n", formatDuration(loadDuration+profile.BuildGraph+profile.Phase1))
+	fmt.Fprintln(w, "Phase 1 (blocking):")
+	fmt.Fprintf(w, "  Load JSONL:      %v\n", formatDuration(loadDuration))
+	fmt.Fprintf(w, "  Build graph:     %v\n", formatDuration(profile.BuildGraph))
+	fmt.Fprintf(w, "  Degree:          %v\n", formatDuration(profile.Degree))
+	fmt.Fprintf(w, "  TopoSort:        %v\n", formatDuration(profile.TopoSort))
+	fmt.Fprintf(w, "  Total Phase 1:   %v\n\n", formatDuration(loadDuration+profile.BuildGraph+profile.Phase1))
 
 	// Phase 2
-	fmt.Println("Phase 2 (async in normal mode, sync for profiling):")
-	printMetricLine("PageRank", profile.PageRank, profile.PageRankTO, profile.Config.ComputePageRank)
-	printMetricLine("Betweenness", profile.Betweenness, profile.BetweennessTO, profile.Config.ComputeBetweenness)
-	printMetricLine("Eigenvector", profile.Eigenvector, false, profile.Config.ComputeEigenvector)
-	printMetricLine("HITS", profile.HITS, profile.HITSTO, profile.Config.ComputeHITS)
-	printMetricLine("Critical Path", profile.CriticalPath, false, profile.Config.ComputeCriticalPath)
-	printCyclesLine(profile)
-	fmt.Printf("  Total Phase 2:   %v\n\n", formatDuration(profile.Phase2))
+	fmt.Fprintln(w, "Phase 2 (async in normal mode, sync for profiling):")
+	printMetricLine(w, "PageRank", profile.PageRank, profile.PageRankTO, profile.Config.ComputePageRank)
+	printMetricLine(w, "Betweenness", profile.Betweenness, profile.BetweennessTO, profile.Config.ComputeBetweenness)
+	printMetricLine(w, "Eigenvector", profile.Eigenvector, false, profile.Config.ComputeEigenvector)
+	printMetricLine(w, "HITS", profile.HITS, profile.HITSTO, profile.Config.ComputeHITS)
+	printMetricLine(w, "Critical Path", profile.CriticalPath, false, profile.Config.ComputeCriticalPath)
+	printCyclesLine(w, profile)
+	printMetricLine(w, "K-Core", profile.KCore, false, true)
+	printMetricLine(w, "Slack", profile.Slack, false, true)
+	fmt.Fprintf(w, "  Total Phase 2:   %v\n\n", formatDuration(profile.Phase2))
 
 	// Total
-	fmt.Printf("Total startup:     %v\n\n", formatDuration(totalWithLoad))
+	fmt.Fprintf(w, "Total startup:     %v\n\n", formatDuration(totalWithLoad))
 
 	// Configuration used
-	fmt.Println("Configuration:")
-	fmt.Printf("  Size tier: %s\n", getSizeTier(profile.NodeCount))
+	fmt.Fprintln(w, "Configuration:")
+	fmt.Fprintf(w, "  Size tier: %s\n", getSizeTier(profile.NodeCount))
 	skipped := profile.Config.SkippedMetrics()
 	if len(skipped) > 0 {
 		var names []string
 		for _, s := range skipped {
 			names = append(names, s.Name)
 		}
-		fmt.Printf("  Skipped metrics: %s\n", strings.Join(names, ", "))
+		fmt.Fprintf(w, "  Skipped metrics: %s\n", strings.Join(names, ", "))
 	} else {
-		fmt.Println("  All metrics computed")
+		fmt.Fprintln(w, "  All metrics computed")
 	}
-	fmt.Println()
+	if profile.BetweennessApprox {
+		fmt.Fprintf(w, "  Betweenness: approximate (sampled %d of %d nodes)\n", profile.BetweennessSample, profile.NodeCount)
+	} else if profile.Config.ComputeBetweenness {
+		fmt.Fprintln(w, "  Betweenness: exact")
+	}
+	fmt.Fprintln(w)
 
 	// Recommendations
 	recommendations := generateProfileRecommendations(profile, loadDuration, totalWithLoad)
 	if len(recommendations) > 0 {
-		fmt.Println("Recommendations:")
+		fmt.Fprintln(w, "Recommendations:")
 		for _, rec := range recommendations {
-			fmt.Printf("  %s\n", rec)
+			fmt.Fprintf(w, "  %s\n", rec)
 		}
 	}
 }
 
 // printMetricLine prints a single metric timing line
-func printMetricLine(name string, duration time.Duration, timedOut, computed bool) {
+func printMetricLine(w io.Writer, name string, duration time.Duration, timedOut, computed bool) {
 	if !computed {
-		fmt.Printf("  %-14s [Skipped]\n", name+":")
+		fmt.Fprintf(w, "  %-14s [Skipped]\n", name+":")
 		return
 	}
 	suffix := ""
 	if timedOut {
 		suffix = " (TIMEOUT)"
 	}
-	fmt.Printf("  %-14s %v%s\n", name+":", formatDuration(duration), suffix)
+	fmt.Fprintf(w, "  %-14s %v%s\n", name+":", formatDuration(duration), suffix)
 }
 
 // printCyclesLine prints the cycles metric line with count
-func printCyclesLine(profile *analysis.StartupProfile) {
+func printCyclesLine(w io.Writer, profile *analysis.StartupProfile) {
 	if !profile.Config.ComputeCycles {
-		fmt.Printf("  %-14s [Skipped]\n", "Cycles:")
+		fmt.Fprintf(w, "  %-14s [Skipped]\n", "Cycles:")
 		return
 	}
 	suffix := ""
@@ -4999,7 +5932,7 @@ func printCyclesLine(profile *analysis.StartupProfile) {
 	} else {
 		suffix = " (none)"
 	}
-	fmt.Printf("  %-14s %v%s\n", "Cycles:", formatDuration(profile.Cycles), suffix)
+	fmt.Fprintf(w, "  %-14s %v%s\n", "Cycles:", formatDuration(profile.Cycles), suffix)
 }
 
 // formatDuration formats a duration for display, right-aligned
@@ -5077,6 +6010,233 @@ func generateProfileRecommendations(profile *analysis.StartupProfile, loadDurati
 	return recs
 }
 
+// computeRobotPlan computes the --robot-plan execution plan, annotated with
+// slack, and the analysis config and status behind it.
+func computeRobotPlan(issues []model.Issue, forceFull bool) (analysis.ExecutionPlan, analysis.AnalysisConfig, analysis.MetricStatus) {
+	analyzer := analysis.NewAnalyzer(issues)
+	// For --robot-plan we primarily need Phase 1 metrics (degree/topo/density).
+	// However, we still emit a stable status contract for agents. If the user
+	// explicitly asks for full analysis, honor it; otherwise, skip expensive
+	// centrality metrics and record the skip reasons deterministically.
+	cfg := analysis.ConfigForSize(len(issues), countEdges(issues))
+	if forceFull {
+		cfg = analysis.FullAnalysisConfig()
+	} else {
+		const skipReason = "not computed for --robot-plan"
+		cfg.ComputePageRank = false
+		cfg.PageRankSkipReason = skipReason
+		cfg.ComputeBetweenness = false
+		cfg.BetweennessMode = analysis.BetweennessSkip
+		cfg.BetweennessSkipReason = skipReason
+		cfg.ComputeHITS = false
+		cfg.HITSSkipReason = skipReason
+		cfg.ComputeEigenvector = false
+		cfg.ComputeCriticalPath = false
+		cfg.ComputeCycles = false
+		cfg.CyclesSkipReason = skipReason
+	}
+
+	plan := analyzer.GetExecutionPlan()
+
+	stats := analyzer.AnalyzeAsyncWithConfig(context.Background(), cfg)
+	stats.WaitForPhase2()
+	status := stats.Status()
+	analyzer.AnnotatePlanSlack(&plan, stats.Slack())
+
+	return plan, cfg, status
+}
+
+// robotInsightsOptions are the CLI settings --robot-insights is built with.
+type robotInsightsOptions struct {
+	ForceFull     bool
+	DataHash      string
+	AsOf          string
+	AsOfCommit    string
+	AsOfTime      time.Time // Ages are measured from here when set, else from now
+	LabelScope    string
+	LabelContext  *analysis.LabelHealth
+	ZombieAge     time.Duration
+	HealthWeights analysis.HealthWeights
+}
+
+// buildRobotInsights builds the --robot-insights document. Recipe analyze
+// steps bind against the same document.
+func buildRobotInsights(issues []model.Issue, o robotInsightsOptions) any {
+	now := time.Now()
+	if !o.AsOfTime.IsZero() {
+		now = o.AsOfTime
+	}
+	analyzer := analysis.NewAnalyzer(issues)
+	if o.ForceFull {
+		cfg := analysis.FullAnalysisConfig()
+		analyzer.SetConfig(&cfg)
+	}
+	stats := analyzer.Analyze()
+	// Generate top 50 lists for summary, but full stats are included in the struct
+	insights := stats.GenerateInsights(50)
+
+	// Add project-level velocity snapshot (using dedicated helper for efficiency)
+	if v := analysis.ComputeProjectVelocity(issues, time.Now(), 8); v != nil {
+		snap := &analysis.VelocitySnapshot{
+			Closed7:   v.ClosedLast7Days,
+			Closed30:  v.ClosedLast30Days,
+			AvgDays:   v.AvgDaysToClose,
+			Estimated: v.Estimated,
+		}
+		if len(v.Weekly) > 0 {
+			snap.Weekly = make([]int, len(v.Weekly))
+			for i := range v.Weekly {
+				snap.Weekly[i] = v.Weekly[i].Closed
+			}
+		}
+		insights.Velocity = snap
+	}
+
+	// Optional cap for metric maps to avoid overload
+	limitMaps := func(m map[string]float64, limit int) map[string]float64 {
+		if limit <= 0 || limit >= len(m) {
+			return m
+		}
+		type kv struct {
+			k string
+			v float64
+		}
+		var items []kv
+		for k, v := range m {
+			items = append(items, kv{k, v})
+		}
+		sort.Slice(items, func(i, j int) bool {
+			if items[i].v == items[j].v {
+				return items[i].k < items[j].k
+			}
+			return items[i].v > items[j].v
+		})
+		trim := make(map[string]float64, limit)
+		for i := 0; i < limit; i++ {
+			trim[items[i].k] = items[i].v
+		}
+		return trim
+	}
+
+	limitMapInt := func(m map[string]int, limit int) map[string]int {
+		if limit <= 0 || len(m) <= limit {
+			return m
+		}
+		trim := make(map[string]int, limit)
+		count := 0
+		for k, v := range m {
+			trim[k] = v
+			count++
+			if count >= limit {
+				break
+			}
+		}
+		return trim
+	}
+
+	limitSlice := func(s []string, limit int) []string {
+		if limit <= 0 || len(s) <= limit {
+			return s
+		}
+		return s[:limit]
+	}
+
+	// Default cap to keep payload small; allow override via env
+	mapLimit := 200
+	if v := os.Getenv("BV_INSIGHTS_MAP_LIMIT"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			mapLimit = n
+		}
+	}
+
+	fullStats := struct {
+		PageRank          map[string]float64 `json:"pagerank"`
+		Betweenness       map[string]float64 `json:"betweenness"`
+		Eigenvector       map[string]float64 `json:"eigenvector"`
+		Hubs              map[string]float64 `json:"hubs"`
+		Authorities       map[string]float64 `json:"authorities"`
+		CriticalPathScore map[string]float64 `json:"critical_path_score"`
+		CoreNumber        map[string]int     `json:"core_number"`
+		Slack             map[string]float64 `json:"slack"`
+		Articulation      []string           `json:"articulation_points"`
+	}{
+		PageRank:          limitMaps(stats.PageRank(), mapLimit),
+		Betweenness:       limitMaps(stats.Betweenness(), mapLimit),
+		Eigenvector:       limitMaps(stats.Eigenvector(), mapLimit),
+		Hubs:              limitMaps(stats.Hubs(), mapLimit),
+		Authorities:       limitMaps(stats.Authorities(), mapLimit),
+		CriticalPathScore: limitMaps(stats.CriticalPathScore(), mapLimit),
+		CoreNumber:        limitMapInt(stats.CoreNumber(), mapLimit),
+		Slack:             limitMaps(stats.Slack(), mapLimit),
+		Articulation:      limitSlice(stats.ArticulationPoints(), mapLimit),
+	}
+
+	// Get top what-if deltas for issues with highest downstream impact (bv-83)
+	topWhatIfs := analyzer.TopWhatIfDeltas(10)
+
+	// Generate advanced insights with canonical structure (bv-181)
+	advancedInsights := analyzer.GenerateAdvancedInsights(analysis.DefaultAdvancedInsightsConfig())
+
+	return struct {
+		GeneratedAt    string                   `json:"generated_at"`
+		DataHash       string                   `json:"data_hash"`
+		AsOf           string                   `json:"as_of,omitempty"`        // Historical snapshot ref
+		AsOfCommit     string                   `json:"as_of_commit,omitempty"` // Resolved commit SHA
+		AnalysisConfig analysis.EffectiveConfig `json:"analysis_config"`
+		Status         analysis.MetricStatus    `json:"status"`
+		LabelScope     string                   `json:"label_scope,omitempty"`   // bv-122: Label filter applied
+		LabelContext   *analysis.LabelHealth    `json:"label_context,omitempty"` // bv-122: Health context for scoped label
+		analysis.Insights
+		FullStats        interface{}                            `json:"full_stats"`
+		TopWhatIfs       []analysis.WhatIfEntry                 `json:"top_what_ifs,omitempty"`        // Issues with highest downstream impact (bv-83)
+		AdvancedInsights *analysis.AdvancedInsights             `json:"advanced_insights,omitempty"`   // bv-181: Canonical advanced features
+		Acceptance       map[string]analysis.AcceptanceProgress `json:"acceptance_progress,omitempty"` // Checklist completion per bead
+		CycleRisks       analysis.CycleRiskReport               `json:"cycle_risks"`                   // Dependency additions that would create a cycle
+		Convergence      []analysis.ConvergencePoint            `json:"convergence_points"`            // Beads where independent chains meet
+		Zombies          []correlation.ZombieBead               `json:"zombies"`                       // In-progress beads with no recent activity
+		BeadHealth       []analysis.BeadHealth                  `json:"bead_health"`                   // Composite 0-100 health per open bead, least healthy first
+		HealthWeights    analysis.HealthWeights                 `json:"health_weights"`                // Weights behind bead_health (--health-weights)
+		UsageHints       []string                               `json:"usage_hints"`                   // bv-84: Agent-friendly hints
+	}{
+		GeneratedAt:      time.Now().UTC().Format(time.RFC3339),
+		DataHash:         o.DataHash,
+		AsOf:             o.AsOf,
+		AsOfCommit:       o.AsOfCommit,
+		AnalysisConfig:   stats.EffectiveConfig(),
+		Status:           stats.Status(),
+		LabelScope:       o.LabelScope,
+		LabelContext:     o.LabelContext,
+		Insights:         insights,
+		FullStats:        fullStats,
+		TopWhatIfs:       topWhatIfs,
+		AdvancedInsights: advancedInsights,
+		Acceptance:       analysis.ComputeAcceptanceProgress(issues),
+		CycleRisks:       analysis.DetectCycleRisks(issues, analysis.DefaultMaxCycleRisks),
+		Convergence:      analysis.DetectConvergencePoints(issues, analysis.DefaultConvergenceMinFanIn),
+		Zombies:          detectZombieBeads(issues, o.ZombieAge, now),
+		BeadHealth:       analyzer.ComputeBeadHealth(&stats, o.HealthWeights, now),
+		HealthWeights:    o.HealthWeights,
+		UsageHints: []string{
+			"jq '.Bottlenecks[:5] | map(.ID)' - Top 5 bottleneck IDs",
+			"jq '.CriticalPath[:3]' - Top 3 critical path items",
+			"jq '.top_what_ifs[] | select(.delta.direct_unblocks > 2)' - High-impact items",
+			"jq '.full_stats.pagerank | to_entries | sort_by(-.value)[:5]' - Top PageRank",
+			"jq '.full_stats.core_number | to_entries | sort_by(-.value)[:5]' - Strongly embedded nodes (k-core)",
+			"jq '.full_stats.articulation_points' - Structural cut points",
+			"jq '.Slack[:5]' - Nodes with slack (good parallel work candidates)",
+			"jq '.Cycles | length' - Count of detected cycles",
+			"jq '.advanced_insights.cycle_break' - Cycle break suggestions (bv-181)",
+			"jq '.cycle_risks.risks[] | .avoid' - Dependency additions that would close a cycle",
+			"jq '.convergence_points[] | {id, upstream_chains, chains}' - Integration hotspots where independent chains meet",
+			"jq '.zombies[] | {id, assignee, days_since_activity}' - In-progress beads with no recent updates or commits (--zombie-days)",
+			"jq '.bead_health[:5] | map({id, score, penalties})' - Least healthy open beads and why (--health-weights)",
+			"jq '.acceptance_progress | to_entries | map(select(.value.ratio < 1))' - Beads with unchecked acceptance items",
+			"jq '.analysis_config | {size_tier, computed_metrics, betweenness_approximated}' - Result fidelity",
+			"BV_INSIGHTS_MAP_LIMIT=50 bv --robot-insights - Reduce map sizes",
+		},
+	}
+}
+
 // filterByRepo filters issues to only include those from a specific repository.
 // The filter matches issue IDs that start with the given prefix.
 // If the prefix doesn't end with a separator character, it normalizes by checking
@@ -5126,6 +6286,49 @@ func filterByRepo(issues []model.Issue, repoFilter string) []model.Issue {
 	return result
 }
 
+// recipeShadowsSeenFile, under .bv/, lists the recipe shadowing notices
+// already shown in this project.
+const recipeShadowsSeenFile = "recipe-shadows-seen"
+
+// unseenRecipeShadows returns the notices not yet recorded in seenPath and
+// records them, so each override is reported once, and again only when the
+// way it differs from the builtin changes. Recording is best effort.
+func unseenRecipeShadows(shadows []string, seenPath string) []string {
+	if len(shadows) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool)
+	if data, err := os.ReadFile(seenPath); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			seen[line] = true
+		}
+	}
+	var unseen []string
+	for _, s := range shadows {
+		if !seen[s] {
+			unseen = append(unseen, s)
+		}
+	}
+	if len(unseen) > 0 {
+		_ = os.WriteFile(seenPath, []byte(strings.Join(shadows, "\n")+"\n"), 0o644)
+	}
+	return unseen
+}
+
+// filterByProject keeps the beads of one --recursive project. Unlike
+// filterByRepo it matches the project path exactly, so "services/api" does not
+// also take in services/api-gateway.
+func filterByProject(issues []model.Issue, project string) []model.Issue {
+	project = filepath.ToSlash(filepath.Clean(project))
+	var result []model.Issue
+	for _, issue := range issues {
+		if issue.SourceRepo == project {
+			result = append(result, issue)
+		}
+	}
+	return result
+}
+
 // buildMetricItems converts a metrics map to a sorted slice of MetricItems
 func buildMetricItems(metrics map[string]float64, limit int) []baseline.MetricItem {
 	if len(metrics) == 0 {
@@ -5138,9 +6341,12 @@ func buildMetricItems(metrics map[string]float64, limit int) []baseline.MetricIt
 		items = append(items, baseline.MetricItem{ID: id, Value: value})
 	}
 
-	// Sort by value descending
+	// Sort by value descending, ties by ID so equal values keep a stable order
 	sort.Slice(items, func(i, j int) bool {
-		return items[i].Value > items[j].Value
+		if items[i].Value != items[j].Value {
+			return items[i].Value > items[j].Value
+		}
+		return items[i].ID < items[j].ID
 	})
 
 	// Limit to top N
@@ -6394,8 +7600,9 @@ type TimeTravelCommit struct {
 	BeadsClosed []string `json:"beads_closed,omitempty"`
 }
 
-// generateHistoryForExport creates time-travel history data from git history
-func generateHistoryForExport(issues []model.Issue) (*TimeTravelHistory, error) {
+// generateExportHistoryReport correlates the beads with git history in the
+// current repository, for exports that embed commits.
+func generateExportHistoryReport(issues []model.Issue) (*correlation.HistoryReport, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return nil, err
@@ -6428,9 +7635,74 @@ func generateHistoryForExport(issues []model.Issue) (*TimeTravelHistory, error)
 
 	// Generate correlation report
 	correlator := correlation.NewCorrelator(cwd, beadsPath)
-	report, err := correlator.GenerateReport(beadInfos, correlation.CorrelatorOptions{
+	return correlator.GenerateReport(beadInfos, correlation.CorrelatorOptions{
 		Limit: 500, // Reasonable limit for time-travel
 	})
+}
+
+// nextPick is the single recommendation --robot-next (and --robot-ready)
+// and bv serve's /next report.
+type nextPick struct {
+	ID       string   `json:"id"`
+	Title    string   `json:"title"`
+	Score    float64  `json:"score"`
+	Reasons  []string `json:"reasons"`
+	Unblocks int      `json:"unblocks"`
+	Impact   int      `json:"unblock_impact"`
+	ClaimCmd string   `json:"claim_command"`
+	ShowCmd  string   `json:"show_command"`
+	Hints    []string `json:"usage_hints"`
+}
+
+// computeNextPick ranks beads the way --robot-next does: triage under
+// analysis.NextConfig, which skips the centrality metrics one pick does not
+// need, so the pick can differ from the --robot-triage top pick. The pick is
+// nil when nothing is actionable; the triage is returned for its WIP status.
+func computeNextPick(issues []model.Issue, opts analysis.TriageOptions) (*nextPick, analysis.TriageResult) {
+	cfg := analysis.NextConfig()
+	opts.Config = &cfg
+	triage := analysis.ComputeTriageWithOptions(issues, opts)
+	if len(triage.QuickRef.TopPicks) == 0 {
+		return nil, triage
+	}
+	top := triage.QuickRef.TopPicks[0]
+	return &nextPick{
+		ID:       top.ID,
+		Title:    top.Title,
+		Score:    top.Score,
+		Reasons:  top.Reasons,
+		Unblocks: top.Unblocks,
+		Impact:   top.UnblockImpact,
+		ClaimCmd: fmt.Sprintf("bd update %s --status=in_progress", top.ID),
+		ShowCmd:  fmt.Sprintf("bd show %s", top.ID),
+		Hints: []string{
+			"--robot-ready - Alias for --robot-next, matching `bd ready` (actionable, unblocked work)",
+			"--robot-triage - Full ranked recommendations, quick wins and blockers",
+		},
+	}, triage
+}
+
+// detectZombieBeads reports in-progress beads idle for longer than threshold,
+// joining git commit correlation when the repo has history. Git is only
+// consulted when some bead is in progress; without it updated_at is used.
+func detectZombieBeads(issues []model.Issue, threshold time.Duration, now time.Time) []correlation.ZombieBead {
+	var report *correlation.HistoryReport
+	for _, issue := range issues {
+		if issue.Status == model.StatusInProgress {
+			report, _ = generateExportHistoryReport(issues)
+			break
+		}
+	}
+	zombies := correlation.DetectZombies(issues, report, threshold, now)
+	if zombies == nil {
+		zombies = []correlation.ZombieBead{}
+	}
+	return zombies
+}
+
+// generateHistoryForExport creates time-travel history data from git history
+func generateHistoryForExport(issues []model.Issue) (*TimeTravelHistory, error) {
+	report, err := generateExportHistoryReport(issues)
 	if err != nil {
 		return nil, err
 	}
@@ -1,10 +1,13 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -82,6 +85,115 @@ func TestRobotPlanAndPriorityIncludeMetadata(t *testing.T) {
 	runAndCheck("--robot-priority")
 }
 
+// TestRobotPlanFieldsProjection asserts --fields keeps only the requested nested
+// and array paths from --robot-plan and reports paths that do not resolve.
+func TestRobotPlanFieldsProjection(t *testing.T) {
+	dir := t.TempDir()
+	beadsDir := filepath.Join(dir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatalf("mkdir beads: %v", err)
+	}
+	beads := `{"id":"TEST-1","title":"A","status":"open","priority":1,"issue_type":"task"}
+{"id":"TEST-2","title":"B","status":"open","priority":2,"issue_type":"task","dependencies":[{"issue_id":"TEST-2","depends_on_id":"TEST-1","type":"blocks"}]}
+`
+	if err := os.WriteFile(filepath.Join(beadsDir, "beads.jsonl"), []byte(beads), 0o644); err != nil {
+		t.Fatalf("write beads: %v", err)
+	}
+
+	exe := buildTestBinary(t)
+	cmd := exec.Command(exe, "--robot-plan", "--fields", "data_hash,plan.summary.highest_impact,plan.tracks[].items[].id,plan.missing")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("--robot-plan --fields failed: %v, out=%s", err, string(out))
+	}
+
+	var payload struct {
+		DataHash string `json:"data_hash"`
+		Plan     struct {
+			Summary map[string]any   `json:"summary"`
+			Tracks  []map[string]any `json:"tracks"`
+		} `json:"plan"`
+		InvalidFields []string `json:"invalid_fields"`
+	}
+	if err := json.Unmarshal(out, &payload); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	var top map[string]any
+	_ = json.Unmarshal(out, &top)
+	if len(top) != 3 {
+		t.Errorf("expected only data_hash, plan, invalid_fields at top level, got %v", top)
+	}
+	if payload.DataHash == "" {
+		t.Error("expected data_hash to be kept")
+	}
+	if len(payload.Plan.Summary) != 1 || payload.Plan.Summary["highest_impact"] != "TEST-1" {
+		t.Errorf("expected only summary.highest_impact, got %v", payload.Plan.Summary)
+	}
+	if len(payload.Plan.Tracks) == 0 {
+		t.Fatal("expected tracks to be projected")
+	}
+	for _, track := range payload.Plan.Tracks {
+		if len(track) != 1 {
+			t.Errorf("expected track to contain only items, got %v", track)
+		}
+		items, _ := track["items"].([]any)
+		for _, item := range items {
+			if m, _ := item.(map[string]any); len(m) != 1 || m["id"] == nil {
+				t.Errorf("expected item to contain only id, got %v", item)
+			}
+		}
+	}
+	if len(payload.InvalidFields) != 1 || payload.InvalidFields[0] != "plan.missing" {
+		t.Errorf("expected plan.missing reported as invalid, got %v", payload.InvalidFields)
+	}
+}
+
+// TestRobotSummaryContract asserts --robot-summary prints exactly one line of
+// JSON with the heartbeat keys and build info and nothing else.
+func TestRobotSummaryContract(t *testing.T) {
+	dir := t.TempDir()
+	beadsDir := filepath.Join(dir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatalf("mkdir beads: %v", err)
+	}
+	beads := `{"id":"TEST-1","title":"A","status":"open","priority":1,"issue_type":"task"}
+{"id":"TEST-2","title":"B","status":"open","priority":2,"issue_type":"task","dependencies":[{"issue_id":"TEST-2","depends_on_id":"TEST-1","type":"blocks"}]}
+{"id":"TEST-3","title":"C","status":"closed","priority":2,"issue_type":"task"}
+`
+	if err := os.WriteFile(filepath.Join(beadsDir, "beads.jsonl"), []byte(beads), 0o644); err != nil {
+		t.Fatalf("write beads: %v", err)
+	}
+
+	exe := buildTestBinary(t)
+	cmd := exec.Command(exe, "--robot-summary")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("--robot-summary failed: %v, out=%s", err, string(out))
+	}
+	if lines := strings.Count(strings.TrimRight(string(out), "\n"), "\n"); lines != 0 {
+		t.Errorf("expected a single line, got:\n%s", out)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(out, &payload); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	want := []string{"nodes", "edges", "actionable", "blocked", "critical", "cycles", "data_hash", "bv_version", "bv_commit", "bv_build_date"}
+	if len(payload) != len(want) {
+		t.Errorf("expected exactly %v, got %v", want, payload)
+	}
+	for _, key := range want {
+		if _, ok := payload[key]; !ok {
+			t.Errorf("missing key %q in %v", key, payload)
+		}
+	}
+	if payload["nodes"] != float64(3) || payload["edges"] != float64(1) || payload["actionable"] != float64(1) || payload["blocked"] != float64(1) {
+		t.Errorf("unexpected counts: %v", payload)
+	}
+}
+
 // buildTestBinary builds the current module's bv binary for testing.
 func buildTestBinary(t *testing.T) string {
 	t.Helper()
@@ -94,3 +206,275 @@ func buildTestBinary(t *testing.T) string {
 	}
 	return exe
 }
+
+func TestRobotDuplicateIDsWarning(t *testing.T) {
+	dir := t.TempDir()
+	beadsDir := filepath.Join(dir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatalf("mkdir beads: %v", err)
+	}
+	beads := `{"id":"DUP-1","title":"Old","status":"open","priority":1,"issue_type":"task"}
+{"id":"DUP-1","title":"New","status":"open","priority":1,"issue_type":"task"}
+`
+	if err := os.WriteFile(filepath.Join(beadsDir, "beads.jsonl"), []byte(beads), 0o644); err != nil {
+		t.Fatalf("write beads: %v", err)
+	}
+
+	exe := buildTestBinary(t)
+	cmd := exec.Command(exe, "--robot-summary")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("--robot-summary failed: %v, out=%s", err, string(out))
+	}
+	var payload struct {
+		Nodes    int      `json:"nodes"`
+		Warnings []string `json:"warnings"`
+	}
+	if err := json.Unmarshal(out, &payload); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if payload.Nodes != 1 {
+		t.Errorf("expected one node for the duplicated ID, got %d", payload.Nodes)
+	}
+	if len(payload.Warnings) != 1 || !strings.Contains(payload.Warnings[0], "DUP-1 (2 lines)") {
+		t.Errorf("expected a duplicate ID warning, got %v", payload.Warnings)
+	}
+
+	strict := exec.Command(exe, "--robot-summary", "--strict")
+	strict.Dir = dir
+	if out, err := strict.CombinedOutput(); err == nil || !strings.Contains(string(out), "duplicate bead IDs") {
+		t.Errorf("expected --strict to fail on duplicate IDs, got err=%v out=%s", err, out)
+	}
+}
+
+func TestRobotRecursiveTagsBeadsWithProject(t *testing.T) {
+	dir := t.TempDir()
+	for rel, beads := range map[string]string{
+		"services/api": `{"id":"a-1","title":"API","status":"open","priority":1,"issue_type":"task"}`,
+		"services/web": `{"id":"w-1","title":"Web","status":"open","priority":2,"issue_type":"task","dependencies":[{"issue_id":"w-1","depends_on_id":"a-1","type":"blocks"}]}`,
+	} {
+		beadsDir := filepath.Join(dir, rel, ".beads")
+		if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+			t.Fatalf("mkdir beads: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(beadsDir, "issues.jsonl"), []byte(beads+"\n"), 0o644); err != nil {
+			t.Fatalf("write beads: %v", err)
+		}
+	}
+
+	exe := buildTestBinary(t)
+	cmd := exec.Command(exe, "--robot-summary", "--recursive")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("--robot-summary --recursive failed: %v, out=%s", err, string(out))
+	}
+	var payload struct {
+		Nodes    int               `json:"nodes"`
+		Edges    int               `json:"edges"`
+		Projects map[string]string `json:"projects"`
+	}
+	if err := json.Unmarshal(out, &payload); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if payload.Nodes != 2 || payload.Edges != 1 {
+		t.Errorf("nodes/edges = %d/%d, want 2/1 (the cross-project dependency resolves)", payload.Nodes, payload.Edges)
+	}
+	if payload.Projects["api-a-1"] != "services/api" || payload.Projects["web-w-1"] != "services/web" {
+		t.Errorf("projects = %v, want api-a-1 -> services/api, web-w-1 -> services/web", payload.Projects)
+	}
+
+	// From inside a subproject, plain bv finds that project's .beads upward
+	src := filepath.Join(dir, "services", "web", "src")
+	if err := os.MkdirAll(src, 0o755); err != nil {
+		t.Fatalf("mkdir src: %v", err)
+	}
+	sub := exec.Command(exe, "--robot-summary")
+	sub.Dir = src
+	if out, err = sub.Output(); err != nil {
+		t.Fatalf("--robot-summary from a subdirectory failed: %v", err)
+	}
+	var single map[string]any
+	if err := json.Unmarshal(out, &single); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if single["nodes"] != float64(1) || single["projects"] != nil {
+		t.Errorf("subdirectory run: nodes=%v projects=%v, want 1 and no projects map", single["nodes"], single["projects"])
+	}
+}
+
+func TestRobotLiveOnlyDropsClosedBlockers(t *testing.T) {
+	dir := t.TempDir()
+	beadsDir := filepath.Join(dir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatalf("mkdir beads: %v", err)
+	}
+	// B and C both wait on A, which is already closed.
+	beads := `{"id":"A","title":"Blocker","status":"closed","priority":1,"issue_type":"task"}
+{"id":"B","title":"Dependent","status":"open","priority":2,"issue_type":"task","dependencies":[{"issue_id":"B","depends_on_id":"A","type":"blocks"}]}
+{"id":"C","title":"Sibling","status":"open","priority":2,"issue_type":"task","dependencies":[{"issue_id":"C","depends_on_id":"A","type":"blocks"}]}
+`
+	if err := os.WriteFile(filepath.Join(beadsDir, "beads.jsonl"), []byte(beads), 0o644); err != nil {
+		t.Fatalf("write beads: %v", err)
+	}
+	exe := buildTestBinary(t)
+	run := func(args ...string) map[string]any {
+		t.Helper()
+		cmd := exec.Command(exe, args...)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("bv %v failed: %v, out=%s", args, err, out)
+		}
+		var payload map[string]any
+		if err := json.Unmarshal(out, &payload); err != nil {
+			t.Fatalf("json: %v\n%s", err, out)
+		}
+		return payload
+	}
+	tracks := func(args ...string) int {
+		t.Helper()
+		plan, _ := run(args...)["plan"].(map[string]any)
+		list, _ := plan["tracks"].([]any)
+		return len(list)
+	}
+
+	if edges := run("--robot-summary", "--live-only")["edges"]; edges != float64(0) {
+		t.Errorf("--live-only edges = %v, want 0", edges)
+	}
+	if edges := run("--robot-summary")["edges"]; edges != float64(2) {
+		t.Errorf("default edges = %v, want 2 (history is kept)", edges)
+	}
+	if n := tracks("--robot-plan"); n != 1 {
+		t.Errorf("default plan has %d tracks, want 1 (B and C share A)", n)
+	}
+	if n := tracks("--robot-plan", "--live-only"); n != 2 {
+		t.Errorf("--live-only plan has %d tracks, want 2", n)
+	}
+}
+
+func TestRobotLogsStayOnStderr(t *testing.T) {
+	dir := t.TempDir()
+	for path, content := range map[string]string{
+		".beads/beads.jsonl": `{"id":"A","title":"Alpha","status":"open","priority":1,"issue_type":"task"}` + "\n",
+		".bv/config.yaml":    "bogus_setting: 1\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, path), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+	exe := buildTestBinary(t)
+	run := func(env ...string) string {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		cmd := exec.Command(exe, "--robot-summary")
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), env...)
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("--robot-summary %v failed: %v, stderr=%s", env, err, stderr.String())
+		}
+		var payload map[string]any
+		if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+			t.Fatalf("stdout should hold only JSON: %v\n%s", err, stdout.String())
+		}
+		return stderr.String()
+	}
+
+	stderr := run("BV_LOG_LEVEL=", "BV_LOG_FORMAT=json")
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(stderr)), &rec); err != nil {
+		t.Fatalf("stderr should be one JSON log line: %v\n%s", err, stderr)
+	}
+	if rec["level"] != "WARN" || !strings.Contains(fmt.Sprint(rec["msg"]), "unknown setting") {
+		t.Errorf("log record = %v, want the unknown setting warning", rec)
+	}
+
+	if stderr := run("BV_LOG_LEVEL=error", "BV_LOG_FORMAT="); stderr != "" {
+		t.Errorf("BV_LOG_LEVEL=error should silence warnings, stderr=%q", stderr)
+	}
+}
+
+func TestRobotInsightsIncludesBuildInfo(t *testing.T) {
+	dir := t.TempDir()
+	beadsDir := filepath.Join(dir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatalf("mkdir beads: %v", err)
+	}
+	beads := `{"id":"A","title":"Alpha","status":"open","priority":1,"issue_type":"task"}
+`
+	if err := os.WriteFile(filepath.Join(beadsDir, "beads.jsonl"), []byte(beads), 0o644); err != nil {
+		t.Fatalf("write beads: %v", err)
+	}
+
+	exe := buildTestBinary(t)
+	cmd := exec.Command(exe, "--robot-insights")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("--robot-insights failed: %v, out=%s", err, string(out))
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(out, &payload); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	for _, key := range []string{"bv_version", "bv_commit", "bv_build_date"} {
+		if v, ok := payload[key].(string); !ok || v == "" {
+			t.Errorf("expected non-empty %s, got %v", key, payload[key])
+		}
+	}
+}
+
+func TestRobotNextPriorityMaxExcludesLowerPriorities(t *testing.T) {
+	dir := t.TempDir()
+	beadsDir := filepath.Join(dir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatalf("mkdir beads: %v", err)
+	}
+	// HUB (P2) unblocks three beads, so it is the unfiltered top pick
+	beads := `{"id":"HUB","title":"Hub","status":"open","priority":2,"issue_type":"task"}
+{"id":"SOLO","title":"Solo","status":"open","priority":1,"issue_type":"task"}
+{"id":"D1","title":"D1","status":"open","priority":3,"issue_type":"task","dependencies":[{"issue_id":"D1","depends_on_id":"HUB","type":"blocks"}]}
+{"id":"D2","title":"D2","status":"open","priority":3,"issue_type":"task","dependencies":[{"issue_id":"D2","depends_on_id":"HUB","type":"blocks"}]}
+{"id":"D3","title":"D3","status":"open","priority":3,"issue_type":"task","dependencies":[{"issue_id":"D3","depends_on_id":"HUB","type":"blocks"}]}
+`
+	if err := os.WriteFile(filepath.Join(beadsDir, "beads.jsonl"), []byte(beads), 0o644); err != nil {
+		t.Fatalf("write beads: %v", err)
+	}
+
+	exe := buildTestBinary(t)
+	next := func(args ...string) (string, map[string]any) {
+		t.Helper()
+		cmd := exec.Command(exe, append([]string{"--robot-next"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("--robot-next %v failed: %v, out=%s", args, err, out)
+		}
+		var payload map[string]any
+		if err := json.Unmarshal(out, &payload); err != nil {
+			t.Fatalf("json: %v\n%s", err, out)
+		}
+		id, _ := payload["id"].(string)
+		pr, _ := payload["priority_range"].(map[string]any)
+		return id, pr
+	}
+
+	if id, pr := next(); id != "HUB" || pr != nil {
+		t.Fatalf("expected HUB without a priority range, got %q (priority_range=%v)", id, pr)
+	}
+	id, pr := next("--priority-max", "1")
+	if id != "SOLO" {
+		t.Errorf("--priority-max 1 must exclude P2+ beads, got %q", id)
+	}
+	if pr == nil || pr["max"] != float64(1) || pr["included"] != float64(1) || pr["excluded"] != float64(4) {
+		t.Errorf("expected priority_range{max:1,included:1,excluded:4}, got %v", pr)
+	}
+	if _, ok := pr["min"]; ok {
+		t.Errorf("unset --priority-min should be omitted, got %v", pr)
+	}
+}
@@ -5,11 +5,15 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
 )
 
 func TestFilterByRepo_CaseInsensitiveAndFlexibleSeparators(t *testing.T) {
@@ -40,6 +44,28 @@ func TestFilterByRepo_CaseInsensitiveAndFlexibleSeparators(t *testing.T) {
 	}
 }
 
+func TestFilterByProjectMatchesPathExactly(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "api-1", SourceRepo: "services/api"},
+		{ID: "api-gateway-1", SourceRepo: "services/api-gateway"},
+		{ID: "root-1", SourceRepo: "."},
+	}
+	for filter, want := range map[string]string{
+		"services/api":    "api-1",
+		"./services/api/": "api-1",
+		"services":        "",
+		".":               "root-1",
+	} {
+		var ids []string
+		for _, iss := range filterByProject(issues, filter) {
+			ids = append(ids, iss.ID)
+		}
+		if got := strings.Join(ids, ","); got != want {
+			t.Errorf("filterByProject(%q) = %q, want %q", filter, got, want)
+		}
+	}
+}
+
 func TestRobotFlagsOutputJSON(t *testing.T) {
 	tmpDir := t.TempDir()
 	beads := `{"id":"A","title":"Root","status":"open","priority":1,"issue_type":"task"}
@@ -168,8 +194,8 @@ func TestApplyRecipeFilters_DatesBlockersAndPrefix(t *testing.T) {
 	r := &recipe.Recipe{Filters: recipe.FilterConfig{
 		CreatedBefore: "1h",
 		UpdatedBefore: "1h",
-		HasBlockers:  ptrBool(true),
-		IDPrefix:     "API-2",
+		HasBlockers:   ptrBool(true),
+		IDPrefix:      "API-2",
 	}}
 	got := applyRecipeFilters(issues, r)
 	if len(got) != 1 || got[0].ID != "API-2" {
@@ -238,6 +264,158 @@ func TestApplyRecipeSort_DefaultsAndFields(t *testing.T) {
 	}
 }
 
+func TestRunRecipe_AppendsOneHistoryRecord(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	issues := []model.Issue{
+		{ID: "A", Title: "Open", Status: model.StatusOpen, Priority: 2},
+		{ID: "B", Title: "Urgent", Status: model.StatusOpen, Priority: 0},
+		{ID: "C", Title: "Done", Status: model.StatusClosed, Priority: 1},
+	}
+	r := &recipe.Recipe{
+		Name:    "open-by-priority",
+		Filters: recipe.FilterConfig{Status: []string{"open"}, UpdatedAfter: "7d"},
+		Sort:    recipe.SortConfig{Field: "priority"},
+	}
+
+	got, run := runRecipe(issues, r, "project", nil, now)
+	if len(got) != 2 || got[0].ID != "B" {
+		t.Fatalf("expected B then A, got %#v", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "bv", "recipe-runs.jsonl")
+	if err := recipe.AppendRun(path, run); err != nil {
+		t.Fatalf("AppendRun: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading history: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 history line, got %d: %q", len(lines), data)
+	}
+
+	var rec recipe.RunRecord
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatalf("history line is not valid JSON: %v", err)
+	}
+	if rec.Recipe != "open-by-priority" || rec.Source != "project" || rec.Outcome != recipe.OutcomeOK {
+		t.Errorf("recipe/source/outcome = %q/%q/%q", rec.Recipe, rec.Source, rec.Outcome)
+	}
+	if !rec.StartedAt.Equal(now) || rec.DurationMs < 0 {
+		t.Errorf("started_at=%v duration_ms=%v", rec.StartedAt, rec.DurationMs)
+	}
+	if want := now.AddDate(0, 0, -7).Format(time.RFC3339); rec.Params["filters.updated_after"] != want {
+		t.Errorf("updated_after resolved to %q, want %q", rec.Params["filters.updated_after"], want)
+	}
+	if len(rec.Steps) != 2 || rec.Steps[0].Name != "filter" || rec.Steps[0].Input != 3 || rec.Steps[0].Output != 2 || rec.Steps[1].Name != "sort" {
+		t.Errorf("unexpected steps: %+v", rec.Steps)
+	}
+}
+
+func TestRunRecipe_SkipsStepWhenConditionFalse(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "A", Title: "Ready", Status: model.StatusOpen, Priority: 1},
+		{ID: "B", Title: "Later", Status: model.StatusOpen, Priority: 3},
+	}
+	r := &recipe.Recipe{
+		Name: "focus",
+		Steps: []recipe.Step{
+			// Nothing is blocked, so this narrowing must not run
+			{Name: "blocked-only", When: "blocked_count > 0", Filters: recipe.FilterConfig{HasBlockers: ptrBool(true)}},
+			{Name: "top-priority", When: "!has_cycles && count > 1", Filters: recipe.FilterConfig{Priority: []int{1}}},
+		},
+	}
+	if err := r.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	got, run := runRecipe(issues, r, "project", nil, time.Now())
+	if len(got) != 1 || got[0].ID != "A" {
+		t.Fatalf("expected only A, got %#v", got)
+	}
+	if len(run.Steps) != 4 {
+		t.Fatalf("expected filter, sort and two recipe steps, got %+v", run.Steps)
+	}
+	skipped, ran := run.Steps[2], run.Steps[3]
+	if skipped.Name != "blocked-only" || skipped.Outcome != recipe.OutcomeSkipped || !strings.Contains(skipped.Detail, "blocked_count > 0") {
+		t.Errorf("blocked-only step = %+v, want skipped with its condition", skipped)
+	}
+	if ran.Name != "top-priority" || ran.Outcome != recipe.OutcomeOK || ran.Input != 2 || ran.Output != 1 {
+		t.Errorf("top-priority step = %+v, want ok 2 -> 1", ran)
+	}
+	if run.Outcome != recipe.OutcomeOK {
+		t.Errorf("outcome = %q, want ok (skips are not failures)", run.Outcome)
+	}
+}
+
+func TestRunRecipe_AnalyzeStepBindsTopPick(t *testing.T) {
+	blocks := []*model.Dependency{{DependsOnID: "core", Type: model.DepBlocks}}
+	issues := []model.Issue{
+		{ID: "core", Title: "Core", Status: model.StatusOpen, Priority: 1},
+		{ID: "core-docs", Title: "Core docs", Status: model.StatusOpen, Priority: 4},
+		{ID: "ui", Title: "UI", Status: model.StatusOpen, Priority: 2, Dependencies: blocks},
+		{ID: "docs", Title: "Docs", Status: model.StatusOpen, Priority: 2, Dependencies: blocks},
+		{ID: "chore", Title: "Chore", Status: model.StatusOpen, Priority: 3},
+	}
+	r := &recipe.Recipe{
+		Name: "next-up",
+		Steps: []recipe.Step{
+			{Name: "pick", Analyze: "triage", Bind: map[string]string{"top_bead": "quick_ref.top_picks[0].id", "missing": "quick_ref.nope"}},
+			{Name: "stats", Analyze: "insights", Bind: map[string]string{"hash": "data_hash"}},
+			{Name: "focus", Filters: recipe.FilterConfig{IDs: []string{"${top_bead}"}}},
+			{Name: "never", Filters: recipe.FilterConfig{TitleContains: "${missing}"}},
+		},
+	}
+	if err := r.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	builders := recipeAnalysisBuilders{
+		"triage": func(all []model.Issue) any {
+			return analysis.ComputeTriageWithOptions(all, analysis.TriageOptions{WaitForPhase2: true})
+		},
+		"insights": func(all []model.Issue) any {
+			return buildRobotInsights(all, robotInsightsOptions{DataHash: "hash-1", HealthWeights: analysis.DefaultHealthWeights()})
+		},
+	}
+	got, run := runRecipe(issues, r, "project", builders, time.Now())
+	if len(got) != 1 || got[0].ID != "core" {
+		t.Fatalf("expected only the top pick core, got %#v", got)
+	}
+	// insights binds against the --robot-insights document, not bare Insights
+	if run.Vars["top_bead"] != "core" || run.Vars["hash"] != "hash-1" {
+		t.Errorf("vars = %v, want top_bead=core and hash=hash-1", run.Vars)
+	}
+	pick, focus, never := run.Steps[2], run.Steps[4], run.Steps[5]
+	if pick.Outcome != recipe.OutcomeOK || !strings.Contains(pick.Detail, "missing") {
+		t.Errorf("pick step = %+v, want ok noting the unbound variable", pick)
+	}
+	if focus.Outcome != recipe.OutcomeOK || focus.Input != 5 || focus.Output != 1 {
+		t.Errorf("focus step = %+v, want ok 5 -> 1", focus)
+	}
+	if never.Outcome != recipe.OutcomeSkipped || !strings.Contains(never.Detail, "${missing}") {
+		t.Errorf("never step = %+v, want skipped for the unbound variable", never)
+	}
+}
+
+func TestRunRecipe_RecordsFailedSteps(t *testing.T) {
+	r := &recipe.Recipe{
+		Name:    "broken",
+		Filters: recipe.FilterConfig{CreatedAfter: "last tuesday"},
+		Sort:    recipe.SortConfig{Field: "velocity"},
+	}
+	_, run := runRecipe([]model.Issue{{ID: "A"}}, r, "user", nil, time.Now())
+	if run.Outcome != recipe.OutcomeFailed {
+		t.Fatalf("outcome = %q, want failed", run.Outcome)
+	}
+	for i, want := range []string{"invalid time format", "unknown sort field"} {
+		if run.Steps[i].Outcome != recipe.OutcomeFailed || !strings.Contains(run.Steps[i].Error, want) {
+			t.Errorf("step %d = %+v, want failed with %q", i, run.Steps[i], want)
+		}
+	}
+}
+
 func TestFormatCycle(t *testing.T) {
 	if got := formatCycle(nil); got != "(empty)" {
 		t.Fatalf("expected (empty), got %q", got)
@@ -268,3 +446,46 @@ func repoRoot(t *testing.T) string {
 		dir = parent
 	}
 }
+
+func TestApplySearchConfigOverrides_WeightsSpec(t *testing.T) {
+	cfg, err := applySearchConfigOverrides(search.SearchConfig{Mode: search.SearchModeHybrid}, "", "", "", "text=0.6,pagerank=0.4")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.HasWeights || cfg.Weights.TextRelevance != 0.6 || cfg.Weights.PageRank != 0.4 {
+		t.Fatalf("expected custom weights to be applied, got %+v", cfg)
+	}
+	if _, preset, _ := resolveSearchWeights(cfg); preset != "custom" {
+		t.Fatalf("expected custom preset, got %q", preset)
+	}
+
+	_, err = applySearchConfigOverrides(search.SearchConfig{}, "", "", "", "text=0.5,status=-0.1")
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{`"status"`, "allowed range"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error to mention %s, got %q", want, err.Error())
+		}
+	}
+
+	_, err = applySearchConfigOverrides(search.SearchConfig{}, "", "", `{"text":1,"pagerank":0,"status":0,"impact":0,"priority":0,"recency":0}`, "text=1")
+	if err == nil {
+		t.Fatal("expected error when both --weights and --search-weights are set")
+	}
+}
+
+func TestUnseenRecipeShadows(t *testing.T) {
+	seenPath := filepath.Join(t.TempDir(), recipeShadowsSeenFile)
+	first := []string{`project recipe "stale" shadows the builtin and differs in filters`}
+	if got := unseenRecipeShadows(first, seenPath); !reflect.DeepEqual(got, first) {
+		t.Fatalf("first run = %v, want %v", got, first)
+	}
+	if got := unseenRecipeShadows(first, seenPath); len(got) != 0 {
+		t.Errorf("second run = %v, want nothing", got)
+	}
+	changed := []string{`project recipe "stale" shadows the builtin and differs in filters, sort`}
+	if got := unseenRecipeShadows(changed, seenPath); !reflect.DeepEqual(got, changed) {
+		t.Errorf("after a change = %v, want %v", got, changed)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"os"
 	"strings"
 	"testing"
@@ -76,9 +77,9 @@ func TestGenerateProfileRecommendations(t *testing.T) {
 
 func TestPrintMetricAndCyclesLines(t *testing.T) {
 	out := captureStdout(t, func() {
-		printMetricLine("PR", 10*time.Millisecond, true, true)
-		printMetricLine("Skip", 0, false, false)
-		printCyclesLine(&analysis.StartupProfile{
+		printMetricLine(os.Stdout, "PR", 10*time.Millisecond, true, true)
+		printMetricLine(os.Stdout, "Skip", 0, false, false)
+		printCyclesLine(os.Stdout, &analysis.StartupProfile{
 			Config:     analysis.FullAnalysisConfig(),
 			Cycles:     5 * time.Millisecond,
 			CycleCount: 2,
@@ -92,7 +93,7 @@ func TestPrintMetricAndCyclesLines(t *testing.T) {
 func TestPrintCyclesLineSkipped(t *testing.T) {
 	profile := &analysis.StartupProfile{Config: analysis.AnalysisConfig{ComputeCycles: false}}
 	out := captureStdout(t, func() {
-		printCyclesLine(profile)
+		printCyclesLine(os.Stdout, profile)
 	})
 	if !strings.Contains(out, "[Skipped]") {
 		t.Fatalf("expected skipped cycles line, got %q", out)
@@ -152,7 +153,7 @@ func TestPrintProfileReport(t *testing.T) {
 		Config:       cfg,
 	}
 	out := captureStdout(t, func() {
-		printProfileReport(profile, 2*time.Millisecond, 7*time.Millisecond)
+		printProfileReport(os.Stdout, profile, 2*time.Millisecond, 7*time.Millisecond)
 	})
 	if !strings.Contains(out, "Startup Profile") || !strings.Contains(out, "PageRank") {
 		t.Fatalf("printProfileReport missing expected text")
@@ -219,3 +220,37 @@ func TestRunProfileStartupJSON(t *testing.T) {
 		t.Fatalf("expected profile field in output")
 	}
 }
+
+func TestProfileAnalysis_NonTrivialGraph(t *testing.T) {
+	// A chain with a fan-out at every step, so every Phase 2 metric has work
+	var issues []model.Issue
+	for i := 0; i < 60; i++ {
+		iss := model.Issue{ID: fmt.Sprintf("P-%d", i), Status: model.StatusOpen}
+		if i > 0 {
+			iss.Dependencies = append(iss.Dependencies, &model.Dependency{DependsOnID: fmt.Sprintf("P-%d", i-1), Type: model.DepBlocks})
+		}
+		if i > 2 {
+			iss.Dependencies = append(iss.Dependencies, &model.Dependency{DependsOnID: fmt.Sprintf("P-%d", i/2), Type: model.DepBlocks})
+		}
+		issues = append(issues, iss)
+	}
+
+	profile := profileAnalysis(issues, true)
+	if profile.Total <= 0 {
+		t.Fatalf("expected a nonzero Total, got %v", profile.Total)
+	}
+	if profile.NodeCount != 60 || profile.EdgeCount == 0 {
+		t.Errorf("unexpected graph size: %d nodes, %d edges", profile.NodeCount, profile.EdgeCount)
+	}
+	if profile.Phase2 <= 0 || profile.PageRank <= 0 {
+		t.Errorf("expected Phase 2 timings, got phase2=%v pagerank=%v", profile.Phase2, profile.PageRank)
+	}
+
+	var buf bytes.Buffer
+	writeProfile(&buf, issues, time.Millisecond, false, true)
+	for _, want := range []string{"Data: 60 issues", "Betweenness: exact", "K-Core:"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("profile report missing %q:\n%s", want, buf.String())
+		}
+	}
+}
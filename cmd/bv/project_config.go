@@ -0,0 +1,285 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
+	"gopkg.in/yaml.v3"
+)
+
+// projectConfigKey maps a .bv/config.yaml setting to the CLI flag it defaults.
+type projectConfigKey struct {
+	path string // Dotted YAML path, e.g. "graph.theme"
+	flag string
+	env  string // Environment variable that still wins over the config, if any
+}
+
+// projectConfigKeys lists every setting .bv/config.yaml understands, in the
+// order --print-config shows them.
+var projectConfigKeys = []projectConfigKey{
+	{path: "search.mode", flag: "search-mode", env: search.EnvSearchMode},
+	{path: "search.preset", flag: "search-preset", env: search.EnvSearchPreset},
+	{path: "search.weights", flag: "weights", env: search.EnvSearchWeights},
+	{path: "search.fusion", flag: "fusion", env: search.EnvSearchFusion},
+	{path: "graph.format", flag: "graph-format"},
+	{path: "graph.preset", flag: "graph-preset"},
+	{path: "graph.theme", flag: "theme"},
+	{path: "graph.palette", flag: "palette"},
+	{path: "analysis.force_full", flag: "force-full-analysis"},
+	{path: "analysis.health_weights", flag: "health-weights"},
+	{path: "suggest.language", flag: "suggest-lang"},
+	{path: "wip_limit", flag: "wip-limit"},
+}
+
+// projectConfig is the parsed .bv/config.yaml, flattened to dotted paths.
+type projectConfig struct {
+	Path     string
+	Values   map[string]string
+	Warnings []string
+
+	// DefaultPriorities is priorities.defaults: the priority for beads of a
+	// type (lowercase) that omit one, e.g. {bug: 1, docs: 3}.
+	DefaultPriorities map[string]int
+}
+
+// projectRoot returns the directory whose .bv/ holds bv's per-project state
+// (config, recipes, hooks, baseline): the one that owns the .beads bv found,
+// which may be an ancestor of the working directory, or the working directory
+// itself when there is none or BEADS_DIR points elsewhere.
+func projectRoot() string {
+	cwd, _ := os.Getwd()
+	if os.Getenv(loader.BeadsDirEnvVar) == "" {
+		if beadsDir, ok := loader.FindBeadsDirUp(cwd); ok {
+			return filepath.Dir(beadsDir)
+		}
+	}
+	return cwd
+}
+
+// projectConfigPath returns .bv/config.yaml under dir (the project root when
+// empty).
+func projectConfigPath(dir string) string {
+	if dir == "" {
+		dir = projectRoot()
+	}
+	return filepath.Join(dir, ".bv", "config.yaml")
+}
+
+// loadProjectConfig reads .bv/config.yaml under dir. Like the recipe loader it
+// is tolerant: a missing file is an empty config, and a malformed file or
+// unknown key becomes a warning rather than an error.
+func loadProjectConfig(dir string) projectConfig {
+	cfg := projectConfig{Path: projectConfigPath(dir), Values: map[string]string{}}
+
+	data, err := os.ReadFile(cfg.Path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			cfg.Warnings = append(cfg.Warnings, fmt.Sprintf("project config: %v", err))
+		}
+		return cfg
+	}
+
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		cfg.Warnings = append(cfg.Warnings, fmt.Sprintf("project config: parsing %s: %v", cfg.Path, err))
+		return cfg
+	}
+
+	known := make(map[string]bool, len(projectConfigKeys))
+	for _, key := range projectConfigKeys {
+		known[key.path] = true
+	}
+	flattenProjectConfig("", raw, known, &cfg)
+	return cfg
+}
+
+// flattenProjectConfig walks the YAML map, recording known settings as flag
+// values and warning about anything else.
+func flattenProjectConfig(prefix string, node map[string]any, known map[string]bool, cfg *projectConfig) {
+	keys := make([]string, 0, len(node))
+	for k := range node {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		switch v := node[k].(type) {
+		case nil:
+			continue
+		case map[string]any:
+			if path == "search.weights" || path == "analysis.health_weights" {
+				cfg.Values[path] = formatWeightsSpec(v)
+				continue
+			}
+			if path == "priorities.defaults" {
+				parseDefaultPriorities(v, cfg)
+				continue
+			}
+			flattenProjectConfig(path, v, known, cfg)
+		default:
+			if !known[path] {
+				cfg.Warnings = append(cfg.Warnings, fmt.Sprintf("project config: unknown setting %q", path))
+				continue
+			}
+			cfg.Values[path] = fmt.Sprint(v)
+		}
+	}
+}
+
+// parseDefaultPriorities reads priorities.defaults, keeping entries whose
+// value is a priority from 0 to 4 and warning about the rest.
+func parseDefaultPriorities(node map[string]any, cfg *projectConfig) {
+	types := make([]string, 0, len(node))
+	for typ := range node {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	for _, typ := range types {
+		v := node[typ]
+		p, ok := configPriority(v)
+		if !ok {
+			cfg.Warnings = append(cfg.Warnings, fmt.Sprintf("project config: priorities.defaults.%s: want a priority from 0 to 4, got %v", typ, v))
+			continue
+		}
+		if cfg.DefaultPriorities == nil {
+			cfg.DefaultPriorities = map[string]int{}
+		}
+		cfg.DefaultPriorities[strings.ToLower(strings.TrimSpace(typ))] = p
+	}
+}
+
+// configPriority returns v as a priority from 0 to 4. YAML may decode a
+// number as any integer type or, when written as 2.0, as a float; whole
+// numbers of either kind are accepted.
+func configPriority(v any) (int, bool) {
+	var f float64
+	switch n := v.(type) {
+	case int:
+		f = float64(n)
+	case int64:
+		f = float64(n)
+	case uint64:
+		f = float64(n)
+	case float64:
+		f = n
+	default:
+		return 0, false
+	}
+	if f != math.Trunc(f) || f < 0 || f > 4 {
+		return 0, false
+	}
+	return int(f), true
+}
+
+// formatWeightsSpec turns a weights map into a --weights (or --health-weights)
+// key=value list.
+func formatWeightsSpec(weights map[string]any) string {
+	parts := make([]string, 0, len(weights))
+	for k, v := range weights {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, v))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// applyProjectConfig sets each configured flag that was not given on the
+// command line and whose environment variable (if any) is unset. Precedence is
+// therefore flags > environment > .bv/config.yaml > built-in defaults. Keys
+// whose flag fs does not define (a subcommand's smaller flag set) are left
+// alone. It returns the flags it set; invalid values become warnings on cfg.
+func applyProjectConfig(fs *flag.FlagSet, cfg *projectConfig) map[string]bool {
+	explicit := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	applied := map[string]bool{}
+	for _, key := range projectConfigKeys {
+		value, ok := cfg.Values[key.path]
+		if !ok || explicit[key.flag] || fs.Lookup(key.flag) == nil || (key.env != "" && os.Getenv(key.env) != "") {
+			continue
+		}
+		if err := fs.Set(key.flag, value); err != nil {
+			cfg.Warnings = append(cfg.Warnings, fmt.Sprintf("project config: %s: %v", key.path, err))
+			continue
+		}
+		applied[key.flag] = true
+	}
+	return applied
+}
+
+// printProjectConfig writes the effective settings as .bv/config.yaml-shaped
+// YAML, each annotated with where its value came from.
+func printProjectConfig(w io.Writer, fs *flag.FlagSet, cfg projectConfig, applied map[string]bool) error {
+	explicit := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	sections := map[string]*yaml.Node{}
+	for _, key := range projectConfigKeys {
+		f := fs.Lookup(key.flag)
+		if f == nil {
+			continue
+		}
+
+		source, effective := "default", f.Value.String()
+		switch {
+		case explicit[key.flag] && !applied[key.flag]:
+			source = "flag --" + key.flag
+		case applied[key.flag]:
+			source = "config"
+		case key.env != "" && os.Getenv(key.env) != "":
+			source, effective = "env "+key.env, os.Getenv(key.env)
+		}
+
+		value := &yaml.Node{Kind: yaml.ScalarNode, Value: effective, LineComment: source}
+		if effective == "" {
+			value.Style = yaml.DoubleQuotedStyle
+		}
+
+		parent := root
+		name := key.path
+		if section, field, ok := strings.Cut(key.path, "."); ok {
+			if sections[section] == nil {
+				sections[section] = &yaml.Node{Kind: yaml.MappingNode}
+				root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: section}, sections[section])
+			}
+			parent, name = sections[section], field
+		}
+		parent.Content = append(parent.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, value)
+	}
+
+	if len(cfg.DefaultPriorities) > 0 {
+		types := make([]string, 0, len(cfg.DefaultPriorities))
+		for typ := range cfg.DefaultPriorities {
+			types = append(types, typ)
+		}
+		sort.Strings(types)
+		defaults := &yaml.Node{Kind: yaml.MappingNode}
+		for _, typ := range types {
+			defaults.Content = append(defaults.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: typ},
+				&yaml.Node{Kind: yaml.ScalarNode, Value: fmt.Sprint(cfg.DefaultPriorities[typ]), LineComment: "config"})
+		}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "priorities"},
+			&yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{{Kind: yaml.ScalarNode, Value: "defaults"}, defaults}})
+	}
+
+	fmt.Fprintf(w, "# Effective configuration (flags > environment > %s > defaults)\n", cfg.Path)
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
+)
+
+func writeProjectConfig(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".bv"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".bv", "config.yaml"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func newProjectConfigFlagSet() (*flag.FlagSet, *string, *int, *string, *string) {
+	fs := flag.NewFlagSet("bv", flag.ContinueOnError)
+	theme := fs.String("theme", "dark", "")
+	wip := fs.Int("wip-limit", 0, "")
+	mode := fs.String("search-mode", "", "")
+	weights := fs.String("weights", "", "")
+	return fs, theme, wip, mode, weights
+}
+
+func TestProjectConfig_ExplicitFlagOverridesConfig(t *testing.T) {
+	dir := writeProjectConfig(t, `graph:
+  theme: light
+search:
+  mode: hybrid
+  weights: {text: 0.6, pagerank: 0.4}
+wip_limit: 3
+`)
+	t.Setenv(search.EnvSearchMode, "")
+
+	fs, theme, wip, mode, weights := newProjectConfigFlagSet()
+	if err := fs.Parse([]string{"--theme", "auto"}); err != nil {
+		t.Fatal(err)
+	}
+	cfg := loadProjectConfig(dir)
+	applied := applyProjectConfig(fs, &cfg)
+
+	if *theme != "auto" {
+		t.Errorf("explicit --theme must win over the config, got %q", *theme)
+	}
+	if *wip != 3 || *mode != "hybrid" || *weights != "pagerank=0.4,text=0.6" {
+		t.Errorf("config defaults not applied: wip=%d mode=%q weights=%q", *wip, *mode, *weights)
+	}
+	if applied["theme"] || !applied["wip-limit"] {
+		t.Errorf("unexpected applied set: %v", applied)
+	}
+	if len(cfg.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", cfg.Warnings)
+	}
+
+	var out bytes.Buffer
+	if err := printProjectConfig(&out, fs, cfg, applied); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"theme: auto # flag --theme", "wip_limit: 3 # config", "mode: hybrid # config"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("--print-config missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestProjectConfig_EnvBeatsConfigAndBadInputWarns(t *testing.T) {
+	dir := writeProjectConfig(t, `search:
+  mode: hybrid
+wip_limit: lots
+colour: blue
+`)
+	t.Setenv(search.EnvSearchMode, "text")
+
+	fs, _, wip, mode, _ := newProjectConfigFlagSet()
+	_ = fs.Parse(nil)
+	cfg := loadProjectConfig(dir)
+	applyProjectConfig(fs, &cfg)
+
+	if *mode != "" {
+		t.Errorf("%s must take precedence over the config, got --search-mode %q", search.EnvSearchMode, *mode)
+	}
+	if *wip != 0 {
+		t.Errorf("invalid wip_limit must be ignored, got %d", *wip)
+	}
+	if len(cfg.Warnings) != 2 {
+		t.Errorf("expected warnings for colour and wip_limit, got %v", cfg.Warnings)
+	}
+
+	if missing := loadProjectConfig(t.TempDir()); len(missing.Values) != 0 || len(missing.Warnings) != 0 {
+		t.Errorf("a missing config must be silently empty, got %+v", missing)
+	}
+}
+
+func TestProjectConfig_PriorityDefaults(t *testing.T) {
+	dir := writeProjectConfig(t, `priorities:
+  defaults:
+    Bug: 1
+    docs: 3
+    chore: 9
+    feature: 2.0
+    epic: 1.5
+`)
+	cfg := loadProjectConfig(dir)
+
+	if len(cfg.DefaultPriorities) != 3 || cfg.DefaultPriorities["bug"] != 1 || cfg.DefaultPriorities["docs"] != 3 || cfg.DefaultPriorities["feature"] != 2 {
+		t.Errorf("unexpected defaults: %v", cfg.DefaultPriorities)
+	}
+	if len(cfg.Warnings) != 2 || !strings.Contains(cfg.Warnings[0], "priorities.defaults.chore") || !strings.Contains(cfg.Warnings[1], "priorities.defaults.epic") {
+		t.Errorf("expected warnings for the out-of-range chore and fractional epic defaults, got %v", cfg.Warnings)
+	}
+
+	fs, _, _, _, _ := newProjectConfigFlagSet()
+	var out bytes.Buffer
+	if err := printProjectConfig(&out, fs, cfg, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "priorities:\n  defaults:\n    bug: 1 # config\n    docs: 3 # config\n") {
+		t.Errorf("--print-config should list the priority defaults:\n%s", out.String())
+	}
+}
+
+func TestProjectConfig_SkipsFlagsASubcommandLacks(t *testing.T) {
+	cfg := loadProjectConfig(writeProjectConfig(t, `analysis:
+  health_weights: {staleness: 1}
+search:
+  mode: hybrid
+`))
+	fs := flag.NewFlagSet("bv serve", flag.ContinueOnError)
+	weights := fs.String("health-weights", "", "")
+
+	applied := applyProjectConfig(fs, &cfg)
+	if !applied["health-weights"] || *weights != "staleness=1" {
+		t.Errorf("health-weights = %q (applied %v), want staleness=1", *weights, applied)
+	}
+	if len(cfg.Warnings) != 0 {
+		t.Errorf("keys without a flag here must be skipped silently, got %v", cfg.Warnings)
+	}
+}
+
+func TestProjectConfig_AppliesEveryKey(t *testing.T) {
+	dir := writeProjectConfig(t, `search:
+  mode: hybrid
+  preset: impact-first
+  weights: {text: 0.7, pagerank: 0.3}
+  fusion: rrf
+graph:
+  format: mermaid
+  preset: roadmap
+  theme: light
+  palette: colorblind
+analysis:
+  force_full: true
+  health_weights: {staleness: 2, blockers: 1}
+suggest:
+  language: fr
+wip_limit: 5
+`)
+	for _, key := range projectConfigKeys {
+		if key.env != "" {
+			t.Setenv(key.env, "")
+		}
+	}
+
+	fs := flag.NewFlagSet("bv", flag.ContinueOnError)
+	for _, key := range projectConfigKeys {
+		switch key.flag {
+		case "force-full-analysis":
+			fs.Bool(key.flag, false, "")
+		case "wip-limit":
+			fs.Int(key.flag, 0, "")
+		default:
+			fs.String(key.flag, "", "")
+		}
+	}
+	_ = fs.Parse(nil)
+	cfg := loadProjectConfig(dir)
+	applied := applyProjectConfig(fs, &cfg)
+	if len(cfg.Warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", cfg.Warnings)
+	}
+
+	want := map[string]string{
+		"search-mode":         "hybrid",
+		"search-preset":       "impact-first",
+		"weights":             "pagerank=0.3,text=0.7",
+		"fusion":              "rrf",
+		"graph-format":        "mermaid",
+		"graph-preset":        "roadmap",
+		"theme":               "light",
+		"palette":             "colorblind",
+		"force-full-analysis": "true",
+		"health-weights":      "blockers=1,staleness=2",
+		"suggest-lang":        "fr",
+		"wip-limit":           "5",
+	}
+	for _, key := range projectConfigKeys {
+		expected, ok := want[key.flag]
+		if !ok {
+			t.Errorf("%s has no case in this test", key.path)
+			continue
+		}
+		if got := fs.Lookup(key.flag).Value.String(); got != expected || !applied[key.flag] {
+			t.Errorf("%s: --%s = %q (applied %v), want %q", key.path, key.flag, got, applied[key.flag], expected)
+		}
+	}
+}
+
+func TestProjectConfig_ReadFromBeadsOwnerInSubdirectory(t *testing.T) {
+	dir := writeProjectConfig(t, "wip_limit: 4\n")
+	if err := os.MkdirAll(filepath.Join(dir, ".beads"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(dir, "src", "pkg")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BEADS_DIR", "")
+	t.Chdir(sub)
+
+	if got, _ := filepath.EvalSymlinks(projectRoot()); got != mustEvalSymlinks(t, dir) {
+		t.Errorf("projectRoot() from %s = %s, want %s", sub, got, dir)
+	}
+	if cfg := loadProjectConfig(""); cfg.Values["wip_limit"] != "4" {
+		t.Errorf("config from a subdirectory = %+v, want wip_limit 4 from %s", cfg.Values, dir)
+	}
+}
+
+func mustEvalSymlinks(t *testing.T, path string) string {
+	t.Helper()
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resolved
+}
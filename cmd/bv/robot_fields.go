@@ -0,0 +1,379 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/version"
+)
+
+// robotFieldPaths holds the parsed --fields projection. When empty, robot
+// output is written unchanged.
+var robotFieldPaths []string
+
+// robotLoadWarnings are data problems found while loading (e.g. duplicate
+// bead IDs). robotEncoder adds them to object payloads as "warnings".
+var robotLoadWarnings []string
+
+// robotUsageHints are extra hints for the current run (e.g. how to create
+// beads when there are none). robotEncoder adds them to "usage_hints".
+var robotUsageHints []string
+
+// robotBeadProjects maps bead IDs to their project path when --recursive
+// aggregates nested .beads directories. robotEncoder adds it as "projects".
+var robotBeadProjects map[string]string
+
+// robotEncoder mirrors json.Encoder for robot output, applying the --fields
+// projection after serialization so every robot command supports it.
+type robotEncoder struct {
+	w              io.Writer
+	prefix, indent string
+}
+
+func newRobotEncoder(w io.Writer) *robotEncoder {
+	return &robotEncoder{w: w}
+}
+
+func (e *robotEncoder) SetIndent(prefix, indent string) {
+	e.prefix, e.indent = prefix, indent
+}
+
+func (e *robotEncoder) Encode(v any) error {
+	// The payload is marshaled once; the header and extra keys are spliced
+	// into its top-level fields. Non-object payloads pass through unchanged.
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	fields, err := splitObject(raw)
+	if err != nil {
+		return err
+	}
+	if fields != nil {
+		if fields, err = addRobotBuildInfo(fields, version.Info()); err != nil {
+			return err
+		}
+		if len(robotUsageHints) > 0 {
+			if fields, err = addRobotList(fields, "usage_hints", robotUsageHints); err != nil {
+				return err
+			}
+		}
+		if len(robotBeadProjects) > 0 {
+			if fields, err = addRobotField(fields, "projects", robotBeadProjects); err != nil {
+				return err
+			}
+		}
+	}
+	if len(robotFieldPaths) > 0 {
+		if fields != nil {
+			if raw, err = encodeOrderedObject(fields); err != nil {
+				return err
+			}
+		}
+		projected, err := projectRobotFields(json.RawMessage(raw), robotFieldPaths)
+		if err != nil {
+			return err
+		}
+		if raw, err = json.Marshal(projected); err != nil {
+			return err
+		}
+		if fields, err = splitObject(raw); err != nil {
+			return err
+		}
+	}
+	if fields != nil {
+		// Added after the projection so --fields never hides data problems
+		if len(robotLoadWarnings) > 0 {
+			if fields, err = addRobotWarnings(fields, robotLoadWarnings); err != nil {
+				return err
+			}
+		}
+		if raw, err = encodeOrderedObject(fields); err != nil {
+			return err
+		}
+	}
+	if robotOutputFormat == "md" {
+		return writeRobotMarkdown(e.w, json.RawMessage(raw))
+	}
+	enc := json.NewEncoder(e.w)
+	enc.SetIndent(e.prefix, e.indent)
+	return enc.Encode(json.RawMessage(raw))
+}
+
+// addRobotWarnings appends warnings to the payload's top-level "warnings"
+// list, creating it after the other keys if absent. Key order is kept.
+func addRobotWarnings(fields []objectField, warnings []string) ([]objectField, error) {
+	return addRobotList(fields, "warnings", warnings)
+}
+
+// addRobotList appends items to the top-level string list under key, as
+// addRobotWarnings does for "warnings".
+func addRobotList(fields []objectField, key string, items []string) ([]objectField, error) {
+	var existing []string
+	at := -1
+	for i, f := range fields {
+		if f.key == key {
+			at = i
+			_ = json.Unmarshal(f.value, &existing) // null or non-string lists are replaced
+		}
+	}
+	merged, err := json.Marshal(append(existing, items...))
+	if err != nil {
+		return nil, err
+	}
+	if at >= 0 {
+		fields[at].value = merged
+		return fields, nil
+	}
+	return append(fields, objectField{key: key, value: merged}), nil
+}
+
+// addRobotField sets the top-level key to value, after the other keys unless
+// the payload already has it.
+func addRobotField(fields []objectField, key string, value any) ([]objectField, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	for i, f := range fields {
+		if f.key == key {
+			fields[i].value = encoded
+			return fields, nil
+		}
+	}
+	return append(fields, objectField{key: key, value: encoded}), nil
+}
+
+// addRobotBuildInfo inserts bv_version, bv_commit and bv_build_date into the
+// payload header, right after data_hash (or generated_at when there is no
+// hash, else first). Keys the payload already sets are left alone.
+func addRobotBuildInfo(fields []objectField, info version.BuildInfo) ([]objectField, error) {
+	raw, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	header, err := splitObject(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	at := 0
+	present := make(map[string]bool, len(fields))
+	for i, f := range fields {
+		present[f.key] = true
+		switch f.key {
+		case "data_hash":
+			at = i + 1
+		case "generated_at":
+			if at == 0 {
+				at = i + 1
+			}
+		}
+	}
+	var insert []objectField
+	for _, f := range header {
+		if !present[f.key] {
+			insert = append(insert, f)
+		}
+	}
+	if len(insert) == 0 {
+		return fields, nil
+	}
+
+	merged := make([]objectField, 0, len(fields)+len(insert))
+	merged = append(merged, fields[:at]...)
+	merged = append(merged, insert...)
+	return append(merged, fields[at:]...), nil
+}
+
+type objectField struct {
+	key   string
+	value json.RawMessage
+}
+
+// splitObject splits a top-level JSON object into its fields in order. It
+// returns nil fields when raw is not an object.
+func splitObject(raw []byte) ([]objectField, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, nil
+	}
+	fields := []objectField{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		fields = append(fields, objectField{key: tok.(string), value: value})
+	}
+	return fields, nil
+}
+
+func encodeOrderedObject(fields []objectField) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(f.key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(f.value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// parseFieldsFlag splits a comma-separated --fields value into paths.
+func parseFieldsFlag(spec string) []string {
+	var paths []string
+	for _, p := range strings.Split(spec, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// projectRobotFields serializes v and keeps only the requested dotted paths.
+// "a.b" selects a nested key and "a[].b" selects b from every element of
+// array a. Paths that do not resolve are listed under "invalid_fields" rather
+// than dropped silently.
+func projectRobotFields(v any, paths []string) (any, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber() // keep numbers byte-identical to the unprojected output
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	var out any
+	var invalid []string
+	for _, path := range paths {
+		segs, err := parseFieldPath(path)
+		if err != nil {
+			invalid = append(invalid, path)
+			continue
+		}
+		projected, ok := projectValue(doc, segs, out)
+		if !ok {
+			invalid = append(invalid, path)
+			continue
+		}
+		out = projected
+	}
+
+	if len(invalid) > 0 {
+		obj, isObj := out.(map[string]any)
+		if out == nil {
+			obj, isObj = map[string]any{}, true
+		}
+		if !isObj {
+			return nil, fmt.Errorf("invalid --fields: %s", strings.Join(invalid, ", "))
+		}
+		obj["invalid_fields"] = invalid
+		out = obj
+	}
+	return out, nil
+}
+
+// fieldSeg is one step of a --fields path: an object key, or "[]" (every
+// array element) when wildcard is set.
+type fieldSeg struct {
+	key      string
+	wildcard bool
+}
+
+func parseFieldPath(path string) ([]fieldSeg, error) {
+	var segs []fieldSeg
+	for _, part := range strings.Split(path, ".") {
+		key := part
+		wildcards := 0
+		for strings.HasSuffix(key, "[]") {
+			key = strings.TrimSuffix(key, "[]")
+			wildcards++
+		}
+		if strings.ContainsAny(key, "[]") || (key == "" && wildcards == 0) {
+			return nil, fmt.Errorf("malformed field path %q", path)
+		}
+		if key != "" {
+			segs = append(segs, fieldSeg{key: key})
+		}
+		for i := 0; i < wildcards; i++ {
+			segs = append(segs, fieldSeg{wildcard: true})
+		}
+	}
+	return segs, nil
+}
+
+// projectValue copies the parts of src selected by segs into dst (which may
+// already hold earlier projections) and reports whether the path resolved.
+// A wildcard over an empty array resolves trivially; over a non-empty array
+// it resolves if at least one element has the remaining path.
+func projectValue(src any, segs []fieldSeg, dst any) (any, bool) {
+	if len(segs) == 0 {
+		return src, true
+	}
+	seg, rest := segs[0], segs[1:]
+
+	if seg.wildcard {
+		arr, ok := src.([]any)
+		if !ok {
+			return dst, false
+		}
+		next := make([]any, len(arr))
+		if prev, _ := dst.([]any); len(prev) == len(arr) {
+			copy(next, prev)
+		}
+		resolved := len(arr) == 0
+		for i, elem := range arr {
+			if child, ok := projectValue(elem, rest, next[i]); ok {
+				next[i] = child
+				resolved = true
+			} else if next[i] == nil {
+				// Keep array positions aligned for elements lacking the field
+				if _, isObj := elem.(map[string]any); isObj {
+					next[i] = map[string]any{}
+				}
+			}
+		}
+		if !resolved {
+			return dst, false
+		}
+		return next, true
+	}
+
+	obj, ok := src.(map[string]any)
+	if !ok {
+		return dst, false
+	}
+	val, ok := obj[seg.key]
+	if !ok {
+		return dst, false
+	}
+	dstObj, _ := dst.(map[string]any)
+	child, ok := projectValue(val, rest, dstObj[seg.key])
+	if !ok {
+		return dst, false
+	}
+	if dstObj == nil {
+		dstObj = make(map[string]any)
+	}
+	dstObj[seg.key] = child
+	return dstObj, true
+}
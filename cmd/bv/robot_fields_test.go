@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/version"
+)
+
+func TestProjectRobotFields(t *testing.T) {
+	input := map[string]any{
+		"a": map[string]any{"b": 1, "c": 2},
+		"list": []any{
+			map[string]any{"id": "x", "n": 1, "opt": true},
+			map[string]any{"id": "y", "n": 2},
+		},
+		"empty": []any{},
+		"grid":  []any{[]any{map[string]any{"v": 1}}},
+	}
+
+	tests := []struct {
+		name  string
+		paths []string
+		want  string
+	}{
+		{"nested key", []string{"a.b"}, `{"a":{"b":1}}`},
+		{"merges siblings", []string{"a.b", "a.c"}, `{"a":{"b":1,"c":2}}`},
+		{"array wildcard", []string{"list[].id"}, `{"list":[{"id":"x"},{"id":"y"}]}`},
+		{"wildcard merges", []string{"list[].id", "list[].n"}, `{"list":[{"id":"x","n":1},{"id":"y","n":2}]}`},
+		{"optional element field", []string{"list[].opt"}, `{"list":[{"opt":true},{}]}`},
+		{"empty array", []string{"empty[].id"}, `{"empty":[]}`},
+		{"nested wildcards", []string{"grid[][].v"}, `{"grid":[[{"v":1}]]}`},
+		{"whole subtree", []string{"a"}, `{"a":{"b":1,"c":2}}`},
+		{"invalid reported", []string{"a.b", "a.zz", "list[].zz", "a[]", "x]y"},
+			`{"a":{"b":1},"invalid_fields":["a.zz","list[].zz","a[]","x]y"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := projectRobotFields(input, tt.paths)
+			if err != nil {
+				t.Fatalf("projectRobotFields: %v", err)
+			}
+			gotJSON, _ := json.Marshal(got)
+			var gotAny, wantAny any
+			_ = json.Unmarshal(gotJSON, &gotAny)
+			_ = json.Unmarshal([]byte(tt.want), &wantAny)
+			if !reflect.DeepEqual(gotAny, wantAny) {
+				t.Errorf("got %s, want %s", gotJSON, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFieldsFlag(t *testing.T) {
+	got := parseFieldsFlag(" a.b , ,c[].d,")
+	want := []string{"a.b", "c[].d"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseFieldsFlag = %v, want %v", got, want)
+	}
+}
+
+func TestAddRobotWarnings(t *testing.T) {
+	type payload struct {
+		Z        int      `json:"z"`
+		Warnings []string `json:"warnings,omitempty"`
+		A        int      `json:"a"`
+	}
+
+	tests := []struct {
+		name  string
+		input any
+		want  string
+	}{
+		{"appended last, order kept", struct {
+			Z int `json:"z"`
+			A int `json:"a"`
+		}{1, 2}, `{"z":1,"a":2,"warnings":["dup"]}`},
+		{"merged into existing", payload{Z: 1, Warnings: []string{"own"}, A: 2}, `{"z":1,"warnings":["own","dup"],"a":2}`},
+		{"empty object", map[string]any{}, `{"warnings":["dup"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := robotFieldsTestObject(t, tt.input, func(fields []objectField) ([]objectField, error) {
+				return addRobotWarnings(fields, []string{"dup"})
+			})
+			if raw != tt.want {
+				t.Errorf("got %s, want %s", raw, tt.want)
+			}
+		})
+	}
+}
+
+func TestRobotEncoderKeepsWarningsWithFields(t *testing.T) {
+	oldPaths, oldWarnings := robotFieldPaths, robotLoadWarnings
+	t.Cleanup(func() { robotFieldPaths, robotLoadWarnings = oldPaths, oldWarnings })
+	robotFieldPaths = []string{"a"}
+	robotLoadWarnings = []string{"dup"}
+
+	var out bytes.Buffer
+	if err := newRobotEncoder(&out).Encode(map[string]any{"a": 1, "b": 2}); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if got := strings.TrimSpace(out.String()); got != `{"a":1,"warnings":["dup"]}` {
+		t.Errorf("got %s, want the projected field plus the load warnings", got)
+	}
+}
+
+func TestAddRobotBuildInfo(t *testing.T) {
+	info := version.BuildInfo{Version: "v1.2.3", Commit: "abc", BuildDate: "2025-01-02"}
+	const header = `"bv_version":"v1.2.3","bv_commit":"abc","bv_build_date":"2025-01-02"`
+
+	tests := []struct {
+		name  string
+		input any
+		want  string
+	}{
+		{"after data_hash", map[string]any{"data_hash": "h", "generated_at": "t", "x": 1},
+			`{"data_hash":"h",` + header + `,"generated_at":"t","x":1}`},
+		{"after generated_at without hash", struct {
+			GeneratedAt string `json:"generated_at"`
+			X           int    `json:"x"`
+		}{"t", 1}, `{"generated_at":"t",` + header + `,"x":1}`},
+		{"first otherwise", struct {
+			X int `json:"x"`
+		}{1}, `{` + header + `,"x":1}`},
+		{"existing keys kept", map[string]any{"bv_version": "mine"},
+			`{"bv_commit":"abc","bv_build_date":"2025-01-02","bv_version":"mine"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := robotFieldsTestObject(t, tt.input, func(fields []objectField) ([]objectField, error) {
+				return addRobotBuildInfo(fields, info)
+			})
+			if raw != tt.want {
+				t.Errorf("got %s, want %s", raw, tt.want)
+			}
+		})
+	}
+}
+
+// robotFieldsTestObject marshals v, applies edit to its top-level fields and
+// returns the re-encoded object.
+func robotFieldsTestObject(t *testing.T, v any, edit func([]objectField) ([]objectField, error)) string {
+	t.Helper()
+	raw, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	fields, err := splitObject(raw)
+	if err != nil || fields == nil {
+		t.Fatalf("splitObject(%s) = %v, %v", raw, fields, err)
+	}
+	if fields, err = edit(fields); err != nil {
+		t.Fatal(err)
+	}
+	out, err := encodeOrderedObject(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestRobotEncoderPassesNonObjectsThrough(t *testing.T) {
+	oldWarnings := robotLoadWarnings
+	t.Cleanup(func() { robotLoadWarnings = oldWarnings })
+	robotLoadWarnings = []string{"dup"}
+
+	var out bytes.Buffer
+	if err := newRobotEncoder(&out).Encode([]int{1, 2}); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if got := strings.TrimSpace(out.String()); got != `[1,2]` {
+		t.Errorf("got %s, want the array unchanged", got)
+	}
+}
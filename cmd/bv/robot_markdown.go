@@ -0,0 +1,360 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// robotOutputFormat is the parsed --output value: "json" (default) or "md".
+// robotEncoder consults it after projection, so every robot command that
+// goes through newRobotEncoder supports both.
+var robotOutputFormat = "json"
+
+// robotOutputFormats are the accepted --output values
+var robotOutputFormats = []string{"json", "md"}
+
+// mdNode is a decoded JSON value that keeps object keys in document order,
+// so tables list columns in the order the robot structs declare them.
+type mdNode struct {
+	keys   []string
+	fields map[string]*mdNode // Set for objects
+	items  []*mdNode          // Set for arrays
+	isArr  bool
+	scalar string // Set for strings, numbers, booleans; "" for null
+	raw    string // The scalar as a JSON literal
+	isNull bool
+}
+
+func (n *mdNode) isObject() bool { return n.fields != nil }
+
+func (n *mdNode) isScalar() bool { return !n.isObject() && !n.isArr }
+
+// multiline reports scalars that span lines, such as DOT or Mermaid graphs
+func (n *mdNode) multiline() bool { return n.isScalar() && strings.Contains(n.scalar, "\n") }
+
+// isEmpty reports null, empty arrays, and empty objects
+func (n *mdNode) isEmpty() bool {
+	return n.isNull || (n.isArr && len(n.items) == 0) || (n.isObject() && len(n.keys) == 0)
+}
+
+// scalarList reports arrays whose items are all scalars
+func (n *mdNode) scalarList() bool {
+	if !n.isArr {
+		return false
+	}
+	for _, item := range n.items {
+		if !item.isScalar() {
+			return false
+		}
+	}
+	return true
+}
+
+// inline renders scalars and scalar lists as one cell or bullet value
+func (n *mdNode) inline(sep string) string {
+	if n.isNull {
+		return ""
+	}
+	if n.isArr {
+		parts := make([]string, len(n.items))
+		for i, item := range n.items {
+			parts[i] = item.scalar
+		}
+		return strings.Join(parts, sep)
+	}
+	return n.scalar
+}
+
+// decodeMDNode parses JSON into an order-preserving mdNode tree
+func decodeMDNode(dec *json.Decoder) (*mdNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			n := &mdNode{fields: make(map[string]*mdNode)}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, _ := keyTok.(string)
+				child, err := decodeMDNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				if _, dup := n.fields[key]; !dup {
+					n.keys = append(n.keys, key)
+				}
+				n.fields[key] = child
+			}
+			_, err := dec.Token() // '}'
+			return n, err
+		}
+		n := &mdNode{isArr: true}
+		for dec.More() {
+			child, err := decodeMDNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, child)
+		}
+		_, err := dec.Token() // ']'
+		return n, err
+	case nil:
+		return &mdNode{isNull: true}, nil
+	case string:
+		raw, _ := json.Marshal(t)
+		return &mdNode{scalar: t, raw: string(raw)}, nil
+	case json.Number:
+		return &mdNode{scalar: mdNumber(t), raw: t.String()}, nil
+	case bool:
+		return &mdNode{scalar: fmt.Sprint(t), raw: fmt.Sprint(t)}, nil
+	}
+	return nil, fmt.Errorf("unexpected JSON token %v", tok)
+}
+
+// writeRobotMarkdown renders a robot payload as markdown. In each object,
+// scalar fields and empty values become a bullet list. Multi-line strings,
+// such as DOT or Mermaid graphs, become fenced code blocks under their own
+// heading. Arrays of objects become tables over their scalar columns. Other
+// arrays, such as cycles and paths, become lists. Nested objects become
+// sections titled with their dotted path. Output follows the payload's field
+// order, so it is deterministic.
+func writeRobotMarkdown(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	root, err := decodeMDNode(dec)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	switch {
+	case root.isObject():
+		writeMDObject(&b, "", root)
+	case root.isArr:
+		writeMDArray(&b, "items", root)
+	case root.multiline():
+		b.WriteString(mdFence(root.scalar))
+	default:
+		b.WriteString(mdEscape(root.scalar) + "\n")
+	}
+	_, err = io.WriteString(w, strings.TrimLeft(b.String(), "\n"))
+	return err
+}
+
+func writeMDObject(b *strings.Builder, path string, n *mdNode) {
+	var nested []string
+	wroteBullets := false
+	for _, key := range n.keys {
+		child := n.fields[key]
+		switch {
+		case child.isEmpty():
+			fmt.Fprintf(b, "- **%s**: none\n", key)
+		case child.multiline():
+			nested = append(nested, key)
+			continue
+		case child.isScalar() || (child.scalarList() && mdShortList(child)):
+			fmt.Fprintf(b, "- **%s**: %s\n", key, mdEscape(child.inline(", ")))
+		default:
+			nested = append(nested, key)
+			continue
+		}
+		wroteBullets = true
+	}
+	if wroteBullets {
+		b.WriteString("\n")
+	}
+
+	for _, key := range nested {
+		childPath := key
+		if path != "" {
+			childPath = path + "." + key
+		}
+		child := n.fields[key]
+		switch {
+		case child.isObject():
+			fmt.Fprintf(b, "## %s\n\n", childPath)
+			writeMDObject(b, childPath, child)
+		case child.isScalar():
+			fmt.Fprintf(b, "## %s\n\n%s\n", childPath, mdFence(child.scalar))
+		default:
+			writeMDArray(b, childPath, child)
+		}
+	}
+}
+
+func writeMDArray(b *strings.Builder, path string, n *mdNode) {
+	fmt.Fprintf(b, "## %s\n\n", path)
+
+	allObjects := true
+	for _, item := range n.items {
+		allObjects = allObjects && item.isObject()
+	}
+	if allObjects {
+		writeMDTable(b, n.items)
+		// Rows that carry their own lists of objects (plan tracks and their
+		// items) get a table per row; nested objects are left out for brevity
+		for r, row := range n.items {
+			for _, key := range row.keys {
+				child := row.fields[key]
+				if child.isArr && !child.isEmpty() && !child.scalarList() {
+					writeMDArray(b, fmt.Sprintf("%s[%d].%s", path, r, key), child)
+				}
+			}
+		}
+		return
+	}
+
+	// Lists of lists are usually cycles or paths: render them as chains
+	for _, item := range n.items {
+		switch {
+		case item.multiline():
+			b.WriteString(mdFence(item.scalar))
+		case item.isScalar():
+			fmt.Fprintf(b, "- %s\n", mdEscape(item.scalar))
+		case item.scalarList():
+			fmt.Fprintf(b, "- %s\n", mdEscape(item.inline(" → ")))
+		default:
+			compact, _ := json.Marshal(mdNodeValue(item))
+			fmt.Fprintf(b, "- `%s`\n", compact)
+		}
+	}
+	b.WriteString("\n")
+}
+
+// writeMDTable renders objects as a table whose columns are every scalar or
+// scalar-list field, in first-seen order, padded so the columns line up
+func writeMDTable(b *strings.Builder, rows []*mdNode) {
+	var cols []string
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		for _, key := range row.keys {
+			child := row.fields[key]
+			if seen[key] || !(child.isScalar() || child.scalarList() || child.isEmpty()) {
+				continue
+			}
+			seen[key] = true
+			cols = append(cols, key)
+		}
+	}
+	if len(cols) == 0 {
+		b.WriteString("_no scalar columns_\n\n")
+		return
+	}
+
+	cells := make([][]string, len(rows))
+	widths := make([]int, len(cols))
+	for i, col := range cols {
+		widths[i] = max(utf8.RuneCountInString(col), 3)
+	}
+	for r, row := range rows {
+		cells[r] = make([]string, len(cols))
+		for i, col := range cols {
+			child, ok := row.fields[col]
+			if !ok || !(child.isScalar() || child.scalarList()) {
+				continue
+			}
+			cells[r][i] = mdEscape(child.inline(", "))
+			widths[i] = max(widths[i], utf8.RuneCountInString(cells[r][i]))
+		}
+	}
+
+	writeRow := func(vals []string) {
+		b.WriteString("|")
+		for i, v := range vals {
+			fmt.Fprintf(b, " %s%s |", v, strings.Repeat(" ", widths[i]-utf8.RuneCountInString(v)))
+		}
+		b.WriteString("\n")
+	}
+	writeRow(cols)
+	rule := make([]string, len(cols))
+	for i := range cols {
+		rule[i] = strings.Repeat("-", widths[i])
+	}
+	writeRow(rule)
+	for _, row := range cells {
+		writeRow(row)
+	}
+	b.WriteString("\n")
+}
+
+// mdShortList reports scalar lists short enough to read inline in a bullet;
+// longer ones (e.g. usage hints) get their own section
+func mdShortList(n *mdNode) bool {
+	return len(n.items) <= 5 && utf8.RuneCountInString(n.inline(", ")) <= 80
+}
+
+// mdNumber rounds decimals to four places so score columns stay narrow
+func mdNumber(n json.Number) string {
+	s := n.String()
+	if !strings.ContainsAny(s, ".eE") {
+		return s
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return s
+	}
+	return strconv.FormatFloat(math.Round(f*1e4)/1e4, 'f', -1, 64)
+}
+
+// mdNodeValue converts a node back to plain values for compact JSON
+func mdNodeValue(n *mdNode) any {
+	switch {
+	case n.isObject():
+		m := make(map[string]any, len(n.keys))
+		for _, key := range n.keys {
+			m[key] = mdNodeValue(n.fields[key])
+		}
+		return m
+	case n.isArr:
+		items := make([]any, len(n.items))
+		for i, item := range n.items {
+			items[i] = mdNodeValue(item)
+		}
+		return items
+	case n.isNull:
+		return nil
+	}
+	return json.RawMessage(n.raw)
+}
+
+// mdEscaper backslash-escapes the characters that would end a table cell or
+// start emphasis or code
+var mdEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"|", `\|`,
+	"*", `\*`,
+	"_", `\_`,
+	"`", "\\`",
+)
+
+// mdEscape keeps a value on one line and renders it literally, in a bullet
+// or a table cell. Multi-line values outside tables are fenced instead (see
+// mdFence).
+func mdEscape(s string) string {
+	return mdEscaper.Replace(strings.Join(strings.Fields(s), " "))
+}
+
+// mdFence renders s verbatim as a fenced code block, with a fence longer than
+// any backtick run inside it
+func mdFence(s string) string {
+	fence := "```"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	return fence + "\n" + strings.TrimRight(s, "\n") + "\n" + fence + "\n"
+}
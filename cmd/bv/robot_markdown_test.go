@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteRobotMarkdown(t *testing.T) {
+	payload := struct {
+		DataHash string     `json:"data_hash"`
+		Cycles   [][]string `json:"cycles"`
+		Picks    []struct {
+			ID     string   `json:"id"`
+			Title  string   `json:"title"`
+			Score  float64  `json:"score"`
+			Labels []string `json:"labels"`
+		} `json:"picks"`
+		Empty []string `json:"empty"`
+	}{
+		DataHash: "abc123",
+		Cycles:   [][]string{{"A", "B", "A"}},
+	}
+	payload.Picks = append(payload.Picks, struct {
+		ID     string   `json:"id"`
+		Title  string   `json:"title"`
+		Score  float64  `json:"score"`
+		Labels []string `json:"labels"`
+	}{ID: "bv-1", Title: "Fix | pipes", Score: 0.123456, Labels: []string{"api", "core"}})
+
+	var buf bytes.Buffer
+	if err := writeRobotMarkdown(&buf, payload); err != nil {
+		t.Fatalf("writeRobotMarkdown: %v", err)
+	}
+	want := "- **data_hash**: abc123\n" +
+		"- **empty**: none\n\n" +
+		"## cycles\n\n" +
+		"- A → B → A\n\n" +
+		"## picks\n\n" +
+		"| id   | title        | score  | labels    |\n" +
+		"| ---- | ------------ | ------ | --------- |\n" +
+		"| bv-1 | Fix \\| pipes | 0.1235 | api, core |\n\n"
+	if got := buf.String(); got != want {
+		t.Errorf("markdown mismatch\n got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteRobotMarkdownFencesGraphsAndEscapes(t *testing.T) {
+	payload := struct {
+		Format string `json:"format"`
+		Title  string `json:"title"`
+		Graph  string `json:"graph"`
+		Rows   []struct {
+			ID    string `json:"id"`
+			Title string `json:"title"`
+		} `json:"rows"`
+	}{
+		Format: "dot",
+		Title:  "*not* bold, `not code`, snake_case",
+		Graph:  "digraph G {\n  \"a|b\" -> \"c_d\";\n  // ```\n}\n",
+	}
+	payload.Rows = append(payload.Rows, struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	}{ID: "bv_1", Title: "two\nlines"})
+
+	var buf bytes.Buffer
+	if err := writeRobotMarkdown(&buf, payload); err != nil {
+		t.Fatalf("writeRobotMarkdown: %v", err)
+	}
+	want := "- **format**: dot\n" +
+		"- **title**: \\*not\\* bold, \\`not code\\`, snake\\_case\n\n" +
+		"## graph\n\n" +
+		"````\ndigraph G {\n  \"a|b\" -> \"c_d\";\n  // ```\n}\n````\n\n" +
+		"## rows\n\n" +
+		"| id    | title     |\n" +
+		"| ----- | --------- |\n" +
+		"| bv\\_1 | two lines |\n\n"
+	if got := buf.String(); got != want {
+		t.Errorf("markdown mismatch\n got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRobotTriageOutputMarkdown(t *testing.T) {
+	dir := t.TempDir()
+	beadsDir := filepath.Join(dir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatalf("mkdir beads: %v", err)
+	}
+	beads := `{"id":"MD-1","title":"Root","status":"open","priority":1,"issue_type":"task"}
+{"id":"MD-2","title":"Leaf","status":"open","priority":2,"issue_type":"task","dependencies":[{"issue_id":"MD-2","depends_on_id":"MD-1","type":"blocks"}]}
+`
+	if err := os.WriteFile(filepath.Join(beadsDir, "beads.jsonl"), []byte(beads), 0o644); err != nil {
+		t.Fatalf("write beads: %v", err)
+	}
+
+	exe := buildTestBinary(t)
+	cmd := exec.Command(exe, "--robot-triage", "--output", "md")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("--robot-triage --output md failed: %v, out=%s", err, out)
+	}
+	md := string(out)
+	idx := strings.Index(md, "## triage.quick_ref.top_picks\n\n")
+	if idx < 0 {
+		t.Fatalf("missing top picks section:\n%s", md)
+	}
+	lines := strings.Split(md[idx:], "\n")
+	header := strings.Fields(strings.ReplaceAll(lines[2], "|", " "))
+	if strings.Join(header, ",") != "id,title,score,reasons,unblocks,unblock_impact" {
+		t.Errorf("top picks header = %q", lines[2])
+	}
+	if !strings.HasPrefix(lines[3], "| --") || !strings.HasPrefix(lines[4], "| MD-1 ") {
+		t.Errorf("expected a rule row then MD-1, got %q / %q", lines[3], lines[4])
+	}
+
+	bad := exec.Command(exe, "--robot-triage", "--output", "xml")
+	bad.Dir = dir
+	if out, err := bad.CombinedOutput(); err == nil || !strings.Contains(string(out), "invalid --output") {
+		t.Errorf("expected an invalid --output error, got err=%v out=%s", err, out)
+	}
+}
@@ -1,7 +1,7 @@
 package main
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"regexp"
@@ -20,30 +20,32 @@ type robotSearchResult struct {
 }
 
 type robotSearchOutput struct {
-	GeneratedAt string                `json:"generated_at"`
-	DataHash    string                `json:"data_hash"`
-	Query       string                `json:"query"`
-	Provider    search.Provider       `json:"provider"`
-	Model       string                `json:"model,omitempty"`
-	Dim         int                   `json:"dim"`
-	IndexPath   string                `json:"index_path"`
-	Index       search.IndexSyncStats `json:"index"`
-	Loaded      bool                  `json:"loaded"`
-	Limit       int                   `json:"limit"`
-	Mode        search.SearchMode     `json:"mode"`
-	Preset      search.PresetName     `json:"preset,omitempty"`
-	Weights     *search.Weights       `json:"weights,omitempty"`
-	Results     []robotSearchResult   `json:"results"`
-	UsageHints  []string              `json:"usage_hints,omitempty"`
+	GeneratedAt string                 `json:"generated_at"`
+	DataHash    string                 `json:"data_hash"`
+	Query       string                 `json:"query"`
+	Provider    search.Provider        `json:"provider"`
+	Model       string                 `json:"model,omitempty"`
+	Dim         int                    `json:"dim"`
+	IndexPath   string                 `json:"index_path"`
+	Index       search.IndexSyncStats  `json:"index"`
+	Loaded      bool                   `json:"loaded"`
+	Limit       int                    `json:"limit"`
+	Mode        search.SearchMode      `json:"mode"`
+	Fusion      search.FusionMode      `json:"fusion,omitempty"`
+	Operators   *search.QueryOperators `json:"operators,omitempty"`
+	Preset      search.PresetName      `json:"preset,omitempty"`
+	Weights     *search.Weights        `json:"weights,omitempty"`
+	Results     []robotSearchResult    `json:"results"`
+	UsageHints  []string               `json:"usage_hints,omitempty"`
 }
 
 func writeRobotSearchOutput(w io.Writer, out robotSearchOutput) error {
-	enc := json.NewEncoder(w)
+	enc := newRobotEncoder(w)
 	enc.SetIndent("", "  ")
 	return enc.Encode(out)
 }
 
-func applySearchConfigOverrides(cfg search.SearchConfig, modeFlag, presetFlag, weightsFlag string) (search.SearchConfig, error) {
+func applySearchConfigOverrides(cfg search.SearchConfig, modeFlag, presetFlag, weightsFlag, weightsSpecFlag string) (search.SearchConfig, error) {
 	if modeFlag != "" {
 		switch search.SearchMode(strings.ToLower(modeFlag)) {
 		case search.SearchModeText, search.SearchModeHybrid:
@@ -54,9 +56,9 @@ func applySearchConfigOverrides(cfg search.SearchConfig, modeFlag, presetFlag, w
 	}
 
 	if presetFlag != "" {
-		name := search.PresetName(strings.ToLower(presetFlag))
-		if _, err := search.GetPreset(name); err != nil {
-			return search.SearchConfig{}, err
+		name, err := search.ResolvePreset(presetFlag)
+		if err != nil {
+			return search.SearchConfig{}, fmt.Errorf("invalid --search-preset: %w", err)
 		}
 		cfg.Preset = name
 	}
@@ -70,6 +72,22 @@ func applySearchConfigOverrides(cfg search.SearchConfig, modeFlag, presetFlag, w
 		cfg.HasWeights = true
 	}
 
+	if weightsSpecFlag != "" {
+		if weightsFlag != "" {
+			return search.SearchConfig{}, fmt.Errorf("--weights and --search-weights are mutually exclusive")
+		}
+		weights, err := search.ParseWeightsSpec(weightsSpecFlag)
+		if err != nil {
+			var wErr *search.WeightValidationError
+			if errors.As(err, &wErr) {
+				return search.SearchConfig{}, fmt.Errorf("invalid --weights: %w (allowed range: each component 0.0-1.0, at least one > 0)", err)
+			}
+			return search.SearchConfig{}, fmt.Errorf("invalid --weights: %w", err)
+		}
+		cfg.Weights = weights
+		cfg.HasWeights = true
+	}
+
 	return cfg, nil
 }
 
@@ -86,13 +104,9 @@ func resolveSearchWeights(cfg search.SearchConfig) (search.Weights, search.Prese
 }
 
 func buildHybridScores(results []search.SearchResult, scorer search.HybridScorer) ([]search.HybridScore, error) {
-	out := make([]search.HybridScore, 0, len(results))
-	for _, result := range results {
-		scored, err := scorer.Score(result.IssueID, result.Score)
-		if err != nil {
-			return nil, err
-		}
-		out = append(out, scored)
+	out, err := scorer.ScoreBatch(results)
+	if err != nil {
+		return nil, err
 	}
 
 	sort.Slice(out, func(i, j int) bool {
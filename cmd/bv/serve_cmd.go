@@ -0,0 +1,700 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/export"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/logging"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/watcher"
+	"golang.org/x/sync/singleflight"
+)
+
+// defaultServePort is the port `bv serve` listens on without --port.
+const defaultServePort = 8787
+
+// serveShutdownTimeout bounds how long in-flight requests may run after an
+// interrupt before the server is closed.
+const serveShutdownTimeout = 5 * time.Second
+
+// runServeCommand implements `bv serve [--port N] [--host H] [--db <path>]
+// [--health-weights spec]`: it keeps the beads loaded and answers the robot
+// operations over HTTP so an agent harness can query repeatedly without
+// paying load and analysis cost on every call. Like the main command it
+// takes its defaults from .bv/config.yaml. Returns the process exit code.
+func runServeCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bv serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	port := fs.Int("port", defaultServePort, "Port to listen on (0 picks a free port)")
+	host := fs.String("host", "127.0.0.1", "Interface to bind; requests must name it, localhost or an IP address as their Host")
+	dbPath := fs.String("db", "", "Read beads from a bd SQLite database instead of the JSONL export")
+	healthWeightsSpec := fs.String("health-weights", "", "Bead health score weights for /insights and /graph, as for the main command")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: bv serve [--port N] [--host H] [--db <path>] [--health-weights spec]")
+		fmt.Fprintln(stderr, "\nServe robot JSON over HTTP, re-analyzing only when the beads change.")
+		fmt.Fprintln(stderr, "Endpoints: /healthz /triage /next /plan /insights /search?q= /path?from=&to= /events /graph")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "bv serve: unexpected argument %q\n", fs.Arg(0))
+		fs.Usage()
+		return 2
+	}
+
+	projectCfg := loadProjectConfig("")
+	applyProjectConfig(fs, &projectCfg)
+	for _, w := range projectCfg.Warnings {
+		logging.Warn(w)
+	}
+	healthWeights := analysis.DefaultHealthWeights()
+	if *healthWeightsSpec != "" {
+		w, err := analysis.ParseHealthWeights(*healthWeightsSpec)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: --health-weights: %v\n", err)
+			return 2
+		}
+		healthWeights = w
+	}
+
+	srv := newServeState(*dbPath)
+	srv.healthWeights = healthWeights
+	srv.bindHost = *host
+	if err := srv.refresh(); err != nil {
+		fmt.Fprintf(stderr, "Error loading beads: %v\n", err)
+		return 1
+	}
+
+	ln, err := net.Listen("tcp", net.JoinHostPort(*host, strconv.Itoa(*port)))
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	httpServer := &http.Server{Handler: srv.handler(), ReadHeaderTimeout: 10 * time.Second}
+	httpServer.RegisterOnShutdown(srv.events.closeAll)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Reload on file changes even when no request arrives, so /events
+	// subscribers hear about edits promptly.
+	if fp, err := srv.sourceFingerprint(); err == nil && fp.path != "" {
+		w, err := watcher.NewWatcher(fp.path,
+			watcher.WithOnChange(func() {
+				if err := srv.refresh(); err != nil {
+					logging.Warn("reloading beads failed", "error", err)
+				}
+			}),
+		)
+		if err == nil && w.Start() == nil {
+			defer w.Stop()
+		}
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- httpServer.Serve(ln) }()
+	fmt.Fprintf(stdout, "bv serve listening on http://%s (%d beads)\n", ln.Addr(), srv.issueCount())
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		fmt.Fprintf(stderr, "Error shutting down: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(stdout, "bv serve stopped")
+	return 0
+}
+
+// serveFingerprint identifies one version of the beads source on disk.
+type serveFingerprint struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// serveState holds the loaded beads and the payloads computed from them.
+// Payloads are memoized per data hash, so requests against unchanged data
+// never re-run the analysis.
+type serveState struct {
+	dbPath        string
+	healthWeights analysis.HealthWeights // Behind /insights bead_health and /graph health
+	bindHost      string                 // --host, also accepted as a Host header name
+
+	// computing collapses concurrent requests for the same payload and data
+	// hash into one computation
+	computing singleflight.Group
+
+	// reloadMu serializes reloads; the expensive load and graph rebuild run
+	// under it, while mu is only held to read or swap in the results, so
+	// requests keep being answered from the previous data meanwhile
+	reloadMu sync.Mutex
+
+	mu          sync.Mutex
+	fingerprint serveFingerprint
+	issues      []model.Issue
+	dataHash    string
+	loadedAt    time.Time
+	payloads    map[string]any
+
+	events *serveEventHub
+	// graph is the data behind /graph pages, built on first request and then
+	// rebuilt on every reload so open pages can be sent a patch. A failed
+	// rebuild keeps the last good graph; the next patch is diffed from it.
+	graph *export.LiveGraph
+}
+
+func newServeState(dbPath string) *serveState {
+	return &serveState{
+		dbPath:        dbPath,
+		healthWeights: analysis.DefaultHealthWeights(),
+		events:        newServeEventHub(serveEventBacklog),
+	}
+}
+
+// sourceFingerprint stats the file the beads are read from. A project
+// without beads yields the zero fingerprint.
+func (s *serveState) sourceFingerprint() (serveFingerprint, error) {
+	path := s.dbPath
+	if path == "" {
+		beadsDir, err := loader.GetBeadsDir("")
+		if err != nil {
+			return serveFingerprint{}, err
+		}
+		if path, err = loader.FindJSONLPath(beadsDir); err != nil {
+			if loader.IsNoBeadsData(err) || errors.Is(err, os.ErrNotExist) {
+				return serveFingerprint{}, nil
+			}
+			return serveFingerprint{}, err
+		}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return serveFingerprint{}, nil
+		}
+		return serveFingerprint{}, err
+	}
+	return serveFingerprint{path: path, size: info.Size(), modTime: info.ModTime()}, nil
+}
+
+// refresh reloads the beads when the source file changed since the last load
+// and drops memoized payloads when the reloaded data actually differs.
+func (s *serveState) refresh() error {
+	fp, err := s.sourceFingerprint()
+	if err != nil {
+		return fmt.Errorf("locating beads: %w", err)
+	}
+	if s.isCurrent(fp) {
+		return nil
+	}
+
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+	// Another request may have finished the same reload while we waited
+	if s.isCurrent(fp) {
+		return nil
+	}
+
+	issues, err := loadIndexIssues(s.dbPath)
+	if err != nil {
+		return err
+	}
+	hash := analysis.ComputeDataHash(issues)
+
+	s.mu.Lock()
+	loaded, prevIssues, prevHash, prevGraph := s.payloads != nil, s.issues, s.dataHash, s.graph
+	s.mu.Unlock()
+
+	var nextGraph *export.LiveGraph
+	if loaded && hash != prevHash && prevGraph != nil {
+		if next, err := serveLiveGraph(issues, hash, prevGraph.Seq()+1, s.healthWeights); err == nil {
+			nextGraph = next
+		} else {
+			logging.Warn("rebuilding /graph data", "error", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if loaded && hash != prevHash {
+		s.events.publish("update", diffServeUpdate(prevIssues, issues, prevHash, hash))
+		if nextGraph != nil {
+			s.events.publish(serveGraphPatchEvent, prevGraph.PatchTo(nextGraph))
+			s.graph = nextGraph
+		}
+	}
+	if s.payloads == nil || hash != s.dataHash {
+		s.payloads = make(map[string]any)
+	}
+	s.fingerprint = fp
+	s.issues = issues
+	s.dataHash = hash
+	s.loadedAt = time.Now()
+	return nil
+}
+
+// isCurrent reports whether the loaded beads match the source fingerprint.
+func (s *serveState) isCurrent(fp serveFingerprint) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.payloads != nil && fp == s.fingerprint
+}
+
+func (s *serveState) issueCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.issues)
+}
+
+// snapshot returns the current beads and their hash after a freshness check.
+func (s *serveState) snapshot() ([]model.Issue, string, error) {
+	if err := s.refresh(); err != nil {
+		return nil, "", err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.issues, s.dataHash, nil
+}
+
+// memoized returns the payload cached under key for the current data,
+// computing it on first use.
+func (s *serveState) memoized(key string, compute func(issues []model.Issue, dataHash string) any) (any, error) {
+	issues, hash, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	if v, ok := s.payloads[key]; ok && s.dataHash == hash {
+		s.mu.Unlock()
+		return v, nil
+	}
+	s.mu.Unlock()
+
+	v, _, _ := s.computing.Do(key+"@"+hash, func() (any, error) {
+		v := compute(issues, hash)
+		s.mu.Lock()
+		if s.dataHash == hash {
+			s.payloads[key] = v
+		}
+		s.mu.Unlock()
+		return v, nil
+	})
+	return v, nil
+}
+
+func (s *serveState) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.handleHealthz)
+	mux.HandleFunc("GET /triage", s.handleMemoized("triage", serveTriagePayload))
+	mux.HandleFunc("GET /next", s.handleMemoized("next", serveNextPayload))
+	mux.HandleFunc("GET /plan", s.handleMemoized("plan", servePlanPayload))
+	mux.HandleFunc("GET /insights", s.handleMemoized("insights", func(issues []model.Issue, dataHash string) any {
+		return serveInsightsPayload(issues, dataHash, s.healthWeights)
+	}))
+	mux.HandleFunc("GET /search", s.handleSearch)
+	mux.HandleFunc("GET /path", s.handlePath)
+	mux.HandleFunc("GET /events", s.handleEvents)
+	mux.HandleFunc("GET /graph", s.handleGraph)
+	return s.checkHost(mux)
+}
+
+// checkHost rejects requests whose Host header names a host other than
+// localhost or the one bv serve was bound to. A page on another site can
+// point its own domain at 127.0.0.1 (DNS rebinding) and then read responses
+// as same-origin; its requests still carry that domain as the Host. IP
+// literals are let through, since rebinding needs a name.
+func (s *serveState) checkHost(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.TrimSuffix(strings.Trim(host, "[]"), ".")
+		switch {
+		case net.ParseIP(host) != nil,
+			strings.EqualFold(host, "localhost"),
+			s.bindHost != "" && strings.EqualFold(host, s.bindHost):
+			next.ServeHTTP(w, r)
+		default:
+			writeServeError(w, http.StatusForbidden, fmt.Errorf("host %q not allowed", r.Host))
+		}
+	})
+}
+
+func (s *serveState) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	issues, hash, err := s.snapshot()
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.mu.Lock()
+	loadedAt := s.loadedAt
+	s.mu.Unlock()
+	writeServeJSON(w, http.StatusOK, struct {
+		Status   string `json:"status"`
+		DataHash string `json:"data_hash"`
+		Issues   int    `json:"issues"`
+		LoadedAt string `json:"loaded_at"`
+	}{"ok", hash, len(issues), loadedAt.UTC().Format(time.RFC3339)})
+}
+
+func (s *serveState) handleMemoized(key string, compute func([]model.Issue, string) any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		payload, err := s.memoized(key, compute)
+		if err != nil {
+			writeServeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeServeJSON(w, http.StatusOK, payload)
+	}
+}
+
+func (s *serveState) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		writeServeError(w, http.StatusBadRequest, errors.New("missing query parameter q"))
+		return
+	}
+	limit := 10
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeServeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %q", v))
+			return
+		}
+		limit = n
+	}
+	issues, hash, err := s.snapshot()
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeServeJSON(w, http.StatusOK, serveSearchPayload(issues, hash, query, limit))
+}
+
+func (s *serveState) handlePath(w http.ResponseWriter, r *http.Request) {
+	from, to := r.URL.Query().Get("from"), r.URL.Query().Get("to")
+	if from == "" || to == "" {
+		writeServeError(w, http.StatusBadRequest, errors.New("path needs both from and to"))
+		return
+	}
+	issues, hash, err := s.snapshot()
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	payload, err := servePathPayload(issues, hash, from, to)
+	if err != nil {
+		writeServeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeServeJSON(w, http.StatusOK, payload)
+}
+
+// handleGraph serves the interactive graph viewer for the current beads. The
+// page subscribes to /events and applies graph-patch events as the beads
+// change, instead of reloading.
+func (s *serveState) handleGraph(w http.ResponseWriter, r *http.Request) {
+	if err := s.refresh(); err != nil {
+		writeServeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	graph, err := s.liveGraph()
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	_, _ = io.WriteString(w, graph.HTML())
+}
+
+// liveGraph returns the /graph data for the current beads, building it on
+// first use. When an earlier rebuild failed it retries, and falls back to
+// the last good graph (which still patches forward) if that fails again.
+func (s *serveState) liveGraph() (*export.LiveGraph, error) {
+	s.mu.Lock()
+	graph, issues, hash := s.graph, s.issues, s.dataHash
+	s.mu.Unlock()
+	if graph != nil && graph.DataHash() == hash {
+		return graph, nil
+	}
+
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+	s.mu.Lock()
+	graph, issues, hash = s.graph, s.issues, s.dataHash
+	s.mu.Unlock()
+	if graph != nil && graph.DataHash() == hash {
+		return graph, nil
+	}
+
+	var seq int64
+	if graph != nil {
+		seq = graph.Seq() + 1
+	}
+	next, err := serveLiveGraph(issues, hash, seq, s.healthWeights)
+	if err != nil {
+		if graph != nil {
+			return graph, nil
+		}
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if graph != nil {
+		s.events.publish(serveGraphPatchEvent, graph.PatchTo(next))
+	}
+	s.graph = next
+	return next, nil
+}
+
+// serveLiveGraph builds the /graph viewer data, as --export-graph would, for
+// the patch numbered seq.
+func serveLiveGraph(issues []model.Issue, dataHash string, seq int64, healthWeights analysis.HealthWeights) (*export.LiveGraph, error) {
+	stats := analysis.NewAnalyzer(issues).Analyze()
+	triage := analysis.ComputeTriageWithOptions(issues, analysis.TriageOptions{WaitForPhase2: true})
+	cwd, _ := os.Getwd()
+	projectName := filepath.Base(cwd)
+	return export.NewLiveGraph(export.InteractiveGraphOptions{
+		Issues:        issues,
+		Stats:         &stats,
+		Triage:        &triage,
+		Title:         projectName,
+		DataHash:      dataHash,
+		ProjectName:   projectName,
+		LiveEventsURL: "/events?graph=1",
+		PatchSeq:      seq,
+		HealthWeights: healthWeights,
+	})
+}
+
+func writeServeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(v)
+}
+
+func writeServeError(w http.ResponseWriter, status int, err error) {
+	writeServeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
+
+func serveTimestamp() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// serveTriagePayload mirrors --robot-triage.
+func serveTriagePayload(issues []model.Issue, dataHash string) any {
+	triage := analysis.ComputeTriageWithOptions(issues, analysis.TriageOptions{WaitForPhase2: true})
+	return struct {
+		GeneratedAt string                `json:"generated_at"`
+		DataHash    string                `json:"data_hash"`
+		Triage      analysis.TriageResult `json:"triage"`
+	}{serveTimestamp(), dataHash, triage}
+}
+
+// serveNextPayload mirrors --robot-next, ranking through the same
+// computeNextPick.
+func serveNextPayload(issues []model.Issue, dataHash string) any {
+	pick, _ := computeNextPick(issues, analysis.TriageOptions{WaitForPhase2: true})
+	if pick == nil {
+		return struct {
+			GeneratedAt string `json:"generated_at"`
+			DataHash    string `json:"data_hash"`
+			Message     string `json:"message"`
+		}{serveTimestamp(), dataHash, "No actionable items available"}
+	}
+	return struct {
+		GeneratedAt string `json:"generated_at"`
+		DataHash    string `json:"data_hash"`
+		nextPick
+	}{serveTimestamp(), dataHash, *pick}
+}
+
+// servePlanPayload mirrors --robot-plan, skipping the centrality metrics the
+// plan does not use.
+func servePlanPayload(issues []model.Issue, dataHash string) any {
+	analyzer := analysis.NewAnalyzer(issues)
+	cfg := analysis.ConfigForSize(len(issues), countEdges(issues))
+	const skipReason = "not computed for --robot-plan"
+	cfg.ComputePageRank = false
+	cfg.PageRankSkipReason = skipReason
+	cfg.ComputeBetweenness = false
+	cfg.BetweennessMode = analysis.BetweennessSkip
+	cfg.BetweennessSkipReason = skipReason
+	cfg.ComputeHITS = false
+	cfg.HITSSkipReason = skipReason
+	cfg.ComputeEigenvector = false
+	cfg.ComputeCriticalPath = false
+	cfg.ComputeCycles = false
+	cfg.CyclesSkipReason = skipReason
+
+	plan := analyzer.GetExecutionPlan()
+	stats := analyzer.AnalyzeAsyncWithConfig(context.Background(), cfg)
+	stats.WaitForPhase2()
+	analyzer.AnnotatePlanSlack(&plan, stats.Slack())
+
+	return struct {
+		GeneratedAt    string                  `json:"generated_at"`
+		DataHash       string                  `json:"data_hash"`
+		AnalysisConfig analysis.AnalysisConfig `json:"analysis_config"`
+		Status         analysis.MetricStatus   `json:"status"`
+		Plan           analysis.ExecutionPlan  `json:"plan"`
+	}{serveTimestamp(), dataHash, cfg, stats.Status(), plan}
+}
+
+// serveInsightsPayload mirrors the summary sections of --robot-insights.
+func serveInsightsPayload(issues []model.Issue, dataHash string, healthWeights analysis.HealthWeights) any {
+	analyzer := analysis.NewAnalyzer(issues)
+	stats := analyzer.Analyze()
+	return struct {
+		GeneratedAt    string                   `json:"generated_at"`
+		DataHash       string                   `json:"data_hash"`
+		AnalysisConfig analysis.EffectiveConfig `json:"analysis_config"`
+		Status         analysis.MetricStatus    `json:"status"`
+		analysis.Insights
+		TopWhatIfs       []analysis.WhatIfEntry      `json:"top_what_ifs,omitempty"`
+		AdvancedInsights *analysis.AdvancedInsights  `json:"advanced_insights,omitempty"`
+		CycleRisks       analysis.CycleRiskReport    `json:"cycle_risks"`
+		Convergence      []analysis.ConvergencePoint `json:"convergence_points"`
+		Zombies          []correlation.ZombieBead    `json:"zombies"`
+		BeadHealth       []analysis.BeadHealth       `json:"bead_health"`
+		HealthWeights    analysis.HealthWeights      `json:"health_weights"`
+	}{
+		GeneratedAt:      serveTimestamp(),
+		DataHash:         dataHash,
+		AnalysisConfig:   stats.EffectiveConfig(),
+		Status:           stats.Status(),
+		Insights:         stats.GenerateInsights(50),
+		TopWhatIfs:       analyzer.TopWhatIfDeltas(10),
+		AdvancedInsights: analyzer.GenerateAdvancedInsights(analysis.DefaultAdvancedInsightsConfig()),
+		CycleRisks:       analysis.DetectCycleRisks(issues, analysis.DefaultMaxCycleRisks),
+		Convergence:      analysis.DetectConvergencePoints(issues, analysis.DefaultConvergenceMinFanIn),
+		Zombies:          detectZombieBeads(issues, correlation.DefaultZombieThreshold, time.Now()),
+		BeadHealth:       analyzer.ComputeBeadHealth(&stats, healthWeights, time.Now()),
+		HealthWeights:    healthWeights,
+	}
+}
+
+// serveSearchPayload answers /search lexically, which needs no embedder and
+// no on-disk index; use --robot-search for semantic or hybrid ranking.
+func serveSearchPayload(issues []model.Issue, dataHash, query string, limit int) any {
+	results := search.LexicalSearch(query, search.DocumentsFromIssues(issues))
+	if isLikelyIssueID(query) {
+		results = promoteExactSearchResult(query, results)
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	titleByID := make(map[string]string, len(issues))
+	for _, iss := range issues {
+		titleByID[iss.ID] = iss.Title
+	}
+	out := make([]robotSearchResult, 0, len(results))
+	for _, r := range results {
+		out = append(out, robotSearchResult{IssueID: r.IssueID, Score: r.Score, Title: titleByID[r.IssueID]})
+	}
+	return struct {
+		GeneratedAt string              `json:"generated_at"`
+		DataHash    string              `json:"data_hash"`
+		Query       string              `json:"query"`
+		Mode        string              `json:"mode"`
+		Limit       int                 `json:"limit"`
+		Results     []robotSearchResult `json:"results"`
+	}{serveTimestamp(), dataHash, query, "lexical", limit, out}
+}
+
+// servePathPayload finds the shortest chain of blocking dependencies that
+// leads from one bead to another, following each bead to what it depends on.
+func servePathPayload(issues []model.Issue, dataHash, from, to string) (any, error) {
+	known := make(map[string]bool, len(issues))
+	for _, iss := range issues {
+		known[iss.ID] = true
+	}
+	for _, id := range []string{from, to} {
+		if !known[id] {
+			return nil, fmt.Errorf("unknown bead %q", id)
+		}
+	}
+
+	adj := make(map[string][]string, len(issues))
+	for _, iss := range issues {
+		for _, dep := range iss.Dependencies {
+			if dep != nil && dep.Type.IsBlocking() && known[dep.DependsOnID] {
+				adj[iss.ID] = append(adj[iss.ID], dep.DependsOnID)
+			}
+		}
+	}
+
+	prev := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 && !hasKey(prev, to) {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, next := range adj[cur] {
+			if !hasKey(prev, next) {
+				prev[next] = cur
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	var path []string
+	if hasKey(prev, to) {
+		for id := to; id != ""; id = prev[id] {
+			path = append([]string{id}, path...)
+		}
+	}
+	return struct {
+		GeneratedAt string   `json:"generated_at"`
+		DataHash    string   `json:"data_hash"`
+		From        string   `json:"from"`
+		To          string   `json:"to"`
+		Found       bool     `json:"found"`
+		Path        []string `json:"path"`
+	}{serveTimestamp(), dataHash, from, to, path != nil, path}, nil
+}
+
+func hasKey(m map[string]string, k string) bool {
+	_, ok := m[k]
+	return ok
+}
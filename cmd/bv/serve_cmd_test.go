@@ -0,0 +1,447 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func writeServeTestRepo(t *testing.T, jsonl string) string {
+	t.Helper()
+	dir := t.TempDir()
+	beadsDir := filepath.Join(dir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(beadsDir, "beads.jsonl")
+	if err := os.WriteFile(path, []byte(jsonl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+	return path
+}
+
+func getServeJSON(t *testing.T, ts *httptest.Server, path string, wantStatus int) map[string]any {
+	t.Helper()
+	resp, err := http.Get(ts.URL + path)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != wantStatus {
+		t.Fatalf("GET %s: status %d, want %d", path, resp.StatusCode, wantStatus)
+	}
+	var body map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("GET %s: invalid JSON: %v", path, err)
+	}
+	return body
+}
+
+func TestServe_EndpointsMirrorRobotPayloads(t *testing.T) {
+	writeServeTestRepo(t, `{"id":"A","title":"Design schema","status":"open","priority":1,"issue_type":"task"}
+{"id":"B","title":"Build API","status":"open","priority":1,"issue_type":"task","dependencies":[{"issue_id":"B","depends_on_id":"A","type":"blocks"}]}
+{"id":"C","title":"Ship login","status":"open","priority":2,"issue_type":"feature","dependencies":[{"issue_id":"C","depends_on_id":"B","type":"blocks"}]}
+`)
+	srv := newServeState("")
+	if err := srv.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv.handler())
+	defer ts.Close()
+
+	health := getServeJSON(t, ts, "/healthz", http.StatusOK)
+	if health["status"] != "ok" || health["issues"] != float64(3) {
+		t.Fatalf("unexpected healthz: %v", health)
+	}
+
+	next := getServeJSON(t, ts, "/next", http.StatusOK)
+	if next["id"] == nil || next["claim_command"] == nil || next["data_hash"] != health["data_hash"] {
+		t.Fatalf("unexpected next: %v", next)
+	}
+	if triage := getServeJSON(t, ts, "/triage", http.StatusOK); triage["triage"] == nil {
+		t.Fatalf("triage payload missing: %v", triage)
+	}
+	if plan := getServeJSON(t, ts, "/plan", http.StatusOK); plan["plan"] == nil {
+		t.Fatalf("plan payload missing: %v", plan)
+	}
+	if insights := getServeJSON(t, ts, "/insights", http.StatusOK); insights["status"] == nil {
+		t.Fatalf("insights payload missing: %v", insights)
+	}
+
+	found := getServeJSON(t, ts, "/search?q=login", http.StatusOK)
+	results, _ := found["results"].([]any)
+	if len(results) == 0 || results[0].(map[string]any)["issue_id"] != "C" {
+		t.Fatalf("expected C first for 'login', got %v", found["results"])
+	}
+	getServeJSON(t, ts, "/search", http.StatusBadRequest)
+
+	path := getServeJSON(t, ts, "/path?from=C&to=A", http.StatusOK)
+	if got, _ := json.Marshal(path["path"]); string(got) != `["C","B","A"]` {
+		t.Fatalf("path C->A = %s, want C,B,A", got)
+	}
+	if reverse := getServeJSON(t, ts, "/path?from=A&to=C", http.StatusOK); reverse["found"] != false {
+		t.Fatalf("A does not depend on C, got %v", reverse)
+	}
+	getServeJSON(t, ts, "/path?from=A&to=missing", http.StatusNotFound)
+}
+
+func TestServe_NextMatchesRobotNext(t *testing.T) {
+	// A long P2 chain wins full triage on centrality; --robot-next ranks
+	// without it and picks the P1 bead instead
+	writeServeTestRepo(t, `{"id":"A","title":"Chain root","status":"open","priority":2,"issue_type":"task"}
+{"id":"B","title":"Chain 2","status":"open","priority":2,"issue_type":"task","dependencies":[{"issue_id":"B","depends_on_id":"A","type":"blocks"}]}
+{"id":"C","title":"Chain 3","status":"open","priority":2,"issue_type":"task","dependencies":[{"issue_id":"C","depends_on_id":"B","type":"blocks"}]}
+{"id":"D","title":"Chain 4","status":"open","priority":2,"issue_type":"task","dependencies":[{"issue_id":"D","depends_on_id":"C","type":"blocks"}]}
+{"id":"E","title":"Chain 5","status":"open","priority":2,"issue_type":"task","dependencies":[{"issue_id":"E","depends_on_id":"D","type":"blocks"}]}
+{"id":"H","title":"Urgent fix","status":"open","priority":1,"issue_type":"task"}
+{"id":"X","title":"Follow-up","status":"open","priority":2,"issue_type":"task","dependencies":[{"issue_id":"X","depends_on_id":"H","type":"blocks"}]}
+`)
+	srv := newServeState("")
+	if err := srv.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv.handler())
+	defer ts.Close()
+
+	issues, _, err := srv.snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pick, _ := computeNextPick(issues, analysis.TriageOptions{WaitForPhase2: true})
+	full := analysis.ComputeTriageWithOptions(issues, analysis.TriageOptions{WaitForPhase2: true})
+	if pick == nil || full.QuickRef.TopPicks[0].ID == pick.ID {
+		t.Fatalf("fixture should split the picks: triage %s, next %v", full.QuickRef.TopPicks[0].ID, pick)
+	}
+
+	if next := getServeJSON(t, ts, "/next", http.StatusOK); next["id"] != pick.ID {
+		t.Fatalf("/next id = %v, want --robot-next pick %s", next["id"], pick.ID)
+	}
+}
+
+func TestServe_InsightsUseConfiguredHealthWeights(t *testing.T) {
+	writeServeTestRepo(t, `{"id":"A","title":"Design schema","status":"open","priority":1,"issue_type":"task"}
+`)
+	srv := newServeState("")
+	srv.healthWeights = analysis.HealthWeights{Blockers: 1}
+	if err := srv.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv.handler())
+	defer ts.Close()
+
+	insights := getServeJSON(t, ts, "/insights", http.StatusOK)
+	weights, _ := insights["health_weights"].(map[string]any)
+	if weights["blockers"] != float64(1) || weights["staleness"] != float64(0) {
+		t.Fatalf("health_weights = %v, want blockers only", insights["health_weights"])
+	}
+	// With only the blockers weight, an unblocked bead is fully healthy
+	health, _ := insights["bead_health"].([]any)
+	if len(health) != 1 || health[0].(map[string]any)["score"] != float64(100) {
+		t.Fatalf("bead_health = %v, want A at 100", insights["bead_health"])
+	}
+}
+
+func TestServe_ConcurrentRequestsComputeOnce(t *testing.T) {
+	writeServeTestRepo(t, `{"id":"A","title":"Design schema","status":"open","priority":1,"issue_type":"task"}
+`)
+	srv := newServeState("")
+	if err := srv.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	var calls atomic.Int32
+	compute := func(issues []model.Issue, dataHash string) any {
+		calls.Add(1)
+		time.Sleep(200 * time.Millisecond)
+		return dataHash
+	}
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if _, err := srv.memoized("slow", compute); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Errorf("payload computed %d times for concurrent requests, want 1", n)
+	}
+}
+
+func TestServe_RejectsForeignHostHeader(t *testing.T) {
+	writeServeTestRepo(t, `{"id":"A","title":"Design schema","status":"open","priority":1,"issue_type":"task"}
+`)
+	srv := newServeState("")
+	srv.bindHost = "devbox.lan"
+	if err := srv.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv.handler())
+	defer ts.Close()
+
+	for host, want := range map[string]int{
+		"":                  http.StatusOK, // ts.URL's 127.0.0.1:port
+		"localhost:8787":    http.StatusOK,
+		"[::1]:8787":        http.StatusOK,
+		"devbox.lan:8787":   http.StatusOK,
+		"evil.example:8787": http.StatusForbidden,
+		"evil.example":      http.StatusForbidden,
+	} {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+"/healthz", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if host != "" {
+			req.Host = host
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("Host %q: status %d, want %d", host, resp.StatusCode, want)
+		}
+	}
+}
+
+func TestServe_ReloadsWhenBeadsChange(t *testing.T) {
+	path := writeServeTestRepo(t, `{"id":"A","title":"First","status":"open","priority":1,"issue_type":"task"}
+`)
+	srv := newServeState("")
+	if err := srv.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv.handler())
+	defer ts.Close()
+
+	before := getServeJSON(t, ts, "/next", http.StatusOK)
+	if again := getServeJSON(t, ts, "/next", http.StatusOK); again["generated_at"] != before["generated_at"] {
+		t.Fatalf("unchanged data should reuse the memoized payload")
+	}
+
+	if err := os.WriteFile(path, []byte(`{"id":"A","title":"First","status":"closed","priority":1,"issue_type":"task"}
+{"id":"Z","title":"Second","status":"open","priority":0,"issue_type":"task"}
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Make the change visible even on filesystems with coarse mtimes.
+	later := time.Now().Add(2 * time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	after := getServeJSON(t, ts, "/next", http.StatusOK)
+	if after["id"] != "Z" || after["data_hash"] == before["data_hash"] {
+		t.Fatalf("expected reload to pick Z with a new hash, got %v", after)
+	}
+}
+
+// readServeEvent reads one SSE event, skipping retry lines and comments.
+func readServeEvent(t *testing.T, r *bufio.Reader) (id, name string, data map[string]any) {
+	t.Helper()
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading event stream: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			if name != "" {
+				return id, name, data
+			}
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &data); err != nil {
+				t.Fatalf("invalid event data %q: %v", line, err)
+			}
+		}
+	}
+}
+
+func openServeEvents(t *testing.T, ts *httptest.Server, lastEventID string) *bufio.Reader {
+	t.Helper()
+	return openServeEventsAt(t, ts, "/events", lastEventID)
+}
+
+func openServeEventsAt(t *testing.T, ts *httptest.Server, path, lastEventID string) *bufio.Reader {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, ts.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	return bufio.NewReader(resp.Body)
+}
+
+func TestServe_EventsStreamUpdatesAndReplay(t *testing.T) {
+	path := writeServeTestRepo(t, `{"id":"A","title":"First","status":"open","priority":1,"issue_type":"task"}
+{"id":"B","title":"Second","status":"open","priority":2,"issue_type":"task"}
+`)
+	srv := newServeState("")
+	if err := srv.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv.handler())
+	t.Cleanup(ts.Close) // after the streams opened below are closed
+
+	stream := openServeEvents(t, ts, "")
+	id, name, data := readServeEvent(t, stream)
+	if name != "snapshot" || id != "0" || data["summary"].(map[string]any)["total"] != float64(2) {
+		t.Fatalf("unexpected first event %s %s %v", id, name, data)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"id":"A","title":"First","status":"closed","priority":1,"issue_type":"task"}
+{"id":"C","title":"Third","status":"open","priority":2,"issue_type":"task"}
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(2 * time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.refresh(); err != nil {
+		t.Fatal(err)
+	}
+
+	id, name, data = readServeEvent(t, stream)
+	if name != "update" || id != "1" {
+		t.Fatalf("expected update 1, got %s %s", id, name)
+	}
+	for field, want := range map[string]string{"added": `["C"]`, "changed": `["A"]`, "removed": `["B"]`} {
+		if got, _ := json.Marshal(data[field]); string(got) != want {
+			t.Errorf("%s = %s, want %s", field, got, want)
+		}
+	}
+	if data["summary"].(map[string]any)["closed"] != float64(1) {
+		t.Errorf("summary not updated: %v", data["summary"])
+	}
+
+	// A client that saw only the snapshot gets the missed update on resume.
+	resumed := openServeEvents(t, ts, "0")
+	if id, name, _ := readServeEvent(t, resumed); id != "1" || name != "update" {
+		t.Fatalf("resume from 0 replayed %s %s, want update 1", id, name)
+	}
+	// An ID from a previous server run falls back to a snapshot.
+	if _, name, _ := readServeEvent(t, openServeEvents(t, ts, "99")); name != "snapshot" {
+		t.Fatalf("unknown Last-Event-ID should yield a snapshot, got %s", name)
+	}
+}
+
+func TestServe_GraphReceivesPatches(t *testing.T) {
+	path := writeServeTestRepo(t, `{"id":"A","title":"First","status":"open","priority":1,"issue_type":"task"}
+{"id":"B","title":"Second","status":"open","priority":2,"issue_type":"task"}
+`)
+	srv := newServeState("")
+	if err := srv.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(srv.handler())
+	t.Cleanup(ts.Close)
+
+	resp, err := http.Get(ts.URL + "/graph")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(resp.Header.Get("Content-Type"), "text/html") {
+		t.Fatalf("GET /graph = %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if !strings.Contains(string(page), `"live":{"events":"/events?graph=1","seq":0}`) {
+		t.Error("/graph page should subscribe to /events?graph=1 from seq 0")
+	}
+
+	stream := openServeEventsAt(t, ts, "/events?graph=1", "")
+	readServeEvent(t, stream) // snapshot
+	plain := openServeEvents(t, ts, "")
+	readServeEvent(t, plain) // snapshot
+
+	if err := os.WriteFile(path, []byte(`{"id":"A","title":"First","status":"open","priority":1,"issue_type":"task"}
+{"id":"C","title":"Third","status":"open","priority":2,"issue_type":"task","dependencies":[{"issue_id":"C","depends_on_id":"A","type":"blocks"}]}
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(2 * time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.refresh(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, name, _ := readServeEvent(t, stream); name != "update" {
+		t.Fatalf("expected update before the graph patch, got %s", name)
+	}
+	_, name, data := readServeEvent(t, stream)
+	if name != "graph-patch" || data["seq"] != float64(1) {
+		t.Fatalf("expected graph-patch seq 1, got %s %v", name, data["seq"])
+	}
+	nodes := data["nodes"].(map[string]any)
+	if got, _ := json.Marshal(nodes["removed"]); string(got) != `["B"]` {
+		t.Errorf("removed nodes = %s, want [\"B\"]", got)
+	}
+	if links, _ := data["links"].(map[string]any)["added"].([]any); len(links) != 1 {
+		t.Errorf("added links = %v, want the C->A link", links)
+	}
+	recs, _ := data["triage"].(map[string]any)["recommendations"].([]any)
+	if len(recs) == 0 {
+		t.Error("graph-patch should carry the recomputed triage")
+	}
+	for _, rec := range recs {
+		if rec.(map[string]any)["id"] == "B" {
+			t.Error("patched triage still recommends the removed bead B")
+		}
+	}
+
+	// Plain /events consumers only get update events
+	if _, name, _ := readServeEvent(t, plain); name != "update" {
+		t.Fatalf("plain stream: expected update, got %s", name)
+	}
+	if err := os.WriteFile(path, []byte(`{"id":"A","title":"First","status":"closed","priority":1,"issue_type":"task"}
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	later = later.Add(2 * time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if id, name, _ := readServeEvent(t, plain); name != "update" || id != "3" {
+		t.Fatalf("plain stream: expected update 3 with no graph-patch in between, got %s %s", id, name)
+	}
+}
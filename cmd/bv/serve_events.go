@@ -0,0 +1,288 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/export"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+const (
+	// serveEventBacklog is how many updates are kept for Last-Event-ID replay.
+	serveEventBacklog = 64
+	// serveEventMaxIDs caps the bead IDs listed in one update; beyond it the
+	// update is marked truncated and clients should refetch.
+	serveEventMaxIDs = 200
+	// serveEventHeartbeat keeps idle connections open through proxies.
+	serveEventHeartbeat = 15 * time.Second
+	// serveEventRetryMillis is the reconnect delay suggested to clients.
+	serveEventRetryMillis = 3000
+)
+
+// serveSummary is the compact set of counts sent with every update.
+type serveSummary struct {
+	Total      int `json:"total"`
+	Open       int `json:"open"`
+	InProgress int `json:"in_progress"`
+	Closed     int `json:"closed"`
+	Blocked    int `json:"blocked"`
+	Ready      int `json:"ready"`
+}
+
+// serveUpdate is the payload of an `update` event: what changed between two
+// loads of the beads.
+type serveUpdate struct {
+	DataHash     string       `json:"data_hash"`
+	PreviousHash string       `json:"previous_hash"`
+	Added        []string     `json:"added,omitempty"`
+	Changed      []string     `json:"changed,omitempty"`
+	Removed      []string     `json:"removed,omitempty"`
+	Truncated    bool         `json:"truncated,omitempty"`
+	Summary      serveSummary `json:"summary"`
+}
+
+// serveSnapshot is the payload of a `snapshot` event, sent when a client
+// connects fresh or asks to resume from an event that is no longer buffered.
+type serveSnapshot struct {
+	DataHash string       `json:"data_hash"`
+	Summary  serveSummary `json:"summary"`
+}
+
+type serveEvent struct {
+	id   int64
+	name string
+	data []byte
+}
+
+// serveGraphPatchEvent is the event carrying /graph viewer patches. Only
+// subscribers that ask for it (/events?graph=1) receive it.
+const serveGraphPatchEvent = "graph-patch"
+
+// serveEventHub buffers recent updates and fans them out to SSE clients.
+type serveEventHub struct {
+	mu      sync.Mutex
+	lastID  int64
+	backlog []serveEvent
+	limit   int
+	subs    map[chan serveEvent]bool // Value: subscriber wants graph-patch events
+}
+
+func newServeEventHub(limit int) *serveEventHub {
+	return &serveEventHub{limit: limit, subs: make(map[chan serveEvent]bool)}
+}
+
+// publish records v as the next event, named name (`update`, or
+// `graph-patch` for live /graph viewers), and delivers it to every
+// subscriber that wants it. A subscriber too slow to keep up is
+// disconnected; it resumes via Last-Event-ID and the replay backlog.
+func (h *serveEventHub) publish(name string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastID++
+	ev := serveEvent{id: h.lastID, name: name, data: data}
+	h.backlog = append(h.backlog, ev)
+	if len(h.backlog) > h.limit {
+		h.backlog = h.backlog[len(h.backlog)-h.limit:]
+	}
+	for ch, graphPatches := range h.subs {
+		if name == serveGraphPatchEvent && !graphPatches {
+			continue
+		}
+		select {
+		case ch <- ev:
+		default:
+			delete(h.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// subscribe registers a client. With resume set, it returns the buffered
+// events after lastEventID; complete is false when some of them have already
+// been dropped, in which case the client needs a fresh snapshot. currentID is
+// the ID of the latest event at subscription time. graphPatches opts in to
+// graph-patch events, which are large and only meant for /graph pages.
+func (h *serveEventHub) subscribe(lastEventID int64, resume, graphPatches bool) (ch chan serveEvent, replay []serveEvent, currentID int64, complete bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ch = make(chan serveEvent, 16)
+	h.subs[ch] = graphPatches
+
+	complete = resume && lastEventID <= h.lastID
+	if complete && lastEventID < h.lastID {
+		oldest := h.lastID - int64(len(h.backlog)) + 1
+		if lastEventID+1 < oldest {
+			complete = false
+		} else {
+			for _, ev := range h.backlog[lastEventID+1-oldest:] {
+				if ev.name != serveGraphPatchEvent || graphPatches {
+					replay = append(replay, ev)
+				}
+			}
+		}
+	}
+	return ch, replay, h.lastID, complete
+}
+
+func (h *serveEventHub) unsubscribe(ch chan serveEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[ch]; ok {
+		delete(h.subs, ch)
+		close(ch)
+	}
+}
+
+// closeAll ends every stream, e.g. so a graceful shutdown is not held open
+// by long-lived SSE connections.
+func (h *serveEventHub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs {
+		delete(h.subs, ch)
+		close(ch)
+	}
+}
+
+// handleEvents streams updates as server-sent events. Clients resume with the
+// standard Last-Event-ID header (or ?last_event_id= for clients that cannot
+// set headers) and receive every buffered update they missed. ?graph=1 adds
+// the graph-patch events live /graph pages apply.
+func (s *serveState) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeServeError(w, http.StatusInternalServerError, fmt.Errorf("streaming not supported"))
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+	var resumeFrom int64
+	resume := lastEventID != ""
+	if resume {
+		n, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || n < 0 {
+			writeServeError(w, http.StatusBadRequest, fmt.Errorf("invalid Last-Event-ID %q", lastEventID))
+			return
+		}
+		resumeFrom = n
+	}
+
+	// Pick up pending file changes first so they arrive as events.
+	if err := s.refresh(); err != nil {
+		writeServeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	// Events are published under s.mu, so reading the snapshot and
+	// subscribing in one critical section means no update can fall between
+	// the snapshot's data and the first event on the channel.
+	graphPatches := r.URL.Query().Get("graph") == "1"
+	s.mu.Lock()
+	issues, hash := s.issues, s.dataHash
+	ch, replay, currentID, complete := s.events.subscribe(resumeFrom, resume, graphPatches)
+	s.mu.Unlock()
+	defer s.events.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", serveEventRetryMillis)
+
+	if complete {
+		for _, ev := range replay {
+			writeServeEvent(w, ev)
+		}
+	} else {
+		data, _ := json.Marshal(serveSnapshot{DataHash: hash, Summary: summarizeServeIssues(issues)})
+		writeServeEvent(w, serveEvent{id: currentID, name: "snapshot", data: data})
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(serveEventHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-ch:
+			if !ok {
+				return
+			}
+			writeServeEvent(w, ev)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func writeServeEvent(w http.ResponseWriter, ev serveEvent) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.id, ev.name, ev.data)
+}
+
+// summarizeServeIssues reuses the backlog gauges behind --robot-metrics.
+func summarizeServeIssues(issues []model.Issue) serveSummary {
+	m := export.ComputeBacklogMetrics(issues, "")
+	return serveSummary{
+		Total:      m.Total,
+		Open:       m.ByStatus[model.StatusOpen],
+		InProgress: m.ByStatus[model.StatusInProgress],
+		Closed:     m.ByStatus[model.StatusClosed],
+		Blocked:    m.Blocked,
+		Ready:      m.Ready,
+	}
+}
+
+// diffServeUpdate lists the beads added, changed or removed between two loads.
+func diffServeUpdate(before, after []model.Issue, beforeHash, afterHash string) serveUpdate {
+	encoded := func(issues []model.Issue) map[string]string {
+		out := make(map[string]string, len(issues))
+		for _, iss := range issues {
+			b, _ := json.Marshal(iss)
+			out[iss.ID] = string(b)
+		}
+		return out
+	}
+	old, cur := encoded(before), encoded(after)
+
+	u := serveUpdate{DataHash: afterHash, PreviousHash: beforeHash, Summary: summarizeServeIssues(after)}
+	for id, b := range cur {
+		if a, ok := old[id]; !ok {
+			u.Added = append(u.Added, id)
+		} else if a != b {
+			u.Changed = append(u.Changed, id)
+		}
+	}
+	for id := range old {
+		if _, ok := cur[id]; !ok {
+			u.Removed = append(u.Removed, id)
+		}
+	}
+	sort.Strings(u.Added)
+	sort.Strings(u.Changed)
+	sort.Strings(u.Removed)
+
+	budget := serveEventMaxIDs
+	for _, ids := range []*[]string{&u.Added, &u.Changed, &u.Removed} {
+		if len(*ids) > budget {
+			*ids = (*ids)[:budget]
+			u.Truncated = true
+		}
+		budget -= len(*ids)
+	}
+	return u
+}
@@ -165,6 +165,22 @@ func NeedsUpdate(content string) bool {
 	return GetBlurbVersion(content) < BlurbVersion
 }
 
+// BlurbDrift checks if the content has the current version of the blurb but its
+// text no longer matches AgentBlurb, e.g. after a hand edit or a lost end marker.
+// Legacy and older blurbs are reported by NeedsUpdate instead.
+func BlurbDrift(content string) bool {
+	if !ContainsBlurb(content) || GetBlurbVersion(content) != BlurbVersion {
+		return false
+	}
+	startIdx := strings.Index(content, "<!-- bv-agent-instructions-v")
+	endIdx := strings.Index(content[startIdx:], BlurbEndMarker)
+	if endIdx == -1 {
+		return true
+	}
+	blurb := content[startIdx : startIdx+endIdx+len(BlurbEndMarker)]
+	return strings.ReplaceAll(blurb, "\r\n", "\n") != AgentBlurb
+}
+
 // AppendBlurb appends the agent blurb to the given content.
 func AppendBlurb(content string) string {
 	if !strings.HasSuffix(content, "\n") {
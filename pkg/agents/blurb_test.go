@@ -192,6 +192,29 @@ func TestNeedsUpdate(t *testing.T) {
 	}
 }
 
+func TestBlurbDrift(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected bool
+	}{
+		{"no blurb", "# No blurb", false},
+		{"current blurb", AppendBlurb("# Project"), false},
+		{"current blurb with CRLF", strings.ReplaceAll(AppendBlurb("# Project"), "\n", "\r\n"), false},
+		{"edited blurb", strings.Replace(AppendBlurb("# Project"), "bd ready ", "bd next ", 1), true},
+		{"missing end marker", strings.Replace(AppendBlurb("# Project"), BlurbEndMarker, "", 1), true},
+		{"older version", "<!-- bv-agent-instructions-v0 -->\nold\n" + BlurbEndMarker, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BlurbDrift(tt.content); got != tt.expected {
+				t.Errorf("BlurbDrift() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestAgentBlurbContent(t *testing.T) {
 	// Verify blurb contains essential commands
 	essentials := []string{
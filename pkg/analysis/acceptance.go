@@ -0,0 +1,68 @@
+package analysis
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// AcceptanceProgress summarizes markdown checklist items ("- [ ]" / "- [x]")
+// found in a bead's acceptance criteria.
+type AcceptanceProgress struct {
+	Done  int     `json:"done"`
+	Total int     `json:"total"`
+	Ratio float64 `json:"ratio"` // Done/Total, 0.0-1.0
+}
+
+// checklistItemRe matches a markdown task-list item: a bullet (-, *, +) or
+// ordered marker (1. / 1)) followed by [ ], [x] or [X].
+var checklistItemRe = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[([ xX])\]`)
+
+// ParseAcceptanceProgress counts checklist items in markdown text. Items inside
+// fenced code blocks are ignored. The second return value is false when the
+// text has no checklist items, so callers can omit the indicator entirely.
+func ParseAcceptanceProgress(text string) (AcceptanceProgress, bool) {
+	var p AcceptanceProgress
+	if text == "" {
+		return p, false
+	}
+
+	inFence := false
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		m := checklistItemRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		p.Total++
+		if m[1] != " " {
+			p.Done++
+		}
+	}
+
+	if p.Total == 0 {
+		return p, false
+	}
+	p.Ratio = float64(p.Done) / float64(p.Total)
+	return p, true
+}
+
+// ComputeAcceptanceProgress returns checklist progress for every bead whose
+// acceptance criteria contain at least one checklist item.
+func ComputeAcceptanceProgress(issues []model.Issue) map[string]AcceptanceProgress {
+	result := make(map[string]AcceptanceProgress)
+	for _, issue := range issues {
+		if p, ok := ParseAcceptanceProgress(issue.AcceptanceCriteria); ok {
+			result[issue.ID] = p
+		}
+	}
+	return result
+}
@@ -0,0 +1,58 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestParseAcceptanceProgress(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		wantOK    bool
+		wantDone  int
+		wantTotal int
+	}{
+		{"empty", "", false, 0, 0},
+		{"prose only", "Works on all platforms.\n- no checkbox here", false, 0, 0},
+		{"mixed", "- [x] parse\n- [ ] render\n* [X] test\n+ [ ] docs", true, 2, 4},
+		{"ordered and indented", "1. [x] first\n  2) [ ] nested", true, 1, 2},
+		{"ignores fenced code", "- [x] real\n```\n- [ ] example\n```", true, 1, 1},
+		{"requires bullet", "[x] not a list item\n-[x] missing space", false, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := ParseAcceptanceProgress(tt.text)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if p.Done != tt.wantDone || p.Total != tt.wantTotal {
+				t.Errorf("got %d/%d, want %d/%d", p.Done, p.Total, tt.wantDone, tt.wantTotal)
+			}
+			if ok {
+				want := float64(tt.wantDone) / float64(tt.wantTotal)
+				if p.Ratio != want {
+					t.Errorf("ratio = %v, want %v", p.Ratio, want)
+				}
+			}
+		})
+	}
+}
+
+func TestComputeAcceptanceProgress_OmitsBeadsWithoutChecklist(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "a", AcceptanceCriteria: "- [x] one\n- [x] two"},
+		{ID: "b", AcceptanceCriteria: "Just prose"},
+		{ID: "c"},
+	}
+
+	got := ComputeAcceptanceProgress(issues)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 entry, got %v", got)
+	}
+	if got["a"].Ratio != 1.0 {
+		t.Errorf("expected a to be fully done, got %+v", got["a"])
+	}
+}
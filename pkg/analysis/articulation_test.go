@@ -9,7 +9,7 @@ func TestFindArticulationPointsHandlesZeroID(t *testing.T) {
 	// Explicit IDs: 0-1-2 chain; 1 should be articulation.
 	adj := undirectedAdjacency{
 		nodes: []int64{0, 1, 2},
-		neighbors: map[int64][]int64{
+		neighbors: [][]int64{
 			0: {1},
 			1: {0, 2},
 			2: {1},
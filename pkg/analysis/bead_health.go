@@ -0,0 +1,188 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HealthBlockerCap is the open-blocker count at which a bead takes the full
+// blocker penalty; each blocker below it adds an equal share.
+const HealthBlockerCap = 3
+
+// HealthWeights sets how much each penalty counts toward a bead's health
+// score. Only the ratios matter: the score divides by the weight sum.
+type HealthWeights struct {
+	Staleness        float64 `json:"staleness"`         // Days since update, saturating at 30
+	Blockers         float64 `json:"blockers"`          // Open blockers, saturating at HealthBlockerCap
+	CriticalPath     float64 `json:"critical_path"`     // Zero slack on a dependency chain
+	PriorityMismatch float64 `json:"priority_mismatch"` // Distance from the impact-suggested priority
+}
+
+// DefaultHealthWeights returns the weights used when none are configured.
+// Staleness and blockers dominate because they mean nobody can or does work
+// on the bead; mismatch and critical-path exposure are risk signals.
+func DefaultHealthWeights() HealthWeights {
+	return HealthWeights{
+		Staleness:        0.30,
+		Blockers:         0.30,
+		CriticalPath:     0.15,
+		PriorityMismatch: 0.25,
+	}
+}
+
+func (w HealthWeights) sum() float64 {
+	return w.Staleness + w.Blockers + w.CriticalPath + w.PriorityMismatch
+}
+
+// ParseHealthWeights parses a key=value list such as
+// "staleness=0.5,blockers=0.5" (keys: staleness, blockers, critical_path,
+// priority_mismatch). Keys left out weigh 0; at least one weight must be
+// positive.
+func ParseHealthWeights(spec string) (HealthWeights, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return HealthWeights{}, fmt.Errorf("health weights spec is empty")
+	}
+
+	var w HealthWeights
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return HealthWeights{}, fmt.Errorf("health weights entry %q must be key=value", part)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		if seen[key] {
+			return HealthWeights{}, fmt.Errorf("health weights spec has duplicate key %q", key)
+		}
+		seen[key] = true
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return HealthWeights{}, fmt.Errorf("health weights value for %q is not a number: %q", key, value)
+		}
+		if v < 0 {
+			return HealthWeights{}, fmt.Errorf("health weights value for %q must not be negative: %g", key, v)
+		}
+		switch key {
+		case "staleness":
+			w.Staleness = v
+		case "blockers":
+			w.Blockers = v
+		case "critical_path":
+			w.CriticalPath = v
+		case "priority_mismatch":
+			w.PriorityMismatch = v
+		default:
+			return HealthWeights{}, fmt.Errorf("health weights spec has unknown key %q (valid: staleness, blockers, critical_path, priority_mismatch)", key)
+		}
+	}
+	if w.sum() == 0 {
+		return HealthWeights{}, fmt.Errorf("health weights must include at least one positive weight")
+	}
+	return w, nil
+}
+
+// HealthPenalties are the 0-1 components behind a health score; 0 is healthy.
+type HealthPenalties struct {
+	Staleness        float64 `json:"staleness"`
+	Blockers         float64 `json:"blockers"`
+	CriticalPath     float64 `json:"critical_path"`
+	PriorityMismatch float64 `json:"priority_mismatch"`
+}
+
+// BeadHealth is the composite health of one open bead.
+type BeadHealth struct {
+	ID                string          `json:"id"`
+	Title             string          `json:"title"`
+	Score             int             `json:"score"` // 0 (unhealthy) to 100 (healthy)
+	Penalties         HealthPenalties `json:"penalties"`
+	OpenBlockers      int             `json:"open_blockers"`
+	OnCriticalPath    bool            `json:"on_critical_path"`
+	Priority          int             `json:"priority"`
+	SuggestedPriority int             `json:"suggested_priority"` // From the impact score, as in --robot-priority
+}
+
+// ComputeBeadHealth scores every open bead from 0 to 100 as
+// 100 * (1 - weighted mean of its penalties):
+//   - staleness: days since the last update over 30, capped at 1
+//   - blockers: open blockers over HealthBlockerCap, capped at 1
+//   - critical path: 1 when the bead has zero slack on a dependency chain
+//   - priority mismatch: levels between its priority and the one its impact
+//     score suggests, over 4
+//
+// Zero weights fall back to DefaultHealthWeights. Results are sorted least
+// healthy first, then by ID.
+func (a *Analyzer) ComputeBeadHealth(stats *GraphStats, weights HealthWeights, now time.Time) []BeadHealth {
+	if weights.sum() <= 0 {
+		weights = DefaultHealthWeights()
+	}
+
+	openBlockers := make(map[string]int, len(a.issueMap))
+	chained := make(map[string]bool, len(a.issueMap))
+	for id, issue := range a.issueMap {
+		if issue.Status.IsClosed() || issue.Status.IsTombstone() {
+			continue
+		}
+		for _, dep := range issue.Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() || dep.DependsOnID == id {
+				continue
+			}
+			blocker, ok := a.issueMap[dep.DependsOnID]
+			if !ok || blocker.Status.IsClosed() || blocker.Status.IsTombstone() {
+				continue
+			}
+			openBlockers[id]++
+			chained[id] = true
+			chained[dep.DependsOnID] = true
+		}
+	}
+
+	var results []BeadHealth
+	for _, impact := range a.ComputeImpactScoresFromStats(stats, now) {
+		issue := a.issueMap[impact.IssueID]
+		h := BeadHealth{
+			ID:                issue.ID,
+			Title:             issue.Title,
+			OpenBlockers:      openBlockers[issue.ID],
+			Priority:          issue.Priority,
+			SuggestedPriority: scoreToPriority(impact.Score),
+		}
+		if slack, ok := stats.SlackValue(issue.ID); ok && slack == 0 && chained[issue.ID] {
+			h.OnCriticalPath = true
+		}
+
+		h.Penalties.Staleness = computeStaleness(issue.UpdatedAt, now)
+		h.Penalties.Blockers = math.Min(float64(h.OpenBlockers)/HealthBlockerCap, 1)
+		if h.OnCriticalPath {
+			h.Penalties.CriticalPath = 1
+		}
+		mismatch := h.Priority - h.SuggestedPriority
+		if mismatch < 0 {
+			mismatch = -mismatch
+		}
+		h.Penalties.PriorityMismatch = math.Min(float64(mismatch)/4, 1)
+
+		penalty := weights.Staleness*h.Penalties.Staleness +
+			weights.Blockers*h.Penalties.Blockers +
+			weights.CriticalPath*h.Penalties.CriticalPath +
+			weights.PriorityMismatch*h.Penalties.PriorityMismatch
+		h.Score = int(math.Round(100 * (1 - penalty/weights.sum())))
+		results = append(results, h)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score < results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+	return results
+}
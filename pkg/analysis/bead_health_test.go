@@ -0,0 +1,117 @@
+package analysis_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func beadHealthByID(t *testing.T, issues []model.Issue, weights analysis.HealthWeights, now time.Time) map[string]analysis.BeadHealth {
+	t.Helper()
+	an := analysis.NewAnalyzer(issues)
+	stats := an.Analyze()
+	byID := make(map[string]analysis.BeadHealth)
+	for _, h := range an.ComputeBeadHealth(&stats, weights, now) {
+		byID[h.ID] = h
+	}
+	return byID
+}
+
+func TestComputeBeadHealth_FreshScoresHighStaleBlockedScoresLow(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	stale := now.Add(-90 * 24 * time.Hour)
+	blockedBy := func(ids ...string) []*model.Dependency {
+		var deps []*model.Dependency
+		for _, id := range ids {
+			deps = append(deps, &model.Dependency{DependsOnID: id, Type: model.DepBlocks})
+		}
+		return deps
+	}
+
+	issues := []model.Issue{
+		// Fresh, unblocked, and prioritized where its impact puts it
+		{ID: "fresh", Title: "Fresh", Status: model.StatusOpen, Priority: 3, UpdatedAt: now},
+		// Stale, waiting on three open blockers, and marked P0 despite blocking nothing
+		{ID: "stale", Title: "Stale", Status: model.StatusOpen, Priority: 0, UpdatedAt: stale, Dependencies: blockedBy("b1", "b2", "b3")},
+		{ID: "b1", Title: "Blocker 1", Status: model.StatusOpen, Priority: 2, UpdatedAt: now},
+		{ID: "b2", Title: "Blocker 2", Status: model.StatusOpen, Priority: 2, UpdatedAt: now},
+		{ID: "b3", Title: "Blocker 3", Status: model.StatusOpen, Priority: 2, UpdatedAt: now},
+		{ID: "done", Title: "Done", Status: model.StatusClosed, Priority: 1, UpdatedAt: stale},
+	}
+
+	health := beadHealthByID(t, issues, analysis.DefaultHealthWeights(), now)
+
+	if _, ok := health["done"]; ok {
+		t.Error("closed bead should not be scored")
+	}
+
+	fresh := health["fresh"]
+	if fresh.SuggestedPriority != fresh.Priority {
+		t.Fatalf("fixture drifted: fresh bead suggested P%d, has P%d", fresh.SuggestedPriority, fresh.Priority)
+	}
+	if fresh.Score < 90 {
+		t.Errorf("fresh bead score = %d, want >= 90 (penalties %+v)", fresh.Score, fresh.Penalties)
+	}
+
+	low := health["stale"]
+	if low.OpenBlockers != 3 || !low.OnCriticalPath {
+		t.Errorf("stale bead: open_blockers=%d on_critical_path=%v, want 3 and true", low.OpenBlockers, low.OnCriticalPath)
+	}
+	if low.Score > 30 {
+		t.Errorf("stale bead score = %d, want <= 30 (penalties %+v)", low.Score, low.Penalties)
+	}
+	if low.Score >= fresh.Score {
+		t.Errorf("stale bead (%d) should score below fresh bead (%d)", low.Score, fresh.Score)
+	}
+}
+
+func TestComputeBeadHealth_WeightsAndOrder(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	issues := []model.Issue{
+		{ID: "b", Title: "Old", Status: model.StatusOpen, Priority: 3, UpdatedAt: now.Add(-60 * 24 * time.Hour)},
+		{ID: "a", Title: "Old too", Status: model.StatusOpen, Priority: 3, UpdatedAt: now.Add(-60 * 24 * time.Hour)},
+		{ID: "c", Title: "New", Status: model.StatusOpen, Priority: 3, UpdatedAt: now},
+	}
+
+	an := analysis.NewAnalyzer(issues)
+	stats := an.Analyze()
+	got := an.ComputeBeadHealth(&stats, analysis.HealthWeights{Staleness: 1}, now)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(got))
+	}
+	if got[0].ID != "a" || got[1].ID != "b" || got[2].ID != "c" {
+		t.Errorf("order = %s,%s,%s; want a,b,c (least healthy first, ties by ID)", got[0].ID, got[1].ID, got[2].ID)
+	}
+	if got[0].Score != 0 || got[2].Score != 100 {
+		t.Errorf("staleness-only scores = %d and %d, want 0 and 100", got[0].Score, got[2].Score)
+	}
+}
+
+func TestParseHealthWeights(t *testing.T) {
+	w, err := analysis.ParseHealthWeights("staleness=0.5, Blockers=0.25,critical_path=0,priority_mismatch=0.25")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := analysis.HealthWeights{Staleness: 0.5, Blockers: 0.25, PriorityMismatch: 0.25}
+	if w != want {
+		t.Errorf("got %+v, want %+v", w, want)
+	}
+
+	for spec, wantErr := range map[string]string{
+		"":                       "empty",
+		"staleness":              "key=value",
+		"age=1":                  "unknown key",
+		"blockers=1,blockers=2":  "duplicate",
+		"blockers=x":             "not a number",
+		"blockers=NaN":           "not a number",
+		"blockers=-1":            "negative",
+		"staleness=0,blockers=0": "positive",
+	} {
+		if _, err := analysis.ParseHealthWeights(spec); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("ParseHealthWeights(%q) error = %v, want it to mention %q", spec, err, wantErr)
+		}
+	}
+}
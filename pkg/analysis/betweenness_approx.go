@@ -1,6 +1,7 @@
 package analysis
 
 import (
+	"context"
 	"math/rand"
 	"runtime"
 	"sort"
@@ -251,6 +252,9 @@ type BetweennessResult struct {
 
 	// TimedOut indicates if computation was interrupted by timeout
 	TimedOut bool
+
+	// Workers is the number of goroutines that processed pivots
+	Workers int
 }
 
 // ApproxBetweenness computes approximate betweenness centrality using sampling.
@@ -269,6 +273,13 @@ type BetweennessResult struct {
 //   - "A Faster Algorithm for Betweenness Centrality" (Brandes, 2001)
 //   - "Approximating Betweenness Centrality" (Bader et al., 2007)
 func ApproxBetweenness(g *simple.DirectedGraph, sampleSize int, seed int64) BetweennessResult {
+	result, _ := ApproxBetweennessContext(context.Background(), g, sampleSize, seed)
+	return result
+}
+
+// ApproxBetweennessContext is ApproxBetweenness with cancellation: remaining
+// pivots are skipped once ctx is done, and ctx.Err() is returned with empty scores.
+func ApproxBetweennessContext(ctx context.Context, g *simple.DirectedGraph, sampleSize int, seed int64) (BetweennessResult, error) {
 	start := time.Now()
 	nodes := pooledNodesOf(g.Nodes())
 	defer putPooledNodes(nodes)
@@ -290,7 +301,7 @@ func ApproxBetweenness(g *simple.DirectedGraph, sampleSize int, seed int64) Betw
 
 	if n == 0 {
 		result.Elapsed = time.Since(start)
-		return result
+		return result, nil
 	}
 
 	// For small graphs or when sample size >= node count, use exact algorithm
@@ -300,7 +311,7 @@ func ApproxBetweenness(g *simple.DirectedGraph, sampleSize int, seed int64) Betw
 		result.Mode = BetweennessExact
 		result.SampleSize = n
 		result.Elapsed = time.Since(start)
-		return result
+		return result, nil
 	}
 
 	idx := buildDenseIndex(nodes)
@@ -320,7 +331,12 @@ func ApproxBetweenness(g *simple.DirectedGraph, sampleSize int, seed int64) Betw
 	var wg sync.WaitGroup
 
 	// Limit concurrency to avoid excessive goroutines
-	sem := make(chan struct{}, runtime.NumCPU())
+	workers := runtime.NumCPU()
+	if workers > len(pivots) {
+		workers = len(pivots)
+	}
+	result.Workers = workers
+	sem := make(chan struct{}, workers)
 
 	for _, pivot := range pivots {
 		wg.Add(1)
@@ -328,6 +344,9 @@ func ApproxBetweenness(g *simple.DirectedGraph, sampleSize int, seed int64) Betw
 			defer wg.Done()
 			sem <- struct{}{} // Acquire token
 			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				return
+			}
 
 			buf := brandesPool.Get().(*brandesBuffers)
 			defer brandesPool.Put(buf)
@@ -344,6 +363,10 @@ func ApproxBetweenness(g *simple.DirectedGraph, sampleSize int, seed int64) Betw
 		}(pivot)
 	}
 	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		result.Elapsed = time.Since(start)
+		return result, err
+	}
 
 	// Scale up: BC_approx = BC_partial * (n / k)
 	// This extrapolates from the sample to the full graph
@@ -357,7 +380,7 @@ func ApproxBetweenness(g *simple.DirectedGraph, sampleSize int, seed int64) Betw
 	}
 	result.Scores = scores
 	result.Elapsed = time.Since(start)
-	return result
+	return result, nil
 }
 
 // sampleIndices returns a random sample of k indices from [0,n).
@@ -289,6 +289,9 @@ func (ca *CachedAnalyzer) AnalyzeAsync(ctx context.Context) *GraphStats {
 	// Store in cache when Phase 2 completes
 	go func() {
 		stats.WaitForPhase2()
+		if stats.Cancelled() {
+			return
+		}
 		ca.cache.SetByHash(fullHash, stats)
 	}()
 
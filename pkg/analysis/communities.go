@@ -0,0 +1,77 @@
+package analysis
+
+import (
+	"math/rand/v2"
+	"sort"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"gonum.org/v1/gonum/graph/community"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// ComputeCommunities groups issues into communities of densely linked beads
+// using Louvain modularity over every dependency type, treated as undirected.
+// Unlike the analysis graph, parent-child and related links count here: they
+// are what visually holds an epic's beads together.
+//
+// Community numbers start at 0 and are ordered by each community's smallest
+// issue ID, and the random source is fixed, so the same issues always get the
+// same assignment. Issues with no links to other known issues are alone in
+// their community.
+func ComputeCommunities(issues []model.Issue) map[string]int {
+	ids := make([]string, 0, len(issues))
+	seen := make(map[string]bool, len(issues))
+	for _, iss := range issues {
+		if !seen[iss.ID] {
+			seen[iss.ID] = true
+			ids = append(ids, iss.ID)
+		}
+	}
+	sort.Strings(ids)
+
+	g := simple.NewUndirectedGraph()
+	nodeOf := make(map[string]int64, len(ids))
+	for i, id := range ids {
+		nodeOf[id] = int64(i)
+		g.AddNode(simple.Node(i))
+	}
+	for _, iss := range issues {
+		for _, dep := range iss.Dependencies {
+			if dep == nil {
+				continue
+			}
+			v, ok := nodeOf[dep.DependsOnID]
+			if u := nodeOf[iss.ID]; ok && u != v {
+				g.SetEdge(g.NewEdge(simple.Node(u), simple.Node(v)))
+			}
+		}
+	}
+
+	out := make(map[string]int, len(ids))
+	if len(ids) == 0 {
+		return out
+	}
+
+	// Smallest member first within each community, then communities by it
+	groups := community.Modularize(g, 1, rand.NewPCG(1, 1)).Communities()
+	smallest := make([]int64, len(groups))
+	for i, members := range groups {
+		smallest[i] = members[0].ID()
+		for _, n := range members[1:] {
+			if n.ID() < smallest[i] {
+				smallest[i] = n.ID()
+			}
+		}
+	}
+	order := make([]int, len(groups))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return smallest[order[a]] < smallest[order[b]] })
+	for c, gi := range order {
+		for _, n := range groups[gi] {
+			out[ids[n.ID()]] = c
+		}
+	}
+	return out
+}
@@ -0,0 +1,43 @@
+package analysis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestComputeCommunities(t *testing.T) {
+	dep := func(from, to string, typ model.DependencyType) *model.Dependency {
+		return &model.Dependency{IssueID: from, DependsOnID: to, Type: typ}
+	}
+	// Two tight triangles joined by a single edge, plus a loner
+	issues := []model.Issue{
+		{ID: "a1", Dependencies: []*model.Dependency{dep("a1", "a2", model.DepBlocks), dep("a1", "a3", model.DepParentChild)}},
+		{ID: "a2", Dependencies: []*model.Dependency{dep("a2", "a3", model.DepRelated)}},
+		{ID: "a3"},
+		{ID: "b1", Dependencies: []*model.Dependency{dep("b1", "b2", model.DepBlocks), dep("b1", "b3", model.DepBlocks), dep("b1", "a3", model.DepBlocks)}},
+		{ID: "b2", Dependencies: []*model.Dependency{dep("b2", "b3", model.DepBlocks), dep("b2", "missing", model.DepBlocks)}},
+		{ID: "b3"},
+		{ID: "loner"},
+	}
+
+	got := ComputeCommunities(issues)
+	want := map[string]int{"a1": 0, "a2": 0, "a3": 0, "b1": 1, "b2": 1, "b3": 1, "loner": 2}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("ComputeCommunities = %v, want %v", got, want)
+	}
+
+	// Input order does not change the assignment
+	reversed := make([]model.Issue, len(issues))
+	for i, iss := range issues {
+		reversed[len(issues)-1-i] = iss
+	}
+	if again := ComputeCommunities(reversed); fmt.Sprint(again) != fmt.Sprint(got) {
+		t.Errorf("reversed input = %v, want %v", again, got)
+	}
+
+	if len(ComputeCommunities(nil)) != 0 {
+		t.Error("no issues should give no communities")
+	}
+}
@@ -246,6 +246,44 @@ type SkippedMetric struct {
 	Reason string
 }
 
+// Size tier names reported by SizeTier.
+const (
+	SizeTierSmall  = "small"
+	SizeTierMedium = "medium"
+	SizeTierLarge  = "large"
+	SizeTierXL     = "xl"
+)
+
+// SizeTier returns the ConfigForSize tier a graph of nodeCount issues falls into.
+func SizeTier(nodeCount int) string {
+	switch {
+	case nodeCount < 100:
+		return SizeTierSmall
+	case nodeCount < 500:
+		return SizeTierMedium
+	case nodeCount < 2000:
+		return SizeTierLarge
+	default:
+		return SizeTierXL
+	}
+}
+
+// EffectiveConfig describes the analysis settings that actually ran, so robot
+// consumers can judge result fidelity. The embedded AnalysisConfig keeps the
+// original field names for backwards compatibility.
+type EffectiveConfig struct {
+	AnalysisConfig
+	SizeTier                string          `json:"size_tier"`
+	NodeCount               int             `json:"node_count"`
+	EdgeCount               int             `json:"edge_count"`
+	AutoTuned               bool            `json:"auto_tuned"` // Config came from ConfigForSize
+	ComputedMetrics         []string        `json:"computed_metrics"`
+	SkippedMetrics          []SkippedMetric `json:"skipped_metrics,omitempty"`
+	BetweennessApproximated bool            `json:"betweenness_approximated"`
+	BetweennessSample       int             `json:"betweenness_sample,omitempty"`
+	Workers                 int             `json:"workers"` // Parallelism used by betweenness
+}
+
 const (
 	// EnvSkipPhase2 disables most Phase 2 metrics (centrality, cycles, critical path).
 	EnvSkipPhase2 = "BV_SKIP_PHASE2"
@@ -1,8 +1,12 @@
 package analysis
 
 import (
+	"fmt"
+	"runtime"
 	"testing"
 	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 )
 
 func TestConfigForSize_SmallGraph(t *testing.T) {
@@ -255,3 +259,81 @@ func TestDefaultConfig_EnvPhase2TimeoutInvalidIgnored(t *testing.T) {
 			EnvPhase2TimeoutSeconds, cfg.BetweennessTimeout, cfg.PageRankTimeout, cfg.HITSTimeout, cfg.CyclesTimeout)
 	}
 }
+
+func TestEffectiveConfig_SmallGraphExactBetweenness(t *testing.T) {
+	stats := NewAnalyzer(treeIssues(20)).Analyze()
+	eff := stats.EffectiveConfig()
+
+	if eff.SizeTier != SizeTierSmall {
+		t.Errorf("Expected size tier %q, got %q", SizeTierSmall, eff.SizeTier)
+	}
+	if !eff.AutoTuned {
+		t.Error("Expected auto-tuned config when no explicit config is set")
+	}
+	if eff.BetweennessApproximated || eff.BetweennessIsApproximate {
+		t.Error("Expected exact betweenness for small graph")
+	}
+	if eff.BetweennessMode != BetweennessExact {
+		t.Errorf("Expected exact betweenness mode, got %q", eff.BetweennessMode)
+	}
+	if eff.Workers != 1 {
+		t.Errorf("Expected 1 worker for exact betweenness, got %d", eff.Workers)
+	}
+	found := false
+	for _, name := range eff.ComputedMetrics {
+		if name == "Betweenness" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected Betweenness in computed metrics, got %v", eff.ComputedMetrics)
+	}
+}
+
+func TestEffectiveConfig_LargeGraphApproximatesBetweenness(t *testing.T) {
+	stats := NewAnalyzer(treeIssues(800)).Analyze()
+	eff := stats.EffectiveConfig()
+
+	if eff.SizeTier != SizeTierLarge {
+		t.Errorf("Expected size tier %q, got %q", SizeTierLarge, eff.SizeTier)
+	}
+	if eff.BetweennessMode != BetweennessApproximate {
+		t.Fatalf("Expected approximate betweenness mode, got %q", eff.BetweennessMode)
+	}
+	if !eff.BetweennessApproximated || !eff.BetweennessIsApproximate {
+		t.Error("Expected betweenness to be reported as approximated")
+	}
+	if eff.BetweennessSample <= 0 {
+		t.Errorf("Expected positive betweenness sample, got %d", eff.BetweennessSample)
+	}
+	if eff.Workers < 1 || eff.Workers > runtime.NumCPU() || eff.Workers > eff.BetweennessSample {
+		t.Errorf("Expected 1..min(NumCPU, sample) workers, got %d", eff.Workers)
+	}
+}
+
+func TestEffectiveConfig_ExplicitConfigNotAutoTuned(t *testing.T) {
+	analyzer := NewAnalyzer(treeIssues(20))
+	cfg := FullAnalysisConfig()
+	analyzer.SetConfig(&cfg)
+	stats := analyzer.Analyze()
+
+	if stats.EffectiveConfig().AutoTuned {
+		t.Error("Expected explicit full-analysis config to report auto_tuned=false")
+	}
+}
+
+// treeIssues builds n open issues where issue i depends on issue i/2 (a sparse binary tree).
+func treeIssues(n int) []model.Issue {
+	issues := make([]model.Issue, n)
+	for i := 0; i < n; i++ {
+		issues[i] = model.Issue{ID: fmt.Sprintf("T-%d", i), Status: model.StatusOpen}
+		if i > 0 {
+			issues[i].Dependencies = []*model.Dependency{{
+				IssueID:     issues[i].ID,
+				DependsOnID: fmt.Sprintf("T-%d", i/2),
+				Type:        model.DepBlocks,
+			}}
+		}
+	}
+	return issues
+}
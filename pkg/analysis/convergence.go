@@ -0,0 +1,267 @@
+package analysis
+
+import (
+	"math/bits"
+	"sort"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
+)
+
+// DefaultConvergenceMinFanIn is the fewest direct blockers a bead needs before
+// it is considered a convergence point.
+const DefaultConvergenceMinFanIn = 3
+
+// ConvergencePoint is a bead where independent dependency chains meet: it waits
+// on several blockers that come from distinct upstream components, so work
+// done in isolation has to integrate here.
+type ConvergencePoint struct {
+	ID              string     `json:"id"`
+	Title           string     `json:"title"`
+	FanIn           int        `json:"fan_in"`                     // Direct open blockers
+	UpstreamChains  int        `json:"upstream_chains"`            // Distinct upstream components among the blockers
+	Chains          [][]string `json:"chains"`                     // Blockers grouped by upstream component
+	UpstreamSize    int        `json:"upstream_size"`              // Open beads transitively upstream
+	SharedAncestors []string   `json:"shared_ancestors,omitempty"` // Reached through more than one blocker (diamonds)
+}
+
+// ConvergenceMaxNodes caps the open-bead count DetectConvergencePoints will
+// analyze. Ancestor sets are kept as bitsets, so memory grows with the square
+// of the graph; larger projects report no convergence points.
+const ConvergenceMaxNodes = 10000
+
+// DetectConvergencePoints finds open beads with at least minFanIn open
+// blockers (<= 0 uses DefaultConvergenceMinFanIn) whose blockers fall into two
+// or more upstream components. Two blockers share a component when they share
+// an ancestor, so a bead fed by one diamond-shaped chain is merely high fan-in,
+// not a convergence point. Results are sorted by chain count, then fan-in,
+// then ID.
+//
+// Upstream sets are computed once for the whole graph in dependency order
+// (over strongly connected components), so the cost is O(V·E/64) rather than
+// a traversal per blocker.
+func DetectConvergencePoints(issues []model.Issue, minFanIn int) []ConvergencePoint {
+	if minFanIn <= 0 {
+		minFanIn = DefaultConvergenceMinFanIn
+	}
+	points := []ConvergencePoint{}
+
+	ids := make([]string, 0, len(issues))
+	titles := make(map[string]string, len(issues))
+	index := make(map[string]int, len(issues))
+	for _, issue := range issues {
+		if issue.Status.IsClosed() || issue.Status.IsTombstone() {
+			continue
+		}
+		if _, dup := index[issue.ID]; dup {
+			continue
+		}
+		index[issue.ID] = -1
+		titles[issue.ID] = issue.Title
+		ids = append(ids, issue.ID)
+	}
+	if len(ids) > ConvergenceMaxNodes {
+		return points
+	}
+	sort.Strings(ids)
+	for i, id := range ids {
+		index[id] = i
+	}
+
+	// dependsOn[a] lists the open beads a is blocked by, sorted by ID
+	dependsOn := make([][]int, len(ids))
+	g := simple.NewDirectedGraph()
+	for i := range ids {
+		g.AddNode(simple.Node(i))
+	}
+	hasCandidate := false
+	for _, issue := range issues {
+		from, ok := index[issue.ID]
+		if !ok {
+			continue
+		}
+		for _, dep := range issue.Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() || dep.DependsOnID == issue.ID {
+				continue
+			}
+			to, ok := index[dep.DependsOnID]
+			if !ok || g.HasEdgeFromTo(int64(from), int64(to)) {
+				continue
+			}
+			g.SetEdge(g.NewEdge(simple.Node(from), simple.Node(to)))
+			dependsOn[from] = append(dependsOn[from], to)
+		}
+		sort.Ints(dependsOn[from])
+		if len(dependsOn[from]) >= minFanIn {
+			hasCandidate = true
+		}
+	}
+	if !hasCandidate {
+		return points
+	}
+
+	anc := upstreamSets(g, dependsOn)
+	for id, blockers := range dependsOn {
+		if len(blockers) < minFanIn {
+			continue
+		}
+		if p, ok := convergenceAt(id, blockers, anc, ids); ok {
+			p.Title = titles[ids[id]]
+			points = append(points, p)
+		}
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].UpstreamChains != points[j].UpstreamChains {
+			return points[i].UpstreamChains > points[j].UpstreamChains
+		}
+		if points[i].FanIn != points[j].FanIn {
+			return points[i].FanIn > points[j].FanIn
+		}
+		return points[i].ID < points[j].ID
+	})
+	return points
+}
+
+// bitset is a fixed-size set of dense node indices.
+type bitset []uint64
+
+func newBitset(n int) bitset {
+	return make(bitset, (n+63)/64)
+}
+
+func (b bitset) set(i int) {
+	b[i/64] |= 1 << (uint(i) % 64)
+}
+
+func (b bitset) clear(i int) {
+	b[i/64] &^= 1 << (uint(i) % 64)
+}
+
+func (b bitset) has(i int) bool {
+	return b[i/64]&(1<<(uint(i)%64)) != 0
+}
+
+func (b bitset) or(other bitset) {
+	for i := range b {
+		b[i] |= other[i]
+	}
+}
+
+func (b bitset) intersects(other bitset) bool {
+	for i := range b {
+		if b[i]&other[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (b bitset) count() int {
+	n := 0
+	for _, w := range b {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// upstreamSets returns, per node, the set holding the node and everything it
+// transitively depends on. Tarjan emits components blockers-first, so each
+// component's set is the union of its members and their (finished) blockers'
+// sets; members of a cycle share one set.
+func upstreamSets(g *simple.DirectedGraph, dependsOn [][]int) []bitset {
+	anc := make([]bitset, len(dependsOn))
+	for _, scc := range topo.TarjanSCC(g) {
+		set := newBitset(len(dependsOn))
+		for _, n := range scc {
+			set.set(int(n.ID()))
+		}
+		for _, n := range scc {
+			for _, dep := range dependsOn[n.ID()] {
+				if anc[dep] != nil {
+					set.or(anc[dep])
+				}
+			}
+		}
+		for _, n := range scc {
+			anc[n.ID()] = set
+		}
+	}
+	return anc
+}
+
+// convergenceAt groups the blockers of id by shared ancestry using a
+// union-find keyed by blocker index.
+func convergenceAt(id int, blockers []int, anc []bitset, ids []string) (ConvergencePoint, bool) {
+	parent := make([]int, len(blockers))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	// id itself never counts as upstream, even when a cycle leads back to it
+	upstream := make([]bitset, len(blockers))
+	for i, b := range blockers {
+		upstream[i] = append(bitset(nil), anc[b]...)
+		upstream[i].clear(id)
+	}
+	for i := range blockers {
+		for j := i + 1; j < len(blockers); j++ {
+			if find(i) != find(j) && upstream[i].intersects(upstream[j]) {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	groups := make(map[int][]string)
+	for i, b := range blockers {
+		root := find(i)
+		groups[root] = append(groups[root], ids[b])
+	}
+	if len(groups) < 2 {
+		return ConvergencePoint{}, false
+	}
+
+	chains := make([][]string, 0, len(groups))
+	for _, g := range groups {
+		chains = append(chains, g) // blockers are sorted, so each group is too
+	}
+	sort.Slice(chains, func(i, j int) bool {
+		if len(chains[i]) != len(chains[j]) {
+			return len(chains[i]) > len(chains[j])
+		}
+		return chains[i][0] < chains[j][0]
+	})
+
+	// once/twice accumulate beads reached through one or several blockers
+	once, twice := newBitset(len(ids)), newBitset(len(ids))
+	for _, u := range upstream {
+		for w := range once {
+			twice[w] |= once[w] & u[w]
+			once[w] |= u[w]
+		}
+	}
+	var sharedIDs []string
+	for i := range ids {
+		if twice.has(i) {
+			sharedIDs = append(sharedIDs, ids[i])
+		}
+	}
+
+	return ConvergencePoint{
+		ID:              ids[id],
+		FanIn:           len(blockers),
+		UpstreamChains:  len(chains),
+		Chains:          chains,
+		UpstreamSize:    once.count(),
+		SharedAncestors: sharedIDs,
+	}, true
+}
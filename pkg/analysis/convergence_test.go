@@ -0,0 +1,94 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func convergenceTestIssue(id string, status model.Status, dependsOn ...string) model.Issue {
+	issue := model.Issue{ID: id, Title: "Title " + id, Status: status}
+	for _, d := range dependsOn {
+		issue.Dependencies = append(issue.Dependencies, &model.Dependency{IssueID: id, DependsOnID: d, Type: model.DepBlocks})
+	}
+	return issue
+}
+
+func TestDetectConvergencePoints_FanInDiamond(t *testing.T) {
+	open := model.StatusOpen
+	issues := []model.Issue{
+		// x waits on a diamond (b and c both built on a) and an unrelated chain e <- d
+		convergenceTestIssue("a", open),
+		convergenceTestIssue("b", open, "a"),
+		convergenceTestIssue("c", open, "a"),
+		convergenceTestIssue("e", open),
+		convergenceTestIssue("d", open, "e"),
+		convergenceTestIssue("x", open, "b", "c", "d"),
+
+		// y has the same fan-in, but every blocker descends from s: one chain
+		convergenceTestIssue("s", open),
+		convergenceTestIssue("p", open, "s"),
+		convergenceTestIssue("q", open, "s"),
+		convergenceTestIssue("r", open, "s"),
+		convergenceTestIssue("y", open, "p", "q", "r"),
+
+		// z joins independent chains but only has two blockers (below threshold)
+		convergenceTestIssue("m", open),
+		convergenceTestIssue("n", open),
+		convergenceTestIssue("z", open, "m", "n"),
+	}
+
+	got := DetectConvergencePoints(issues, 0)
+	want := []ConvergencePoint{{
+		ID:              "x",
+		Title:           "Title x",
+		FanIn:           3,
+		UpstreamChains:  2,
+		Chains:          [][]string{{"b", "c"}, {"d"}},
+		UpstreamSize:    5,
+		SharedAncestors: []string{"a"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected convergence points:\n got %+v\nwant %+v", got, want)
+	}
+
+	if got := DetectConvergencePoints(issues, 2); len(got) != 2 || got[0].ID != "x" || got[1].ID != "z" {
+		t.Errorf("with threshold 2 expected x then z, got %+v", got)
+	}
+}
+
+func TestDetectConvergencePoints_IgnoresClosedBlockers(t *testing.T) {
+	issues := []model.Issue{
+		convergenceTestIssue("a", model.StatusOpen),
+		convergenceTestIssue("b", model.StatusOpen),
+		convergenceTestIssue("c", model.StatusClosed),
+		convergenceTestIssue("x", model.StatusOpen, "a", "b", "c"),
+	}
+	if got := DetectConvergencePoints(issues, 3); len(got) != 0 {
+		t.Errorf("closed blockers should not count toward fan-in, got %+v", got)
+	}
+}
+
+func TestDetectConvergencePoints_EmptyIsNotNil(t *testing.T) {
+	got := DetectConvergencePoints([]model.Issue{convergenceTestIssue("a", model.StatusOpen)}, 0)
+	if got == nil || len(got) != 0 {
+		t.Errorf("expected an empty, non-nil slice (JSON []), got %#v", got)
+	}
+}
+
+func TestDetectConvergencePoints_CycleUpstream(t *testing.T) {
+	open := model.StatusOpen
+	issues := []model.Issue{
+		// a <-> b is a cycle feeding x alongside two independent roots
+		convergenceTestIssue("a", open, "b"),
+		convergenceTestIssue("b", open, "a"),
+		convergenceTestIssue("c", open),
+		convergenceTestIssue("d", open),
+		convergenceTestIssue("x", open, "a", "c", "d"),
+	}
+	got := DetectConvergencePoints(issues, 0)
+	if len(got) != 1 || got[0].ID != "x" || got[0].UpstreamChains != 3 || got[0].UpstreamSize != 4 {
+		t.Fatalf("expected x with three chains over four upstream beads, got %+v", got)
+	}
+}
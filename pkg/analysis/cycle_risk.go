@@ -0,0 +1,183 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// DefaultMaxCycleRisks caps how many near-cycles are reported.
+const DefaultMaxCycleRisks = 50
+
+// CycleRisk is a dependency addition to avoid: adding "From depends on To"
+// would close a cycle, because To already (transitively) depends on From.
+type CycleRisk struct {
+	From         string   `json:"from"`          // Bead that would gain the dependency
+	To           string   `json:"to"`            // Bead it would depend on
+	ExistingPath []string `json:"existing_path"` // Current chain To -> ... -> From (each step depends on the next)
+	CycleLength  int      `json:"cycle_length"`  // Beads in the cycle the addition would create
+	Avoid        string   `json:"avoid"`         // The bd command that would introduce the cycle
+}
+
+// CycleRiskTimeout bounds DetectCycleRisks. Enumerating near-cycles visits
+// every reachable pair, so huge graphs stop early with TimedOut set.
+const CycleRiskTimeout = 500 * time.Millisecond
+
+// CycleRiskReport lists near-cycles (feedback candidates), shortest first.
+type CycleRiskReport struct {
+	Total     int         `json:"total"`               // All near-cycles found
+	Truncated bool        `json:"truncated"`           // Risks was capped
+	TimedOut  bool        `json:"timed_out,omitempty"` // Scan stopped at CycleRiskTimeout; Total is a lower bound
+	Risks     []CycleRisk `json:"risks"`
+}
+
+// DetectCycleRisks finds every non-edge (u, v) between open beads where v
+// already reaches u over blocking dependencies, so adding "u depends on v"
+// would create a cycle. Closed and tombstoned beads are skipped since new
+// dependencies are added between live work. Risks are sorted by cycle length
+// (tightest coupling first), then IDs, and capped at limit (<= 0 uses
+// DefaultMaxCycleRisks). The scan is bounded by CycleRiskTimeout.
+func DetectCycleRisks(issues []model.Issue, limit int) CycleRiskReport {
+	return detectCycleRisks(issues, limit, CycleRiskTimeout)
+}
+
+func detectCycleRisks(issues []model.Issue, limit int, timeout time.Duration) CycleRiskReport {
+	if limit <= 0 {
+		limit = DefaultMaxCycleRisks
+	}
+
+	ids := make([]string, 0, len(issues))
+	index := make(map[string]int, len(issues))
+	for _, issue := range issues {
+		if issue.Status.IsClosed() || issue.Status.IsTombstone() {
+			continue
+		}
+		if _, dup := index[issue.ID]; dup {
+			continue
+		}
+		index[issue.ID] = -1
+		ids = append(ids, issue.ID)
+	}
+	sort.Strings(ids)
+	for i, id := range ids {
+		index[id] = i
+	}
+
+	// dependsOn[a] lists the beads a is blocked by (dense indices, sorted by ID)
+	dependsOn := make([][]int, len(ids))
+	direct := make(map[[2]int]bool)
+	for _, issue := range issues {
+		from, ok := index[issue.ID]
+		if !ok {
+			continue
+		}
+		for _, dep := range issue.Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() || dep.DependsOnID == issue.ID {
+				continue
+			}
+			to, ok := index[dep.DependsOnID]
+			if !ok {
+				continue
+			}
+			key := [2]int{from, to}
+			if direct[key] {
+				continue
+			}
+			direct[key] = true
+			dependsOn[from] = append(dependsOn[from], to)
+		}
+	}
+	for i := range dependsOn {
+		sort.Ints(dependsOn[i])
+	}
+
+	// Only the best `limit` risks are kept (with paths); the rest are counted.
+	// Candidates are compacted whenever the buffer doubles.
+	less := func(a, b CycleRisk) bool {
+		if a.CycleLength != b.CycleLength {
+			return a.CycleLength < b.CycleLength
+		}
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	}
+	compact := func(risks []CycleRisk) []CycleRisk {
+		sort.Slice(risks, func(i, j int) bool { return less(risks[i], risks[j]) })
+		if len(risks) > limit {
+			risks = risks[:limit]
+		}
+		return risks
+	}
+
+	// BFS buffers are reused across sources; seen[] holds the source that last
+	// visited a node so nothing needs clearing between runs.
+	parent := make([]int, len(ids))
+	dist := make([]int, len(ids))
+	seen := make([]int, len(ids))
+	for i := range seen {
+		seen[i] = -1
+	}
+	queue := make([]int, 0, len(ids))
+
+	deadline := time.Now().Add(timeout)
+	var risks []CycleRisk
+	var worst *CycleRisk
+	total := 0
+	timedOut := false
+	for v := range ids {
+		if timeout > 0 && v%64 == 0 && time.Now().After(deadline) {
+			timedOut = true
+			break
+		}
+		// BFS over v's transitive blockers; parent links rebuild shortest paths
+		seen[v], parent[v], dist[v] = v, -1, 1
+		queue = append(queue[:0], v)
+		for head := 0; head < len(queue); head++ {
+			cur := queue[head]
+			for _, u := range dependsOn[cur] {
+				if seen[u] == v {
+					continue
+				}
+				seen[u], parent[u], dist[u] = v, cur, dist[cur]+1
+				queue = append(queue, u)
+
+				if deps := dependsOn[u]; len(deps) > 0 {
+					if i := sort.SearchInts(deps, v); i < len(deps) && deps[i] == v {
+						continue // Already a cycle, reported by cycle detection
+					}
+				}
+				total++
+				risk := CycleRisk{From: ids[u], To: ids[v], CycleLength: dist[u]}
+				if worst != nil && !less(risk, *worst) {
+					continue
+				}
+				// Walk parents u..v, then flip to read v -> ... -> u
+				path := []string{ids[u]}
+				for p := parent[u]; p != -1; p = parent[p] {
+					path = append(path, ids[p])
+				}
+				for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+				risk.ExistingPath = path
+				risk.Avoid = fmt.Sprintf("bd dep add %s %s", ids[u], ids[v])
+				risks = append(risks, risk)
+				if len(risks) >= 2*limit {
+					risks = compact(risks)
+					w := risks[len(risks)-1]
+					worst = &w
+				}
+			}
+		}
+	}
+	risks = compact(risks)
+
+	report := CycleRiskReport{Total: total, Truncated: timedOut || total > len(risks), TimedOut: timedOut, Risks: risks}
+	if report.Risks == nil {
+		report.Risks = []CycleRisk{}
+	}
+	return report
+}
@@ -0,0 +1,80 @@
+package analysis
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestDetectCycleRisks_Chain(t *testing.T) {
+	// c depends on b, b depends on a: adding "a depends on c" (or on b) would
+	// close a loop.
+	issues := []model.Issue{
+		{ID: "a", Status: model.StatusOpen},
+		{ID: "b", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "b", DependsOnID: "a", Type: model.DepBlocks}}},
+		{ID: "c", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "c", DependsOnID: "b", Type: model.DepBlocks}}},
+	}
+
+	report := DetectCycleRisks(issues, 0)
+	if report.Total != 3 || report.Truncated {
+		t.Fatalf("expected 3 risks, got %+v", report)
+	}
+	want := []CycleRisk{
+		{From: "a", To: "b", ExistingPath: []string{"b", "a"}, CycleLength: 2, Avoid: "bd dep add a b"},
+		{From: "b", To: "c", ExistingPath: []string{"c", "b"}, CycleLength: 2, Avoid: "bd dep add b c"},
+		{From: "a", To: "c", ExistingPath: []string{"c", "b", "a"}, CycleLength: 3, Avoid: "bd dep add a c"},
+	}
+	if !reflect.DeepEqual(report.Risks, want) {
+		t.Errorf("unexpected risks:\n got %+v\nwant %+v", report.Risks, want)
+	}
+
+	capped := DetectCycleRisks(issues, 1)
+	if capped.Total != 3 || !capped.Truncated || len(capped.Risks) != 1 || capped.Risks[0].From != "a" || capped.Risks[0].To != "b" {
+		t.Errorf("expected the shortest risk to survive the cap, got %+v", capped)
+	}
+}
+
+func TestDetectCycleRisks_SkipsClosedAndExistingCycles(t *testing.T) {
+	issues := []model.Issue{
+		// x <-> y is already a cycle: nothing to warn about
+		{ID: "x", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "x", DependsOnID: "y", Type: model.DepBlocks}}},
+		{ID: "y", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "y", DependsOnID: "x", Type: model.DepBlocks}}},
+		// Closed blockers and non-blocking links don't count
+		{ID: "done", Status: model.StatusClosed},
+		{ID: "p", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{IssueID: "p", DependsOnID: "done", Type: model.DepBlocks},
+			{IssueID: "p", DependsOnID: "x", Type: model.DepRelated},
+		}},
+	}
+
+	report := DetectCycleRisks(issues, 0)
+	if report.Total != 0 || len(report.Risks) != 0 {
+		t.Errorf("expected no cycle risks, got %+v", report)
+	}
+}
+
+func TestDetectCycleRisks_StopsAtTimeout(t *testing.T) {
+	issues := make([]model.Issue, 200)
+	for i := range issues {
+		issues[i] = model.Issue{ID: fmt.Sprintf("n%03d", i), Status: model.StatusOpen}
+		if i > 0 {
+			issues[i].Dependencies = []*model.Dependency{{IssueID: issues[i].ID, DependsOnID: issues[i-1].ID, Type: model.DepBlocks}}
+		}
+	}
+
+	full := detectCycleRisks(issues, 5, 0)
+	if full.TimedOut || full.Total != 200*199/2 {
+		t.Fatalf("expected every reachable pair without a budget, got total=%d timed_out=%v", full.Total, full.TimedOut)
+	}
+
+	partial := detectCycleRisks(issues, 5, time.Nanosecond)
+	if !partial.TimedOut || !partial.Truncated {
+		t.Errorf("expected an exhausted budget to be reported, got %+v", partial)
+	}
+	if partial.Total >= full.Total {
+		t.Errorf("expected a partial scan, got total=%d", partial.Total)
+	}
+}
@@ -0,0 +1,92 @@
+package analysis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestDegreeStats(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "a", Status: model.StatusOpen},
+		{ID: "b", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "b", DependsOnID: "a", Type: model.DepBlocks}}},
+		{ID: "c", Status: model.StatusInProgress, Dependencies: []*model.Dependency{{IssueID: "c", DependsOnID: "done", Type: model.DepBlocks}}},
+		{ID: "d", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "d", DependsOnID: "missing", Type: model.DepBlocks}}},
+		{ID: "done", Status: model.StatusClosed},
+	}
+
+	an := NewAnalyzer(issues)
+	ds := an.DegreeStats()
+
+	if ds.NodeCount != 5 || ds.EdgeCount != 2 {
+		t.Errorf("nodes/edges = %d/%d, want 5/2", ds.NodeCount, ds.EdgeCount)
+	}
+	// a, c, d are actionable: c's blocker is closed and d's is missing
+	if ds.Open != 4 || ds.Actionable != 3 || ds.Blocked != 1 {
+		t.Errorf("open/actionable/blocked = %d/%d/%d, want 4/3/1", ds.Open, ds.Actionable, ds.Blocked)
+	}
+	if ds.Actionable != len(an.GetActionableIssues()) {
+		t.Errorf("Actionable = %d, GetActionableIssues = %d", ds.Actionable, len(an.GetActionableIssues()))
+	}
+
+	stats := an.Analyze()
+	for id, in := range stats.InDegree {
+		if ds.InDegree[id] != in || ds.OutDegree[id] != stats.OutDegree[id] {
+			t.Errorf("%s: degree in/out = %d/%d, Analyze = %d/%d", id, ds.InDegree[id], ds.OutDegree[id], in, stats.OutDegree[id])
+		}
+	}
+}
+
+// degreeBenchIssues builds a sparse graph where each bead depends on up to
+// three earlier ones
+func degreeBenchIssues(n int) []model.Issue {
+	issues := make([]model.Issue, n)
+	for i := range issues {
+		id := fmt.Sprintf("bench-%d", i)
+		issues[i] = model.Issue{ID: id, Title: id, Status: model.StatusOpen}
+		for _, step := range []int{1, 7, 31} {
+			if i >= step {
+				issues[i].Dependencies = append(issues[i].Dependencies, &model.Dependency{
+					IssueID: id, DependsOnID: fmt.Sprintf("bench-%d", i-step), Type: model.DepBlocks,
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// resetIncrementalGraphStatsCache stops repeated Analyze calls on the same
+// graph from returning cached stats
+func resetIncrementalGraphStatsCache() {
+	incrementalGraphStatsCacheMu.Lock()
+	defer incrementalGraphStatsCacheMu.Unlock()
+	clear(incrementalGraphStatsCache)
+}
+
+// BenchmarkDegreeStats_Sparse2000 and BenchmarkDegreeStatsFullAnalysis_Sparse2000
+// compare the degree-only path with full analysis on the same graph:
+//
+//	go test ./pkg/analysis -run '^$' -bench 'DegreeStats'
+func BenchmarkDegreeStats_Sparse2000(b *testing.B) {
+	issues := degreeBenchIssues(2000)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = NewAnalyzer(issues).DegreeStats()
+	}
+}
+
+func BenchmarkDegreeStatsFullAnalysis_Sparse2000(b *testing.B) {
+	issues := degreeBenchIssues(2000)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		resetIncrementalGraphStatsCache()
+		b.StartTimer()
+		_ = NewAnalyzer(issues).Analyze()
+	}
+}
@@ -33,6 +33,10 @@ type DependencySuggestionConfig struct {
 	// IgnoreExistingDeps skips pairs that already have dependencies
 	// Default: true
 	IgnoreExistingDeps bool
+
+	// StopWords are ignored when extracting keywords, e.g. StopWordsFor("fr")
+	// Default: nil (English stop words)
+	StopWords map[string]bool
 }
 
 // DefaultDependencySuggestionConfig returns sensible defaults
@@ -76,7 +80,7 @@ func DetectMissingDependencies(issues []model.Issue, config DependencySuggestion
 
 	for i := range issues {
 		// Keywords
-		kws := extractKeywords(issues[i].Title, issues[i].Description)
+		kws := extractKeywordsWithStopWords(issues[i].Title, issues[i].Description, config.StopWords)
 		keywords[i] = kws
 		
 		// Only index if we have enough keywords to possibly match
@@ -269,7 +273,13 @@ func findSharedKeys(m1, m2 map[string]bool) []string {
 // Uses sort.Slice for O(n log n) performance instead of bubble sort O(n²)
 func sortMatchesByConfidence(matches []DependencyMatch) {
 	sort.Slice(matches, func(i, j int) bool {
-		return matches[i].Confidence > matches[j].Confidence
+		if matches[i].Confidence != matches[j].Confidence {
+			return matches[i].Confidence > matches[j].Confidence
+		}
+		if matches[i].From != matches[j].From {
+			return matches[i].From < matches[j].From
+		}
+		return matches[i].To < matches[j].To
 	})
 }
 
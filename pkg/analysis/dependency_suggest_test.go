@@ -497,3 +497,20 @@ func BenchmarkDetectMissingDependencies_Large(b *testing.B) {
 		DetectMissingDependencies(issues, config)
 	}
 }
+
+func TestDetectMissingDependencies_UsesConfiguredStopWords(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "A", Title: "Corriger sans avec pour dans", Status: model.StatusOpen},
+		{ID: "B", Title: "Ajouter sans avec pour dans", Status: model.StatusOpen},
+	}
+	config := DefaultDependencySuggestionConfig()
+	config.MinConfidence = 0
+
+	if got := DetectMissingDependencies(issues, config); len(got) == 0 {
+		t.Fatal("expected the shared French function words to match with English stop words only")
+	}
+	config.StopWords = StopWordsFor("fr")
+	if got := DetectMissingDependencies(issues, config); len(got) != 0 {
+		t.Errorf("expected no suggestion once French stop words are ignored, got %+v", got)
+	}
+}
@@ -2,17 +2,15 @@ package analysis
 
 import (
 	"fmt"
-	"regexp"
 	"sort"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 )
 
-// Package-level compiled regex for performance (avoids recompilation on each call)
-var nonWordRegex = regexp.MustCompile(`[^\w\s]`)
-
 // Package-level stop words map for performance (avoids recreation on each call)
 var stopWords = map[string]bool{
 	"the": true, "and": true, "for": true, "with": true,
@@ -32,6 +30,46 @@ var stopWords = map[string]bool{
 	"very": true, "most": true, "make": true, "use": true,
 }
 
+// languageStopWords holds additional stop words for non-English projects,
+// keyed by ISO 639-1 code. Only words of three or more letters are listed,
+// since shorter words are dropped anyway.
+var languageStopWords = map[string][]string{
+	"de": {"der", "die", "das", "und", "oder", "aber", "nicht", "mit", "für", "von", "auf", "aus", "bei", "nach", "über", "unter", "ist", "sind", "war", "wird", "werden", "ein", "eine", "einen", "dem", "den", "des", "sich", "auch", "noch", "wenn", "dass", "wie", "zum", "zur"},
+	"es": {"los", "las", "una", "uno", "unos", "del", "para", "por", "con", "sin", "que", "como", "pero", "más", "está", "esta", "este", "son", "ser", "hay", "sobre", "entre", "cuando", "donde", "también", "muy"},
+	"fr": {"les", "des", "une", "pour", "par", "avec", "sans", "dans", "sur", "sous", "que", "qui", "est", "sont", "pas", "plus", "mais", "comme", "aux", "ces", "cette", "son", "ses", "leur", "leurs", "être", "avoir", "très", "aussi", "quand"},
+	"it": {"gli", "una", "uno", "del", "della", "delle", "dei", "per", "con", "che", "non", "sono", "come", "più", "anche", "quando", "dove", "questo", "questa", "nel", "nella", "sul", "sulla"},
+	"pt": {"uma", "umas", "uns", "dos", "das", "para", "por", "com", "sem", "que", "não", "como", "mas", "mais", "está", "são", "ser", "sobre", "entre", "quando", "onde", "também", "muito", "pelo", "pela"},
+}
+
+// StopWordsFor returns the stop word set for an ISO 639-1 language code: the
+// English list plus that language's words, since mixed-language trackers
+// commonly use English technical terms. Unknown or empty codes yield the
+// English list.
+func StopWordsFor(lang string) map[string]bool {
+	extra := languageStopWords[strings.ToLower(strings.TrimSpace(lang))]
+	if len(extra) == 0 {
+		return stopWords
+	}
+	set := make(map[string]bool, len(stopWords)+len(extra))
+	for w := range stopWords {
+		set[w] = true
+	}
+	for _, w := range extra {
+		set[w] = true
+	}
+	return set
+}
+
+// StopWordLanguages returns the language codes StopWordsFor knows, sorted.
+func StopWordLanguages() []string {
+	langs := make([]string, 0, len(languageStopWords))
+	for lang := range languageStopWords {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
 // DuplicateConfig configures duplicate detection behavior
 type DuplicateConfig struct {
 	// JaccardThreshold is the minimum similarity score (0.0-1.0)
@@ -49,6 +87,10 @@ type DuplicateConfig struct {
 	// MaxSuggestions limits the number of duplicate suggestions
 	// Default: 20
 	MaxSuggestions int
+
+	// StopWords are ignored when extracting keywords, e.g. StopWordsFor("fr")
+	// Default: nil (English stop words)
+	StopWords map[string]bool
 }
 
 // DefaultDuplicateConfig returns sensible defaults
@@ -84,7 +126,7 @@ func DetectDuplicates(issues []model.Issue, config DuplicateConfig) []Suggestion
 	index := make(map[string][]int)
 
 	for i := range issues {
-		kws := extractKeywords(issues[i].Title, issues[i].Description)
+		kws := extractKeywordsWithStopWords(issues[i].Title, issues[i].Description, config.StopWords)
 		keywords[i] = kws
 
 		// Only index if enough keywords to matter
@@ -213,27 +255,36 @@ func intersectKeywords(a, b []string) []string {
 	return common
 }
 
-// extractKeywords extracts meaningful keywords from text
+// extractKeywords extracts meaningful keywords from text using the English stop words
 func extractKeywords(title, description string) []string {
-	text := strings.ToLower(title + " " + description)
+	return extractKeywordsWithStopWords(title, description, nil)
+}
 
-	// Remove common markdown/code artifacts (uses package-level compiled regex)
-	text = nonWordRegex.ReplaceAllString(text, " ")
+// extractKeywordsWithStopWords splits text on Unicode word boundaries (letters,
+// digits and underscores form words), folds case rune by rune, and drops
+// words shorter than three characters or present in stop (nil uses English).
+func extractKeywordsWithStopWords(title, description string, stop map[string]bool) []string {
+	if stop == nil {
+		stop = stopWords
+	}
+	text := strings.ToLower(title + " " + description)
 
-	words := strings.Fields(text)
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r) && r != '_'
+	})
 
 	// Filter out stop words and short words
 	keywords := make([]string, 0, len(words)/2)
 	seen := make(map[string]bool)
 
 	for _, word := range words {
-		// Skip short words
-		if len(word) < 3 {
+		// Skip short words (counted in runes so accented words aren't favoured)
+		if utf8.RuneCountInString(word) < 3 {
 			continue
 		}
 
-		// Skip common stop words (uses package-level map)
-		if stopWords[word] {
+		// Skip stop words
+		if stop[word] {
 			continue
 		}
 
@@ -254,7 +305,13 @@ func extractKeywords(title, description string) []string {
 // Uses sort.Slice for O(n log n) performance instead of bubble sort O(n²)
 func sortPairsBySimilarity(pairs []DuplicatePair) {
 	sort.Slice(pairs, func(i, j int) bool {
-		return pairs[i].Similarity > pairs[j].Similarity
+		if pairs[i].Similarity != pairs[j].Similarity {
+			return pairs[i].Similarity > pairs[j].Similarity
+		}
+		if pairs[i].Issue1 != pairs[j].Issue1 {
+			return pairs[i].Issue1 < pairs[j].Issue1
+		}
+		return pairs[i].Issue2 < pairs[j].Issue2
 	})
 }
 
@@ -1,6 +1,7 @@
 package analysis
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
@@ -622,3 +623,44 @@ func TestDetectDuplicates_LargeIssueSet(t *testing.T) {
 		t.Error("Should find at least one duplicate pair")
 	}
 }
+
+func TestExtractKeywords_UnicodeAndLanguageStopWords(t *testing.T) {
+	keywords := extractKeywordsWithStopWords("Réparer l'éclairage du Café", "Problème avec les données pour ÉTÉ", StopWordsFor("fr"))
+
+	want := []string{"réparer", "éclairage", "café", "problème", "données", "été"}
+	if !reflect.DeepEqual(keywords, want) {
+		t.Errorf("extractKeywordsWithStopWords() = %v, want %v", keywords, want)
+	}
+
+	// Without the French list, "avec" and "pour" survive as keywords.
+	english := extractKeywords("Réparer l'éclairage du Café", "Problème avec les données pour ÉTÉ")
+	if !containsString(english, "avec") || !containsString(english, "pour") {
+		t.Errorf("expected French function words with English stop words only, got %v", english)
+	}
+	if !containsString(english, "éclairage") {
+		t.Errorf("accented words should not be mangled, got %v", english)
+	}
+}
+
+func TestDetectDuplicates_NonEnglishStopWords(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "A", Title: "Fehler beim Speichern der Größe", Description: "Die Größe wird nicht gespeichert und das Formular stürzt ab", Status: model.StatusOpen},
+		{ID: "B", Title: "Speichern der Größe schlägt fehl", Description: "Das Formular stürzt ab, wenn die Größe gespeichert wird", Status: model.StatusOpen},
+	}
+	config := DefaultDuplicateConfig()
+	config.JaccardThreshold = 0.5
+	config.StopWords = StopWordsFor("de")
+
+	if got := DetectDuplicates(issues, config); len(got) != 1 {
+		t.Fatalf("expected one duplicate suggestion with German stop words, got %d", len(got))
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package analysis_test
+
+import (
+	"fmt"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// ExampleAnalyzeIssues shows how to embed bead analysis in another Go program.
+func ExampleAnalyzeIssues() {
+	issues := []model.Issue{
+		{ID: "api", Title: "Design API", Status: model.StatusOpen},
+		{ID: "impl", Title: "Implement API", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{IssueID: "impl", DependsOnID: "api", Type: model.DepBlocks},
+		}},
+		{ID: "docs", Title: "Document API", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{IssueID: "docs", DependsOnID: "impl", Type: model.DepBlocks},
+		}},
+	}
+
+	// A zero-value config picks size-appropriate algorithms automatically.
+	stats, insights, err := analysis.AnalyzeIssues(issues, analysis.AnalysisConfig{})
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+
+	fmt.Println("nodes:", stats.NodeCount, "edges:", stats.EdgeCount)
+	fmt.Println("phase 2 ready:", stats.IsPhase2Ready())
+	fmt.Println("order:", stats.TopologicalOrder)
+	fmt.Println("cycles:", len(insights.Cycles))
+	// Output:
+	// nodes: 3 edges: 2
+	// phase 2 ready: true
+	// order: [api impl docs]
+	// cycles: 0
+}
@@ -50,6 +50,10 @@ type StartupProfile struct {
 	Slack         time.Duration `json:"slack"`        // bv-85
 	Phase2        time.Duration `json:"phase2_total"`
 
+	// Set when betweenness was sampled rather than computed exactly
+	BetweennessApprox bool `json:"betweenness_approximate"`
+	BetweennessSample int  `json:"betweenness_sample,omitempty"`
+
 	// Configuration used
 	Config AnalysisConfig `json:"config"`
 
@@ -101,6 +105,9 @@ type GraphStats struct {
 
 	// Phase 2 status flags for robot visibility
 	status MetricStatus
+
+	// cancelled is set when Phase 2 stopped early because its context was done
+	cancelled bool
 }
 
 // metricStatus captures per-metric computation outcome for transparency.
@@ -118,10 +125,11 @@ type MetricStatus struct {
 
 // statusEntry records computation state for a single metric.
 type statusEntry struct {
-	State   string        `json:"state"`            // computed|approx|timeout|skipped
-	Reason  string        `json:"reason,omitempty"` // explanation when skipped/timeout/approx
-	Sample  int           `json:"sample,omitempty"` // sample size when approximate
-	Elapsed time.Duration `json:"-"`                // serialized in ms via MarshalJSON
+	State   string        `json:"state"`             // computed|approx|timeout|skipped
+	Reason  string        `json:"reason,omitempty"`  // explanation when skipped/timeout/approx
+	Sample  int           `json:"sample,omitempty"`  // sample size when approximate
+	Workers int           `json:"workers,omitempty"` // goroutines used (betweenness only)
+	Elapsed time.Duration `json:"-"`                 // serialized in ms via MarshalJSON
 }
 
 // MarshalJSON encodes Elapsed as milliseconds to match the JSON field name.
@@ -130,12 +138,14 @@ func (s statusEntry) MarshalJSON() ([]byte, error) {
 		State   string  `json:"state"`
 		Reason  string  `json:"reason,omitempty"`
 		Sample  int     `json:"sample,omitempty"`
+		Workers int     `json:"workers,omitempty"`
 		Elapsed float64 `json:"ms,omitempty"`
 	}
 	payload := out{
-		State:  s.State,
-		Reason: s.Reason,
-		Sample: s.Sample,
+		State:   s.State,
+		Reason:  s.Reason,
+		Sample:  s.Sample,
+		Workers: s.Workers,
 	}
 	if s.Elapsed != 0 {
 		payload.Elapsed = float64(s.Elapsed) / float64(time.Millisecond)
@@ -150,6 +160,14 @@ func (s *GraphStats) IsPhase2Ready() bool {
 	return s.phase2Ready
 }
 
+// Cancelled reports whether Phase 2 was cut short by context cancellation.
+// Metrics that did not finish have status "cancelled" and empty results.
+func (s *GraphStats) Cancelled() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cancelled
+}
+
 // Status returns a copy of metric status flags.
 func (s *GraphStats) Status() MetricStatus {
 	s.mu.RLock()
@@ -157,6 +175,57 @@ func (s *GraphStats) Status() MetricStatus {
 	return s.status
 }
 
+// EffectiveConfig reports the configuration that produced these stats,
+// including size-based auto-tuning and whether betweenness was approximated.
+// Call after Phase 2 completes for accurate approximation details.
+func (s *GraphStats) EffectiveConfig() EffectiveConfig {
+	s.mu.RLock()
+	cfg := s.Config
+	status := s.status
+	nodeCount, edgeCount := s.NodeCount, s.EdgeCount
+	s.mu.RUnlock()
+
+	autoTuned := cfg == ConfigForSize(nodeCount, edgeCount)
+
+	approx := cfg.ComputeBetweenness &&
+		(status.Betweenness.Sample > 0 || status.Betweenness.Reason == "approximate")
+	cfg.BetweennessIsApproximate = approx
+
+	eff := EffectiveConfig{
+		AnalysisConfig:          cfg,
+		SizeTier:                SizeTier(nodeCount),
+		NodeCount:               nodeCount,
+		EdgeCount:               edgeCount,
+		AutoTuned:               autoTuned,
+		ComputedMetrics:         []string{},
+		SkippedMetrics:          cfg.SkippedMetrics(),
+		BetweennessApproximated: approx,
+		BetweennessSample:       status.Betweenness.Sample,
+		Workers:                 status.Betweenness.Workers,
+	}
+
+	metrics := []struct {
+		name  string
+		entry statusEntry
+	}{
+		{"PageRank", status.PageRank},
+		{"Betweenness", status.Betweenness},
+		{"Eigenvector", status.Eigenvector},
+		{"HITS", status.HITS},
+		{"Critical", status.Critical},
+		{"Cycles", status.Cycles},
+		{"KCore", status.KCore},
+		{"Articulation", status.Articulation},
+		{"Slack", status.Slack},
+	}
+	for _, m := range metrics {
+		if m.entry.State == "computed" {
+			eff.ComputedMetrics = append(eff.ComputedMetrics, m.name)
+		}
+	}
+	return eff
+}
+
 // stateFromTiming converts config flags/timeouts to a user-facing state string.
 func stateFromTiming(enabled bool, timedOut bool) string {
 	switch {
@@ -169,6 +238,15 @@ func stateFromTiming(enabled bool, timedOut bool) string {
 	}
 }
 
+// stateWithCancel is stateFromTiming for a run that may have been cancelled:
+// enabled metrics that never finished report "cancelled".
+func stateWithCancel(enabled, timedOut, finished bool) string {
+	if enabled && !timedOut && !finished {
+		return "cancelled"
+	}
+	return stateFromTiming(enabled, timedOut)
+}
+
 func betweennessReason(cfg AnalysisConfig, isApprox bool) string {
 	if cfg.BetweennessSkipReason != "" {
 		return cfg.BetweennessSkipReason
@@ -882,6 +960,7 @@ const (
 type incrementalGraphStatsCacheEntry struct {
 	stats      *GraphStats
 	insertedAt time.Time
+	done       <-chan struct{} // Done channel of the context computing stats
 }
 
 var (
@@ -905,10 +984,28 @@ func getIncrementalGraphStatsCache(key string) (*GraphStats, bool) {
 		delete(incrementalGraphStatsCache, key)
 		return nil, false
 	}
+	// An entry whose context has been cancelled either already holds partial
+	// results or is about to; either way it must not be reused.
+	if entry.stats.Cancelled() || (entryContextDone(entry) && !entry.stats.IsPhase2Ready()) {
+		delete(incrementalGraphStatsCache, key)
+		return nil, false
+	}
 	return entry.stats, true
 }
 
-func putIncrementalGraphStatsCache(key string, stats *GraphStats) {
+func entryContextDone(entry incrementalGraphStatsCacheEntry) bool {
+	if entry.done == nil {
+		return false
+	}
+	select {
+	case <-entry.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func putIncrementalGraphStatsCache(ctx context.Context, key string, stats *GraphStats) {
 	if key == "" || stats == nil {
 		return
 	}
@@ -920,10 +1017,26 @@ func putIncrementalGraphStatsCache(key string, stats *GraphStats) {
 	incrementalGraphStatsCache[key] = incrementalGraphStatsCacheEntry{
 		stats:      stats,
 		insertedAt: now,
+		done:       ctx.Done(),
 	}
 	pruneIncrementalGraphStatsCacheLocked(now)
 }
 
+// evictIncrementalGraphStatsCache drops key only if it still maps to stats, so a
+// newer analysis stored under the same key is left alone.
+func evictIncrementalGraphStatsCache(key string, stats *GraphStats) {
+	if key == "" {
+		return
+	}
+
+	incrementalGraphStatsCacheMu.Lock()
+	defer incrementalGraphStatsCacheMu.Unlock()
+
+	if entry, ok := incrementalGraphStatsCache[key]; ok && entry.stats == stats {
+		delete(incrementalGraphStatsCache, key)
+	}
+}
+
 func pruneIncrementalGraphStatsCacheLocked(now time.Time) {
 	for k, entry := range incrementalGraphStatsCache {
 		if entry.stats == nil || now.Sub(entry.insertedAt) > incrementalGraphStatsCacheTTL {
@@ -1063,7 +1176,8 @@ func NewAnalyzer(issues []model.Issue) *Analyzer {
 			}
 
 			v, exists := idToNode[dep.DependsOnID]
-			if exists {
+			// Self-dependencies can't be represented in a simple graph; --robot-lint reports them.
+			if exists && v != u {
 				// Issue (u) depends on v → edge u -> v
 				// Optimization: Use simple.Node directly to avoid internal map lookups in g.Node()
 				g.SetEdge(g.NewEdge(simple.Node(u), simple.Node(v)))
@@ -1175,15 +1289,34 @@ func (a *Analyzer) AnalyzeAsyncWithConfig(ctx context.Context, config AnalysisCo
 	a.computePhase1(stats)
 
 	if incCacheKey != "" {
-		putIncrementalGraphStatsCache(incCacheKey, stats)
+		putIncrementalGraphStatsCache(ctx, incCacheKey, stats)
 	}
 
 	// Phase 2: Expensive metrics in background goroutine
-	go a.computePhase2(ctx, stats, config, robotCacheKey, dataHash, configHash)
+	go a.computePhase2(ctx, stats, config, incCacheKey, robotCacheKey, dataHash, configHash)
 
 	return stats
 }
 
+// AnalyzeWithContext performs synchronous graph analysis that can be aborted.
+// Cancellation is checked between Phase 2 computations and inside the
+// iterative PageRank and approximate betweenness loops. When ctx is done
+// before Phase 2 finishes, the partial stats are returned (Cancelled() is
+// true; unfinished metrics have status "cancelled") together with an error
+// wrapping ctx.Err(). Phase 1 metrics are always complete.
+func (a *Analyzer) AnalyzeWithContext(ctx context.Context, config AnalysisConfig) (*GraphStats, error) {
+	stats := a.AnalyzeAsyncWithConfig(ctx, config)
+	stats.WaitForPhase2()
+	if stats.Cancelled() {
+		err := ctx.Err()
+		if err == nil {
+			err = context.Canceled
+		}
+		return stats, fmt.Errorf("analysis cancelled: %w", err)
+	}
+	return stats, nil
+}
+
 // Analyze performs synchronous graph analysis (for backward compatibility).
 // Blocks until all metrics are computed.
 func (a *Analyzer) Analyze() GraphStats {
@@ -1256,6 +1389,41 @@ func (a *Analyzer) AnalyzeWithConfig(config AnalysisConfig) GraphStats {
 	}
 }
 
+// LibraryInsightsLimit is the number of items per insight list returned by
+// AnalyzeIssues (matches --robot-insights).
+const LibraryInsightsLimit = 50
+
+// AnalyzeIssues is the library entrypoint for embedding bead analysis in other
+// Go programs. It builds the dependency graph, runs both analysis phases with
+// cfg, and derives insights. A zero-value cfg selects ConfigForSize for the
+// graph, like the CLI does.
+//
+// The returned GraphStats has Phase 2 complete (IsPhase2Ready is true), so its
+// accessor methods never block and are safe for concurrent use. Its exported
+// Phase 1 maps (OutDegree, InDegree, TopologicalOrder) may be shared with an
+// internal cache and must be treated as read-only.
+func AnalyzeIssues(issues []model.Issue, cfg AnalysisConfig) (*GraphStats, Insights, error) {
+	for i := range issues {
+		if issues[i].ID == "" {
+			return nil, Insights{}, fmt.Errorf("analyze issues: issue at index %d has empty ID", i)
+		}
+	}
+
+	analyzer := NewAnalyzer(issues)
+	if cfg == (AnalysisConfig{}) {
+		cfg = ConfigForSize(len(analyzer.issueMap), analyzer.g.Edges().Len())
+	}
+
+	stats := analyzer.AnalyzeAsyncWithConfig(context.Background(), cfg)
+	stats.WaitForPhase2()
+
+	if st := stats.Status(); st.PageRank.State == "panic" {
+		return stats, Insights{}, fmt.Errorf("analyze issues: %s", st.PageRank.Reason)
+	}
+
+	return stats, stats.GenerateInsights(LibraryInsightsLimit), nil
+}
+
 // AnalyzeWithProfile performs synchronous graph analysis and returns detailed timing profile.
 // This is intended for diagnostics and the --profile-startup CLI flag.
 func (a *Analyzer) AnalyzeWithProfile(config AnalysisConfig) (*GraphStats, *StartupProfile) {
@@ -1361,8 +1529,15 @@ func (a *Analyzer) computePhase2WithProfile(ctx context.Context, stats *GraphSta
 
 	betweennessIsApprox := false
 	actualBetweennessSample := 0
+	betweennessWorkers := 0
 	cyclesTruncated := false
 
+	// Track which metrics ran to completion so a cancelled run can report
+	// partial results honestly.
+	cancelled := false
+	var prFinished, bwFinished, evFinished, cpFinished, cyclesFinished, advancedFinished bool
+	hitsFinished := a.g.Edges().Len() == 0 // nothing to compute without edges
+
 	// PageRank
 	if ctx.Err() == nil && config.ComputePageRank {
 		prStart := time.Now()
@@ -1373,13 +1548,16 @@ func (a *Analyzer) computePhase2WithProfile(ctx context.Context, stats *GraphSta
 					// Panic -> implicitly causes timeout in parent
 				}
 			}()
-			prDone <- computePageRank(a.g, 0.85, 1e-6)
+			if pr, err := computePageRankContext(ctx, a.g, 0.85, 1e-6); err == nil {
+				prDone <- pr
+			}
 		}()
 
 		timer := time.NewTimer(config.PageRankTimeout)
 		select {
 		case pr := <-prDone:
 			timer.Stop()
+			prFinished = true
 			for id, score := range pr {
 				localPageRank[a.nodeToID[id]] = score
 			}
@@ -1393,8 +1571,7 @@ func (a *Analyzer) computePhase2WithProfile(ctx context.Context, stats *GraphSta
 			}
 		case <-ctx.Done():
 			timer.Stop()
-			// Abort immediately
-			return
+			cancelled = true
 		}
 		profile.PageRank = time.Since(prStart)
 	}
@@ -1411,14 +1588,18 @@ func (a *Analyzer) computePhase2WithProfile(ctx context.Context, stats *GraphSta
 			}()
 			// Choose algorithm based on mode
 			if config.BetweennessMode == BetweennessApproximate && config.BetweennessSampleSize > 0 {
-				bwDone <- ApproxBetweenness(a.g, config.BetweennessSampleSize, 1)
+				if result, err := ApproxBetweennessContext(ctx, a.g, config.BetweennessSampleSize, 1); err == nil {
+					bwDone <- result
+				}
 			} else {
-				// Exact mode or mode not set (default to exact)
+				// Exact mode or mode not set (default to exact). gonum offers no
+				// cancellation hook, so a cancelled run simply abandons the result.
 				exact := network.Betweenness(a.g)
 				bwDone <- BetweennessResult{
 					Scores:     exact,
 					Mode:       BetweennessExact,
 					TotalNodes: a.g.Nodes().Len(),
+					Workers:    1,
 				}
 			}
 		}()
@@ -1427,6 +1608,8 @@ func (a *Analyzer) computePhase2WithProfile(ctx context.Context, stats *GraphSta
 		select {
 		case result := <-bwDone:
 			timer.Stop()
+			bwFinished = true
+			betweennessWorkers = result.Workers
 			for id, score := range result.Scores {
 				localBetweenness[a.nodeToID[id]] = score
 			}
@@ -1434,12 +1617,14 @@ func (a *Analyzer) computePhase2WithProfile(ctx context.Context, stats *GraphSta
 			if result.Mode == BetweennessApproximate {
 				betweennessIsApprox = true
 				actualBetweennessSample = result.SampleSize
+				profile.BetweennessApprox = true
+				profile.BetweennessSample = result.SampleSize
 			}
 		case <-timer.C:
 			profile.BetweennessTO = true
 		case <-ctx.Done():
 			timer.Stop()
-			return
+			cancelled = true
 		}
 		profile.Betweenness = time.Since(bwStart)
 	}
@@ -1450,6 +1635,7 @@ func (a *Analyzer) computePhase2WithProfile(ctx context.Context, stats *GraphSta
 		for id, score := range computeEigenvector(a.g) {
 			localEigenvector[a.nodeToID[id]] = score
 		}
+		evFinished = true
 		profile.Eigenvector = time.Since(evStart)
 	}
 
@@ -1470,6 +1656,7 @@ func (a *Analyzer) computePhase2WithProfile(ctx context.Context, stats *GraphSta
 		select {
 		case hubAuth := <-hitsDone:
 			timer.Stop()
+			hitsFinished = true
 			for id, ha := range hubAuth {
 				localHubs[a.nodeToID[id]] = ha.Hub
 				localAuthorities[a.nodeToID[id]] = ha.Authority
@@ -1478,7 +1665,7 @@ func (a *Analyzer) computePhase2WithProfile(ctx context.Context, stats *GraphSta
 			profile.HITSTO = true
 		case <-ctx.Done():
 			timer.Stop()
-			return
+			cancelled = true
 		}
 		profile.HITS = time.Since(hitsStart)
 	}
@@ -1490,6 +1677,7 @@ func (a *Analyzer) computePhase2WithProfile(ctx context.Context, stats *GraphSta
 		if err == nil {
 			localCriticalPath = a.computeHeights(sorted)
 		}
+		cpFinished = true
 		profile.CriticalPath = time.Since(cpStart)
 	}
 
@@ -1510,6 +1698,7 @@ func (a *Analyzer) computePhase2WithProfile(ctx context.Context, stats *GraphSta
 			}
 		}
 
+		cyclesFinished = !hasCycles
 		if hasCycles {
 			cyclesDone := make(chan [][]graph.Node, 1)
 			go func() {
@@ -1525,6 +1714,7 @@ func (a *Analyzer) computePhase2WithProfile(ctx context.Context, stats *GraphSta
 			select {
 			case cycles := <-cyclesDone:
 				timer.Stop()
+				cyclesFinished = true
 				profile.CycleCount = len(cycles)
 				cyclesToProcess := cycles
 				if len(cyclesToProcess) > maxCycles {
@@ -1543,7 +1733,7 @@ func (a *Analyzer) computePhase2WithProfile(ctx context.Context, stats *GraphSta
 				profile.CyclesTO = true
 			case <-ctx.Done():
 				timer.Stop()
-				return
+				cancelled = true
 			}
 		}
 		profile.Cycles = time.Since(cyclesStart)
@@ -1551,18 +1741,19 @@ func (a *Analyzer) computePhase2WithProfile(ctx context.Context, stats *GraphSta
 
 	// Check cancellation before advanced signals
 	if ctx.Err() != nil {
-		return
-	}
-
-	// Advanced graph signals: k-core, articulation points (undirected), slack (bv-85)
-	kcoreStart := time.Now()
-	localCore, localArticulation = a.computeCoreAndArticulation()
-	profile.KCore = time.Since(kcoreStart)
-	profile.Articulation = 0 // Computed together with k-core
+		cancelled = true
+	} else {
+		// Advanced graph signals: k-core, articulation points (undirected), slack (bv-85)
+		kcoreStart := time.Now()
+		localCore, localArticulation = a.computeCoreAndArticulation()
+		profile.KCore = time.Since(kcoreStart)
+		profile.Articulation = 0 // Computed together with k-core
 
-	slackStart := time.Now()
-	localSlack = a.computeSlack(stats.TopologicalOrder)
-	profile.Slack = time.Since(slackStart)
+		slackStart := time.Now()
+		localSlack = a.computeSlack(stats.TopologicalOrder)
+		profile.Slack = time.Since(slackStart)
+		advancedFinished = true
+	}
 
 	// Compute ranks (background optimization)
 	localPageRankRank := computeFloatRanks(localPageRank)
@@ -1594,6 +1785,7 @@ func (a *Analyzer) computePhase2WithProfile(ctx context.Context, stats *GraphSta
 	stats.criticalPathRank = localCriticalPathRank
 
 	stats.phase2Ready = true
+	stats.cancelled = cancelled
 
 	cycleReason := config.CyclesSkipReason
 	if cyclesTruncated {
@@ -1605,20 +1797,21 @@ func (a *Analyzer) computePhase2WithProfile(ctx context.Context, stats *GraphSta
 
 	// record status snapshot
 	stats.status = MetricStatus{
-		PageRank: statusEntry{State: stateFromTiming(config.ComputePageRank, profile.PageRankTO), Elapsed: profile.PageRank},
+		PageRank: statusEntry{State: stateWithCancel(config.ComputePageRank, profile.PageRankTO, prFinished), Elapsed: profile.PageRank},
 		Betweenness: statusEntry{
-			State:   stateFromTiming(config.ComputeBetweenness, profile.BetweennessTO),
+			State:   stateWithCancel(config.ComputeBetweenness, profile.BetweennessTO, bwFinished),
 			Reason:  betweennessReason(config, betweennessIsApprox),
 			Sample:  actualBetweennessSample,
+			Workers: betweennessWorkers,
 			Elapsed: profile.Betweenness,
 		},
-		Eigenvector:  statusEntry{State: stateFromTiming(config.ComputeEigenvector, false), Elapsed: profile.Eigenvector},
-		HITS:         statusEntry{State: stateFromTiming(config.ComputeHITS, profile.HITSTO), Reason: config.HITSSkipReason, Elapsed: profile.HITS},
-		Critical:     statusEntry{State: stateFromTiming(config.ComputeCriticalPath, false), Elapsed: profile.CriticalPath},
-		Cycles:       statusEntry{State: stateFromTiming(config.ComputeCycles, profile.CyclesTO), Reason: cycleReason, Elapsed: profile.Cycles},
-		KCore:        statusEntry{State: "computed", Elapsed: profile.KCore},        // bv-85: always computed (fast)
-		Articulation: statusEntry{State: "computed", Elapsed: profile.Articulation}, // bv-85: computed with k-core
-		Slack:        statusEntry{State: "computed", Elapsed: profile.Slack},        // bv-85: always computed (fast)
+		Eigenvector:  statusEntry{State: stateWithCancel(config.ComputeEigenvector, false, evFinished), Elapsed: profile.Eigenvector},
+		HITS:         statusEntry{State: stateWithCancel(config.ComputeHITS, profile.HITSTO, hitsFinished), Reason: config.HITSSkipReason, Elapsed: profile.HITS},
+		Critical:     statusEntry{State: stateWithCancel(config.ComputeCriticalPath, false, cpFinished), Elapsed: profile.CriticalPath},
+		Cycles:       statusEntry{State: stateWithCancel(config.ComputeCycles, profile.CyclesTO, cyclesFinished), Reason: cycleReason, Elapsed: profile.Cycles},
+		KCore:        statusEntry{State: stateWithCancel(true, false, advancedFinished), Elapsed: profile.KCore},        // bv-85: always computed (fast)
+		Articulation: statusEntry{State: stateWithCancel(true, false, advancedFinished), Elapsed: profile.Articulation}, // bv-85: computed with k-core
+		Slack:        statusEntry{State: stateWithCancel(true, false, advancedFinished), Elapsed: profile.Slack},        // bv-85: always computed (fast)
 	}
 	stats.mu.Unlock()
 }
@@ -1667,7 +1860,7 @@ func (a *Analyzer) computePhase1(stats *GraphStats) {
 // computePhase2 calculates expensive metrics in background.
 // Computes to local variables first, then atomically assigns under lock.
 // Respects the config to skip expensive algorithms for large graphs.
-func (a *Analyzer) computePhase2(ctx context.Context, stats *GraphStats, config AnalysisConfig, cacheKey, dataHash, configHash string) {
+func (a *Analyzer) computePhase2(ctx context.Context, stats *GraphStats, config AnalysisConfig, incCacheKey, cacheKey, dataHash, configHash string) {
 	defer close(stats.phase2Done)
 
 	// Recover from panics to prevent crashing the entire application
@@ -1700,6 +1893,12 @@ func (a *Analyzer) computePhase2(ctx context.Context, stats *GraphStats, config
 	dummyProfile := &StartupProfile{}
 	a.computePhase2WithProfile(ctx, stats, config, dummyProfile)
 
+	// Partial results from a cancelled run must never be served from a cache.
+	if stats.Cancelled() {
+		evictIncrementalGraphStatsCache(incCacheKey, stats)
+		return
+	}
+
 	if cacheKey != "" {
 		putRobotDiskCachedStats(cacheKey, dataHash, configHash, stats)
 	}
@@ -2045,6 +2244,70 @@ func findArticulationPoints(adj undirectedAdjacency) map[int64]bool {
 	return out
 }
 
+// TopologicalLayers assigns every issue a dependency depth: 0 for issues with
+// no blockers, otherwise one more than its deepest blocker. Members of a cycle
+// are reported in inCycle; each cycle is layered as a single unit, so issues
+// downstream of it still get a numeric level.
+func (a *Analyzer) TopologicalLayers() (levels map[string]int, inCycle map[string]bool) {
+	levels = make(map[string]int, len(a.nodeToID))
+	inCycle = make(map[string]bool)
+
+	// Tarjan emits components in reverse topological order: every component's
+	// blockers (edge targets) are layered before the component itself.
+	sccs := topo.TarjanSCC(a.g)
+	compOf := make(map[int64]int, len(a.nodeToID))
+	compLevel := make([]int, len(sccs))
+	for i, scc := range sccs {
+		for _, n := range scc {
+			compOf[n.ID()] = i
+		}
+		level := 0
+		for _, n := range scc {
+			blockers := a.g.From(n.ID())
+			for blockers.Next() {
+				if c := compOf[blockers.Node().ID()]; c != i && compLevel[c]+1 > level {
+					level = compLevel[c] + 1
+				}
+			}
+		}
+		compLevel[i] = level
+		for _, n := range scc {
+			id := a.nodeToID[n.ID()]
+			levels[id] = level
+			if len(scc) > 1 {
+				inCycle[id] = true
+			}
+		}
+	}
+	return levels, inCycle
+}
+
+// LiveOnly returns copies of issues without their blocking dependencies on
+// closed or tombstoned beads, for forward planning: a finished blocker no
+// longer shapes the critical path, slack, tracks or centrality. Non-blocking
+// links are kept. Analyze the unfiltered issues for the historical graph.
+func LiveOnly(issues []model.Issue) []model.Issue {
+	done := make(map[string]bool)
+	for _, issue := range issues {
+		if isClosedLikeStatus(issue.Status) {
+			done[issue.ID] = true
+		}
+	}
+	out := make([]model.Issue, len(issues))
+	for i, issue := range issues {
+		var deps []*model.Dependency
+		for _, dep := range issue.Dependencies {
+			if dep != nil && dep.Type.IsBlocking() && done[dep.DependsOnID] {
+				continue
+			}
+			deps = append(deps, dep)
+		}
+		issue.Dependencies = deps
+		out[i] = issue
+	}
+	return out
+}
+
 // GetActionableIssues returns issues that can be worked on immediately.
 // An issue is actionable if:
 // 1. It is not closed or tombstone
@@ -2067,29 +2330,78 @@ func (a *Analyzer) GetActionableIssues() []model.Issue {
 			continue
 		}
 
-		isBlocked := false
-		for _, dep := range issue.Dependencies {
-			if dep == nil || !dep.Type.IsBlocking() {
-				continue
-			}
+		if !a.hasOpenBlocker(issue) {
+			actionable = append(actionable, issue)
+		}
+	}
 
-			blocker, exists := a.issueMap[dep.DependsOnID]
-			if !exists {
-				continue
-			}
+	return actionable
+}
 
-			if !isClosedLikeStatus(blocker.Status) {
-				isBlocked = true
-				break
-			}
+// hasOpenBlocker reports whether a blocking dependency of the issue is still
+// open. Missing blockers don't block.
+func (a *Analyzer) hasOpenBlocker(issue model.Issue) bool {
+	for _, dep := range issue.Dependencies {
+		if dep == nil || !dep.Type.IsBlocking() {
+			continue
 		}
 
-		if !isBlocked {
-			actionable = append(actionable, issue)
+		blocker, exists := a.issueMap[dep.DependsOnID]
+		if !exists {
+			continue
+		}
+
+		if !isClosedLikeStatus(blocker.Status) {
+			return true
 		}
 	}
+	return false
+}
 
-	return actionable
+// DegreeStats holds the cheap, degree-only view of the graph: per-issue
+// in/out degree and the actionable/blocked split of open issues.
+type DegreeStats struct {
+	NodeCount  int
+	EdgeCount  int
+	InDegree   map[string]int // Issues depending on this one
+	OutDegree  map[string]int // Issues this one depends on
+	Open       int            // Issues that are not closed or tombstone
+	Actionable int            // Open issues with no open blockers
+	Blocked    int            // Open issues waiting on an open blocker
+}
+
+// DegreeStats computes in/out degree and actionable/blocked counts in
+// O(V+E), without phase 2 and without the topological sort. Use it for
+// quick queries that never read PageRank, betweenness, or other centrality
+// metrics; those stay lazy until Analyze or AnalyzeAsync is called.
+func (a *Analyzer) DegreeStats() DegreeStats {
+	ds := DegreeStats{
+		NodeCount: len(a.issueMap),
+		EdgeCount: a.g.Edges().Len(),
+		InDegree:  make(map[string]int, len(a.issueMap)),
+		OutDegree: make(map[string]int, len(a.issueMap)),
+	}
+
+	nodes := a.g.Nodes()
+	for nodes.Next() {
+		n := nodes.Node()
+		id := a.nodeToID[n.ID()]
+		ds.InDegree[id] = a.g.To(n.ID()).Len()
+		ds.OutDegree[id] = a.g.From(n.ID()).Len()
+	}
+
+	for _, issue := range a.issueMap {
+		if isClosedLikeStatus(issue.Status) {
+			continue
+		}
+		ds.Open++
+		if a.hasOpenBlocker(issue) {
+			ds.Blocked++
+		} else {
+			ds.Actionable++
+		}
+	}
+	return ds
 }
 
 // GetIssue returns a single issue by ID, or nil if not found
@@ -2295,10 +2607,17 @@ func (a *Analyzer) countBlockedBy(issueID string) int {
 // It uses a deterministic power iteration with damping factor damp and terminates
 // when the L2 norm of the delta is below tol (or after a hard iteration cap).
 func computePageRank(g graph.Directed, damp, tol float64) map[int64]float64 {
+	ranks, _ := computePageRankContext(context.Background(), g, damp, tol)
+	return ranks
+}
+
+// computePageRankContext is computePageRank that stops between iterations once
+// ctx is done, returning nil and ctx.Err().
+func computePageRankContext(ctx context.Context, g graph.Directed, damp, tol float64) (map[int64]float64, error) {
 	nodes := graph.NodesOf(g.Nodes())
 	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID() < nodes[j].ID() })
 	if len(nodes) == 0 {
-		return map[int64]float64{}
+		return map[int64]float64{}, nil
 	}
 	if tol <= 0 {
 		tol = 1e-6
@@ -2361,6 +2680,9 @@ func computePageRank(g graph.Directed, damp, tol float64) map[int64]float64 {
 	base := (1 - damp) / n
 	const maxIterations = 1000
 	for iter := 0; iter < maxIterations; iter++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		for i := range next {
 			next[i] = base
 		}
@@ -2401,7 +2723,7 @@ func computePageRank(g graph.Directed, damp, tol float64) map[int64]float64 {
 		ranks[node.ID()] = rank[i]
 	}
 
-	return ranks
+	return ranks, nil
 }
 
 // computeEigenvector runs a simple power-iteration to estimate eigenvector centrality.
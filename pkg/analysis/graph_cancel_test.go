@@ -0,0 +1,130 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// cancelAfterChecks is a context that reports itself cancelled after a fixed
+// number of Err() calls, giving tests a deterministic "mid-computation" point.
+type cancelAfterChecks struct {
+	context.Context
+	remaining atomic.Int64
+	done      chan struct{}
+	once      sync.Once
+}
+
+func newCancelAfterChecks(checks int64) *cancelAfterChecks {
+	c := &cancelAfterChecks{Context: context.Background(), done: make(chan struct{})}
+	c.remaining.Store(checks)
+	return c
+}
+
+func (c *cancelAfterChecks) Done() <-chan struct{} { return c.done }
+
+func (c *cancelAfterChecks) Err() error {
+	if c.remaining.Add(-1) >= 0 {
+		return nil
+	}
+	c.once.Do(func() { close(c.done) })
+	return context.Canceled
+}
+
+func cancelChainIssues(prefix string, n int) []model.Issue {
+	issues := make([]model.Issue, n)
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("%s-%d", prefix, i)
+		issues[i] = model.Issue{ID: id, Status: model.StatusOpen}
+		if i > 0 {
+			issues[i].Dependencies = []*model.Dependency{
+				{IssueID: id, DependsOnID: fmt.Sprintf("%s-%d", prefix, i-1), Type: model.DepBlocks},
+			}
+		}
+	}
+	return issues
+}
+
+func TestComputePageRankContext_StopsBetweenIterations(t *testing.T) {
+	an := NewAnalyzer(cancelChainIssues("pr-cancel", 30))
+
+	ranks, err := computePageRankContext(newCancelAfterChecks(2), an.g, 0.85, 1e-6)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if ranks != nil {
+		t.Fatalf("expected no ranks after cancellation, got %d", len(ranks))
+	}
+
+	ranks, err = computePageRankContext(context.Background(), an.g, 0.85, 1e-6)
+	if err != nil || len(ranks) != 30 {
+		t.Fatalf("uncancelled run: got %d ranks, err %v", len(ranks), err)
+	}
+}
+
+func TestAnalyzeWithContext_CancelledMidComputation(t *testing.T) {
+	issues := cancelChainIssues("mid-cancel", 30)
+	an := NewAnalyzer(issues)
+	cfg := ConfigForSize(len(issues), len(issues)-1)
+
+	// One check guards the PageRank phase and two more let it iterate before
+	// cancellation lands inside the power-iteration loop.
+	stats, err := an.AnalyzeWithContext(newCancelAfterChecks(3), cfg)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if stats == nil || !stats.Cancelled() {
+		t.Fatal("expected partial stats flagged as cancelled")
+	}
+	if !stats.IsPhase2Ready() {
+		t.Error("cancelled stats should still be marked phase-2 ready")
+	}
+
+	status := stats.Status()
+	if status.PageRank.State != "cancelled" {
+		t.Errorf("PageRank state = %q, want cancelled", status.PageRank.State)
+	}
+	if status.Betweenness.State != "cancelled" {
+		t.Errorf("Betweenness state = %q, want cancelled", status.Betweenness.State)
+	}
+	if len(stats.PageRank()) != 0 {
+		t.Error("expected no PageRank scores from a cancelled run")
+	}
+	if len(stats.TopologicalOrder) != len(issues) {
+		t.Errorf("phase 1 should be complete, got topo order of %d", len(stats.TopologicalOrder))
+	}
+
+	// A fresh run over the same graph must not reuse the partial result.
+	fresh, err := NewAnalyzer(issues).AnalyzeWithContext(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("fresh run: %v", err)
+	}
+	if fresh.Cancelled() || fresh.Status().PageRank.State != "computed" {
+		t.Fatalf("fresh run reused cancelled stats: %+v", fresh.Status().PageRank)
+	}
+}
+
+func TestAnalyzeWithContext_AlreadyCancelled(t *testing.T) {
+	issues := cancelChainIssues("pre-cancel", 5)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	stats, err := NewAnalyzer(issues).AnalyzeWithContext(ctx, ConfigForSize(len(issues), len(issues)-1))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if !stats.Cancelled() {
+		t.Fatal("expected stats flagged as cancelled")
+	}
+	if stats.Status().KCore.State != "cancelled" {
+		t.Errorf("KCore state = %q, want cancelled", stats.Status().KCore.State)
+	}
+	if stats.InDegree["pre-cancel-0"] != 1 {
+		t.Errorf("phase 1 in-degree missing: %v", stats.InDegree)
+	}
+}
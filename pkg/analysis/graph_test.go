@@ -156,6 +156,70 @@ func TestGetActionableIssuesChainTwoClosed(t *testing.T) {
 	}
 }
 
+func TestLiveOnlyDropsEdgesFromClosedBlockers(t *testing.T) {
+	// Closed A blocks B and E; C depends on B; D stands alone. B is also
+	// related to A.
+	issues := []model.Issue{
+		{ID: "A", Status: model.StatusClosed},
+		{ID: "B", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{DependsOnID: "A", Type: model.DepBlocks},
+			{DependsOnID: "A", Type: model.DepRelated},
+		}},
+		{ID: "C", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{DependsOnID: "B", Type: model.DepBlocks},
+		}},
+		{ID: "D", Status: model.StatusOpen},
+		{ID: "E", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{DependsOnID: "A", Type: model.DepBlocks},
+		}},
+	}
+	live := analysis.LiveOnly(issues)
+	if len(live[1].Dependencies) != 1 || live[1].Dependencies[0].Type != model.DepRelated {
+		t.Errorf("B deps = %v, want only the related link", live[1].Dependencies)
+	}
+	if len(issues[1].Dependencies) != 2 {
+		t.Errorf("LiveOnly must not modify its input, B deps = %d", len(issues[1].Dependencies))
+	}
+
+	full := analysis.NewAnalyzer(issues)
+	fullStats := full.Analyze()
+	liveAn := analysis.NewAnalyzer(live)
+	liveStats := liveAn.Analyze()
+
+	// The closed blocker heads the longest chain only in the full graph.
+	if got := fullStats.GetCriticalPathScore("A"); got != 3 {
+		t.Errorf("full critical path score of A = %v, want 3", got)
+	}
+	if got := liveStats.GetCriticalPathScore("A"); got != 1 {
+		t.Errorf("live critical path score of A = %v, want 1", got)
+	}
+
+	// D's slack is measured against the longest chain, which shrinks.
+	if got, _ := fullStats.SlackValue("D"); got != 2 {
+		t.Errorf("full slack of D = %v, want 2", got)
+	}
+	if got, _ := liveStats.SlackValue("D"); got != 1 {
+		t.Errorf("live slack of D = %v, want 1", got)
+	}
+
+	// B and E are one track while they share A, separate tracks without it.
+	trackOf := func(plan analysis.ExecutionPlan) map[string]string {
+		m := make(map[string]string)
+		for _, track := range plan.Tracks {
+			for _, item := range track.Items {
+				m[item.ID] = track.TrackID
+			}
+		}
+		return m
+	}
+	if tracks := trackOf(full.GetExecutionPlan()); tracks["B"] != tracks["E"] {
+		t.Errorf("full graph: B and E should share a track, got %v", tracks)
+	}
+	if tracks := trackOf(liveAn.GetExecutionPlan()); tracks["B"] == tracks["E"] {
+		t.Errorf("live graph: B and E should be separate tracks, got %v", tracks)
+	}
+}
+
 func TestGetActionableIssuesParallelTracks(t *testing.T) {
 	// Two independent chains:
 	// A depends on B (both open) → only B actionable
@@ -722,3 +786,75 @@ func TestGetBlockerChain(t *testing.T) {
 		}
 	})
 }
+
+func TestAnalyzeIssues_RejectsEmptyID(t *testing.T) {
+	issues := []model.Issue{{ID: "a"}, {ID: ""}}
+	if _, _, err := analysis.AnalyzeIssues(issues, analysis.AnalysisConfig{}); err == nil {
+		t.Fatal("expected error for empty issue ID")
+	}
+}
+
+func TestAnalyzeIssues_ExplicitConfigHonored(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "a", Status: model.StatusOpen},
+		{ID: "b", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "b", DependsOnID: "a", Type: model.DepBlocks}}},
+	}
+	cfg := analysis.AnalysisConfig{ComputePageRank: true, PageRankTimeout: time.Second}
+
+	stats, insights, err := analysis.AnalyzeIssues(issues, cfg)
+	if err != nil {
+		t.Fatalf("AnalyzeIssues: %v", err)
+	}
+	if !stats.IsPhase2Ready() {
+		t.Error("expected phase 2 to be complete")
+	}
+	if stats.Config != cfg {
+		t.Errorf("expected explicit config to be used, got %+v", stats.Config)
+	}
+	if st := stats.Status(); st.Betweenness.State != "skipped" {
+		t.Errorf("expected betweenness skipped, got %q", st.Betweenness.State)
+	}
+	if insights.Stats != stats {
+		t.Error("expected insights to reference returned stats")
+	}
+}
+
+func TestTopologicalLayers(t *testing.T) {
+	// Root -> Mid -> Leaf chain, Side depends on Root and Leaf (longest path wins),
+	// X <-> Y cycle blocks Down.
+	issues := []model.Issue{
+		{ID: "Root", Status: model.StatusOpen},
+		{ID: "Mid", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{DependsOnID: "Root", Type: model.DepBlocks},
+		}},
+		{ID: "Leaf", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{DependsOnID: "Mid", Type: model.DepBlocks},
+			{DependsOnID: "Root", Type: model.DepRelated},
+		}},
+		{ID: "Side", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{DependsOnID: "Root", Type: model.DepBlocks},
+			{DependsOnID: "Leaf", Type: model.DepBlocks},
+		}},
+		{ID: "X", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{DependsOnID: "Y", Type: model.DepBlocks},
+		}},
+		{ID: "Y", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{DependsOnID: "X", Type: model.DepBlocks},
+			{DependsOnID: "Root", Type: model.DepBlocks},
+		}},
+		{ID: "Down", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{DependsOnID: "X", Type: model.DepBlocks},
+		}},
+	}
+
+	levels, inCycle := analysis.NewAnalyzer(issues).TopologicalLayers()
+	want := map[string]int{"Root": 0, "Mid": 1, "Leaf": 2, "Side": 3, "X": 1, "Y": 1, "Down": 2}
+	for id, level := range want {
+		if levels[id] != level {
+			t.Errorf("level[%s] = %d, want %d", id, levels[id], level)
+		}
+	}
+	if !inCycle["X"] || !inCycle["Y"] || len(inCycle) != 2 {
+		t.Errorf("expected only X and Y in a cycle, got %v", inCycle)
+	}
+}
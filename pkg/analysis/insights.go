@@ -81,6 +81,14 @@ func (s *GraphStats) GenerateInsights(limit int) Insights {
 		}
 	}
 
+	// Empty lists rather than null keep the JSON shape stable on empty graphs.
+	if artPts == nil {
+		artPts = []string{}
+	}
+	if cycles == nil {
+		cycles = [][]string{}
+	}
+
 	return Insights{
 		Bottlenecks:    getTopItems(betweenness, limit),
 		Keystones:      getTopItems(criticalPath, limit),
@@ -89,6 +97,7 @@ func (s *GraphStats) GenerateInsights(limit int) Insights {
 		Authorities:    getTopItems(authorities, limit),
 		Cores:          getTopItemsInt(coreNum, limit),
 		Articulation:   limitStrings(artPts, limit),
+		Orphans:        []string{},
 		Slack:          getTopItems(slack, limit),
 		Cycles:         cycles,
 		ClusterDensity: s.Density,
@@ -1069,7 +1069,10 @@ func ComputeBlockageCascade(issues []model.Issue, flow CrossLabelFlow, cfg Label
 
 	// Sort cascades by total impact (highest first)
 	sort.Slice(allCascades, func(i, j int) bool {
-		return allCascades[i].TotalImpact > allCascades[j].TotalImpact
+		if allCascades[i].TotalImpact != allCascades[j].TotalImpact {
+			return allCascades[i].TotalImpact > allCascades[j].TotalImpact
+		}
+		return allCascades[i].SourceLabel < allCascades[j].SourceLabel
 	})
 	result.Cascades = allCascades
 
@@ -1082,7 +1085,10 @@ func ComputeBlockageCascade(issues []model.Issue, flow CrossLabelFlow, cfg Label
 		if allRecs[i].UnblocksCount != allRecs[j].UnblocksCount {
 			return allRecs[i].UnblocksCount > allRecs[j].UnblocksCount
 		}
-		return allRecs[i].CascadeDepth > allRecs[j].CascadeDepth
+		if allRecs[i].CascadeDepth != allRecs[j].CascadeDepth {
+			return allRecs[i].CascadeDepth > allRecs[j].CascadeDepth
+		}
+		return allRecs[i].IssueID < allRecs[j].IssueID
 	})
 
 	// Take top 10 recommendations
@@ -1178,7 +1184,10 @@ func computeSingleCascade(sourceLabel string, blockedIssues []model.Issue, flow
 		if len(levelEntries) > 0 {
 			// Sort entries by waiting count (highest first)
 			sort.Slice(levelEntries, func(i, j int) bool {
-				return levelEntries[i].WaitingCount > levelEntries[j].WaitingCount
+				if levelEntries[i].WaitingCount != levelEntries[j].WaitingCount {
+					return levelEntries[i].WaitingCount > levelEntries[j].WaitingCount
+				}
+				return levelEntries[i].Label < levelEntries[j].Label
 			})
 
 			result.CascadeLevels = append(result.CascadeLevels, CascadeLevel{
@@ -1227,7 +1236,10 @@ func computeSingleCascade(sourceLabel string, blockedIssues []model.Issue, flow
 		blockers = append(blockers, blockerRec{id: id, impact: impact})
 	}
 	sort.Slice(blockers, func(i, j int) bool {
-		return blockers[i].impact > blockers[j].impact
+		if blockers[i].impact != blockers[j].impact {
+			return blockers[i].impact > blockers[j].impact
+		}
+		return blockers[i].id < blockers[j].id
 	})
 
 	// Take top 5 recommendations for this cascade
@@ -1321,6 +1333,17 @@ type LabelSubgraph struct {
 // The resulting subgraph can be used to run PageRank, critical path, and other
 // graph algorithms within the context of a specific label.
 func ComputeLabelSubgraph(issues []model.Issue, label string) LabelSubgraph {
+	return computeLabelSubgraph(issues, label, func(l string) bool { return l == label })
+}
+
+// ComputeLabelSubgraphMatching is ComputeLabelSubgraph for a label pattern:
+// core issues are those with any label the matcher accepts, so "team/*"
+// scopes to every team label at once. Label is set to the pattern.
+func ComputeLabelSubgraphMatching(issues []model.Issue, m LabelMatcher) LabelSubgraph {
+	return computeLabelSubgraph(issues, m.String(), m.Match)
+}
+
+func computeLabelSubgraph(issues []model.Issue, label string, match func(string) bool) LabelSubgraph {
 	result := LabelSubgraph{
 		Label:            label,
 		CoreIssues:       []string{},
@@ -1346,7 +1369,7 @@ func ComputeLabelSubgraph(issues []model.Issue, label string) LabelSubgraph {
 	coreSet := make(map[string]bool)
 	for _, iss := range issues {
 		for _, l := range iss.Labels {
-			if l == label {
+			if match(l) {
 				coreSet[iss.ID] = true
 				result.IssueMap[iss.ID] = iss
 				break
@@ -2005,8 +2028,15 @@ func computeLabelAttention(label string, issues []model.Issue, issueMap map[stri
 	sg := ComputeLabelSubgraph(issues, label)
 	if !sg.IsEmpty() {
 		pr := ComputeLabelPageRank(sg)
-		for _, s := range pr.CoreOnly {
-			score.PageRankSum += s
+		// Sum in ID order: float addition order would otherwise follow map
+		// iteration and perturb the last digits between runs
+		ids := make([]string, 0, len(pr.CoreOnly))
+		for id := range pr.CoreOnly {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			score.PageRankSum += pr.CoreOnly[id]
 		}
 	}
 
@@ -0,0 +1,118 @@
+package analysis
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LabelMatcher selects labels by exact name, glob, or regular expression:
+//   - "team/infra" matches exactly that label
+//   - "team/*" is a glob: * matches any run of characters (including /),
+//     ? one character, [...] a character class; case is ignored
+//   - "/^team\/(infra|web)$/" is a regular expression (RE2 syntax), used
+//     as written; add (?i) for case-insensitive matching
+type LabelMatcher struct {
+	pattern string
+	exact   string
+	re      *regexp.Regexp
+}
+
+// ParseLabelPattern compiles a label filter. An empty pattern matches
+// every label.
+func ParseLabelPattern(pattern string) (LabelMatcher, error) {
+	pattern = strings.TrimSpace(pattern)
+	m := LabelMatcher{pattern: pattern}
+	switch {
+	case pattern == "":
+		return m, nil
+	case len(pattern) >= 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/"):
+		body := pattern[1 : len(pattern)-1]
+		if body == "" {
+			return LabelMatcher{}, fmt.Errorf("label regex %q is empty", pattern)
+		}
+		re, err := regexp.Compile(body)
+		if err != nil {
+			return LabelMatcher{}, fmt.Errorf("invalid label regex %q: %w", pattern, err)
+		}
+		m.re = re
+	case strings.ContainsAny(pattern, "*?["):
+		re, err := globToRegexp(pattern)
+		if err != nil {
+			return LabelMatcher{}, fmt.Errorf("invalid label glob %q: %w", pattern, err)
+		}
+		m.re = re
+	default:
+		m.exact = pattern
+	}
+	return m, nil
+}
+
+// String returns the pattern as given
+func (m LabelMatcher) String() string {
+	return m.pattern
+}
+
+// IsPattern reports whether the matcher is a glob or regex rather than a
+// single label name
+func (m LabelMatcher) IsPattern() bool {
+	return m.re != nil
+}
+
+// Match reports whether a label satisfies the filter
+func (m LabelMatcher) Match(label string) bool {
+	switch {
+	case m.re != nil:
+		return m.re.MatchString(label)
+	case m.exact != "":
+		return label == m.exact
+	}
+	return true
+}
+
+// MatchAny reports whether any of the labels satisfies the filter. An empty
+// matcher accepts every issue, labeled or not.
+func (m LabelMatcher) MatchAny(labels []string) bool {
+	if m.pattern == "" {
+		return true
+	}
+	for _, l := range labels {
+		if m.Match(l) {
+			return true
+		}
+	}
+	return false
+}
+
+// globToRegexp translates a label glob into an anchored, case-insensitive
+// regular expression
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("(?i)^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed [ at offset %d", i)
+			}
+			class := glob[i+1 : i+1+end]
+			if class == "" {
+				return nil, fmt.Errorf("empty [] at offset %d", i)
+			}
+			if class[0] == '!' {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
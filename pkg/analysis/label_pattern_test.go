@@ -0,0 +1,113 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestParseLabelPatternMatching(t *testing.T) {
+	tests := []struct {
+		pattern string
+		label   string
+		want    bool
+	}{
+		{"team/infra", "team/infra", true},
+		{"team/infra", "Team/Infra", false},
+		{"team/infra", "team/infra2", false},
+		{"team/*", "team/infra", true},
+		{"team/*", "Team/Frontend", true},
+		{"team/*", "team/a/b", true},
+		{"team/*", "ops", false},
+		{"team/*", "myteam/infra", false},
+		{"v?", "v2", true},
+		{"v?", "v10", false},
+		{"p[0-2]", "p1", true},
+		{"p[!0-2]", "p1", false},
+		{"p[!0-2]", "p3", true},
+		{"a.b", "axb", false},
+		{`/^team\/(infra|web)$/`, "team/infra", true},
+		{`/^team\/(infra|web)$/`, "team/frontend", false},
+		{`/^team\/(infra|web)$/`, "TEAM/infra", false},
+		{`/(?i)^team\//`, "TEAM/infra", true},
+	}
+	for _, tt := range tests {
+		m, err := ParseLabelPattern(tt.pattern)
+		if err != nil {
+			t.Fatalf("ParseLabelPattern(%q): %v", tt.pattern, err)
+		}
+		if got := m.Match(tt.label); got != tt.want {
+			t.Errorf("ParseLabelPattern(%q).Match(%q) = %v, want %v", tt.pattern, tt.label, got, tt.want)
+		}
+	}
+}
+
+func TestParseLabelPatternErrors(t *testing.T) {
+	tests := map[string]string{
+		"/(/":     "invalid label regex",
+		"//":      "is empty",
+		"team/[x": "invalid label glob",
+		"p[]":     "invalid label glob",
+	}
+	for pattern, want := range tests {
+		_, err := ParseLabelPattern(pattern)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseLabelPattern(%q) error = %v, want %q", pattern, err, want)
+		}
+	}
+}
+
+func TestLabelMatcherMatchAny(t *testing.T) {
+	empty, _ := ParseLabelPattern("")
+	if !empty.MatchAny(nil) || empty.IsPattern() {
+		t.Error("empty pattern should match unlabeled issues and not be a pattern")
+	}
+	glob, _ := ParseLabelPattern("team/*")
+	if !glob.IsPattern() || glob.String() != "team/*" {
+		t.Errorf("glob matcher: IsPattern=%v String=%q", glob.IsPattern(), glob.String())
+	}
+	if glob.MatchAny(nil) || glob.MatchAny([]string{"ops", "docs"}) {
+		t.Error("team/* should not match issues without a team/ label")
+	}
+	if !glob.MatchAny([]string{"ops", "team/infra"}) {
+		t.Error("team/* should match when any label matches")
+	}
+}
+
+func TestComputeLabelSubgraphMatchingGlob(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "L-1", Labels: []string{"team/infra"}},
+		{ID: "L-2", Labels: []string{"team/frontend"}},
+		{
+			ID:     "L-3",
+			Labels: []string{"ops"},
+			Dependencies: []*model.Dependency{
+				{DependsOnID: "L-1", Type: model.DepBlocks},
+			},
+		},
+		{ID: "L-4", Labels: []string{"docs"}},
+	}
+
+	m, err := ParseLabelPattern("team/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sg := ComputeLabelSubgraphMatching(issues, m)
+
+	if strings.Join(sg.CoreIssues, ",") != "L-1,L-2" {
+		t.Errorf("CoreIssues = %v, want [L-1 L-2]", sg.CoreIssues)
+	}
+	if strings.Join(sg.DependencyIssues, ",") != "L-3" {
+		t.Errorf("DependencyIssues = %v, want [L-3]", sg.DependencyIssues)
+	}
+	if sg.Label != "team/*" {
+		t.Errorf("Label = %q, want team/*", sg.Label)
+	}
+
+	// A plain label still goes through the same path
+	exact, _ := ParseLabelPattern("team/infra")
+	if got := ComputeLabelSubgraphMatching(issues, exact).CoreIssues; strings.Join(got, ",") != "L-1" {
+		t.Errorf("exact CoreIssues = %v, want [L-1]", got)
+	}
+}
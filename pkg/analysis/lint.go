@@ -0,0 +1,303 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// LintSeverity ranks how urgently a lint finding should be addressed.
+type LintSeverity string
+
+const (
+	LintSeverityError   LintSeverity = "error"
+	LintSeverityWarning LintSeverity = "warning"
+	LintSeverityInfo    LintSeverity = "info"
+)
+
+// Lint finding codes
+const (
+	LintCycle                = "cycle"
+	LintSelfDependency       = "self_dependency"
+	LintDanglingReference    = "dangling_reference"
+	LintBlockedByClosedOnly  = "blocked_by_closed_only"
+	LintEpicWithoutChildren  = "epic_without_children"
+	LintP0ManyBlockers       = "p0_many_blockers"
+	LintOrphanedHighPriority = "orphaned_high_priority"
+)
+
+// LintFinding is a single structural smell in the bead graph.
+type LintFinding struct {
+	Code         string       `json:"code"`
+	Severity     LintSeverity `json:"severity"`
+	IssueIDs     []string     `json:"issue_ids"`
+	Message      string       `json:"message"`
+	SuggestedFix string       `json:"suggested_fix"`
+}
+
+// LintConfig configures graph linting thresholds.
+type LintConfig struct {
+	// MaxCycles limits how many cycles are reported
+	// Default: 20
+	MaxCycles int
+
+	// P0BlockerThreshold flags P0 beads with at least this many open blockers
+	// Default: 3
+	P0BlockerThreshold int
+
+	// HighPriorityMax is the highest priority value considered "high priority"
+	// for orphan detection (0 = P0 only, 1 = P0/P1)
+	// Default: 1
+	HighPriorityMax int
+
+	// MaxCycleRisks limits how many near-cycles are reported
+	// Default: 50
+	MaxCycleRisks int
+}
+
+// DefaultLintConfig returns sensible defaults
+func DefaultLintConfig() LintConfig {
+	return LintConfig{
+		MaxCycles:          20,
+		P0BlockerThreshold: 3,
+		HighPriorityMax:    1,
+		MaxCycleRisks:      DefaultMaxCycleRisks,
+	}
+}
+
+// LintIssues checks the bead graph for common anti-patterns. Tombstoned beads
+// are ignored. Findings are sorted by severity, then code, then first bead ID.
+func LintIssues(issues []model.Issue, config LintConfig) []LintFinding {
+	issueMap := make(map[string]*model.Issue, len(issues))
+	live := make([]model.Issue, 0, len(issues))
+	for i := range issues {
+		if issues[i].Status.IsTombstone() {
+			continue
+		}
+		issueMap[issues[i].ID] = &issues[i]
+		live = append(live, issues[i])
+	}
+
+	findings := make([]LintFinding, 0)
+
+	// Track which beads participate in any relationship (for orphan detection)
+	// and which epics have children.
+	connected := make(map[string]bool, len(live))
+	hasChildren := make(map[string]bool)
+
+	for _, issue := range live {
+		openBlockers := 0
+		blockingDeps := 0
+		closedBlockers := 0
+
+		for _, dep := range issue.Dependencies {
+			if dep == nil || dep.DependsOnID == "" {
+				continue
+			}
+
+			if dep.DependsOnID == issue.ID {
+				findings = append(findings, LintFinding{
+					Code:         LintSelfDependency,
+					Severity:     LintSeverityError,
+					IssueIDs:     []string{issue.ID},
+					Message:      fmt.Sprintf("%s depends on itself (%s)", issue.ID, depTypeLabel(dep.Type)),
+					SuggestedFix: fmt.Sprintf("bd dep remove %s %s", issue.ID, issue.ID),
+				})
+				continue
+			}
+
+			target, ok := issueMap[dep.DependsOnID]
+			if !ok {
+				findings = append(findings, LintFinding{
+					Code:         LintDanglingReference,
+					Severity:     LintSeverityWarning,
+					IssueIDs:     []string{issue.ID, dep.DependsOnID},
+					Message:      fmt.Sprintf("%s references missing bead %s (%s)", issue.ID, dep.DependsOnID, depTypeLabel(dep.Type)),
+					SuggestedFix: fmt.Sprintf("bd dep remove %s %s", issue.ID, dep.DependsOnID),
+				})
+				continue
+			}
+
+			connected[issue.ID] = true
+			connected[target.ID] = true
+			if dep.Type == model.DepParentChild {
+				hasChildren[target.ID] = true
+			}
+
+			if dep.Type.IsBlocking() {
+				blockingDeps++
+				if target.Status.IsClosed() {
+					closedBlockers++
+				} else {
+					openBlockers++
+				}
+			}
+		}
+
+		if issue.Status == model.StatusBlocked && blockingDeps > 0 && closedBlockers == blockingDeps {
+			findings = append(findings, LintFinding{
+				Code:         LintBlockedByClosedOnly,
+				Severity:     LintSeverityWarning,
+				IssueIDs:     []string{issue.ID},
+				Message:      fmt.Sprintf("%s is marked blocked but all %d blocker(s) are closed", issue.ID, blockingDeps),
+				SuggestedFix: fmt.Sprintf("bd update %s --status open", issue.ID),
+			})
+		}
+
+		if issue.Priority == 0 && !issue.Status.IsClosed() && config.P0BlockerThreshold > 0 && openBlockers >= config.P0BlockerThreshold {
+			findings = append(findings, LintFinding{
+				Code:         LintP0ManyBlockers,
+				Severity:     LintSeverityWarning,
+				IssueIDs:     []string{issue.ID},
+				Message:      fmt.Sprintf("P0 bead %s is waiting on %d open blockers", issue.ID, openBlockers),
+				SuggestedFix: fmt.Sprintf("Split %s or raise the priority of its blockers (bd dep tree %s)", issue.ID, issue.ID),
+			})
+		}
+	}
+
+	for _, issue := range live {
+		if issue.Status.IsClosed() {
+			continue
+		}
+		if issue.IssueType == model.TypeEpic && !hasChildren[issue.ID] {
+			findings = append(findings, LintFinding{
+				Code:         LintEpicWithoutChildren,
+				Severity:     LintSeverityInfo,
+				IssueIDs:     []string{issue.ID},
+				Message:      fmt.Sprintf("Epic %s has no child beads", issue.ID),
+				SuggestedFix: fmt.Sprintf("Attach children with bd dep add <child> %s --type parent-child, or close the epic", issue.ID),
+			})
+		}
+		if issue.Priority <= config.HighPriorityMax && !connected[issue.ID] {
+			findings = append(findings, LintFinding{
+				Code:         LintOrphanedHighPriority,
+				Severity:     LintSeverityInfo,
+				IssueIDs:     []string{issue.ID},
+				Message:      fmt.Sprintf("P%d bead %s has no dependencies, dependents, or parent", issue.Priority, issue.ID),
+				SuggestedFix: fmt.Sprintf("Link %s to its epic or the work it unblocks (bd dep add)", issue.ID),
+			})
+		}
+	}
+
+	findings = append(findings, lintCycles(live, config.MaxCycles)...)
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		ri, rj := lintSeverityRank(findings[i].Severity), lintSeverityRank(findings[j].Severity)
+		if ri != rj {
+			return ri < rj
+		}
+		if findings[i].Code != findings[j].Code {
+			return findings[i].Code < findings[j].Code
+		}
+		return strings.Join(findings[i].IssueIDs, ",") < strings.Join(findings[j].IssueIDs, ",")
+	})
+
+	return findings
+}
+
+func lintCycles(issues []model.Issue, maxCycles int) []LintFinding {
+	if len(issues) < 2 {
+		return nil
+	}
+
+	stats := NewAnalyzer(issues).AnalyzeWithConfig(AnalysisConfig{
+		ComputeCycles:    true,
+		CyclesTimeout:    500 * time.Millisecond,
+		MaxCyclesToStore: 100,
+	})
+
+	var findings []LintFinding
+	for _, cycle := range stats.Cycles() {
+		if maxCycles > 0 && len(findings) >= maxCycles {
+			break
+		}
+		members := cycle
+		if len(members) > 1 && members[0] == members[len(members)-1] {
+			members = members[:len(members)-1]
+		}
+		if len(members) == 0 {
+			continue
+		}
+		findings = append(findings, LintFinding{
+			Code:         LintCycle,
+			Severity:     LintSeverityError,
+			IssueIDs:     append([]string(nil), members...),
+			Message:      fmt.Sprintf("Dependency cycle: %s", formatCyclePath(cycle)),
+			SuggestedFix: fmt.Sprintf("Remove one edge to break the cycle (e.g. bd dep remove %s %s)", members[0], members[(1)%len(members)]),
+		})
+	}
+	return findings
+}
+
+func lintSeverityRank(s LintSeverity) int {
+	switch s {
+	case LintSeverityError:
+		return 0
+	case LintSeverityWarning:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func depTypeLabel(t model.DependencyType) string {
+	if t == "" {
+		return string(model.DepBlocks)
+	}
+	return string(t)
+}
+
+// LintSummary counts findings by severity and code.
+type LintSummary struct {
+	Total    int            `json:"total"`
+	Errors   int            `json:"errors"`
+	Warnings int            `json:"warnings"`
+	Info     int            `json:"info"`
+	ByCode   map[string]int `json:"by_code"`
+}
+
+// RobotLintOutput is the JSON output structure for --robot-lint
+type RobotLintOutput struct {
+	GeneratedAt string          `json:"generated_at"`
+	DataHash    string          `json:"data_hash"`
+	Summary     LintSummary     `json:"summary"`
+	Findings    []LintFinding   `json:"findings"`
+	CycleRisks  CycleRiskReport `json:"cycle_risks"` // Dependency additions that would create a cycle
+	UsageHints  []string        `json:"usage_hints"`
+}
+
+// GenerateRobotLintOutput creates the full robot-lint output
+func GenerateRobotLintOutput(issues []model.Issue, config LintConfig, dataHash string) RobotLintOutput {
+	findings := LintIssues(issues, config)
+
+	summary := LintSummary{Total: len(findings), ByCode: make(map[string]int)}
+	for _, f := range findings {
+		summary.ByCode[f.Code]++
+		switch f.Severity {
+		case LintSeverityError:
+			summary.Errors++
+		case LintSeverityWarning:
+			summary.Warnings++
+		default:
+			summary.Info++
+		}
+	}
+
+	return RobotLintOutput{
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		DataHash:    dataHash,
+		Summary:     summary,
+		Findings:    findings,
+		CycleRisks:  DetectCycleRisks(issues, config.MaxCycleRisks),
+		UsageHints: []string{
+			"jq '.findings[] | select(.severity==\"error\")' - Errors only",
+			"jq '.summary.by_code' - Count by finding code",
+			"jq '.findings[].suggested_fix' - All suggested fixes",
+			"jq '[.findings[].issue_ids[]] | unique' - Every bead with a finding",
+			"jq '.cycle_risks.risks[].avoid' - Dependency additions that would create a cycle",
+		},
+	}
+}
@@ -0,0 +1,143 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func lintCodes(findings []LintFinding) map[string][]LintFinding {
+	byCode := make(map[string][]LintFinding)
+	for _, f := range findings {
+		byCode[f.Code] = append(byCode[f.Code], f)
+	}
+	return byCode
+}
+
+func TestLintIssues_Clean(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "epic", Title: "Epic", Status: model.StatusOpen, IssueType: model.TypeEpic, Priority: 1},
+		{ID: "a", Title: "A", Status: model.StatusOpen, IssueType: model.TypeTask, Priority: 1, Dependencies: []*model.Dependency{
+			{IssueID: "a", DependsOnID: "epic", Type: model.DepParentChild},
+		}},
+		{ID: "b", Title: "B", Status: model.StatusOpen, IssueType: model.TypeTask, Priority: 2, Dependencies: []*model.Dependency{
+			{IssueID: "b", DependsOnID: "a", Type: model.DepBlocks},
+		}},
+	}
+
+	findings := LintIssues(issues, DefaultLintConfig())
+	if len(findings) != 0 {
+		t.Fatalf("expected no findings, got %+v", findings)
+	}
+}
+
+func TestLintIssues_DetectsEachSmell(t *testing.T) {
+	issues := []model.Issue{
+		// Cycle x <-> y
+		{ID: "x", Status: model.StatusOpen, Priority: 2, Dependencies: []*model.Dependency{{IssueID: "x", DependsOnID: "y", Type: model.DepBlocks}}},
+		{ID: "y", Status: model.StatusOpen, Priority: 2, Dependencies: []*model.Dependency{{IssueID: "y", DependsOnID: "x", Type: model.DepBlocks}}},
+		// Self dependency and dangling reference
+		{ID: "self", Status: model.StatusOpen, Priority: 2, Dependencies: []*model.Dependency{{IssueID: "self", DependsOnID: "self"}}},
+		{ID: "dangle", Status: model.StatusOpen, Priority: 2, Dependencies: []*model.Dependency{{IssueID: "dangle", DependsOnID: "ghost", Type: model.DepBlocks}}},
+		// Blocked only by closed beads
+		{ID: "done", Status: model.StatusClosed, Priority: 2},
+		{ID: "stuck", Status: model.StatusBlocked, Priority: 2, Dependencies: []*model.Dependency{{IssueID: "stuck", DependsOnID: "done", Type: model.DepBlocks}}},
+		// Empty epic
+		{ID: "lonely-epic", Status: model.StatusOpen, IssueType: model.TypeEpic, Priority: 2},
+		// P0 with many blockers
+		{ID: "hot", Status: model.StatusOpen, Priority: 0, Dependencies: []*model.Dependency{
+			{IssueID: "hot", DependsOnID: "b1", Type: model.DepBlocks},
+			{IssueID: "hot", DependsOnID: "b2", Type: model.DepBlocks},
+			{IssueID: "hot", DependsOnID: "b3", Type: model.DepBlocks},
+		}},
+		{ID: "b1", Status: model.StatusOpen, Priority: 2},
+		{ID: "b2", Status: model.StatusOpen, Priority: 2},
+		{ID: "b3", Status: model.StatusInProgress, Priority: 2},
+		// Orphaned high priority
+		{ID: "orphan", Status: model.StatusOpen, Priority: 1},
+		// Tombstones are ignored entirely
+		{ID: "gone", Status: model.StatusTombstone, Priority: 0, Dependencies: []*model.Dependency{{IssueID: "gone", DependsOnID: "gone"}}},
+	}
+
+	findings := LintIssues(issues, DefaultLintConfig())
+	byCode := lintCodes(findings)
+
+	expect := map[string]string{
+		LintCycle:                "x",
+		LintSelfDependency:       "self",
+		LintDanglingReference:    "dangle",
+		LintBlockedByClosedOnly:  "stuck",
+		LintEpicWithoutChildren:  "lonely-epic",
+		LintP0ManyBlockers:       "hot",
+		LintOrphanedHighPriority: "orphan",
+	}
+	for code, id := range expect {
+		got := byCode[code]
+		if len(got) != 1 {
+			t.Errorf("expected 1 %s finding, got %d: %+v", code, len(got), got)
+			continue
+		}
+		found := false
+		for _, gotID := range got[0].IssueIDs {
+			if gotID == id {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%s: expected %q in issue_ids, got %v", code, id, got[0].IssueIDs)
+		}
+		if got[0].SuggestedFix == "" {
+			t.Errorf("%s: expected a suggested fix", code)
+		}
+	}
+	if len(findings) != len(expect) {
+		t.Errorf("expected %d findings, got %d: %+v", len(expect), len(findings), findings)
+	}
+
+	// Errors sort first, info last
+	if findings[0].Severity != LintSeverityError {
+		t.Errorf("expected first finding to be an error, got %s", findings[0].Severity)
+	}
+	if findings[len(findings)-1].Severity != LintSeverityInfo {
+		t.Errorf("expected last finding to be info, got %s", findings[len(findings)-1].Severity)
+	}
+}
+
+func TestLintIssues_BlockedWithOpenBlockerNotFlagged(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "done", Status: model.StatusClosed},
+		{ID: "open", Status: model.StatusOpen, Priority: 2},
+		{ID: "stuck", Status: model.StatusBlocked, Priority: 2, Dependencies: []*model.Dependency{
+			{IssueID: "stuck", DependsOnID: "done", Type: model.DepBlocks},
+			{IssueID: "stuck", DependsOnID: "open", Type: model.DepBlocks},
+		}},
+	}
+
+	if got := lintCodes(LintIssues(issues, DefaultLintConfig()))[LintBlockedByClosedOnly]; len(got) != 0 {
+		t.Errorf("expected no blocked_by_closed_only finding, got %+v", got)
+	}
+}
+
+func TestGenerateRobotLintOutput_Summary(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "self", Status: model.StatusOpen, Priority: 2, Dependencies: []*model.Dependency{{IssueID: "self", DependsOnID: "self"}}},
+		{ID: "orphan", Status: model.StatusOpen, Priority: 0},
+	}
+
+	output := GenerateRobotLintOutput(issues, DefaultLintConfig(), "hash123")
+	if output.DataHash != "hash123" {
+		t.Errorf("expected data_hash to be passed through, got %q", output.DataHash)
+	}
+	if output.GeneratedAt == "" {
+		t.Error("expected generated_at to be set")
+	}
+	if output.Summary.Total != 2 || output.Summary.Errors != 1 || output.Summary.Info != 1 {
+		t.Errorf("unexpected summary: %+v", output.Summary)
+	}
+	if output.Summary.ByCode[LintSelfDependency] != 1 {
+		t.Errorf("expected by_code to count self_dependency, got %v", output.Summary.ByCode)
+	}
+	if len(output.UsageHints) == 0 {
+		t.Error("expected usage hints")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"sort"
 
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"gonum.org/v1/gonum/graph"
 )
 
 // PlanItem represents a single actionable item in the execution plan
@@ -13,6 +14,8 @@ type PlanItem struct {
 	Priority    int      `json:"priority"`
 	Status      string   `json:"status"`
 	UnblocksIDs []string `json:"unblocks"` // Issues that become actionable when this is done
+	// Longest-path slack (0 = on the critical path); set by AnnotatePlanSlack
+	Slack *float64 `json:"slack,omitempty"`
 }
 
 // ExecutionTrack represents a group of related actionable items
@@ -28,6 +31,14 @@ type ExecutionPlan struct {
 	TotalActionable int              `json:"total_actionable"`
 	TotalBlocked    int              `json:"total_blocked"`
 	Summary         PlanSummary      `json:"summary"`
+	Schedule        *PlanSchedule    `json:"schedule,omitempty"` // Set by AnnotatePlanSlack
+}
+
+// PlanSchedule turns per-item slack into scheduling advice
+type PlanSchedule struct {
+	DoFirst      []string `json:"do_first"`      // Zero-slack items on the critical path
+	Deferrable   []string `json:"deferrable"`    // Items with slack: can run alongside the critical path or wait
+	ParallelSafe []string `json:"parallel_safe"` // Items with no dependency path to any other actionable item
 }
 
 // PlanSummary provides quick insights about the plan
@@ -80,6 +91,121 @@ func (a *Analyzer) GetExecutionPlan() ExecutionPlan {
 	}
 }
 
+// AnnotatePlanSlack tags every plan item with its slack (as reported by
+// GraphStats.Slack) and fills plan.Schedule: zero-slack items go to do_first,
+// items with slack to deferrable, and items that neither depend on nor block
+// another actionable item (directly or through other issues) to parallel_safe.
+// Does nothing when slack is nil, e.g. when Phase 2 did not finish.
+func (a *Analyzer) AnnotatePlanSlack(plan *ExecutionPlan, slack map[string]float64) {
+	if plan == nil || slack == nil {
+		return
+	}
+
+	schedule := &PlanSchedule{
+		DoFirst:      []string{},
+		Deferrable:   []string{},
+		ParallelSafe: []string{},
+	}
+	var items []*PlanItem
+	actionable := make(map[int64]bool)
+	for t := range plan.Tracks {
+		for i := range plan.Tracks[t].Items {
+			item := &plan.Tracks[t].Items[i]
+			items = append(items, item)
+			if nodeID, ok := a.idToNode[item.ID]; ok {
+				actionable[nodeID] = true
+			}
+		}
+	}
+
+	// An item is unsafe to parallelize when another actionable item is
+	// reachable from it along dependencies, or it is reachable from one.
+	dependsOnActionable := a.reachesActionable(actionable, func(id int64) graph.Nodes { return a.g.From(id) })
+	blocksActionable := a.reachesActionable(actionable, func(id int64) graph.Nodes { return a.g.To(id) })
+
+	for _, item := range items {
+		s, ok := slack[item.ID]
+		if !ok {
+			continue
+		}
+		item.Slack = &s
+		if s == 0 {
+			schedule.DoFirst = append(schedule.DoFirst, item.ID)
+		} else {
+			schedule.Deferrable = append(schedule.Deferrable, item.ID)
+		}
+	}
+	for _, item := range items {
+		nodeID, ok := a.idToNode[item.ID]
+		if ok && !dependsOnActionable(nodeID) && !blocksActionable(nodeID) {
+			schedule.ParallelSafe = append(schedule.ParallelSafe, item.ID)
+		}
+	}
+
+	byID := make(map[string]*PlanItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	sort.Slice(schedule.DoFirst, func(i, j int) bool {
+		x, y := byID[schedule.DoFirst[i]], byID[schedule.DoFirst[j]]
+		if x.Priority != y.Priority {
+			return x.Priority < y.Priority
+		}
+		return x.ID < y.ID
+	})
+	sort.Slice(schedule.Deferrable, func(i, j int) bool {
+		x, y := byID[schedule.Deferrable[i]], byID[schedule.Deferrable[j]]
+		if *x.Slack != *y.Slack {
+			return *x.Slack < *y.Slack
+		}
+		if x.Priority != y.Priority {
+			return x.Priority < y.Priority
+		}
+		return x.ID < y.ID
+	})
+	sort.Strings(schedule.ParallelSafe)
+
+	plan.Schedule = schedule
+}
+
+// reachesActionable returns a memoized predicate reporting whether an
+// actionable node can be reached from the given node (excluding itself) by
+// following next. Nodes on a cycle that is still being explored count as not
+// reaching, which keeps the walk linear.
+func (a *Analyzer) reachesActionable(actionable map[int64]bool, next func(int64) graph.Nodes) func(int64) bool {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[int64]int)
+	reaches := make(map[int64]bool)
+
+	var visit func(id int64) bool
+	visit = func(id int64) bool {
+		switch state[id] {
+		case visiting:
+			return false
+		case done:
+			return reaches[id]
+		}
+		state[id] = visiting
+		found := false
+		nodes := next(id)
+		for nodes.Next() {
+			n := nodes.Node().ID()
+			if actionable[n] || visit(n) {
+				found = true
+				break
+			}
+		}
+		state[id] = done
+		reaches[id] = found
+		return found
+	}
+	return visit
+}
+
 // computeUnblocks finds issues that would become actionable if the given issue is closed
 func (a *Analyzer) computeUnblocks(issueID string) []string {
 	var unblocks []string
@@ -470,7 +470,7 @@ func TestGetExecutionPlanLegacyDependencyGrouping(t *testing.T) {
 	// Since A depends on B, they form a connected component.
 	// Therefore, B should appear in a track that represents this component.
 	// We verify that the tracks logic respects this legacy dependency grouping.
-	
+
 	// Scenario:
 	// X -> A (legacy). X -> B (legacy).
 	// X is the common ancestor/dependent.
@@ -483,7 +483,7 @@ func TestGetExecutionPlanLegacyDependencyGrouping(t *testing.T) {
 	// We get 1 track with {A, B}.
 	// If connection logic FAILS (ignoring legacy), we get {A}, {B}, {X}.
 	// We get 2 tracks: Track 1 {A}, Track 2 {B}.
-	
+
 	issues := []model.Issue{
 		{ID: "X", Title: "Common Root", Status: model.StatusClosed, Priority: 1},
 		{ID: "A", Title: "Task A", Status: model.StatusOpen, Priority: 1, Dependencies: []*model.Dependency{
@@ -502,4 +502,99 @@ func TestGetExecutionPlanLegacyDependencyGrouping(t *testing.T) {
 	if len(plan.Tracks) != 1 {
 		t.Errorf("Expected 1 track (grouped via legacy dependency), got %d tracks", len(plan.Tracks))
 	}
-}
\ No newline at end of file
+}
+
+func TestAnnotatePlanSlackDiamond(t *testing.T) {
+	// Diamond with the root done: A (closed) <- B, C <- D.
+	// B and C are ready at the same time and neither waits on the other.
+	issues := []model.Issue{
+		{ID: "A", Title: "Root", Status: model.StatusClosed},
+		{ID: "B", Title: "Left", Status: model.StatusOpen, Priority: 2, Dependencies: []*model.Dependency{
+			{DependsOnID: "A", Type: model.DepBlocks},
+		}},
+		{ID: "C", Title: "Right", Status: model.StatusOpen, Priority: 1, Dependencies: []*model.Dependency{
+			{DependsOnID: "A", Type: model.DepBlocks},
+		}},
+		{ID: "D", Title: "Join", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{DependsOnID: "B", Type: model.DepBlocks},
+			{DependsOnID: "C", Type: model.DepBlocks},
+		}},
+	}
+
+	an := analysis.NewAnalyzer(issues)
+	plan := an.GetExecutionPlan()
+	stats := an.Analyze()
+	an.AnnotatePlanSlack(&plan, stats.Slack())
+
+	if plan.Schedule == nil {
+		t.Fatal("expected schedule to be set")
+	}
+	if got := plan.Schedule.ParallelSafe; len(got) != 2 || got[0] != "B" || got[1] != "C" {
+		t.Errorf("expected middle branches [B C] to be parallel-safe, got %v", got)
+	}
+	// Both branches lie on a longest path, so they are critical: C first by priority.
+	if got := plan.Schedule.DoFirst; len(got) != 2 || got[0] != "C" || got[1] != "B" {
+		t.Errorf("expected do_first [C B], got %v", got)
+	}
+	if len(plan.Schedule.Deferrable) != 0 {
+		t.Errorf("expected nothing deferrable, got %v", plan.Schedule.Deferrable)
+	}
+	for _, track := range plan.Tracks {
+		for _, item := range track.Items {
+			if item.Slack == nil || *item.Slack != 0 {
+				t.Errorf("expected %s to be tagged with slack 0, got %v", item.ID, item.Slack)
+			}
+		}
+	}
+}
+
+func TestAnnotatePlanSlackDeferrableAndConflicts(t *testing.T) {
+	// A (closed) <- B <- C <- E and A <- D <- E: D has one step of slack.
+	// X is ready, but closed Z depends on it and ready Y depends on Z, so
+	// X and Y must not be worked at the same time.
+	issues := []model.Issue{
+		{ID: "A", Status: model.StatusClosed},
+		{ID: "B", Status: model.StatusOpen, Dependencies: []*model.Dependency{{DependsOnID: "A", Type: model.DepBlocks}}},
+		{ID: "C", Status: model.StatusOpen, Dependencies: []*model.Dependency{{DependsOnID: "B", Type: model.DepBlocks}}},
+		{ID: "D", Status: model.StatusOpen, Dependencies: []*model.Dependency{{DependsOnID: "A", Type: model.DepBlocks}}},
+		{ID: "E", Status: model.StatusOpen, Dependencies: []*model.Dependency{{DependsOnID: "C", Type: model.DepBlocks}, {DependsOnID: "D", Type: model.DepBlocks}}},
+		{ID: "X", Status: model.StatusOpen},
+		{ID: "Z", Status: model.StatusClosed, Dependencies: []*model.Dependency{{DependsOnID: "X", Type: model.DepBlocks}}},
+		{ID: "Y", Status: model.StatusOpen, Dependencies: []*model.Dependency{{DependsOnID: "Z", Type: model.DepBlocks}}},
+	}
+
+	an := analysis.NewAnalyzer(issues)
+	plan := an.GetExecutionPlan()
+	stats := an.Analyze()
+	an.AnnotatePlanSlack(&plan, stats.Slack())
+
+	if plan.Schedule == nil {
+		t.Fatal("expected schedule to be set")
+	}
+	if got := plan.Schedule.DoFirst; len(got) != 1 || got[0] != "B" {
+		t.Errorf("expected do_first [B], got %v", got)
+	}
+	deferrable := map[string]bool{}
+	for _, id := range plan.Schedule.Deferrable {
+		deferrable[id] = true
+	}
+	if !deferrable["D"] || deferrable["B"] {
+		t.Errorf("expected D (slack 1) deferrable and B not, got %v", plan.Schedule.Deferrable)
+	}
+	if got := plan.Schedule.ParallelSafe; len(got) != 2 || got[0] != "B" || got[1] != "D" {
+		t.Errorf("expected parallel_safe [B D] (X and Y conflict), got %v", got)
+	}
+}
+
+func TestAnnotatePlanSlackWithoutSlack(t *testing.T) {
+	an := analysis.NewAnalyzer([]model.Issue{{ID: "A", Status: model.StatusOpen}})
+	plan := an.GetExecutionPlan()
+	an.AnnotatePlanSlack(&plan, nil)
+
+	if plan.Schedule != nil {
+		t.Error("expected no schedule without slack data")
+	}
+	if plan.Tracks[0].Items[0].Slack != nil {
+		t.Error("expected no slack tag without slack data")
+	}
+}
@@ -41,6 +41,10 @@ type ScoreBreakdown struct {
 	UrgencyNorm       float64 `json:"urgency_norm"`
 	RiskNorm          float64 `json:"risk_norm"`
 
+	// Unnormalized centrality scores, cited as reason-code evidence
+	pageRankRaw    float64
+	betweennessRaw float64
+
 	// Explanation text for signals
 	TimeToImpactExplanation string `json:"time_to_impact_explanation,omitempty"`
 	UrgencyExplanation      string `json:"urgency_explanation,omitempty"`
@@ -182,6 +186,9 @@ func (a *Analyzer) ComputeImpactScoresFromStats(stats *GraphStats, now time.Time
 			UrgencyNorm:       urgencyNorm,
 			RiskNorm:          riskSignals.CompositeRisk,
 
+			pageRankRaw:    pageRank[id],
+			betweennessRaw: betweenness[id],
+
 			TimeToImpactExplanation: timeToImpactExplanation,
 			UrgencyExplanation:      urgencyExplanation,
 			RiskExplanation:         riskSignals.Explanation,
@@ -478,10 +485,63 @@ type PriorityRecommendation struct {
 	ImpactScore       float64      `json:"impact_score"`
 	Confidence        float64      `json:"confidence"`        // 0-1, higher when evidence is strong
 	Reasoning         []string     `json:"reasoning"`         // Human-readable explanations (top 3)
+	Reasons           []ReasonCode `json:"reasons,omitempty"` // Machine-readable signals with their evidence
 	Direction         string       `json:"direction"`         // "increase" or "decrease"
 	WhatIf            *WhatIfDelta `json:"what_if,omitempty"` // Impact of completing this issue
 }
 
+// Reason codes explain a PriorityRecommendation without prose, so agents can
+// act on them directly. The human-readable Reasoning is derived from the same
+// signals.
+const (
+	ReasonHighPageRank          = "HIGH_PAGERANK"            // evidence: pagerank (raw), pagerank_norm
+	ReasonHighBetweenness       = "HIGH_BETWEENNESS"         // evidence: betweenness (raw), betweenness_norm
+	ReasonHighDependentCount    = "HIGH_DEPENDENT_COUNT"     // evidence: dependent_count (3 or more)
+	ReasonHasDependents         = "HAS_DEPENDENTS"           // evidence: dependent_count (1 or 2)
+	ReasonStale                 = "STALE"                    // evidence: days_stale
+	ReasonTimeToImpact          = "TIME_TO_IMPACT"           // evidence: time_to_impact
+	ReasonUrgency               = "URGENCY"                  // evidence: urgency
+	ReasonHighRisk              = "HIGH_RISK"                // evidence: risk
+	ReasonArticulationPoint     = "ARTICULATION_POINT"       // no evidence
+	ReasonHighCohesion          = "HIGH_COHESION"            // evidence: k_core
+	ReasonOnCriticalPath        = "ON_CRITICAL_PATH"         // evidence: slack (always 0)
+	ReasonParallelFriendly      = "PARALLEL_FRIENDLY"        // evidence: slack
+	ReasonPrioritySet           = "PRIORITY_SET"             // evidence: current_priority (impact-only entries)
+	ReasonNoDependents          = "NO_DEPENDENTS"            // demotions; evidence: dependent_count (always 0)
+	ReasonLowCentrality         = "LOW_CENTRALITY"           // demotions; evidence: as HIGH_PAGERANK and HIGH_BETWEENNESS
+	ReasonLowPriorityHighImpact = "LOW_PRIORITY_HIGH_IMPACT" // direction "increase"; see below
+	ReasonHighPriorityLowImpact = "HIGH_PRIORITY_LOW_IMPACT" // direction "decrease"; see below
+)
+
+// ReasonCode is one machine-readable signal behind a recommendation. The
+// LOW_PRIORITY_HIGH_IMPACT / HIGH_PRIORITY_LOW_IMPACT summary codes come first
+// and carry impact_score, current_priority, suggested_priority and
+// dependent_count; the rest follow in the same order as Reasoning (uncapped).
+type ReasonCode struct {
+	Code     string             `json:"code"`
+	Evidence map[string]float64 `json:"evidence,omitempty"`
+}
+
+// pageRankEvidence cites the raw PageRank score, plus the normalized value
+// the recommendation thresholds compare against.
+func (b ScoreBreakdown) pageRankEvidence() map[string]float64 {
+	return map[string]float64{"pagerank": b.pageRankRaw, "pagerank_norm": b.PageRankNorm}
+}
+
+// betweennessEvidence is pageRankEvidence for betweenness.
+func (b ScoreBreakdown) betweennessEvidence() map[string]float64 {
+	return map[string]float64{"betweenness": b.betweennessRaw, "betweenness_norm": b.BetweennessNorm}
+}
+
+// centralityEvidence combines pageRankEvidence and betweennessEvidence.
+func (b ScoreBreakdown) centralityEvidence() map[string]float64 {
+	evidence := b.pageRankEvidence()
+	for k, v := range b.betweennessEvidence() {
+		evidence[k] = v
+	}
+	return evidence
+}
+
 // RecommendationThresholds configure when to suggest priority changes
 type RecommendationThresholds struct {
 	HighPageRank     float64 // Normalized PageRank above this suggests high priority
@@ -556,8 +616,12 @@ func (a *Analyzer) GenerateRecommendationsWithThresholds(thresholds Recommendati
 		}
 	}
 
-	// Sort by confidence descending, then by impact score, then by ID for determinism (bv-83)
+	// Promotions before demotions; within each, by confidence descending, then
+	// by impact score, then by ID for determinism (bv-83)
 	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Direction != recommendations[j].Direction {
+			return directionRank(recommendations[i].Direction) < directionRank(recommendations[j].Direction)
+		}
 		if recommendations[i].Confidence != recommendations[j].Confidence {
 			return recommendations[i].Confidence > recommendations[j].Confidence
 		}
@@ -570,101 +634,115 @@ func (a *Analyzer) GenerateRecommendationsWithThresholds(thresholds Recommendati
 	return recommendations
 }
 
+// directionRank orders recommendation directions for output: promotions, then
+// demotions, then impact-only entries with no priority change.
+func directionRank(direction string) int {
+	switch direction {
+	case "increase":
+		return 0
+	case "decrease":
+		return 1
+	default:
+		return 2
+	}
+}
+
 // generateRecommendation creates a recommendation for a single issue
 func generateRecommendation(score ImpactScore, unblocksCount int, core int, isArt bool, slack float64, maxCore int, thresholds RecommendationThresholds) *PriorityRecommendation {
+	// Calculate suggested priority based on impact score
+	suggestedPriority := scoreToPriority(score.Score)
+	if suggestedPriority > score.Priority {
+		if rec := generateDemotion(score, suggestedPriority, unblocksCount, thresholds); rec != nil {
+			return rec
+		}
+	}
+
 	var reasoning []string
+	var reasons []ReasonCode
 	var signals int
 	var signalStrength float64
+	addReason := func(code, text string, evidence map[string]float64) {
+		reasoning = append(reasoning, text)
+		reasons = append(reasons, ReasonCode{Code: code, Evidence: evidence})
+		signals++
+	}
 
 	// Check PageRank (fundamental dependency)
 	if score.Breakdown.PageRankNorm > thresholds.HighPageRank {
-		reasoning = append(reasoning, "High centrality in dependency graph")
-		signals++
+		addReason(ReasonHighPageRank, "High centrality in dependency graph", score.Breakdown.pageRankEvidence())
 		signalStrength += score.Breakdown.PageRankNorm
 	}
 
 	// Check Betweenness (bottleneck)
 	if score.Breakdown.BetweennessNorm > thresholds.HighBetweenness {
-		reasoning = append(reasoning, "Critical path bottleneck")
-		signals++
+		addReason(ReasonHighBetweenness, "Critical path bottleneck", score.Breakdown.betweennessEvidence())
 		signalStrength += score.Breakdown.BetweennessNorm
 	}
 
 	// Check unblocks count
 	if unblocksCount >= 3 {
-		reasoning = append(reasoning, fmt.Sprintf("Blocks %d other items", unblocksCount))
-		signals++
+		addReason(ReasonHighDependentCount, fmt.Sprintf("Blocks %d other items", unblocksCount), map[string]float64{"dependent_count": float64(unblocksCount)})
 		signalStrength += 0.5 + float64(unblocksCount)/10.0
 	} else if unblocksCount == 2 {
-		reasoning = append(reasoning, "Blocks 2 other items")
-		signals++
+		addReason(ReasonHasDependents, "Blocks 2 other items", map[string]float64{"dependent_count": 2})
 		signalStrength += 0.3
 	} else if unblocksCount == 1 {
-		reasoning = append(reasoning, "Blocks 1 other item")
-		signals++
+		addReason(ReasonHasDependents, "Blocks 1 other item", map[string]float64{"dependent_count": 1})
 		signalStrength += 0.2
 	}
 
 	// Check staleness
 	if score.Breakdown.StalenessNorm >= float64(thresholds.StalenessDays)/30.0 {
 		days := int(score.Breakdown.StalenessNorm * 30)
-		reasoning = append(reasoning, fmt.Sprintf("Stale for %d+ days", days))
-		signals++
+		addReason(ReasonStale, fmt.Sprintf("Stale for %d+ days", days), map[string]float64{"days_stale": float64(days)})
 		signalStrength += 0.2
 	}
 
 	// Check time-to-impact signal
 	if score.Breakdown.TimeToImpactNorm > 0.5 {
+		text := "High time-to-impact score"
 		if score.Breakdown.TimeToImpactExplanation != "" {
-			reasoning = append(reasoning, score.Breakdown.TimeToImpactExplanation)
-		} else {
-			reasoning = append(reasoning, "High time-to-impact score")
+			text = score.Breakdown.TimeToImpactExplanation
 		}
-		signals++
+		addReason(ReasonTimeToImpact, text, map[string]float64{"time_to_impact": score.Breakdown.TimeToImpactNorm})
 		signalStrength += score.Breakdown.TimeToImpactNorm
 	}
 
 	// Check urgency signal
 	if score.Breakdown.UrgencyNorm > 0.3 {
+		text := "Elevated urgency"
 		if score.Breakdown.UrgencyExplanation != "" {
-			reasoning = append(reasoning, score.Breakdown.UrgencyExplanation)
-		} else {
-			reasoning = append(reasoning, "Elevated urgency")
+			text = score.Breakdown.UrgencyExplanation
 		}
-		signals++
+		addReason(ReasonUrgency, text, map[string]float64{"urgency": score.Breakdown.UrgencyNorm})
 		signalStrength += score.Breakdown.UrgencyNorm
 	}
 
 	// Check risk signal (bv-82)
 	if score.Breakdown.RiskNorm > 0.4 {
+		text := "Elevated risk/volatility"
 		if score.Breakdown.RiskExplanation != "" {
-			reasoning = append(reasoning, score.Breakdown.RiskExplanation)
-		} else {
-			reasoning = append(reasoning, "Elevated risk/volatility")
+			text = score.Breakdown.RiskExplanation
 		}
-		signals++
+		addReason(ReasonHighRisk, text, map[string]float64{"risk": score.Breakdown.RiskNorm})
 		signalStrength += score.Breakdown.RiskNorm
 	}
 
 	// Structural signals (bv-85)
 	if isArt {
-		reasoning = append(reasoning, "Articulation point (disconnects graph)")
-		signals++
+		addReason(ReasonArticulationPoint, "Articulation point (disconnects graph)", nil)
 		signalStrength += 0.35
 	}
 	if maxCore > 0 && core == maxCore {
-		reasoning = append(reasoning, fmt.Sprintf("High cohesion (k-core %d)", core))
-		signals++
+		addReason(ReasonHighCohesion, fmt.Sprintf("High cohesion (k-core %d)", core), map[string]float64{"k_core": float64(core)})
 		signalStrength += 0.3
 	}
 	if slack == 0 {
-		reasoning = append(reasoning, "Zero slack on critical chain")
-		signals++
+		addReason(ReasonOnCriticalPath, "Zero slack on critical chain", map[string]float64{"slack": 0})
 		signalStrength += 0.25
 	} else if slack > 2 {
 		// Parallel-friendly; softer weight so it doesn't overshadow bottlenecks
-		reasoning = append(reasoning, "Parallel-friendly (slack available)")
-		signals++
+		addReason(ReasonParallelFriendly, "Parallel-friendly (slack available)", map[string]float64{"slack": slack})
 		signalStrength += 0.15
 	}
 
@@ -673,9 +751,6 @@ func generateRecommendation(score ImpactScore, unblocksCount int, core int, isAr
 		return nil
 	}
 
-	// Calculate suggested priority based on impact score
-	suggestedPriority := scoreToPriority(score.Score)
-
 	// If no change suggested, skip
 	if suggestedPriority == score.Priority {
 		return nil
@@ -686,9 +761,12 @@ func generateRecommendation(score ImpactScore, unblocksCount int, core int, isAr
 	confidence := calculateConfidence(signals, signalStrength, scoreDelta, thresholds)
 
 	direction := "increase"
+	summaryCode := ReasonLowPriorityHighImpact
 	if suggestedPriority > score.Priority {
 		direction = "decrease"
+		summaryCode = ReasonHighPriorityLowImpact
 	}
+	reasons = append([]ReasonCode{summaryReason(summaryCode, score, suggestedPriority, unblocksCount)}, reasons...)
 
 	// Cap reasoning at top 3 for conciseness (bv-83)
 	if len(reasoning) > 3 {
@@ -703,10 +781,63 @@ func generateRecommendation(score ImpactScore, unblocksCount int, core int, isAr
 		ImpactScore:       score.Score,
 		Confidence:        confidence,
 		Reasoning:         reasoning,
+		Reasons:           reasons,
 		Direction:         direction,
 	}
 }
 
+// MaxDemotablePriority is the lowest priority number considered for demotion;
+// suggesting P3 -> P4 for every quiet leaf would only be noise.
+const MaxDemotablePriority = 1
+
+// generateDemotion suggests lowering a high-priority leaf: a bead whose impact
+// score maps to a lower priority, that nothing depends on and that is not
+// central. Such beads trip none of the signals above, so they get their own.
+// Returns nil when the bead is not such a leaf.
+func generateDemotion(score ImpactScore, suggestedPriority, unblocksCount int, thresholds RecommendationThresholds) *PriorityRecommendation {
+	if score.Priority > MaxDemotablePriority || unblocksCount > 0 {
+		return nil
+	}
+	if score.Breakdown.PageRankNorm > thresholds.HighPageRank || score.Breakdown.BetweennessNorm > thresholds.HighBetweenness {
+		return nil
+	}
+
+	reasoning := []string{
+		"Nothing depends on it",
+		fmt.Sprintf("Low centrality (PageRank %.2f)", score.Breakdown.PageRankNorm),
+	}
+	reasons := []ReasonCode{
+		summaryReason(ReasonHighPriorityLowImpact, score, suggestedPriority, unblocksCount),
+		{Code: ReasonNoDependents, Evidence: map[string]float64{"dependent_count": 0}},
+		{Code: ReasonLowCentrality, Evidence: score.Breakdown.centralityEvidence()},
+	}
+	signalStrength := 0.3 + 0.3*(1-score.Breakdown.PageRankNorm)
+	scoreDelta := abs(score.Score - priorityToScore(score.Priority))
+
+	return &PriorityRecommendation{
+		IssueID:           score.IssueID,
+		Title:             score.Title,
+		CurrentPriority:   score.Priority,
+		SuggestedPriority: suggestedPriority,
+		ImpactScore:       score.Score,
+		Confidence:        calculateConfidence(len(reasoning), signalStrength, scoreDelta, thresholds),
+		Reasoning:         reasoning,
+		Reasons:           reasons,
+		Direction:         "decrease",
+	}
+}
+
+// summaryReason builds the leading LOW_PRIORITY_HIGH_IMPACT or
+// HIGH_PRIORITY_LOW_IMPACT code of a recommendation.
+func summaryReason(code string, score ImpactScore, suggestedPriority, unblocksCount int) ReasonCode {
+	return ReasonCode{Code: code, Evidence: map[string]float64{
+		"impact_score":       score.Score,
+		"current_priority":   float64(score.Priority),
+		"suggested_priority": float64(suggestedPriority),
+		"dependent_count":    float64(unblocksCount),
+	}}
+}
+
 // scoreToPriority converts an impact score (0-1) to a priority (0-4)
 func scoreToPriority(score float64) int {
 	switch {
@@ -877,3 +877,139 @@ func TestParallelizationGainZero(t *testing.T) {
 		t.Errorf("Expected ParallelizationGain=%d, got %d", expectedGain, *recA.WhatIf.ParallelizationGain)
 	}
 }
+
+func TestRecommendationReasonCodes(t *testing.T) {
+	// A P2 root that the rest of the backlog waits on should be pushed to P0,
+	// with a structured code carrying the evidence rather than only prose.
+	issues := []model.Issue{
+		{ID: "root", Title: "Root", Status: model.StatusOpen, Priority: 2, Labels: []string{"urgent", "critical"}, UpdatedAt: time.Now().AddDate(0, 0, -45),
+			Dependencies: []*model.Dependency{{IssueID: "root", DependsOnID: "setup", Type: model.DepBlocks}}},
+		{ID: "setup", Title: "Setup", Status: model.StatusOpen, Priority: 2},
+	}
+	for _, id := range []string{"d1", "d2", "d3", "d4"} {
+		issues = append(issues, model.Issue{ID: id, Title: id, Status: model.StatusOpen, Priority: 2, Dependencies: []*model.Dependency{
+			{IssueID: id, DependsOnID: "root", Type: model.DepBlocks},
+		}})
+	}
+
+	an := analysis.NewAnalyzer(issues)
+	var rec *analysis.PriorityRecommendation
+	for _, r := range an.GenerateRecommendations() {
+		if r.IssueID == "root" {
+			rec = &r
+			break
+		}
+	}
+	if rec == nil {
+		t.Fatal("Expected a recommendation for root")
+	}
+	if rec.SuggestedPriority != 0 {
+		t.Fatalf("Expected root to be suggested as P0, got P%d (score %.2f)", rec.SuggestedPriority, rec.ImpactScore)
+	}
+	if len(rec.Reasons) == 0 || rec.Reasons[0].Code != analysis.ReasonLowPriorityHighImpact {
+		t.Fatalf("Expected %s first, got %+v", analysis.ReasonLowPriorityHighImpact, rec.Reasons)
+	}
+	if got := rec.Reasons[0].Evidence["dependent_count"]; got != 4 {
+		t.Errorf("Expected dependent_count 4, got %v", got)
+	}
+	if got := rec.Reasons[0].Evidence["current_priority"]; got != 2 {
+		t.Errorf("Expected current_priority 2, got %v", got)
+	}
+
+	found := false
+	for _, r := range rec.Reasons[1:] {
+		if r.Code == analysis.ReasonHighDependentCount {
+			found = r.Evidence["dependent_count"] == 4
+		}
+	}
+	if !found {
+		t.Errorf("Expected %s with dependent_count 4, got %+v", analysis.ReasonHighDependentCount, rec.Reasons)
+	}
+	stats := an.Analyze()
+	var pageRankReason *analysis.ReasonCode
+	for i, r := range rec.Reasons {
+		if r.Code == analysis.ReasonHighPageRank {
+			pageRankReason = &rec.Reasons[i]
+		}
+	}
+	if pageRankReason == nil {
+		t.Errorf("Expected %s, got %+v", analysis.ReasonHighPageRank, rec.Reasons)
+	} else if pr := stats.PageRank()["root"]; pageRankReason.Evidence["pagerank"] != pr || pageRankReason.Evidence["pagerank_norm"] <= pr {
+		t.Errorf("Expected %s to cite the raw PageRank %v and its normalized value, got %+v", analysis.ReasonHighPageRank, pr, pageRankReason.Evidence)
+	}
+	if len(rec.Reasons)-1 < len(rec.Reasoning) {
+		t.Errorf("Expected a code for every reasoning line: %+v vs %v", rec.Reasons, rec.Reasoning)
+	}
+
+	// --robot-priority goes through the enhanced recommendations
+	for _, enhanced := range an.GenerateEnhancedRecommendations() {
+		if len(enhanced.Reasons) == 0 {
+			t.Errorf("Expected reason codes for %s (direction %s)", enhanced.IssueID, enhanced.Direction)
+		}
+	}
+}
+
+func TestRecommendationDemotesHighPriorityLeaf(t *testing.T) {
+	// "leaf" is P0 but nothing depends on it; "core" is what everything waits on.
+	issues := []model.Issue{
+		{ID: "core", Title: "Core", Status: model.StatusOpen, Priority: 2},
+		{ID: "leaf", Title: "Leaf", Status: model.StatusOpen, Priority: 0, Dependencies: []*model.Dependency{
+			{IssueID: "leaf", DependsOnID: "core", Type: model.DepBlocks},
+		}},
+	}
+	for _, id := range []string{"o1", "o2", "o3"} {
+		issues = append(issues, model.Issue{ID: id, Title: id, Status: model.StatusOpen, Priority: 2, Dependencies: []*model.Dependency{
+			{IssueID: id, DependsOnID: "core", Type: model.DepBlocks},
+		}})
+	}
+
+	an := analysis.NewAnalyzer(issues)
+	recs := an.GenerateRecommendations()
+
+	var leaf *analysis.PriorityRecommendation
+	for i := range recs {
+		if recs[i].IssueID == "leaf" {
+			leaf = &recs[i]
+		}
+	}
+	if leaf == nil {
+		t.Fatalf("Expected a demotion for the P0 leaf, got %+v", recs)
+	}
+	if leaf.Direction != "decrease" || leaf.SuggestedPriority <= leaf.CurrentPriority {
+		t.Errorf("Expected a decrease from P0, got %s to P%d", leaf.Direction, leaf.SuggestedPriority)
+	}
+	if leaf.Confidence < analysis.DefaultThresholds().MinConfidence {
+		t.Errorf("Expected a usable confidence, got %f", leaf.Confidence)
+	}
+	if len(leaf.Reasons) < 2 || leaf.Reasons[0].Code != analysis.ReasonHighPriorityLowImpact || leaf.Reasons[1].Code != analysis.ReasonNoDependents {
+		t.Errorf("Unexpected demotion reasons: %+v", leaf.Reasons)
+	}
+
+	// Promotions come first, demotions after them
+	for _, list := range [][]string{directions(recs), enhancedDirections(an.GenerateEnhancedRecommendations())} {
+		seenDecrease := false
+		for _, d := range list {
+			if d == "decrease" {
+				seenDecrease = true
+			} else if d == "increase" && seenDecrease {
+				t.Errorf("Promotion listed after a demotion: %v", list)
+			}
+		}
+	}
+}
+
+func directions(recs []analysis.PriorityRecommendation) []string {
+	out := make([]string, len(recs))
+	for i, r := range recs {
+		out[i] = r.Direction
+	}
+	return out
+}
+
+func enhancedDirections(recs []analysis.EnhancedPriorityRecommendation) []string {
+	out := make([]string, len(recs))
+	for i, r := range recs {
+		out[i] = r.Direction
+	}
+	return out
+}
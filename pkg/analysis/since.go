@@ -0,0 +1,134 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
+)
+
+// SinceWindow describes a --since time window applied to robot output.
+// Only beads created or updated at or after Cutoff are reported; graph
+// metrics and blocker relationships are still computed on the full set.
+type SinceWindow struct {
+	Spec     string    `json:"spec"`     // Raw --since value (e.g. "72h", "2024-01-01")
+	Cutoff   time.Time `json:"cutoff"`   // Start of the window (inclusive)
+	Included int       `json:"included"` // Beads touched within the window
+	Excluded int       `json:"excluded"` // Beads outside the window
+}
+
+// ParseSince converts a --since value to a cutoff time. It accepts Go
+// durations ("72h", "90m"), day/week/month/year shorthands ("3d", "2w", "1y";
+// note "1m" is one minute, as a Go duration), and dates ("2024-01-01" or
+// RFC3339).
+func ParseSince(spec string, now time.Time) (time.Time, error) {
+	return ParseWindow("--since", spec, now)
+}
+
+// ParseWindow is ParseSince for any window flag; flag names the flag in
+// error messages.
+func ParseWindow(flag, spec string, now time.Time) (time.Time, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return time.Time{}, fmt.Errorf("empty %s value", flag)
+	}
+	if d, err := time.ParseDuration(spec); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("invalid %s %q: duration must be positive", flag, spec)
+		}
+		return now.Add(-d), nil
+	}
+	t, err := recipe.ParseRelativeTime(spec, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q (use a duration like 72h or 3d, or a date like 2024-01-01)", flag, spec)
+	}
+	return t, nil
+}
+
+// TouchedSince reports whether an issue was created or updated at or after cutoff.
+func TouchedSince(issue model.Issue, cutoff time.Time) bool {
+	return !issue.CreatedAt.Before(cutoff) || !issue.UpdatedAt.Before(cutoff)
+}
+
+// ComputeSinceWindow parses spec and returns the window summary together with
+// the set of issue IDs inside it.
+func ComputeSinceWindow(issues []model.Issue, spec string, now time.Time) (SinceWindow, map[string]bool, error) {
+	cutoff, err := ParseSince(spec, now)
+	if err != nil {
+		return SinceWindow{}, nil, err
+	}
+
+	window := SinceWindow{Spec: spec, Cutoff: cutoff.UTC()}
+	ids := make(map[string]bool)
+	for _, issue := range issues {
+		if TouchedSince(issue, cutoff) {
+			ids[issue.ID] = true
+			window.Included++
+		} else {
+			window.Excluded++
+		}
+	}
+	return window, ids, nil
+}
+
+// PriorityRange is the --priority-min/--priority-max band reported beads must
+// fall in. Like SinceWindow it narrows what is reported, not what is analysed.
+type PriorityRange struct {
+	Min      *int `json:"min,omitempty"` // Lowest priority number included (nil = unbounded)
+	Max      *int `json:"max,omitempty"` // Highest priority number included (nil = unbounded)
+	Included int  `json:"included"`      // Beads within the range
+	Excluded int  `json:"excluded"`      // Beads outside it
+}
+
+// ComputePriorityRange returns the range summary together with the set of
+// issue IDs inside it. A negative min or max leaves that end unbounded.
+func ComputePriorityRange(issues []model.Issue, min, max int) (PriorityRange, map[string]bool, error) {
+	var pr PriorityRange
+	if min >= 0 {
+		pr.Min = &min
+	}
+	if max >= 0 {
+		pr.Max = &max
+	}
+	if pr.Min != nil && pr.Max != nil && min > max {
+		return PriorityRange{}, nil, fmt.Errorf("invalid priority range: --priority-min %d is above --priority-max %d", min, max)
+	}
+
+	ids := make(map[string]bool)
+	for _, issue := range issues {
+		if (pr.Min == nil || issue.Priority >= min) && (pr.Max == nil || issue.Priority <= max) {
+			ids[issue.ID] = true
+			pr.Included++
+		} else {
+			pr.Excluded++
+		}
+	}
+	return pr, ids, nil
+}
+
+// IntersectScopes combines report scopes such as the --since window and the
+// priority range. A nil scope is unrestricted; the result is nil only when
+// every scope is.
+func IntersectScopes(scopes ...map[string]bool) map[string]bool {
+	var out map[string]bool
+	for _, scope := range scopes {
+		if scope == nil {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]bool, len(scope))
+			for id := range scope {
+				out[id] = true
+			}
+			continue
+		}
+		for id := range out {
+			if !scope[id] {
+				delete(out, id)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,123 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		spec    string
+		want    time.Time
+		wantErr bool
+	}{
+		{"72h", now.Add(-72 * time.Hour), false},
+		{"30m", now.Add(-30 * time.Minute), false},
+		{"3d", now.AddDate(0, 0, -3), false},
+		{"2w", now.AddDate(0, 0, -14), false},
+		{"2024-01-01", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"-5h", time.Time{}, true},
+		{"last tuesday", time.Time{}, true},
+		{"", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSince(tt.spec, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSince(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !got.Equal(tt.want) {
+			t.Errorf("ParseSince(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParseWindow_NamesFlag(t *testing.T) {
+	_, err := ParseWindow("--closed-window", "soon", time.Now())
+	if err == nil || err.Error() != `invalid --closed-window "soon" (use a duration like 72h or 3d, or a date like 2024-01-01)` {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestSinceWindow_FiltersOldBeadsButKeepsFullGraph(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, -2, 0)
+	recent := now.Add(-24 * time.Hour)
+
+	issues := []model.Issue{
+		// Old, untouched blocker: outside the window
+		{ID: "old", Title: "Old blocker", Status: model.StatusOpen, IssueType: model.TypeTask, CreatedAt: old, UpdatedAt: old},
+		// Old but recently updated: inside the window
+		{ID: "touched", Title: "Touched", Status: model.StatusOpen, IssueType: model.TypeTask, CreatedAt: old, UpdatedAt: recent},
+		// Recent bead blocked by the old one
+		{ID: "recent", Title: "Recent", Status: model.StatusOpen, IssueType: model.TypeTask, CreatedAt: recent, UpdatedAt: recent,
+			Dependencies: []*model.Dependency{{IssueID: "recent", DependsOnID: "old", Type: model.DepBlocks}}},
+	}
+
+	window, ids, err := ComputeSinceWindow(issues, "72h", now)
+	if err != nil {
+		t.Fatalf("ComputeSinceWindow: %v", err)
+	}
+	if window.Included != 2 || window.Excluded != 1 {
+		t.Fatalf("expected 2 included / 1 excluded, got %+v", window)
+	}
+	if ids["old"] || !ids["touched"] || !ids["recent"] {
+		t.Fatalf("unexpected window membership: %v", ids)
+	}
+
+	triage := ComputeTriageWithOptionsAndTime(issues, TriageOptions{WaitForPhase2: true, ScopeIDs: ids}, now)
+	for _, rec := range triage.Recommendations {
+		if rec.ID == "old" {
+			t.Error("bead outside the window should not be recommended")
+		}
+		// Blockers are still resolved against the full graph
+		if rec.ID == "recent" && len(rec.BlockedBy) != 1 {
+			t.Errorf("recent should still be blocked by old, got %v", rec.BlockedBy)
+		}
+	}
+	for _, b := range triage.BlockersToClear {
+		if b.ID == "old" {
+			t.Error("blocker outside the window should be omitted from blockers_to_clear")
+		}
+	}
+	if triage.Meta.IssueCount != len(issues) {
+		t.Errorf("triage should still analyze all %d beads, got %d", len(issues), triage.Meta.IssueCount)
+	}
+
+	unscoped := ComputeTriageWithOptionsAndTime(issues, TriageOptions{WaitForPhase2: true}, now)
+	foundOld := false
+	for _, b := range unscoped.BlockersToClear {
+		foundOld = foundOld || b.ID == "old"
+	}
+	if !foundOld {
+		t.Error("expected old to be a blocker to clear without --since")
+	}
+}
+
+func TestComputePriorityRange_AndIntersectScopes(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "p0", Priority: 0}, {ID: "p1", Priority: 1}, {ID: "p2", Priority: 2}, {ID: "p4", Priority: 4},
+	}
+
+	pr, ids, err := ComputePriorityRange(issues, 1, -1)
+	if err != nil {
+		t.Fatalf("ComputePriorityRange: %v", err)
+	}
+	if pr.Min == nil || *pr.Min != 1 || pr.Max != nil || pr.Included != 3 || pr.Excluded != 1 || ids["p0"] {
+		t.Fatalf("unexpected min-only range %+v / %v", pr, ids)
+	}
+	if _, _, err := ComputePriorityRange(issues, 3, 1); err == nil {
+		t.Error("expected an error when min is above max")
+	}
+
+	scoped := IntersectScopes(nil, ids, map[string]bool{"p1": true, "p0": true})
+	if len(scoped) != 1 || !scoped["p1"] {
+		t.Errorf("expected only p1 in both scopes, got %v", scoped)
+	}
+	if IntersectScopes(nil, nil) != nil {
+		t.Error("all-nil scopes must stay unrestricted (nil)")
+	}
+}
@@ -110,7 +110,16 @@ func GenerateAllSuggestions(issues []model.Issue, config SuggestAllConfig, dataH
 
 	// Sort by confidence (highest first)
 	sort.Slice(filtered, func(i, j int) bool {
-		return filtered[i].Confidence > filtered[j].Confidence
+		if filtered[i].Confidence != filtered[j].Confidence {
+			return filtered[i].Confidence > filtered[j].Confidence
+		}
+		if filtered[i].TargetBead != filtered[j].TargetBead {
+			return filtered[i].TargetBead < filtered[j].TargetBead
+		}
+		if filtered[i].Type != filtered[j].Type {
+			return filtered[i].Type < filtered[j].Type
+		}
+		return filtered[i].RelatedBead < filtered[j].RelatedBead
 	})
 
 	// Apply max limit
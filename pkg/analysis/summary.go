@@ -0,0 +1,76 @@
+package analysis
+
+import (
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// RobotSummary is the single-line heartbeat printed by --robot-summary.
+type RobotSummary struct {
+	Nodes      int    `json:"nodes"`
+	Edges      int    `json:"edges"`
+	Actionable int    `json:"actionable"` // Open beads with no open blockers
+	Blocked    int    `json:"blocked"`    // Open beads waiting on an open blocker
+	Critical   int    `json:"critical"`   // Beads with zero slack (on a longest chain); 0 when cycles exist
+	Cycles     int    `json:"cycles"`
+	DataHash   string `json:"data_hash"`
+}
+
+// SummaryConfig returns the minimal analysis config behind RobotSummary:
+// cycle detection and the O(V+E) critical path/slack pass only. Betweenness,
+// PageRank, HITS and eigenvector centrality are skipped.
+func SummaryConfig() AnalysisConfig {
+	const reason = "not needed for summary"
+	return AnalysisConfig{
+		BetweennessMode:       BetweennessSkip,
+		BetweennessSkipReason: reason,
+		PageRankSkipReason:    reason,
+		HITSSkipReason:        reason,
+
+		ComputeCycles:    true,
+		CyclesTimeout:    500 * time.Millisecond,
+		MaxCyclesToStore: 100,
+
+		ComputeCriticalPath: true,
+	}
+}
+
+// NextConfig returns the analysis config behind --robot-next: cycles and the
+// critical path/slack pass, with betweenness, PageRank, HITS and eigenvector
+// skipped. The pick is then ranked on priority, blockers, unblocks, age and
+// critical path, so it can differ from the --robot-triage top pick.
+func NextConfig() AnalysisConfig {
+	cfg := SummaryConfig()
+	const reason = "not needed for next pick"
+	cfg.BetweennessSkipReason = reason
+	cfg.PageRankSkipReason = reason
+	cfg.HITSSkipReason = reason
+	return cfg
+}
+
+// ComputeRobotSummary builds the --robot-summary heartbeat. Counts come from
+// the degree-only DegreeStats pass; SummaryConfig adds cycles and slack.
+func ComputeRobotSummary(issues []model.Issue, dataHash string) (RobotSummary, MetricStatus) {
+	analyzer := NewAnalyzer(issues)
+	degrees := analyzer.DegreeStats()
+	stats := analyzer.AnalyzeWithConfig(SummaryConfig())
+
+	summary := RobotSummary{
+		Nodes:      degrees.NodeCount,
+		Edges:      degrees.EdgeCount,
+		Actionable: degrees.Actionable,
+		Blocked:    degrees.Blocked,
+		Cycles:     len(stats.Cycles()),
+		DataHash:   dataHash,
+	}
+
+	stats.SlackAll(func(_ string, slack float64) bool {
+		if slack == 0 {
+			summary.Critical++
+		}
+		return true
+	})
+
+	return summary, stats.Status()
+}
@@ -0,0 +1,53 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestComputeRobotSummary_SkipsCentrality(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "a", Status: model.StatusOpen},
+		{ID: "b", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "b", DependsOnID: "a", Type: model.DepBlocks}}},
+		{ID: "c", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "c", DependsOnID: "b", Type: model.DepBlocks}}},
+		{ID: "x", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "x", DependsOnID: "y", Type: model.DepBlocks}}},
+		{ID: "y", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "y", DependsOnID: "x", Type: model.DepBlocks}}},
+		{ID: "done", Status: model.StatusClosed},
+	}
+
+	summary, status := ComputeRobotSummary(issues, "hash")
+	if status.Betweenness.State != "skipped" || status.PageRank.State != "skipped" {
+		t.Errorf("expected centrality to be skipped, got betweenness=%q pagerank=%q", status.Betweenness.State, status.PageRank.State)
+	}
+
+	// Critical stays 0: slack needs a topological order, which the x<->y cycle prevents
+	want := RobotSummary{Nodes: 6, Edges: 4, Actionable: 1, Blocked: 4, Cycles: 1, DataHash: "hash"}
+	if summary != want {
+		t.Errorf("unexpected summary:\n got %+v\nwant %+v", summary, want)
+	}
+
+	chain, _ := ComputeRobotSummary(issues[:3], "")
+	if chain.Critical != 3 {
+		t.Errorf("expected the whole a<-b<-c chain to be critical, got %d", chain.Critical)
+	}
+}
+
+func TestNextConfig_SkipsCentrality(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "a", Status: model.StatusOpen, Priority: 1},
+		{ID: "b", Status: model.StatusOpen, Priority: 2, Dependencies: []*model.Dependency{{IssueID: "b", DependsOnID: "a", Type: model.DepBlocks}}},
+	}
+
+	stats := NewAnalyzer(issues).AnalyzeWithConfig(NextConfig())
+	status := stats.Status()
+	if status.Betweenness.State != "skipped" || status.PageRank.State != "skipped" || status.HITS.State != "skipped" {
+		t.Errorf("expected centrality to be skipped, got betweenness=%q pagerank=%q hits=%q", status.Betweenness.State, status.PageRank.State, status.HITS.State)
+	}
+
+	cfg := NextConfig()
+	triage := ComputeTriageWithOptions(issues, TriageOptions{WaitForPhase2: true, Config: &cfg})
+	if len(triage.QuickRef.TopPicks) == 0 || triage.QuickRef.TopPicks[0].ID != "a" {
+		t.Errorf("expected a as the next pick, got %+v", triage.QuickRef.TopPicks)
+	}
+}
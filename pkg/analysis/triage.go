@@ -8,6 +8,10 @@ import (
 	"time"
 
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	"gonum.org/v1/gonum/graph/flow"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
 )
 
 func isClosedLikeStatus(status model.Status) bool {
@@ -30,6 +34,12 @@ type TriageResult struct {
 	// These allow multiple agents to grab their own top-N without collision
 	RecommendationsByTrack []TrackRecommendationGroup `json:"recommendations_by_track,omitempty"`
 	RecommendationsByLabel []LabelRecommendationGroup `json:"recommendations_by_label,omitempty"`
+
+	// WIP is set when a WIP limit was requested (TriageOptions.WIPLimit > 0)
+	WIP *WIPStatus `json:"wip,omitempty"`
+
+	// RecentlyClosed lists what got done within TriageOptions.RecentlyClosedWindow
+	RecentlyClosed *RecentlyClosed `json:"recently_closed,omitempty"`
 }
 
 // TriageMeta contains metadata about the triage computation
@@ -57,6 +67,8 @@ type TopPick struct {
 	Score    float64  `json:"score"`
 	Reasons  []string `json:"reasons"`
 	Unblocks int      `json:"unblocks"` // How many items this unblocks
+	// UnblockImpact counts direct and cascading unblocks (see Analyzer.UnblockImpact)
+	UnblockImpact int `json:"unblock_impact"`
 }
 
 // Recommendation is an actionable item with full context
@@ -73,6 +85,8 @@ type Recommendation struct {
 	Reasons     []string       `json:"reasons"`
 	UnblocksIDs []string       `json:"unblocks_ids,omitempty"`
 	BlockedBy   []string       `json:"blocked_by,omitempty"`
+	// UnblockImpact counts direct and cascading unblocks (see Analyzer.UnblockImpact)
+	UnblockImpact int `json:"unblock_impact"`
 }
 
 // QuickWin represents a low-effort, high-impact item
@@ -257,6 +271,67 @@ func truncateToMonday(t time.Time) time.Time {
 	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, offset)
 }
 
+// DefaultRecentlyClosedWindow is the triage "what got done" window when
+// TriageOptions.RecentlyClosedWindow is empty
+const DefaultRecentlyClosedWindow = "7d"
+
+// RecentlyClosed summarizes beads closed within a window, newest first, for
+// a sense of momentum alongside the forward-looking picks.
+type RecentlyClosed struct {
+	Window         string               `json:"window"`                            // Window spec, e.g. "7d"
+	Cutoff         time.Time            `json:"cutoff"`                            // Start of the window (inclusive)
+	Count          int                  `json:"count"`                             // len(Items)
+	ByType         map[string]int       `json:"by_type"`                           // Closed count per issue type
+	TotalEstimated *int                 `json:"total_estimated_minutes,omitempty"` // Sum of estimates; nil when no closed bead has one
+	Items          []RecentlyClosedItem `json:"items"`
+}
+
+// RecentlyClosedItem is one bead in RecentlyClosed
+type RecentlyClosedItem struct {
+	ID               string    `json:"id"`
+	Title            string    `json:"title"`
+	Type             string    `json:"type"`
+	ClosedAt         time.Time `json:"closed_at"`
+	EstimatedMinutes *int      `json:"estimated_minutes,omitempty"`
+}
+
+// ComputeRecentlyClosed collects beads with status closed and a closed_at at
+// or after since. Beads without closed_at are left out rather than guessed.
+func ComputeRecentlyClosed(issues []model.Issue, window string, since time.Time) *RecentlyClosed {
+	rc := &RecentlyClosed{
+		Window: window,
+		Cutoff: since.UTC(),
+		ByType: make(map[string]int),
+		Items:  []RecentlyClosedItem{},
+	}
+	total := 0
+	for _, iss := range issues {
+		if iss.Status != model.StatusClosed || iss.ClosedAt == nil || iss.ClosedAt.Before(since) {
+			continue
+		}
+		rc.Items = append(rc.Items, RecentlyClosedItem{
+			ID:               iss.ID,
+			Title:            iss.Title,
+			Type:             string(iss.IssueType),
+			ClosedAt:         iss.ClosedAt.UTC(),
+			EstimatedMinutes: iss.EstimatedMinutes,
+		})
+		rc.ByType[string(iss.IssueType)]++
+		if iss.EstimatedMinutes != nil {
+			total += *iss.EstimatedMinutes
+			rc.TotalEstimated = &total
+		}
+	}
+	sort.SliceStable(rc.Items, func(i, j int) bool {
+		if !rc.Items[i].ClosedAt.Equal(rc.Items[j].ClosedAt) {
+			return rc.Items[i].ClosedAt.After(rc.Items[j].ClosedAt)
+		}
+		return rc.Items[i].ID < rc.Items[j].ID
+	})
+	rc.Count = len(rc.Items)
+	return rc
+}
+
 // Staleness tracks stale issues (future: from history)
 type Staleness struct {
 	StaleCount       int    `json:"stale_count"` // Issues with no activity > threshold
@@ -295,9 +370,29 @@ type TriageOptions struct {
 	BlockerN      int  // Number of blockers to show (default 5)
 	WaitForPhase2 bool // Block until Phase 2 metrics ready
 
+	// Config, when non-nil, replaces the size-based analysis config, e.g.
+	// NextConfig to rank without the centrality metrics.
+	Config *AnalysisConfig
+
 	// bv-87: Track/label-aware recommendation grouping for multi-agent coordination
 	GroupByTrack bool // Group recommendations by execution track (connected component)
 	GroupByLabel bool // Group recommendations by primary label
+
+	// ScopeIDs, when non-nil, restricts recommendations, quick wins and
+	// blockers to these beads (e.g. a --since window). Scores and blocker
+	// relationships are still computed against the full graph.
+	ScopeIDs map[string]bool
+
+	// WIPLimit, when > 0, caps in-progress work: once the in-progress count
+	// (for WIPAssignee, or globally when empty) reaches the limit, in-progress
+	// beads are recommended ahead of new ones.
+	WIPLimit    int
+	WIPAssignee string
+
+	// RecentlyClosedWindow is how far back the recently_closed section looks,
+	// in ParseWindow syntax ("7d", "72h", "2024-01-01"). Empty means
+	// DefaultRecentlyClosedWindow; an unparseable value also falls back to it.
+	RecentlyClosedWindow string
 }
 
 // TrackRecommendationGroup groups recommendations by execution track (bv-87)
@@ -328,7 +423,12 @@ func ComputeTriageWithOptions(issues []model.Issue, opts TriageOptions) TriageRe
 func ComputeTriageWithOptionsAndTime(issues []model.Issue, opts TriageOptions, now time.Time) TriageResult {
 	// Build analyzer and stats
 	analyzer := NewAnalyzer(issues)
-	stats := analyzer.AnalyzeAsync(context.Background())
+	var stats *GraphStats
+	if opts.Config != nil {
+		stats = analyzer.AnalyzeAsyncWithConfig(context.Background(), *opts.Config)
+	} else {
+		stats = analyzer.AnalyzeAsync(context.Background())
+	}
 
 	// Triage requires advanced metrics (PageRank, etc.) for scoring.
 	// If requested, wait for Phase 2 to complete.
@@ -384,14 +484,50 @@ func ComputeTriageFromAnalyzer(analyzer *Analyzer, stats *GraphStats, issues []m
 	// Compute enhanced triage scores (bv-147)
 	triageScores := computeTriageScoresFromImpact(impactScores, unblocksMap, analyzer, DefaultTriageScoringOptions())
 
+	if opts.ScopeIDs != nil {
+		impactScores = filterScoresToScope(impactScores, func(s ImpactScore) string { return s.IssueID }, opts.ScopeIDs)
+		triageScores = filterScoresToScope(triageScores, func(s TriageScore) string { return s.IssueID }, opts.ScopeIDs)
+	}
+
+	// Prefer finishing over starting once the WIP limit is reached
+	var wip *WIPStatus
+	if opts.WIPLimit > 0 {
+		status := ComputeWIPStatus(issues, opts.WIPLimit, opts.WIPAssignee)
+		wip = &status
+		if status.Reached {
+			triageScores = prioritizeInProgress(triageScores, analyzer, opts.WIPAssignee)
+		}
+	}
+
 	// Build recommendations using enhanced scores (bv-148)
 	recommendations := buildRecommendationsFromTriageScores(triageScores, analyzer, unblocksMap, opts.TopN)
+	unblockImpact := analyzer.UnblockImpact()
+	for i := range recommendations {
+		recommendations[i].UnblockImpact = unblockImpact[recommendations[i].ID]
+	}
+	if wip != nil && wip.Reached {
+		for i := range recommendations {
+			if issue := analyzer.GetIssue(recommendations[i].ID); issue != nil && countsTowardWIP(*issue, opts.WIPAssignee) {
+				reason := fmt.Sprintf("🏁 Finish before starting new work - WIP limit reached (%d/%d)", wip.Current, wip.Limit)
+				recommendations[i].Reasons = append([]string{reason}, recommendations[i].Reasons...)
+			}
+		}
+	}
 
 	// Build quick wins
 	quickWins := buildQuickWins(impactScores, unblocksMap, opts.QuickWinN)
 
 	// Build blockers to clear (uses cached actionable issues)
-	blockersToClear := buildBlockersToClearWithContext(triageCtx, unblocksMap, opts.BlockerN)
+	var blockersToClear []BlockerItem
+	if opts.ScopeIDs != nil {
+		blockersToClear = filterScoresToScope(buildBlockersToClearWithContext(triageCtx, unblocksMap, len(unblocksMap)),
+			func(b BlockerItem) string { return b.ID }, opts.ScopeIDs)
+		if len(blockersToClear) > opts.BlockerN {
+			blockersToClear = blockersToClear[:opts.BlockerN]
+		}
+	} else {
+		blockersToClear = buildBlockersToClearWithContext(triageCtx, unblocksMap, opts.BlockerN)
+	}
 
 	// Build top picks for quick ref
 	topPicks := buildTopPicks(recommendations, 3)
@@ -405,6 +541,18 @@ func ComputeTriageFromAnalyzer(analyzer *Analyzer, stats *GraphStats, issues []m
 	elapsed := time.Since(start)
 	projectVelocity := ComputeProjectVelocity(issues, now.UTC(), 8)
 
+	closedWindow := opts.RecentlyClosedWindow
+	closedSince, err := ParseWindow("recently closed window", closedWindow, now)
+	if err != nil {
+		closedWindow = DefaultRecentlyClosedWindow
+		closedSince, _ = ParseWindow("recently closed window", closedWindow, now)
+	}
+	closedIssues := issues
+	if opts.ScopeIDs != nil {
+		closedIssues = filterScoresToScope(issues, func(iss model.Issue) string { return iss.ID }, opts.ScopeIDs)
+	}
+	recentlyClosed := ComputeRecentlyClosed(closedIssues, closedWindow, closedSince)
+
 	// bv-87: Build grouped recommendations if requested
 	var recsByTrack []TrackRecommendationGroup
 	var recsByLabel []LabelRecommendationGroup
@@ -441,8 +589,21 @@ func ComputeTriageFromAnalyzer(analyzer *Analyzer, stats *GraphStats, issues []m
 			Velocity: projectVelocity,
 			// Staleness remains nil until history integration is ready
 		},
-		Commands: buildCommands(topID),
+		Commands:       buildCommands(topID),
+		WIP:            wip,
+		RecentlyClosed: recentlyClosed,
+	}
+}
+
+// filterScoresToScope keeps items whose ID is in scope, preserving order.
+func filterScoresToScope[T any](items []T, id func(T) string, scope map[string]bool) []T {
+	kept := make([]T, 0, len(items))
+	for _, item := range items {
+		if scope[id(item)] {
+			kept = append(kept, item)
+		}
 	}
+	return kept
 }
 
 // buildUnblocksMap computes what each issue unblocks
@@ -521,6 +682,133 @@ func buildUnblocksMap(analyzer *Analyzer, issues []model.Issue) map[string][]str
 	return unblocksMap
 }
 
+// UnblockImpact returns, for each open bead, how many currently blocked beads
+// would become actionable once it is closed, directly or transitively: a
+// dependent counts when every open blocker it has is the bead itself or
+// another bead the closure unblocks in turn. Unlike the raw out-degree, a
+// dependent that still has other unresolved blockers does not count.
+// Blocking-dependency semantics match buildUnblocksMap.
+//
+// Closing x cascades to d exactly when every path from an unblocked bead to d
+// runs through x, so the counts are dominator-subtree sizes over a virtual
+// root feeding every unblocked bead. Beads on dependency cycles can never be
+// freed from outside their cycle; they hang off the root too and only their
+// own counts are simulated.
+func (a *Analyzer) UnblockImpact() map[string]int {
+	ids := make([]string, 0, len(a.issueMap))
+	for id, issue := range a.issueMap {
+		if !isClosedLikeStatus(issue.Status) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	index := make(map[string]int64, len(ids))
+	for i, id := range ids {
+		index[id] = int64(i)
+	}
+
+	// dependentsByBlocker: open blocker -> open dependents (deduped per dependent)
+	dependentsByBlocker := make(map[string][]string)
+	// openBlockerCount: open dependent -> number of distinct open blockers
+	openBlockerCount := make(map[string]int)
+	g := simple.NewDirectedGraph()
+	root := simple.Node(len(ids))
+	g.AddNode(root)
+	for i := range ids {
+		g.AddNode(simple.Node(i))
+	}
+	cyclic := make(map[int64]bool)
+	for _, id := range ids {
+		dependent := a.issueMap[id]
+		seen := make(map[string]bool, len(dependent.Dependencies))
+		for _, dep := range dependent.Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() || seen[dep.DependsOnID] {
+				continue
+			}
+			blocker, exists := a.issueMap[dep.DependsOnID]
+			if !exists || isClosedLikeStatus(blocker.Status) {
+				continue
+			}
+			seen[dep.DependsOnID] = true
+			dependentsByBlocker[dep.DependsOnID] = append(dependentsByBlocker[dep.DependsOnID], id)
+			openBlockerCount[id]++
+			if dep.DependsOnID == id {
+				cyclic[index[id]] = true // gonum graphs can't hold self-loops
+				continue
+			}
+			g.SetEdge(g.NewEdge(simple.Node(index[dep.DependsOnID]), simple.Node(index[id])))
+		}
+	}
+	for _, scc := range topo.TarjanSCC(g) {
+		if len(scc) > 1 {
+			for _, n := range scc {
+				cyclic[n.ID()] = true
+			}
+		}
+	}
+	for i, id := range ids {
+		if openBlockerCount[id] == 0 || cyclic[int64(i)] {
+			g.SetEdge(g.NewEdge(root, simple.Node(i)))
+		}
+	}
+
+	// Subtree sizes, children before parents (reverse preorder)
+	dom := flow.Dominators(root, g)
+	order := []int64{root.ID()}
+	for k := 0; k < len(order); k++ {
+		for _, child := range dom.DominatedBy(order[k]) {
+			order = append(order, child.ID())
+		}
+	}
+	size := make(map[int64]int, len(order))
+	for k := len(order) - 1; k >= 0; k-- {
+		size[order[k]]++
+		if k > 0 {
+			size[dom.DominatorOf(order[k]).ID()] += size[order[k]]
+		}
+	}
+
+	impact := make(map[string]int, len(ids))
+	for i, id := range ids {
+		if cyclic[int64(i)] {
+			impact[id] = simulateUnblockCascade(id, dependentsByBlocker, openBlockerCount)
+			continue
+		}
+		impact[id] = size[int64(i)] - 1
+	}
+	return impact
+}
+
+// simulateUnblockCascade closes id, then everything that closure unblocks,
+// and returns how many beads were freed.
+func simulateUnblockCascade(id string, dependentsByBlocker map[string][]string, openBlockerCount map[string]int) int {
+	remaining := make(map[string]int)
+	resolved := map[string]bool{id: true}
+	queue := []string{id}
+	count := 0
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		for _, depID := range dependentsByBlocker[curr] {
+			if resolved[depID] {
+				continue
+			}
+			left, ok := remaining[depID]
+			if !ok {
+				left = openBlockerCount[depID]
+			}
+			left--
+			remaining[depID] = left
+			if left == 0 {
+				resolved[depID] = true
+				queue = append(queue, depID)
+				count++
+			}
+		}
+	}
+	return count
+}
+
 // computeCounts tallies issues by various dimensions
 // Deprecated: Use computeCountsWithContext for better performance via caching.
 func computeCounts(issues []model.Issue, analyzer *Analyzer) HealthCounts {
@@ -823,11 +1111,12 @@ func buildTopPicks(recommendations []Recommendation, limit int) []TopPick {
 	picks := make([]TopPick, 0, len(recommendations))
 	for _, rec := range recommendations {
 		picks = append(picks, TopPick{
-			ID:       rec.ID,
-			Title:    rec.Title,
-			Score:    rec.Score,
-			Reasons:  rec.Reasons,
-			Unblocks: len(rec.UnblocksIDs),
+			ID:            rec.ID,
+			Title:         rec.Title,
+			Score:         rec.Score,
+			Reasons:       rec.Reasons,
+			Unblocks:      len(rec.UnblocksIDs),
+			UnblockImpact: rec.UnblockImpact,
 		})
 	}
 
@@ -1408,11 +1697,12 @@ func buildRecommendationsByTrack(recs []Recommendation, analyzer *Analyzer, unbl
 		// update top pick logic (highest score)
 		if group.TopPick == nil || rec.Score > group.TopPick.Score {
 			group.TopPick = &TopPick{
-				ID:       rec.ID,
-				Title:    rec.Title,
-				Score:    rec.Score,
-				Reasons:  rec.Reasons,
-				Unblocks: len(unblocksMap[rec.ID]),
+				ID:            rec.ID,
+				Title:         rec.Title,
+				Score:         rec.Score,
+				Reasons:       rec.Reasons,
+				Unblocks:      len(unblocksMap[rec.ID]),
+				UnblockImpact: rec.UnblockImpact,
 			}
 			group.ClaimCommand = fmt.Sprintf("CI=1 bd update %s --status in_progress --json", rec.ID)
 		}
@@ -1452,11 +1742,12 @@ func buildRecommendationsByLabel(recs []Recommendation, unblocksMap map[string][
 
 		if group.TopPick == nil || rec.Score > group.TopPick.Score {
 			group.TopPick = &TopPick{
-				ID:       rec.ID,
-				Title:    rec.Title,
-				Score:    rec.Score,
-				Reasons:  rec.Reasons,
-				Unblocks: len(unblocksMap[rec.ID]),
+				ID:            rec.ID,
+				Title:         rec.Title,
+				Score:         rec.Score,
+				Reasons:       rec.Reasons,
+				Unblocks:      len(unblocksMap[rec.ID]),
+				UnblockImpact: rec.UnblockImpact,
 			}
 			group.ClaimCommand = fmt.Sprintf("CI=1 bd update %s --status in_progress --json", rec.ID)
 		}
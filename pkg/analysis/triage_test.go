@@ -2,6 +2,8 @@ package analysis
 
 import (
 	"context"
+	"fmt"
+	"math/rand"
 	"testing"
 	"time"
 
@@ -1276,3 +1278,157 @@ func TestComputeTriageFromAnalyzer_Empty(t *testing.T) {
 		t.Errorf("expected 0 recommendations, got %d", len(triage.Recommendations))
 	}
 }
+
+func TestUnblockImpact_CountsChainOnlyForLastBlocker(t *testing.T) {
+	blocks := func(id, on string) *model.Dependency {
+		return &model.Dependency{IssueID: id, DependsOnID: on, Type: model.DepBlocks}
+	}
+	build := func(xStatus model.Status) []model.Issue {
+		return []model.Issue{
+			{ID: "A", Title: "A", Status: model.StatusOpen},
+			{ID: "X", Title: "X", Status: xStatus},
+			{ID: "B", Title: "B", Status: model.StatusOpen, Dependencies: []*model.Dependency{blocks("B", "A")}},
+			{ID: "C", Title: "C", Status: model.StatusOpen, Dependencies: []*model.Dependency{blocks("C", "B")}},
+			{ID: "D", Title: "D", Status: model.StatusOpen, Dependencies: []*model.Dependency{blocks("D", "B"), blocks("D", "X")}},
+			// E heads a chain E <- F <- G that needs both A and X closed.
+			{ID: "E", Title: "E", Status: model.StatusOpen, Dependencies: []*model.Dependency{blocks("E", "A"), blocks("E", "X")}},
+			{ID: "F", Title: "F", Status: model.StatusOpen, Dependencies: []*model.Dependency{blocks("F", "E")}},
+			{ID: "G", Title: "G", Status: model.StatusOpen, Dependencies: []*model.Dependency{blocks("G", "F"), {IssueID: "G", DependsOnID: "A", Type: model.DepRelated}}},
+		}
+	}
+
+	// X still open: closing A frees B and then C; D, E and E's chain still wait on X.
+	impact := NewAnalyzer(build(model.StatusOpen)).UnblockImpact()
+	if impact["A"] != 2 {
+		t.Errorf("A impact = %d, want 2 (B, C)", impact["A"])
+	}
+	if impact["X"] != 0 {
+		t.Errorf("X impact = %d, want 0 (every dependent has another open blocker)", impact["X"])
+	}
+	if impact["B"] != 1 {
+		t.Errorf("B impact = %d, want 1 (C; D still waits on X)", impact["B"])
+	}
+
+	// X closed: A is now the last blocker of E, so the whole chain and D follow.
+	impact = NewAnalyzer(build(model.StatusClosed)).UnblockImpact()
+	if impact["A"] != 6 {
+		t.Errorf("A impact = %d, want 6 (B, C, D, E, F, G)", impact["A"])
+	}
+	if _, ok := impact["X"]; ok {
+		t.Error("closed beads should have no impact entry")
+	}
+
+	triage := ComputeTriage(build(model.StatusClosed))
+	for _, rec := range triage.Recommendations {
+		if rec.ID == "A" && rec.UnblockImpact != 6 {
+			t.Errorf("recommendation A unblock_impact = %d, want 6", rec.UnblockImpact)
+		}
+	}
+	for _, pick := range triage.QuickRef.TopPicks {
+		if pick.UnblockImpact != impact[pick.ID] {
+			t.Errorf("top pick %s unblock_impact = %d, want %d", pick.ID, pick.UnblockImpact, impact[pick.ID])
+		}
+	}
+}
+
+func TestUnblockImpact_MatchesCascadeSimulation(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	for round := 0; round < 50; round++ {
+		n := 5 + rng.Intn(25)
+		issues := make([]model.Issue, n)
+		for i := range issues {
+			id := fmt.Sprintf("n%02d", i)
+			status := model.StatusOpen
+			if rng.Intn(6) == 0 {
+				status = model.StatusClosed
+			}
+			issues[i] = model.Issue{ID: id, Title: id, Status: status}
+			// Mostly backward edges, with occasional forward edges and
+			// self-loops to exercise cycles
+			for k := rng.Intn(3); k > 0; k-- {
+				target := rng.Intn(n)
+				if target > i && rng.Intn(4) != 0 {
+					continue
+				}
+				issues[i].Dependencies = append(issues[i].Dependencies, &model.Dependency{
+					IssueID: id, DependsOnID: fmt.Sprintf("n%02d", target), Type: model.DepBlocks,
+				})
+			}
+		}
+
+		analyzer := NewAnalyzer(issues)
+		impact := analyzer.UnblockImpact()
+
+		dependentsByBlocker := make(map[string][]string)
+		openBlockerCount := make(map[string]int)
+		for _, issue := range issues {
+			if isClosedLikeStatus(issue.Status) {
+				continue
+			}
+			seen := make(map[string]bool)
+			for _, dep := range issue.Dependencies {
+				blocker := analyzer.issueMap[dep.DependsOnID]
+				if seen[dep.DependsOnID] || isClosedLikeStatus(blocker.Status) {
+					continue
+				}
+				seen[dep.DependsOnID] = true
+				dependentsByBlocker[dep.DependsOnID] = append(dependentsByBlocker[dep.DependsOnID], issue.ID)
+				openBlockerCount[issue.ID]++
+			}
+		}
+		for _, issue := range issues {
+			if isClosedLikeStatus(issue.Status) {
+				continue
+			}
+			want := simulateUnblockCascade(issue.ID, dependentsByBlocker, openBlockerCount)
+			if impact[issue.ID] != want {
+				t.Fatalf("round %d: %s impact = %d, simulation says %d", round, issue.ID, impact[issue.ID], want)
+			}
+		}
+	}
+}
+
+func TestComputeTriage_RecentlyClosed(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time { ts := now.Add(-d); return &ts }
+	mins := func(m int) *int { return &m }
+	issues := []model.Issue{
+		{ID: "open", Title: "Still open", Status: model.StatusOpen, IssueType: model.TypeTask},
+		{ID: "recent-bug", Title: "Fixed crash", Status: model.StatusClosed, IssueType: model.TypeBug, ClosedAt: at(24 * time.Hour), EstimatedMinutes: mins(90)},
+		{ID: "recent-task", Title: "Wrote docs", Status: model.StatusClosed, IssueType: model.TypeTask, ClosedAt: at(2 * time.Hour)},
+		{ID: "old", Title: "Ancient", Status: model.StatusClosed, IssueType: model.TypeTask, ClosedAt: at(30 * 24 * time.Hour), EstimatedMinutes: mins(600)},
+		{ID: "no-closed-at", Title: "Unknown close", Status: model.StatusClosed, IssueType: model.TypeTask, UpdatedAt: now},
+	}
+
+	rc := ComputeTriageWithOptionsAndTime(issues, TriageOptions{}, now).RecentlyClosed
+	if rc == nil {
+		t.Fatal("expected a recently_closed section")
+	}
+	if rc.Window != DefaultRecentlyClosedWindow || !rc.Cutoff.Equal(now.AddDate(0, 0, -7)) {
+		t.Errorf("window = %q cutoff %v, want %q cutoff %v", rc.Window, rc.Cutoff, DefaultRecentlyClosedWindow, now.AddDate(0, 0, -7))
+	}
+	var ids []string
+	for _, item := range rc.Items {
+		ids = append(ids, item.ID)
+	}
+	// Newest first; the 30-day-old bead and the one without closed_at are left out
+	if len(ids) != 2 || ids[0] != "recent-task" || ids[1] != "recent-bug" || rc.Count != 2 {
+		t.Fatalf("recently closed = %v (count %d), want [recent-task recent-bug]", ids, rc.Count)
+	}
+	if rc.ByType["bug"] != 1 || rc.ByType["task"] != 1 {
+		t.Errorf("by_type = %v, want bug:1 task:1", rc.ByType)
+	}
+	if rc.TotalEstimated == nil || *rc.TotalEstimated != 90 {
+		t.Errorf("total_estimated_minutes = %v, want 90", rc.TotalEstimated)
+	}
+
+	wide := ComputeTriageWithOptionsAndTime(issues, TriageOptions{RecentlyClosedWindow: "60d"}, now).RecentlyClosed
+	if wide.Count != 3 || wide.Window != "60d" || *wide.TotalEstimated != 690 {
+		t.Errorf("60d window: count %d window %q total %v, want 3, 60d, 690", wide.Count, wide.Window, *wide.TotalEstimated)
+	}
+
+	noEstimates := ComputeRecentlyClosed(issues[2:3], "7d", now.AddDate(0, 0, -7))
+	if noEstimates.TotalEstimated != nil {
+		t.Errorf("total_estimated_minutes should be omitted without estimates, got %d", *noEstimates.TotalEstimated)
+	}
+}
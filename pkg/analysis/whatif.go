@@ -78,7 +78,10 @@ func GenerateTopReasons(score ImpactScore) []PriorityReason {
 
 	// Sort by weighted contribution (descending)
 	sort.Slice(factors, func(i, j int) bool {
-		return factors[i].weight > factors[j].weight
+		if factors[i].weight != factors[j].weight {
+			return factors[i].weight > factors[j].weight
+		}
+		return factors[i].name < factors[j].name
 	})
 
 	// Take top 3 with significant contribution
@@ -187,6 +190,7 @@ func (a *Analyzer) GenerateEnhancedRecommendationsWithThresholds(thresholds Reco
 					ImpactScore:       score.Score,
 					Confidence:        0.5,
 					Reasoning:         extractReasoningStrings(topReasons),
+					Reasons:           topReasonCodes(score, topReasons, whatIf),
 					Direction:         "none",
 					WhatIf:            whatIf, // bv-129: populate top-level WhatIf for consistency
 				},
@@ -195,17 +199,58 @@ func (a *Analyzer) GenerateEnhancedRecommendationsWithThresholds(thresholds Reco
 		}
 	}
 
-	// Sort by impact score descending
+	// Promotions, then demotions, then impact-only entries; by impact score
+	// descending within each group
 	sort.Slice(enhanced, func(i, j int) bool {
-		return enhanced[i].ImpactScore > enhanced[j].ImpactScore
+		if enhanced[i].Direction != enhanced[j].Direction {
+			return directionRank(enhanced[i].Direction) < directionRank(enhanced[j].Direction)
+		}
+		if enhanced[i].ImpactScore != enhanced[j].ImpactScore {
+			return enhanced[i].ImpactScore > enhanced[j].ImpactScore
+		}
+		return enhanced[i].IssueID < enhanced[j].IssueID
 	})
 
-	// Cap at 10 items
-	if len(enhanced) > 10 {
-		enhanced = enhanced[:10]
+	return capEnhancedRecommendations(enhanced, maxEnhancedRecommendations, reservedDemotionSlots)
+}
+
+const (
+	// maxEnhancedRecommendations caps GenerateEnhancedRecommendations
+	maxEnhancedRecommendations = 10
+	// reservedDemotionSlots keeps a long promotion list from crowding out
+	// every demotion
+	reservedDemotionSlots = 3
+)
+
+// capEnhancedRecommendations trims direction-sorted recommendations to limit,
+// holding up to demotionSlots of them for demotions.
+func capEnhancedRecommendations(enhanced []EnhancedPriorityRecommendation, limit, demotionSlots int) []EnhancedPriorityRecommendation {
+	if len(enhanced) <= limit {
+		return enhanced
+	}
+	demotions := 0
+	for _, e := range enhanced {
+		if e.Direction == "decrease" {
+			demotions++
+		}
 	}
+	maxPromotions := limit - min(demotions, demotionSlots)
 
-	return enhanced
+	capped := make([]EnhancedPriorityRecommendation, 0, limit)
+	promotions := 0
+	for _, e := range enhanced {
+		if len(capped) == limit {
+			break
+		}
+		if e.Direction == "increase" {
+			if promotions == maxPromotions {
+				continue
+			}
+			promotions++
+		}
+		capped = append(capped, e)
+	}
+	return capped
 }
 
 // extractReasoningStrings converts PriorityReasons to string slice
@@ -217,6 +262,41 @@ func extractReasoningStrings(reasons []PriorityReason) []string {
 	return result
 }
 
+// topReasonCodes gives the reason code and evidence behind each top reason, for
+// impact-only entries that have no generateRecommendation signals.
+func topReasonCodes(score ImpactScore, reasons []PriorityReason, whatIf *WhatIfDelta) []ReasonCode {
+	codes := make([]ReasonCode, 0, len(reasons))
+	for _, r := range reasons {
+		switch r.Factor {
+		case "pagerank":
+			codes = append(codes, ReasonCode{Code: ReasonHighPageRank, Evidence: score.Breakdown.pageRankEvidence()})
+		case "betweenness":
+			codes = append(codes, ReasonCode{Code: ReasonHighBetweenness, Evidence: score.Breakdown.betweennessEvidence()})
+		case "blockers":
+			dependents := 0
+			if whatIf != nil {
+				dependents = whatIf.DirectUnblocks
+			}
+			code := ReasonHasDependents
+			if dependents >= 3 {
+				code = ReasonHighDependentCount
+			}
+			codes = append(codes, ReasonCode{Code: code, Evidence: map[string]float64{"dependent_count": float64(dependents)}})
+		case "staleness":
+			codes = append(codes, ReasonCode{Code: ReasonStale, Evidence: map[string]float64{"days_stale": float64(int(score.Breakdown.StalenessNorm * 30))}})
+		case "priority":
+			codes = append(codes, ReasonCode{Code: ReasonPrioritySet, Evidence: map[string]float64{"current_priority": float64(score.Priority)}})
+		case "time_to_impact":
+			codes = append(codes, ReasonCode{Code: ReasonTimeToImpact, Evidence: map[string]float64{"time_to_impact": score.Breakdown.TimeToImpactNorm}})
+		case "urgency":
+			codes = append(codes, ReasonCode{Code: ReasonUrgency, Evidence: map[string]float64{"urgency": score.Breakdown.UrgencyNorm}})
+		case "risk":
+			codes = append(codes, ReasonCode{Code: ReasonHighRisk, Evidence: map[string]float64{"risk": score.Breakdown.RiskNorm}})
+		}
+	}
+	return codes
+}
+
 // WhatIfEntry represents a single issue with its what-if delta
 type WhatIfEntry struct {
 	IssueID string      `json:"issue_id"`
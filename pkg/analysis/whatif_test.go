@@ -1,6 +1,7 @@
 package analysis
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -282,6 +283,56 @@ func TestGenerateEnhancedRecommendations_CappedAt10(t *testing.T) {
 	}
 }
 
+func TestGenerateEnhancedRecommendations_CapKeepsDemotions(t *testing.T) {
+	// Twelve P3 roots that three beads each wait on, and one P0 leaf
+	var issues []model.Issue
+	for i := 0; i < 12; i++ {
+		root := fmt.Sprintf("root-%02d", i)
+		issues = append(issues, model.Issue{ID: root, Title: root, Status: model.StatusOpen, Priority: 3})
+		for j := 0; j < 3; j++ {
+			id := fmt.Sprintf("%s-dep-%d", root, j)
+			issues = append(issues, model.Issue{ID: id, Title: id, Status: model.StatusOpen, Priority: 3, Dependencies: []*model.Dependency{
+				{IssueID: id, DependsOnID: root, Type: model.DepBlocks},
+			}})
+		}
+	}
+	issues = append(issues, model.Issue{ID: "leaf", Title: "Leaf", Status: model.StatusOpen, Priority: 0})
+
+	analyzer := NewAnalyzer(issues)
+	promotions := 0
+	for _, rec := range analyzer.GenerateRecommendations() {
+		if rec.Direction == "increase" {
+			promotions++
+		}
+	}
+	if promotions <= maxEnhancedRecommendations {
+		t.Fatalf("fixture should yield more than %d promotions, got %d", maxEnhancedRecommendations, promotions)
+	}
+
+	recs := analyzer.GenerateEnhancedRecommendations()
+	if len(recs) != maxEnhancedRecommendations {
+		t.Fatalf("expected %d recommendations, got %d", maxEnhancedRecommendations, len(recs))
+	}
+	var leaf *EnhancedPriorityRecommendation
+	for i := range recs {
+		if recs[i].IssueID == "leaf" {
+			leaf = &recs[i]
+		}
+	}
+	// Demotions keep the "decrease" direction used by the rest of the schema
+	if leaf == nil || leaf.Direction != "decrease" {
+		t.Fatalf("expected the P0 leaf demotion to survive the cap, got %v", enhancedIDs(recs))
+	}
+}
+
+func enhancedIDs(recs []EnhancedPriorityRecommendation) []string {
+	ids := make([]string, len(recs))
+	for i, r := range recs {
+		ids[i] = r.IssueID + ":" + r.Direction
+	}
+	return ids
+}
+
 func TestGenerateEnhancedRecommendations_SortedByImpactScore(t *testing.T) {
 	now := time.Now()
 	issues := []model.Issue{
@@ -310,9 +361,14 @@ func TestGenerateEnhancedRecommendations_SortedByImpactScore(t *testing.T) {
 		t.Skip("Not enough recommendations to test sorting")
 	}
 
-	// Should be sorted by impact score descending
+	// Should be grouped by direction (promotions, demotions, impact-only) and
+	// sorted by impact score descending within each group
 	for i := 1; i < len(recs); i++ {
-		if recs[i].ImpactScore > recs[i-1].ImpactScore {
+		prev, cur := directionRank(recs[i-1].Direction), directionRank(recs[i].Direction)
+		if cur < prev {
+			t.Errorf("%s listed after %s", recs[i].Direction, recs[i-1].Direction)
+		}
+		if cur == prev && recs[i].ImpactScore > recs[i-1].ImpactScore {
 			t.Error("recommendations should be sorted by impact score descending")
 		}
 	}
@@ -0,0 +1,69 @@
+package analysis
+
+import (
+	"fmt"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// WIPStatus reports in-progress work against a --wip-limit.
+type WIPStatus struct {
+	Current  int    `json:"wip_current"`
+	Limit    int    `json:"wip_limit"`
+	Assignee string `json:"wip_assignee,omitempty"` // Empty when counted globally
+	Reached  bool   `json:"wip_limit_reached"`      // Current >= Limit: finish before starting new work
+	Exceeded bool   `json:"wip_limit_exceeded"`     // Current > Limit
+	Note     string `json:"wip_note,omitempty"`
+}
+
+// countsTowardWIP reports whether issue is in progress and, when assignee is
+// set, owned by that assignee.
+func countsTowardWIP(issue model.Issue, assignee string) bool {
+	if issue.Status != model.StatusInProgress {
+		return false
+	}
+	return assignee == "" || issue.Assignee == assignee
+}
+
+// ComputeWIPStatus counts in-progress beads (globally, or for one assignee)
+// and compares them with limit. A limit <= 0 disables the check.
+func ComputeWIPStatus(issues []model.Issue, limit int, assignee string) WIPStatus {
+	status := WIPStatus{Limit: limit, Assignee: assignee}
+	for _, issue := range issues {
+		if countsTowardWIP(issue, assignee) {
+			status.Current++
+		}
+	}
+	if limit <= 0 {
+		return status
+	}
+
+	status.Reached = status.Current >= limit
+	status.Exceeded = status.Current > limit
+	scope := "in progress"
+	if assignee != "" {
+		scope = fmt.Sprintf("in progress for %s", assignee)
+	}
+	switch {
+	case status.Exceeded:
+		status.Note = fmt.Sprintf("WIP limit exceeded: %d beads %s (limit %d); finish in-progress work before starting new beads", status.Current, scope, limit)
+	case status.Reached:
+		status.Note = fmt.Sprintf("WIP limit reached: %d beads %s (limit %d); finish in-progress work before starting new beads", status.Current, scope, limit)
+	}
+	return status
+}
+
+// prioritizeInProgress moves scores for in-progress beads that count toward
+// WIP ahead of everything else, keeping the relative order of both groups.
+func prioritizeInProgress(scores []TriageScore, analyzer *Analyzer, assignee string) []TriageScore {
+	inProgress := make([]TriageScore, 0, len(scores))
+	rest := make([]TriageScore, 0, len(scores))
+	for _, s := range scores {
+		if issue := analyzer.GetIssue(s.IssueID); issue != nil && countsTowardWIP(*issue, assignee) {
+			inProgress = append(inProgress, s)
+		} else {
+			rest = append(rest, s)
+		}
+	}
+	return append(inProgress, rest...)
+}
@@ -0,0 +1,76 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func wipTestIssues(now time.Time) []model.Issue {
+	return []model.Issue{
+		// A high-priority new bead that would normally be the top pick
+		{ID: "new", Title: "Hot new thing", Status: model.StatusOpen, Priority: 0, IssueType: model.TypeTask, CreatedAt: now, UpdatedAt: now},
+		{ID: "wip-a", Title: "Half done A", Status: model.StatusInProgress, Priority: 3, IssueType: model.TypeTask, Assignee: "ann", CreatedAt: now, UpdatedAt: now},
+		{ID: "wip-b", Title: "Half done B", Status: model.StatusInProgress, Priority: 3, IssueType: model.TypeTask, Assignee: "bob", CreatedAt: now, UpdatedAt: now},
+	}
+}
+
+func TestComputeWIPStatus(t *testing.T) {
+	issues := wipTestIssues(time.Now())
+
+	global := ComputeWIPStatus(issues, 1, "")
+	if global.Current != 2 || !global.Reached || !global.Exceeded || global.Note == "" {
+		t.Errorf("global: %+v", global)
+	}
+	perAssignee := ComputeWIPStatus(issues, 2, "ann")
+	if perAssignee.Current != 1 || perAssignee.Reached || perAssignee.Note != "" {
+		t.Errorf("per-assignee: %+v", perAssignee)
+	}
+	disabled := ComputeWIPStatus(issues, 0, "")
+	if disabled.Reached || disabled.Current != 2 {
+		t.Errorf("disabled: %+v", disabled)
+	}
+}
+
+func TestTriage_WIPLimitPrefersInProgress(t *testing.T) {
+	now := time.Now()
+	issues := wipTestIssues(now)
+
+	base := ComputeTriageWithOptionsAndTime(issues, TriageOptions{WaitForPhase2: true}, now)
+	if len(base.Recommendations) == 0 || base.Recommendations[0].ID != "new" {
+		t.Fatalf("expected new bead on top without a WIP limit, got %+v", base.Recommendations)
+	}
+	if base.WIP != nil {
+		t.Error("expected no WIP status without a limit")
+	}
+
+	limited := ComputeTriageWithOptionsAndTime(issues, TriageOptions{WaitForPhase2: true, WIPLimit: 2}, now)
+	if limited.WIP == nil || !limited.WIP.Reached || limited.WIP.Current != 2 || limited.WIP.Limit != 2 {
+		t.Fatalf("unexpected WIP status: %+v", limited.WIP)
+	}
+	if len(limited.Recommendations) < 3 {
+		t.Fatalf("expected 3 recommendations, got %d", len(limited.Recommendations))
+	}
+	for i, rec := range limited.Recommendations[:2] {
+		if rec.Status != string(model.StatusInProgress) {
+			t.Errorf("recommendation %d: expected in-progress bead first, got %s (%s)", i, rec.ID, rec.Status)
+		}
+		if len(rec.Reasons) == 0 || !strings.Contains(rec.Reasons[0], "WIP limit reached (2/2)") {
+			t.Errorf("recommendation %d: expected WIP reason first, got %v", i, rec.Reasons)
+		}
+	}
+	if limited.QuickRef.TopPicks[0].ID == "new" {
+		t.Error("top pick should not start new work when WIP limit is reached")
+	}
+
+	// Per-assignee: only ann's in-progress bead counts and is promoted
+	ann := ComputeTriageWithOptionsAndTime(issues, TriageOptions{WaitForPhase2: true, WIPLimit: 1, WIPAssignee: "ann"}, now)
+	if ann.Recommendations[0].ID != "wip-a" {
+		t.Errorf("expected ann's bead first, got %s", ann.Recommendations[0].ID)
+	}
+	if ann.Recommendations[1].ID != "new" {
+		t.Errorf("expected other work to keep its order after ann's bead, got %s", ann.Recommendations[1].ID)
+	}
+}
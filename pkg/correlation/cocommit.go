@@ -160,9 +160,11 @@ func (c *CoCommitExtractor) getFilesChanged(sha string) ([]FileChange, error) {
 
 		action := parts[0]
 		path := parts[1]
+		oldPath := ""
 
 		// Handle renames: R100\told\tnew
 		if len(parts) == 3 && strings.HasPrefix(action, "R") {
+			oldPath = path
 			path = parts[2] // Use new name
 			action = "R"
 		}
@@ -173,8 +175,9 @@ func (c *CoCommitExtractor) getFilesChanged(sha string) ([]FileChange, error) {
 		}
 
 		files = append(files, FileChange{
-			Path:   path,
-			Action: action,
+			Path:    path,
+			Action:  action,
+			OldPath: oldPath,
 		})
 	}
 
@@ -123,7 +123,10 @@ func BuildFileIndex(report *HistoryReport) *FileBeadIndex {
 
 		// Sort by last touch time (most recent first)
 		sort.Slice(refs, func(i, j int) bool {
-			return refs[i].LastTouch.After(refs[j].LastTouch)
+			if !refs[i].LastTouch.Equal(refs[j].LastTouch) {
+				return refs[i].LastTouch.After(refs[j].LastTouch)
+			}
+			return refs[i].BeadID < refs[j].BeadID
 		})
 
 		result.FileToBeads[filePath] = refs
@@ -306,7 +309,10 @@ func (fl *FileLookup) GetHotspots(limit int) []FileHotspot {
 
 	// Sort by count descending
 	sort.Slice(counts, func(i, j int) bool {
-		return counts[i].count > counts[j].count
+		if counts[i].count != counts[j].count {
+			return counts[i].count > counts[j].count
+		}
+		return counts[i].path < counts[j].path
 	})
 
 	// Take top N
@@ -506,7 +512,10 @@ func (m *CoChangeMatrix) GetRelatedFiles(filePath string, threshold float64, lim
 
 	// Sort by correlation descending
 	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].Correlation > entries[j].Correlation
+		if entries[i].Correlation != entries[j].Correlation {
+			return entries[i].Correlation > entries[j].Correlation
+		}
+		return entries[i].FilePath < entries[j].FilePath
 	})
 
 	// Apply limit
@@ -656,7 +665,10 @@ func (fl *FileLookup) ImpactAnalysis(files []string) *ImpactResult {
 		if pi != pj {
 			return pi < pj
 		}
-		return result.AffectedBeads[i].Relevance > result.AffectedBeads[j].Relevance
+		if result.AffectedBeads[i].Relevance != result.AffectedBeads[j].Relevance {
+			return result.AffectedBeads[i].Relevance > result.AffectedBeads[j].Relevance
+		}
+		return result.AffectedBeads[i].BeadID < result.AffectedBeads[j].BeadID
 	})
 
 	result.RiskScore = float64(inProgressCount)*0.4 + float64(openCount)*0.2 + float64(recentClosedCount)*0.05
@@ -2,6 +2,7 @@
 package correlation
 
 import (
+	"math"
 	"sort"
 	"time"
 
@@ -25,7 +26,7 @@ type NetworkEdge struct {
 	FromBead string          `json:"from_bead"`
 	ToBead   string          `json:"to_bead"`
 	EdgeType NetworkEdgeType `json:"edge_type"`
-	Weight   int             `json:"weight"`  // Number of shared commits/files
+	Weight   int             `json:"weight"`  // Number of shared commits/files (change-weighted files: see NetworkBuilderOptions)
 	Details  []string        `json:"details"` // Sample commit SHAs or file paths
 }
 
@@ -37,6 +38,7 @@ type NetworkNode struct {
 	Priority     int       `json:"priority"`
 	LastActivity time.Time `json:"last_activity"`
 	Degree       int       `json:"degree"`       // Number of connections
+	Strength     int       `json:"strength"`     // Sum of connected edge weights
 	ClusterID    int       `json:"cluster_id"`   // Cluster membership (-1 if none)
 	CommitCount  int       `json:"commit_count"` // Number of associated commits
 	FileCount    int       `json:"file_count"`   // Number of touched files
@@ -64,30 +66,53 @@ type ImpactNetwork struct {
 	Edges       []NetworkEdge           `json:"edges"`
 	Clusters    []BeadCluster           `json:"clusters"`
 	Stats       NetworkStats            `json:"stats"`
+	// ChangeWeighted is set when shared-file edges were weighted by shared
+	// lines (WeightBySharedLines); TopConnected then ranks beads by Strength
+	// rather than Degree.
+	ChangeWeighted bool `json:"change_weighted,omitempty"`
 }
 
 // NetworkStats provides aggregate statistics about the network.
 type NetworkStats struct {
-	TotalNodes     int     `json:"total_nodes"`
-	TotalEdges     int     `json:"total_edges"`
-	ClusterCount   int     `json:"cluster_count"`
-	AvgDegree      float64 `json:"avg_degree"`
-	MaxDegree      int     `json:"max_degree"`
-	Density        float64 `json:"density"`         // edges / max_possible_edges
-	IsolatedNodes  int     `json:"isolated_nodes"`  // Nodes with no connections
-	LargestCluster int     `json:"largest_cluster"` // Size of largest cluster
+	TotalNodes             int     `json:"total_nodes"`
+	TotalEdges             int     `json:"total_edges"`
+	ClusterCount           int     `json:"cluster_count"`
+	AvgDegree              float64 `json:"avg_degree"` // Typed edges per node (2 * edges / nodes)
+	MaxDegree              int     `json:"max_degree"`
+	Density                float64 `json:"density"`                  // edges / max_possible_edges
+	IsolatedNodes          int     `json:"isolated_nodes"`           // Nodes with no connections
+	LargestCluster         int     `json:"largest_cluster"`          // Size of largest cluster
+	LargestClusterFraction float64 `json:"largest_cluster_fraction"` // largest_cluster / total_nodes
+	// LargestComponent is the size of the largest group of beads connected by
+	// edges of any weight (clusters only follow strong edges).
+	LargestComponent         int     `json:"largest_component"`
+	LargestComponentFraction float64 `json:"largest_component_fraction"` // largest_component / total_nodes
 }
 
 // NetworkBuilder constructs an impact network from correlation data.
 type NetworkBuilder struct {
 	report      *HistoryReport
-	fileIndex   *FileBeadIndex
-	beadFiles   map[string]map[string]bool // beadID -> set of file paths
+	opts        NetworkBuilderOptions
+	renames     map[string]string          // file path -> canonical path across git renames
+	beadFiles   map[string]map[string]int  // beadID -> canonical file path -> lines changed (min 1 per touch)
 	beadCommits map[string]map[string]bool // beadID -> set of commit SHAs
 	issues      []model.Issue
 	issueIndex  map[string]model.Issue
 }
 
+// NetworkBuilderOptions configures how the impact network is built.
+type NetworkBuilderOptions struct {
+	// WeightBySharedLines weights shared-file edges by the lines both beads
+	// changed in each file: a shared file contributes
+	// ceil(log2(1 + min(linesA, linesB))) instead of 1, so two large edits to a
+	// file couple beads more tightly than two one-line touches. The log keeps
+	// one huge file from drowning out many shared ones. It is a sum over shared
+	// files, not normalised by what the beads changed elsewhere; it feeds
+	// clustering (which needs weight >= 2) and TopConnected. Files without line
+	// stats count as one line.
+	WeightBySharedLines bool
+}
+
 // NewNetworkBuilder creates a new network builder from a history report.
 func NewNetworkBuilder(report *HistoryReport) *NetworkBuilder {
 	return NewNetworkBuilderWithIssues(report, nil)
@@ -95,15 +120,21 @@ func NewNetworkBuilder(report *HistoryReport) *NetworkBuilder {
 
 // NewNetworkBuilderWithIssues creates a new network builder from a history report and issues.
 func NewNetworkBuilderWithIssues(report *HistoryReport, issues []model.Issue) *NetworkBuilder {
+	return NewNetworkBuilderWithOptions(report, issues, NetworkBuilderOptions{})
+}
+
+// NewNetworkBuilderWithOptions creates a network builder with explicit options.
+func NewNetworkBuilderWithOptions(report *HistoryReport, issues []model.Issue, opts NetworkBuilderOptions) *NetworkBuilder {
 	nb := &NetworkBuilder{
 		report:      report,
-		beadFiles:   make(map[string]map[string]bool),
+		opts:        opts,
+		beadFiles:   make(map[string]map[string]int),
 		beadCommits: make(map[string]map[string]bool),
 		issues:      issues,
 	}
 
 	if report != nil {
-		nb.fileIndex = BuildFileIndex(report)
+		nb.renames = buildRenameAliases(report)
 		nb.buildBeadMaps()
 	}
 	if len(issues) > 0 {
@@ -126,25 +157,107 @@ func (nb *NetworkBuilder) buildBeadMaps() {
 	}
 
 	for beadID, history := range nb.report.Histories {
-		nb.beadFiles[beadID] = make(map[string]bool)
+		nb.beadFiles[beadID] = make(map[string]int)
 		nb.beadCommits[beadID] = make(map[string]bool)
 
 		for _, commit := range history.Commits {
 			nb.beadCommits[beadID][commit.SHA] = true
 			for _, file := range commit.Files {
-				nb.beadFiles[beadID][normalizePath(file.Path)] = true
+				nb.beadFiles[beadID][nb.canonicalPath(file.Path)] += max(file.Insertions+file.Deletions, 1)
+			}
+		}
+	}
+}
+
+// buildRenameAliases groups file paths linked by git renames (FileChange.OldPath)
+// so every name a file has had maps to one canonical path: the name it was last
+// renamed to. Paths that were never renamed are absent from the result.
+func buildRenameAliases(report *HistoryReport) map[string]string {
+	parent := make(map[string]string)
+	var find func(string) string
+	find = func(p string) string {
+		root, ok := parent[p]
+		if !ok || root == p {
+			return p
+		}
+		root = find(root)
+		parent[p] = root
+		return root
+	}
+
+	renamedTo := make(map[string]bool)
+	renamedFrom := make(map[string]bool)
+	for _, history := range report.Histories {
+		for _, commit := range history.Commits {
+			for _, file := range commit.Files {
+				if file.OldPath == "" {
+					continue
+				}
+				oldPath, newPath := normalizePath(file.OldPath), normalizePath(file.Path)
+				if oldPath == newPath {
+					continue
+				}
+				renamedFrom[oldPath] = true
+				renamedTo[newPath] = true
+				a, b := find(oldPath), find(newPath)
+				if a != b {
+					parent[a] = b
+				}
+			}
+		}
+	}
+	if len(parent) == 0 {
+		return nil
+	}
+
+	// Pick the current name of each group: a rename target that was not renamed
+	// again, falling back to the smallest path if the renames form a cycle.
+	groups := make(map[string][]string)
+	for p := range renamedTo {
+		groups[find(p)] = append(groups[find(p)], p)
+	}
+	for p := range renamedFrom {
+		if !renamedTo[p] {
+			groups[find(p)] = append(groups[find(p)], p)
+		}
+	}
+	aliases := make(map[string]string)
+	for _, members := range groups {
+		sort.Strings(members)
+		canonical := ""
+		for _, p := range members {
+			if renamedTo[p] && !renamedFrom[p] {
+				canonical = p
+				break
 			}
 		}
+		if canonical == "" {
+			canonical = members[0]
+		}
+		for _, p := range members {
+			aliases[p] = canonical
+		}
 	}
+	return aliases
+}
+
+// canonicalPath normalizes path and resolves it through known renames.
+func (nb *NetworkBuilder) canonicalPath(path string) string {
+	path = normalizePath(path)
+	if canonical, ok := nb.renames[path]; ok {
+		return canonical
+	}
+	return path
 }
 
 // Build constructs the full impact network.
 func (nb *NetworkBuilder) Build() *ImpactNetwork {
 	network := &ImpactNetwork{
-		GeneratedAt: time.Now(),
-		Nodes:       make(map[string]*NetworkNode),
-		Edges:       []NetworkEdge{},
-		Clusters:    []BeadCluster{},
+		GeneratedAt:    time.Now(),
+		Nodes:          make(map[string]*NetworkNode),
+		Edges:          []NetworkEdge{},
+		Clusters:       []BeadCluster{},
+		ChangeWeighted: nb.opts.WeightBySharedLines,
 	}
 
 	if nb.report == nil {
@@ -199,9 +312,11 @@ func (nb *NetworkBuilder) Build() *ImpactNetwork {
 	for _, edge := range network.Edges {
 		if node, ok := network.Nodes[edge.FromBead]; ok {
 			node.Degree++
+			node.Strength += edge.Weight
 		}
 		if node, ok := network.Nodes[edge.ToBead]; ok {
 			node.Degree++
+			node.Strength += edge.Weight
 		}
 	}
 
@@ -270,29 +385,49 @@ func (nb *NetworkBuilder) addSharedCommitEdges(network *ImpactNetwork) {
 }
 
 // addSharedFileEdges adds edges for beads that touch the same files.
+// A renamed file counts as one file under all of its names.
 func (nb *NetworkBuilder) addSharedFileEdges(network *ImpactNetwork) {
 	// Track edges we've already added (to avoid duplicates and combine with commit edges)
 	edgeSet := make(map[string]bool)
 	edgeWeights := make(map[string]int)
 	edgeDetails := make(map[string][]string)
 
+	fileToBeads := make(map[string][]string)
+	for beadID, files := range nb.beadFiles {
+		for filePath := range files {
+			fileToBeads[filePath] = append(fileToBeads[filePath], beadID)
+		}
+	}
+	filePaths := make([]string, 0, len(fileToBeads))
+	for filePath := range fileToBeads {
+		filePaths = append(filePaths, filePath)
+	}
+	sort.Strings(filePaths)
+
 	// For each file, find all beads that touched it
-	for filePath, refs := range nb.fileIndex.FileToBeads {
+	for _, filePath := range filePaths {
+		refs := fileToBeads[filePath]
 		if len(refs) < 2 {
 			continue
 		}
+		sort.Strings(refs)
 
 		// Create edges between all pairs of beads touching this file
 		for i := 0; i < len(refs); i++ {
 			for j := i + 1; j < len(refs); j++ {
-				beadA, beadB := refs[i].BeadID, refs[j].BeadID
+				beadA, beadB := refs[i], refs[j]
 				// Ensure consistent ordering
 				if beadA > beadB {
 					beadA, beadB = beadB, beadA
 				}
 				key := beadA + ":" + beadB + ":file"
 
-				edgeWeights[key]++
+				weight := 1
+				if nb.opts.WeightBySharedLines {
+					shared := min(nb.beadFiles[beadA][filePath], nb.beadFiles[beadB][filePath])
+					weight = int(math.Ceil(math.Log2(1 + float64(shared))))
+				}
+				edgeWeights[key] += weight
 				if !edgeSet[key] {
 					edgeSet[key] = true
 				}
@@ -442,6 +577,7 @@ func (nb *NetworkBuilder) detectClusters(network *ImpactNetwork) {
 
 		// Only create cluster if it has multiple beads
 		if len(component) >= 2 {
+			sort.Strings(component)
 			cluster := nb.buildCluster(clusterID, component, network)
 			network.Clusters = append(network.Clusters, cluster)
 
@@ -458,7 +594,10 @@ func (nb *NetworkBuilder) detectClusters(network *ImpactNetwork) {
 
 	// Sort clusters by size (largest first)
 	sort.Slice(network.Clusters, func(i, j int) bool {
-		return len(network.Clusters[i].BeadIDs) > len(network.Clusters[j].BeadIDs)
+		if len(network.Clusters[i].BeadIDs) != len(network.Clusters[j].BeadIDs) {
+			return len(network.Clusters[i].BeadIDs) > len(network.Clusters[j].BeadIDs)
+		}
+		return network.Clusters[i].BeadIDs[0] < network.Clusters[j].BeadIDs[0]
 	})
 
 	// Re-number cluster IDs after sorting
@@ -673,6 +812,41 @@ func (nb *NetworkBuilder) calculateStats(network *ImpactNetwork) {
 			stats.LargestCluster = len(cluster.BeadIDs)
 		}
 	}
+
+	// Find largest connected component (any edge weight)
+	adj := make(map[string][]string)
+	for _, edge := range network.Edges {
+		adj[edge.FromBead] = append(adj[edge.FromBead], edge.ToBead)
+		adj[edge.ToBead] = append(adj[edge.ToBead], edge.FromBead)
+	}
+	visited := make(map[string]bool)
+	for beadID := range network.Nodes {
+		if visited[beadID] {
+			continue
+		}
+		size := 0
+		stack := []string{beadID}
+		visited[beadID] = true
+		for len(stack) > 0 {
+			current := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			size++
+			for _, neighbor := range adj[current] {
+				if !visited[neighbor] {
+					visited[neighbor] = true
+					stack = append(stack, neighbor)
+				}
+			}
+		}
+		if size > stats.LargestComponent {
+			stats.LargestComponent = size
+		}
+	}
+
+	if stats.TotalNodes > 0 {
+		stats.LargestClusterFraction = float64(stats.LargestCluster) / float64(stats.TotalNodes)
+		stats.LargestComponentFraction = float64(stats.LargestComponent) / float64(stats.TotalNodes)
+	}
 }
 
 // GetSubNetwork returns a subnetwork centered on a specific bead with given depth.
@@ -726,11 +900,12 @@ func (network *ImpactNetwork) GetSubNetwork(beadID string, depth int) *ImpactNet
 
 	// Build subnetwork
 	subNetwork := &ImpactNetwork{
-		GeneratedAt: network.GeneratedAt,
-		DataHash:    network.DataHash,
-		Nodes:       make(map[string]*NetworkNode),
-		Edges:       []NetworkEdge{},
-		Clusters:    []BeadCluster{},
+		GeneratedAt:    network.GeneratedAt,
+		DataHash:       network.DataHash,
+		Nodes:          make(map[string]*NetworkNode),
+		Edges:          []NetworkEdge{},
+		Clusters:       []BeadCluster{},
+		ChangeWeighted: network.ChangeWeighted,
 	}
 
 	// Copy relevant nodes
@@ -802,7 +977,16 @@ func (network *ImpactNetwork) ToResult(beadID string, depth int) *ImpactNetworkR
 		nodes = append(nodes, *node)
 	}
 	sort.Slice(nodes, func(i, j int) bool {
-		return nodes[i].Degree > nodes[j].Degree
+		if network.ChangeWeighted && nodes[i].Strength != nodes[j].Strength {
+			return nodes[i].Strength > nodes[j].Strength
+		}
+		if nodes[i].Degree != nodes[j].Degree {
+			return nodes[i].Degree > nodes[j].Degree
+		}
+		if nodes[i].Strength != nodes[j].Strength {
+			return nodes[i].Strength > nodes[j].Strength
+		}
+		return nodes[i].BeadID < nodes[j].BeadID
 	})
 
 	nodeLimit := 10
@@ -170,6 +170,31 @@ func TestBuildNetwork(t *testing.T) {
 		if network.Stats.TotalEdges == 0 {
 			t.Error("Expected edges in stats")
 		}
+
+		// 4 typed edges (commit+file for 001-002 and 002-003) over 4 nodes.
+		if network.Stats.AvgDegree != 2 {
+			t.Errorf("Expected avg degree 2, got %v", network.Stats.AvgDegree)
+		}
+		// 4 edges out of 6 possible pairs.
+		if got := network.Stats.Density; got < 0.666 || got > 0.667 {
+			t.Errorf("Expected density 2/3, got %v", got)
+		}
+		// No edge reaches the clustering weight threshold.
+		if network.Stats.LargestCluster != 0 || network.Stats.LargestClusterFraction != 0 {
+			t.Errorf("Expected no clusters, got largest %d (%v)", network.Stats.LargestCluster, network.Stats.LargestClusterFraction)
+		}
+		// bv-001, bv-002 and bv-003 are connected; bv-004 is isolated.
+		if network.Stats.LargestComponent != 3 {
+			t.Errorf("Expected largest component 3, got %d", network.Stats.LargestComponent)
+		}
+		if network.Stats.LargestComponentFraction != 0.75 {
+			t.Errorf("Expected largest component fraction 0.75, got %v", network.Stats.LargestComponentFraction)
+		}
+
+		result := network.ToResult("", 0)
+		if result.Stats.LargestComponent != 3 || result.Stats.Density != network.Stats.Density {
+			t.Errorf("Expected ToResult to carry the shape stats, got %+v", result.Stats)
+		}
 	})
 }
 
@@ -753,3 +778,127 @@ func TestEmptyHistoryReport(t *testing.T) {
 		t.Errorf("Expected 0 total nodes in stats, got %d", network.Stats.TotalNodes)
 	}
 }
+
+// TestSharedFileEdgeFollowsRename tests that beads touching a file before and
+// after a rename are still linked by a shared-file edge
+func TestSharedFileEdgeFollowsRename(t *testing.T) {
+	now := time.Now()
+	report := &HistoryReport{
+		GeneratedAt: now,
+		Histories: map[string]BeadHistory{
+			"bv-old": {
+				BeadID: "bv-old",
+				Title:  "Token parsing",
+				Status: "closed",
+				Commits: []CorrelatedCommit{
+					{SHA: "aaa111", ShortSHA: "aaa111", Files: []FileChange{{Path: "auth/token.go", Action: "M"}}},
+				},
+			},
+			"bv-rename": {
+				BeadID: "bv-rename",
+				Title:  "Rename token file",
+				Status: "closed",
+				Commits: []CorrelatedCommit{
+					{SHA: "bbb222", ShortSHA: "bbb222", Files: []FileChange{{Path: "auth/tokens.go", OldPath: "auth/token.go", Action: "R"}}},
+				},
+			},
+			"bv-new": {
+				BeadID: "bv-new",
+				Title:  "Token refresh",
+				Status: "open",
+				Commits: []CorrelatedCommit{
+					{SHA: "ccc333", ShortSHA: "ccc333", Files: []FileChange{{Path: "auth/tokens.go", Action: "M"}}},
+				},
+			},
+		},
+		CommitIndex: CommitIndex{
+			"aaa111": {"bv-old"},
+			"bbb222": {"bv-rename"},
+			"ccc333": {"bv-new"},
+		},
+	}
+
+	network := NewNetworkBuilder(report).Build()
+
+	var found *NetworkEdge
+	for i, edge := range network.Edges {
+		if edge.EdgeType == EdgeSharedFile && edge.FromBead == "bv-new" && edge.ToBead == "bv-old" {
+			found = &network.Edges[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("Expected a shared-file edge between bv-old and bv-new across the rename, got %+v", network.Edges)
+	}
+	if len(found.Details) != 1 || found.Details[0] != "auth/tokens.go" {
+		t.Errorf("Expected the edge to name the current path auth/tokens.go, got %v", found.Details)
+	}
+	if network.Nodes["bv-rename"].Degree != 2 {
+		t.Errorf("Expected the renaming bead to link to both others, got degree %d", network.Nodes["bv-rename"].Degree)
+	}
+}
+
+// TestChangeWeightedSharedFileEdges tests that, with change weighting enabled,
+// two large edits to a file couple beads more than two one-line touches
+func TestChangeWeightedSharedFileEdges(t *testing.T) {
+	now := time.Now()
+	touch := func(id, sha string, lines int) BeadHistory {
+		return BeadHistory{
+			BeadID: id,
+			Title:  id,
+			Status: "open",
+			Commits: []CorrelatedCommit{{
+				SHA:       sha,
+				ShortSHA:  sha,
+				Timestamp: now,
+				Files:     []FileChange{{Path: "auth/token.go", Action: "M", Insertions: lines / 2, Deletions: lines - lines/2}},
+			}},
+		}
+	}
+	report := &HistoryReport{
+		GeneratedAt: now,
+		Histories: map[string]BeadHistory{
+			"bv-big1":   touch("bv-big1", "a1", 200),
+			"bv-big2":   touch("bv-big2", "a2", 180),
+			"bv-small1": touch("bv-small1", "b1", 1),
+			"bv-small2": touch("bv-small2", "b2", 1),
+		},
+		CommitIndex: CommitIndex{"a1": {"bv-big1"}, "a2": {"bv-big2"}, "b1": {"bv-small1"}, "b2": {"bv-small2"}},
+	}
+
+	fileWeight := func(network *ImpactNetwork, a, b string) int {
+		for _, edge := range network.Edges {
+			if edge.EdgeType == EdgeSharedFile && edge.FromBead == a && edge.ToBead == b {
+				return edge.Weight
+			}
+		}
+		t.Fatalf("missing shared-file edge %s-%s", a, b)
+		return 0
+	}
+
+	plain := NewNetworkBuilder(report).Build()
+	if fileWeight(plain, "bv-big1", "bv-big2") != fileWeight(plain, "bv-small1", "bv-small2") {
+		t.Fatal("Expected equal shared-file weights without change weighting")
+	}
+
+	weighted := NewNetworkBuilderWithOptions(report, nil, NetworkBuilderOptions{WeightBySharedLines: true}).Build()
+	big := fileWeight(weighted, "bv-big1", "bv-big2")
+	small := fileWeight(weighted, "bv-small1", "bv-small2")
+	if big <= small {
+		t.Fatalf("Expected the large-edit overlap to outweigh the trivial one, got %d vs %d", big, small)
+	}
+	if small != 1 || fileWeight(weighted, "bv-big1", "bv-small1") != 1 {
+		t.Errorf("Expected overlaps involving a one-line edit to weigh 1")
+	}
+
+	// Only the large-edit pair is strong enough to cluster.
+	if len(weighted.Clusters) != 1 || len(weighted.Clusters[0].BeadIDs) != 2 ||
+		weighted.Nodes["bv-big1"].ClusterID != 0 || weighted.Nodes["bv-small1"].ClusterID != -1 {
+		t.Errorf("Expected a single cluster of the large-edit beads, got %+v", weighted.Clusters)
+	}
+
+	result := weighted.ToResult("", 0)
+	if !result.Network.ChangeWeighted || result.TopConnected[0].BeadID != "bv-big1" || result.TopConnected[1].BeadID != "bv-big2" {
+		t.Errorf("Expected the large-edit beads to lead TopConnected, got %s, %s",
+			result.TopConnected[0].BeadID, result.TopConnected[1].BeadID)
+	}
+}
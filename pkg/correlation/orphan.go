@@ -193,7 +193,10 @@ func (od *OrphanDetector) DetectOrphans(opts ExtractOptions) (*OrphanReport, err
 
 	// Sort by suspicion score (highest first)
 	sort.Slice(report.Candidates, func(i, j int) bool {
-		return report.Candidates[i].SuspicionScore > report.Candidates[j].SuspicionScore
+		if report.Candidates[i].SuspicionScore != report.Candidates[j].SuspicionScore {
+			return report.Candidates[i].SuspicionScore > report.Candidates[j].SuspicionScore
+		}
+		return report.Candidates[i].SHA < report.Candidates[j].SHA
 	})
 
 	// Calculate stats
@@ -259,7 +262,10 @@ func (od *OrphanDetector) analyzeOrphan(orphan OrphanCommit) OrphanCandidate {
 
 	// Sort probable beads by confidence
 	sort.Slice(candidate.ProbableBeads, func(i, j int) bool {
-		return candidate.ProbableBeads[i].Confidence > candidate.ProbableBeads[j].Confidence
+		if candidate.ProbableBeads[i].Confidence != candidate.ProbableBeads[j].Confidence {
+			return candidate.ProbableBeads[i].Confidence > candidate.ProbableBeads[j].Confidence
+		}
+		return candidate.ProbableBeads[i].BeadID < candidate.ProbableBeads[j].BeadID
 	})
 
 	// Limit to top 3 probable beads
@@ -201,7 +201,10 @@ func (hr *HistoryReport) findFileOverlap(targetID string, targetFiles map[string
 
 	// Sort by relevance descending
 	sort.Slice(results, func(i, j int) bool {
-		return results[i].Relevance > results[j].Relevance
+		if results[i].Relevance != results[j].Relevance {
+			return results[i].Relevance > results[j].Relevance
+		}
+		return results[i].BeadID < results[j].BeadID
 	})
 
 	// Limit results
@@ -272,7 +275,10 @@ func (hr *HistoryReport) findCommitOverlap(targetID string, targetCommits map[st
 
 	// Sort by relevance descending
 	sort.Slice(results, func(i, j int) bool {
-		return results[i].Relevance > results[j].Relevance
+		if results[i].Relevance != results[j].Relevance {
+			return results[i].Relevance > results[j].Relevance
+		}
+		return results[i].BeadID < results[j].BeadID
 	})
 
 	// Limit results
@@ -371,7 +377,10 @@ func (hr *HistoryReport) findDependencyCluster(targetID string, opts RelatedWork
 
 	// Sort by relevance descending
 	sort.Slice(results, func(i, j int) bool {
-		return results[i].Relevance > results[j].Relevance
+		if results[i].Relevance != results[j].Relevance {
+			return results[i].Relevance > results[j].Relevance
+		}
+		return results[i].BeadID < results[j].BeadID
 	})
 
 	// Limit results
@@ -485,7 +494,10 @@ func (hr *HistoryReport) findConcurrent(targetID string, target BeadHistory, opt
 
 	// Sort by relevance descending
 	sort.Slice(results, func(i, j int) bool {
-		return results[i].Relevance > results[j].Relevance
+		if results[i].Relevance != results[j].Relevance {
+			return results[i].Relevance > results[j].Relevance
+		}
+		return results[i].BeadID < results[j].BeadID
 	})
 
 	// Limit results
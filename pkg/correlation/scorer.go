@@ -264,7 +264,13 @@ func (s *Scorer) MergeCommits(sources ...[]CorrelatedCommit) []CorrelatedCommit
 
 	// Sort by confidence descending
 	sort.Slice(merged, func(i, j int) bool {
-		return merged[i].Confidence > merged[j].Confidence
+		if merged[i].Confidence != merged[j].Confidence {
+			return merged[i].Confidence > merged[j].Confidence
+		}
+		if merged[i].BeadID != merged[j].BeadID {
+			return merged[i].BeadID < merged[j].BeadID
+		}
+		return merged[i].SHA < merged[j].SHA
 	})
 
 	return merged
@@ -451,8 +451,10 @@ func (b *BatchFileStatsExtractor) extractBatchFiles(shas []string) (map[string][
 		if len(parts) >= 2 {
 			action := parts[0]
 			path := parts[1]
+			oldPath := ""
 
 			if len(parts) == 3 && strings.HasPrefix(action, "R") {
+				oldPath = path
 				path = parts[2]
 				action = "R"
 			}
@@ -462,8 +464,9 @@ func (b *BatchFileStatsExtractor) extractBatchFiles(shas []string) (map[string][
 			}
 
 			currentFiles = append(currentFiles, FileChange{
-				Path:   path,
-				Action: action,
+				Path:    path,
+				Action:  action,
+				OldPath: oldPath,
 			})
 		}
 	}
@@ -76,7 +76,8 @@ func (c CorrelationMethod) IsValid() bool {
 // FileChange represents a single file modification within a commit
 type FileChange struct {
 	Path       string `json:"path"`
-	Action     string `json:"action"` // A=added, M=modified, D=deleted, R=renamed
+	Action     string `json:"action"`             // A=added, M=modified, D=deleted, R=renamed
+	OldPath    string `json:"old_path,omitempty"` // Previous path when Action is R
 	Insertions int    `json:"insertions"`
 	Deletions  int    `json:"deletions"`
 }
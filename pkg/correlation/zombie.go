@@ -0,0 +1,74 @@
+package correlation
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// DefaultZombieThreshold is how long an in-progress bead may go without
+// activity before it is reported as a zombie.
+const DefaultZombieThreshold = 14 * 24 * time.Hour
+
+// ZombieBead is an in-progress bead with no recent activity: neither its own
+// updated_at nor its most recent correlated commit falls within the threshold,
+// so the work has most likely been abandoned.
+type ZombieBead struct {
+	ID                string    `json:"id"`
+	Title             string    `json:"title"`
+	Assignee          string    `json:"assignee,omitempty"`
+	DaysSinceActivity int       `json:"days_since_activity"`
+	LastActivity      time.Time `json:"last_activity"`
+	LastCommit        string    `json:"last_commit,omitempty"` // Short SHA of the latest correlated commit
+	ActivitySource    string    `json:"activity_source"`       // "commit" or "updated_at"
+}
+
+// DetectZombies flags in-progress beads whose last activity is older than
+// threshold (<= 0 uses DefaultZombieThreshold). Activity is the later of the
+// bead's updated_at and its newest correlated commit in report made by now;
+// a nil report (no git history) falls back to updated_at alone. Ages are
+// measured from now, which is the snapshot's time for a historical view.
+// Results are sorted by days since activity, longest first.
+func DetectZombies(issues []model.Issue, report *HistoryReport, threshold time.Duration, now time.Time) []ZombieBead {
+	if threshold <= 0 {
+		threshold = DefaultZombieThreshold
+	}
+
+	var zombies []ZombieBead
+	for _, issue := range issues {
+		if issue.Status != model.StatusInProgress {
+			continue
+		}
+
+		last, source, sha := issue.UpdatedAt, "updated_at", ""
+		if report != nil {
+			for _, c := range report.Histories[issue.ID].Commits {
+				if c.Timestamp.After(last) && !c.Timestamp.After(now) {
+					last, source, sha = c.Timestamp, "commit", c.ShortSHA
+				}
+			}
+		}
+		if last.IsZero() || now.Sub(last) < threshold {
+			continue
+		}
+
+		zombies = append(zombies, ZombieBead{
+			ID:                issue.ID,
+			Title:             issue.Title,
+			Assignee:          issue.Assignee,
+			DaysSinceActivity: int(now.Sub(last).Hours() / 24),
+			LastActivity:      last,
+			LastCommit:        sha,
+			ActivitySource:    source,
+		})
+	}
+
+	sort.Slice(zombies, func(i, j int) bool {
+		if zombies[i].DaysSinceActivity != zombies[j].DaysSinceActivity {
+			return zombies[i].DaysSinceActivity > zombies[j].DaysSinceActivity
+		}
+		return zombies[i].ID < zombies[j].ID
+	})
+	return zombies
+}
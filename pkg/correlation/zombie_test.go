@@ -0,0 +1,72 @@
+package correlation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestDetectZombies_UsesLastCorrelatedCommit(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	longAgo := now.AddDate(0, 0, -60)
+
+	issues := []model.Issue{
+		// Claimed long ago, last commit 40 days ago: a zombie.
+		{ID: "bv-old", Title: "Abandoned refactor", Status: model.StatusInProgress, Assignee: "alice", UpdatedAt: longAgo},
+		// Claimed long ago, but a commit landed yesterday: still alive.
+		{ID: "bv-live", Title: "Active work", Status: model.StatusInProgress, UpdatedAt: longAgo},
+		// Stale but not in progress: not a zombie.
+		{ID: "bv-open", Title: "Backlog", Status: model.StatusOpen, UpdatedAt: longAgo},
+	}
+	report := &HistoryReport{Histories: map[string]BeadHistory{
+		"bv-old": {BeadID: "bv-old", Commits: []CorrelatedCommit{
+			{SHA: "aaa1111", ShortSHA: "aaa1111", Timestamp: now.AddDate(0, 0, -50)},
+			{SHA: "bbb2222", ShortSHA: "bbb2222", Timestamp: now.AddDate(0, 0, -40)},
+		}},
+		"bv-live": {BeadID: "bv-live", Commits: []CorrelatedCommit{
+			{SHA: "ccc3333", ShortSHA: "ccc3333", Timestamp: now.AddDate(0, 0, -1)},
+		}},
+	}}
+
+	zombies := DetectZombies(issues, report, 0, now)
+	if len(zombies) != 1 {
+		t.Fatalf("expected 1 zombie, got %+v", zombies)
+	}
+	z := zombies[0]
+	if z.ID != "bv-old" || z.DaysSinceActivity != 40 || z.LastCommit != "bbb2222" || z.ActivitySource != "commit" || z.Assignee != "alice" {
+		t.Errorf("unexpected zombie: %+v", z)
+	}
+}
+
+func TestDetectZombies_FallsBackToUpdatedAtWithoutHistory(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	issues := []model.Issue{
+		{ID: "a", Status: model.StatusInProgress, UpdatedAt: now.AddDate(0, 0, -20)},
+		{ID: "b", Status: model.StatusInProgress, UpdatedAt: now.AddDate(0, 0, -3)},
+	}
+
+	zombies := DetectZombies(issues, nil, 7*24*time.Hour, now)
+	if len(zombies) != 1 || zombies[0].ID != "a" || zombies[0].ActivitySource != "updated_at" || zombies[0].DaysSinceActivity != 20 {
+		t.Fatalf("unexpected zombies: %+v", zombies)
+	}
+}
+
+func TestDetectZombies_IgnoresCommitsAfterNow(t *testing.T) {
+	// Viewed as of a past snapshot, commits made later are not activity yet
+	asOf := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	issues := []model.Issue{
+		{ID: "a", Status: model.StatusInProgress, UpdatedAt: asOf.AddDate(0, 0, -30)},
+	}
+	report := &HistoryReport{Histories: map[string]BeadHistory{
+		"a": {BeadID: "a", Commits: []CorrelatedCommit{
+			{SHA: "ddd4444", ShortSHA: "ddd4444", Timestamp: asOf.AddDate(0, 0, -20)},
+			{SHA: "eee5555", ShortSHA: "eee5555", Timestamp: asOf.AddDate(0, 0, 5)},
+		}},
+	}}
+
+	zombies := DetectZombies(issues, report, 0, asOf)
+	if len(zombies) != 1 || zombies[0].LastCommit != "ddd4444" || zombies[0].DaysSinceActivity != 20 {
+		t.Fatalf("expected a measured from ddd4444 at the as-of time, got %+v", zombies)
+	}
+}
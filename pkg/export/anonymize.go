@@ -0,0 +1,136 @@
+package export
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// Anonymizer scrubs beads and history for sharing. People are replaced by
+// pseudonyms derived from an HMAC of their name under the Anonymizer's key,
+// so a pseudonym is consistent within one export but cannot be reversed by
+// hashing a list of likely names or emails.
+type Anonymizer struct {
+	key []byte
+}
+
+// NewAnonymizer returns an Anonymizer keyed by salt. An empty salt draws a
+// random key, giving each export its own pseudonyms; pass the same salt to
+// keep pseudonyms comparable across exports.
+func NewAnonymizer(salt string) *Anonymizer {
+	if salt != "" {
+		return &Anonymizer{key: []byte(salt)}
+	}
+	key := make([]byte, 32)
+	rand.Read(key) // Never returns an error; it crashes the program instead
+	return &Anonymizer{key: key}
+}
+
+// Issues returns copies of issues that are safe to share externally: titles
+// become placeholders, free text (description, design, acceptance criteria,
+// notes, comments, external refs) is dropped, and people are replaced by
+// pseudonyms (see Name). IDs, statuses, types, priorities, labels,
+// timestamps and dependencies are kept, so graph metrics are unchanged.
+func (a *Anonymizer) Issues(issues []model.Issue) []model.Issue {
+	out := make([]model.Issue, len(issues))
+	for i, iss := range issues {
+		anon := iss.Clone()
+		anon.Title = anonymousTitle(iss.IssueType, iss.ID)
+		anon.ContentHash = ""
+		anon.Description = ""
+		anon.Design = ""
+		anon.AcceptanceCriteria = ""
+		anon.Notes = ""
+		anon.ExternalRef = nil
+		anon.Comments = nil
+		anon.Assignee = a.Name(iss.Assignee)
+		for _, dep := range anon.Dependencies {
+			if dep != nil {
+				dep.CreatedBy = a.Name(dep.CreatedBy)
+			}
+		}
+		out[i] = anon
+	}
+	return out
+}
+
+// Name maps a person's name or email to a pseudonym such as "user-3f9a1c2e",
+// so the same person stays recognisable across beads without being
+// identified. Names are compared case-insensitively. Empty names stay empty.
+func (a *Anonymizer) Name(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(strings.ToLower(name)))
+	return "user-" + hex.EncodeToString(mac.Sum(nil)[:4])
+}
+
+// HistoryReport strips commit messages, author emails and correlation
+// reasons from a history report in place and pseudonymizes author names,
+// matching the titles and people in anonymized, which Issues returned.
+func (a *Anonymizer) HistoryReport(report *correlation.HistoryReport, anonymized []model.Issue) {
+	if report == nil {
+		return
+	}
+	titles := make(map[string]string, len(anonymized))
+	for _, iss := range anonymized {
+		titles[iss.ID] = iss.Title
+	}
+	anonEvent := func(ev *correlation.BeadEvent) {
+		if ev == nil {
+			return
+		}
+		ev.CommitMsg = ""
+		ev.Author = a.Name(ev.Author)
+		ev.AuthorEmail = ""
+	}
+	for id, h := range report.Histories {
+		h.Title = titles[id]
+		h.LastAuthor = a.Name(h.LastAuthor)
+		for i := range h.Events {
+			anonEvent(&h.Events[i])
+		}
+		anonEvent(h.Milestones.Created)
+		anonEvent(h.Milestones.Claimed)
+		anonEvent(h.Milestones.Closed)
+		anonEvent(h.Milestones.Reopened)
+		for i := range h.Commits {
+			c := &h.Commits[i]
+			c.Message = ""
+			c.Author = a.Name(c.Author)
+			c.AuthorEmail = ""
+			c.Reason = ""
+		}
+		report.Histories[id] = h
+	}
+}
+
+// anonymousTitle builds a placeholder like "Bug bv-12" from the issue type
+// and ID, which are kept anyway; deriving it from the ID keeps titles stable
+// across snapshots so diffs do not report spurious renames.
+func anonymousTitle(t model.IssueType, id string) string {
+	label := strings.TrimSpace(string(t))
+	if label == "" {
+		label = "issue"
+	}
+	return fmt.Sprintf("%s %s", capitalize(label), id)
+}
+
+// capitalize upper-cases the first rune of s.
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
@@ -0,0 +1,139 @@
+package export
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestAnonymizerIssues_StripsSensitiveFieldsKeepsTopology(t *testing.T) {
+	ref := "https://tracker.example.com/SECRET-1"
+	issues := []model.Issue{
+		{
+			ID: "bv-1", Title: "Secret payroll migration", Description: "Move salaries to Acme",
+			Design: "Confidential design", AcceptanceCriteria: "- [ ] secret check", Notes: "private note",
+			Status: model.StatusOpen, Priority: 1, IssueType: model.TypeTask, Assignee: "alice@example.com",
+			ExternalRef: &ref, Labels: []string{"backend"},
+			Comments: []*model.Comment{{ID: 1, IssueID: "bv-1", Author: "bob", Text: "secret comment"}},
+		},
+		{
+			ID: "bv-2", Title: "Secret launch", Status: model.StatusInProgress, Priority: 0, IssueType: model.TypeFeature,
+			Assignee:     "alice@example.com",
+			Dependencies: []*model.Dependency{{IssueID: "bv-2", DependsOnID: "bv-1", Type: model.DepBlocks, CreatedBy: "carol"}},
+		},
+		{
+			ID: "bv-3", Title: "Secret cleanup", Status: model.StatusClosed, IssueType: model.TypeBug,
+			Dependencies: []*model.Dependency{{IssueID: "bv-3", DependsOnID: "bv-2", Type: model.DepBlocks}},
+		},
+	}
+
+	anon := NewAnonymizer("").Issues(issues)
+
+	raw, err := json.Marshal(anon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"Secret", "secret", "Acme", "Confidential", "private", "alice", "bob", "carol", "tracker.example.com"} {
+		if strings.Contains(string(raw), secret) {
+			t.Errorf("anonymized JSON still contains %q: %s", secret, raw)
+		}
+	}
+	if issues[0].Title != "Secret payroll migration" || issues[1].Dependencies[0].CreatedBy != "carol" {
+		t.Fatal("Issues must not modify its input")
+	}
+
+	for i := range issues {
+		a, o := anon[i], issues[i]
+		if a.ID != o.ID || a.Status != o.Status || a.IssueType != o.IssueType || a.Priority != o.Priority || !reflect.DeepEqual(a.Labels, o.Labels) {
+			t.Errorf("structural fields changed for %s: %+v", o.ID, a)
+		}
+		if len(a.Dependencies) != len(o.Dependencies) {
+			t.Fatalf("dependencies changed for %s", o.ID)
+		}
+		for j, dep := range a.Dependencies {
+			if dep.DependsOnID != o.Dependencies[j].DependsOnID || dep.Type != o.Dependencies[j].Type {
+				t.Errorf("dependency %d of %s changed: %+v", j, o.ID, dep)
+			}
+		}
+	}
+	if anon[0].Title != "Task bv-1" {
+		t.Errorf("placeholder title = %q, want %q", anon[0].Title, "Task bv-1")
+	}
+	if anon[0].Assignee == "" || anon[0].Assignee != anon[1].Assignee {
+		t.Errorf("the same assignee should map to the same pseudonym: %q vs %q", anon[0].Assignee, anon[1].Assignee)
+	}
+
+	before := analysis.NewAnalyzer(issues).Analyze()
+	after := analysis.NewAnalyzer(anon).Analyze()
+	if !reflect.DeepEqual(before.PageRank(), after.PageRank()) || !reflect.DeepEqual(before.CriticalPathScore(), after.CriticalPathScore()) {
+		t.Error("graph metrics should be unchanged by anonymization")
+	}
+	if analysis.ComputeDataHash(anon) == analysis.ComputeDataHash(issues) {
+		t.Error("data hash should reflect the anonymized content")
+	}
+}
+
+func TestAnonymizerHistoryReport_StripsCommitMessages(t *testing.T) {
+	a := NewAnonymizer("")
+	anon := a.Issues([]model.Issue{{ID: "bv-1", Title: "Secret", IssueType: model.TypeTask}})
+	created := &correlation.BeadEvent{BeadID: "bv-1", CommitMsg: "secret message", Author: "Dana", AuthorEmail: "dana@example.com", Timestamp: time.Now()}
+	report := &correlation.HistoryReport{Histories: map[string]correlation.BeadHistory{
+		"bv-1": {
+			BeadID:     "bv-1",
+			Title:      "Secret",
+			Events:     []correlation.BeadEvent{*created},
+			Milestones: correlation.BeadMilestones{Created: created},
+			Commits:    []correlation.CorrelatedCommit{{SHA: "abc123", Message: "secret fix", Author: "Dana", AuthorEmail: "dana@example.com", Reason: "mentions secret"}},
+			LastAuthor: "Dana",
+		},
+	}}
+
+	a.HistoryReport(report, anon)
+
+	raw, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"Secret", "secret", "Dana", "dana@"} {
+		if strings.Contains(string(raw), secret) {
+			t.Errorf("anonymized history still contains %q: %s", secret, raw)
+		}
+	}
+	if !strings.Contains(string(raw), "abc123") {
+		t.Error("commit SHAs should be kept")
+	}
+}
+
+func TestAnonymizer_NamesAreKeyed(t *testing.T) {
+	a, b := NewAnonymizer(""), NewAnonymizer("")
+	if a.Name("Alice@Example.com") != a.Name(" alice@example.com") {
+		t.Error("one Anonymizer should give a name the same pseudonym regardless of case")
+	}
+	if a.Name("alice@example.com") == b.Name("alice@example.com") {
+		t.Error("Anonymizers with random keys should give different pseudonyms")
+	}
+	if NewAnonymizer("s3cret").Name("alice") != NewAnonymizer("s3cret").Name("alice") {
+		t.Error("Anonymizers with the same salt should give the same pseudonyms")
+	}
+	if got := a.Name("alice"); !strings.HasPrefix(got, "user-") || len(got) != len("user-")+8 {
+		t.Errorf("pseudonym = %q, want user- and 8 hex digits", got)
+	}
+	if a.Name("") != "" {
+		t.Error("empty names should stay empty")
+	}
+}
+
+func TestAnonymousTitle_CapitalizesFirstRune(t *testing.T) {
+	if got := anonymousTitle("élan", "bv-1"); got != "Élan bv-1" {
+		t.Errorf("anonymousTitle = %q, want %q", got, "Élan bv-1")
+	}
+	if got := anonymousTitle("", "bv-2"); got != "Issue bv-2" {
+		t.Errorf("anonymousTitle = %q, want %q", got, "Issue bv-2")
+	}
+}
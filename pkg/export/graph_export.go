@@ -1,11 +1,14 @@
 package export
 
 import (
+	"container/heap"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"hash/fnv"
 	"sort"
 	"strings"
+	"unicode"
 
 	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
@@ -18,15 +21,37 @@ const (
 	GraphFormatJSON    GraphExportFormat = "json"
 	GraphFormatDOT     GraphExportFormat = "dot"
 	GraphFormatMermaid GraphExportFormat = "mermaid"
+	GraphFormatDSM     GraphExportFormat = "dsm"
 )
 
+// GraphClusterBy selects how DOT output groups nodes into subgraph clusters.
+type GraphClusterBy string
+
+const (
+	GraphClusterNone      GraphClusterBy = ""
+	GraphClusterType      GraphClusterBy = "type"
+	GraphClusterStatus    GraphClusterBy = "status"
+	GraphClusterComponent GraphClusterBy = "component"
+)
+
+// ParseGraphClusterBy validates a --cluster-by value (case-insensitive).
+func ParseGraphClusterBy(s string) (GraphClusterBy, error) {
+	switch c := GraphClusterBy(strings.ToLower(strings.TrimSpace(s))); c {
+	case GraphClusterNone, GraphClusterType, GraphClusterStatus, GraphClusterComponent:
+		return c, nil
+	default:
+		return "", fmt.Errorf("invalid cluster mode %q (use type, status, or component)", s)
+	}
+}
+
 // GraphExportConfig configures graph export behavior.
 type GraphExportConfig struct {
-	Format   GraphExportFormat // Output format (json, dot, mermaid)
-	Label    string            // Filter to specific label
-	Root     string            // Subgraph from specific root
-	Depth    int               // Max depth for subgraph (0 = unlimited)
-	DataHash string            // Hash of input data for provenance
+	Format    GraphExportFormat // Output format (json, dot, mermaid, dsm)
+	Label     string            // Filter to specific label
+	Root      string            // Subgraph from specific root
+	Depth     int               // Max depth for subgraph (0 = unlimited)
+	ClusterBy GraphClusterBy    // DOT only: group nodes into subgraph clusters
+	DataHash  string            // Hash of input data for provenance
 }
 
 // GraphExportResult contains the exported graph and metadata.
@@ -39,6 +64,7 @@ type GraphExportResult struct {
 	Explanation    GraphExplanation  `json:"explanation"`
 	DataHash       string            `json:"data_hash,omitempty"`
 	Adjacency      *AdjacencyGraph   `json:"adjacency,omitempty"`
+	Matrix         *DependencyMatrix `json:"matrix,omitempty"`
 }
 
 // GraphExplanation provides context for AI agents.
@@ -71,10 +97,37 @@ type AdjacencyEdge struct {
 	Type string `json:"type"` // "blocks" or "related"
 }
 
+// DependencyMatrix is a design structure matrix (DSM), stored sparsely: Cells
+// lists only the filled cells, where the bead Order[Row] depends on
+// Order[Col]. Beads are ordered topologically with dependencies first, so an
+// acyclic graph is strictly lower-triangular and every cell above the
+// diagonal is a back edge from a cycle.
+type DependencyMatrix struct {
+	Order     []string        `json:"order"`
+	Ordering  string          `json:"ordering"`
+	Cells     []DSMCell       `json:"cells"`
+	BackEdges []AdjacencyEdge `json:"back_edges"`
+}
+
+// DSMCell is a filled cell of a DependencyMatrix.
+type DSMCell struct {
+	Row  int    `json:"row"`
+	Col  int    `json:"col"`
+	Type string `json:"type"`
+}
+
 // ExportGraph exports the dependency graph in the specified format.
 func ExportGraph(issues []model.Issue, stats *analysis.GraphStats, config GraphExportConfig) (*GraphExportResult, error) {
+	clusterBy, err := ParseGraphClusterBy(string(config.ClusterBy))
+	if err != nil {
+		return nil, err
+	}
+
 	// Filter issues if needed
-	filteredIssues := filterIssues(issues, config)
+	filteredIssues, err := filterIssues(issues, config)
+	if err != nil {
+		return nil, err
+	}
 
 	if len(filteredIssues) == 0 {
 		return &GraphExportResult{
@@ -126,7 +179,7 @@ func ExportGraph(issues []model.Issue, stats *analysis.GraphStats, config GraphE
 
 	switch config.Format {
 	case GraphFormatDOT:
-		graph := generateDOT(filteredIssues, issueIDs, stats)
+		graph := generateDOT(filteredIssues, issueIDs, stats, clusterBy)
 		result.Graph = graph
 		result.Explanation = GraphExplanation{
 			What:        "Dependency graph in Graphviz DOT format",
@@ -143,6 +196,16 @@ func ExportGraph(issues []model.Issue, stats *analysis.GraphStats, config GraphE
 			WhenToUse:   "When you need an embeddable diagram for documentation or GitHub issues",
 		}
 
+	case GraphFormatDSM:
+		matrix := generateDSM(filteredIssues, issueIDs)
+		result.Matrix = matrix
+		result.Graph = dsmCSV(matrix)
+		result.Explanation = GraphExplanation{
+			What:        "Sparse dependency structure matrix: each cell is a row bead depending on a column bead; graph holds the same cells as CSV",
+			HowToRender: "Save graph to file.csv and load it into a DSM tool or pivot it in a spreadsheet; cells above the diagonal (col > row) are back_edges",
+			WhenToUse:   "When you need matrix-based dependency analysis or want to spot feedback loops at a glance",
+		}
+
 	case GraphFormatJSON:
 		fallthrough
 	default:
@@ -158,18 +221,20 @@ func ExportGraph(issues []model.Issue, stats *analysis.GraphStats, config GraphE
 	return result, nil
 }
 
-// filterIssues applies label and root filters to the issue list.
-func filterIssues(issues []model.Issue, config GraphExportConfig) []model.Issue {
+// filterIssues applies label and root filters to the issue list. The label
+// may be an exact name, a glob, or a /regex/ (see analysis.ParseLabelPattern).
+func filterIssues(issues []model.Issue, config GraphExportConfig) ([]model.Issue, error) {
 	// Filter by label first
 	filtered := issues
 	if config.Label != "" {
+		matcher, err := analysis.ParseLabelPattern(config.Label)
+		if err != nil {
+			return nil, err
+		}
 		var labeled []model.Issue
 		for _, i := range issues {
-			for _, l := range i.Labels {
-				if strings.EqualFold(l, config.Label) {
-					labeled = append(labeled, i)
-					break
-				}
+			if matcher.MatchAny(i.Labels) {
+				labeled = append(labeled, i)
 			}
 		}
 		filtered = labeled
@@ -180,7 +245,7 @@ func filterIssues(issues []model.Issue, config GraphExportConfig) []model.Issue
 		filtered = extractSubgraph(filtered, config.Root, config.Depth)
 	}
 
-	return filtered
+	return filtered, nil
 }
 
 // extractSubgraph extracts a subgraph starting from a root node.
@@ -236,8 +301,10 @@ func extractSubgraph(issues []model.Issue, rootID string, maxDepth int) []model.
 	return result
 }
 
-// generateDOT creates a Graphviz DOT format graph.
-func generateDOT(issues []model.Issue, issueIDs map[string]bool, stats *analysis.GraphStats) string {
+// generateDOT creates a Graphviz DOT format graph. When clusterBy is set,
+// nodes are wrapped in labelled "subgraph cluster_*" blocks; edges are emitted
+// at the top level so links between clusters are preserved.
+func generateDOT(issues []model.Issue, issueIDs map[string]bool, stats *analysis.GraphStats, clusterBy GraphClusterBy) string {
 	var sb strings.Builder
 
 	sb.WriteString("digraph G {\n")
@@ -259,8 +326,7 @@ func generateDOT(issues []model.Issue, issueIDs map[string]bool, stats *analysis
 		return sortedIssues[i].ID < sortedIssues[j].ID
 	})
 
-	// Nodes
-	for _, i := range sortedIssues {
+	writeNode := func(indent string, i model.Issue) {
 		// Truncate title first to ensure we don't split escape sequences later
 		rawTitle := i.Title
 		if len(rawTitle) > 30 {
@@ -289,8 +355,27 @@ func generateDOT(issues []model.Issue, issueIDs map[string]bool, stats *analysis
 			}
 		}
 
-		sb.WriteString(fmt.Sprintf("    \"%s\" [label=\"%s\", fillcolor=\"%s\", style=filled, penwidth=%.1f];\n",
-			sanitizeDOTID(i.ID), label, color, penwidth))
+		sb.WriteString(fmt.Sprintf("%s\"%s\" [label=\"%s\", shape=%s, fillcolor=\"%s\", style=filled, penwidth=%.1f];\n",
+			indent, sanitizeDOTID(i.ID), label, dotTypeShape(i.IssueType), color, penwidth))
+	}
+
+	// Nodes
+	if clusterBy == GraphClusterNone {
+		for _, i := range sortedIssues {
+			writeNode("    ", i)
+		}
+	} else {
+		for idx, c := range dotClusters(sortedIssues, issueIDs, clusterBy) {
+			sb.WriteString(fmt.Sprintf("    subgraph \"cluster_%s\" {\n", sanitizeDOTClusterName(string(clusterBy)+"_"+c.key)))
+			sb.WriteString(fmt.Sprintf("        label=\"%s (%d)\";\n", sanitizeDOTID(c.label), len(c.members)))
+			sb.WriteString(fmt.Sprintf("        style=\"rounded,filled\"; fillcolor=\"%s\"; color=\"#B0BEC5\";\n",
+				dotClusterBackgrounds[idx%len(dotClusterBackgrounds)]))
+			sb.WriteString("        fontname=\"Helvetica\"; fontsize=11;\n")
+			for _, i := range c.members {
+				writeNode("        ", i)
+			}
+			sb.WriteString("    }\n")
+		}
 	}
 
 	sb.WriteString("\n")
@@ -331,6 +416,129 @@ func generateDOT(issues []model.Issue, issueIDs map[string]bool, stats *analysis
 	return sb.String()
 }
 
+// dotClusterBackgrounds are light fills cycled across clusters so grouped
+// regions stay distinguishable without overpowering node status colors.
+var dotClusterBackgrounds = []string{
+	"#F3F6FB", "#FBF6EE", "#F1FAF3", "#FBF0F3", "#F5F1FB", "#EFF8FA",
+}
+
+// dotCluster is one subgraph cluster in DOT output.
+type dotCluster struct {
+	key     string
+	label   string
+	members []model.Issue
+}
+
+// dotClusters groups sorted issues by clusterBy. Clusters are ordered by key
+// (type/status) or by their smallest member ID (component); members keep the
+// input order.
+func dotClusters(sortedIssues []model.Issue, issueIDs map[string]bool, clusterBy GraphClusterBy) []dotCluster {
+	keyOf := make(map[string]string, len(sortedIssues))
+	switch clusterBy {
+	case GraphClusterType:
+		for _, i := range sortedIssues {
+			key := string(i.IssueType)
+			if key == "" {
+				key = "untyped"
+			}
+			keyOf[i.ID] = key
+		}
+	case GraphClusterStatus:
+		for _, i := range sortedIssues {
+			key := string(i.Status)
+			if key == "" {
+				key = "unknown"
+			}
+			keyOf[i.ID] = key
+		}
+	case GraphClusterComponent:
+		// Weakly connected components via union-find over exported edges
+		parent := make(map[string]string, len(sortedIssues))
+		var find func(string) string
+		find = func(id string) string {
+			if parent[id] != id {
+				parent[id] = find(parent[id])
+			}
+			return parent[id]
+		}
+		for _, i := range sortedIssues {
+			parent[i.ID] = i.ID
+		}
+		for _, i := range sortedIssues {
+			for _, dep := range i.Dependencies {
+				if dep == nil || !issueIDs[dep.DependsOnID] {
+					continue
+				}
+				a, b := find(i.ID), find(dep.DependsOnID)
+				if a == b {
+					continue
+				}
+				// Keep the smallest ID as root so numbering is stable
+				if b < a {
+					a, b = b, a
+				}
+				parent[b] = a
+			}
+		}
+		for _, i := range sortedIssues {
+			keyOf[i.ID] = find(i.ID)
+		}
+	}
+
+	byKey := make(map[string]*dotCluster)
+	var keys []string
+	for _, i := range sortedIssues {
+		key := keyOf[i.ID]
+		c, ok := byKey[key]
+		if !ok {
+			c = &dotCluster{key: key, label: key}
+			byKey[key] = c
+			keys = append(keys, key)
+		}
+		c.members = append(c.members, i)
+	}
+	if clusterBy != GraphClusterComponent {
+		sort.Strings(keys)
+	}
+
+	clusters := make([]dotCluster, 0, len(keys))
+	for n, key := range keys {
+		c := *byKey[key]
+		if clusterBy == GraphClusterComponent {
+			// Component roots are issue IDs; number them for readable labels
+			c.key = fmt.Sprintf("%d", n+1)
+			c.label = fmt.Sprintf("component %d", n+1)
+		}
+		clusters = append(clusters, c)
+	}
+	return clusters
+}
+
+// dotTypeShape returns the Graphviz shape matching the interactive viewer's
+// node shapes: bug=triangle, task=square, epic=diamond, others=circle.
+func dotTypeShape(t model.IssueType) string {
+	switch t {
+	case model.TypeBug:
+		return "triangle"
+	case model.TypeTask:
+		return "box"
+	case model.TypeEpic:
+		return "diamond"
+	default:
+		return "ellipse"
+	}
+}
+
+// sanitizeDOTClusterName reduces s to characters safe in a cluster name.
+func sanitizeDOTClusterName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
 // dotStatusColor returns a DOT-compatible color for a status.
 func dotStatusColor(status model.Status) string {
 	switch status {
@@ -353,23 +561,40 @@ func sanitizeDOTID(id string) string {
 	return strings.ReplaceAll(id, "\"", "\\\"")
 }
 
+// mermaidTitleWidth and mermaidTitleLines bound how long titles are wrapped
+// inside Mermaid node labels.
+const (
+	mermaidTitleWidth = 30
+	mermaidTitleLines = 3
+)
+
+// mermaidEdge is a dependency edge between two exported nodes.
+type mermaidEdge struct {
+	from, to string
+	blocks   bool
+}
+
 // generateMermaid creates a Mermaid diagram format graph.
+// Output is byte-for-byte deterministic: nodes are sorted by ID and edges by
+// (source, target), so diagrams diff cleanly between runs.
 func generateMermaid(issues []model.Issue, issueIDs map[string]bool) string {
 	var sb strings.Builder
 
 	sb.WriteString("graph TD\n")
 
-	// Class definitions for styling
+	// Class definitions for styling (one per status)
 	sb.WriteString("    classDef open fill:#50FA7B,stroke:#333,color:#000\n")
 	sb.WriteString("    classDef inprogress fill:#8BE9FD,stroke:#333,color:#000\n")
 	sb.WriteString("    classDef blocked fill:#FF5555,stroke:#333,color:#000\n")
 	sb.WriteString("    classDef closed fill:#6272A4,stroke:#333,color:#fff\n")
+	sb.WriteString("    classDef tombstone fill:#44475A,stroke:#333,color:#ccc,stroke-dasharray:3 3\n")
+	sb.WriteString("    classDef other fill:#F8F8F2,stroke:#333,color:#000\n")
 	sb.WriteString("\n")
 
 	// Sort issues for deterministic output
 	sortedIssues := make([]model.Issue, len(issues))
 	copy(sortedIssues, issues)
-	sort.Slice(sortedIssues, func(i, j int) bool {
+	sort.SliceStable(sortedIssues, func(i, j int) bool {
 		return sortedIssues[i].ID < sortedIssues[j].ID
 	})
 
@@ -405,61 +630,116 @@ func generateMermaid(issues []model.Issue, issueIDs map[string]bool) string {
 	// Nodes
 	for _, i := range sortedIssues {
 		safeID := getSafeID(i.ID)
-		safeTitle := sanitizeMermaidText(i.Title)
-		safeLabelID := sanitizeMermaidText(i.ID)
-
-		sb.WriteString(fmt.Sprintf("    %s[\"%s<br/>%s\"]\n", safeID, safeLabelID, safeTitle))
-
-		// Apply class based on status
-		var class string
-		switch i.Status {
-		case model.StatusOpen:
-			class = "open"
-		case model.StatusInProgress:
-			class = "inprogress"
-		case model.StatusBlocked:
-			class = "blocked"
-		case model.StatusClosed:
-			class = "closed"
+		label := escapeMermaidLabel(i.ID)
+		if title := wrapMermaidTitle(i.Title); title != "" {
+			label += "<br/>" + title
 		}
-		sb.WriteString(fmt.Sprintf("    class %s %s\n", safeID, class))
+
+		sb.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", safeID, label))
+		sb.WriteString(fmt.Sprintf("    class %s %s\n", safeID, mermaidStatusClass(i.Status)))
 	}
 
 	sb.WriteString("\n")
 
-	// Edges
+	// Edges, sorted by (source, target) on the original IDs
+	var edges []mermaidEdge
 	for _, i := range sortedIssues {
-		// Sort dependencies
-		deps := make([]*model.Dependency, len(i.Dependencies))
-		copy(deps, i.Dependencies)
-		sort.Slice(deps, func(a, b int) bool {
-			if deps[a] == nil {
-				return false
-			}
-			if deps[b] == nil {
-				return true
-			}
-			return deps[a].DependsOnID < deps[b].DependsOnID
-		})
-
-		for _, dep := range deps {
+		for _, dep := range i.Dependencies {
 			if dep == nil || !issueIDs[dep.DependsOnID] {
 				continue
 			}
+			edges = append(edges, mermaidEdge{from: i.ID, to: dep.DependsOnID, blocks: dep.Type == model.DepBlocks})
+		}
+	}
+	sort.SliceStable(edges, func(a, b int) bool {
+		if edges[a].from != edges[b].from {
+			return edges[a].from < edges[b].from
+		}
+		if edges[a].to != edges[b].to {
+			return edges[a].to < edges[b].to
+		}
+		return edges[a].blocks && !edges[b].blocks
+	})
 
-			safeFromID := getSafeID(i.ID)
-			safeToID := getSafeID(dep.DependsOnID)
+	for _, e := range edges {
+		linkStyle := "-.->" // Dashed for related
+		if e.blocks {
+			linkStyle = "==>" // Bold for blockers
+		}
+		sb.WriteString(fmt.Sprintf("    %s %s %s\n", getSafeID(e.from), linkStyle, getSafeID(e.to)))
+	}
 
-			linkStyle := "-.->" // Dashed for related
-			if dep.Type == model.DepBlocks {
-				linkStyle = "==>" // Bold for blockers
-			}
+	return sb.String()
+}
 
-			sb.WriteString(fmt.Sprintf("    %s %s %s\n", safeFromID, linkStyle, safeToID))
+// mermaidStatusClass maps a status to one of the classDefs emitted by generateMermaid.
+func mermaidStatusClass(status model.Status) string {
+	switch status {
+	case model.StatusOpen:
+		return "open"
+	case model.StatusInProgress:
+		return "inprogress"
+	case model.StatusBlocked:
+		return "blocked"
+	case model.StatusClosed:
+		return "closed"
+	case model.StatusTombstone:
+		return "tombstone"
+	default:
+		return "other"
+	}
+}
+
+// wrapMermaidTitle word-wraps a title to mermaidTitleWidth runes per line,
+// keeping at most mermaidTitleLines lines (the last ending in "..." when
+// truncated). Lines are escaped individually and joined with <br/>.
+func wrapMermaidTitle(title string) string {
+	words := strings.Fields(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, title))
+
+	var lines []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			lines = append(lines, string(current))
+			current = nil
 		}
 	}
+	for _, word := range words {
+		w := []rune(word)
+		// Hard-split words that cannot fit on a line by themselves
+		for len(w) > mermaidTitleWidth {
+			flush()
+			lines = append(lines, string(w[:mermaidTitleWidth]))
+			w = w[mermaidTitleWidth:]
+		}
+		if len(current) > 0 && len(current)+1+len(w) > mermaidTitleWidth {
+			flush()
+		}
+		if len(current) > 0 {
+			current = append(current, ' ')
+		}
+		current = append(current, w...)
+	}
+	flush()
 
-	return sb.String()
+	if len(lines) > mermaidTitleLines {
+		lines = lines[:mermaidTitleLines]
+		last := []rune(lines[mermaidTitleLines-1])
+		if len(last) > mermaidTitleWidth-3 {
+			last = last[:mermaidTitleWidth-3]
+		}
+		lines[mermaidTitleLines-1] = string(last) + "..."
+	}
+
+	for i, line := range lines {
+		lines[i] = escapeMermaidLabel(line)
+	}
+	return strings.Join(lines, "<br/>")
 }
 
 // generateAdjacency creates a JSON adjacency list representation.
@@ -535,6 +815,132 @@ func generateAdjacency(issues []model.Issue, issueIDs map[string]bool, stats *an
 	}
 }
 
+// dsmOrdering describes how generateDSM orders beads.
+const dsmOrdering = "topological: dependencies before dependents, ties by ID; in a cycle the bead with the fewest unplaced dependencies (then lowest ID) goes first"
+
+// generateDSM builds the dependency matrix over every dependency type, ordering
+// beads with Kahn's algorithm so dependencies come first.
+func generateDSM(issues []model.Issue, issueIDs map[string]bool) *DependencyMatrix {
+	dependsOn := make(map[string]map[string]string, len(issues)) // bead -> dependency -> type
+	dependents := make(map[string][]string, len(issues))
+	for _, i := range issues {
+		if dependsOn[i.ID] == nil {
+			dependsOn[i.ID] = make(map[string]string)
+		}
+		for _, dep := range i.Dependencies {
+			if dep == nil || !issueIDs[dep.DependsOnID] || dep.DependsOnID == i.ID {
+				continue
+			}
+			if _, dup := dependsOn[i.ID][dep.DependsOnID]; dup {
+				continue
+			}
+			depType := string(dep.Type)
+			if depType == "" {
+				depType = string(model.DepBlocks)
+			}
+			dependsOn[i.ID][dep.DependsOnID] = depType
+			dependents[dep.DependsOnID] = append(dependents[dep.DependsOnID], i.ID)
+		}
+	}
+
+	ids := make([]string, 0, len(dependsOn))
+	for id := range dependsOn {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	remaining := make(map[string]int, len(ids)) // unplaced dependencies per bead
+	ready := &dsmQueue{}
+	for _, id := range ids {
+		remaining[id] = len(dependsOn[id])
+		heap.Push(ready, dsmCandidate{id: id, remaining: remaining[id]})
+	}
+	placed := make(map[string]bool, len(ids))
+	order := make([]string, 0, len(ids))
+	for len(order) < len(ids) {
+		// Lowest ID among the ready beads; if a cycle leaves none ready, the
+		// bead closest to ready breaks it. Entries whose count has since
+		// dropped are stale and skipped.
+		next := heap.Pop(ready).(dsmCandidate)
+		if placed[next.id] || next.remaining != remaining[next.id] {
+			continue
+		}
+		placed[next.id] = true
+		order = append(order, next.id)
+		for _, d := range dependents[next.id] {
+			if !placed[d] {
+				remaining[d]--
+				heap.Push(ready, dsmCandidate{id: d, remaining: remaining[d]})
+			}
+		}
+	}
+
+	index := make(map[string]int, len(order))
+	for i, id := range order {
+		index[id] = i
+	}
+	cells := []DSMCell{}
+	backEdges := []AdjacencyEdge{}
+	for row, id := range order {
+		start := len(cells)
+		for dep, depType := range dependsOn[id] {
+			cells = append(cells, DSMCell{Row: row, Col: index[dep], Type: depType})
+		}
+		rowCells := cells[start:]
+		sort.Slice(rowCells, func(a, b int) bool { return rowCells[a].Col < rowCells[b].Col })
+		for _, c := range rowCells {
+			if c.Col > row {
+				backEdges = append(backEdges, AdjacencyEdge{From: id, To: order[c.Col], Type: c.Type})
+			}
+		}
+	}
+
+	return &DependencyMatrix{
+		Order:     order,
+		Ordering:  dsmOrdering,
+		Cells:     cells,
+		BackEdges: backEdges,
+	}
+}
+
+// dsmCandidate is a bead waiting to be placed in the DSM order.
+type dsmCandidate struct {
+	id        string
+	remaining int
+}
+
+// dsmQueue is a min-heap of candidates by unplaced dependencies, then ID.
+type dsmQueue []dsmCandidate
+
+func (q dsmQueue) Len() int { return len(q) }
+func (q dsmQueue) Less(i, j int) bool {
+	if q[i].remaining != q[j].remaining {
+		return q[i].remaining < q[j].remaining
+	}
+	return q[i].id < q[j].id
+}
+func (q dsmQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *dsmQueue) Push(x any)   { *q = append(*q, x.(dsmCandidate)) }
+func (q *dsmQueue) Pop() any {
+	old := *q
+	x := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return x
+}
+
+// dsmCSV renders the filled cells as CSV, one row per cell in matrix order,
+// so the output grows with the edges rather than the square of the beads.
+func dsmCSV(m *DependencyMatrix) string {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	_ = w.Write([]string{"row", "col", "bead", "depends_on", "type"})
+	for _, c := range m.Cells {
+		_ = w.Write([]string{fmt.Sprint(c.Row), fmt.Sprint(c.Col), m.Order[c.Row], m.Order[c.Col], c.Type})
+	}
+	w.Flush()
+	return b.String()
+}
+
 // GraphExportResultJSON returns the result as JSON bytes.
 func (r *GraphExportResult) JSON() ([]byte, error) {
 	return json.MarshalIndent(r, "", "  ")
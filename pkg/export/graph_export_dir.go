@@ -0,0 +1,134 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Files written by GenerateInteractiveGraphDir, relative to the output dir.
+const (
+	graphDirIndex      = "index.html"
+	graphDirApp        = "app.js"
+	graphDirStyles     = "styles.css"
+	graphDirData       = "data.json"
+	graphDirForceGraph = "vendor/force-graph.min.js"
+	graphDirMarked     = "vendor/marked.min.js"
+	graphDirPurify     = "vendor/purify.min.js"
+)
+
+// graphDirLoader replaces the inline app script in index.html: it fetches
+// data.json and then loads app.js, which reads the data from window.BV_DATA.
+const graphDirLoader = `    <script>
+fetch('` + graphDirData + `')
+    .then(r => { if (!r.ok) throw new Error(r.status + ' ' + r.statusText); return r.json(); })
+    .then(data => {
+        window.BV_DATA = data;
+        const app = document.createElement('script');
+        app.src = '` + graphDirApp + `';
+        document.body.appendChild(app);
+    })
+    .catch(err => {
+        const msg = document.createElement('div');
+        msg.style.cssText = 'position:fixed;top:0;left:0;right:0;z-index:10000;padding:12px 16px;background:#ef4444;color:#fff;font:14px sans-serif';
+        msg.textContent = 'Could not load ` + graphDirData + ` (' + err.message + '). Serve this directory over HTTP, e.g. python3 -m http.server';
+        document.body.appendChild(msg);
+    });
+    </script>
+`
+
+// GenerateInteractiveGraphDir writes the interactive graph as separate,
+// cacheable files for static hosting: index.html, app.js, styles.css,
+// data.json and the vendored force-graph/marked/DOMPurify libraries under vendor/.
+// All references are relative, so the directory can be served from any path.
+// opts.Path and opts.CompressData are ignored. Returns the index.html path.
+func GenerateInteractiveGraphDir(opts InteractiveGraphOptions, dir string) (string, error) {
+	if dir == "" {
+		return "", fmt.Errorf("no output directory given")
+	}
+	g, err := buildInteractiveGraph(opts)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := g.indentedData()
+	if err != nil {
+		return "", err
+	}
+
+	index, styles, app, err := splitViewerPage(g.render("window.BV_DATA", "", "", ""))
+	if err != nil {
+		return "", err
+	}
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{graphDirIndex, index},
+		{graphDirStyles, styles},
+		{graphDirApp, app},
+		{graphDirData, string(data)},
+		{graphDirForceGraph, forceGraphJS},
+		{graphDirMarked, markedJS},
+		{graphDirPurify, dompurifyJS},
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, filepath.FromSlash(f.name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return "", fmt.Errorf("create dir: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(f.content), 0644); err != nil {
+			return "", fmt.Errorf("write %s: %w", f.name, err)
+		}
+	}
+
+	return filepath.Join(dir, graphDirIndex), nil
+}
+
+// splitViewerPage cuts a page rendered with empty library slots into
+// index.html (with relative <link>/<script src> references), the stylesheet
+// and the app script.
+func splitViewerPage(page string) (index, styles, app string, err error) {
+	cut := func(s, start, end string) (before, inner, after string, ok bool) {
+		i := strings.Index(s, start)
+		if i < 0 {
+			return "", "", "", false
+		}
+		j := strings.Index(s[i+len(start):], end)
+		if j < 0 {
+			return "", "", "", false
+		}
+		return s[:i], s[i+len(start) : i+len(start)+j], s[i+len(start)+j+len(end):], true
+	}
+
+	before, css, rest, ok := cut(page, "    <style>\n", "    </style>\n")
+	if !ok {
+		return "", "", "", fmt.Errorf("split viewer page: stylesheet not found")
+	}
+	body, js, tail, ok := cut(rest, "    <script></script>\n    <script></script>\n    <script></script>\n    <script>\n", "    </script>\n")
+	if !ok {
+		return "", "", "", fmt.Errorf("split viewer page: app script not found")
+	}
+
+	index = before +
+		`    <link rel="stylesheet" href="` + graphDirStyles + `">` + "\n" +
+		body +
+		`    <script src="` + graphDirForceGraph + `"></script>` + "\n" +
+		`    <script src="` + graphDirMarked + `"></script>` + "\n" +
+		`    <script src="` + graphDirPurify + `"></script>` + "\n" +
+		graphDirLoader +
+		tail
+	return index, dedentViewerBlock(css), js, nil
+}
+
+// dedentViewerBlock strips the 8-space indentation the stylesheet has inside
+// the page template.
+func dedentViewerBlock(block string) string {
+	lines := strings.Split(block, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, "        ")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,72 @@
+package export
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateInteractiveGraphDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	indexPath, err := GenerateInteractiveGraphDir(InteractiveGraphOptions{
+		Issues: interactiveTestIssues(),
+		Title:  "Split",
+	}, dir)
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphDir: %v", err)
+	}
+	if indexPath != filepath.Join(dir, "index.html") {
+		t.Errorf("unexpected index path %q", indexPath)
+	}
+
+	read := func(name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("expected %s: %v", name, err)
+		}
+		return string(data)
+	}
+
+	index := read("index.html")
+	for _, want := range []string{
+		`<link rel="stylesheet" href="styles.css">`,
+		`<script src="vendor/force-graph.min.js"></script>`,
+		`<script src="vendor/marked.min.js"></script>`,
+		`<script src="vendor/purify.min.js"></script>`,
+		`fetch('data.json')`,
+		`app.src = 'app.js';`,
+		`<span>Split</span> Graph`,
+	} {
+		if !strings.Contains(index, want) {
+			t.Errorf("expected index.html to contain %q", want)
+		}
+	}
+	if strings.Contains(index, "<style>") || strings.Contains(index, "const DATA") {
+		t.Error("expected styles and app script to be moved out of index.html")
+	}
+
+	if app := read("app.js"); !strings.HasPrefix(app, "const DATA = window.BV_DATA;\n") || strings.Contains(app, "</script>") {
+		t.Errorf("unexpected app.js start: %.60q", app)
+	}
+	if styles := read("styles.css"); !strings.HasPrefix(styles, ":root {") || strings.Contains(styles, "</style>") {
+		t.Errorf("unexpected styles.css start: %.60q", styles)
+	}
+
+	var data struct {
+		Nodes []graphNode `json:"nodes"`
+		Links []graphLink `json:"links"`
+	}
+	if err := json.Unmarshal([]byte(read("data.json")), &data); err != nil {
+		t.Fatalf("data.json: %v", err)
+	}
+	if len(data.Nodes) != 2 || len(data.Links) != 1 {
+		t.Errorf("expected 2 nodes and 1 link, got %d and %d", len(data.Nodes), len(data.Links))
+	}
+
+	if read("vendor/force-graph.min.js") != forceGraphJS || read("vendor/marked.min.js") != markedJS || read("vendor/purify.min.js") != dompurifyJS {
+		t.Error("expected vendored libraries to be written verbatim")
+	}
+}
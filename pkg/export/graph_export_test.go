@@ -2,6 +2,8 @@ package export
 
 import (
 	"encoding/json"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -193,6 +195,31 @@ func TestExportGraph_LabelFilter(t *testing.T) {
 	}
 }
 
+func TestExportGraph_LabelGlobAndRegex(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "L-1", Title: "Infra", Status: model.StatusOpen, Labels: []string{"team/infra"}},
+		{ID: "L-2", Title: "Frontend", Status: model.StatusOpen, Labels: []string{"team/frontend"}},
+		{ID: "L-3", Title: "Ops", Status: model.StatusOpen, Labels: []string{"ops"}},
+	}
+
+	analyzer := analysis.NewAnalyzer(issues)
+	stats := analyzer.Analyze()
+
+	for label, want := range map[string]int{"team/*": 2, "/infra$/": 1, "ops": 1, "OPS": 0, "/(?i)OPS/": 1} {
+		result, err := ExportGraph(issues, &stats, GraphExportConfig{Format: GraphFormatJSON, Label: label})
+		if err != nil {
+			t.Fatalf("ExportGraph(%q) failed: %v", label, err)
+		}
+		if result.Nodes != want {
+			t.Errorf("label %q: expected %d nodes, got %d", label, want, result.Nodes)
+		}
+	}
+
+	if _, err := ExportGraph(issues, &stats, GraphExportConfig{Format: GraphFormatJSON, Label: "/(/"}); err == nil {
+		t.Error("expected an error for an invalid label regex")
+	}
+}
+
 func TestExportGraph_SubgraphRoot(t *testing.T) {
 	issues := []model.Issue{
 		{ID: "bv-1", Title: "Root Issue", Status: model.StatusOpen},
@@ -310,3 +337,272 @@ func TestExportGraph_DeterministicOutput(t *testing.T) {
 		t.Error("DOT output should be deterministic across calls")
 	}
 }
+
+func TestExportGraph_MermaidDeterministic(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "bv-3", Title: "Third", Status: model.StatusClosed},
+		{ID: "bv-1", Title: "First", Status: model.StatusOpen},
+		{ID: "bv-2", Title: "Second", Status: model.StatusInProgress,
+			Dependencies: []*model.Dependency{
+				{IssueID: "bv-2", DependsOnID: "bv-3", Type: model.DepRelated},
+				{IssueID: "bv-2", DependsOnID: "bv-1", Type: model.DepBlocks},
+			},
+		},
+	}
+	// Same graph, different input order
+	reordered := []model.Issue{issues[2], issues[0], issues[1]}
+	reordered[0].Dependencies = []*model.Dependency{issues[2].Dependencies[1], issues[2].Dependencies[0]}
+
+	config := GraphExportConfig{Format: GraphFormatMermaid}
+	first, _ := ExportGraph(issues, nil, config)
+	for run := 0; run < 5; run++ {
+		again, _ := ExportGraph(reordered, nil, config)
+		if again.Graph != first.Graph {
+			t.Fatalf("run %d: mermaid output differs:\n%s\nvs\n%s", run, first.Graph, again.Graph)
+		}
+	}
+
+	// Nodes by ID, then edges by (source, target)
+	order := []string{`bv-1["bv-1`, `bv-2["bv-2`, `bv-3["bv-3`, "bv-2 ==> bv-1", "bv-2 -.-> bv-3"}
+	last := -1
+	for _, want := range order {
+		idx := strings.Index(first.Graph, want)
+		if idx <= last {
+			t.Fatalf("expected %q after previous element in:\n%s", want, first.Graph)
+		}
+		last = idx
+	}
+	if !strings.Contains(first.Graph, "class bv-3 closed") {
+		t.Error("expected closed node to use the closed classDef")
+	}
+}
+
+func TestExportGraph_MermaidEscapesLabels(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "bv-1", Title: `Fix "quoted" [bracket] <b> #1`, Status: model.StatusOpen},
+		{ID: "bv-2", Title: "A rather long title that needs wrapping across several lines to stay readable", Status: model.StatusOpen},
+	}
+
+	result, err := ExportGraph(issues, nil, GraphExportConfig{Format: GraphFormatMermaid})
+	if err != nil {
+		t.Fatalf("ExportGraph failed: %v", err)
+	}
+
+	want := `bv-1["bv-1<br/>Fix #quot;quoted#quot; #91;bracket#93; #lt;b#gt; #35;1"]`
+	if !strings.Contains(result.Graph, want) {
+		t.Errorf("expected escaped label %q in:\n%s", want, result.Graph)
+	}
+	if strings.Contains(result.Graph, `"quoted"`) || strings.Contains(result.Graph, "[bracket]") {
+		t.Error("raw quotes or brackets leaked into mermaid label")
+	}
+
+	wrapped := `bv-2["bv-2<br/>A rather long title that needs<br/>wrapping across several lines<br/>to stay readable"]`
+	if !strings.Contains(result.Graph, wrapped) {
+		t.Errorf("expected wrapped label %q in:\n%s", wrapped, result.Graph)
+	}
+}
+
+func TestWrapMermaidTitle_Truncates(t *testing.T) {
+	got := wrapMermaidTitle(strings.Repeat("word ", 40))
+	if n := strings.Count(got, "<br/>"); n != mermaidTitleLines-1 {
+		t.Errorf("expected %d lines, got %d: %q", mermaidTitleLines, n+1, got)
+	}
+	if !strings.HasSuffix(got, "...") {
+		t.Errorf("expected truncation marker, got %q", got)
+	}
+}
+
+// dotClusterBody returns the text of the named cluster subgraph, or "" if absent.
+func dotClusterBody(graph, name string) string {
+	start := strings.Index(graph, "subgraph \""+name+"\" {")
+	if start < 0 {
+		return ""
+	}
+	end := strings.Index(graph[start:], "\n    }\n")
+	if end < 0 {
+		return ""
+	}
+	return graph[start : start+end]
+}
+
+func TestExportGraph_DOTClusterBy(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "bv-1", Title: "Epic", Status: model.StatusOpen, IssueType: model.TypeEpic},
+		{ID: "bv-2", Title: "Bug", Status: model.StatusInProgress, IssueType: model.TypeBug,
+			Dependencies: []*model.Dependency{{IssueID: "bv-2", DependsOnID: "bv-1", Type: model.DepBlocks}}},
+		{ID: "bv-3", Title: "Task", Status: model.StatusOpen, IssueType: model.TypeTask,
+			Dependencies: []*model.Dependency{{IssueID: "bv-3", DependsOnID: "bv-2", Type: model.DepBlocks}}},
+		{ID: "bv-4", Title: "Loner", Status: model.StatusClosed, IssueType: model.TypeBug},
+	}
+
+	tests := []struct {
+		clusterBy GraphClusterBy
+		members   map[string][]string // cluster name -> expected member IDs
+	}{
+		{GraphClusterType, map[string][]string{
+			"cluster_type_bug":  {"bv-2", "bv-4"},
+			"cluster_type_epic": {"bv-1"},
+			"cluster_type_task": {"bv-3"},
+		}},
+		{GraphClusterStatus, map[string][]string{
+			"cluster_status_open":        {"bv-1", "bv-3"},
+			"cluster_status_in_progress": {"bv-2"},
+			"cluster_status_closed":      {"bv-4"},
+		}},
+		{GraphClusterComponent, map[string][]string{
+			"cluster_component_1": {"bv-1", "bv-2", "bv-3"},
+			"cluster_component_2": {"bv-4"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.clusterBy), func(t *testing.T) {
+			result, err := ExportGraph(issues, nil, GraphExportConfig{Format: GraphFormatDOT, ClusterBy: tt.clusterBy})
+			if err != nil {
+				t.Fatalf("ExportGraph failed: %v", err)
+			}
+			if got := strings.Count(result.Graph, "subgraph \"cluster_"); got != len(tt.members) {
+				t.Errorf("expected %d clusters, got %d:\n%s", len(tt.members), got, result.Graph)
+			}
+			for name, ids := range tt.members {
+				body := dotClusterBody(result.Graph, name)
+				if body == "" {
+					t.Errorf("missing cluster %s:\n%s", name, result.Graph)
+					continue
+				}
+				if !strings.Contains(body, "label=") || !strings.Contains(body, "fillcolor=") {
+					t.Errorf("cluster %s missing label or background", name)
+				}
+				if got := strings.Count(body, "[label="); got != len(ids) {
+					t.Errorf("cluster %s: expected %d members, got %d", name, len(ids), got)
+				}
+				for _, id := range ids {
+					if !strings.Contains(body, "\""+id+"\" [label=") {
+						t.Errorf("cluster %s should contain %s", name, id)
+					}
+				}
+			}
+			// Edges stay at top level, including those crossing clusters
+			if !strings.Contains(result.Graph, "    \"bv-2\" -> \"bv-1\"") || !strings.Contains(result.Graph, "    \"bv-3\" -> \"bv-2\"") {
+				t.Errorf("expected edges to be preserved:\n%s", result.Graph)
+			}
+		})
+	}
+}
+
+func TestExportGraph_DOTShapesAndInvalidCluster(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "bv-1", Title: "Epic", Status: model.StatusOpen, IssueType: model.TypeEpic},
+		{ID: "bv-2", Title: "Bug", Status: model.StatusBlocked, IssueType: model.TypeBug},
+	}
+	result, err := ExportGraph(issues, nil, GraphExportConfig{Format: GraphFormatDOT})
+	if err != nil {
+		t.Fatalf("ExportGraph failed: %v", err)
+	}
+	if strings.Contains(result.Graph, "subgraph") {
+		t.Error("unclustered DOT output should be flat")
+	}
+	if !strings.Contains(result.Graph, "shape=diamond, fillcolor=\"#C8E6C9\"") {
+		t.Errorf("expected open epic drawn as a green diamond:\n%s", result.Graph)
+	}
+	if !strings.Contains(result.Graph, "shape=triangle, fillcolor=\"#FFCDD2\"") {
+		t.Errorf("expected blocked bug drawn as a red triangle:\n%s", result.Graph)
+	}
+
+	if _, err := ExportGraph(issues, nil, GraphExportConfig{Format: GraphFormatDOT, ClusterBy: "label"}); err == nil {
+		t.Error("expected error for unknown cluster mode")
+	}
+}
+
+func TestExportGraph_DSMChainIsLowerTriangular(t *testing.T) {
+	// a <- b <- c <- d, listed out of order and with IDs that sort against the chain
+	blockedBy := func(id, on string) []*model.Dependency {
+		return []*model.Dependency{{IssueID: id, DependsOnID: on, Type: model.DepBlocks}}
+	}
+	issues := []model.Issue{
+		{ID: "a-4", Title: "Last", Status: model.StatusOpen, Dependencies: blockedBy("a-4", "z-3")},
+		{ID: "m-2", Title: "Second", Status: model.StatusOpen, Dependencies: blockedBy("m-2", "z-1")},
+		{ID: "z-1", Title: "First", Status: model.StatusOpen},
+		{ID: "z-3", Title: "Third", Status: model.StatusOpen, Dependencies: blockedBy("z-3", "m-2")},
+	}
+
+	result, err := ExportGraph(issues, nil, GraphExportConfig{Format: GraphFormatDSM})
+	if err != nil {
+		t.Fatalf("ExportGraph failed: %v", err)
+	}
+	if result.Format != "dsm" || result.Matrix == nil {
+		t.Fatalf("expected dsm matrix, got format %q", result.Format)
+	}
+	m := result.Matrix
+	if got := strings.Join(m.Order, ","); got != "z-1,m-2,z-3,a-4" {
+		t.Errorf("order = %s, want z-1,m-2,z-3,a-4", got)
+	}
+	if m.Ordering == "" {
+		t.Error("expected the ordering to be described")
+	}
+	// Each bead depends on the one just before it, and nothing else is filled
+	wantCells := []DSMCell{{Row: 1, Col: 0, Type: "blocks"}, {Row: 2, Col: 1, Type: "blocks"}, {Row: 3, Col: 2, Type: "blocks"}}
+	if !reflect.DeepEqual(m.Cells, wantCells) {
+		t.Errorf("cells = %+v, want %+v", m.Cells, wantCells)
+	}
+	if len(m.BackEdges) != 0 {
+		t.Errorf("acyclic chain should have no back edges, got %+v", m.BackEdges)
+	}
+
+	wantCSV := "row,col,bead,depends_on,type\n1,0,m-2,z-1,blocks\n2,1,z-3,m-2,blocks\n3,2,a-4,z-3,blocks\n"
+	if result.Graph != wantCSV {
+		t.Errorf("CSV = %q, want %q", result.Graph, wantCSV)
+	}
+}
+
+func TestExportGraph_DSMCycleAboveDiagonal(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "bv-1", Title: "One", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{IssueID: "bv-1", DependsOnID: "bv-2", Type: model.DepBlocks},
+		}},
+		{ID: "bv-2", Title: "Two", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{IssueID: "bv-2", DependsOnID: "bv-1", Type: model.DepBlocks},
+		}},
+		{ID: "bv-3", Title: "Three", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{IssueID: "bv-3", DependsOnID: "bv-2", Type: model.DepRelated},
+		}},
+	}
+
+	result, err := ExportGraph(issues, nil, GraphExportConfig{Format: GraphFormatDSM})
+	if err != nil {
+		t.Fatalf("ExportGraph failed: %v", err)
+	}
+	m := result.Matrix
+	if got := strings.Join(m.Order, ","); got != "bv-1,bv-2,bv-3" {
+		t.Errorf("order = %s, want bv-1,bv-2,bv-3", got)
+	}
+	if len(m.BackEdges) != 1 || m.BackEdges[0] != (AdjacencyEdge{From: "bv-1", To: "bv-2", Type: "blocks"}) {
+		t.Errorf("back edges = %+v, want the single feedback edge bv-1 -> bv-2", m.BackEdges)
+	}
+	wantCells := []DSMCell{{Row: 0, Col: 1, Type: "blocks"}, {Row: 1, Col: 0, Type: "blocks"}, {Row: 2, Col: 1, Type: "related"}}
+	if !reflect.DeepEqual(m.Cells, wantCells) {
+		t.Errorf("cells = %+v, want %+v", m.Cells, wantCells)
+	}
+}
+
+func TestExportGraph_DSMIsSparse(t *testing.T) {
+	const n = 5000
+	issues := make([]model.Issue, n)
+	for i := range issues {
+		issues[i] = model.Issue{ID: fmt.Sprintf("bv-%05d", i), Title: "Bead", Status: model.StatusOpen}
+		if i > 0 {
+			issues[i].Dependencies = []*model.Dependency{{IssueID: issues[i].ID, DependsOnID: fmt.Sprintf("bv-%05d", i-1), Type: model.DepBlocks}}
+		}
+	}
+
+	result, err := ExportGraph(issues, nil, GraphExportConfig{Format: GraphFormatDSM})
+	if err != nil {
+		t.Fatalf("ExportGraph failed: %v", err)
+	}
+	if got := len(result.Matrix.Cells); got != n-1 {
+		t.Errorf("cells = %d, want one per dependency (%d)", got, n-1)
+	}
+	if got := strings.Count(result.Graph, "\n"); got != n {
+		t.Errorf("CSV has %d lines, want a header plus one per dependency (%d)", got, n)
+	}
+}
@@ -1,12 +1,17 @@
 package export
 
 import (
+	"bytes"
 	_ "embed"
 	"encoding/json"
 	"fmt"
+	"html"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -14,6 +19,8 @@ import (
 	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	"gopkg.in/yaml.v3"
 )
 
 //go:embed force-graph.min.js
@@ -22,6 +29,9 @@ var forceGraphJS string
 //go:embed marked.min.js
 var markedJS string
 
+//go:embed dompurify.min.js
+var dompurifyJS string
+
 // InteractiveGraphOptions configures HTML graph generation
 type InteractiveGraphOptions struct {
 	Issues      []model.Issue
@@ -32,6 +42,345 @@ type InteractiveGraphOptions struct {
 	DataHash    string
 	Path        string // Output path - if empty, auto-generates based on project
 	ProjectName string // Project name for auto-naming
+	NoAnimation bool   // Disable link particles and CSS animations by default
+	// NoSanitize renders raw HTML in markdown fields as written. By default
+	// DOMPurify strips scripts, event handlers and javascript: URLs, so an
+	// exported graph from an untrusted repo is safe to open.
+	NoSanitize bool
+	Theme      string // Default color scheme: light, dark (default) or auto
+	Palette    string // Color palette: default or cb-safe (colorblind-safe)
+
+	// CriticalColor (#rgb or #rrggbb) draws critical-path edges, their particles
+	// and the zero-slack node halo; empty keeps the built-in pink (#ec4899) edges
+	// and the node's own color for the halo. ArticulationColor likewise replaces
+	// the pink articulation-point glow.
+	CriticalColor     string
+	ArticulationColor string
+
+	// CommitURLTemplate links related commits to any forge, e.g.
+	// https://gitlab.example.com/g/p/-/commit/{sha}; {sha} and {short_sha} are
+	// substituted. IssueURLTemplate links bead IDs to an external tracker via
+	// {id}. Empty leaves SHAs and IDs as plain text.
+	CommitURLTemplate string
+	IssueURLTemplate  string
+	// AvatarURLTemplate draws each assignee's avatar, e.g.
+	// https://github.com/{assignee}.png, in place of the initials badge.
+	AvatarURLTemplate string
+
+	// TypeStyles registers shapes/colors for issue types, on top of the
+	// built-in feature/bug/task/epic styles (see LoadGraphTypeStyles)
+	TypeStyles map[string]GraphTypeStyle
+
+	// MaxNodes, when positive and exceeded, keeps only the MaxNodes most
+	// important beads by SampleMetric (pagerank, betweenness, critical,
+	// indegree or impact; empty means pagerank) and the links between them.
+	// Metrics are computed on the full graph first.
+	MaxNodes     int
+	SampleMetric string
+
+	// HealthWeights weighs the bead health score; zero uses
+	// analysis.DefaultHealthWeights
+	HealthWeights analysis.HealthWeights
+
+	// CompressData gzips the embedded DATA payload; the page inflates it on load
+	CompressData bool
+	// PayloadStats, if set, receives the embedded payload sizes
+	PayloadStats *GraphPayloadStats
+
+	// LiveEventsURL makes the page subscribe to a server-sent events stream
+	// and apply the GraphPatch events it carries (see LiveGraph). PatchSeq is
+	// the sequence number of the patch that produced this data, 0 if none.
+	LiveEventsURL string
+	PatchSeq      int64
+}
+
+// Interactive graph themes
+const (
+	GraphThemeDark  = "dark"
+	GraphThemeLight = "light"
+	GraphThemeAuto  = "auto"
+)
+
+// MaxClusteredIssues caps the graphs that get community clusters for edge
+// bundling: Louvain is the slowest step of an export on large graphs, which
+// are too dense to read bundled anyway. Larger exports carry no clusters.
+const MaxClusteredIssues = 2000
+
+// Interactive graph color palettes
+const (
+	GraphPaletteDefault = "default"
+	GraphPaletteCBSafe  = "cb-safe" // Deuteranopia-friendly blue/orange statuses and viridis heatmap
+)
+
+// GraphTypeStyle sets how the interactive viewer draws one issue type.
+type GraphTypeStyle struct {
+	Shape string `json:"shape" yaml:"shape"` // circle, square, triangle, diamond, hexagon, or star
+	Color string `json:"color" yaml:"color"` // Hex color, e.g. "#14b8a6"
+}
+
+// graphTypeShapeGlyphs maps supported shapes to their legend glyphs.
+var graphTypeShapeGlyphs = map[string]string{
+	"circle":   "●",
+	"square":   "■",
+	"triangle": "▲",
+	"diamond":  "◆",
+	"hexagon":  "⬢",
+	"star":     "★",
+}
+
+var graphTypeColorRegex = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// normalizeGraphColor validates a #rgb or #rrggbb color and expands it to
+// lowercase #rrggbb, so the viewer can append an alpha byte. Empty stays empty.
+func normalizeGraphColor(color string) (string, error) {
+	color = strings.TrimSpace(color)
+	if color == "" {
+		return "", nil
+	}
+	if !graphTypeColorRegex.MatchString(color) {
+		return "", fmt.Errorf("%q (use #rgb or #rrggbb)", color)
+	}
+	color = strings.ToLower(color)
+	if len(color) == 4 {
+		color = "#" + strings.Repeat(color[1:2], 2) + strings.Repeat(color[2:3], 2) + strings.Repeat(color[3:4], 2)
+	}
+	return color, nil
+}
+
+// Placeholders accepted in the commit, issue and avatar URL templates.
+var (
+	commitURLPlaceholders = []string{"{sha}", "{short_sha}"}
+	issueURLPlaceholders  = []string{"{id}"}
+	avatarURLPlaceholders = []string{"{assignee}"}
+)
+
+var urlTemplatePlaceholderRegex = regexp.MustCompile(`\{[^{}]*\}`)
+
+// validateURLTemplate checks that tmpl is an absolute http(s) URL that uses
+// at least one of the allowed placeholders and no others. Empty is allowed.
+func validateURLTemplate(tmpl string, placeholders []string) (string, error) {
+	tmpl = strings.TrimSpace(tmpl)
+	if tmpl == "" {
+		return "", nil
+	}
+	used := false
+	for _, ph := range urlTemplatePlaceholderRegex.FindAllString(tmpl, -1) {
+		if !slices.Contains(placeholders, ph) {
+			return "", fmt.Errorf("%q: unknown placeholder %s (use %s)", tmpl, ph, strings.Join(placeholders, ", "))
+		}
+		used = true
+	}
+	if !used {
+		return "", fmt.Errorf("%q: missing placeholder (use %s)", tmpl, strings.Join(placeholders, ", "))
+	}
+	u, err := url.Parse(urlTemplatePlaceholderRegex.ReplaceAllString(tmpl, "x"))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("%q: must be an absolute http or https URL", tmpl)
+	}
+	return tmpl, nil
+}
+
+// builtinGraphStatuses is the display order of the statuses the viewer colors;
+// their legend colors follow the palette CSS variables.
+var builtinGraphStatuses = []string{"open", "in_progress", "blocked", "closed"}
+
+var graphStatusLegendColors = map[string]string{
+	"open":        "var(--green)",
+	"in_progress": "var(--orange)",
+	"blocked":     "var(--red)",
+	"closed":      "#555577",
+}
+
+// graphStatusFallbackColor colors any other status (matches STATUS_FALLBACK_COLOR in the viewer).
+const graphStatusFallbackColor = "#94a3b8"
+
+// builtinGraphTypes is the display order of the built-in types.
+var builtinGraphTypes = []string{"feature", "bug", "task", "epic"}
+
+// fallbackGraphTypeStyle is the neutral style for types without a registration.
+var fallbackGraphTypeStyle = GraphTypeStyle{Shape: "hexagon", Color: "#8888aa"}
+
+// builtinGraphEdgeTypes is the display order of the dependency edge types the
+// viewer styles (see graphEdgeType).
+var builtinGraphEdgeTypes = []string{"blocks", "parent", "related", "discovered-from"}
+
+// graphEdgeStyle sets how the viewer draws one dependency edge type.
+type graphEdgeStyle struct {
+	Color    string  `json:"color,omitempty"` // Empty uses the theme's link color
+	Dash     []int   `json:"dash,omitempty"`  // Canvas line-dash pattern; empty is solid
+	Arrow    int     `json:"arrow"`           // Arrowhead length; 0 draws none
+	ArrowPos float64 `json:"arrow_pos"`       // 1 at the target, 0.5 mid-edge
+	Glyph    string  `json:"-"`               // Legend sample
+}
+
+// graphEdgeStyles: blocks are solid with an arrow at the blocker, parent links
+// carry a larger mid-edge arrowhead, related links are dashed and undirected.
+var graphEdgeStyles = map[string]graphEdgeStyle{
+	"blocks":          {Arrow: 5, ArrowPos: 1, Glyph: "──▶"},
+	"parent":          {Color: "#bd93f9", Arrow: 9, ArrowPos: 0.5, Glyph: "─▶─"},
+	"related":         {Color: "#8be9fd", Dash: []int{4, 3}, Glyph: "┄┄┄"},
+	"discovered-from": {Color: "#50fa7b", Dash: []int{1, 3}, Arrow: 4, ArrowPos: 1, Glyph: "┈┈▷"},
+}
+
+// fallbackGraphEdgeStyle draws any other dependency type (matches
+// FALLBACK_EDGE_STYLE in the viewer).
+var fallbackGraphEdgeStyle = graphEdgeStyle{Color: "#8888aa", Dash: []int{6, 3}, Arrow: 4, ArrowPos: 1, Glyph: "╌╌▶"}
+
+// graphEdgeType is the viewer's edge_type for a dependency: untyped legacy
+// dependencies block, and parent-child is shortened to parent.
+func graphEdgeType(t model.DependencyType) string {
+	switch t {
+	case "", model.DepBlocks:
+		return "blocks"
+	case model.DepParentChild:
+		return "parent"
+	}
+	return string(t)
+}
+
+// DefaultGraphTypeStyles returns the built-in type styles.
+func DefaultGraphTypeStyles() map[string]GraphTypeStyle {
+	return map[string]GraphTypeStyle{
+		"feature": {Shape: "circle", Color: "#a855f7"},
+		"bug":     {Shape: "triangle", Color: "#ef4444"},
+		"task":    {Shape: "square", Color: "#22d3ee"},
+		"epic":    {Shape: "diamond", Color: "#fbbf24"},
+	}
+}
+
+// LoadGraphTypeStyles reads a YAML (or JSON) map of type name to style, e.g.
+//
+//	question: {shape: star, color: "#14b8a6"}
+//	docs: {shape: hexagon, color: "#64748b"}
+func LoadGraphTypeStyles(path string) (map[string]GraphTypeStyle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading type config: %w", err)
+	}
+	var styles map[string]GraphTypeStyle
+	if err := yaml.Unmarshal(data, &styles); err != nil {
+		return nil, fmt.Errorf("parsing type config: %w", err)
+	}
+	if err := validateGraphTypeStyles(styles); err != nil {
+		return nil, err
+	}
+	return styles, nil
+}
+
+func validateGraphTypeStyles(styles map[string]GraphTypeStyle) error {
+	for name, style := range styles {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("invalid type config: empty type name")
+		}
+		if _, ok := graphTypeShapeGlyphs[style.Shape]; !ok {
+			return fmt.Errorf("invalid type config: type %q has unknown shape %q (use circle, square, triangle, diamond, hexagon, or star)", name, style.Shape)
+		}
+		if !graphTypeColorRegex.MatchString(style.Color) {
+			return fmt.Errorf("invalid type config: type %q has invalid color %q (use #rgb or #rrggbb)", name, style.Color)
+		}
+	}
+	return nil
+}
+
+// viewerTypeStyle is a GraphTypeStyle as embedded in the viewer; Custom marks
+// user registrations, whose color replaces the built-in badge styling.
+type viewerTypeStyle struct {
+	GraphTypeStyle
+	Custom bool `json:"custom,omitempty"`
+}
+
+// graphValueOrder lists the built-in values followed by the other values
+// present in the data, alphabetically.
+func graphValueOrder(builtin []string, present map[string]bool) []string {
+	order := append([]string(nil), builtin...)
+	var extra []string
+	for t := range present {
+		if t != "" && !slices.Contains(order, t) {
+			extra = append(extra, t)
+		}
+	}
+	sort.Strings(extra)
+	return append(order, extra...)
+}
+
+// graphOptionLabel turns a status/type value into a display label
+// ("in_review" -> "In Review").
+func graphOptionLabel(value string) string {
+	words := strings.FieldsFunc(value, func(r rune) bool { return r == '_' || r == '-' || r == ' ' })
+	for i, w := range words {
+		words[i] = capitalize(w)
+	}
+	return strings.Join(words, " ")
+}
+
+// renderFilterOptions renders filter <option>s for the values present in the data.
+func renderFilterOptions(order []string, present map[string]bool) string {
+	var sb strings.Builder
+	for _, t := range order {
+		if present[t] {
+			fmt.Fprintf(&sb, "\n                    <option value=\"%s\">%s</option>", html.EscapeString(t), html.EscapeString(graphOptionLabel(t)))
+		}
+	}
+	return sb.String()
+}
+
+// renderStatusLegend renders the status legend: built-in statuses plus every
+// other status present in the data, which gets the fallback color.
+func renderStatusLegend(order []string, present map[string]bool) string {
+	var sb strings.Builder
+	for _, st := range order {
+		color, ok := graphStatusLegendColors[st]
+		if !ok {
+			if !present[st] {
+				continue
+			}
+			color = graphStatusFallbackColor
+		}
+		fmt.Fprintf(&sb, "\n                    <div class=\"legend-item\"><div class=\"legend-dot\" style=\"background:%s;color:%s\"></div>%s</div>",
+			color, color, html.EscapeString(graphOptionLabel(st)))
+	}
+	return sb.String()
+}
+
+// renderTypeLegend renders the "Type Shapes" legend: built-in types plus every
+// other type present in the data.
+func renderTypeLegend(order []string, present map[string]bool, styles map[string]GraphTypeStyle) string {
+	var sb strings.Builder
+	for _, t := range order {
+		style, ok := styles[t]
+		if !ok {
+			if !present[t] {
+				continue
+			}
+			style = fallbackGraphTypeStyle
+		}
+		fmt.Fprintf(&sb, "\n                    <div class=\"legend-item\"><span style=\"font-size:1rem;color:%s\">%s</span> %s</div>",
+			style.Color, graphTypeShapeGlyphs[style.Shape], html.EscapeString(graphOptionLabel(t)))
+	}
+	return sb.String()
+}
+
+// renderEdgeLegend renders the "Edge Types" legend: a line sample per built-in
+// edge type plus every other type present in the data. Items carry their type
+// so clicking one filters the graph to it.
+func renderEdgeLegend(order []string, present map[string]bool) string {
+	var sb strings.Builder
+	for _, t := range order {
+		style, ok := graphEdgeStyles[t]
+		if !ok {
+			if !present[t] {
+				continue
+			}
+			style = fallbackGraphEdgeStyle
+		}
+		color := style.Color
+		if color == "" {
+			color = "var(--fg-muted)"
+		}
+		fmt.Fprintf(&sb, "\n                    <div class=\"legend-item edge-legend-item\" data-edge-type=\"%s\" title=\"Show only %s edges\"><span class=\"edge-sample\" style=\"color:%s\">%s</span> %s</div>",
+			html.EscapeString(t), html.EscapeString(t), color, style.Glyph, html.EscapeString(graphOptionLabel(t)))
+	}
+	return sb.String()
 }
 
 // graphNode represents a node in the interactive graph with full bead data
@@ -46,6 +395,9 @@ type graphNode struct {
 	AcceptanceCriteria string `json:"acceptance_criteria,omitempty"`
 	Notes              string `json:"notes,omitempty"`
 
+	// Checklist progress parsed from acceptance criteria (nil when no checklist)
+	Acceptance *analysis.AcceptanceProgress `json:"acceptance,omitempty"`
+
 	// Metadata
 	Status   string   `json:"status"`
 	Priority int      `json:"priority"`
@@ -60,8 +412,10 @@ type graphNode struct {
 	DueDate   string `json:"due_date,omitempty"`
 
 	// Dependencies
-	BlockedBy []string `json:"blocked_by,omitempty"`
-	Blocks    []string `json:"blocks,omitempty"`
+	BlockedBy  []string        `json:"blocked_by,omitempty"`
+	Blocks     []string        `json:"blocks,omitempty"`
+	Parent     string          `json:"parent,omitempty"`      // Parent-child parent, e.g. the epic; lets the viewer collapse subtrees
+	WhyBlocked []blockerReason `json:"why_blocked,omitempty"` // Open blockers and what they wait on; nil for closed beads
 
 	// Git history correlation
 	CommitCount int                            `json:"commit_count,omitempty"`
@@ -82,6 +436,78 @@ type graphNode struct {
 	IsArticulation  bool    `json:"is_articulation"`
 	PageRankRank    int     `json:"pagerank_rank"`
 	BetweennessRank int     `json:"betweenness_rank"`
+	TopoLevel       int     `json:"topo_level"`         // Dependency depth: 0 = no blockers
+	InCycle         bool    `json:"in_cycle,omitempty"` // Level is shared by the whole cycle
+	// Community from analysis.ComputeCommunities; the viewer bundles edges
+	// between them. Nil above MaxClusteredIssues.
+	Cluster *int `json:"cluster,omitempty"`
+	// Blocked beads that become actionable, directly or in cascade, once this closes
+	UnblockImpact int `json:"unblock_impact"`
+	// Composite 0-100 health (see analysis.ComputeBeadHealth); nil for closed beads
+	Health *int `json:"health,omitempty"`
+}
+
+// blockerReason is an open blocker of a bead and what it is waiting on.
+type blockerReason struct {
+	ID     string   `json:"id"`
+	Status string   `json:"status"`
+	State  string   `json:"state"` // actionable, blocked, or cycle
+	Roots  []string `json:"roots"` // For blocked: the nearest actionable beads up its chain
+	Hops   int      `json:"hops"`  // For blocked: how many rings up the roots are
+}
+
+// explainBlockers returns, for each blocker ID it is asked about, whether the
+// blocker can be worked on now and, if not, the nearest actionable beads up
+// its chain, found by walking open prerequisites ring by ring. A chain with no
+// actionable bead upstream is stuck on a cycle. It runs over the full graph
+// before any MaxNodes sampling, so blockers that were sampled out still
+// count, and memoizes per blocker since many beads share them.
+func explainBlockers(blockedBy map[string][]string, status map[string]model.Status) func(id string) blockerReason {
+	openBlockers := func(id string) []string {
+		var open []string
+		for _, b := range blockedBy[id] {
+			if !status[b].IsClosed() {
+				open = append(open, b)
+			}
+		}
+		return open
+	}
+	memo := make(map[string]blockerReason)
+	return func(id string) blockerReason {
+		if r, ok := memo[id]; ok {
+			return r
+		}
+		r := blockerReason{ID: id, Status: string(status[id]), State: "cycle", Roots: []string{}}
+		if len(openBlockers(id)) == 0 {
+			r.State = "actionable"
+		} else {
+			seen := map[string]bool{id: true}
+			ring := []string{id}
+			for hops := 1; len(ring) > 0 && r.State == "cycle"; hops++ {
+				var next []string
+				for _, n := range ring {
+					for _, b := range openBlockers(n) {
+						if !seen[b] {
+							seen[b] = true
+							next = append(next, b)
+						}
+					}
+				}
+				for _, b := range next {
+					if len(openBlockers(b)) == 0 {
+						r.Roots = append(r.Roots, b)
+					}
+				}
+				if len(r.Roots) > 0 {
+					sort.Strings(r.Roots)
+					r.State, r.Hops = "blocked", hops
+				}
+				ring = next
+			}
+		}
+		memo[id] = r
+		return r
+	}
 }
 
 // graphLink represents an edge in the interactive graph
@@ -89,9 +515,115 @@ type graphLink struct {
 	Source   string `json:"source"`
 	Target   string `json:"target"`
 	Type     string `json:"type"`
+	EdgeType string `json:"edge_type"` // blocks, parent, related, discovered-from, or a custom type
 	Critical bool   `json:"critical"`
 }
 
+// graphSummary is the "summary" block of the viewer data, also the headline
+// numbers for --export-json consumers.
+type graphSummary struct {
+	Nodes         int            `json:"nodes"`
+	Edges         int            `json:"edges"`
+	CriticalEdges int            `json:"critical_edges"`
+	ByStatus      map[string]int `json:"by_status"`
+	ByType        map[string]int `json:"by_type"`
+	InCycle       int            `json:"in_cycle"`       // Beads on a dependency cycle
+	MaxTopoLevel  int            `json:"max_topo_level"` // Longest blocker chain below the roots
+	Omitted       *graphOmission `json:"omitted,omitempty"`
+}
+
+// graphOmission records what a MaxNodes export left out.
+type graphOmission struct {
+	Nodes      int    `json:"nodes"`
+	Edges      int    `json:"edges"`
+	TotalNodes int    `json:"total_nodes"`
+	Metric     string `json:"metric"` // Importance metric the kept beads were chosen by
+}
+
+// graphSampleMetrics maps a SampleMetric to the node value it ranks by; the
+// names match the viewer's size-by options.
+var graphSampleMetrics = map[string]func(n graphNode) float64{
+	"pagerank":    func(n graphNode) float64 { return n.PageRank },
+	"betweenness": func(n graphNode) float64 { return n.Betweenness },
+	"critical":    func(n graphNode) float64 { return n.CriticalPath },
+	"indegree":    func(n graphNode) float64 { return float64(n.InDegree) },
+	"impact":      func(n graphNode) float64 { return float64(n.UnblockImpact) },
+}
+
+// parseGraphSampleMetric validates a SampleMetric (case-insensitive), with
+// "" meaning pagerank.
+func parseGraphSampleMetric(s string) (string, error) {
+	metric := strings.ToLower(strings.TrimSpace(s))
+	if metric == "" {
+		return "pagerank", nil
+	}
+	if _, ok := graphSampleMetrics[metric]; !ok {
+		return "", fmt.Errorf("invalid sample metric %q (use pagerank, betweenness, critical, indegree, or impact)", s)
+	}
+	return metric, nil
+}
+
+// sampleGraph keeps the maxNodes nodes ranking highest by metric (ties by
+// ID) and the links whose ends are both kept. Node order is preserved.
+func sampleGraph(nodes []graphNode, links []graphLink, maxNodes int, metric string) ([]graphNode, []graphLink, *graphOmission) {
+	value := graphSampleMetrics[metric]
+	ranked := make([]graphNode, len(nodes))
+	copy(ranked, nodes)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		vi, vj := value(ranked[i]), value(ranked[j])
+		if vi != vj {
+			return vi > vj
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
+	keep := make(map[string]bool, maxNodes)
+	for _, n := range ranked[:maxNodes] {
+		keep[n.ID] = true
+	}
+
+	keptNodes := make([]graphNode, 0, maxNodes)
+	for _, n := range nodes {
+		if keep[n.ID] {
+			keptNodes = append(keptNodes, n)
+		}
+	}
+	keptLinks := make([]graphLink, 0, len(links))
+	for _, l := range links {
+		if keep[l.Source] && keep[l.Target] {
+			keptLinks = append(keptLinks, l)
+		}
+	}
+	return keptNodes, keptLinks, &graphOmission{
+		Nodes:      len(nodes) - len(keptNodes),
+		Edges:      len(links) - len(keptLinks),
+		TotalNodes: len(nodes),
+		Metric:     metric,
+	}
+}
+
+func summarizeGraph(nodes []graphNode, links []graphLink) graphSummary {
+	summary := graphSummary{
+		Nodes:    len(nodes),
+		Edges:    len(links),
+		ByStatus: make(map[string]int),
+		ByType:   make(map[string]int),
+	}
+	for _, n := range nodes {
+		summary.ByStatus[n.Status]++
+		summary.ByType[n.Type]++
+		if n.InCycle {
+			summary.InCycle++
+		}
+		summary.MaxTopoLevel = max(summary.MaxTopoLevel, n.TopoLevel)
+	}
+	for _, l := range links {
+		if l.Critical {
+			summary.CriticalEdges++
+		}
+	}
+	return summary
+}
+
 // GenerateInteractiveGraphFilename creates an auto-generated filename
 // Format: {project}_graph_export__as_of__YYYY_MM_DD__HH_MM__git_head_hash__{gitshort}.html
 func GenerateInteractiveGraphFilename(projectName string) string {
@@ -115,8 +647,128 @@ func GenerateInteractiveGraphFilename(projectName string) string {
 
 // GenerateInteractiveGraphHTML creates a self-contained HTML file with force-graph visualization
 func GenerateInteractiveGraphHTML(opts InteractiveGraphOptions) (string, error) {
+	g, err := buildInteractiveGraph(opts)
+	if err != nil {
+		return "", err
+	}
+
+	// Generate filename if not provided
+	outputPath := opts.Path
+	if outputPath == "" {
+		projectName := opts.ProjectName
+		if projectName == "" {
+			projectName = "graph"
+		}
+		outputPath = GenerateInteractiveGraphFilename(projectName)
+	}
+
+	// Ensure .html extension
+	if !strings.HasSuffix(strings.ToLower(outputPath), ".html") {
+		outputPath = strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".html"
+	}
+
+	payload, payloadStats, err := embedGraphPayload(g.dataJSON, opts.CompressData)
+	if err != nil {
+		return "", err
+	}
+	if opts.PayloadStats != nil {
+		*opts.PayloadStats = payloadStats
+	}
+
+	page := g.render(payload, forceGraphJS, markedJS, dompurifyJS)
+
+	// Ensure directory exists
+	dir := filepath.Dir(outputPath)
+	if dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return "", fmt.Errorf("create dir: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(outputPath, []byte(page), 0644); err != nil {
+		return "", err
+	}
+
+	return outputPath, nil
+}
+
+// GenerateInteractiveGraphJSON writes the viewer's DATA payload (nodes with
+// metrics, links, triage, summary) as standalone indented JSON to opts.Path,
+// so external dashboards consume exactly what the HTML viewer renders.
+func GenerateInteractiveGraphJSON(opts InteractiveGraphOptions) error {
+	if opts.Path == "" {
+		return fmt.Errorf("no output path given")
+	}
+	g, err := buildInteractiveGraph(opts)
+	if err != nil {
+		return err
+	}
+
+	data, err := g.indentedData()
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(opts.Path); dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("create dir: %w", err)
+		}
+	}
+	return os.WriteFile(opts.Path, data, 0644)
+}
+
+// interactiveGraph holds everything generateUltimateHTML needs except the
+// DATA expression and the vendored libraries, which differ between the
+// single-file and --export-dir layouts.
+type interactiveGraph struct {
+	title, dataHash, projectName string
+	dataJSON                     []byte
+	nodes                        []graphNode // Kept so live servers can diff loads
+	links                        []graphLink
+	summary                      graphSummary
+	triage                       *analysis.TriageResult
+	nodeCount, edgeCount         int
+	animations                   bool
+	sanitize                     bool // Run rendered markdown through DOMPurify
+	theme, palette               string
+	criticalColor                string // Normalized #rrggbb, or "" for the default
+	articulationColor            string
+	linkTemplatesJSON            string // {"commit": ..., "issue": ..., "avatar": ...}; empty strings disable them
+	statusOptions, typeOptions   string
+	edgeTypeOptions              string
+	statusLegend, typeLegend     string
+	edgeLegend                   string
+	typeStylesJSON               string
+	edgeStylesJSON               string
+}
+
+func (g *interactiveGraph) render(dataExpr, forceGraphLib, markedLib, purifyLib string) string {
+	return generateUltimateHTML(html.EscapeString(g.title), g.dataHash, dataExpr, g.nodeCount, g.edgeCount, html.EscapeString(g.projectName), forceGraphLib, markedLib, purifyLib, g.animations, g.sanitize, g.theme, g.palette,
+		g.statusOptions, g.typeOptions, g.edgeTypeOptions, g.statusLegend, g.typeLegend, g.edgeLegend, g.typeStylesJSON, g.edgeStylesJSON,
+		g.criticalColor, g.articulationColor, g.linkTemplatesJSON)
+}
+
+// indentedData returns the graph data as readable, diff-friendly JSON.
+func (g *interactiveGraph) indentedData() ([]byte, error) {
+	var data bytes.Buffer
+	if err := json.Indent(&data, g.dataJSON, "", "  "); err != nil {
+		return nil, fmt.Errorf("format graph data: %w", err)
+	}
+	data.WriteByte('\n')
+	return data.Bytes(), nil
+}
+
+// buildInteractiveGraph computes the graph data and validated viewer settings.
+func buildInteractiveGraph(opts InteractiveGraphOptions) (*interactiveGraph, error) {
 	if len(opts.Issues) == 0 {
-		return "", fmt.Errorf("no issues to export")
+		return nil, fmt.Errorf("no issues to export")
+	}
+	if opts.MaxNodes < 0 {
+		return nil, fmt.Errorf("invalid max nodes %d (use 0 for no limit)", opts.MaxNodes)
+	}
+	sampleMetric, err := parseGraphSampleMetric(opts.SampleMetric)
+	if err != nil {
+		return nil, err
 	}
 
 	// Build graph data with all metrics
@@ -168,13 +820,47 @@ func GenerateInteractiveGraphHTML(opts InteractiveGraphOptions) (string, error)
 		}
 	}
 
+	// Blockers of every bead on the full graph, for why_blocked
+	blockedByMap := make(map[string][]string)
+	statusMap := make(map[string]model.Status, len(opts.Issues))
+	for _, iss := range opts.Issues {
+		statusMap[iss.ID] = iss.Status
+		for _, dep := range iss.Dependencies {
+			if dep != nil && issueMap[dep.DependsOnID] && dep.Type.IsBlocking() {
+				blockedByMap[iss.ID] = append(blockedByMap[iss.ID], dep.DependsOnID)
+			}
+		}
+	}
+	explainBlocker := explainBlockers(blockedByMap, statusMap)
+
+	// Topological levels (0 = no blockers), matching Analyzer.TopologicalLayers
+	graphAnalyzer := analysis.NewAnalyzer(opts.Issues)
+	topoLevels, inCycle := graphAnalyzer.TopologicalLayers()
+	unblockImpact := graphAnalyzer.UnblockImpact()
+	var clusters map[string]int
+	if len(opts.Issues) <= MaxClusteredIssues {
+		clusters = analysis.ComputeCommunities(opts.Issues)
+	}
+	health := make(map[string]int)
+	if opts.Stats != nil {
+		for _, h := range graphAnalyzer.ComputeBeadHealth(opts.Stats, opts.HealthWeights, time.Now()) {
+			health[h.ID] = h.Score
+		}
+	}
+
 	// Build nodes with full bead data
 	for _, iss := range opts.Issues {
-		// Compute blocked_by list
+		// Compute blocked_by list and the parent bead
 		var blockedBy []string
+		parent := ""
 		for _, dep := range iss.Dependencies {
-			if dep != nil && issueMap[dep.DependsOnID] && dep.Type.IsBlocking() {
+			if dep == nil || !issueMap[dep.DependsOnID] {
+				continue
+			}
+			if dep.Type.IsBlocking() {
 				blockedBy = append(blockedBy, dep.DependsOnID)
+			} else if dep.Type == model.DepParentChild && parent == "" {
+				parent = dep.DependsOnID
 			}
 		}
 
@@ -196,6 +882,11 @@ func GenerateInteractiveGraphHTML(opts InteractiveGraphOptions) (string, error)
 			dueDate = iss.DueDate.Format("2006-01-02")
 		}
 
+		var acceptance *analysis.AcceptanceProgress
+		if p, ok := analysis.ParseAcceptanceProgress(iss.AcceptanceCriteria); ok {
+			acceptance = &p
+		}
+
 		// Get history data if available
 		var commits []correlation.CorrelatedCommit
 		var lastAuthor string
@@ -218,6 +909,7 @@ func GenerateInteractiveGraphHTML(opts InteractiveGraphOptions) (string, error)
 			Design:             iss.Design,
 			AcceptanceCriteria: iss.AcceptanceCriteria,
 			Notes:              iss.Notes,
+			Acceptance:         acceptance,
 
 			// Metadata
 			Status:   string(iss.Status),
@@ -235,6 +927,7 @@ func GenerateInteractiveGraphHTML(opts InteractiveGraphOptions) (string, error)
 			// Dependencies
 			BlockedBy: blockedBy,
 			Blocks:    blocksMap[iss.ID],
+			Parent:    parent,
 
 			// Git history
 			CommitCount: commitCount,
@@ -255,6 +948,22 @@ func GenerateInteractiveGraphHTML(opts InteractiveGraphOptions) (string, error)
 			IsArticulation:  articulationSet[iss.ID],
 			PageRankRank:    pageRankRank[iss.ID],
 			BetweennessRank: betweennessRank[iss.ID],
+			TopoLevel:       topoLevels[iss.ID],
+			InCycle:         inCycle[iss.ID],
+			UnblockImpact:   unblockImpact[iss.ID],
+		}
+		if c, ok := clusters[iss.ID]; ok {
+			node.Cluster = &c
+		}
+		if !iss.Status.IsClosed() {
+			for _, b := range blockedBy {
+				if !statusMap[b].IsClosed() {
+					node.WhyBlocked = append(node.WhyBlocked, explainBlocker(b))
+				}
+			}
+		}
+		if score, ok := health[iss.ID]; ok {
+			node.Health = &score
 		}
 		nodes = append(nodes, node)
 
@@ -269,6 +978,7 @@ func GenerateInteractiveGraphHTML(opts InteractiveGraphOptions) (string, error)
 				Source:   iss.ID,
 				Target:   dep.DependsOnID,
 				Type:     string(dep.Type),
+				EdgeType: graphEdgeType(dep.Type),
 				Critical: isCritical,
 			}
 			links = append(links, link)
@@ -280,15 +990,29 @@ func GenerateInteractiveGraphHTML(opts InteractiveGraphOptions) (string, error)
 		return nodes[i].ID < nodes[j].ID
 	})
 
+	var omitted *graphOmission
+	if opts.MaxNodes > 0 && len(nodes) > opts.MaxNodes {
+		nodes, links, omitted = sampleGraph(nodes, links, opts.MaxNodes, sampleMetric)
+	}
+	summary := summarizeGraph(nodes, links)
+	summary.Omitted = omitted
+
 	graphData := map[string]interface{}{
-		"nodes": nodes,
-		"links": links,
+		"nodes":   nodes,
+		"links":   links,
+		"summary": summary,
 	}
 
 	// Add triage data if available
 	if opts.Triage != nil {
 		graphData["triage"] = opts.Triage
 	}
+	if opts.DataHash != "" {
+		graphData["data_hash"] = opts.DataHash
+	}
+	if opts.LiveEventsURL != "" {
+		graphData["live"] = map[string]interface{}{"events": opts.LiveEventsURL, "seq": opts.PatchSeq}
+	}
 
 	// Add history stats if available
 	if opts.History != nil {
@@ -298,7 +1022,7 @@ func GenerateInteractiveGraphHTML(opts InteractiveGraphOptions) (string, error)
 
 	dataJSON, err := json.Marshal(graphData)
 	if err != nil {
-		return "", fmt.Errorf("marshal graph data: %w", err)
+		return nil, fmt.Errorf("marshal graph data: %w", err)
 	}
 
 	title := opts.Title
@@ -306,34 +1030,117 @@ func GenerateInteractiveGraphHTML(opts InteractiveGraphOptions) (string, error)
 		title = "Dependency Graph"
 	}
 
-	// Generate filename if not provided
-	outputPath := opts.Path
-	if outputPath == "" {
-		projectName := opts.ProjectName
-		if projectName == "" {
-			projectName = "graph"
-		}
-		outputPath = GenerateInteractiveGraphFilename(projectName)
+	theme := strings.ToLower(strings.TrimSpace(opts.Theme))
+	switch theme {
+	case "":
+		theme = GraphThemeDark
+	case GraphThemeDark, GraphThemeLight, GraphThemeAuto:
+	default:
+		return nil, fmt.Errorf("invalid theme %q (use light, dark, or auto)", opts.Theme)
 	}
 
-	// Ensure .html extension
-	if !strings.HasSuffix(strings.ToLower(outputPath), ".html") {
-		outputPath = strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".html"
+	palette := strings.ToLower(strings.TrimSpace(opts.Palette))
+	switch palette {
+	case "":
+		palette = GraphPaletteDefault
+	case GraphPaletteDefault, GraphPaletteCBSafe:
+	default:
+		return nil, fmt.Errorf("invalid palette %q (use default or cb-safe)", opts.Palette)
 	}
 
-	html := generateUltimateHTML(title, opts.DataHash, string(dataJSON), len(nodes), len(links), opts.ProjectName, forceGraphJS, markedJS)
+	criticalColor, err := normalizeGraphColor(opts.CriticalColor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid critical-path color: %w", err)
+	}
+	articulationColor, err := normalizeGraphColor(opts.ArticulationColor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid articulation color: %w", err)
+	}
 
-	// Ensure directory exists
-	dir := filepath.Dir(outputPath)
-	if dir != "" && dir != "." {
-		if err := os.MkdirAll(dir, 0o755); err != nil {
-			return "", fmt.Errorf("create dir: %w", err)
-		}
+	commitURL, err := validateURLTemplate(opts.CommitURLTemplate, commitURLPlaceholders)
+	if err != nil {
+		return nil, fmt.Errorf("invalid commit URL template: %w", err)
+	}
+	issueURL, err := validateURLTemplate(opts.IssueURLTemplate, issueURLPlaceholders)
+	if err != nil {
+		return nil, fmt.Errorf("invalid issue URL template: %w", err)
+	}
+	avatarURL, err := validateURLTemplate(opts.AvatarURLTemplate, avatarURLPlaceholders)
+	if err != nil {
+		return nil, fmt.Errorf("invalid avatar URL template: %w", err)
+	}
+	// json.Marshal escapes <, > and &, so the templates are safe inside <script>
+	linkTemplatesJSON, err := json.Marshal(map[string]string{"commit": commitURL, "issue": issueURL, "avatar": avatarURL})
+	if err != nil {
+		return nil, fmt.Errorf("marshal URL templates: %w", err)
 	}
 
-	if err := os.WriteFile(outputPath, []byte(html), 0644); err != nil {
-		return "", err
+	if err := validateGraphTypeStyles(opts.TypeStyles); err != nil {
+		return nil, err
+	}
+	typeStyles := DefaultGraphTypeStyles()
+	viewerStyles := make(map[string]viewerTypeStyle, len(typeStyles)+len(opts.TypeStyles))
+	for t, style := range typeStyles {
+		viewerStyles[t] = viewerTypeStyle{GraphTypeStyle: style}
+	}
+	for t, style := range opts.TypeStyles {
+		typeStyles[t] = style
+		viewerStyles[t] = viewerTypeStyle{GraphTypeStyle: style, Custom: true}
+	}
+	typeStylesJSON, err := json.Marshal(viewerStyles)
+	if err != nil {
+		return nil, fmt.Errorf("marshal type styles: %w", err)
+	}
+	// Filter dropdowns and legends list every status/type present in the data
+	presentStatuses := make(map[string]bool)
+	presentTypes := make(map[string]bool)
+	for _, n := range nodes {
+		presentStatuses[n.Status] = true
+		presentTypes[n.Type] = true
+	}
+	statusOrder := graphValueOrder(builtinGraphStatuses, presentStatuses)
+	typeOrder := graphValueOrder(builtinGraphTypes, presentTypes)
+	presentEdgeTypes := make(map[string]bool)
+	for _, l := range links {
+		presentEdgeTypes[l.EdgeType] = true
+	}
+	edgeOrder := graphValueOrder(builtinGraphEdgeTypes, presentEdgeTypes)
+	edgeStyles := make(map[string]graphEdgeStyle, len(edgeOrder))
+	for _, t := range edgeOrder {
+		if style, ok := graphEdgeStyles[t]; ok {
+			edgeStyles[t] = style
+		}
+	}
+	edgeStylesJSON, err := json.Marshal(edgeStyles)
+	if err != nil {
+		return nil, fmt.Errorf("marshal edge styles: %w", err)
 	}
 
-	return outputPath, nil
+	return &interactiveGraph{
+		title:             title,
+		dataHash:          opts.DataHash,
+		projectName:       opts.ProjectName,
+		dataJSON:          dataJSON,
+		nodes:             nodes,
+		links:             links,
+		summary:           summary,
+		triage:            opts.Triage,
+		nodeCount:         len(nodes),
+		edgeCount:         len(links),
+		animations:        !opts.NoAnimation,
+		sanitize:          !opts.NoSanitize,
+		theme:             theme,
+		palette:           palette,
+		criticalColor:     criticalColor,
+		articulationColor: articulationColor,
+		linkTemplatesJSON: string(linkTemplatesJSON),
+		statusOptions:     renderFilterOptions(statusOrder, presentStatuses),
+		typeOptions:       renderFilterOptions(typeOrder, presentTypes),
+		edgeTypeOptions:   renderFilterOptions(edgeOrder, presentEdgeTypes),
+		statusLegend:      renderStatusLegend(statusOrder, presentStatuses),
+		typeLegend:        renderTypeLegend(typeOrder, presentTypes, typeStyles),
+		edgeLegend:        renderEdgeLegend(edgeOrder, presentEdgeTypes),
+		typeStylesJSON:    string(typeStylesJSON),
+		edgeStylesJSON:    string(edgeStylesJSON),
+	}, nil
 }
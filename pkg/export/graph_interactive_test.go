@@ -0,0 +1,1514 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/correlation"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func interactiveTestIssues() []model.Issue {
+	return []model.Issue{
+		{ID: "A", Title: "Alpha", Status: model.StatusOpen, IssueType: model.TypeTask, AcceptanceCriteria: "- [x] one\n- [ ] two"},
+		{ID: "B", Title: "Beta", Status: model.StatusOpen, IssueType: model.TypeTask, Dependencies: []*model.Dependency{
+			{IssueID: "B", DependsOnID: "A", Type: model.DepBlocks},
+		}},
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_Options(t *testing.T) {
+	dir := t.TempDir()
+
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{
+		Issues:      interactiveTestIssues(),
+		Path:        filepath.Join(dir, "graph.html"),
+		NoAnimation: true,
+		Theme:       "Auto",
+		Palette:     "CB-Safe",
+	})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	html := string(data)
+
+	for _, want := range []string{
+		"const EXPORT_ANIMATIONS = false;",
+		"const EXPORT_THEME = 'auto';",
+		"const EXPORT_PALETTE = 'cb-safe';",
+		`"acceptance":{"done":1,"total":2,"ratio":0.5}`,
+		`"topo_level":1`, // B is blocked by A
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected output to contain %q", want)
+		}
+	}
+	if strings.Contains(html, "%!") {
+		t.Error("output contains a fmt formatting error")
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_Defaults(t *testing.T) {
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{
+		Issues: interactiveTestIssues(),
+		Path:   filepath.Join(t.TempDir(), "graph.html"),
+	})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+	if !strings.Contains(html, "const EXPORT_ANIMATIONS = true;") {
+		t.Error("expected animations enabled by default")
+	}
+	if !strings.Contains(html, "const EXPORT_THEME = 'dark';") {
+		t.Error("expected dark theme by default")
+	}
+	if !strings.Contains(html, "const EXPORT_PALETTE = 'default';") {
+		t.Error("expected default palette by default")
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_InvalidTheme(t *testing.T) {
+	_, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{
+		Issues: interactiveTestIssues(),
+		Path:   filepath.Join(t.TempDir(), "graph.html"),
+		Theme:  "sepia",
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid theme") {
+		t.Fatalf("expected invalid theme error, got %v", err)
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_InvalidPalette(t *testing.T) {
+	_, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{
+		Issues:  interactiveTestIssues(),
+		Path:    filepath.Join(t.TempDir(), "graph.html"),
+		Palette: "rainbow",
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid palette") {
+		t.Fatalf("expected invalid palette error, got %v", err)
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_TypeStyles(t *testing.T) {
+	issues := append(interactiveTestIssues(),
+		model.Issue{ID: "Q", Title: "Why?", Status: model.StatusOpen, IssueType: "question"},
+		model.Issue{ID: "D", Title: "Docs", Status: model.StatusOpen, IssueType: "docs"},
+	)
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{
+		Issues:     issues,
+		Path:       filepath.Join(t.TempDir(), "graph.html"),
+		TypeStyles: map[string]GraphTypeStyle{"question": {Shape: "star", Color: "#14b8a6"}},
+	})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+
+	for _, want := range []string{
+		`"question":{"shape":"star","color":"#14b8a6","custom":true}`,
+		`"bug":{"shape":"triangle","color":"#ef4444"}`,
+		`<option value="task">Task</option>`,
+		`<option value="question">Question</option>`,
+		`<option value="docs">Docs</option>`,
+		`<span style="font-size:1rem;color:#14b8a6">★</span> Question`,
+		`<span style="font-size:1rem;color:#8888aa">⬢</span> Docs`, // Unregistered: neutral fallback
+		`<span style="font-size:1rem;color:#ef4444">▲</span> Bug`,  // Built-ins stay in the legend
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected output to contain %q", want)
+		}
+	}
+	// Only types present in the data are filterable
+	if strings.Contains(html, `<option value="bug">`) {
+		t.Error("expected no filter option for absent type bug")
+	}
+	if strings.Contains(html, "%!") {
+		t.Error("output contains a fmt formatting error")
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_EdgeTypes(t *testing.T) {
+	issues := append(interactiveTestIssues(),
+		model.Issue{ID: "E", Title: "Epic", Status: model.StatusOpen, IssueType: model.TypeEpic},
+		model.Issue{ID: "C", Title: "Child", Status: model.StatusOpen, IssueType: model.TypeTask, Dependencies: []*model.Dependency{
+			{IssueID: "C", DependsOnID: "E", Type: model.DepParentChild},
+			{IssueID: "C", DependsOnID: "A", Type: model.DepRelated},
+			{IssueID: "C", DependsOnID: "B"}, // Legacy untyped: blocks
+			{IssueID: "C", DependsOnID: "B", Type: "duplicates"},
+		}},
+	)
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{Issues: issues, Path: filepath.Join(t.TempDir(), "graph.html")})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+
+	for _, want := range []string{
+		`"type":"parent-child","edge_type":"parent"`,
+		`"type":"related","edge_type":"related"`,
+		`"type":"","edge_type":"blocks"`,
+		`"type":"duplicates","edge_type":"duplicates"`,
+		`<option value="parent">Parent</option>`,
+		`<option value="duplicates">Duplicates</option>`,
+		`data-edge-type="related"`,
+		`data-edge-type="discovered-from"`,                           // Built-ins stay in the legend
+		`<span class="edge-sample" style="color:#8888aa">╌╌▶</span>`, // Unknown type: fallback style
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected output to contain %q", want)
+		}
+	}
+
+	type style struct {
+		Color string `json:"color"`
+		Dash  []int  `json:"dash"`
+		Arrow int    `json:"arrow"`
+	}
+	var styles map[string]style
+	runViewerJS(t, html, []string{"const EDGE_STYLES", "const FALLBACK_EDGE_STYLE", "function edgeType", "function edgeStyle"}, `
+const result = {};
+DATA.links.filter(l => l.source === 'C').forEach(l => { result[l.type || '(untyped)'] = edgeStyle(l); });
+out(result);
+`, &styles)
+	if got := styles["related"]; got.Color != "#8be9fd" || !reflect.DeepEqual(got.Dash, []int{4, 3}) || got.Arrow != 0 {
+		t.Errorf("related edge style = %+v, want dashed cyan without an arrow", got)
+	}
+	if got := styles["(untyped)"]; got.Dash != nil || got.Arrow == 0 {
+		t.Errorf("untyped edge style = %+v, want the solid blocks style with an arrow", got)
+	}
+	if got := styles["duplicates"]; got.Color != "#8888aa" || !reflect.DeepEqual(got.Dash, []int{6, 3}) {
+		t.Errorf("unknown edge type style = %+v, want the fallback style", got)
+	}
+	if styles["parent-child"].Arrow <= styles["(untyped)"].Arrow {
+		t.Errorf("parent edge arrow %d should be larger than blocks arrow %d", styles["parent-child"].Arrow, styles["(untyped)"].Arrow)
+	}
+	// Only edge types present in the data are filterable
+	if strings.Contains(html, `<option value="discovered-from">`) {
+		t.Error("expected no filter option for absent edge type discovered-from")
+	}
+	if strings.Contains(html, "%!") {
+		t.Error("output contains a fmt formatting error")
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_EpicCollapse(t *testing.T) {
+	issues := append(interactiveTestIssues(),
+		model.Issue{ID: "E", Title: "Epic", Status: model.StatusOpen, IssueType: model.TypeEpic},
+		model.Issue{ID: "S", Title: "Sub-epic", Status: model.StatusOpen, IssueType: model.TypeEpic, Dependencies: []*model.Dependency{
+			{IssueID: "S", DependsOnID: "E", Type: model.DepParentChild},
+		}},
+		model.Issue{ID: "C", Title: "Child", Status: model.StatusOpen, IssueType: model.TypeTask, Dependencies: []*model.Dependency{
+			{IssueID: "C", DependsOnID: "A", Type: model.DepBlocks},
+			{IssueID: "C", DependsOnID: "S", Type: model.DepParentChild},
+		}},
+	)
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{Issues: issues, Path: filepath.Join(t.TempDir(), "graph.html")})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+
+	for _, want := range []string{
+		`"blocked_by":["A"],"parent":"S"`,
+		`id="ctx-collapse"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected output to contain %q", want)
+		}
+	}
+	if strings.Count(html, `"parent":"`) != 2 {
+		t.Error("expected only the child bead and the sub-epic to carry a parent")
+	}
+
+	type link struct {
+		Source string `json:"source"`
+		Target string `json:"target"`
+		Type   string `json:"type"`
+	}
+	var got struct {
+		Owners map[string]string `json:"owners"`
+		Counts map[string]int    `json:"counts"`
+		Links  []link            `json:"links"`
+		Saved  string            `json:"saved"`
+	}
+	runViewerJS(t, html, []string{"function edgeType", "const parentOf", "function indexParents", "function collapsedAncestor", "function applyEpicCollapse"}, `
+let collapsedEpics = new Set(['E', 'S']), collapsedOwner, collapsedCounts, graphData = { nodes: [], links: [] };
+const Graph = { graphData(d) { if (d) graphData = d; return graphData; } };
+function applyFilters() {}
+indexParents();
+applyEpicCollapse();
+out({
+    owners: Object.fromEntries(collapsedOwner),
+    counts: Object.fromEntries(collapsedCounts),
+    links: graphData.links.map(l => ({ source: l.source, target: l.target, type: l.type })),
+    saved: localStorage.getItem('bv-graph-collapsed-epics'),
+});
+`, &got)
+
+	// Nested collapsed epics fold into the outermost one, and the subtree's
+	// blocking edge is rerouted to it; the parent-child edges inside vanish.
+	if want := map[string]string{"S": "E", "C": "E"}; !reflect.DeepEqual(got.Owners, want) {
+		t.Errorf("collapsed owners = %v, want %v", got.Owners, want)
+	}
+	if want := map[string]int{"E": 2}; !reflect.DeepEqual(got.Counts, want) {
+		t.Errorf("collapsed counts = %v, want %v", got.Counts, want)
+	}
+	if want := []link{{Source: "E", Target: "A", Type: "blocks"}}; !reflect.DeepEqual(got.Links, want) {
+		t.Errorf("rerouted links = %+v, want %+v", got.Links, want)
+	}
+	if got.Saved != `["E","S"]` {
+		t.Errorf("saved collapsed epics = %q, want %q", got.Saved, `["E","S"]`)
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_WhyBlocked(t *testing.T) {
+	blocks := func(id, on string) *model.Dependency {
+		return &model.Dependency{IssueID: id, DependsOnID: on, Type: model.DepBlocks}
+	}
+	// C waits on B, which waits on actionable A; D waits on closed Z and on A;
+	// X and Y block each other, and W waits on X.
+	issues := append(interactiveTestIssues(),
+		model.Issue{ID: "C", Title: "C", Status: model.StatusOpen, Dependencies: []*model.Dependency{blocks("C", "B")}},
+		model.Issue{ID: "Z", Title: "Z", Status: model.StatusClosed},
+		model.Issue{ID: "D", Title: "D", Status: model.StatusOpen, Dependencies: []*model.Dependency{blocks("D", "Z"), blocks("D", "A")}},
+		model.Issue{ID: "X", Title: "X", Status: model.StatusOpen, Dependencies: []*model.Dependency{blocks("X", "Y")}},
+		model.Issue{ID: "Y", Title: "Y", Status: model.StatusOpen, Dependencies: []*model.Dependency{blocks("Y", "X")}},
+		model.Issue{ID: "W", Title: "W", Status: model.StatusOpen, Dependencies: []*model.Dependency{blocks("W", "X")}},
+	)
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{Issues: issues, Path: filepath.Join(t.TempDir(), "graph.html")})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+	for _, want := range []string{`id="hover-why-blocked"`, `id="docked-why-blocked-list"`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected output to contain %q", want)
+		}
+	}
+
+	type reason struct {
+		ID    string   `json:"id"`
+		State string   `json:"state"`
+		Roots []string `json:"roots"`
+		Hops  int      `json:"hops"`
+	}
+	var got map[string][]reason
+	runViewerJS(t, html, []string{"function whyBlocked"}, `
+const byId = new Map(DATA.nodes.map(n => [n.id, n]));
+const result = {};
+['A', 'B', 'C', 'D', 'W'].forEach(id => { result[id] = whyBlocked(byId.get(id)); });
+out(result);
+`, &got)
+
+	want := map[string][]reason{
+		"A": {},
+		"B": {{ID: "A", State: "actionable", Roots: []string{}}},
+		"C": {{ID: "B", State: "blocked", Roots: []string{"A"}, Hops: 1}},
+		"D": {{ID: "A", State: "actionable", Roots: []string{}}}, // Closed Z no longer blocks
+		"W": {{ID: "X", State: "cycle", Roots: []string{}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("whyBlocked:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestGenerateInteractiveGraphJSON_MaxNodesKeepsTopPageRank(t *testing.T) {
+	// Beads a..e all wait on "core"; "lib" waits on nothing but blocks b and c,
+	// so core and lib carry the most PageRank
+	var issues []model.Issue
+	issues = append(issues, model.Issue{ID: "core", Title: "Core", Status: model.StatusOpen, IssueType: model.TypeTask})
+	issues = append(issues, model.Issue{ID: "lib", Title: "Lib", Status: model.StatusOpen, IssueType: model.TypeTask})
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		deps := []*model.Dependency{{IssueID: id, DependsOnID: "core", Type: model.DepBlocks}}
+		if id == "b" || id == "c" {
+			deps = append(deps, &model.Dependency{IssueID: id, DependsOnID: "lib", Type: model.DepBlocks})
+		}
+		issues = append(issues, model.Issue{ID: id, Title: id, Status: model.StatusOpen, IssueType: model.TypeTask, Dependencies: deps})
+	}
+	stats := analysis.NewAnalyzer(issues).Analyze()
+
+	pr := stats.PageRank()
+	ranked := make([]string, 0, len(pr))
+	for id := range pr {
+		ranked = append(ranked, id)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if pr[ranked[i]] != pr[ranked[j]] {
+			return pr[ranked[i]] > pr[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	want := append([]string(nil), ranked[:3]...)
+	sort.Strings(want)
+
+	path := filepath.Join(t.TempDir(), "graph.json")
+	if err := GenerateInteractiveGraphJSON(InteractiveGraphOptions{Issues: issues, Stats: &stats, Path: path, MaxNodes: 3}); err != nil {
+		t.Fatalf("GenerateInteractiveGraphJSON: %v", err)
+	}
+	raw, _ := os.ReadFile(path)
+	var data struct {
+		Nodes []struct {
+			ID string `json:"id"`
+		} `json:"nodes"`
+		Links []struct {
+			Source string `json:"source"`
+			Target string `json:"target"`
+		} `json:"links"`
+		Summary struct {
+			Nodes   int            `json:"nodes"`
+			Omitted *graphOmission `json:"omitted"`
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	var got []string
+	kept := make(map[string]bool)
+	for _, n := range data.Nodes {
+		got = append(got, n.ID)
+		kept[n.ID] = true
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("kept %v, want the top-PageRank beads %v", got, want)
+	}
+	if !kept["core"] || !kept["lib"] {
+		t.Errorf("expected the shared blockers core and lib to be kept, got %v", got)
+	}
+	for _, l := range data.Links {
+		if !kept[l.Source] || !kept[l.Target] {
+			t.Errorf("link %s -> %s touches an omitted bead", l.Source, l.Target)
+		}
+	}
+	want0 := &graphOmission{Nodes: 4, Edges: 7 - len(data.Links), TotalNodes: 7, Metric: "pagerank"}
+	if !reflect.DeepEqual(data.Summary.Omitted, want0) {
+		t.Errorf("omitted = %+v, want %+v", data.Summary.Omitted, want0)
+	}
+
+	if err := GenerateInteractiveGraphJSON(InteractiveGraphOptions{Issues: issues, Path: path, MaxNodes: 3, SampleMetric: "age"}); err == nil {
+		t.Error("expected an unknown sample metric to be rejected")
+	}
+}
+
+func TestGenerateInteractiveGraphJSON_MaxNodesKeepsSampledOutBlockers(t *testing.T) {
+	// Without stats every bead ranks 0, so the sample keeps a and b by ID;
+	// a still waits on z1, which waits on actionable z2
+	issues := []model.Issue{
+		{ID: "a", Title: "A", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "a", DependsOnID: "z1", Type: model.DepBlocks}}},
+		{ID: "b", Title: "B", Status: model.StatusOpen},
+		{ID: "z1", Title: "Z1", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "z1", DependsOnID: "z2", Type: model.DepBlocks}}},
+		{ID: "z2", Title: "Z2", Status: model.StatusOpen},
+	}
+	path := filepath.Join(t.TempDir(), "graph.json")
+	if err := GenerateInteractiveGraphJSON(InteractiveGraphOptions{Issues: issues, Path: path, MaxNodes: 2}); err != nil {
+		t.Fatalf("GenerateInteractiveGraphJSON: %v", err)
+	}
+	raw, _ := os.ReadFile(path)
+	var data struct {
+		Nodes []graphNode `json:"nodes"`
+	}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(data.Nodes) != 2 || data.Nodes[0].ID != "a" {
+		t.Fatalf("expected a and b to be kept, got %+v", data.Nodes)
+	}
+	want := []blockerReason{{ID: "z1", Status: "open", State: "blocked", Roots: []string{"z2"}, Hops: 1}}
+	if got := data.Nodes[0].WhyBlocked; !reflect.DeepEqual(got, want) {
+		t.Errorf("a why_blocked = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadGraphTypeStyles(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "types.yaml")
+	if err := os.WriteFile(good, []byte("question: {shape: star, color: \"#14b8a6\"}\ndocs:\n  shape: hexagon\n  color: \"#abc\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	styles, err := LoadGraphTypeStyles(good)
+	if err != nil {
+		t.Fatalf("LoadGraphTypeStyles: %v", err)
+	}
+	if styles["question"].Shape != "star" || styles["docs"].Color != "#abc" {
+		t.Errorf("unexpected styles: %+v", styles)
+	}
+
+	for name, body := range map[string]string{
+		"shape": "question: {shape: blob, color: \"#14b8a6\"}\n",
+		"color": "question: {shape: star, color: \"red; background: url(x)\"}\n",
+	} {
+		bad := filepath.Join(dir, name+".yaml")
+		if err := os.WriteFile(bad, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadGraphTypeStyles(bad); err == nil || !strings.Contains(err.Error(), "invalid type config") {
+			t.Errorf("%s: expected invalid type config error, got %v", name, err)
+		}
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_StatusOptionsFromData(t *testing.T) {
+	issues := append(interactiveTestIssues(),
+		model.Issue{ID: "R", Title: "Review me", Status: "in_review", IssueType: model.TypeTask},
+		model.Issue{ID: "C", Title: "Done", Status: model.StatusClosed, IssueType: model.TypeTask},
+	)
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{
+		Issues: issues,
+		Path:   filepath.Join(t.TempDir(), "graph.html"),
+	})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+
+	open := strings.Index(html, `<option value="open">Open</option>`)
+	closed := strings.Index(html, `<option value="closed">Closed</option>`)
+	review := strings.Index(html, `<option value="in_review">In Review</option>`)
+	if open < 0 || closed < 0 || review < 0 {
+		t.Fatalf("expected open, closed and in_review filter options")
+	}
+	if !(open < closed && closed < review) {
+		t.Error("expected built-in statuses first, then other statuses")
+	}
+	if strings.Contains(html, `<option value="blocked">`) {
+		t.Error("expected no filter option for absent status blocked")
+	}
+	for _, want := range []string{
+		`style="background:#94a3b8;color:#94a3b8"></div>In Review</div>`, // Fallback legend entry
+		`style="background:var(--red);color:var(--red)"></div>Blocked</div>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected legend to contain %q", want)
+		}
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_SubgraphMermaid(t *testing.T) {
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{
+		Issues: interactiveTestIssues(),
+		Path:   filepath.Join(t.TempDir(), "graph.html"),
+	})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+
+	for _, want := range []string{
+		`id="ctx-mermaid"`,
+		`id="btn-mermaid"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected viewer to contain %q", want)
+		}
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_SubgraphMermaidMatchesExport(t *testing.T) {
+	// Labels needing escapes, a title to wrap and truncate, IDs whose safe
+	// forms collide, and every status class
+	issues := []model.Issue{
+		{ID: "a.b", Title: `Fix "quoted" <b>|pipes|</b> & [brackets] {braces} #hash`, Status: model.StatusOpen},
+		{ID: "ab", Title: "supercalifragilisticexpialidocious-and-then-some words that keep going on and on past three lines", Status: model.StatusInProgress,
+			Dependencies: []*model.Dependency{{IssueID: "ab", DependsOnID: "a.b", Type: model.DepBlocks}}},
+		{ID: "zé-1", Title: "Ünïcode\ttitle\nwith controls", Status: model.StatusBlocked,
+			Dependencies: []*model.Dependency{
+				{IssueID: "zé-1", DependsOnID: "ab", Type: model.DepRelated},
+				{IssueID: "zé-1", DependsOnID: "a.b", Type: model.DepBlocks},
+			}},
+		{ID: "c", Title: "closed", Status: model.StatusClosed},
+		{ID: "t", Title: "", Status: model.StatusTombstone},
+		{ID: "r", Title: "review", Status: "in_review", Dependencies: []*model.Dependency{{IssueID: "r", DependsOnID: "c"}}},
+	}
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{Issues: issues, Path: filepath.Join(t.TempDir(), "graph.html")})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+
+	var got string
+	runViewerJS(t, string(data), []string{
+		"const MERMAID_TITLE_WIDTH", "const MERMAID_ESCAPES", "MERMAID_ESCAPES[String.fromCharCode(96)]", "function stripControl",
+		"function escapeMermaidLabel", "function wrapMermaidTitle", "function mermaidStatusClass", "function fnv32aHex", "function subgraphMermaid",
+	}, `out(subgraphMermaid(DATA.nodes.map(n => n.id)));`, &got)
+
+	ids := make(map[string]bool, len(issues))
+	for _, iss := range issues {
+		ids[iss.ID] = true
+	}
+	if want := generateMermaid(issues, ids); got != want {
+		t.Errorf("viewer Mermaid differs from --export-graph .mmd\n got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_CompressData(t *testing.T) {
+	var stats GraphPayloadStats
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{
+		Issues:       interactiveTestIssues(),
+		Path:         filepath.Join(t.TempDir(), "graph.html"),
+		CompressData: true,
+		PayloadStats: &stats,
+	})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+
+	if !stats.Compressed || stats.RawBytes == 0 || stats.EmbeddedBytes == 0 {
+		t.Fatalf("expected compressed payload stats, got %+v", stats)
+	}
+	match := regexp.MustCompile(`\}\)\("([A-Za-z0-9+/=]+)"\);`).FindStringSubmatch(html)
+	if match == nil {
+		t.Fatal("expected base64 gzip payload passed to the inflate loader")
+	}
+	gz, err := base64.StdEncoding.DecodeString(match[1])
+	if err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if len(match[1]) != stats.EmbeddedBytes {
+		t.Errorf("EmbeddedBytes = %d, want %d", stats.EmbeddedBytes, len(match[1]))
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(gz))
+	if err != nil {
+		t.Fatalf("gunzip payload: %v", err)
+	}
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("gunzip payload: %v", err)
+	}
+	if len(raw) != stats.RawBytes {
+		t.Errorf("RawBytes = %d, want %d", stats.RawBytes, len(raw))
+	}
+	var graph struct {
+		Nodes []graphNode `json:"nodes"`
+	}
+	if err := json.Unmarshal(raw, &graph); err != nil {
+		t.Fatalf("payload is not graph JSON: %v", err)
+	}
+	if len(graph.Nodes) != len(interactiveTestIssues()) {
+		t.Errorf("expected %d nodes in payload, got %d", len(interactiveTestIssues()), len(graph.Nodes))
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_SearchesCommitMessages(t *testing.T) {
+	history := &correlation.HistoryReport{Histories: map[string]correlation.BeadHistory{
+		"A": {BeadID: "A", Commits: []correlation.CorrelatedCommit{{SHA: "abc1234def", ShortSHA: "abc1234", Message: "fix token refresh"}}},
+	}}
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{
+		Issues:  interactiveTestIssues(),
+		History: history,
+		Path:    filepath.Join(t.TempDir(), "graph.html"),
+	})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+
+	for _, want := range []string{
+		`"message":"fix token refresh"`,
+		"(n.commits || []).map(c => c.message || '')", // Commit messages are a search field
+		`'<span class="search-result-sha">' + escapeHtml(commit.short_sha)`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected viewer to contain %q", want)
+		}
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_SearchPagination(t *testing.T) {
+	// 30 beads match "widget": every third also has it in the description, so
+	// its row carries a preview and is taller
+	var issues []model.Issue
+	for i := 0; i < 30; i++ {
+		iss := model.Issue{ID: fmt.Sprintf("W-%02d", i), Title: "Widget " + strconv.Itoa(i), Status: model.StatusOpen}
+		if i%3 == 0 {
+			iss.Description = "the widget does things"
+		}
+		issues = append(issues, iss)
+	}
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{Issues: issues, Path: filepath.Join(t.TempDir(), "graph.html")})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+	for _, want := range []string{
+		`class="search-more">Show more</button>`,
+		"setTimeout(() => performSearch(e.target.value), 150)",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected viewer to contain %q", want)
+		}
+	}
+
+	type page struct {
+		Count  string `json:"count"`
+		Height string `json:"height"`
+		Rows   []struct {
+			ID     string `json:"id"`
+			Top    int    `json:"top"`
+			Height int    `json:"height"`
+		} `json:"rows"`
+	}
+	var pages []page
+	runViewerJS(t, html, []string{
+		"function escapeHtml", "const escapeAttr", "function searchFields", "function searchSnippet",
+		"function boundedLevenshtein", "let searchTokenCache", "function searchTokens", "function fuzzyMatch", "function rankSearchMatches",
+		"const SEARCH_PAGE_SIZE", "const SEARCH_ROW_HEIGHT", "const SEARCH_ROW_HEIGHT_PLAIN", "const SEARCH_OVERSCAN", "let searchResults",
+		"function performSearch", "function searchPreview", "function layoutSearchRows", "function searchRowAt",
+		"function searchResultRow", "function renderSearchWindow", "function showMoreSearchResults",
+	}, `
+function applyFilters() {}
+const resultsEl = document.getElementById('search-results'), windowEl = resultsEl.querySelector('.search-results-window');
+function snapshot() {
+    const rows = [...windowEl.innerHTML.matchAll(/data-id="([^"]*)" style="top:(\d+)px;height:(\d+)px"/g)]
+        .map(m => ({ id: m[1], top: +m[2], height: +m[3] }));
+    return { count: resultsEl.querySelector('.search-results-count').textContent, height: windowEl.style.height, rows: rows };
+}
+const pages = [];
+performSearch('widget');
+pages.push(snapshot());
+showMoreSearchResults(); showMoreSearchResults(); showMoreSearchResults();
+pages.push(snapshot());
+resultsEl.scrollTop = 2000;
+renderSearchWindow();
+pages.push(snapshot());
+out(pages);
+`, &pages)
+	if len(pages) != 3 {
+		t.Fatalf("got %d snapshots, want 3", len(pages))
+	}
+
+	// Rows stack without gaps: 112px with a description preview, 72px without
+	first := pages[0]
+	if first.Count != "Showing 8 of 30" || len(first.Rows) != 8 {
+		t.Fatalf("first page = %q with %d rows, want 8 of 30", first.Count, len(first.Rows))
+	}
+	for i, r := range first.Rows {
+		if i > 0 && r.Top != first.Rows[i-1].Top+first.Rows[i-1].Height {
+			t.Errorf("row %d at %dpx overlaps or gaps the row above", i, r.Top)
+		}
+		n, _ := strconv.Atoi(strings.TrimPrefix(r.ID, "W-"))
+		want := 72
+		if n%3 == 0 {
+			want = 112
+		}
+		if r.Height != want {
+			t.Errorf("row %s height %d, want %d", r.ID, r.Height, want)
+		}
+	}
+
+	// All 30 loaded: 10 previewed rows, 20 plain ones, but only the rows in
+	// view plus overscan are in the DOM
+	all := pages[1]
+	if all.Count != "Showing 30 of 30" || all.Height != strconv.Itoa(10*112+20*72)+"px" {
+		t.Errorf("after show more: %q, window height %s", all.Count, all.Height)
+	}
+	scrolled := pages[2]
+	if len(scrolled.Rows) == 0 || len(scrolled.Rows) >= 30 {
+		t.Fatalf("scrolled window renders %d rows, want a windowed subset", len(scrolled.Rows))
+	}
+	if top := scrolled.Rows[0].Top; top > 2000 || top+scrolled.Rows[0].Height+4*112 < 2000 {
+		t.Errorf("first rendered row at %dpx does not cover scroll offset 2000 with overscan", top)
+	}
+	if last := scrolled.Rows[len(scrolled.Rows)-1]; last.Top+last.Height < 2400 {
+		t.Errorf("rendered rows end at %dpx, before the bottom of the 400px viewport", last.Top+last.Height)
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_EdgeBundling(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "A1", Title: "a1", Status: model.StatusOpen},
+		{ID: "A2", Title: "a2", Status: model.StatusOpen, Dependencies: []*model.Dependency{{DependsOnID: "A1", Type: model.DepBlocks}}},
+		{ID: "B1", Title: "b1", Status: model.StatusOpen},
+		{ID: "B2", Title: "b2", Status: model.StatusOpen, Dependencies: []*model.Dependency{{DependsOnID: "B1", Type: model.DepBlocks}}},
+	}
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{
+		Issues: issues,
+		Path:   filepath.Join(t.TempDir(), "graph.html"),
+	})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+
+	// Each node carries its community so the viewer can bundle between them
+	for _, want := range []string{`"id":"A1"`, `"id":"B2"`} {
+		if !strings.Contains(html, want) {
+			t.Fatalf("expected node %s in data", want)
+		}
+	}
+	if !strings.Contains(html, `"cluster":0`) || !strings.Contains(html, `"cluster":1`) {
+		t.Error("expected two clusters in the node data")
+	}
+
+	for _, want := range []string{
+		`<button id="btn-bundle"`,
+		"if (bundledLink(l)) return false;",
+		".onRenderFramePre(drawEdgeBundles)",
+		"case 'u': toggleEdgeBundling(); break;",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected viewer to contain %q", want)
+		}
+	}
+
+	// Only links between clusters are bundled, and only while bundling is on,
+	// clusters exist and the link is not highlighted
+	var got map[string]bool
+	runViewerJS(t, html, []string{"const clusterOf", "let clustersAvailable", "function indexClusters", "function bundledLink"}, `
+let edgeBundling = false, highlightedNodes = new Set();
+const across = { source: 'A2', target: 'B1' }, within = { source: { id: 'A2' }, target: { id: 'A1' } };
+const result = {};
+indexClusters();
+result.off = bundledLink(across);
+edgeBundling = true;
+result.across = bundledLink(across);
+result.within = bundledLink(within);
+highlightedNodes = new Set(['A2', 'B1']);
+result.highlighted = bundledLink(across);
+highlightedNodes = new Set();
+DATA.nodes.forEach(n => { delete n.cluster; });
+indexClusters();
+result.noClusters = bundledLink(across);
+out(result);
+`, &got)
+	want := map[string]bool{"off": false, "across": true, "within": false, "highlighted": false, "noClusters": false}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("bundledLink = %v, want %v", got, want)
+	}
+}
+
+func TestBuildInteractiveGraph_SkipsClustersAboveCap(t *testing.T) {
+	issues := make([]model.Issue, MaxClusteredIssues+1)
+	for i := range issues {
+		issues[i] = model.Issue{ID: fmt.Sprintf("N-%d", i), Title: "n", Status: model.StatusOpen}
+	}
+	g, err := buildInteractiveGraph(InteractiveGraphOptions{Issues: issues})
+	if err != nil {
+		t.Fatalf("buildInteractiveGraph: %v", err)
+	}
+	for _, n := range g.nodes {
+		if n.Cluster != nil {
+			t.Fatalf("node %s has cluster %d above the %d-issue cap", n.ID, *n.Cluster, MaxClusteredIssues)
+		}
+	}
+
+	g, err = buildInteractiveGraph(InteractiveGraphOptions{Issues: issues[:MaxClusteredIssues]})
+	if err != nil {
+		t.Fatalf("buildInteractiveGraph: %v", err)
+	}
+	if g.nodes[0].Cluster == nil {
+		t.Error("expected clusters at the cap")
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_ExplainsMetrics(t *testing.T) {
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{
+		Issues: interactiveTestIssues(),
+		Path:   filepath.Join(t.TempDir(), "graph.html"),
+	})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+
+	for _, want := range []string{
+		`<div class="panel-title">How Metrics Are Computed</div>`,
+		`<button class="metric-info" data-metric="betweenness" title="What is Betweenness?">`,
+		`<button class="metric-info" data-metric="slack" title="What is Slack?">`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected viewer to contain %q", want)
+		}
+	}
+
+	// Percentiles count beads on the less critical side; for slack that is
+	// the beads with more of it. Beads without the metric are left out.
+	var got []any
+	runViewerJS(t, html, []string{"const METRIC_INFO", "function metricPercentile"}, `
+DATA.nodes = [
+    { pagerank: 0.1, slack: 0 }, { pagerank: 0.2, slack: 3 }, { pagerank: 0.3, slack: 5 },
+    { pagerank: 0.4, slack: null }, { pagerank: NaN },
+];
+const info = key => METRIC_INFO.find(m => m.key === key);
+out([
+    metricPercentile(DATA.nodes[3], info('pagerank')),
+    metricPercentile(DATA.nodes[0], info('pagerank')),
+    metricPercentile(DATA.nodes[0], info('slack')),
+    metricPercentile(DATA.nodes[3], info('slack')),
+]);
+`, &got)
+	want := []any{"Higher than 75% of beads", "Higher than 0% of beads", "Lower than 67% of beads", nil}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("metricPercentile = %v, want %v", got, want)
+	}
+}
+
+func TestLiveGraphPatchTo(t *testing.T) {
+	before := []model.Issue{
+		{ID: "A", Title: "First", Status: model.StatusOpen},
+		{ID: "B", Title: "Second", Status: model.StatusOpen, Dependencies: []*model.Dependency{{DependsOnID: "A", Type: model.DepBlocks}}},
+		{ID: "C", Title: "Third", Status: model.StatusOpen, Dependencies: []*model.Dependency{{DependsOnID: "A", Type: model.DepRelated}}},
+	}
+	after := []model.Issue{
+		{ID: "A", Title: "First", Status: model.StatusOpen},
+		{ID: "B", Title: "Second, renamed", Status: model.StatusOpen, Dependencies: []*model.Dependency{{DependsOnID: "A", Type: model.DepBlocks}}},
+		{ID: "D", Title: "Fourth", Status: model.StatusOpen, Dependencies: []*model.Dependency{{DependsOnID: "B", Type: model.DepBlocks}}},
+	}
+	base, err := NewLiveGraph(InteractiveGraphOptions{Issues: before, DataHash: "h1", LiveEventsURL: "/events"})
+	if err != nil {
+		t.Fatalf("NewLiveGraph: %v", err)
+	}
+	next, err := NewLiveGraph(InteractiveGraphOptions{Issues: after, DataHash: "h2", LiveEventsURL: "/events", PatchSeq: 1})
+	if err != nil {
+		t.Fatalf("NewLiveGraph: %v", err)
+	}
+
+	// A changes too: losing C's link changes its degree
+	p := base.PatchTo(next)
+	if p.Seq != 1 || p.BaseHash != "h1" || p.DataHash != "h2" {
+		t.Errorf("seq/base/data = %d/%s/%s, want 1/h1/h2", p.Seq, p.BaseHash, p.DataHash)
+	}
+	ids := func(nodes []graphNode) string {
+		var out []string
+		for _, n := range nodes {
+			out = append(out, n.ID)
+		}
+		return strings.Join(out, ",")
+	}
+	if ids(p.Nodes.Added) != "D" || ids(p.Nodes.Changed) != "A,B" || strings.Join(p.Nodes.Removed, ",") != "C" {
+		t.Errorf("nodes added=%s changed=%s removed=%v, want D, A,B, [C]", ids(p.Nodes.Added), ids(p.Nodes.Changed), p.Nodes.Removed)
+	}
+	if len(p.Links.Added) != 1 || p.Links.Added[0].Source != "D" || p.Links.Added[0].Target != "B" {
+		t.Errorf("links added = %+v, want D->B", p.Links.Added)
+	}
+	if len(p.Links.Removed) != 1 || p.Links.Removed[0] != (graphLinkRef{Source: "C", Target: "A", Type: "related"}) {
+		t.Errorf("links removed = %+v, want C->A related", p.Links.Removed)
+	}
+	if p.Summary.Nodes != 3 {
+		t.Errorf("summary nodes = %d, want 3", p.Summary.Nodes)
+	}
+
+	page := next.HTML()
+	if want := `"live":{"events":"/events","seq":1}`; !strings.Contains(page, want) {
+		t.Errorf("expected live page to contain %q", want)
+	}
+
+	// Applied in the viewer, the patch turns the base page's data into the
+	// next page's; a patch out of sequence reloads the page instead
+	type viewerData struct {
+		Nodes   []graphNode  `json:"nodes"`
+		Links   []graphLink  `json:"links"`
+		Summary graphSummary `json:"summary"`
+		Hash    string       `json:"data_hash"`
+	}
+	const sortedData = `
+const key = l => l.source + '|' + l.target + '|' + l.type;
+DATA.links.sort((a, b) => key(a) < key(b) ? -1 : key(a) > key(b) ? 1 : 0);
+`
+	var want viewerData
+	runViewerJS(t, page, nil, sortedData+`out(DATA);`, &want)
+	var got struct {
+		Applied  []bool     `json:"applied"`
+		Reloads  int        `json:"reloads"`
+		Data     viewerData `json:"data"`
+		Rendered int        `json:"rendered"`
+	}
+	runViewerJS(t, base.HTML(), []string{"let graphPatchSeq", "function applyGraphPatch"}, `
+let reloads = 0, rendered = null, searchTokenCache = null, selectedNode = null;
+const location = { reload() { reloads++; } };
+const collapsedEpics = new Set(), childrenOf = new Map();
+const Graph = { graphData(d) { if (d) rendered = d; return rendered || { nodes: [] }; } };
+function computeStats() {} function computeMetricMaxima() {} function computeScheduleRisk() {}
+function indexClusters() {} function indexParents() {} function indexAssignees() {}
+function renderTriageList() {} function applyEpicCollapse() {} function clearSelection() {} function selectNode() {}
+const patch = `+jsLiteral(t, p)+`;
+const applied = [applyGraphPatch(patch), applyGraphPatch(patch)];
+`+sortedData+`
+out({ applied: applied, reloads: reloads, data: DATA, rendered: rendered.nodes.length });
+`, &got)
+	if !reflect.DeepEqual(got.Applied, []bool{true, false}) || got.Reloads != 1 {
+		t.Errorf("applied = %v with %d reloads, want [true false] with 1 (replayed patch)", got.Applied, got.Reloads)
+	}
+	if !reflect.DeepEqual(got.Data, want) {
+		t.Errorf("patched viewer data differs from the next page:\n got %+v\nwant %+v", got.Data, want)
+	}
+	if got.Rendered != len(want.Nodes) {
+		t.Errorf("graph re-rendered %d nodes, want %d", got.Rendered, len(want.Nodes))
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_HighlightDepthControl(t *testing.T) {
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{
+		Issues: interactiveTestIssues(),
+		Path:   filepath.Join(t.TempDir(), "graph.html"),
+	})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+
+	for _, want := range []string{
+		`<input type="range" id="highlight-depth" min="1" max="5"`,
+		"localStorage.getItem('bv-graph-highlight-depth')",
+		"getConnectedNodes(node.id, highlightDepth)",
+		"case ']': setHighlightDepth(highlightDepth + 1)",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected viewer to contain %q", want)
+		}
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_BlastRadiusMode(t *testing.T) {
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{
+		Issues: interactiveTestIssues(),
+		Path:   filepath.Join(t.TempDir(), "graph.html"),
+	})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+
+	for _, want := range []string{
+		`<button id="btn-blast"`,
+		"if (blastMode) showBlastRadius(node);",
+		"case 'b': cycleBlastMode(); break;",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected viewer to contain %q", want)
+		}
+	}
+
+	// C waits on B, which waits on A: downstream of A is everything waiting on
+	// it, upstream of C everything it waits on
+	var got map[string]map[string]int
+	runViewerJS(t, html, []string{"function computeBlastRadius"}, `
+DATA.links.push({ source: 'C', target: 'B', type: 'blocks' });
+const dist = (id, dir) => Object.fromEntries(computeBlastRadius(id, dir));
+out({ downA: dist('A', 'downstream'), upC: dist('C', 'upstream'), downC: dist('C', 'downstream') });
+`, &got)
+	want := map[string]map[string]int{
+		"downA": {"A": 0, "B": 1, "C": 2},
+		"upC":   {"C": 0, "B": 1, "A": 2},
+		"downC": {"C": 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("computeBlastRadius = %v, want %v", got, want)
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_CriticalColors(t *testing.T) {
+	dir := t.TempDir()
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{
+		Issues:            interactiveTestIssues(),
+		Path:              filepath.Join(dir, "graph.html"),
+		CriticalColor:     "#0EA5E9",
+		ArticulationColor: "#abc",
+	})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+	for _, want := range []string{
+		"const CRITICAL_COLOR_OVERRIDE = '#0ea5e9';",
+		"const ARTICULATION_COLOR = '#aabbcc' || '#ec4899';",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected viewer to contain %q", want)
+		}
+	}
+
+	// Defaults keep the built-in pink
+	path, err = GenerateInteractiveGraphHTML(InteractiveGraphOptions{Issues: interactiveTestIssues(), Path: filepath.Join(dir, "default.html")})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if !strings.Contains(string(data), "const CRITICAL_COLOR_OVERRIDE = '';") {
+		t.Error("expected no critical color override by default")
+	}
+
+	if _, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{Issues: interactiveTestIssues(), Path: filepath.Join(dir, "bad.html"), CriticalColor: "red"}); err == nil {
+		t.Error("expected an error for a non-hex critical color")
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_ScheduleHeatmapMetrics(t *testing.T) {
+	due := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	issues := interactiveTestIssues()
+	issues[0].UpdatedAt = time.Date(2025, 1, 2, 3, 4, 0, 0, time.UTC)
+	issues[0].DueDate = &due
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{
+		Issues: issues,
+		Path:   filepath.Join(t.TempDir(), "graph.html"),
+	})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+
+	for _, want := range []string{
+		`<option value="staleness">Size: Staleness</option>`,
+		`<option value="due">Size: Due Urgency</option>`,
+		`staleness: 'Staleness', due: 'Due Urgency'`,
+		"case 'staleness': case 'due':",
+		`"updated_at":"2025-01-02 03:04"`,
+		`"due_date":"2025-03-01"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected viewer to contain %q", want)
+		}
+	}
+}
+
+func TestGenerateInteractiveGraphJSON(t *testing.T) {
+	issues := append(interactiveTestIssues(),
+		model.Issue{ID: "C", Title: "Done", Status: model.StatusClosed, IssueType: model.TypeBug},
+	)
+	path := filepath.Join(t.TempDir(), "out", "graph.json")
+	if err := GenerateInteractiveGraphJSON(InteractiveGraphOptions{Issues: issues, Path: path, DataHash: "h1"}); err != nil {
+		t.Fatalf("GenerateInteractiveGraphJSON: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+
+	var got struct {
+		Nodes    []graphNode  `json:"nodes"`
+		Links    []graphLink  `json:"links"`
+		Summary  graphSummary `json:"summary"`
+		DataHash string       `json:"data_hash"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("export is not JSON: %v", err)
+	}
+	if len(got.Nodes) != 3 || len(got.Links) != 1 || got.DataHash != "h1" {
+		t.Fatalf("unexpected export: %d nodes, %d links, hash %q", len(got.Nodes), len(got.Links), got.DataHash)
+	}
+	want := graphSummary{
+		Nodes:        3,
+		Edges:        1,
+		ByStatus:     map[string]int{"open": 2, "closed": 1},
+		ByType:       map[string]int{"task": 2, "bug": 1},
+		MaxTopoLevel: 1,
+	}
+	if !reflect.DeepEqual(got.Summary, want) {
+		t.Errorf("summary = %+v, want %+v", got.Summary, want)
+	}
+
+	// The HTML viewer embeds the same payload
+	htmlPath, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{Issues: issues, Path: filepath.Join(t.TempDir(), "graph.html"), DataHash: "h1"})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	page, _ := os.ReadFile(htmlPath)
+	summaryJSON, _ := json.Marshal(want)
+	if !strings.Contains(string(page), `"summary":`+string(summaryJSON)) {
+		t.Error("expected the viewer DATA to carry the same summary block")
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_CameraLock(t *testing.T) {
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{Issues: interactiveTestIssues(), Path: filepath.Join(t.TempDir(), "graph.html")})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+	for _, want := range []string{
+		`id="btn-lock"`,
+		".onEngineTick(followSelection)",
+		".onEngineStop(() => { cameraFollowing = false; })",
+		".onBackgroundClick(() => { cameraFollowing = false;",
+		"case 'k': toggleCameraLock(); break;",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected viewer to contain %q", want)
+		}
+	}
+
+	// Each step ticks the engine once and records how many times the camera
+	// was centered.
+	var centered []int
+	runViewerJS(t, html, []string{"function followSelection", "function toggleCameraLock"}, `
+let cameraLocked = false, cameraFollowing = false, selectedNode = null, calls = 0;
+const Graph = { centerAt() { calls++; } };
+function showToast() {}
+const steps = [];
+function tick() { calls = 0; followSelection(); steps.push(calls); }
+selectedNode = { x: 1, y: 2 };
+tick();                                  // Unlocked: camera stays put
+toggleCameraLock(); tick();              // Locked with a selection: follows
+cameraFollowing = false; tick();         // Engine stopped or background click: released
+selectedNode = { x: NaN, y: 0 }; cameraFollowing = cameraLocked; tick(); // No position yet
+toggleCameraLock(); selectedNode = { x: 3, y: 4 }; cameraFollowing = cameraLocked; tick(); // Unlocked again
+out(steps);
+`, &centered)
+	if want := []int{0, 1, 0, 0, 0}; !reflect.DeepEqual(centered, want) {
+		t.Errorf("centerAt calls per tick = %v, want %v", centered, want)
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_PriorityRangeFilter(t *testing.T) {
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{Issues: interactiveTestIssues(), Path: filepath.Join(t.TempDir(), "graph.html")})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+	for _, want := range []string{
+		`id="filter-priority-min"`,
+		`id="filter-priority-max"`,
+		"if (priorityMax !== null && n.priority > priorityMax) return false;",
+		"Graph.nodeVisibility(currentVisibilityFilter);",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected viewer to contain %q", want)
+		}
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_URLTemplates(t *testing.T) {
+	history := &correlation.HistoryReport{Histories: map[string]correlation.BeadHistory{
+		"A": {BeadID: "A", Commits: []correlation.CorrelatedCommit{{SHA: "0123456789abcdef", ShortSHA: "0123456"}}},
+	}}
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{
+		Issues:            interactiveTestIssues(),
+		History:           history,
+		Path:              filepath.Join(t.TempDir(), "graph.html"),
+		CommitURLTemplate: "https://gitlab.example.com/group/proj/-/commit/{sha}?ref=</script>&x={short_sha}",
+		IssueURLTemplate:  "https://gitea.example.com/org/repo/issues/{id}",
+		AvatarURLTemplate: "https://avatars.example.com/{assignee}.png",
+	})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+	for _, want := range []string{
+		`const LINK_TEMPLATES = {"avatar":"https://avatars.example.com/{assignee}.png","commit":"https://gitlab.example.com/group/proj/-/commit/{sha}?ref=\u003c/script\u003e\u0026x={short_sha}","issue":"https://gitea.example.com/org/repo/issues/{id}"};`,
+		"templateURL(LINK_TEMPLATES.commit, { sha: c.sha, short_sha: c.short_sha })",
+		"templateURL(LINK_TEMPLATES.issue, { id: node.id })",
+		`"sha":"0123456789abcdef"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected viewer to contain %q", want)
+		}
+	}
+	if strings.Contains(html, "ref=</script>") {
+		t.Error("URL template must be escaped inside the script block")
+	}
+
+	for _, tc := range []struct{ commit, issue, avatar string }{
+		{commit: "javascript:alert(1)//{sha}"},
+		{commit: "https://gitlab.example.com/commit/{hash}"},
+		{commit: "https://gitlab.example.com/commit/"},
+		{issue: "/relative/{id}"},
+		{avatar: "https://avatars.example.com/{id}.png"},
+	} {
+		_, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{
+			Issues:            interactiveTestIssues(),
+			Path:              filepath.Join(t.TempDir(), "graph.html"),
+			CommitURLTemplate: tc.commit,
+			IssueURLTemplate:  tc.issue,
+			AvatarURLTemplate: tc.avatar,
+		})
+		if err == nil {
+			t.Errorf("expected templates %+v to be rejected", tc)
+		}
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_AssigneeBadges(t *testing.T) {
+	issues := interactiveTestIssues()
+	issues[0].Assignee = "alice.smith@example.com"
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{
+		Issues: issues,
+		Path:   filepath.Join(t.TempDir(), "graph.html"),
+	})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+	for _, want := range []string{
+		`"assignee":"alice.smith@example.com"`,
+		`const LINK_TEMPLATES = {"avatar":"",`,
+		`<option value="assignee">Color: Assignee</option>`,
+		`<div class="legend" id="assignee-legend"></div>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected viewer to contain %q", want)
+		}
+	}
+
+	var got struct {
+		Initials []string `json:"initials"`
+		Colors   []string `json:"colors"`
+	}
+	runViewerJS(t, html, []string{"const ASSIGNEE_COLORS", "const UNASSIGNED_COLOR", "const assigneeIndex", "function indexAssignees", "function assigneeColor", "function assigneeInitials"}, `
+function renderAssigneeLegend() {}
+DATA.nodes = [{ assignee: 'bob' }, { assignee: 'alice.smith@example.com' }, { assignee: 'alice.smith@example.com' }, {}];
+indexAssignees();
+out({
+    initials: ['alice', 'Alice Smith', 'alice.smith@example.com', 'jean-luc_picard', 'élodie', '@x', ''].map(assigneeInitials),
+    colors: ['alice.smith@example.com', 'bob', 'bob', 'nobody', ''].map(assigneeColor),
+});
+`, &got)
+	if want := []string{"AL", "AS", "AS", "JP", "ÉL", "?", "?"}; !reflect.DeepEqual(got.Initials, want) {
+		t.Errorf("assigneeInitials = %v, want %v", got.Initials, want)
+	}
+	c := got.Colors
+	if len(c) != 5 || c[0] == c[1] || c[1] != c[2] || c[3] != c[4] || c[3] == c[0] || c[3] == c[1] {
+		t.Errorf("assigneeColor = %v, want distinct stable colors per assignee and one for unknown or unassigned", c)
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_HealthScore(t *testing.T) {
+	issues := append(interactiveTestIssues(),
+		model.Issue{ID: "Z", Title: "Done", Status: model.StatusClosed, IssueType: model.TypeTask})
+	stats := analysis.NewAnalyzer(issues).Analyze()
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{
+		Issues:        issues,
+		Stats:         &stats,
+		Path:          filepath.Join(t.TempDir(), "graph.html"),
+		HealthWeights: analysis.HealthWeights{Blockers: 1},
+	})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+
+	for _, want := range []string{
+		`<option value="health">Size: Health Risk</option>`,
+		`"unblock_impact":1,"health":100}`, // Blockers-only weights: A is unblocked
+		`"unblock_impact":0,"health":67}`,  // B waits on one open blocker of HealthBlockerCap
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected output to contain %q", want)
+		}
+	}
+	// Closed beads are not scored
+	if n := strings.Count(html, `"health":`); n != 2 {
+		t.Errorf("expected 2 scored beads, got %d", n)
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_LabelPatternFilter(t *testing.T) {
+	issues := interactiveTestIssues()
+	issues[0].Labels = []string{"team/infra"}
+	issues[1].Labels = []string{"team/frontend"}
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{Issues: issues, Path: filepath.Join(t.TempDir(), "graph.html")})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+
+	for _, want := range []string{
+		`<input type="text" id="filter-label" list="filter-label-options"`,
+		`<datalist id="filter-label-options">`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected output to contain %q", want)
+		}
+	}
+
+	// The viewer must agree with bv --label on what each pattern selects
+	labels := []string{"team/infra", "Team/Infra", "team/frontend", "ops", "myteam/infra"}
+	patterns := []string{"team/infra", "team/*", "p[!0-2]", `/^team\/(infra|web)$/`, "/(?i)^TEAM/", "/(?is)ops/", "", "/(/", "p[", "//"}
+	var got map[string]any
+	runViewerJS(t, html, []string{"function compileLabelPattern"}, `
+const labels = `+jsLiteral(t, labels)+`, result = {};
+`+jsLiteral(t, patterns)+`.forEach(p => {
+    try {
+        const f = compileLabelPattern(p);
+        result[p] = f ? labels.filter(f) : null;
+    } catch (e) {
+        result[p] = 'error';
+    }
+});
+out(result);
+`, &got)
+	for _, p := range patterns {
+		var want any = "error"
+		if m, err := analysis.ParseLabelPattern(p); err == nil {
+			if p == "" {
+				want = nil
+			} else {
+				matched := []any{}
+				for _, l := range labels {
+					if m.Match(l) {
+						matched = append(matched, l)
+					}
+				}
+				want = matched
+			}
+		}
+		if !reflect.DeepEqual(got[p], want) {
+			t.Errorf("compileLabelPattern(%q) selects %v, bv --label selects %v", p, got[p], want)
+		}
+	}
+	if strings.Contains(html, `<select id="filter-label">`) {
+		t.Error("label filter should be a pattern input, not a select")
+	}
+	if strings.Contains(html, "%!") {
+		t.Error("template formatting error in output")
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_EscapesBeadContent(t *testing.T) {
+	const payload = `<img src=x onerror=alert(1)>`
+	issues := interactiveTestIssues()
+	issues[0].Title = payload
+	issues[0].Labels = []string{payload}
+	issues[0].Assignee = payload
+	path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{
+		Issues:      issues,
+		Title:       payload,
+		ProjectName: payload,
+		Path:        filepath.Join(t.TempDir(), "graph.html"),
+	})
+	if err != nil {
+		t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	html := string(data)
+
+	// Bead data is embedded as JSON (which escapes <), and the page title and
+	// project name are HTML-escaped, so the payload never appears as markup
+	if strings.Contains(html, payload) {
+		t.Error("bead or title content appears unescaped in the exported HTML")
+	}
+	if !strings.Contains(html, "<title>&lt;img src=x onerror=alert(1)&gt; | bv Graph</title>") {
+		t.Error("expected the page title to be HTML-escaped")
+	}
+
+	// Search results are built with innerHTML from every bead field; render
+	// a row for a bead whose fields are all markup and check none survives
+	var row struct {
+		Escaped string `json:"escaped"`
+		Snippet string `json:"snippet"`
+		Commit  string `json:"commit"`
+	}
+	runViewerJS(t, html, []string{
+		"function escapeHtml", "const escapeAttr", "function searchSnippet", "function searchPreview",
+		"const SEARCH_ROW_HEIGHT", "const SEARCH_ROW_HEIGHT_PLAIN", "function layoutSearchRows", "function searchResultRow",
+	}, `
+const p = `+jsLiteral(t, payload)+`;
+const node = { id: p, status: p, title: p, description: 'xx ' + p + ' img yy', commits: [{ short_sha: p, message: 'fix img ' + p }] };
+let searchResults = { q: 'img', ranked: [{ node: node }], shown: 1, previews: [], tops: [0] };
+layoutSearchRows();
+out({
+    escaped: escapeHtml('<a href="x" title=\'y\'>&</a>'),
+    snippet: searchResultRow(searchResults.ranked[0], 0),
+    commit: searchPreview({ node: { commits: node.commits } }, 'fix'),
+});
+`, &row)
+	if want := "&lt;a href=&quot;x&quot; title=&#39;y&#39;&gt;&amp;&lt;/a&gt;"; row.Escaped != want {
+		t.Errorf("escapeHtml = %q, want %q", row.Escaped, want)
+	}
+	for name, got := range map[string]string{"search row": row.Snippet, "commit preview": row.Commit} {
+		if strings.Contains(got, "<img") {
+			t.Errorf("%s contains unescaped bead markup: %s", name, got)
+		}
+		if !strings.Contains(got, "&lt;img src=x onerror=alert(1)&gt;") {
+			t.Errorf("%s lost the escaped bead text: %s", name, got)
+		}
+	}
+	if !strings.Contains(row.Snippet, "<mark>img</mark>") {
+		t.Errorf("search row should still mark the match: %s", row.Snippet)
+	}
+}
+
+func TestGenerateInteractiveGraphHTML_SanitizesMarkdown(t *testing.T) {
+	issues := interactiveTestIssues()
+	issues[0].Description = "Some `code` and a [link](https://example.com)\n\n<script>alert(1)</script>"
+	render := func(noSanitize bool) string {
+		path, err := GenerateInteractiveGraphHTML(InteractiveGraphOptions{
+			Issues:     issues,
+			NoSanitize: noSanitize,
+			Path:       filepath.Join(t.TempDir(), "graph.html"),
+		})
+		if err != nil {
+			t.Fatalf("GenerateInteractiveGraphHTML: %v", err)
+		}
+		data, _ := os.ReadFile(path)
+		return string(data)
+	}
+	html := render(false)
+
+	// The description stays inert JSON data until renderMarkdown runs it
+	// through marked and then DOMPurify, which strips the <script>
+	if strings.Contains(html, "<script>alert(1)</script>") {
+		t.Error("description <script> appears as markup in the exported HTML")
+	}
+	for _, want := range []string{
+		`\u003cscript\u003ealert(1)\u003c/script\u003e`,
+		"DOMPurify 3.0.6",
+		"const EXPORT_SANITIZE = true;",
+		"return DOMPurify.sanitize(html);",
+		"innerHTML = renderMarkdown(node.description);",
+		"innerHTML = renderMarkdown(node.notes);",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected output to contain %q", want)
+		}
+	}
+	if strings.Contains(html, "innerHTML = marked.parse(") {
+		t.Error("markdown must not be assigned to innerHTML without renderMarkdown")
+	}
+
+	if !strings.Contains(render(true), "const EXPORT_SANITIZE = false;") {
+		t.Error("expected NoSanitize to turn sanitization off")
+	}
+}
+
+func TestGraphOptionLabel(t *testing.T) {
+	for value, want := range map[string]string{
+		"in_review": "In Review",
+		"über-task": "Über Task",
+		"blocked":   "Blocked",
+	} {
+		if got := graphOptionLabel(value); got != want {
+			t.Errorf("graphOptionLabel(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
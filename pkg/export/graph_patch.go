@@ -0,0 +1,144 @@
+package export
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+)
+
+// GraphPatch turns the viewer's DATA for one load of the beads into the DATA
+// for the next, so a live viewer updates in place instead of reloading.
+// Patches are numbered; a viewer that sees a gap in Seq, or whose data_hash
+// is not BaseHash, has missed one and reloads the page instead.
+type GraphPatch struct {
+	Seq      int64        `json:"seq"`
+	BaseHash string       `json:"base_hash"`
+	DataHash string       `json:"data_hash"`
+	Nodes    nodeChanges  `json:"nodes"`
+	Links    linkChanges  `json:"links"`
+	Summary  graphSummary `json:"summary"`
+	// Triage replaces DATA.triage whole when the page was built with one
+	Triage *analysis.TriageResult `json:"triage,omitempty"`
+}
+
+// nodeChanges carries added and changed nodes whole; removed ones by ID.
+type nodeChanges struct {
+	Added   []graphNode `json:"added,omitempty"`
+	Changed []graphNode `json:"changed,omitempty"`
+	Removed []string    `json:"removed,omitempty"`
+}
+
+// linkChanges identifies links by source, target and dependency type.
+type linkChanges struct {
+	Added   []graphLink    `json:"added,omitempty"`
+	Changed []graphLink    `json:"changed,omitempty"`
+	Removed []graphLinkRef `json:"removed,omitempty"`
+}
+
+type graphLinkRef struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Type   string `json:"type"`
+}
+
+// LiveGraph is the viewer data for one load of the beads. A server keeping
+// viewers open across edits (bv serve's /graph) holds the latest LiveGraph,
+// renders pages from it, and diffs each reload against it into a GraphPatch.
+type LiveGraph struct {
+	seq   int64
+	graph *interactiveGraph
+}
+
+// NewLiveGraph builds the viewer data for opts, numbered opts.PatchSeq. Set
+// opts.LiveEventsURL so rendered pages subscribe for patches.
+func NewLiveGraph(opts InteractiveGraphOptions) (*LiveGraph, error) {
+	g, err := buildInteractiveGraph(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &LiveGraph{seq: opts.PatchSeq, graph: g}, nil
+}
+
+// Seq is the sequence number of the patch that produced this data.
+func (lg *LiveGraph) Seq() int64 { return lg.seq }
+
+// DataHash is the hash of the beads this data was built from.
+func (lg *LiveGraph) DataHash() string { return lg.graph.dataHash }
+
+// HTML renders the self-contained viewer page for this data.
+func (lg *LiveGraph) HTML() string {
+	return lg.graph.render(string(lg.graph.dataJSON), forceGraphJS, markedJS, dompurifyJS)
+}
+
+// PatchTo returns the patch that turns lg's data into next's. It is
+// numbered next.Seq(), which should be lg.Seq()+1.
+func (lg *LiveGraph) PatchTo(next *LiveGraph) GraphPatch {
+	p := GraphPatch{
+		Seq:      next.seq,
+		BaseHash: lg.graph.dataHash,
+		DataHash: next.graph.dataHash,
+		Summary:  next.graph.summary,
+		Triage:   next.graph.triage,
+	}
+
+	// Same approach as the serve update events: compare each item's JSON
+	oldNodes := make(map[string]string, len(lg.graph.nodes))
+	for _, n := range lg.graph.nodes {
+		oldNodes[n.ID] = encodePatchItem(n)
+	}
+	seen := make(map[string]bool, len(next.graph.nodes))
+	for _, n := range next.graph.nodes {
+		seen[n.ID] = true
+		if old, ok := oldNodes[n.ID]; !ok {
+			p.Nodes.Added = append(p.Nodes.Added, n)
+		} else if old != encodePatchItem(n) {
+			p.Nodes.Changed = append(p.Nodes.Changed, n)
+		}
+	}
+	for _, n := range lg.graph.nodes {
+		if !seen[n.ID] {
+			p.Nodes.Removed = append(p.Nodes.Removed, n.ID)
+		}
+	}
+
+	oldLinks := make(map[graphLinkRef]string, len(lg.graph.links))
+	for _, l := range lg.graph.links {
+		oldLinks[linkRef(l)] = encodePatchItem(l)
+	}
+	seenLinks := make(map[graphLinkRef]bool, len(next.graph.links))
+	for _, l := range next.graph.links {
+		ref := linkRef(l)
+		seenLinks[ref] = true
+		if old, ok := oldLinks[ref]; !ok {
+			p.Links.Added = append(p.Links.Added, l)
+		} else if old != encodePatchItem(l) {
+			p.Links.Changed = append(p.Links.Changed, l)
+		}
+	}
+	for _, l := range lg.graph.links {
+		if ref := linkRef(l); !seenLinks[ref] {
+			p.Links.Removed = append(p.Links.Removed, ref)
+		}
+	}
+	sort.Slice(p.Links.Removed, func(i, j int) bool {
+		a, b := p.Links.Removed[i], p.Links.Removed[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Target != b.Target {
+			return a.Target < b.Target
+		}
+		return a.Type < b.Type
+	})
+	return p
+}
+
+func linkRef(l graphLink) graphLinkRef {
+	return graphLinkRef{Source: l.Source, Target: l.Target, Type: l.Type}
+}
+
+func encodePatchItem(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,158 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+)
+
+// GraphPayloadStats reports the size of the DATA payload embedded in an
+// interactive graph export.
+type GraphPayloadStats struct {
+	RawBytes        int  // Uncompressed JSON size
+	EmbeddedBytes   int  // Bytes actually embedded (base64 of the gzip stream when compressed)
+	Compressed      bool // Payload was gzipped
+	SavedPercentage float64
+}
+
+// gzipDataLoaderJS evaluates to the graph data decoded from a base64 gzip
+// stream. It carries its own small DEFLATE decoder (after zlib's puff.c)
+// instead of DecompressionStream so that DATA stays synchronous and the page
+// still works from file:// in browsers without the Compression Streams API.
+// The %s slot receives the base64 payload.
+const gzipDataLoaderJS = `(function (b64) {
+    const bin = atob(b64), bytes = new Uint8Array(bin.length);
+    for (let i = 0; i < bin.length; i++) bytes[i] = bin.charCodeAt(i);
+    if (bytes[0] !== 0x1f || bytes[1] !== 0x8b || bytes[2] !== 8) throw new Error('graph data is not gzip');
+    const flags = bytes[3];
+    let pos = 10;
+    if (flags & 4) pos += 2 + (bytes[pos] | (bytes[pos + 1] << 8));
+    if (flags & 8) while (bytes[pos++] !== 0) {}
+    if (flags & 16) while (bytes[pos++] !== 0) {}
+    if (flags & 2) pos += 2;
+
+    let bitBuf = 0, bitCnt = 0, out = new Uint8Array(Math.max(1024, bytes.length * 6)), outLen = 0;
+    const ensure = n => {
+        if (outLen + n <= out.length) return;
+        let size = out.length * 2;
+        while (size < outLen + n) size *= 2;
+        const next = new Uint8Array(size);
+        next.set(out.subarray(0, outLen));
+        out = next;
+    };
+    const bits = n => {
+        while (bitCnt < n) { bitBuf |= bytes[pos++] << bitCnt; bitCnt += 8; }
+        const v = bitBuf & ((1 << n) - 1);
+        bitBuf >>>= n; bitCnt -= n;
+        return v;
+    };
+    const build = lengths => {
+        const counts = new Uint16Array(16), offs = new Uint16Array(16), symbols = new Uint16Array(lengths.length);
+        for (let s = 0; s < lengths.length; s++) counts[lengths[s]]++;
+        counts[0] = 0;
+        for (let i = 1; i < 16; i++) offs[i] = offs[i - 1] + counts[i - 1];
+        for (let s = 0; s < lengths.length; s++) if (lengths[s]) symbols[offs[lengths[s]]++] = s;
+        return { counts, symbols };
+    };
+    const decode = h => {
+        let code = 0, first = 0, index = 0;
+        for (let len = 1; len < 16; len++) {
+            code |= bits(1);
+            const count = h.counts[len];
+            if (code - first < count) return h.symbols[index + code - first];
+            index += count; first = (first + count) << 1; code <<= 1;
+        }
+        throw new Error('corrupt graph data');
+    };
+
+    const LBASE = [3, 4, 5, 6, 7, 8, 9, 10, 11, 13, 15, 17, 19, 23, 27, 31, 35, 43, 51, 59, 67, 83, 99, 115, 131, 163, 195, 227, 258];
+    const LEXT = [0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1, 1, 2, 2, 2, 2, 3, 3, 3, 3, 4, 4, 4, 4, 5, 5, 5, 5, 0];
+    const DBASE = [1, 2, 3, 4, 5, 7, 9, 13, 17, 25, 33, 49, 65, 97, 129, 193, 257, 385, 513, 769, 1025, 1537, 2049, 3073, 4097, 6145, 8193, 12289, 16385, 24577];
+    const DEXT = [0, 0, 0, 0, 1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6, 7, 7, 8, 8, 9, 9, 10, 10, 11, 11, 12, 12, 13, 13];
+    const ORDER = [16, 17, 18, 0, 8, 7, 9, 6, 10, 5, 11, 4, 12, 3, 13, 2, 14, 1, 15];
+
+    let last;
+    do {
+        last = bits(1);
+        const type = bits(2);
+        if (type === 0) {
+            bitBuf = 0; bitCnt = 0; // Stored block: skip to the byte boundary
+            const len = bytes[pos] | (bytes[pos + 1] << 8);
+            pos += 4;
+            ensure(len);
+            out.set(bytes.subarray(pos, pos + len), outLen);
+            outLen += len; pos += len;
+            continue;
+        }
+        let lit, dist;
+        if (type === 1) {
+            const lengths = new Uint8Array(288);
+            lengths.fill(8, 0, 144); lengths.fill(9, 144, 256); lengths.fill(7, 256, 280); lengths.fill(8, 280, 288);
+            lit = build(lengths);
+            dist = build(new Uint8Array(30).fill(5));
+        } else if (type === 2) {
+            const hlit = bits(5) + 257, hdist = bits(5) + 1, hclen = bits(4) + 4;
+            const clens = new Uint8Array(19);
+            for (let i = 0; i < hclen; i++) clens[ORDER[i]] = bits(3);
+            const clen = build(clens);
+            const lengths = new Uint8Array(hlit + hdist);
+            for (let i = 0; i < hlit + hdist;) {
+                const sym = decode(clen);
+                if (sym < 16) { lengths[i++] = sym; continue; }
+                let rep = 0, val = 0;
+                if (sym === 16) { val = lengths[i - 1]; rep = 3 + bits(2); }
+                else if (sym === 17) rep = 3 + bits(3);
+                else rep = 11 + bits(7);
+                while (rep-- > 0) lengths[i++] = val;
+            }
+            lit = build(lengths.subarray(0, hlit));
+            dist = build(lengths.subarray(hlit));
+        } else {
+            throw new Error('corrupt graph data');
+        }
+        for (;;) {
+            let sym = decode(lit);
+            if (sym < 256) { ensure(1); out[outLen++] = sym; continue; }
+            if (sym === 256) break;
+            sym -= 257;
+            const len = LBASE[sym] + bits(LEXT[sym]);
+            const ds = decode(dist);
+            const d = DBASE[ds] + bits(DEXT[ds]);
+            ensure(len);
+            for (let i = 0; i < len; i++, outLen++) out[outLen] = out[outLen - d];
+        }
+    } while (!last);
+
+    return JSON.parse(new TextDecoder().decode(out.subarray(0, outLen)));
+})("%s")`
+
+// embedGraphPayload returns the JS expression assigned to DATA in the viewer:
+// the JSON itself, or (when compress is set) a gzip+base64 payload wrapped in
+// gzipDataLoaderJS.
+func embedGraphPayload(dataJSON []byte, compress bool) (string, GraphPayloadStats, error) {
+	stats := GraphPayloadStats{RawBytes: len(dataJSON), EmbeddedBytes: len(dataJSON)}
+	if !compress {
+		return string(dataJSON), stats, nil
+	}
+
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return "", stats, fmt.Errorf("gzip graph data: %w", err)
+	}
+	if _, err := zw.Write(dataJSON); err != nil {
+		return "", stats, fmt.Errorf("gzip graph data: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return "", stats, fmt.Errorf("gzip graph data: %w", err)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(buf.Bytes())
+	stats.EmbeddedBytes = len(encoded)
+	stats.Compressed = true
+	if stats.RawBytes > 0 {
+		stats.SavedPercentage = 100 * float64(stats.RawBytes-stats.EmbeddedBytes) / float64(stats.RawBytes)
+	}
+	return fmt.Sprintf(gzipDataLoaderJS, encoded), stats, nil
+}
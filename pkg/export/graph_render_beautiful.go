@@ -5,8 +5,12 @@ import (
 	"time"
 )
 
-// generateUltimateHTML creates the enhanced HTML visualization with all features
-func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edgeCount int, projectName, forceGraphLib, markedLib string) string {
+// generateUltimateHTML creates the enhanced HTML visualization with all features.
+// animations sets the default for link particles and CSS motion; viewers can
+// still toggle it, and prefers-reduced-motion turns it off by default. theme is
+// the default color scheme ("light", "dark" or "auto"); a theme the viewer
+// picked with the toggle is remembered and takes precedence.
+func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edgeCount int, projectName, forceGraphLib, markedLib, purifyLib string, animations, sanitize bool, theme, palette, statusOptions, typeOptions, edgeTypeOptions, statusLegend, typeLegend, edgeLegend, typeStylesJSON, edgeStylesJSON, criticalColor, articulationColor, linkTemplatesJSON string) string {
 	timestamp := time.Now().Format("2006-01-02 15:04:05")
 	return fmt.Sprintf(`<!DOCTYPE html>
 <html lang="en">
@@ -52,10 +56,24 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
             --fg-dim: #8888aa;
             --shadow: 0 8px 32px rgba(0,0,0,0.1);
         }
+        /* Colorblind-safe palette (Okabe-Ito blue/orange, viridis heatmap) */
+        body.palette-cb {
+            --green: #56b4e9;
+            --orange: #e69f00;
+            --red: #d55e00;
+            --yellow: #f0e442;
+        }
+        body.palette-cb .heatmap-gradient {
+            background: linear-gradient(90deg, #440154, #3b528b, #21918c, #5ec962, #fde725);
+        }
         body.light-mode #graph-container {
             background: radial-gradient(ellipse at center, #ffffff 0%%, #f0f2f5 100%%);
         }
         * { box-sizing: border-box; margin: 0; padding: 0; }
+        /* Animations disabled (toggle, --no-animation, or prefers-reduced-motion) */
+        body.no-animation *, body.no-animation *::before, body.no-animation *::after {
+            animation: none !important; transition: none !important;
+        }
         body {
             font-family: 'Inter', -apple-system, BlinkMacSystemFont, sans-serif;
             background: var(--bg);
@@ -132,6 +150,16 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
             padding-right: 2rem;
         }
         select:focus { outline: none; border-color: var(--purple); box-shadow: 0 0 0 3px var(--purple-glow); }
+        #filter-label {
+            font-family: inherit; font-size: 0.8rem; width: 150px;
+            padding: 0.5rem 0.875rem; border-radius: 8px;
+            background: var(--bg); color: var(--fg); border: 1px solid var(--bg-elevated);
+        }
+        #filter-label:focus { outline: none; border-color: var(--purple); box-shadow: 0 0 0 3px var(--purple-glow); }
+        #filter-label.invalid { border-color: var(--red); box-shadow: 0 0 0 3px rgba(239, 68, 68, 0.25); }
+        .depth-control { display: flex; align-items: center; gap: 0.375rem; padding: 0 0.5rem; font-size: 0.8rem; color: var(--fg-muted); }
+        .depth-control input { width: 72px; accent-color: var(--gold); cursor: pointer; }
+        .depth-control span { font-family: 'JetBrains Mono', monospace; color: var(--gold); min-width: 0.75rem; }
 
         /* Search */
         .search-container { position: relative; }
@@ -180,6 +208,23 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
             max-height: 60px; overflow: hidden;
         }
         .search-result-preview p { margin: 0.25rem 0; }
+        .search-result-sha { font-family: 'JetBrains Mono', monospace; color: var(--purple); }
+        .search-results-count {
+            position: sticky; top: 0; z-index: 1;
+            padding: 0.5rem 1rem; font-size: 0.75rem; color: var(--fg-muted);
+            background: var(--bg-secondary); border-bottom: 1px solid var(--bg-elevated);
+        }
+        .search-results-window { position: relative; }
+        .search-results-window .search-result-item {
+            position: absolute; left: 0; right: 0;
+            box-sizing: border-box; overflow: hidden;
+        }
+        .search-results-window .search-result-title { white-space: nowrap; overflow: hidden; text-overflow: ellipsis; }
+        .search-more {
+            display: block; width: 100%%; padding: 0.625rem; border: none;
+            background: transparent; color: var(--cyan); font-size: 0.8rem; cursor: pointer;
+        }
+        .search-more:hover { background: var(--bg-elevated); }
 
         /* Main */
         main { flex: 1; display: flex; overflow: hidden; position: relative; }
@@ -295,12 +340,18 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
             cursor: pointer; transition: all 0.15s ease;
         }
         .hover-dep-chip:hover { background: var(--purple); color: white; }
+        .why-row { display: flex; flex-wrap: wrap; align-items: center; gap: 0.375rem; font-size: 0.75rem; margin-bottom: 0.375rem; }
+        .why-status { color: var(--fg-muted); }
+        .why-actionable { color: var(--green); }
+        .why-blocked { color: var(--orange); }
+        .why-cycle { color: var(--red); font-weight: 600; }
         .hover-commits { max-height: 150px; overflow-y: auto; }
         .hover-commit {
             padding: 0.5rem; background: var(--bg); border-radius: 6px;
             margin-bottom: 0.375rem; font-size: 0.75rem;
         }
         .hover-commit-sha { font-family: 'JetBrains Mono', monospace; color: var(--cyan); }
+        a.hover-commit-sha, .hover-id a { color: inherit; text-decoration: underline dotted; }
         .hover-commit-msg { margin-top: 0.25rem; color: var(--fg-muted); }
         .hover-close {
             position: absolute; top: 0.75rem; right: 0.75rem;
@@ -350,6 +401,12 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
             text-transform: uppercase; letter-spacing: 0.1em; margin-bottom: 0.75rem;
             display: flex; align-items: center; gap: 0.5rem;
         }
+        .triage-copy {
+            margin-top: 0.5rem; width: 100%%; padding: 0.375rem; font-size: 0.75rem;
+            background: var(--bg-elevated); color: var(--fg-muted); border: 1px solid var(--bg-tertiary);
+            border-radius: 6px; cursor: pointer;
+        }
+        .triage-copy:hover { color: var(--fg); border-color: var(--purple); }
         .panel-title::before {
             content: ''; width: 4px; height: 14px;
             background: linear-gradient(180deg, var(--purple), var(--pink));
@@ -367,6 +424,10 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
             width: 12px; height: 12px; border-radius: 50%%;
             box-shadow: 0 0 8px currentColor;
         }
+        .edge-legend-item { cursor: pointer; border: 1px solid transparent; }
+        .edge-legend-item:hover { border-color: var(--purple); }
+        .edge-legend-item.active { border-color: var(--gold); color: var(--fg); }
+        .edge-sample { font-family: 'JetBrains Mono', monospace; letter-spacing: -1px; }
 
         /* Triage Panel */
         .triage-item {
@@ -390,6 +451,7 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
         .badge-in_progress { background: var(--orange); color: var(--bg); }
         .badge-blocked { background: var(--red); color: white; }
         .badge-closed { background: var(--fg-dim); color: var(--bg); }
+        .badge-status-other { background: #94a3b8; color: var(--bg); }
         .badge-type { background: var(--purple); color: white; }
         .badge-feature { background: linear-gradient(135deg, var(--purple), var(--pink)); color: white; }
         .badge-bug { background: var(--red); color: white; }
@@ -397,6 +459,9 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
         .badge-epic { background: linear-gradient(135deg, var(--gold), var(--orange)); color: var(--bg); }
         .badge-articulation { background: linear-gradient(135deg, var(--pink), var(--purple)); color: white; animation: pulse 2s infinite; }
         .badge-critical { background: linear-gradient(135deg, var(--red), var(--orange)); color: white; }
+        .ac-progress { display: flex; align-items: center; gap: 0.5rem; margin-bottom: 0.5rem; font-size: 0.75rem; color: var(--fg-muted); }
+        .ac-progress-track { flex: 1; height: 6px; background: var(--bg-elevated); border-radius: 3px; overflow: hidden; }
+        .ac-progress-fill { height: 100%%; background: var(--green); border-radius: 3px; }
         @keyframes pulse { 0%%, 100%% { opacity: 1; } 50%% { opacity: 0.7; } }
 
         /* Node Detail */
@@ -418,7 +483,37 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
         .metric-label { color: var(--fg-muted); }
         .metric-value { color: var(--fg); font-weight: 500; font-family: 'JetBrains Mono', monospace; }
         .metric-value.highlight { color: var(--green); }
+        .metric-info {
+            background: none; border: none; padding: 0; cursor: pointer;
+            color: var(--fg-dim); font-size: 0.7rem;
+        }
+        .metric-info:hover { color: var(--cyan); }
+        .metric-popover {
+            position: fixed; max-width: 280px; padding: 0.75rem 1rem;
+            background: var(--bg-glass); backdrop-filter: blur(20px);
+            border: 1px solid var(--purple); border-radius: var(--radius);
+            font-size: 0.75rem; line-height: 1.5; z-index: 1000;
+            box-shadow: var(--shadow); display: none;
+        }
+        .metric-popover.visible { display: block; }
+        .metric-popover-title { font-weight: 600; color: var(--cyan); margin-bottom: 0.25rem; }
+        .metric-percentile { margin-top: 0.375rem; color: var(--green); }
+        .metrics-explain { font-size: 0.75rem; color: var(--fg-muted); line-height: 1.5; }
+        .metrics-explain-item { padding: 0.375rem 0; border-bottom: 1px solid var(--bg-elevated); }
+        .metrics-explain-item:last-child { border-bottom: none; }
+        .metrics-explain-name { color: var(--fg); font-weight: 600; }
         .no-selection { text-align: center; padding: 2rem 1rem; color: var(--fg-muted); font-size: 0.8rem; }
+        .nav-breadcrumbs { display: none; align-items: center; gap: 0.25rem; flex-wrap: wrap; margin-bottom: 0.625rem; font-size: 0.7rem; }
+        .nav-breadcrumbs.visible { display: flex; }
+        .nav-btn {
+            background: var(--bg-elevated); border: 1px solid var(--fg-dim); color: var(--fg-muted);
+            border-radius: 4px; padding: 0 0.375rem; cursor: pointer; font-size: 0.7rem;
+        }
+        .nav-btn:disabled { opacity: 0.35; cursor: default; }
+        .nav-crumb { font-family: 'JetBrains Mono', monospace; color: var(--fg-muted); cursor: pointer; }
+        .nav-crumb:hover { color: var(--cyan); }
+        .nav-crumb.current { color: var(--cyan); font-weight: 600; cursor: default; }
+        .nav-sep { color: var(--fg-dim); }
         .no-selection-icon { font-size: 2rem; margin-bottom: 0.625rem; opacity: 0.4; }
 
         /* Shortcuts */
@@ -504,6 +599,19 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
             transition: all 0.2s ease;
         }
         .heatmap-legend.heatmap-active { border-color: var(--gold); box-shadow: 0 0 20px var(--gold-glow); }
+
+        /* Comparison Legend */
+        .compare-legend {
+            position: absolute; bottom: 1rem; left: 1rem;
+            background: var(--bg-glass); backdrop-filter: blur(15px);
+            border: 1px solid var(--fg-dim); border-radius: var(--radius);
+            padding: 0.625rem 0.75rem; z-index: 10; font-size: 0.75rem;
+            display: none; align-items: center; gap: 0.75rem;
+        }
+        .compare-legend.visible { display: flex; }
+        .compare-swatch { display: inline-block; width: 10px; height: 10px; border-radius: 50%%; margin-right: 0.25rem; vertical-align: middle; }
+        .compare-clear { background: none; border: none; color: var(--fg-muted); cursor: pointer; font-size: 0.9rem; }
+        .compare-clear:hover { color: var(--fg); }
         .heatmap-gradient {
             width: 140px; height: 14px;
             background: linear-gradient(90deg, var(--green), var(--yellow), var(--orange), var(--red));
@@ -613,18 +721,13 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
             </div>
             <div class="toolbar-group">
                 <select id="filter-status" title="Filter nodes by status. Shows only beads matching the selected status.">
-                    <option value="">All Status</option>
-                    <option value="open">Open</option>
-                    <option value="in_progress">In Progress</option>
-                    <option value="blocked">Blocked</option>
-                    <option value="closed">Closed</option>
+                    <option value="">All Status</option>%s
                 </select>
                 <select id="filter-type" title="Filter nodes by type. Shows only beads matching the selected type.">
-                    <option value="">All Types</option>
-                    <option value="feature">Feature</option>
-                    <option value="bug">Bug</option>
-                    <option value="task">Task</option>
-                    <option value="epic">Epic</option>
+                    <option value="">All Types</option>%s
+                </select>
+                <select id="filter-edge-type" title="Filter edges by dependency type. Shows only edges of the selected type.">
+                    <option value="">All Edges</option>%s
                 </select>
             </div>
             <div class="toolbar-group">
@@ -633,20 +736,39 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
                     <option value="betweenness">Size: Betweenness</option>
                     <option value="critical">Size: Critical Path</option>
                     <option value="indegree">Size: In-Degree</option>
+                    <option value="staleness">Size: Staleness</option>
+                    <option value="due">Size: Due Urgency</option>
+                    <option value="impact">Size: Unblock Impact</option>
+                    <option value="health">Size: Health Risk</option>
+                </select>
+                <select id="color-by" title="Color nodes by status, or by assignee to see who owns what. The heatmap overrides both.">
+                    <option value="status">Color: Status</option>
+                    <option value="assignee">Color: Assignee</option>
                 </select>
+                <label class="depth-control" title="How many dependency hops the hover highlight reaches ([ / ])">Depth
+                    <input type="range" id="highlight-depth" min="1" max="5" step="1" value="2"><span id="highlight-depth-value">2</span>
+                </label>
             </div>
             <div class="toolbar-group">
-                <select id="filter-priority" title="Filter nodes by priority level (P0=critical, P4=backlog)">
-                    <option value="">All Priorities</option>
-                    <option value="0">P0 Critical</option>
-                    <option value="1">P1 High</option>
-                    <option value="2">P2 Medium</option>
-                    <option value="3">P3 Low</option>
-                    <option value="4">P4 Backlog</option>
-                </select>
-                <select id="filter-label" title="Filter nodes by label">
-                    <option value="">All Labels</option>
-                </select>
+                <label class="depth-control" title="Show only beads whose priority is within this range (P0=critical, P4=backlog)">Priority
+                    <select id="filter-priority-min">
+                        <option value="">Any</option>
+                        <option value="0">P0</option>
+                        <option value="1">P1</option>
+                        <option value="2">P2</option>
+                        <option value="3">P3</option>
+                        <option value="4">P4</option>
+                    </select>–<select id="filter-priority-max">
+                        <option value="">Any</option>
+                        <option value="0">P0</option>
+                        <option value="1">P1</option>
+                        <option value="2">P2</option>
+                        <option value="3">P3</option>
+                        <option value="4">P4</option>
+                    </select>
+                </label>
+                <input type="text" id="filter-label" list="filter-label-options" placeholder="Label, team/*, /regex/" autocomplete="off" title="Filter nodes by label: an exact name, a glob (team/*), or a /regex/">
+                <datalist id="filter-label-options"></datalist>
             </div>
             <div class="toolbar-group">
                 <button id="btn-heatmap" title="Toggle heatmap coloring - shows node importance by color intensity (H)">🔥</button>
@@ -654,7 +776,14 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
                 <button id="btn-top" title="Show/hide top nodes panel with highest PageRank nodes (T)">⭐</button>
                 <button id="btn-recent" title="Show/hide recently viewed nodes (Y)">🕐</button>
                 <button id="btn-path" title="Enter path finder mode - click two nodes to find shortest path (P)">🛤️</button>
+                <button id="btn-blast" title="Blast radius - click a bead to shade everything that depends on it by distance; press again for its prerequisites (B)">💥</button>
+                <button id="btn-levels" title="Label nodes with their topological level in DAG modes (V)">🪜</button>
+                <button id="btn-bundle" title="Bundle edges between clusters into one curved trunk per cluster pair (U)">🪢</button>
+                <button id="btn-lock" title="Lock camera to selection - keep the selected bead centered while the layout settles (K)">🎯</button>
+                <button id="btn-mermaid" title="Copy the highlighted nodes and their edges as a Mermaid diagram (M)">🧜</button>
                 <button id="btn-theme" title="Switch to light mode (L)">☀️</button>
+                <button id="btn-motion" title="Disable animations (A)">✨</button>
+                <button id="btn-palette" title="Use colorblind-safe palette (C)">👁️</button>
                 <button id="btn-help" title="Show keyboard shortcuts and help (?)">❓</button>
                 <button id="btn-fit" title="Fit all nodes in view (F)">Fit</button>
                 <button id="btn-reset" title="Reset graph to initial state with all filters cleared (R)">Reset</button>
@@ -690,6 +819,10 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
                 </div>
                 <div id="docked-acceptance" class="hover-section" style="display:none;">
                     <div class="hover-section-title">Acceptance Criteria</div>
+                    <div class="ac-progress" id="docked-acceptance-progress" style="display:none;">
+                        <div class="ac-progress-track"><div class="ac-progress-fill"></div></div>
+                        <span class="ac-progress-label"></span>
+                    </div>
                     <div class="hover-content" id="docked-acceptance-content"></div>
                 </div>
                 <div id="docked-notes" class="hover-section" style="display:none;">
@@ -704,6 +837,10 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
                     <div class="hover-section-title">Blocked By</div>
                     <div class="hover-deps" id="docked-blocked-by-list"></div>
                 </div>
+                <div id="docked-why-blocked" class="hover-section" style="display:none;" title="For each open blocker: can it be worked on now, and if not, which beads up its chain to finish first">
+                    <div class="hover-section-title">Why Blocked</div>
+                    <div id="docked-why-blocked-list"></div>
+                </div>
                 <div id="docked-blocks" class="hover-section" style="display:none;">
                     <div class="hover-section-title">Blocks</div>
                     <div class="hover-deps" id="docked-blocks-list"></div>
@@ -731,6 +868,12 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
                 <div class="heatmap-gradient"></div>
                 <div class="heatmap-labels"><span>Low</span><span id="heatmap-metric">PageRank</span><span>High</span></div>
             </div>
+            <div class="compare-legend" id="compare-legend">
+                <span><span class="compare-swatch" style="background:#22d3ee"></span>A <b id="compare-count-a">0</b></span>
+                <span><span class="compare-swatch" style="background:#ec4899"></span>B <b id="compare-count-b">0</b></span>
+                <span><span class="compare-swatch" style="background:#fbbf24"></span>Overlap <b id="compare-count-both">0</b></span>
+                <button class="compare-clear" id="compare-clear" title="Clear comparison">×</button>
+            </div>
             <div class="minimap" id="minimap">
                 <canvas id="minimap-canvas"></canvas>
                 <div class="minimap-viewport" id="minimap-viewport"></div>
@@ -740,6 +883,7 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
                 <div id="recent-list"></div>
             </div>
             <div class="pathfinder-banner" id="pathfinder-banner">🔗 Path Finder: Click destination node (Esc to cancel)</div>
+            <div class="pathfinder-banner" id="blast-banner"></div>
             <div class="edge-tooltip" id="edge-tooltip"></div>
             <div id="hover-panel">
                 <button class="hover-close" id="hover-close">×</button>
@@ -759,6 +903,10 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
                 </div>
                 <div id="hover-acceptance" class="hover-section" style="display:none;">
                     <div class="hover-section-title">Acceptance Criteria</div>
+                    <div class="ac-progress" id="hover-acceptance-progress" style="display:none;">
+                        <div class="ac-progress-track"><div class="ac-progress-fill"></div></div>
+                        <span class="ac-progress-label"></span>
+                    </div>
                     <div class="hover-content" id="hover-acceptance-content"></div>
                 </div>
                 <div id="hover-notes" class="hover-section" style="display:none;">
@@ -773,6 +921,10 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
                     <div class="hover-section-title">Blocked By</div>
                     <div class="hover-deps" id="hover-blocked-by-list"></div>
                 </div>
+                <div id="hover-why-blocked" class="hover-section" style="display:none;" title="For each open blocker: can it be worked on now, and if not, which beads up its chain to finish first">
+                    <div class="hover-section-title">Why Blocked</div>
+                    <div id="hover-why-blocked-list"></div>
+                </div>
                 <div id="hover-blocks" class="hover-section" style="display:none;">
                     <div class="hover-section-title">Blocks</div>
                     <div class="hover-deps" id="hover-blocks-list"></div>
@@ -799,27 +951,34 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
             <div class="panel" id="triage-panel" style="display:none;">
                 <div class="panel-title">Top Recommendations</div>
                 <div id="triage-list"></div>
+                <button class="triage-copy" id="btn-copy-checklist" title="Copy recommendations as a Markdown checklist">📝 Copy as checklist</button>
             </div>
             <div class="panel">
                 <div class="panel-title">Status Legend</div>
-                <div class="legend">
-                    <div class="legend-item"><div class="legend-dot" style="background:#22c55e;color:#22c55e"></div>Open</div>
-                    <div class="legend-item"><div class="legend-dot" style="background:#f97316;color:#f97316"></div>In Progress</div>
-                    <div class="legend-item"><div class="legend-dot" style="background:#ef4444;color:#ef4444"></div>Blocked</div>
-                    <div class="legend-item"><div class="legend-dot" style="background:#555577;color:#555577"></div>Closed</div>
+                <div class="legend">%s
                 </div>
             </div>
+            <div class="panel" id="assignee-panel" style="display:none">
+                <div class="panel-title">Assignees</div>
+                <div class="legend" id="assignee-legend"></div>
+            </div>
             <div class="panel">
                 <div class="panel-title">Type Shapes</div>
-                <div class="legend">
-                    <div class="legend-item"><span style="font-size:1rem">●</span> Feature</div>
-                    <div class="legend-item"><span style="font-size:1rem">▲</span> Bug</div>
-                    <div class="legend-item"><span style="font-size:1rem">■</span> Task</div>
-                    <div class="legend-item"><span style="font-size:1rem">◆</span> Epic</div>
+                <div class="legend">%s
+                </div>
+            </div>
+            <div class="panel">
+                <div class="panel-title">Edge Types</div>
+                <div class="legend" id="edge-legend">%s
                 </div>
             </div>
             <div class="panel">
                 <div class="panel-title">Selected Node</div>
+                <div class="nav-breadcrumbs" id="nav-breadcrumbs">
+                    <button class="nav-btn" id="nav-back" title="Back (Alt+←)">◀</button>
+                    <button class="nav-btn" id="nav-forward" title="Forward (Alt+→)">▶</button>
+                    <span id="nav-trail"></span>
+                </div>
                 <div id="node-detail">
                     <div class="detail-header">
                         <div class="detail-id" id="detail-id">-</div>
@@ -828,14 +987,15 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
                     <div class="detail-name" id="detail-name">-</div>
                     <div class="detail-badges" id="detail-badges"></div>
                     <div class="detail-metrics">
-                        <div class="metric-item"><span class="metric-label">PageRank</span><span class="metric-value" id="m-pagerank">-</span></div>
-                        <div class="metric-item"><span class="metric-label">Rank</span><span class="metric-value" id="m-prrank">-</span></div>
-                        <div class="metric-item"><span class="metric-label">Betweenness</span><span class="metric-value" id="m-between">-</span></div>
-                        <div class="metric-item"><span class="metric-label">BW Rank</span><span class="metric-value" id="m-bwrank">-</span></div>
-                        <div class="metric-item"><span class="metric-label">Critical</span><span class="metric-value" id="m-critical">-</span></div>
-                        <div class="metric-item"><span class="metric-label">Slack</span><span class="metric-value" id="m-slack">-</span></div>
-                        <div class="metric-item"><span class="metric-label">In-Deg</span><span class="metric-value" id="m-indeg">-</span></div>
-                        <div class="metric-item"><span class="metric-label">Out-Deg</span><span class="metric-value" id="m-outdeg">-</span></div>
+                        <div class="metric-item"><span class="metric-label">PageRank <button class="metric-info" data-metric="pagerank" title="What is PageRank?">ⓘ</button></span><span class="metric-value" id="m-pagerank">-</span></div>
+                        <div class="metric-item"><span class="metric-label">Rank <button class="metric-info" data-metric="pagerank" title="What is Rank?">ⓘ</button></span><span class="metric-value" id="m-prrank">-</span></div>
+                        <div class="metric-item"><span class="metric-label">Betweenness <button class="metric-info" data-metric="betweenness" title="What is Betweenness?">ⓘ</button></span><span class="metric-value" id="m-between">-</span></div>
+                        <div class="metric-item"><span class="metric-label">BW Rank <button class="metric-info" data-metric="betweenness" title="What is BW Rank?">ⓘ</button></span><span class="metric-value" id="m-bwrank">-</span></div>
+                        <div class="metric-item"><span class="metric-label">Critical <button class="metric-info" data-metric="critical_path" title="What is Critical?">ⓘ</button></span><span class="metric-value" id="m-critical">-</span></div>
+                        <div class="metric-item"><span class="metric-label">Slack <button class="metric-info" data-metric="slack" title="What is Slack?">ⓘ</button></span><span class="metric-value" id="m-slack">-</span></div>
+                        <div class="metric-item"><span class="metric-label">In-Deg <button class="metric-info" data-metric="in_degree" title="What is In-Deg?">ⓘ</button></span><span class="metric-value" id="m-indeg">-</span></div>
+                        <div class="metric-item"><span class="metric-label">Out-Deg <button class="metric-info" data-metric="out_degree" title="What is Out-Deg?">ⓘ</button></span><span class="metric-value" id="m-outdeg">-</span></div>
+                        <div class="metric-item"><span class="metric-label">Level <button class="metric-info" data-metric="topo_level" title="What is Level?">ⓘ</button></span><span class="metric-value" id="m-level">-</span></div>
                     </div>
                 </div>
                 <div class="no-selection" id="no-selection">
@@ -844,30 +1004,42 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
                     <small>or hover for full info</small>
                 </div>
             </div>
+            <div class="panel">
+                <div class="panel-title">How Metrics Are Computed</div>
+                <div class="metrics-explain" id="metrics-explain"></div>
+            </div>
             <div class="panel">
                 <div class="panel-title">Shortcuts</div>
                 <div class="keyboard-hints">
                     <kbd>F</kbd> Fit · <kbd>R</kbd> Reset · <kbd>Space</kbd> Fullscreen<br>
                     <kbd>Esc</kbd> Clear · <kbd>1-4</kbd> View modes<br>
-                    <kbd>H</kbd> Heatmap · <kbd>T</kbd> Top · <kbd>G</kbd> Triage
+                    <kbd>Alt+←/→</kbd> Back/forward<br>
+                    <kbd>H</kbd> Heatmap · <kbd>T</kbd> Top · <kbd>G</kbd> Triage<br>
+                    <kbd>L</kbd> Light/dark · <kbd>C</kbd> Colorblind palette<br>
+                    <kbd>V</kbd> DAG level labels · <kbd>K</kbd> Camera lock<br>
+                    <kbd>U</kbd> Bundle edges
                 </div>
             </div>
         </div>
     </main>
     <footer>
-        <div>Generated %s | Hash: %s</div>
+        <div>Generated %s | Hash: %s<span id="footer-omitted"></span></div>
         <div>Project: %s | <a href="https://github.com/Dicklesworthstone/beads_viewer">bv</a></div>
     </footer>
     <div class="toast" id="toast"></div>
+    <div class="metric-popover" id="metric-popover"></div>
     <div class="context-menu" id="context-menu">
         <div class="context-menu-item" id="ctx-focus">🎯 Focus on this node</div>
         <div class="context-menu-item" id="ctx-details">📄 Show full details</div>
         <div class="context-menu-item" id="ctx-deps">📥 Show dependencies</div>
         <div class="context-menu-item" id="ctx-dependents">📤 Show dependents</div>
         <div class="context-menu-item" id="ctx-connected">✨ Highlight connected</div>
+        <div class="context-menu-item" id="ctx-compare">🆚 Add to comparison</div>
+        <div class="context-menu-item" id="ctx-collapse">📦 Collapse epic</div>
         <div class="context-menu-divider"></div>
         <div class="context-menu-item" id="ctx-path">🛤️ Find path to...</div>
         <div class="context-menu-item" id="ctx-copy">📋 Copy ID</div>
+        <div class="context-menu-item" id="ctx-mermaid">🧜 Copy subgraph as Mermaid</div>
     </div>
     <div class="help-overlay" id="help-overlay">
         <div class="help-content">
@@ -880,6 +1052,8 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
                     <div class="help-item"><span class="help-key">R</span> Reset to initial state</div>
                     <div class="help-item"><span class="help-key">Space</span> Toggle fullscreen</div>
                     <div class="help-item"><span class="help-key">Esc</span> Clear selection</div>
+                    <div class="help-item"><span class="help-key">Alt+←</span> Back to previous bead</div>
+                    <div class="help-item"><span class="help-key">Alt+→</span> Forward in selection history</div>
                 </div>
             </div>
             <div class="help-section">
@@ -887,11 +1061,17 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
                 <div class="help-grid">
                     <div class="help-item"><span class="help-key">D</span> Dock/detach detail panel</div>
                     <div class="help-item"><span class="help-key">L</span> Toggle light/dark mode</div>
+                    <div class="help-item"><span class="help-key">A</span> Toggle animations</div>
                     <div class="help-item"><span class="help-key">H</span> Toggle heatmap coloring</div>
                     <div class="help-item"><span class="help-key">T</span> Show top nodes panel</div>
                     <div class="help-item"><span class="help-key">G</span> Show triage panel</div>
                     <div class="help-item"><span class="help-key">Y</span> Show recently viewed</div>
                     <div class="help-item"><span class="help-key">P</span> Enter path finder mode</div>
+                    <div class="help-item"><span class="help-key">B</span> Blast radius: downstream, upstream, off</div>
+                    <div class="help-item"><span class="help-key">M</span> Copy highlighted subgraph as Mermaid</div>
+                    <div class="help-item"><span class="help-key">K</span> Lock camera to the selected bead while the layout settles</div>
+                    <div class="help-item"><span class="help-key">U</span> Bundle edges between clusters</div>
+                    <div class="help-item"><span class="help-key">[ ]</span> Decrease/increase highlight depth</div>
                     <div class="help-item"><span class="help-key">?</span> Show this help</div>
                 </div>
             </div>
@@ -918,38 +1098,157 @@ func generateUltimateHTML(title, dataHash, graphDataJSON string, nodeCount, edge
     </div>
     <script>%s</script>
     <script>%s</script>
+    <script>%s</script>
     <script>
 const DATA = %s;
-const STATUS_COLORS = { open: '#22c55e', in_progress: '#f97316', blocked: '#ef4444', closed: '#555577' };
-const PRIORITY_COLORS = ['#ef4444', '#f97316', '#eab308', '#22c55e', '#555577'];
-const TYPE_COLORS = { feature: '#a855f7', bug: '#ef4444', task: '#22d3ee', epic: '#fbbf24' };
-
-// Configure marked for safe HTML rendering
+const EXPORT_ANIMATIONS = %t;
+// Markdown fields go through DOMPurify unless exported with --no-sanitize
+const EXPORT_SANITIZE = %t;
+const EXPORT_THEME = '%s';
+const EXPORT_PALETTE = '%s';
+// Per-type shape/color (built-ins plus --type-config registrations); unknown types use FALLBACK_TYPE_STYLE
+const TYPE_STYLES = %s;
+const FALLBACK_TYPE_STYLE = { shape: 'hexagon', color: '#8888aa' };
+// Critical-path and articulation highlight colors (--critical-color / --articulation-color);
+// without an override, zero-slack nodes keep a halo in their own color
+const CRITICAL_COLOR_OVERRIDE = '%s';
+const CRITICAL_COLOR = CRITICAL_COLOR_OVERRIDE || '#ec4899';
+const ARTICULATION_COLOR = '%s' || '#ec4899';
+// External links for commits and bead IDs (--commit-url-template / --issue-url-template)
+const LINK_TEMPLATES = %s;
+function templateURL(tmpl, vars) {
+    if (!tmpl) return '';
+    return tmpl.replace(/\{(\w+)\}/g, (m, k) => k in vars ? encodeURIComponent(vars[k]) : m);
+}
+// Bead content (titles, IDs, labels, statuses, commit messages) is untrusted:
+// escape it before building HTML strings, or set it with textContent. Only
+// the markdown fields go through marked.parse.
+function escapeHtml(s) {
+    return String(s ?? '').replace(/&/g, '&amp;').replace(/</g, '&lt;').replace(/>/g, '&gt;').replace(/"/g, '&quot;').replace(/'/g, '&#39;');
+}
+const escapeAttr = escapeHtml;
+function typeStyle(t) { return TYPE_STYLES[t] || FALLBACK_TYPE_STYLE; }
+// Per-dependency-type edge styling: blocks solid with an arrow, parent with a
+// larger mid-edge arrowhead, related dashed without one; other types use FALLBACK_EDGE_STYLE
+const EDGE_STYLES = %s;
+const FALLBACK_EDGE_STYLE = { color: '#8888aa', dash: [6, 3], arrow: 4, arrow_pos: 1 };
+function edgeType(l) { return l.edge_type || 'blocks'; }
+function edgeStyle(l) { return EDGE_STYLES[edgeType(l)] || FALLBACK_EDGE_STYLE; }
+function edgeBaseColor(l) { const c = edgeStyle(l).color; return c ? c + '80' : themeLinkColor(); }
+// Statuses outside open/in_progress/blocked/closed (e.g. in_review) share a fallback color
+const STATUS_FALLBACK_COLOR = '#94a3b8';
+function statusColor(s) { return STATUS_COLORS[s] || STATUS_FALLBACK_COLOR; }
+function statusBadgeClass(s) { return STATUS_COLORS[s] ? 'badge-' + s : 'badge-status-other'; }
+// Palettes are swapped in place so every STATUS_COLORS/PRIORITY_COLORS lookup follows the active one
+const PALETTES = {
+    'default': {
+        status: { open: '#22c55e', in_progress: '#f97316', blocked: '#ef4444', closed: '#555577' },
+        priority: ['#ef4444', '#f97316', '#eab308', '#22c55e', '#555577'],
+        type: Object.fromEntries(Object.entries(TYPE_STYLES).map(([t, st]) => [t, st.color]))
+    },
+    'cb-safe': {
+        status: { open: '#56b4e9', in_progress: '#e69f00', blocked: '#d55e00', closed: '#555577' },
+        priority: ['#d55e00', '#e69f00', '#f0e442', '#56b4e9', '#555577'],
+        type: { feature: '#cc79a7', bug: '#d55e00', task: '#56b4e9', epic: '#f0e442' }
+    }
+};
+const STATUS_COLORS = Object.assign({}, PALETTES['default'].status);
+const PRIORITY_COLORS = PALETTES['default'].priority.slice();
+const TYPE_COLORS = Object.assign({}, PALETTES['default'].type);
+// Viridis stops for the colorblind-safe heatmap (low to high)
+const VIRIDIS = [[68, 1, 84], [59, 82, 139], [33, 145, 140], [94, 201, 98], [253, 231, 37]];
+let activePalette = 'default';
+
+// Configure marked for GitHub-flavored markdown; renderMarkdown makes it safe
 marked.setOptions({ breaks: true, gfm: true });
 
-// Stats calculation
+// Markdown fields (description, design, acceptance criteria, notes) may carry
+// raw HTML from untrusted beads. DOMPurify drops scripts, event handlers and
+// javascript: URLs while keeping code, links, lists and tables. If DOMPurify
+// failed to load, fall back to plain escaped text rather than raw HTML.
+function renderMarkdown(src) {
+    const html = marked.parse(src);
+    if (!EXPORT_SANITIZE) return html;
+    if (typeof DOMPurify === 'undefined' || !DOMPurify.sanitize) {
+        return '<p>' + escapeHtml(src).replace(/\n/g, '<br>') + '</p>';
+    }
+    return DOMPurify.sanitize(html);
+}
+
+// Stats calculation (recomputed when a live patch arrives)
 let actionable = 0, blocked = 0, onCriticalPath = 0, articulationCount = 0;
-const blockerCount = {};
-DATA.links.forEach(l => blockerCount[l.source] = (blockerCount[l.source] || 0) + 1);
-DATA.nodes.forEach(n => {
-    n.blockerCount = blockerCount[n.id] || 0;
-    if ((n.status === 'open' || n.status === 'in_progress') && n.blockerCount === 0) actionable++;
-    if (n.status === 'blocked') blocked++;
-    if (n.slack === 0) onCriticalPath++;
-    if (n.is_articulation) articulationCount++;
-});
-document.getElementById('stat-actionable').textContent = actionable;
-document.getElementById('stat-blocked').textContent = blocked;
-document.getElementById('stat-critical').textContent = onCriticalPath;
-document.getElementById('stat-articulation').textContent = articulationCount;
+function computeStats() {
+    actionable = 0; blocked = 0; onCriticalPath = 0; articulationCount = 0;
+    const blockerCount = {};
+    DATA.links.forEach(l => blockerCount[l.source] = (blockerCount[l.source] || 0) + 1);
+    DATA.nodes.forEach(n => {
+        n.blockerCount = blockerCount[n.id] || 0;
+        if ((n.status === 'open' || n.status === 'in_progress') && n.blockerCount === 0) actionable++;
+        if (n.status === 'blocked') blocked++;
+        if (n.slack === 0) onCriticalPath++;
+        if (n.is_articulation) articulationCount++;
+    });
+    document.getElementById('stat-nodes').textContent = DATA.nodes.length;
+    document.getElementById('stat-edges').textContent = DATA.links.length;
+    document.getElementById('stat-actionable').textContent = actionable;
+    document.getElementById('stat-blocked').textContent = blocked;
+    document.getElementById('stat-critical').textContent = onCriticalPath;
+    document.getElementById('stat-articulation').textContent = articulationCount;
+}
+computeStats();
 
 // Max values for sizing
-const maxPR = Math.max(...DATA.nodes.map(n => n.pagerank || 0), 0.001);
-const maxBW = Math.max(...DATA.nodes.map(n => n.betweenness || 0), 0.001);
-const maxCP = Math.max(...DATA.nodes.map(n => n.critical_path || 0), 1);
-const maxInDeg = Math.max(...DATA.nodes.map(n => n.in_degree || 0), 1);
+let maxPR, maxBW, maxCP, maxInDeg, maxImpact;
+function computeMetricMaxima() {
+    maxPR = Math.max(...DATA.nodes.map(n => n.pagerank || 0), 0.001);
+    maxBW = Math.max(...DATA.nodes.map(n => n.betweenness || 0), 0.001);
+    maxCP = Math.max(...DATA.nodes.map(n => n.critical_path || 0), 1);
+    maxInDeg = Math.max(...DATA.nodes.map(n => n.in_degree || 0), 1);
+    maxImpact = Math.max(...DATA.nodes.map(n => n.unblock_impact || 0), 1);
+}
+computeMetricMaxima();
+
+// Schedule-risk metrics from updated_at/due_date: days idle and days until due (open beads only)
+const DAY_MS = 86400000, DUE_HORIZON_DAYS = 14;
+function parseExportDate(s) {
+    if (!s) return null;
+    const d = new Date(s.length === 10 ? s + 'T23:59:59' : s.replace(' ', 'T')); // Due dates count to the end of the day
+    return isNaN(d) ? null : d;
+}
+let maxIdle = 14;
+function computeScheduleRisk() {
+    DATA.nodes.forEach(n => {
+        const updated = parseExportDate(n.updated_at), due = parseExportDate(n.due_date);
+        const open = n.status !== 'closed';
+        n.idle_days = open && updated ? Math.max(0, (Date.now() - updated) / DAY_MS) : null;
+        n.due_in_days = open && due ? (due - Date.now()) / DAY_MS : null;
+    });
+    maxIdle = Math.max(...DATA.nodes.map(n => n.idle_days || 0), 14);
+}
+computeScheduleRisk();
+// 0 = recently touched / not due soon, 1 = stalest / overdue; null when the bead has no such date
+function scheduleRisk(n, metric) {
+    if (metric === 'staleness') return n.idle_days == null ? null : n.idle_days / maxIdle;
+    if (n.due_in_days == null) return null;
+    return n.due_in_days <= 0 ? 1 : Math.max(0, 1 - n.due_in_days / DUE_HORIZON_DAYS);
+}
+// 0 = healthy (score 100), 1 = unhealthy (score 0); null for closed beads, which are not scored
+function healthRisk(n) { return n.health == null ? null : 1 - n.health / 100; }
+const METRIC_LABELS = { pagerank: 'PageRank', betweenness: 'Betweenness', critical: 'Critical Path', indegree: 'In-Degree', staleness: 'Staleness', due: 'Due Urgency', impact: 'Unblock Impact', health: 'Health Risk' };
+
+// --max-nodes exports keep only the most important beads; say how many were left out and why
+if (DATA.summary && DATA.summary.omitted) {
+    const o = DATA.summary.omitted;
+    document.getElementById('footer-omitted').textContent = ' | Showing top ' + DATA.summary.nodes + ' of ' + o.total_nodes + ' beads by ' + (METRIC_LABELS[o.metric] || o.metric) + ' (' + o.nodes + ' beads, ' + o.edges + ' edges omitted)';
+}
 
 let sizeMetric = 'pagerank', heatmapMode = false, hoveredNode = null, highlightedNodes = new Set();
+let edgeTypeFilter = ''; // '' shows every dependency type
+// Collapsed epics, and for each bead they hide the collapsed ancestor absorbing it
+let collapsedEpics = new Set(), collapsedOwner = new Map(), collapsedCounts = new Map();
+const savedAnimations = localStorage.getItem('bv-graph-animations');
+const prefersReducedMotion = window.matchMedia && window.matchMedia('(prefers-reduced-motion: reduce)').matches;
+let animationsEnabled = savedAnimations ? savedAnimations === 'on' : (EXPORT_ANIMATIONS && !prefersReducedMotion);
 
 function getNodeSize(n) {
     const base = 5, scale = 16;
@@ -958,10 +1257,35 @@ function getNodeSize(n) {
         case 'betweenness': return base + ((n.betweenness || 0) / maxBW) * scale;
         case 'critical': return base + ((n.critical_path || 0) / maxCP) * scale;
         case 'indegree': return base + ((n.in_degree || 0) / maxInDeg) * scale;
+        case 'impact': return base + ((n.unblock_impact || 0) / maxImpact) * scale;
+        case 'staleness': case 'due': return base + (scheduleRisk(n, sizeMetric) || 0) * scale;
+        case 'health': return base + (healthRisk(n) || 0) * scale;
         default: return base + ((n.pagerank || 0) / maxPR) * scale;
     }
 }
 
+// Topological level: 0 for beads with no blockers; cycle members show "cycle"
+function topoLevelLabel(n) { return n.in_cycle ? 'cycle' : String(n.topo_level ?? '-'); }
+let showLevels = false;
+// Camera lock: while on, selecting a bead keeps it centered every tick until
+// the force simulation cools; a background click releases it early.
+let cameraLocked = false;
+let cameraFollowing = false;
+function followSelection() {
+    if (cameraLocked && cameraFollowing && selectedNode && isFinite(selectedNode.x) && isFinite(selectedNode.y)) {
+        Graph.centerAt(selectedNode.x, selectedNode.y);
+    }
+}
+function levelLabelsVisible() { const mode = Graph.dagMode(); return showLevels && (mode === 'td' || mode === 'lr'); }
+
+// Built-in types are styled by their badge-* CSS class; registered and unknown types use TYPE_COLORS
+function styleTypeBadge(el, t) {
+    const st = TYPE_STYLES[t];
+    const inline = !st || st.custom;
+    el.style.background = inline ? (TYPE_COLORS[t] || FALLBACK_TYPE_STYLE.color) : '';
+    el.style.color = inline ? '#0f0f1a' : '';
+}
+
 function getHeatmapColor(n) {
     let val = 0, max = 1;
     switch(sizeMetric) {
@@ -969,12 +1293,87 @@ function getHeatmapColor(n) {
         case 'betweenness': val = n.betweenness || 0; max = maxBW; break;
         case 'critical': val = n.critical_path || 0; max = maxCP; break;
         case 'indegree': val = n.in_degree || 0; max = maxInDeg; break;
+        case 'impact': val = n.unblock_impact || 0; max = maxImpact; break;
+        case 'staleness': case 'due':
+            val = scheduleRisk(n, sizeMetric);
+            if (val == null) return '#6b7280'; // No date to judge by
+            break;
+        case 'health':
+            val = healthRisk(n);
+            if (val == null) return '#6b7280'; // Closed, not scored
+            break;
     }
-    const ratio = val / max;
+    const ratio = Math.min(Math.max(val / max, 0), 1);
+    if (activePalette === 'cb-safe') return viridisColor(ratio);
     const hue = 120 - ratio * 120; // Green to red
     return 'hsl(' + hue + ', 80%%, 50%%)';
 }
 
+function viridisColor(ratio) {
+    const pos = ratio * (VIRIDIS.length - 1);
+    const i = Math.min(Math.floor(pos), VIRIDIS.length - 2), t = pos - i;
+    const c = VIRIDIS[i].map((v, k) => Math.round(v + (VIRIDIS[i + 1][k] - v) * t));
+    return 'rgb(' + c.join(', ') + ')';
+}
+
+// Ownership: "Color: Assignee" colors nodes by owner, and at high zoom each
+// node carries an initials badge, or an avatar when --avatar-url-template
+// ({assignee}) is set. Colors follow the sorted assignee list, so the first
+// dozen owners never share one. Unassigned beads get a neutral dashed marker.
+const ASSIGNEE_COLORS = ['#60a5fa', '#f97316', '#34d399', '#f472b6', '#a78bfa', '#facc15', '#22d3ee', '#fb7185', '#a3e635', '#c084fc', '#2dd4bf', '#fdba74'];
+const UNASSIGNED_COLOR = '#64748b';
+const assigneeIndex = new Map();
+function indexAssignees() {
+    const counts = new Map();
+    DATA.nodes.forEach(n => { if (n.assignee) counts.set(n.assignee, (counts.get(n.assignee) || 0) + 1); });
+    assigneeIndex.clear();
+    [...counts.keys()].sort().forEach((a, i) => assigneeIndex.set(a, i));
+    renderAssigneeLegend(counts);
+}
+function assigneeColor(a) {
+    if (!a || !assigneeIndex.has(a)) return UNASSIGNED_COLOR;
+    return ASSIGNEE_COLORS[assigneeIndex.get(a) %% ASSIGNEE_COLORS.length];
+}
+// "alice" -> AL, "Alice Smith" / "alice.smith@example.com" -> AS
+function assigneeInitials(a) {
+    const parts = String(a).split('@')[0].split(/[\s._-]+/).filter(Boolean).map(p => [...p]);
+    if (parts.length === 0) return '?';
+    if (parts.length === 1) return parts[0].slice(0, 2).join('').toUpperCase();
+    return (parts[0][0] + parts[parts.length - 1][0]).toUpperCase();
+}
+const avatarImages = new Map(); // assignee -> Image, or null once it failed to load
+function assigneeAvatar(a) {
+    if (!LINK_TEMPLATES.avatar || typeof Image === 'undefined') return null;
+    if (!avatarImages.has(a)) {
+        const img = new Image();
+        img.onload = () => Graph.nodeColor(Graph.nodeColor()); // Repaint with the avatar
+        img.onerror = () => avatarImages.set(a, null);
+        img.src = templateURL(LINK_TEMPLATES.avatar, { assignee: a });
+        avatarImages.set(a, img);
+    }
+    const img = avatarImages.get(a);
+    return img && img.complete && img.naturalWidth > 0 ? img : null;
+}
+function renderAssigneeLegend(counts) {
+    const el = document.getElementById('assignee-legend');
+    el.innerHTML = [...assigneeIndex.keys()].map(a =>
+        '<div class="legend-item" title="' + escapeAttr(a) + '"><div class="legend-dot" style="background:' + assigneeColor(a) + ';color:' + assigneeColor(a) + '"></div>' +
+        escapeHtml(assigneeInitials(a)) + ' · ' + escapeHtml(a) + ' (' + counts.get(a) + ')</div>').join('');
+    document.getElementById('assignee-panel').style.display = assigneeIndex.size ? '' : 'none';
+}
+let colorBy = localStorage.getItem('bv-graph-color-by') === 'assignee' ? 'assignee' : 'status';
+function nodeFillColor(n) {
+    if (heatmapMode) return getHeatmapColor(n);
+    return colorBy === 'assignee' ? assigneeColor(n.assignee) : statusColor(n.status);
+}
+function setColorBy(mode) {
+    colorBy = mode === 'assignee' ? 'assignee' : 'status';
+    document.getElementById('color-by').value = colorBy;
+    localStorage.setItem('bv-graph-color-by', colorBy);
+    Graph.nodeColor(Graph.nodeColor());
+}
+indexAssignees();
+
 // Get connected subgraph (for golden glow highlight)
 function getConnectedNodes(nodeId, depth = 2) {
     const connected = new Set([nodeId]);
@@ -992,6 +1391,130 @@ function getConnectedNodes(nodeId, depth = 2) {
     return connected;
 }
 
+// Hover highlight depth, set with the toolbar slider or [ / ]; the context
+// menu's "Highlight connected" reaches one hop further
+const MIN_HIGHLIGHT_DEPTH = 1, MAX_HIGHLIGHT_DEPTH = 5;
+let highlightDepth = 2;
+function setHighlightDepth(depth) {
+    if (!(depth >= MIN_HIGHLIGHT_DEPTH)) depth = 2;
+    highlightDepth = Math.min(MAX_HIGHLIGHT_DEPTH, Math.max(MIN_HIGHLIGHT_DEPTH, Math.round(depth)));
+    document.getElementById('highlight-depth').value = highlightDepth;
+    document.getElementById('highlight-depth-value').textContent = highlightDepth;
+    localStorage.setItem('bv-graph-highlight-depth', highlightDepth);
+    if (hoveredNode) {
+        highlightedNodes = getConnectedNodes(hoveredNode.id, highlightDepth);
+        Graph.nodeColor(Graph.nodeColor());
+        Graph.linkColor(Graph.linkColor());
+        Graph.linkWidth(Graph.linkWidth());
+    }
+}
+setHighlightDepth(parseInt(localStorage.getItem('bv-graph-highlight-depth'), 10));
+document.getElementById('highlight-depth').oninput = e => setHighlightDepth(parseInt(e.target.value, 10));
+
+// Comparison mode: up to two highlight groups (A/B) with an overlap tint
+const COMPARE_COLORS = { a: '#22d3ee', b: '#ec4899', both: '#fbbf24' };
+let compareGroups = [null, null];
+let lastHighlight = null; // { anchor, nodes } from the most recent explicit highlight
+function compareActive() { return compareGroups[0] !== null; }
+function compareGroupOf(id) {
+    const inA = compareGroups[0] && compareGroups[0].has(id);
+    const inB = compareGroups[1] && compareGroups[1].has(id);
+    if (inA && inB) return 'both';
+    return inA ? 'a' : (inB ? 'b' : null);
+}
+// A link belongs to a group when both endpoints do; overlap wins if shared
+function compareLinkGroup(src, tgt) {
+    const gs = compareGroupOf(src), gt = compareGroupOf(tgt);
+    if (!gs || !gt) return null;
+    if (gs === gt) return gs;
+    if (gs === 'both') return gt;
+    if (gt === 'both') return gs;
+    return null;
+}
+
+// Edge bundling: links between beads in different communities (node.cluster,
+// from analysis.ComputeCommunities) are drawn as one curved trunk per pair of
+// clusters, running between the cluster centroids, with each bead tied to its
+// own centroid. Highlighted links are still drawn individually. Without cluster
+// data the toggle does nothing and edges stay as they are.
+const BUNDLE_CURVATURE = 0.25;
+const clusterOf = new Map();
+let clustersAvailable = false;
+function indexClusters() {
+    clusterOf.clear();
+    DATA.nodes.forEach(n => { if (typeof n.cluster === 'number') clusterOf.set(n.id, n.cluster); });
+    clustersAvailable = new Set(clusterOf.values()).size > 1;
+}
+indexClusters();
+let edgeBundling = localStorage.getItem('bv-graph-edge-bundling') === 'on';
+function bundledLink(l) {
+    if (!edgeBundling || !clustersAvailable) return false;
+    const src = typeof l.source === 'object' ? l.source.id : l.source;
+    const tgt = typeof l.target === 'object' ? l.target.id : l.target;
+    const a = clusterOf.get(src), b = clusterOf.get(tgt);
+    if (a === undefined || b === undefined || a === b) return false;
+    return !(highlightedNodes.size > 0 && highlightedNodes.has(src) && highlightedNodes.has(tgt));
+}
+function drawEdgeBundles(ctx, globalScale) {
+    if (!edgeBundling || !clustersAvailable) return;
+    const data = Graph.graphData();
+    const shown = n => currentVisibilityFilter(n) && isFinite(n.x) && isFinite(n.y);
+    const sums = new Map();
+    data.nodes.forEach(n => {
+        if (!clusterOf.has(n.id) || !shown(n)) return;
+        const c = clusterOf.get(n.id);
+        const sum = sums.get(c) || { x: 0, y: 0, count: 0 };
+        sum.x += n.x; sum.y += n.y; sum.count++;
+        sums.set(c, sum);
+    });
+    const trunks = new Map(), tied = new Map();
+    data.links.forEach(l => {
+        if (typeof l.source !== 'object' || typeof l.target !== 'object' || !bundledLink(l)) return;
+        if (!shown(l.source) || !shown(l.target)) return;
+        if (edgeTypeFilter && edgeType(l) !== edgeTypeFilter) return;
+        const a = Math.min(clusterOf.get(l.source.id), clusterOf.get(l.target.id));
+        const b = Math.max(clusterOf.get(l.source.id), clusterOf.get(l.target.id));
+        const trunk = trunks.get(a + '|' + b) || { a: a, b: b, count: 0, critical: false };
+        trunk.count++;
+        trunk.critical = trunk.critical || l.critical;
+        trunks.set(a + '|' + b, trunk);
+        tied.set(l.source.id, l.source);
+        tied.set(l.target.id, l.target);
+    });
+    if (trunks.size === 0) return;
+    const centroid = c => { const sum = sums.get(c); return { x: sum.x / sum.count, y: sum.y / sum.count }; };
+    const dimmed = highlightedNodes.size > 0 || blastActive() || compareActive();
+
+    ctx.save();
+    ctx.strokeStyle = isDarkMode ? '#6272a4' : '#8888aa';
+    ctx.globalAlpha = dimmed ? 0.1 : 0.35;
+    ctx.lineWidth = 1 / globalScale;
+    ctx.beginPath();
+    tied.forEach(n => {
+        const c = centroid(clusterOf.get(n.id));
+        ctx.moveTo(n.x, n.y); ctx.lineTo(c.x, c.y);
+    });
+    ctx.stroke();
+
+    ctx.globalAlpha = dimmed ? 0.15 : 0.7;
+    ctx.font = (10 / globalScale) + 'px sans-serif';
+    ctx.textAlign = 'center'; ctx.textBaseline = 'middle';
+    trunks.forEach(t => {
+        const p = centroid(t.a), q = centroid(t.b);
+        // Control point bowed off the midpoint, as linkCurvature does for single links
+        const cx = (p.x + q.x) / 2 - (q.y - p.y) * BUNDLE_CURVATURE;
+        const cy = (p.y + q.y) / 2 + (q.x - p.x) * BUNDLE_CURVATURE;
+        ctx.strokeStyle = t.critical ? CRITICAL_COLOR : (isDarkMode ? '#6272a4' : '#8888aa');
+        ctx.lineWidth = (1 + Math.log2(t.count)) / globalScale;
+        ctx.beginPath(); ctx.moveTo(p.x, p.y); ctx.quadraticCurveTo(cx, cy, q.x, q.y); ctx.stroke();
+        if (t.count > 1) {
+            ctx.fillStyle = isDarkMode ? '#f8f8f2' : '#1a1a2e';
+            ctx.fillText(String(t.count), (p.x + 2 * cx + q.x) / 4, (p.y + 2 * cy + q.y) / 4);
+        }
+    });
+    ctx.restore();
+}
+
 const container = document.getElementById('graph-container');
 const Graph = ForceGraph()(container)
     .graphData(JSON.parse(JSON.stringify(DATA)))
@@ -999,47 +1522,73 @@ const Graph = ForceGraph()(container)
     .nodeId('id')
     .nodeLabel(null)
     .nodeColor(n => {
-        if (highlightedNodes.size > 0 && !highlightedNodes.has(n.id)) return (STATUS_COLORS[n.status] || '#555577') + '20';
-        if (heatmapMode) return getHeatmapColor(n);
-        return STATUS_COLORS[n.status] || '#555577';
+        if (blastActive()) return blastDistances.has(n.id) ? blastColor(blastDistances.get(n.id)) : statusColor(n.status) + '20';
+        if (highlightedNodes.size > 0 && !highlightedNodes.has(n.id)) return statusColor(n.status) + '20';
+        if (highlightedNodes.size === 0 && compareActive() && !compareGroupOf(n.id)) return statusColor(n.status) + '20';
+        return nodeFillColor(n);
     })
     .nodeVal(n => getNodeSize(n))
     .linkColor(l => {
         const src = typeof l.source === 'object' ? l.source.id : l.source;
         const tgt = typeof l.target === 'object' ? l.target.id : l.target;
+        if (blastActive()) {
+            const d = blastLinkDistance(src, tgt);
+            return d === null ? '#44475a15' : blastColor(d) + 'cc';
+        }
         if (highlightedNodes.size > 0) {
             if (highlightedNodes.has(src) && highlightedNodes.has(tgt)) return '#fbbf24aa';
             return '#44475a15';
         }
-        return l.critical ? '#ec489980' : '#44475a40';
+        if (compareActive()) {
+            const g = compareLinkGroup(src, tgt);
+            return g ? COMPARE_COLORS[g] + 'aa' : '#44475a15';
+        }
+        return l.critical ? CRITICAL_COLOR + '80' : edgeBaseColor(l);
     })
     .linkWidth(l => {
         const src = typeof l.source === 'object' ? l.source.id : l.source;
         const tgt = typeof l.target === 'object' ? l.target.id : l.target;
+        if (blastActive()) return blastLinkDistance(src, tgt) === null ? 1 : 3;
         if (highlightedNodes.size > 0 && highlightedNodes.has(src) && highlightedNodes.has(tgt)) return 3;
         return l.critical ? 2 : 1;
     })
-    .linkDirectionalArrowLength(5)
+    .linkLineDash(l => edgeStyle(l).dash || null)
+    .linkVisibility(l => {
+        const src = typeof l.source === 'object' ? l.source.id : l.source;
+        const tgt = typeof l.target === 'object' ? l.target.id : l.target;
+        if (collapsedOwner.has(src) || collapsedOwner.has(tgt)) return false;
+        if (bundledLink(l)) return false; // Drawn as part of a cluster trunk
+        return !edgeTypeFilter || edgeType(l) === edgeTypeFilter;
+    })
+    .linkDirectionalArrowLength(l => edgeStyle(l).arrow)
     .linkDirectionalArrowColor(l => {
         const src = typeof l.source === 'object' ? l.source.id : l.source;
         const tgt = typeof l.target === 'object' ? l.target.id : l.target;
+        if (blastActive()) { const d = blastLinkDistance(src, tgt); return d === null ? '#44475a30' : blastColor(d); }
         if (highlightedNodes.size > 0 && highlightedNodes.has(src) && highlightedNodes.has(tgt)) return '#fbbf24';
-        return l.critical ? '#ec4899' : '#44475a';
+        if (l.critical) return CRITICAL_COLOR;
+        return edgeStyle(l).color || (isDarkMode ? '#44475a' : '#8888aa');
     })
-    .linkDirectionalArrowRelPos(1)
+    .linkDirectionalArrowRelPos(l => edgeStyle(l).arrow_pos)
     .linkCurvature(0.1)
-    .linkDirectionalParticles(l => l.critical ? 2 : 0)
+    .linkDirectionalParticles(l => animationsEnabled && l.critical ? 2 : 0)
     .linkDirectionalParticleSpeed(0.003)
     .linkDirectionalParticleWidth(2)
-    .linkDirectionalParticleColor(() => '#ec4899')
+    .linkDirectionalParticleColor(() => CRITICAL_COLOR)
     .d3AlphaDecay(0.02)
     .d3VelocityDecay(0.25)
+    .onEngineTick(followSelection)
+    .onEngineStop(() => { cameraFollowing = false; })
+    .onRenderFramePre(drawEdgeBundles)
     .nodeCanvasObject((node, ctx, globalScale) => {
         const x = node.x, y = node.y;
         if (x === undefined || y === undefined || !isFinite(x) || !isFinite(y)) return;
         const size = getNodeSize(node);
-        const baseColor = heatmapMode ? getHeatmapColor(node) : STATUS_COLORS[node.status] || '#555577';
-        const isHighlighted = highlightedNodes.size === 0 || highlightedNodes.has(node.id);
+        const blastDist = blastActive() ? blastDistances.get(node.id) : undefined;
+        const baseColor = blastDist !== undefined ? blastColor(blastDist) : nodeFillColor(node);
+        const compareGroup = compareActive() ? compareGroupOf(node.id) : null;
+        const isHighlighted = blastActive() ? blastDist !== undefined
+            : highlightedNodes.size > 0 ? highlightedNodes.has(node.id) : (!compareActive() || compareGroup !== null);
         const isHovered = hoveredNode && hoveredNode.id === node.id;
         const alpha = isHighlighted ? 1 : 0.15;
 
@@ -1051,18 +1600,26 @@ const Graph = ForceGraph()(container)
             ctx.fillStyle = g; ctx.fill();
         }
 
+        // Comparison group tint
+        if (compareGroup) {
+            ctx.beginPath(); ctx.arc(x, y, size + 7, 0, 2 * Math.PI);
+            const g = ctx.createRadialGradient(x, y, size, x, y, size + 10);
+            g.addColorStop(0, COMPARE_COLORS[compareGroup] + 'b0'); g.addColorStop(1, 'transparent');
+            ctx.fillStyle = g; ctx.fill();
+        }
+
         // Articulation point glow
         if (node.is_articulation && isHighlighted) {
             ctx.beginPath(); ctx.arc(x, y, size + 6, 0, 2 * Math.PI);
             const g = ctx.createRadialGradient(x, y, size, x, y, size + 8);
-            g.addColorStop(0, '#ec489960'); g.addColorStop(1, 'transparent');
+            g.addColorStop(0, ARTICULATION_COLOR + '60'); g.addColorStop(1, 'transparent');
             ctx.fillStyle = g; ctx.fill();
         }
 
         // Critical path indicator
         if (node.slack === 0 && isHighlighted) {
             ctx.beginPath(); ctx.arc(x, y, size + 3, 0, 2 * Math.PI);
-            ctx.fillStyle = baseColor + '30'; ctx.fill();
+            ctx.fillStyle = (CRITICAL_COLOR_OVERRIDE || baseColor) + '30'; ctx.fill();
         }
 
         // Priority ring
@@ -1071,27 +1628,51 @@ const Graph = ForceGraph()(container)
         ctx.beginPath(); ctx.arc(x, y, size + 1.5, 0, 2 * Math.PI);
         ctx.strokeStyle = pColor; ctx.lineWidth = 2; ctx.stroke();
 
+        // Acceptance checklist progress arc (clockwise from 12 o'clock)
+        if (node.acceptance && node.acceptance.total > 0) {
+            ctx.beginPath();
+            ctx.arc(x, y, size + 4, -Math.PI / 2, -Math.PI / 2 + 2 * Math.PI * node.acceptance.ratio);
+            ctx.strokeStyle = STATUS_COLORS.open; ctx.lineWidth = 1.5; ctx.stroke();
+        }
+
         // Node shape based on type
         ctx.fillStyle = baseColor;
         ctx.beginPath();
-        switch(node.type) {
-            case 'bug': // Triangle
+        switch(typeStyle(node.type).shape) {
+            case 'triangle': // bug
                 ctx.moveTo(x, y - size);
                 ctx.lineTo(x + size * 0.866, y + size * 0.5);
                 ctx.lineTo(x - size * 0.866, y + size * 0.5);
                 ctx.closePath();
                 break;
-            case 'task': // Square
+            case 'square': // task
                 ctx.rect(x - size * 0.7, y - size * 0.7, size * 1.4, size * 1.4);
                 break;
-            case 'epic': // Diamond
+            case 'diamond': // epic
                 ctx.moveTo(x, y - size);
                 ctx.lineTo(x + size, y);
                 ctx.lineTo(x, y + size);
                 ctx.lineTo(x - size, y);
                 ctx.closePath();
                 break;
-            default: // Circle (feature)
+            case 'hexagon': // unregistered types
+                for (let i = 0; i < 6; i++) {
+                    const a = Math.PI / 3 * i - Math.PI / 6;
+                    if (i === 0) ctx.moveTo(x + size * Math.cos(a), y + size * Math.sin(a));
+                    else ctx.lineTo(x + size * Math.cos(a), y + size * Math.sin(a));
+                }
+                ctx.closePath();
+                break;
+            case 'star':
+                for (let i = 0; i < 10; i++) {
+                    const r = i %% 2 === 0 ? size * 1.15 : size * 0.5;
+                    const a = Math.PI / 5 * i - Math.PI / 2;
+                    if (i === 0) ctx.moveTo(x + r * Math.cos(a), y + r * Math.sin(a));
+                    else ctx.lineTo(x + r * Math.cos(a), y + r * Math.sin(a));
+                }
+                ctx.closePath();
+                break;
+            default: // circle (feature)
                 ctx.arc(x, y, size, 0, 2 * Math.PI);
         }
         ctx.fill();
@@ -1102,12 +1683,32 @@ const Graph = ForceGraph()(container)
         ctx.fillStyle = hl; ctx.fill();
         ctx.globalAlpha = 1;
 
+        // Collapsed epic: badge with the number of hidden beads
+        if (collapsedCounts.has(node.id)) {
+            const r = Math.max(size * 0.45, 3), bx = x + size * 0.8, by = y - size * 0.8;
+            ctx.beginPath(); ctx.arc(bx, by, r, 0, 2 * Math.PI);
+            ctx.fillStyle = '#fbbf24'; ctx.fill();
+            ctx.font = 'bold ' + (r * 1.1) + 'px Inter, sans-serif';
+            ctx.textAlign = 'center'; ctx.textBaseline = 'middle';
+            ctx.fillStyle = '#1a1a2e';
+            ctx.fillText('+' + collapsedCounts.get(node.id), bx, by);
+        }
+
+        // Topological level above the node (DAG modes, when enabled)
+        if (levelLabelsVisible()) {
+            const fontSize = Math.max(9 / globalScale, 2.5);
+            ctx.font = fontSize + 'px JetBrains Mono, monospace';
+            ctx.textAlign = 'center'; ctx.textBaseline = 'bottom';
+            ctx.fillStyle = node.in_cycle ? STATUS_COLORS.blocked : (isDarkMode ? '#8888aa' : '#555577');
+            ctx.fillText(node.in_cycle ? 'cycle' : 'L' + node.topo_level, x, y - size - 2);
+        }
+
         // Labels at zoom
         if (globalScale > 1.2 && isHighlighted) {
             const fontSize = Math.max(10 / globalScale, 3);
             ctx.font = fontSize + 'px Inter, sans-serif';
             ctx.textAlign = 'center'; ctx.textBaseline = 'middle';
-            ctx.fillStyle = '#e8e8f0';
+            ctx.fillStyle = isDarkMode ? '#e8e8f0' : '#1a1a2e';
             ctx.fillText(node.id, x, y + size + fontSize + 2);
             if (globalScale > 2) {
                 ctx.fillStyle = pColor;
@@ -1115,6 +1716,31 @@ const Graph = ForceGraph()(container)
                 ctx.fillText('P' + node.priority, x, y);
             }
         }
+
+        // Owner badge at high zoom, opposite the collapsed-epic count
+        if (globalScale > 2 && isHighlighted) {
+            const r = Math.max(size * 0.45, 3), bx = x + size * 0.8, by = y + size * 0.8;
+            ctx.beginPath(); ctx.arc(bx, by, r, 0, 2 * Math.PI);
+            if (!node.assignee) {
+                ctx.setLineDash([1, 1]);
+                ctx.strokeStyle = UNASSIGNED_COLOR; ctx.lineWidth = 0.6; ctx.stroke();
+                ctx.setLineDash([]);
+            } else {
+                const img = assigneeAvatar(node.assignee);
+                if (img) {
+                    ctx.save(); ctx.clip();
+                    ctx.drawImage(img, bx - r, by - r, 2 * r, 2 * r);
+                    ctx.restore();
+                } else {
+                    ctx.fillStyle = assigneeColor(node.assignee); ctx.fill();
+                    ctx.font = 'bold ' + (r * 0.9) + 'px Inter, sans-serif';
+                    ctx.textAlign = 'center'; ctx.textBaseline = 'middle';
+                    ctx.fillStyle = '#ffffff';
+                    ctx.fillText(assigneeInitials(node.assignee), bx, by);
+                }
+                ctx.strokeStyle = isDarkMode ? '#1a1a2e' : '#ffffff'; ctx.lineWidth = 0.5; ctx.stroke();
+            }
+        }
     })
     .nodePointerAreaPaint((n, c, ctx) => {
         if (n.x === undefined || n.y === undefined || !isFinite(n.x) || !isFinite(n.y)) return;
@@ -1124,7 +1750,7 @@ const Graph = ForceGraph()(container)
     .onNodeClick(handleNodeClick)
     .onNodeRightClick((node, event) => { event.preventDefault(); showContextMenu(node, event); })
     .onNodeHover(handleNodeHover)
-    .onBackgroundClick(() => { clearSelection(); hideContextMenu(); hideHoverPanel(); })
+    .onBackgroundClick(() => { cameraFollowing = false; clearSelection(); hideContextMenu(); hideHoverPanel(); })
     .onBackgroundRightClick(() => hideContextMenu());
 
 // Hover handling with golden glow and detail panel
@@ -1132,7 +1758,7 @@ function handleNodeHover(node) {
     hoveredNode = node;
     container.style.cursor = node ? 'pointer' : 'grab';
     if (node) {
-        highlightedNodes = getConnectedNodes(node.id, 2);
+        if (!blastActive()) highlightedNodes = getConnectedNodes(node.id, highlightDepth);
         showHoverPanel(node);
     } else {
         highlightedNodes = new Set();
@@ -1148,19 +1774,30 @@ let panelMode = 'docked';
 
 // Populate panel content for a given prefix (hover- or docked-)
 function populatePanelContent(prefix, node) {
-    document.getElementById(prefix + 'id').textContent = node.id;
+    const idEl = document.getElementById(prefix + 'id');
+    const issueURL = templateURL(LINK_TEMPLATES.issue, { id: node.id });
+    idEl.textContent = '';
+    if (issueURL) {
+        const a = document.createElement('a');
+        a.href = issueURL; a.target = '_blank'; a.rel = 'noopener noreferrer';
+        a.textContent = node.id;
+        idEl.appendChild(a);
+    } else {
+        idEl.textContent = node.id;
+    }
     document.getElementById(prefix + 'title').textContent = node.title;
 
     // Type badge
     const typeBadge = document.getElementById(prefix + 'type-badge');
     typeBadge.textContent = node.type || 'task';
     typeBadge.className = 'hover-type-badge badge-' + (node.type || 'task');
+    styleTypeBadge(typeBadge, node.type || 'task');
 
     // Badges
     const badgesEl = document.getElementById(prefix + 'badges');
     badgesEl.innerHTML = '';
     const addBadge = (cls, text) => { const b = document.createElement('span'); b.className = 'badge ' + cls; b.textContent = text; badgesEl.appendChild(b); };
-    addBadge('badge-' + node.status, node.status.replace('_', ' '));
+    addBadge(statusBadgeClass(node.status), node.status.replace(/_/g, ' '));
     addBadge('', 'P' + node.priority);
     if (node.is_articulation) addBadge('badge-articulation', 'Cut Vertex');
     if (node.slack === 0) addBadge('badge-critical', 'Critical Path');
@@ -1170,28 +1807,34 @@ function populatePanelContent(prefix, node) {
     const descSection = document.getElementById(prefix + 'description');
     if (node.description) {
         descSection.style.display = 'block';
-        document.getElementById(prefix + 'description-content').innerHTML = marked.parse(node.description);
+        document.getElementById(prefix + 'description-content').innerHTML = renderMarkdown(node.description);
     } else { descSection.style.display = 'none'; }
 
     // Design
     const designSection = document.getElementById(prefix + 'design');
     if (node.design) {
         designSection.style.display = 'block';
-        document.getElementById(prefix + 'design-content').innerHTML = marked.parse(node.design);
+        document.getElementById(prefix + 'design-content').innerHTML = renderMarkdown(node.design);
     } else { designSection.style.display = 'none'; }
 
     // Acceptance Criteria
     const acSection = document.getElementById(prefix + 'acceptance');
     if (node.acceptance_criteria) {
         acSection.style.display = 'block';
-        document.getElementById(prefix + 'acceptance-content').innerHTML = marked.parse(node.acceptance_criteria);
+        document.getElementById(prefix + 'acceptance-content').innerHTML = renderMarkdown(node.acceptance_criteria);
     } else { acSection.style.display = 'none'; }
+    const acProgress = document.getElementById(prefix + 'acceptance-progress');
+    if (node.acceptance && node.acceptance.total > 0) {
+        acProgress.style.display = 'flex';
+        acProgress.querySelector('.ac-progress-fill').style.width = Math.round(node.acceptance.ratio * 100) + '%%';
+        acProgress.querySelector('.ac-progress-label').textContent = node.acceptance.done + '/' + node.acceptance.total + ' done';
+    } else { acProgress.style.display = 'none'; }
 
     // Notes
     const notesSection = document.getElementById(prefix + 'notes');
     if (node.notes) {
         notesSection.style.display = 'block';
-        document.getElementById(prefix + 'notes-content').innerHTML = marked.parse(node.notes);
+        document.getElementById(prefix + 'notes-content').innerHTML = renderMarkdown(node.notes);
     } else { notesSection.style.display = 'none'; }
 
     // Metadata
@@ -1199,7 +1842,7 @@ function populatePanelContent(prefix, node) {
     metaEl.innerHTML = '';
     const addMeta = (label, value) => {
         if (!value) return;
-        metaEl.innerHTML += '<div class="hover-meta-item"><span class="hover-meta-label">' + label + '</span><span class="hover-meta-value">' + value + '</span></div>';
+        metaEl.innerHTML += '<div class="hover-meta-item"><span class="hover-meta-label">' + label + '</span><span class="hover-meta-value">' + escapeHtml(value) + '</span></div>';
     };
     addMeta('Assignee', node.assignee);
     addMeta('Created', node.created_at);
@@ -1212,15 +1855,30 @@ function populatePanelContent(prefix, node) {
     const blockedByList = document.getElementById(prefix + 'blocked-by-list');
     if (node.blocked_by && node.blocked_by.length > 0) {
         blockedBySection.style.display = 'block';
-        blockedByList.innerHTML = node.blocked_by.map(id => '<span class="hover-dep-chip" data-id="' + id + '">' + id + '</span>').join('');
+        blockedByList.innerHTML = node.blocked_by.map(id => '<span class="hover-dep-chip" data-id="' + escapeAttr(id) + '">' + escapeHtml(id) + '</span>').join('');
     } else { blockedBySection.style.display = 'none'; }
 
+    // Why Blocked: what each open blocker is waiting on
+    const whySection = document.getElementById(prefix + 'why-blocked');
+    const reasons = node.status === 'closed' ? [] : whyBlocked(node);
+    if (reasons.length > 0) {
+        whySection.style.display = 'block';
+        const chip = id => '<span class="hover-dep-chip" data-id="' + escapeAttr(id) + '">' + escapeAttr(id) + '</span>';
+        document.getElementById(prefix + 'why-blocked-list').innerHTML = reasons.map(r => {
+            let verdict;
+            if (r.state === 'actionable') verdict = '<span class="why-actionable">actionable now</span>';
+            else if (r.state === 'cycle') verdict = '<span class="why-cycle">unblockable chain (cycle)</span>';
+            else verdict = '<span class="why-blocked">blocked; finish first (' + r.hops + (r.hops === 1 ? ' hop' : ' hops') + ' up):</span> ' + r.roots.map(chip).join(' ');
+            return '<div class="why-row">' + chip(r.id) + '<span class="why-status">' + escapeAttr(r.status) + '</span>' + verdict + '</div>';
+        }).join('');
+    } else { whySection.style.display = 'none'; }
+
     // Blocks
     const blocksSection = document.getElementById(prefix + 'blocks');
     const blocksList = document.getElementById(prefix + 'blocks-list');
     if (node.blocks && node.blocks.length > 0) {
         blocksSection.style.display = 'block';
-        blocksList.innerHTML = node.blocks.map(id => '<span class="hover-dep-chip" data-id="' + id + '">' + id + '</span>').join('');
+        blocksList.innerHTML = node.blocks.map(id => '<span class="hover-dep-chip" data-id="' + escapeAttr(id) + '">' + escapeHtml(id) + '</span>').join('');
     } else { blocksSection.style.display = 'none'; }
 
     // Commits
@@ -1228,14 +1886,20 @@ function populatePanelContent(prefix, node) {
     const commitsList = document.getElementById(prefix + 'commits-list');
     if (node.commits && node.commits.length > 0) {
         commitsSection.style.display = 'block';
-        commitsList.innerHTML = node.commits.slice(0, 5).map(c => '<div class="hover-commit"><span class="hover-commit-sha">' + c.short_sha + '</span> <span class="hover-commit-msg">' + (c.message || '').split('\\n')[0].substring(0, 60) + '</span></div>').join('');
+        commitsList.innerHTML = node.commits.slice(0, 5).map(c => {
+            const url = templateURL(LINK_TEMPLATES.commit, { sha: c.sha, short_sha: c.short_sha });
+            const sha = url
+                ? '<a class="hover-commit-sha" href="' + escapeAttr(url) + '" target="_blank" rel="noopener noreferrer">' + escapeHtml(c.short_sha) + '</a>'
+                : '<span class="hover-commit-sha">' + escapeHtml(c.short_sha) + '</span>';
+            return '<div class="hover-commit">' + sha + ' <span class="hover-commit-msg">' + escapeHtml((c.message || '').split('\\n')[0].substring(0, 60)) + '</span></div>';
+        }).join('');
     } else { commitsSection.style.display = 'none'; }
 
     // Metrics
     const metricsEl = document.getElementById(prefix + 'metrics');
     metricsEl.innerHTML = '';
     const addMetric = (label, value) => {
-        metricsEl.innerHTML += '<div class="hover-meta-item"><span class="hover-meta-label">' + label + '</span><span class="hover-meta-value">' + value + '</span></div>';
+        metricsEl.innerHTML += '<div class="hover-meta-item"><span class="hover-meta-label">' + label + '</span><span class="hover-meta-value">' + escapeHtml(value) + '</span></div>';
     };
     const fmt = (v, d) => (v != null && isFinite(v)) ? v.toFixed(d) : '-';
     addMetric('PageRank', fmt(node.pagerank * 100, 3) + '%%');
@@ -1246,6 +1910,9 @@ function populatePanelContent(prefix, node) {
     addMetric('Slack', fmt(node.slack, 1));
     addMetric('In-Degree', node.in_degree ?? '-');
     addMetric('Out-Degree', node.out_degree ?? '-');
+    addMetric('Topo Level', topoLevelLabel(node));
+    addMetric('Unblock Impact', node.unblock_impact ?? '-');
+    addMetric('Health', node.health ?? '-');
 }
 
 // Wire up dep chip clicks for a container
@@ -1256,9 +1923,13 @@ function wireDepChips(container) {
             const graphNodes = Graph.graphData().nodes;
             const target = graphNodes.find(n => n.id === targetId);
             if (target) {
+                // Record the bead we're drilling from so Alt+← returns to it
+                const fromId = container.querySelector('.hover-id').textContent;
+                const from = graphNodes.find(n => n.id === fromId);
+                if (from) pushNavHistory(from);
                 Graph.centerAt(target.x, target.y, 500);
                 Graph.zoom(2.5, 500);
-                setTimeout(() => showHoverPanel(target), 600);
+                setTimeout(() => selectNode(target), 600);
             }
         };
     });
@@ -1324,9 +1995,69 @@ function togglePanelMode() {
 document.getElementById('btn-detach').onclick = togglePanelMode;
 document.getElementById('hover-close').onclick = () => document.getElementById('hover-panel').classList.remove('visible');
 
+// Metric explanations for the sidebar's info popovers and the "How Metrics
+// Are Computed" panel. A bead's percentile is the share of beads in this export
+// it outranks; for slack, lower is more critical, so it outranks beads with more.
+const METRIC_INFO = [
+    { key: 'pagerank', name: 'PageRank', text: 'How much of the project rests on this bead. PageRank flows along blocking dependencies, so a bead scores high when many beads, especially important ones, depend on it directly or transitively. Rank is its position by PageRank.' },
+    { key: 'betweenness', name: 'Betweenness', text: 'How often this bead lies on the shortest dependency path between two other beads. High betweenness marks a bottleneck: work on either side has to flow through it. BW Rank is its position by betweenness.' },
+    { key: 'critical_path', name: 'Critical Path', text: 'Length of the longest chain of beads waiting on this one, counting itself. A high value means a delay here pushes back the most downstream work.' },
+    { key: 'slack', name: 'Slack', lowerIsCritical: true, text: 'How many steps this bead can slip without lengthening the longest dependency chain in the project. Zero slack (shown in green) puts it on the critical path.' },
+    { key: 'in_degree', name: 'In-Degree', text: 'Number of beads that directly depend on this one, i.e. that it blocks.' },
+    { key: 'out_degree', name: 'Out-Degree', text: 'Number of beads this one directly depends on, i.e. its blockers.' },
+    { key: 'topo_level', name: 'Level', text: 'Dependency depth: 0 for beads with no blockers, otherwise one more than the deepest blocker. All beads on a dependency cycle share the cycle\'s level.' },
+];
+const metricInfoByKey = new Map(METRIC_INFO.map(m => [m.key, m]));
+
+// "Higher than 85%% of beads", or null when the value is missing
+function metricPercentile(node, info) {
+    const v = node[info.key];
+    if (v == null || !isFinite(v)) return null;
+    let below = 0, total = 0;
+    DATA.nodes.forEach(n => {
+        const w = n[info.key];
+        if (w == null || !isFinite(w)) return;
+        total++;
+        if (info.lowerIsCritical ? w > v : w < v) below++;
+    });
+    if (total === 0) return null;
+    const pct = Math.round(below / total * 100);
+    return (info.lowerIsCritical ? 'Lower than ' : 'Higher than ') + pct + '%% of beads';
+}
+function metricExplainHtml(info, node) {
+    const pct = node ? metricPercentile(node, info) : null;
+    return '<div class="metric-popover-title">' + escapeHtml(info.name) + '</div>' + escapeHtml(info.text) +
+        (pct ? '<div class="metric-percentile">' + escapeHtml(node.id) + ': ' + pct + '</div>' : '');
+}
+function renderMetricsExplain() {
+    document.getElementById('metrics-explain').innerHTML = METRIC_INFO.map(info => {
+        const pct = selectedNode ? metricPercentile(selectedNode, info) : null;
+        return '<div class="metrics-explain-item"><span class="metrics-explain-name">' + escapeHtml(info.name) + '</span> — ' + escapeHtml(info.text) +
+            (pct ? '<div class="metric-percentile">' + escapeHtml(selectedNode.id) + ': ' + pct + '</div>' : '') + '</div>';
+    }).join('');
+}
+function showMetricPopover(btn) {
+    const info = metricInfoByKey.get(btn.dataset.metric);
+    if (!info) return;
+    const pop = document.getElementById('metric-popover');
+    pop.innerHTML = metricExplainHtml(info, selectedNode);
+    const r = btn.getBoundingClientRect();
+    pop.style.left = Math.max(8, Math.min(r.left, window.innerWidth - 296)) + 'px';
+    pop.style.top = (r.bottom + 6) + 'px';
+    pop.classList.add('visible');
+}
+function hideMetricPopover() { document.getElementById('metric-popover').classList.remove('visible'); }
+document.querySelectorAll('.metric-info').forEach(btn => {
+    btn.onclick = e => { e.stopPropagation(); showMetricPopover(btn); };
+});
+document.addEventListener('click', e => { if (!e.target.closest('.metric-popover')) hideMetricPopover(); });
+
 let selectedNode = null;
+renderMetricsExplain();
 function selectNode(node) {
     selectedNode = node;
+    cameraFollowing = cameraLocked;
+    pushNavHistory(node);
     showHoverPanel(node);
     document.getElementById('detail-id').textContent = node.id;
     document.getElementById('detail-name').textContent = node.title;
@@ -1336,8 +2067,9 @@ function selectNode(node) {
     prioEl.style.color = node.priority <= 1 ? 'white' : '#0f0f1a';
     const badgesEl = document.getElementById('detail-badges');
     badgesEl.innerHTML = '';
-    const sb = document.createElement('span'); sb.className = 'badge badge-' + node.status; sb.textContent = node.status.replace('_', ' '); badgesEl.appendChild(sb);
+    const sb = document.createElement('span'); sb.className = 'badge ' + statusBadgeClass(node.status); sb.textContent = node.status.replace(/_/g, ' '); badgesEl.appendChild(sb);
     const tb = document.createElement('span'); tb.className = 'badge badge-' + (node.type || 'task'); tb.textContent = node.type || 'task'; badgesEl.appendChild(tb);
+    styleTypeBadge(tb, node.type || 'task');
     const fmtSide = (v, d) => (v != null && isFinite(v)) ? v.toFixed(d) : '-';
     document.getElementById('m-pagerank').textContent = fmtSide(node.pagerank * 100, 2) + '%%';
     document.getElementById('m-prrank').textContent = '#' + (node.pagerank_rank || '-');
@@ -1349,15 +2081,20 @@ function selectNode(node) {
     slackEl.className = 'metric-value' + (node.slack === 0 ? ' highlight' : '');
     document.getElementById('m-indeg').textContent = node.in_degree ?? '-';
     document.getElementById('m-outdeg').textContent = node.out_degree ?? '-';
+    document.getElementById('m-level').textContent = topoLevelLabel(node);
     document.getElementById('node-detail').classList.add('visible');
     document.getElementById('no-selection').style.display = 'none';
+    hideMetricPopover();
+    renderMetricsExplain();
 }
 
 function clearSelection() {
     selectedNode = null;
     highlightedNodes = new Set();
+    if (blastDistances) { blastDistances = null; refreshBlastRendering(); }
     document.getElementById('node-detail').classList.remove('visible');
     document.getElementById('no-selection').style.display = 'block';
+    renderMetricsExplain();
     Graph.nodeColor(Graph.nodeColor());
     Graph.linkColor(Graph.linkColor());
 }
@@ -1369,65 +2106,238 @@ document.getElementById('search-input').oninput = e => {
     searchDebounce = setTimeout(() => performSearch(e.target.value), 150);
 };
 
+// Searchable fields in importance order (lower index ranks higher)
+function searchFields(n) {
+    return [n.id, n.title, (n.labels || []).join(' '), n.assignee || '',
+            n.description || '', n.design || '', n.notes || '', n.acceptance_criteria || '',
+            (n.commits || []).map(c => c.message || '').join('\n')];
+}
+
+// "...context around the first match..." with matches marked
+function searchSnippet(text, q) {
+    const idx = text.toLowerCase().indexOf(q);
+    const start = Math.max(0, idx - 30);
+    const end = Math.min(text.length, idx + q.length + 50);
+    const pattern = new RegExp(q.replace(/[.*+?^${}()|[\]\\]/g, '\\$&'), 'gi');
+    const snippet = text.substring(start, end);
+    let out = '', last = 0;
+    snippet.replace(pattern, (m, offset) => {
+        out += escapeHtml(snippet.slice(last, offset)) + '<mark>' + escapeHtml(m) + '</mark>';
+        last = offset + m.length;
+        return m;
+    });
+    return '...' + out + escapeHtml(snippet.slice(last)) + '...';
+}
+
+// Levenshtein distance, giving up (returns max + 1) once it must exceed max
+function boundedLevenshtein(a, b, max) {
+    if (Math.abs(a.length - b.length) > max) return max + 1;
+    let prev = new Array(b.length + 1);
+    for (let j = 0; j <= b.length; j++) prev[j] = j;
+    for (let i = 1; i <= a.length; i++) {
+        const cur = [i];
+        let rowMin = i;
+        for (let j = 1; j <= b.length; j++) {
+            const cost = a[i - 1] === b[j - 1] ? 0 : 1;
+            cur[j] = Math.min(prev[j] + 1, cur[j - 1] + 1, prev[j - 1] + cost);
+            if (cur[j] < rowMin) rowMin = cur[j];
+        }
+        if (rowMin > max) return max + 1;
+        prev = cur;
+    }
+    return prev[b.length];
+}
+
+// Lowercased word tokens per node and field, built on first fuzzy search
+let searchTokenCache = null;
+function searchTokens(n) {
+    if (!searchTokenCache) searchTokenCache = new Map();
+    let tokens = searchTokenCache.get(n.id);
+    if (!tokens) {
+        tokens = searchFields(n).map(f => Array.from(new Set(f.toLowerCase().split(/[^a-z0-9]+/).filter(Boolean))));
+        searchTokenCache.set(n.id, tokens);
+    }
+    return tokens;
+}
+
+// Each query term must be within the edit budget of some word in one field.
+// Returns { dist, field, word } for the best field, or null.
+function fuzzyMatch(n, terms) {
+    let best = null;
+    searchTokens(n).forEach((words, field) => {
+        let total = 0, firstWord = '';
+        for (const term of terms) {
+            const max = term.length <= 4 ? 1 : 2;
+            let termBest = max + 1, termWord = '';
+            for (const w of words) {
+                const d = boundedLevenshtein(term, w, max);
+                if (d < termBest) { termBest = d; termWord = w; if (d === 0) break; }
+            }
+            if (termBest > max) return;
+            total += termBest;
+            if (!firstWord) firstWord = termWord;
+        }
+        if (!best || total < best.dist || (total === best.dist && field < best.field)) {
+            best = { dist: total, field: field, word: firstWord };
+        }
+    });
+    return best;
+}
+
+// Exact substring matches rank first (by field importance); then, for queries
+// of 3+ characters, typo-tolerant matches ranked by edit distance and field.
+function rankSearchMatches(q) {
+    const exact = [], fuzzy = [];
+    const terms = q.split(/[^a-z0-9]+/).filter(Boolean);
+    DATA.nodes.forEach(n => {
+        const field = searchFields(n).findIndex(f => f.toLowerCase().includes(q));
+        if (field >= 0) { exact.push({ node: n, field: field }); return; }
+        if (q.length < 3 || terms.length === 0) return;
+        const m = fuzzyMatch(n, terms);
+        if (m) fuzzy.push({ node: n, field: m.field, dist: m.dist, word: m.word });
+    });
+    exact.sort((a, b) => a.field - b.field);
+    fuzzy.sort((a, b) => a.dist - b.dist || a.field - b.field);
+    return exact.concat(fuzzy);
+}
+
+// Results render in pages of SEARCH_PAGE_SIZE ("Show more" or scrolling to the
+// bottom loads the next page). A row is one of two fixed heights, depending on
+// whether it has a preview, so row offsets are known without measuring and
+// only the rows in view, plus a small overscan, are in the DOM however many
+// results are loaded.
+const SEARCH_PAGE_SIZE = 8;
+const SEARCH_ROW_HEIGHT = 112;      // ID, title and preview
+const SEARCH_ROW_HEIGHT_PLAIN = 72; // ID and title only
+const SEARCH_OVERSCAN = 4;
+let searchResults = null; // { q, ranked, shown, first, last, previews, tops }
+
 function performSearch(query) {
     const resultsEl = document.getElementById('search-results');
+    searchResults = null;
     if (!query || query.length < 2) {
         resultsEl.classList.remove('visible');
         applyFilters();
         return;
     }
     const q = query.toLowerCase();
-    const matches = DATA.nodes.filter(n => {
-        return n.id.toLowerCase().includes(q) ||
-               n.title.toLowerCase().includes(q) ||
-               (n.description || '').toLowerCase().includes(q) ||
-               (n.design || '').toLowerCase().includes(q) ||
-               (n.notes || '').toLowerCase().includes(q) ||
-               (n.acceptance_criteria || '').toLowerCase().includes(q) ||
-               (n.labels || []).some(l => l.toLowerCase().includes(q)) ||
-               (n.assignee || '').toLowerCase().includes(q);
-    }).slice(0, 8);
-
-    if (matches.length === 0) {
+    const ranked = rankSearchMatches(q);
+
+    if (ranked.length === 0) {
         resultsEl.innerHTML = '<div class="search-result-item">No results found</div>';
     } else {
-        resultsEl.innerHTML = matches.map(n => {
-            let preview = '';
-            const fields = [n.description, n.design, n.notes, n.acceptance_criteria];
-            for (const f of fields) {
-                if (f && f.toLowerCase().includes(q)) {
-                    const idx = f.toLowerCase().indexOf(q);
-                    const start = Math.max(0, idx - 30);
-                    const end = Math.min(f.length, idx + q.length + 50);
-                    preview = '...' + f.substring(start, end).replace(new RegExp(q, 'gi'), '<mark>$&</mark>') + '...';
-                    break;
-                }
-            }
-            return '<div class="search-result-item" data-id="' + n.id + '">' +
-                   '<div class="search-result-id">' + n.id + ' <span class="badge badge-' + n.status + '">' + n.status + '</span></div>' +
-                   '<div class="search-result-title">' + n.title + '</div>' +
-                   (preview ? '<div class="search-result-preview">' + preview + '</div>' : '') +
-                   '</div>';
-        }).join('');
+        searchResults = { q: q, ranked: ranked, shown: Math.min(SEARCH_PAGE_SIZE, ranked.length), first: -1, last: -1, previews: [], tops: [0] };
+        resultsEl.innerHTML = '<div class="search-results-count"></div>' +
+            '<div class="search-results-window"></div>' +
+            '<button type="button" class="search-more">Show more</button>';
+        resultsEl.scrollTop = 0;
     }
     resultsEl.classList.add('visible');
+    renderSearchWindow();
+}
 
-    resultsEl.querySelectorAll('.search-result-item[data-id]').forEach(item => {
-        item.onclick = () => {
-            const id = item.dataset.id;
-            const graphNodes = Graph.graphData().nodes;
-            const node = graphNodes.find(n => n.id === id);
-            if (node) {
-                selectNode(node);
-                Graph.centerAt(node.x, node.y, 500);
-                Graph.zoom(2.5, 500);
-            }
-            resultsEl.classList.remove('visible');
-            document.getElementById('search-input').value = '';
-        };
-    });
+// Preview for a ranked result: a matching snippet from the long-form fields or
+// a commit message, else the fuzzy word it matched
+function searchPreview(r, q) {
+    const n = r.node;
+    for (const f of [n.description, n.design, n.notes, n.acceptance_criteria]) {
+        if (f && f.toLowerCase().includes(q)) return searchSnippet(f, q);
+    }
+    // Commit messages: show which commit matched
+    const commit = (n.commits || []).find(c => (c.message || '').toLowerCase().includes(q));
+    if (commit) return '<span class="search-result-sha">' + escapeHtml(commit.short_sha) + '</span> ' + searchSnippet(commit.message, q);
+    if (r.word) return 'Did you mean <mark>' + escapeHtml(r.word) + '</mark>?';
+    return '';
 }
 
+// Build previews and row offsets for newly loaded rows: tops[i] is row i's
+// offset and tops[shown] the height of all loaded rows
+function layoutSearchRows() {
+    const s = searchResults;
+    for (let i = s.previews.length; i < s.shown; i++) {
+        const preview = searchPreview(s.ranked[i], s.q);
+        s.previews.push(preview);
+        s.tops.push(s.tops[i] + (preview ? SEARCH_ROW_HEIGHT : SEARCH_ROW_HEIGHT_PLAIN));
+    }
+}
+
+// Index of the loaded row that covers offset y
+function searchRowAt(y) {
+    const tops = searchResults.tops;
+    let lo = 0, hi = searchResults.shown - 1;
+    while (lo < hi) {
+        const mid = (lo + hi + 1) >> 1;
+        if (tops[mid] <= y) lo = mid; else hi = mid - 1;
+    }
+    return lo;
+}
+
+function searchResultRow(r, i) {
+    const n = r.node;
+    const preview = searchResults.previews[i];
+    const top = searchResults.tops[i], height = searchResults.tops[i + 1] - top;
+    return '<div class="search-result-item" data-id="' + escapeAttr(n.id) + '" style="top:' + top + 'px;height:' + height + 'px">' +
+           '<div class="search-result-id">' + escapeHtml(n.id) + ' <span class="badge badge-' + escapeAttr(n.status) + '">' + escapeHtml(n.status) + '</span></div>' +
+           '<div class="search-result-title">' + escapeHtml(n.title) + '</div>' +
+           (preview ? '<div class="search-result-preview">' + preview + '</div>' : '') +
+           '</div>';
+}
+
+// Render only the loaded rows that intersect the dropdown's viewport
+function renderSearchWindow() {
+    if (!searchResults) return;
+    const resultsEl = document.getElementById('search-results');
+    const windowEl = resultsEl.querySelector('.search-results-window');
+    const total = searchResults.ranked.length, shown = searchResults.shown;
+    resultsEl.querySelector('.search-results-count').textContent = 'Showing ' + shown + ' of ' + total;
+    resultsEl.querySelector('.search-more').style.display = shown < total ? '' : 'none';
+    layoutSearchRows();
+    windowEl.style.height = searchResults.tops[shown] + 'px';
+
+    const top = Math.max(0, resultsEl.scrollTop - (windowEl.offsetTop || 0));
+    const first = Math.max(0, searchRowAt(top) - SEARCH_OVERSCAN);
+    const last = Math.min(shown, searchRowAt(top + (resultsEl.clientHeight || 400)) + 1 + SEARCH_OVERSCAN);
+    if (first === searchResults.first && last === searchResults.last) return;
+    searchResults.first = first;
+    searchResults.last = last;
+    windowEl.innerHTML = searchResults.ranked.slice(first, last).map((r, i) => searchResultRow(r, first + i)).join('');
+}
+
+function showMoreSearchResults() {
+    if (!searchResults || searchResults.shown >= searchResults.ranked.length) return;
+    searchResults.shown = Math.min(searchResults.shown + SEARCH_PAGE_SIZE, searchResults.ranked.length);
+    renderSearchWindow();
+}
+
+document.getElementById('search-results').addEventListener('scroll', e => {
+    const el = e.target;
+    if (searchResults && el.scrollTop + el.clientHeight >= el.scrollHeight - SEARCH_ROW_HEIGHT) {
+        showMoreSearchResults();
+    } else {
+        renderSearchWindow();
+    }
+});
+
+document.getElementById('search-results').onclick = e => {
+    if (e.target.closest('.search-more')) {
+        showMoreSearchResults();
+        return;
+    }
+    const item = e.target.closest('.search-result-item[data-id]');
+    if (!item) return;
+    const id = item.dataset.id;
+    const graphNodes = Graph.graphData().nodes;
+    const node = graphNodes.find(n => n.id === id);
+    if (node) {
+        selectNode(node);
+        Graph.centerAt(node.x, node.y, 500);
+        Graph.zoom(2.5, 500);
+    }
+    e.currentTarget.classList.remove('visible');
+    searchResults = null;
+    document.getElementById('search-input').value = '';
+};
+
 // Close search on click outside
 document.addEventListener('click', e => {
     if (!e.target.closest('.search-container')) {
@@ -1439,6 +2349,9 @@ document.addEventListener('click', e => {
 let contextNode = null;
 function showContextMenu(node, event) {
     contextNode = node;
+    const collapseItem = document.getElementById('ctx-collapse');
+    collapseItem.style.display = childrenOf.has(node.id) ? '' : 'none';
+    collapseItem.textContent = collapsedEpics.has(node.id) ? '📂 Expand epic' : '📦 Collapse epic';
     const menu = document.getElementById('context-menu');
     menu.style.left = event.clientX + 'px';
     menu.style.top = event.clientY + 'px';
@@ -1451,14 +2364,52 @@ document.getElementById('ctx-deps').onclick = () => { if (contextNode) highlight
 document.getElementById('ctx-dependents').onclick = () => { if (contextNode) highlightDependencies(contextNode, 'dependents'); hideContextMenu(); };
 document.getElementById('ctx-connected').onclick = () => {
     if (contextNode) {
-        highlightedNodes = getConnectedNodes(contextNode.id, 3);
+        highlightedNodes = getConnectedNodes(contextNode.id, highlightDepth + 1);
+        lastHighlight = { anchor: contextNode.id, nodes: highlightedNodes };
         Graph.nodeColor(Graph.nodeColor());
         Graph.linkColor(Graph.linkColor());
         showToast(highlightedNodes.size + ' connected nodes highlighted');
     }
     hideContextMenu();
 };
+document.getElementById('ctx-compare').onclick = () => {
+    if (contextNode) addToComparison(contextNode);
+    hideContextMenu();
+};
+
+// Adds the node's most recent explicit highlight (deps/dependents/connected)
+// or, failing that, its hover neighborhood to the next free comparison group.
+function addToComparison(node) {
+    const nodes = lastHighlight && lastHighlight.anchor === node.id
+        ? new Set(lastHighlight.nodes)
+        : getConnectedNodes(node.id, highlightDepth);
+    const slot = compareGroups[0] === null ? 0 : 1;
+    if (slot === 1 && compareGroups[1] !== null) showToast('Replaced comparison group B');
+    compareGroups[slot] = nodes;
+    highlightedNodes = new Set();
+    refreshComparison();
+    showToast('Group ' + (slot === 0 ? 'A' : 'B') + ': ' + nodes.size + ' nodes (' + node.id + ')');
+}
+function clearComparison() {
+    compareGroups = [null, null];
+    refreshComparison();
+}
+function refreshComparison() {
+    let a = 0, b = 0, both = 0;
+    DATA.nodes.forEach(n => {
+        const g = compareGroupOf(n.id);
+        if (g === 'a') a++; else if (g === 'b') b++; else if (g === 'both') both++;
+    });
+    document.getElementById('compare-count-a').textContent = a + both;
+    document.getElementById('compare-count-b').textContent = b + both;
+    document.getElementById('compare-count-both').textContent = both;
+    document.getElementById('compare-legend').classList.toggle('visible', compareActive());
+    Graph.nodeColor(Graph.nodeColor());
+    Graph.linkColor(Graph.linkColor());
+}
+document.getElementById('compare-clear').onclick = clearComparison;
 document.getElementById('ctx-copy').onclick = () => { if (contextNode) { navigator.clipboard.writeText(contextNode.id); showToast('Copied: ' + contextNode.id); } hideContextMenu(); };
+document.getElementById('ctx-mermaid').onclick = () => { if (contextNode) copySubgraphMermaid(contextNode); hideContextMenu(); };
 document.getElementById('ctx-path').onclick = () => { showToast('Click another node to find path'); pathStartNode = contextNode; hideContextMenu(); };
 
 let pathStartNode = null;
@@ -1487,7 +2438,12 @@ function highlightPath(path) {
     showToast('Path: ' + path.length + ' nodes');
 }
 
+let lastNodeClick = { id: null, time: 0 };
 function handleNodeClick(node) {
+    // force-graph has no double-click event: a second click within 350ms on a parent toggles its collapse
+    const now = Date.now(), isDouble = lastNodeClick.id === node.id && now - lastNodeClick.time < 350;
+    lastNodeClick = { id: node.id, time: isDouble ? 0 : now };
+    if (isDouble && childrenOf.has(node.id) && !pathStartNode) { toggleEpicCollapse(node.id); return; }
     if (pathStartNode) {
         const path = findPath(pathStartNode.id, node.id);
         if (path) highlightPath(path);
@@ -1495,6 +2451,7 @@ function handleNodeClick(node) {
         pathStartNode = null;
     } else {
         selectNode(node);
+        if (blastMode) showBlastRadius(node);
     }
 }
 
@@ -1507,15 +2464,99 @@ function highlightDependencies(node, type) {
         if (type === 'dependents' && tgt === node.id) connected.add(src);
     });
     highlightedNodes = connected;
+    lastHighlight = { anchor: node.id, nodes: connected };
     Graph.nodeColor(Graph.nodeColor());
     Graph.linkColor(Graph.linkColor());
     updateVisibleCount();
     showToast(connected.size + ' nodes shown');
 }
 
+// Blast radius: clicking a bead shades the beads it can ripple into, by hop
+// distance along dependency links. Downstream follows dependents (links point
+// from a dependent to its blocker), upstream follows prerequisites.
+let blastMode = null; // null | 'downstream' | 'upstream'
+let blastDistances = null; // Map of bead id -> hops from the clicked bead
+let blastMaxDistance = 0;
+const BLAST_NEAR = [239, 68, 68], BLAST_FAR = [253, 224, 71];
+function blastActive() { return blastDistances !== null; }
+function blastColor(d) {
+    const t = blastMaxDistance > 0 ? d / blastMaxDistance : 0;
+    return '#' + BLAST_NEAR.map((c, i) => Math.round(c + (BLAST_FAR[i] - c) * t).toString(16).padStart(2, '0')).join('');
+}
+// Distance of the far end of a link that lies on a blast path, or null.
+function blastLinkDistance(src, tgt) {
+    const [from, to] = blastMode === 'upstream' ? [src, tgt] : [tgt, src];
+    const df = blastDistances.get(from), dt = blastDistances.get(to);
+    return df !== undefined && dt === df + 1 ? dt : null;
+}
+function computeBlastRadius(id, direction) {
+    const next = {};
+    DATA.links.forEach(l => {
+        const src = typeof l.source === 'object' ? l.source.id : l.source;
+        const tgt = typeof l.target === 'object' ? l.target.id : l.target;
+        const [from, to] = direction === 'upstream' ? [src, tgt] : [tgt, src];
+        (next[from] = next[from] || []).push(to);
+    });
+    const dist = new Map([[id, 0]]);
+    const queue = [id];
+    while (queue.length > 0) {
+        const current = queue.shift();
+        (next[current] || []).forEach(n => {
+            if (!dist.has(n)) { dist.set(n, dist.get(current) + 1); queue.push(n); }
+        });
+    }
+    return dist;
+}
+// Why blocked: for each open blocker of a bead, whether it can be worked on
+// now and, if not, the nearest actionable beads up its chain. The export
+// computes it on the full graph, so blockers a --max-nodes sample left out
+// still count.
+function whyBlocked(node) { return (node && node.why_blocked) || []; }
+function showBlastRadius(node) {
+    blastDistances = computeBlastRadius(node.id, blastMode);
+    blastMaxDistance = Math.max(0, ...blastDistances.values());
+    lastHighlight = { anchor: node.id, nodes: new Set(blastDistances.keys()) };
+    highlightedNodes = new Set();
+    refreshBlastRendering();
+    const what = blastMode === 'upstream' ? 'prerequisites' : 'dependents';
+    showToast((blastDistances.size - 1) + ' ' + what + ' within ' + blastMaxDistance + ' hops');
+}
+function refreshBlastRendering() {
+    Graph.nodeColor(Graph.nodeColor());
+    Graph.linkColor(Graph.linkColor());
+    Graph.linkWidth(Graph.linkWidth());
+    Graph.linkDirectionalArrowColor(Graph.linkDirectionalArrowColor());
+}
+function setBlastMode(mode) {
+    blastMode = mode;
+    if (mode && pathFinderMode) togglePathFinder();
+    const btn = document.getElementById('btn-blast');
+    btn.classList.toggle('active', mode !== null);
+    const banner = document.getElementById('blast-banner');
+    banner.classList.toggle('visible', mode !== null);
+    banner.textContent = mode === 'upstream'
+        ? '💥 Blast radius (upstream): click a bead to shade its prerequisites, nearest first (B: off, Esc to exit)'
+        : '💥 Blast radius (downstream): click a bead to shade what depends on it, nearest first (B: upstream, Esc to exit)';
+    if (!mode) { blastDistances = null; refreshBlastRendering(); return; }
+    if (selectedNode) showBlastRadius(selectedNode);
+}
+function cycleBlastMode() { setBlastMode(blastMode === null ? 'downstream' : blastMode === 'downstream' ? 'upstream' : null); }
+document.getElementById('btn-blast').onclick = cycleBlastMode;
+
 // Filters
 let statusFilter = '', typeFilter = '';
 let currentVisibilityFilter = () => true;
+// Edge-type filter: the toolbar select or a click on an Edge Types legend entry
+function setEdgeTypeFilter(t) {
+    edgeTypeFilter = t;
+    document.getElementById('filter-edge-type').value = t;
+    document.querySelectorAll('.edge-legend-item').forEach(el => el.classList.toggle('active', el.dataset.edgeType === t));
+    Graph.linkVisibility(Graph.linkVisibility());
+}
+document.getElementById('filter-edge-type').onchange = e => setEdgeTypeFilter(e.target.value);
+document.querySelectorAll('.edge-legend-item').forEach(el => {
+    el.onclick = () => setEdgeTypeFilter(edgeTypeFilter === el.dataset.edgeType ? '' : el.dataset.edgeType);
+});
 document.getElementById('filter-status').onchange = e => { statusFilter = e.target.value; applyFilters(); };
 document.getElementById('filter-type').onchange = e => { typeFilter = e.target.value; applyFilters(); };
 
@@ -1546,25 +2587,34 @@ document.getElementById('view-mode').onchange = e => {
 // Size metric
 document.getElementById('size-by').onchange = e => {
     sizeMetric = e.target.value;
-    document.getElementById('heatmap-metric').textContent = { pagerank: 'PageRank', betweenness: 'Betweenness', critical: 'Critical Path', indegree: 'In-Degree' }[sizeMetric];
+    document.getElementById('heatmap-metric').textContent = METRIC_LABELS[sizeMetric];
     Graph.nodeVal(n => getNodeSize(n));
     if (heatmapMode) Graph.nodeColor(n => getHeatmapColor(n));
 };
+document.getElementById('color-by').onchange = e => setColorBy(e.target.value);
 
 // Controls
 document.getElementById('btn-fit').onclick = () => Graph.zoomToFit(400, 50);
 document.getElementById('btn-reset').onclick = () => {
     document.getElementById('filter-status').value = '';
     document.getElementById('filter-type').value = '';
+    document.getElementById('filter-priority-min').value = '';
+    document.getElementById('filter-priority-max').value = '';
+    document.getElementById('filter-label').value = '';
     document.getElementById('search-input').value = '';
     document.getElementById('view-mode').value = 'force';
     document.getElementById('size-by').value = 'pagerank';
-    statusFilter = ''; typeFilter = ''; sizeMetric = 'pagerank'; heatmapMode = false;
-    highlightedNodes = new Set();
-    Graph.dagMode(null); Graph.nodeVisibility(() => true); Graph.nodeVal(n => getNodeSize(n));
-    Graph.nodeColor(n => STATUS_COLORS[n.status] || '#555577');
-    Graph.linkColor(l => l.critical ? '#ec489980' : '#44475a40');
-    clearSelection(); hideHoverPanel(); Graph.zoomToFit(400, 50); updateVisibleCount();
+    statusFilter = ''; typeFilter = ''; priorityMin = null; priorityMax = null; labelFilter = null; currentVisibilityFilter = () => true;
+    labelInput.classList.remove('invalid'); labelInput.title = labelInputTitle;
+    sizeMetric = 'pagerank'; heatmapMode = false;
+    document.getElementById('heatmap-metric').textContent = METRIC_LABELS[sizeMetric];
+    highlightedNodes = new Set(); setHighlightDepth(2); setBlastMode(null);
+    Graph.dagMode(null); applyFilters(); Graph.nodeVal(n => getNodeSize(n));
+    setColorBy('status');
+    Graph.nodeColor(n => nodeFillColor(n));
+    Graph.linkColor(l => l.critical ? CRITICAL_COLOR + '80' : edgeBaseColor(l));
+    setEdgeTypeFilter('');
+    clearSelection(); hideHoverPanel(); clearNavHistory(); clearComparison(); Graph.zoomToFit(400, 50); updateVisibleCount();
     document.getElementById('heatmap-legend').classList.remove('heatmap-active');
     document.getElementById('top-nodes-panel').classList.remove('visible');
     document.getElementById('triage-panel').style.display = 'none';
@@ -1578,36 +2628,146 @@ document.getElementById('btn-heatmap').onclick = () => {
     heatmapMode = !heatmapMode;
     document.getElementById('btn-heatmap').classList.toggle('active', heatmapMode);
     document.getElementById('heatmap-legend').classList.toggle('heatmap-active', heatmapMode);
-    Graph.nodeColor(n => heatmapMode ? getHeatmapColor(n) : STATUS_COLORS[n.status] || '#555577');
+    Graph.nodeColor(n => nodeFillColor(n));
 };
 
 // Triage panel
+function renderTriageList() {
+    const list = document.getElementById('triage-list');
+    const recs = (DATA.triage && DATA.triage.recommendations) || [];
+    list.innerHTML = recs.slice(0, 5).map(r => {
+        const score = (r.score != null && isFinite(r.score)) ? r.score.toFixed(2) : '-';
+        const reason = (r.reasons && r.reasons.length > 0) ? r.reasons[0] : '';
+        return '<div class="triage-item" data-id="' + escapeAttr(r.id || '') + '">' +
+            '<div class="triage-item-header"><span class="triage-item-id">' + escapeHtml(r.id || '-') + '</span><span class="triage-item-score">' + score + '</span></div>' +
+            '<div class="triage-item-title">' + escapeHtml(r.title || '') + '</div>' +
+            '<div class="triage-item-reason">' + escapeHtml(reason) + '</div></div>';
+    }).join('');
+    list.querySelectorAll('.triage-item').forEach(item => {
+        item.onclick = () => {
+            const graphNodes = Graph.graphData().nodes;
+            const node = graphNodes.find(n => n.id === item.dataset.id);
+            if (node) { selectNode(node); Graph.centerAt(node.x, node.y, 500); Graph.zoom(2.5, 500); }
+        };
+    });
+}
 document.getElementById('btn-triage').onclick = () => {
     const panel = document.getElementById('triage-panel');
     const btn = document.getElementById('btn-triage');
     const visible = panel.style.display === 'none';
     panel.style.display = visible ? 'block' : 'none';
     btn.classList.toggle('active', visible);
-    if (visible && DATA.triage && DATA.triage.recommendations) {
-        const list = document.getElementById('triage-list');
-        list.innerHTML = DATA.triage.recommendations.slice(0, 5).map(r => {
-            const score = (r.score != null && isFinite(r.score)) ? r.score.toFixed(2) : '-';
-            const reason = (r.reasons && r.reasons.length > 0) ? r.reasons[0] : '';
-            return '<div class="triage-item" data-id="' + (r.id || '') + '">' +
-                '<div class="triage-item-header"><span class="triage-item-id">' + (r.id || '-') + '</span><span class="triage-item-score">' + score + '</span></div>' +
-                '<div class="triage-item-title">' + (r.title || '') + '</div>' +
-                '<div class="triage-item-reason">' + reason + '</div></div>';
-        }).join('');
-        list.querySelectorAll('.triage-item').forEach(item => {
-            item.onclick = () => {
-                const graphNodes = Graph.graphData().nodes;
-                const node = graphNodes.find(n => n.id === item.dataset.id);
-                if (node) { selectNode(node); Graph.centerAt(node.x, node.y, 500); Graph.zoom(2.5, 500); }
-            };
-        });
-    }
+    if (visible && DATA.triage) renderTriageList();
+};
+
+// Markdown checklist of the triage recommendations (same format as --export-todo)
+function singleLine(s) { return String(s || '').split(/\s+/).filter(Boolean).join(' '); }
+function triageChecklist() {
+    const recs = (DATA.triage && DATA.triage.recommendations) || [];
+    let md = '## Triage todo\n\n';
+    if (recs.length === 0) md += '_No recommendations._\n';
+    recs.forEach(r => {
+        md += '- [ ] **' + r.id + '** ' + singleLine(r.title);
+        if (r.reasons && r.reasons.length > 0) md += ' — ' + singleLine(r.reasons[0]);
+        md += '\n';
+    });
+    if (DATA.data_hash) md += '\n---\n_Generated by bv from data hash ' + String.fromCharCode(96) + DATA.data_hash + String.fromCharCode(96) + '_\n';
+    return md;
+}
+document.getElementById('btn-copy-checklist').onclick = () => {
+    navigator.clipboard.writeText(triageChecklist()).then(
+        () => showToast('Copied triage checklist'),
+        () => showToast('Clipboard unavailable'));
 };
 
+// Mermaid snippet of a highlighted neighborhood, in the same format as
+// --export-graph *.mmd (sorted nodes and edges, entity-escaped labels).
+const MERMAID_TITLE_WIDTH = 30, MERMAID_TITLE_LINES = 3;
+const MERMAID_ESCAPES = { '#': '#35;', '"': '#quot;', '&': '#amp;', '<': '#lt;', '>': '#gt;', '[': '#91;', ']': '#93;', '{': '#123;', '}': '#125;', '|': '#124;' };
+MERMAID_ESCAPES[String.fromCharCode(96)] = '#96;';
+function stripControl(s) { return String(s || '').replace(/[\u0000-\u001f\u007f-\u009f]/g, ' '); }
+function escapeMermaidLabel(s) { return Array.from(stripControl(s)).map(c => MERMAID_ESCAPES[c] || c).join(''); }
+function wrapMermaidTitle(title) {
+    const lines = [];
+    let current = [];
+    const flush = () => { if (current.length > 0) { lines.push(current.join('')); current = []; } };
+    stripControl(title).split(/\s+/).filter(Boolean).forEach(word => {
+        let w = Array.from(word);
+        while (w.length > MERMAID_TITLE_WIDTH) {
+            flush();
+            lines.push(w.slice(0, MERMAID_TITLE_WIDTH).join(''));
+            w = w.slice(MERMAID_TITLE_WIDTH);
+        }
+        if (current.length > 0 && current.length + 1 + w.length > MERMAID_TITLE_WIDTH) flush();
+        if (current.length > 0) current.push(' ');
+        current = current.concat(w);
+    });
+    flush();
+    if (lines.length > MERMAID_TITLE_LINES) {
+        lines.length = MERMAID_TITLE_LINES;
+        lines[MERMAID_TITLE_LINES - 1] = Array.from(lines[MERMAID_TITLE_LINES - 1]).slice(0, MERMAID_TITLE_WIDTH - 3).join('') + '...';
+    }
+    return lines.map(escapeMermaidLabel).join('<br/>');
+}
+function mermaidStatusClass(status) {
+    return { open: 'open', in_progress: 'inprogress', blocked: 'blocked', closed: 'closed', tombstone: 'tombstone' }[status] || 'other';
+}
+function fnv32aHex(s) {
+    let h = 0x811c9dc5;
+    new TextEncoder().encode(s).forEach(b => { h ^= b; h = Math.imul(h, 0x01000193) >>> 0; });
+    return h.toString(16);
+}
+function subgraphMermaid(ids) {
+    const byId = new Map(DATA.nodes.map(n => [n.id, n]));
+    const nodes = [...ids].filter(id => byId.has(id)).sort().map(id => byId.get(id));
+    const safeIds = new Map(), used = new Set();
+    nodes.forEach(n => {
+        const base = Array.from(n.id).filter(c => /[\p{L}\p{N}_-]/u.test(c)).join('') || 'node';
+        const safe = used.has(base) ? base + '_' + fnv32aHex(n.id) : base;
+        used.add(safe);
+        safeIds.set(n.id, safe);
+    });
+    let out = 'graph TD\n' +
+        '    classDef open fill:#50FA7B,stroke:#333,color:#000\n' +
+        '    classDef inprogress fill:#8BE9FD,stroke:#333,color:#000\n' +
+        '    classDef blocked fill:#FF5555,stroke:#333,color:#000\n' +
+        '    classDef closed fill:#6272A4,stroke:#333,color:#fff\n' +
+        '    classDef tombstone fill:#44475A,stroke:#333,color:#ccc,stroke-dasharray:3 3\n' +
+        '    classDef other fill:#F8F8F2,stroke:#333,color:#000\n\n';
+    nodes.forEach(n => {
+        let label = escapeMermaidLabel(n.id);
+        const title = wrapMermaidTitle(n.title);
+        if (title) label += '<br/>' + title;
+        out += '    ' + safeIds.get(n.id) + '["' + label + '"]\n';
+        out += '    class ' + safeIds.get(n.id) + ' ' + mermaidStatusClass(n.status) + '\n';
+    });
+    out += '\n';
+    const cmp = (a, b) => (a < b ? -1 : a > b ? 1 : 0);
+    DATA.links
+        .map(l => ({
+            from: typeof l.source === 'object' ? l.source.id : l.source,
+            to: typeof l.target === 'object' ? l.target.id : l.target,
+            blocks: l.type === 'blocks'
+        }))
+        .filter(e => safeIds.has(e.from) && safeIds.has(e.to))
+        .sort((a, b) => cmp(a.from, b.from) || cmp(a.to, b.to) || (b.blocks - a.blocks))
+        .forEach(e => { out += '    ' + safeIds.get(e.from) + ' ' + (e.blocks ? '==>' : '-.->') + ' ' + safeIds.get(e.to) + '\n'; });
+    return out;
+}
+// The live highlight wins; otherwise the last explicit highlight, then the
+// anchor node's 2-hop neighborhood.
+function copySubgraphMermaid(anchor) {
+    let ids = highlightedNodes;
+    if (ids.size === 0 && lastHighlight && (!anchor || lastHighlight.anchor === anchor.id)) ids = lastHighlight.nodes;
+    if (ids.size === 0 && anchor) ids = getConnectedNodes(anchor.id, highlightDepth);
+    if (ids.size === 0) { showToast('Highlight or select some nodes first'); return; }
+    const count = ids.size;
+    navigator.clipboard.writeText(subgraphMermaid(ids)).then(
+        () => showToast('Copied ' + count + ' nodes as Mermaid'),
+        () => showToast('Clipboard unavailable'));
+}
+document.getElementById('btn-mermaid').onclick = () => copySubgraphMermaid(selectedNode);
+
 // Top nodes panel
 document.getElementById('btn-top').onclick = () => {
     const panel = document.getElementById('top-nodes-panel');
@@ -1615,7 +2775,7 @@ document.getElementById('btn-top').onclick = () => {
     document.getElementById('btn-top').classList.toggle('active', visible);
     if (visible) {
         const sorted = [...DATA.nodes].sort((a, b) => (b.pagerank || 0) - (a.pagerank || 0)).slice(0, 10);
-        panel.innerHTML = sorted.map((n, i) => '<div class="top-node-item" data-id="' + n.id + '"><span class="rank">#' + (i+1) + '</span><span>' + n.id + '</span></div>').join('');
+        panel.innerHTML = sorted.map((n, i) => '<div class="top-node-item" data-id="' + escapeAttr(n.id) + '"><span class="rank">#' + (i+1) + '</span><span>' + escapeHtml(n.id) + '</span></div>').join('');
         panel.querySelectorAll('.top-node-item').forEach(el => {
             el.onclick = () => {
                 const graphNodes = Graph.graphData().nodes;
@@ -1639,16 +2799,69 @@ function showToast(msg) {
     setTimeout(() => toast.classList.remove('visible'), 2500);
 }
 
-// Light/Dark mode toggle
+// Light/Dark mode: a saved toggle wins, otherwise the export theme ('auto' follows the OS)
+const colorSchemeQuery = window.matchMedia ? window.matchMedia('(prefers-color-scheme: light)') : null;
 let isDarkMode = true;
-function toggleLightMode() {
-    isDarkMode = !isDarkMode;
+function themeLinkColor() { return isDarkMode ? '#44475a40' : '#8888aa80'; }
+function applyTheme(dark) {
+    isDarkMode = dark;
     document.body.classList.toggle('light-mode', !isDarkMode);
     const btn = document.getElementById('btn-theme');
     btn.textContent = isDarkMode ? '☀️' : '🌙';
     btn.title = isDarkMode ? 'Switch to light mode (L)' : 'Switch to dark mode (L)';
+    Graph.linkColor(Graph.linkColor());
+    updateMinimap();
+}
+function toggleLightMode() {
+    applyTheme(!isDarkMode);
     localStorage.setItem('bv-graph-theme', isDarkMode ? 'dark' : 'light');
 }
+function initialThemeIsDark() {
+    const saved = localStorage.getItem('bv-graph-theme');
+    if (saved === 'light' || saved === 'dark') return saved === 'dark';
+    if (EXPORT_THEME === 'auto') return !(colorSchemeQuery && colorSchemeQuery.matches);
+    return EXPORT_THEME !== 'light';
+}
+if (colorSchemeQuery && colorSchemeQuery.addEventListener) {
+    colorSchemeQuery.addEventListener('change', e => {
+        if (EXPORT_THEME === 'auto' && !localStorage.getItem('bv-graph-theme')) applyTheme(!e.matches);
+    });
+}
+
+// Animations: a saved choice wins, otherwise the export default unless the OS asks for reduced motion
+function toggleAnimations() {
+    animationsEnabled = !animationsEnabled;
+    localStorage.setItem('bv-graph-animations', animationsEnabled ? 'on' : 'off');
+    applyAnimationSetting();
+}
+function applyAnimationSetting() {
+    document.body.classList.toggle('no-animation', !animationsEnabled);
+    Graph.linkDirectionalParticles(Graph.linkDirectionalParticles());
+    const btn = document.getElementById('btn-motion');
+    btn.classList.toggle('active', !animationsEnabled);
+    btn.title = animationsEnabled ? 'Disable animations (A)' : 'Enable animations (A)';
+}
+document.getElementById('btn-motion').onclick = toggleAnimations;
+
+// Color palette: a saved choice wins, otherwise the export default (--palette)
+function applyPalette(name) {
+    activePalette = PALETTES[name] ? name : 'default';
+    const p = PALETTES[activePalette];
+    Object.assign(STATUS_COLORS, p.status);
+    p.priority.forEach((c, i) => PRIORITY_COLORS[i] = c);
+    Object.assign(TYPE_COLORS, PALETTES['default'].type, p.type);
+    document.body.classList.toggle('palette-cb', activePalette === 'cb-safe');
+    const btn = document.getElementById('btn-palette');
+    btn.classList.toggle('active', activePalette === 'cb-safe');
+    btn.title = activePalette === 'cb-safe' ? 'Use default palette (C)' : 'Use colorblind-safe palette (C)';
+    Graph.nodeColor(Graph.nodeColor());
+    updateMinimap();
+}
+function togglePalette() {
+    applyPalette(activePalette === 'cb-safe' ? 'default' : 'cb-safe');
+    localStorage.setItem('bv-graph-palette', activePalette);
+}
+document.getElementById('btn-palette').onclick = togglePalette;
 
 // Recently viewed nodes
 const recentlyViewed = [];
@@ -1663,7 +2876,7 @@ function addToRecent(node) {
 function updateRecentPanel() {
     const list = document.getElementById('recent-list');
     list.innerHTML = recentlyViewed.map(n =>
-        '<div class="recent-item" data-id="' + n.id + '"><span class="recent-id">' + n.id + '</span></div>'
+        '<div class="recent-item" data-id="' + escapeAttr(n.id) + '"><span class="recent-id">' + escapeHtml(n.id) + '</span></div>'
     ).join('');
     list.querySelectorAll('.recent-item').forEach(el => {
         el.onclick = () => {
@@ -1679,6 +2892,49 @@ document.getElementById('btn-recent').onclick = () => {
     document.getElementById('btn-recent').classList.toggle('active', visible);
 };
 
+// Selection history (back/forward through drilled beads)
+let navHistory = [];
+let navIndex = -1;
+let navReplaying = false;
+const MAX_NAV_HISTORY = 50;
+const MAX_BREADCRUMBS = 5;
+function pushNavHistory(node) {
+    if (navReplaying || (navIndex >= 0 && navHistory[navIndex] === node.id)) return;
+    navHistory = navHistory.slice(0, navIndex + 1);
+    navHistory.push(node.id);
+    if (navHistory.length > MAX_NAV_HISTORY) navHistory.shift();
+    navIndex = navHistory.length - 1;
+    renderBreadcrumbs();
+}
+function navigateTo(index) {
+    if (index < 0 || index >= navHistory.length || index === navIndex) return;
+    const node = Graph.graphData().nodes.find(n => n.id === navHistory[index]);
+    if (!node) return;
+    navIndex = index;
+    navReplaying = true;
+    selectNode(node);
+    navReplaying = false;
+    Graph.centerAt(node.x, node.y, 500);
+    renderBreadcrumbs();
+}
+function navigateHistory(delta) { navigateTo(navIndex + delta); }
+function clearNavHistory() { navHistory = []; navIndex = -1; renderBreadcrumbs(); }
+function renderBreadcrumbs() {
+    const el = document.getElementById('nav-breadcrumbs');
+    el.classList.toggle('visible', navHistory.length > 1);
+    document.getElementById('nav-back').disabled = navIndex <= 0;
+    document.getElementById('nav-forward').disabled = navIndex >= navHistory.length - 1;
+    const start = Math.max(0, navIndex - MAX_BREADCRUMBS + 1);
+    const trail = document.getElementById('nav-trail');
+    trail.innerHTML = (start > 0 ? '<span class="nav-sep">… › </span>' : '') +
+        navHistory.slice(start, navIndex + 1).map((id, i) =>
+            '<span class="nav-crumb' + (start + i === navIndex ? ' current' : '') + '" data-index="' + (start + i) + '">' + escapeHtml(id) + '</span>'
+        ).join('<span class="nav-sep"> › </span>');
+    trail.querySelectorAll('.nav-crumb').forEach(c => { c.onclick = () => navigateTo(parseInt(c.dataset.index, 10)); });
+}
+document.getElementById('nav-back').onclick = () => navigateHistory(-1);
+document.getElementById('nav-forward').onclick = () => navigateHistory(1);
+
 // Path finder mode
 let pathFinderMode = false;
 let pathFinderStart = null;
@@ -1686,6 +2942,7 @@ function togglePathFinder() {
     pathFinderMode = !pathFinderMode;
     document.getElementById('btn-path').classList.toggle('active', pathFinderMode);
     document.getElementById('pathfinder-banner').classList.toggle('visible', pathFinderMode);
+    if (pathFinderMode && blastMode) setBlastMode(null);
     if (!pathFinderMode) { pathFinderStart = null; }
     else if (selectedNode) { pathFinderStart = selectedNode; showToast('Now click destination node'); }
 }
@@ -1727,40 +2984,151 @@ document.getElementById('btn-help').onclick = toggleHelp;
 document.getElementById('help-close').onclick = toggleHelp;
 document.getElementById('help-overlay').onclick = e => { if (e.target.id === 'help-overlay') toggleHelp(); };
 
-// Priority filter
-let priorityFilter = '';
-document.getElementById('filter-priority').onchange = e => {
-    priorityFilter = e.target.value;
+// Priority range filter; moving one end past the other drags it along
+let priorityMin = null, priorityMax = null;
+const priorityMinSelect = document.getElementById('filter-priority-min');
+const priorityMaxSelect = document.getElementById('filter-priority-max');
+function readPriorityRange(changed) {
+    priorityMin = priorityMinSelect.value === '' ? null : parseInt(priorityMinSelect.value, 10);
+    priorityMax = priorityMaxSelect.value === '' ? null : parseInt(priorityMaxSelect.value, 10);
+    if (priorityMin !== null && priorityMax !== null && priorityMin > priorityMax) {
+        if (changed === priorityMinSelect) { priorityMax = priorityMin; priorityMaxSelect.value = String(priorityMax); }
+        else { priorityMin = priorityMax; priorityMinSelect.value = String(priorityMin); }
+    }
     applyFilters();
-};
+}
+priorityMinSelect.onchange = () => readPriorityRange(priorityMinSelect);
+priorityMaxSelect.onchange = () => readPriorityRange(priorityMaxSelect);
 
-// Label filter - populate from data
+// Label filter: an exact label, a glob where * also crosses "/" (team/*),
+// or a /regex/, matched the same way as bv --label. JS has no inline flags,
+// so a regex's leading Go-style (?i), (?m) or (?s) becomes a RegExp flag.
+// Suggestions list every label plus a glob per hierarchical prefix.
 const allLabels = new Set();
 DATA.nodes.forEach(n => (n.labels || []).forEach(l => allLabels.add(l)));
-const labelSelect = document.getElementById('filter-label');
-[...allLabels].sort().forEach(l => {
+const labelGroups = new Set();
+allLabels.forEach(l => { const i = l.lastIndexOf('/'); if (i > 0) labelGroups.add(l.slice(0, i) + '/*'); });
+const labelInput = document.getElementById('filter-label');
+const labelInputTitle = labelInput.title;
+[...labelGroups].sort().concat([...allLabels].sort()).forEach(l => {
     const opt = document.createElement('option');
-    opt.value = l; opt.textContent = l;
-    labelSelect.appendChild(opt);
+    opt.value = l;
+    document.getElementById('filter-label-options').appendChild(opt);
 });
-let labelFilter = '';
-labelSelect.onchange = e => {
-    labelFilter = e.target.value;
+function compileLabelPattern(p) {
+    p = p.trim();
+    if (!p) return null;
+    if (p.length >= 2 && p[0] === '/' && p[p.length - 1] === '/') {
+        if (p.length === 2) throw new Error('regex is empty');
+        const body = p.slice(1, -1), flags = /^\(\?([ims]+)\)/.exec(body);
+        const re = flags ? new RegExp(body.slice(flags[0].length), [...new Set(flags[1])].join('')) : new RegExp(body);
+        return l => re.test(l);
+    }
+    if (/[*?[]/.test(p)) {
+        let src = '';
+        for (let i = 0; i < p.length; i++) {
+            const c = p[i];
+            if (c === '*') src += '.*';
+            else if (c === '?') src += '.';
+            else if (c === '[') {
+                const end = p.indexOf(']', i + 1);
+                if (end < 0) throw new Error('unclosed [ at offset ' + i);
+                if (end === i + 1) throw new Error('empty [] at offset ' + i);
+                let cls = p.slice(i + 1, end);
+                if (cls[0] === '!') cls = '^' + cls.slice(1);
+                src += '[' + cls.replace(/\\/g, '\\\\') + ']';
+                i = end;
+            } else src += c.replace(/[.+^${}()|\]\\\/]/g, '\\$&');
+        }
+        const re = new RegExp('^' + src + '$', 'i');
+        return l => re.test(l);
+    }
+    return l => l === p;
+}
+let labelFilter = null;
+labelInput.oninput = () => {
+    try {
+        labelFilter = compileLabelPattern(labelInput.value);
+        labelInput.classList.remove('invalid');
+        labelInput.title = labelInputTitle;
+    } catch (err) {
+        // Leave the graph unfiltered rather than hide everything mid-typing
+        labelFilter = null;
+        labelInput.classList.add('invalid');
+        labelInput.title = 'Invalid label pattern: ' + err.message;
+    }
     applyFilters();
 };
 
 // Combined filter function
 function applyFilters() {
-    Graph.nodeVisibility(n => {
+    currentVisibilityFilter = n => {
+        if (collapsedOwner.has(n.id)) return false; // Folded into a collapsed epic
         if (statusFilter && n.status !== statusFilter) return false;
         if (typeFilter && n.type !== typeFilter) return false;
-        if (priorityFilter && n.priority !== parseInt(priorityFilter)) return false;
-        if (labelFilter && !(n.labels || []).includes(labelFilter)) return false;
+        if (priorityMin !== null && n.priority < priorityMin) return false;
+        if (priorityMax !== null && n.priority > priorityMax) return false;
+        if (labelFilter && !(n.labels || []).some(labelFilter)) return false;
         return true;
-    });
+    };
+    Graph.nodeVisibility(currentVisibilityFilter);
     updateVisibleCount();
 }
 
+// Epic collapse: collapsing a parent (double-click or context menu) hides its
+// parent-child subtree behind a count badge and reroutes the subtree's other
+// edges to the parent. The collapsed set persists with the other view settings.
+const parentOf = new Map(), childrenOf = new Map();
+function indexParents() {
+    parentOf.clear(); childrenOf.clear();
+    DATA.nodes.forEach(n => { if (n.parent) parentOf.set(n.id, n.parent); });
+    parentOf.forEach((p, id) => { if (!childrenOf.has(p)) childrenOf.set(p, []); childrenOf.get(p).push(id); });
+}
+indexParents();
+// The outermost collapsed ancestor of id, so nested collapsed epics fold into the outer one
+function collapsedAncestor(id) {
+    let owner = null;
+    const seen = new Set([id]);
+    for (let p = parentOf.get(id); p && !seen.has(p); p = parentOf.get(p)) {
+        seen.add(p);
+        if (collapsedEpics.has(p)) owner = p;
+    }
+    return owner;
+}
+function applyEpicCollapse() {
+    collapsedOwner = new Map(); collapsedCounts = new Map();
+    DATA.nodes.forEach(n => {
+        const owner = collapsedAncestor(n.id);
+        if (!owner) return;
+        collapsedOwner.set(n.id, owner);
+        collapsedCounts.set(owner, (collapsedCounts.get(owner) || 0) + 1);
+    });
+    const ownerOf = id => collapsedOwner.get(id) || id;
+    const seen = new Set(), rerouted = [];
+    DATA.links.forEach(l => {
+        if (!collapsedOwner.has(l.source) && !collapsedOwner.has(l.target)) return;
+        const src = ownerOf(l.source), tgt = ownerOf(l.target), key = src + '|' + tgt + '|' + edgeType(l);
+        if (src === tgt || seen.has(key)) return;
+        seen.add(key);
+        rerouted.push({ source: src, target: tgt, type: l.type, edge_type: l.edge_type, critical: false, rerouted: true });
+    });
+    const data = Graph.graphData();
+    Graph.graphData({ nodes: data.nodes, links: data.links.filter(l => !l.rerouted).concat(rerouted) });
+    localStorage.setItem('bv-graph-collapsed-epics', JSON.stringify([...collapsedEpics]));
+    applyFilters();
+}
+function toggleEpicCollapse(id) {
+    if (collapsedEpics.has(id)) collapsedEpics.delete(id);
+    else collapsedEpics.add(id);
+    applyEpicCollapse();
+    showToast(collapsedEpics.has(id) ? 'Collapsed ' + id + ' (' + (collapsedCounts.get(id) || 0) + ' hidden)' : 'Expanded ' + id);
+}
+document.getElementById('ctx-collapse').onclick = () => { if (contextNode) toggleEpicCollapse(contextNode.id); hideContextMenu(); };
+try {
+    (JSON.parse(localStorage.getItem('bv-graph-collapsed-epics')) || []).forEach(id => { if (childrenOf.has(id)) collapsedEpics.add(id); });
+} catch (e) { /* ignore a corrupt saved value */ }
+if (collapsedEpics.size > 0) applyEpicCollapse();
+
 // Override existing filter handlers to use combined function
 document.getElementById('filter-status').onchange = e => { statusFilter = e.target.value; applyFilters(); };
 document.getElementById('filter-type').onchange = e => { typeFilter = e.target.value; applyFilters(); };
@@ -1792,7 +3160,7 @@ function updateMinimap() {
         if (n.x == null || n.y == null) return;
         const x = padding + (n.x - bounds.minX) * scale;
         const y = padding + (n.y - bounds.minY) * scale;
-        minimapCtx.fillStyle = STATUS_COLORS[n.status] || '#555577';
+        minimapCtx.fillStyle = statusColor(n.status);
         minimapCtx.beginPath();
         minimapCtx.arc(x, y, 2, 0, Math.PI * 2);
         minimapCtx.fill();
@@ -1802,13 +3170,15 @@ setInterval(updateMinimap, 2000);
 
 // LocalStorage preferences
 function loadPreferences() {
-    const theme = localStorage.getItem('bv-graph-theme');
-    if (theme === 'light') { isDarkMode = false; toggleLightMode(); toggleLightMode(); }
+    applyTheme(initialThemeIsDark());
+    applyPalette(localStorage.getItem('bv-graph-palette') || EXPORT_PALETTE);
     const layout = localStorage.getItem('bv-graph-layout');
     if (layout) {
         document.getElementById('view-mode').value = layout;
         Graph.dagMode(layout === 'force' ? null : layout);
     }
+    document.getElementById('color-by').value = colorBy;
+    applyEdgeBundling();
 }
 document.getElementById('view-mode').addEventListener('change', e => {
     localStorage.setItem('bv-graph-layout', e.target.value);
@@ -1818,12 +3188,19 @@ document.getElementById('view-mode').addEventListener('change', e => {
 document.onkeydown = e => {
     if (e.target.tagName === 'INPUT') return;
     if (e.key === '?') { toggleHelp(); return; }
+    if (e.altKey && (e.key === 'ArrowLeft' || e.key === 'ArrowRight')) {
+        e.preventDefault();
+        navigateHistory(e.key === 'ArrowLeft' ? -1 : 1);
+        return;
+    }
     switch(e.key.toLowerCase()) {
         case 'f': Graph.zoomToFit(400, 50); break;
         case 'r': document.getElementById('btn-reset').click(); break;
         case 'escape':
-            if (pathFinderMode) { pathFinderMode = false; pathFinderStart = null; document.getElementById('pathfinder-banner').classList.remove('visible'); document.getElementById('btn-path').classList.remove('active'); }
-            else { clearSelection(); hideHoverPanel(); highlightedNodes = new Set(); Graph.nodeColor(Graph.nodeColor()); }
+            hideMetricPopover();
+            if (blastMode) setBlastMode(null);
+            else if (pathFinderMode) { pathFinderMode = false; pathFinderStart = null; document.getElementById('pathfinder-banner').classList.remove('visible'); document.getElementById('btn-path').classList.remove('active'); }
+            else { cameraFollowing = false; clearSelection(); hideHoverPanel(); highlightedNodes = new Set(); clearComparison(); }
             break;
         case ' ': e.preventDefault(); document.getElementById('btn-fullscreen').click(); break;
         case 'h': document.getElementById('btn-heatmap').click(); break;
@@ -1831,8 +3208,17 @@ document.onkeydown = e => {
         case 'g': document.getElementById('btn-triage').click(); break;
         case 'd': togglePanelMode(); break;
         case 'l': toggleLightMode(); break;
+        case 'a': toggleAnimations(); break;
+        case 'c': togglePalette(); break;
+        case 'v': toggleLevels(); break;
+        case 'u': toggleEdgeBundling(); break;
         case 'y': document.getElementById('btn-recent').click(); break;
         case 'p': togglePathFinder(); break;
+        case 'b': cycleBlastMode(); break;
+        case 'k': toggleCameraLock(); break;
+        case 'm': copySubgraphMermaid(selectedNode); break;
+        case '[': setHighlightDepth(highlightDepth - 1); showToast('Highlight depth ' + highlightDepth); break;
+        case ']': setHighlightDepth(highlightDepth + 1); showToast('Highlight depth ' + highlightDepth); break;
         case '1': document.getElementById('view-mode').value = 'force'; Graph.dagMode(null); localStorage.setItem('bv-graph-layout', 'force'); break;
         case '2': document.getElementById('view-mode').value = 'td'; Graph.dagMode('td'); localStorage.setItem('bv-graph-layout', 'td'); break;
         case '3': document.getElementById('view-mode').value = 'lr'; Graph.dagMode('lr'); localStorage.setItem('bv-graph-layout', 'lr'); break;
@@ -1840,13 +3226,99 @@ document.onkeydown = e => {
     }
 };
 
+// Topological level labels (only drawn in td/lr DAG modes)
+function toggleLevels() {
+    showLevels = !showLevels;
+    document.getElementById('btn-levels').classList.toggle('active', showLevels);
+    if (showLevels && !levelLabelsVisible()) showToast('Level labels show in Top-Down and Left-Right views');
+    Graph.nodeCanvasObject(Graph.nodeCanvasObject());
+}
+document.getElementById('btn-levels').onclick = toggleLevels;
+
+// Edge bundling toggle; persists with the other view settings
+function toggleEdgeBundling() {
+    if (!clustersAvailable) { showToast('No clusters in this export; edges stay unbundled'); return; }
+    edgeBundling = !edgeBundling;
+    localStorage.setItem('bv-graph-edge-bundling', edgeBundling ? 'on' : 'off');
+    applyEdgeBundling();
+    showToast(edgeBundling ? 'Edges between clusters bundled' : 'Edge bundling off');
+}
+function applyEdgeBundling() {
+    document.getElementById('btn-bundle').classList.toggle('active', edgeBundling && clustersAvailable);
+    Graph.linkVisibility(Graph.linkVisibility());
+}
+document.getElementById('btn-bundle').onclick = toggleEdgeBundling;
+
+// Camera lock toggle
+function toggleCameraLock() {
+    cameraLocked = !cameraLocked;
+    cameraFollowing = cameraLocked && selectedNode !== null;
+    document.getElementById('btn-lock').classList.toggle('active', cameraLocked);
+    if (cameraFollowing && isFinite(selectedNode.x)) Graph.centerAt(selectedNode.x, selectedNode.y, 300);
+    showToast(cameraLocked ? 'Camera locked to selection' : 'Camera unlocked');
+}
+document.getElementById('btn-lock').onclick = toggleCameraLock;
+
 // Wire up theme button
 document.getElementById('btn-theme').onclick = toggleLightMode;
 
+// Live updates: a page served by bv serve's /graph subscribes to its event
+// stream and applies graph-patch events to DATA in place, keeping node
+// positions, the camera and the selection. A patch whose seq is not the next
+// one, or whose base_hash is not this page's data, means one was missed, and
+// a snapshot for other data means the page is stale: both reload the page.
+let graphPatchSeq = DATA.live ? DATA.live.seq : 0;
+function applyGraphPatch(patch) {
+    if (patch.seq !== graphPatchSeq + 1 || patch.base_hash !== DATA.data_hash) {
+        location.reload();
+        return false;
+    }
+    graphPatchSeq = patch.seq;
+    const copy = v => JSON.parse(JSON.stringify(v));
+    const removedNodes = new Set(patch.nodes.removed || []);
+    const upserts = new Map((patch.nodes.added || []).concat(patch.nodes.changed || []).map(n => [n.id, n]));
+    DATA.nodes = DATA.nodes.filter(n => !removedNodes.has(n.id) && !upserts.has(n.id)).concat([...upserts.values()]);
+    DATA.nodes.sort((a, b) => a.id < b.id ? -1 : a.id > b.id ? 1 : 0);
+    const linkKey = l => l.source + '|' + l.target + '|' + l.type;
+    const replaced = new Set((patch.links.removed || []).concat(patch.links.changed || []).map(linkKey));
+    DATA.links = DATA.links.filter(l => !replaced.has(linkKey(l))).concat(patch.links.added || [], patch.links.changed || []);
+    DATA.summary = patch.summary;
+    DATA.data_hash = patch.data_hash;
+    if (patch.triage) {
+        DATA.triage = patch.triage;
+        if (document.getElementById('triage-panel').style.display !== 'none') renderTriageList();
+    }
+
+    computeStats();
+    computeMetricMaxima();
+    computeScheduleRisk();
+    indexClusters();
+    indexParents();
+    indexAssignees();
+    searchTokenCache = null;
+    collapsedEpics.forEach(id => { if (!childrenOf.has(id)) collapsedEpics.delete(id); });
+
+    // Update the rendered node objects in place so positions and references survive
+    const rendered = new Map(Graph.graphData().nodes.map(n => [n.id, n]));
+    const nodes = DATA.nodes.map(n => rendered.has(n.id) ? Object.assign(rendered.get(n.id), copy(n)) : copy(n));
+    Graph.graphData({ nodes: nodes, links: copy(DATA.links) });
+    applyEpicCollapse();
+
+    if (selectedNode && removedNodes.has(selectedNode.id)) clearSelection();
+    else if (selectedNode) selectNode(selectedNode);
+    return true;
+}
+if (DATA.live && DATA.live.events && typeof EventSource !== 'undefined') {
+    const liveEvents = new EventSource(DATA.live.events);
+    liveEvents.addEventListener('graph-patch', e => applyGraphPatch(JSON.parse(e.data)));
+    liveEvents.addEventListener('snapshot', e => { if (JSON.parse(e.data).data_hash !== DATA.data_hash) location.reload(); });
+}
+
 // Load preferences and initial fit
 loadPreferences();
+applyAnimationSetting();
 setTimeout(() => { Graph.zoomToFit(400, 50); updateVisibleCount(); updateMinimap(); }, 800);
     </script>
 </body>
-</html>`, title, title, nodeCount, edgeCount, nodeCount, nodeCount, edgeCount, timestamp, dataHash, projectName, forceGraphLib, markedLib, graphDataJSON)
+</html>`, title, title, statusOptions, typeOptions, edgeTypeOptions, nodeCount, edgeCount, nodeCount, nodeCount, edgeCount, statusLegend, typeLegend, edgeLegend, timestamp, dataHash, projectName, forceGraphLib, markedLib, purifyLib, graphDataJSON, animations, sanitize, theme, palette, typeStylesJSON, criticalColor, articulationColor, linkTemplatesJSON, edgeStylesJSON)
 }
@@ -10,6 +10,7 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 )
 
@@ -32,42 +33,54 @@ func sanitizeMermaidID(id string) string {
 	return result
 }
 
-// sanitizeMermaidText prepares text for use in Mermaid node labels.
-// Removes/escapes characters that break Mermaid syntax.
-func sanitizeMermaidText(text string) string {
-	// Remove or replace problematic characters
-	replacer := strings.NewReplacer(
-		"\"", "'",
-		"[", "(",
-		"]", ")",
-		"{", "(",
-		"}", ")",
-		"<", "&lt;",
-		">", "&gt;",
-		"|", "/",
-		"`", "'",
-		"\n", " ",
-		"\r", "",
-	)
-	result := replacer.Replace(text)
-
-	// Remove any remaining control characters
-	result = strings.Map(func(r rune) rune {
+// mermaidLabelEscaper rewrites characters that would end a quoted Mermaid
+// label or be read as markup into Mermaid entity codes. It runs in a single
+// pass, so the '#' of an inserted entity is never escaped again.
+var mermaidLabelEscaper = strings.NewReplacer(
+	"#", "#35;",
+	"\"", "#quot;",
+	"&", "#amp;",
+	"<", "#lt;",
+	">", "#gt;",
+	"[", "#91;",
+	"]", "#93;",
+	"{", "#123;",
+	"}", "#125;",
+	"|", "#124;",
+	"`", "#96;",
+)
+
+// escapeMermaidLabel makes text safe inside a quoted Mermaid node label.
+// Control characters (including newlines) collapse to spaces.
+func escapeMermaidLabel(text string) string {
+	text = strings.Map(func(r rune) rune {
 		if unicode.IsControl(r) {
-			return -1
+			return ' '
 		}
 		return r
-	}, result)
+	}, text)
+	return mermaidLabelEscaper.Replace(text)
+}
 
-	result = strings.TrimSpace(result)
+// sanitizeMermaidText prepares text for use in Mermaid node labels: runs of
+// whitespace and control characters collapse to one space, text longer than
+// 40 runes is truncated with "...", and the rest is escaped by
+// escapeMermaidLabel.
+func sanitizeMermaidText(text string) string {
+	text = strings.Join(strings.Fields(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, text)), " ")
 
 	// Truncate if too long (UTF-8 safe using runes)
-	runes := []rune(result)
+	runes := []rune(text)
 	if len(runes) > 40 {
-		result = string(runes[:37]) + "..."
+		text = string(runes[:37]) + "..."
 	}
 
-	return result
+	return escapeMermaidLabel(text)
 }
 
 // GenerateMarkdown creates a comprehensive markdown report of all issues
@@ -707,6 +720,35 @@ func GeneratePriorityBriefFromTriageJSON(triageJSON []byte, config PriorityBrief
 	return sb.String(), nil
 }
 
+// GenerateTodoChecklist renders triage recommendations as a Markdown task list,
+// ready to paste into a PR description. Items keep the triage score order and
+// carry the top reason; the data hash footer ties the list to its snapshot.
+// The viewer's "Copy as checklist" button produces the same format.
+func GenerateTodoChecklist(recs []analysis.Recommendation, dataHash string) string {
+	var sb strings.Builder
+	sb.WriteString("## Triage todo\n\n")
+	if len(recs) == 0 {
+		sb.WriteString("_No recommendations._\n")
+	}
+	for _, rec := range recs {
+		sb.WriteString(fmt.Sprintf("- [ ] **%s** %s", rec.ID, singleLine(rec.Title)))
+		if len(rec.Reasons) > 0 {
+			sb.WriteString(" — " + singleLine(rec.Reasons[0]))
+		}
+		sb.WriteString("\n")
+	}
+	if dataHash != "" {
+		sb.WriteString(fmt.Sprintf("\n---\n_Generated by bv from data hash `%s`_\n", dataHash))
+	}
+	return sb.String()
+}
+
+// singleLine collapses all whitespace (including newlines) so text fits on
+// one checklist line.
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
 // barChart creates a mini ASCII bar chart for a 0-1 value
 func barChart(value float64) string {
 	if value < 0 {
@@ -78,13 +78,14 @@ func TestSanitizeMermaidText_BasicInput(t *testing.T) {
 		expected string
 	}{
 		{"simple text", "Hello World", "Hello World"},
-		{"quotes replaced", `Say "Hello"`, "Say 'Hello'"},
-		{"brackets replaced", "[TODO] fix", "(TODO) fix"},
-		{"curly brackets replaced", "{config}", "(config)"},
-		{"angle brackets escaped", "A < B > C", "A &lt; B &gt; C"},
-		{"pipe replaced", "Option|Other", "Option/Other"},
-		{"hash preserved", "Issue #123", "Issue #123"},
-		{"backticks replaced", "`code`", "'code'"},
+		{"quotes escaped", `Say "Hello"`, "Say #quot;Hello#quot;"},
+		{"brackets escaped", "[TODO] fix", "#91;TODO#93; fix"},
+		{"curly brackets escaped", "{config}", "#123;config#125;"},
+		{"angle brackets escaped", "A < B > C", "A #lt; B #gt; C"},
+		{"pipe escaped", "Option|Other", "Option#124;Other"},
+		{"hash escaped", "Issue #123", "Issue #35;123"},
+		{"ampersand escaped", "R&D", "R#amp;D"},
+		{"backticks escaped", "`code`", "#96;code#96;"},
 		{"newlines removed", "Line1\nLine2", "Line1 Line2"},
 		{"carriage returns removed", "Line1\r\nLine2", "Line1 Line2"},
 	}
@@ -1516,3 +1517,26 @@ func TestGenerateMarkdown_ClosedIssueNoCommands(t *testing.T) {
 		t.Error("Closed issue should not have command snippets")
 	}
 }
+
+// ============================================================================
+// GenerateTodoChecklist tests
+// ============================================================================
+
+func TestGenerateTodoChecklist(t *testing.T) {
+	recs := []analysis.Recommendation{
+		{ID: "bv-2", Title: "Fix\nlogin  flow", Reasons: []string{"Unblocks 3 items", "High PageRank"}},
+		{ID: "bv-1", Title: "Write docs"},
+	}
+	got := GenerateTodoChecklist(recs, "abc123")
+	want := "## Triage todo\n\n" +
+		"- [ ] **bv-2** Fix login flow — Unblocks 3 items\n" +
+		"- [ ] **bv-1** Write docs\n" +
+		"\n---\n_Generated by bv from data hash `abc123`_\n"
+	if got != want {
+		t.Errorf("unexpected checklist:\n%s\nwant:\n%s", got, want)
+	}
+
+	if empty := GenerateTodoChecklist(nil, ""); !strings.Contains(empty, "_No recommendations._") || strings.Contains(empty, "data hash") {
+		t.Errorf("unexpected empty checklist: %q", empty)
+	}
+}
@@ -0,0 +1,177 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
+)
+
+// GraphFormatPrometheus emits backlog health as Prometheus text exposition
+// rather than a graph; see WritePrometheusMetrics.
+const GraphFormatPrometheus GraphExportFormat = "prometheus"
+
+// BacklogMetrics holds the backlog health gauges exported for dashboards.
+type BacklogMetrics struct {
+	Project            string
+	Total              int
+	ByStatus           map[model.Status]int
+	Blocked            int // Non-closed beads with status blocked or an open blocker
+	Ready              int // Open beads with no open blockers
+	Dependencies       int // Blocking edges between known beads
+	Cycles             int // Groups of beads that block each other (non-trivial SCCs)
+	CriticalPathLength int // Beads on the longest dependency chain; a cycle counts all its beads
+}
+
+// prometheusStatuses fixes the status label set so every scrape reports the
+// same series, even when a status has no beads. Other statuses found in the
+// data are reported after these.
+var prometheusStatuses = []model.Status{
+	model.StatusOpen,
+	model.StatusInProgress,
+	model.StatusBlocked,
+	model.StatusClosed,
+}
+
+// ComputeBacklogMetrics derives the exported gauges from the issues alone, in
+// O(V+E): cycles and the critical path come from one strongly connected
+// component pass rather than a full graph analysis, so a scrape stays cheap.
+// Tombstoned beads are ignored.
+func ComputeBacklogMetrics(issues []model.Issue, project string) BacklogMetrics {
+	m := BacklogMetrics{Project: project, ByStatus: make(map[model.Status]int)}
+
+	byID := make(map[string]model.Issue, len(issues))
+	nodes := make(map[string]int64, len(issues))
+	for _, iss := range issues {
+		if !iss.Status.IsTombstone() {
+			byID[iss.ID] = iss
+			if _, ok := nodes[iss.ID]; !ok {
+				nodes[iss.ID] = int64(len(nodes))
+			}
+		}
+	}
+
+	g := simple.NewDirectedGraph()
+	for _, id := range nodes {
+		g.AddNode(simple.Node(id))
+	}
+
+	for _, iss := range issues {
+		if iss.Status.IsTombstone() {
+			continue
+		}
+		m.Total++
+		m.ByStatus[iss.Status]++
+
+		openBlockers := 0
+		for _, dep := range iss.Dependencies {
+			if dep == nil || !dep.Type.IsBlocking() {
+				continue
+			}
+			blocker, ok := byID[dep.DependsOnID]
+			if !ok {
+				continue
+			}
+			m.Dependencies++
+			if !blocker.Status.IsClosed() {
+				openBlockers++
+			}
+			if from, to := nodes[iss.ID], nodes[dep.DependsOnID]; from != to {
+				g.SetEdge(g.NewEdge(simple.Node(from), simple.Node(to)))
+			}
+		}
+
+		if iss.Status.IsClosed() {
+			continue
+		}
+		if iss.Status == model.StatusBlocked || openBlockers > 0 {
+			m.Blocked++
+		} else if iss.Status == model.StatusOpen {
+			m.Ready++
+		}
+	}
+
+	// TarjanSCC yields components blockers-first, so each component's chain
+	// length can be built from those it depends on, already computed.
+	sccs := topo.TarjanSCC(g)
+	component := make(map[int64]int, len(nodes))
+	chain := make([]int, len(sccs))
+	for i, scc := range sccs {
+		if len(scc) > 1 {
+			m.Cycles++
+		}
+		for _, n := range scc {
+			component[n.ID()] = i
+		}
+		longest := 0
+		for _, n := range scc {
+			to := g.From(n.ID())
+			for to.Next() {
+				if c := component[to.Node().ID()]; c != i && chain[c] > longest {
+					longest = chain[c]
+				}
+			}
+		}
+		chain[i] = len(scc) + longest
+		if chain[i] > m.CriticalPathLength {
+			m.CriticalPathLength = chain[i]
+		}
+	}
+	return m
+}
+
+// WritePrometheusMetrics writes m in the Prometheus text exposition format
+// (version 0.0.4), suitable for the node_exporter textfile collector. Every
+// series carries a project label.
+func WritePrometheusMetrics(w io.Writer, m BacklogMetrics) error {
+	bw := bufio.NewWriter(w)
+	project := `project="` + escapePrometheusLabel(m.Project) + `"`
+
+	gauge := func(name, help string) {
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	}
+
+	gauge("bv_beads_total", "Number of beads, excluding tombstones.")
+	fmt.Fprintf(bw, "bv_beads_total{%s} %d\n", project, m.Total)
+
+	gauge("bv_beads_by_status", "Number of beads by status.")
+	statuses := append([]model.Status(nil), prometheusStatuses...)
+	var extra []model.Status
+	for status := range m.ByStatus {
+		if !slices.Contains(prometheusStatuses, status) {
+			extra = append(extra, status)
+		}
+	}
+	slices.Sort(extra)
+	for _, status := range append(statuses, extra...) {
+		fmt.Fprintf(bw, "bv_beads_by_status{%s,status=\"%s\"} %d\n", project, escapePrometheusLabel(string(status)), m.ByStatus[status])
+	}
+
+	gauge("bv_beads_blocked", "Number of unfinished beads that are blocked or wait on an open blocker.")
+	fmt.Fprintf(bw, "bv_beads_blocked{%s} %d\n", project, m.Blocked)
+
+	gauge("bv_beads_ready", "Number of open beads with no open blockers.")
+	fmt.Fprintf(bw, "bv_beads_ready{%s} %d\n", project, m.Ready)
+
+	gauge("bv_dependencies_total", "Number of blocking dependencies between beads.")
+	fmt.Fprintf(bw, "bv_dependencies_total{%s} %d\n", project, m.Dependencies)
+
+	gauge("bv_cycles_total", "Number of dependency cycles (groups of beads that block each other).")
+	fmt.Fprintf(bw, "bv_cycles_total{%s} %d\n", project, m.Cycles)
+
+	gauge("bv_critical_path_length", "Number of beads on the longest dependency chain; a cycle counts all its beads.")
+	fmt.Fprintf(bw, "bv_critical_path_length{%s} %d\n", project, m.CriticalPathLength)
+
+	return bw.Flush()
+}
+
+// escapePrometheusLabel escapes a label value per the exposition format.
+func escapePrometheusLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,138 @@
+package export
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// promSampleRe matches a sample line: metric{label="value",...} number
+var promSampleRe = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)\{((?:[a-zA-Z_][a-zA-Z0-9_]*="(?:[^"\\]|\\.)*",?)*)\} (\S+)$`)
+
+func TestWritePrometheusMetrics_ExpositionFormat(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "a", Title: "Root", Status: model.StatusOpen},
+		{ID: "b", Title: "Waits on a", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{IssueID: "b", DependsOnID: "a", Type: model.DepBlocks},
+		}},
+		{ID: "c", Title: "Waits on b", Status: model.StatusInProgress, Dependencies: []*model.Dependency{
+			{IssueID: "c", DependsOnID: "b", Type: model.DepBlocks},
+		}},
+		{ID: "x", Title: "Cycle x", Status: model.StatusBlocked, Dependencies: []*model.Dependency{
+			{IssueID: "x", DependsOnID: "y", Type: model.DepBlocks},
+		}},
+		{ID: "y", Title: "Cycle y", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{IssueID: "y", DependsOnID: "x", Type: model.DepBlocks},
+		}},
+		{ID: "done", Title: "Done", Status: model.StatusClosed},
+		{ID: "gone", Title: "Deleted", Status: model.StatusTombstone},
+	}
+	var buf bytes.Buffer
+	metrics := ComputeBacklogMetrics(issues, `my "proj"`)
+	if err := WritePrometheusMetrics(&buf, metrics); err != nil {
+		t.Fatalf("WritePrometheusMetrics: %v", err)
+	}
+
+	values := map[string]float64{}
+	typed := map[string]bool{}
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		if strings.HasPrefix(line, "# HELP ") {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "# TYPE "); ok {
+			name, kind, _ := strings.Cut(rest, " ")
+			if kind != "gauge" {
+				t.Errorf("unexpected metric type %q for %s", kind, name)
+			}
+			typed[name] = true
+			continue
+		}
+		m := promSampleRe.FindStringSubmatch(line)
+		if m == nil {
+			t.Fatalf("line is not valid exposition format: %q", line)
+		}
+		if !typed[m[1]] {
+			t.Errorf("sample %s appears before its # TYPE line", m[1])
+		}
+		if !strings.Contains(m[2], `project="my \"proj\""`) {
+			t.Errorf("sample %s lacks an escaped project label: %s", m[1], m[2])
+		}
+		v, err := strconv.ParseFloat(m[3], 64)
+		if err != nil {
+			t.Errorf("non-numeric value in %q", line)
+		}
+		key := m[1]
+		if status := regexp.MustCompile(`status="([^"]*)"`).FindStringSubmatch(m[2]); status != nil {
+			key += "/" + status[1]
+		}
+		values[key] = v
+	}
+
+	want := map[string]float64{
+		"bv_beads_total":                 6,
+		"bv_beads_by_status/open":        3,
+		"bv_beads_by_status/in_progress": 1,
+		"bv_beads_by_status/blocked":     1,
+		"bv_beads_by_status/closed":      1,
+		"bv_beads_blocked":               4, // b, c, x, y
+		"bv_beads_ready":                 1, // a
+		"bv_dependencies_total":          4,
+		"bv_cycles_total":                1,
+	}
+	for key, w := range want {
+		if got, ok := values[key]; !ok || got != w {
+			t.Errorf("%s = %v (present %v), want %v", key, got, ok, w)
+		}
+	}
+}
+
+func TestComputeBacklogMetrics_CriticalPathLength(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "a", Status: model.StatusOpen},
+		{ID: "b", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "b", DependsOnID: "a", Type: model.DepBlocks}}},
+		{ID: "c", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "c", DependsOnID: "b", Type: model.DepBlocks}}},
+		{ID: "d", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "d", DependsOnID: "a", Type: model.DepBlocks}}},
+	}
+
+	m := ComputeBacklogMetrics(issues, "p")
+	if m.CriticalPathLength != 3 || m.Cycles != 0 {
+		t.Errorf("expected critical path 3 (a <- b <- c) and no cycles, got %+v", m)
+	}
+
+	// A cycle no longer hides the chain: x <-> y sits between a and z, and
+	// counts both its beads.
+	issues = append(issues,
+		model.Issue{ID: "x", Status: model.StatusOpen, Dependencies: []*model.Dependency{
+			{IssueID: "x", DependsOnID: "c", Type: model.DepBlocks},
+			{IssueID: "x", DependsOnID: "y", Type: model.DepBlocks},
+		}},
+		model.Issue{ID: "y", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "y", DependsOnID: "x", Type: model.DepBlocks}}},
+		model.Issue{ID: "z", Status: model.StatusOpen, Dependencies: []*model.Dependency{{IssueID: "z", DependsOnID: "y", Type: model.DepBlocks}}},
+	)
+	m = ComputeBacklogMetrics(issues, "p")
+	if m.CriticalPathLength != 6 || m.Cycles != 1 {
+		t.Errorf("expected critical path 6 (a <- b <- c <- {x,y} <- z) and one cycle, got %+v", m)
+	}
+}
+
+func TestWritePrometheusMetrics_ReportsUnknownStatuses(t *testing.T) {
+	issues := []model.Issue{
+		{ID: "a", Status: model.StatusOpen},
+		{ID: "b", Status: model.Status("review")},
+		{ID: "c", Status: model.Status("review")},
+	}
+	var buf bytes.Buffer
+	if err := WritePrometheusMetrics(&buf, ComputeBacklogMetrics(issues, "p")); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `bv_beads_by_status{project="p",status="review"} 2`) {
+		t.Errorf("custom status missing from output:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), `bv_beads_by_status{project="p",status="closed"} 0`) {
+		t.Errorf("fixed statuses should still be reported:\n%s", buf.String())
+	}
+}
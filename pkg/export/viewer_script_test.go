@@ -0,0 +1,195 @@
+package export
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The interactive viewer's logic lives in JavaScript inside the exported page.
+// These helpers pull individual top-level declarations out of a generated page
+// and run them under node, so tests exercise what the viewer computes rather
+// than grep its source. Tests using them are skipped when node is not
+// installed.
+
+// viewerJSPrelude stubs the browser globals the extracted functions touch:
+// elements are inert records that remember what was written to them.
+const viewerJSPrelude = `
+function __el() {
+    const kids = new Map(), classes = new Set();
+    return {
+        classList: {
+            add: c => classes.add(c),
+            remove: c => classes.delete(c),
+            toggle(c, on) { if (on === undefined) on = !classes.has(c); if (on) classes.add(c); else classes.delete(c); return on; },
+            contains: c => classes.has(c),
+        },
+        style: {}, dataset: {}, textContent: '', innerHTML: '', value: '', title: '',
+        scrollTop: 0, clientHeight: 400, scrollHeight: 0, offsetTop: 0,
+        appendChild() {}, addEventListener() {},
+        querySelector(sel) { if (!kids.has(sel)) kids.set(sel, __el()); return kids.get(sel); },
+        querySelectorAll() { return []; },
+    };
+}
+const __els = new Map();
+globalThis.document = {
+    getElementById(id) { if (!__els.has(id)) __els.set(id, __el()); return __els.get(id); },
+    querySelectorAll() { return []; },
+    createElement() { return __el(); },
+    addEventListener() {},
+};
+globalThis.localStorage = {
+    items: new Map(),
+    getItem(k) { return this.items.has(k) ? this.items.get(k) : null; },
+    setItem(k, v) { this.items.set(k, String(v)); },
+    removeItem(k) { this.items.delete(k); },
+};
+function out(v) { process.stdout.write(JSON.stringify(v)); }
+`
+
+// runViewerJS runs body after the prelude, the page's DATA and the named
+// declarations from the viewer script ("function name" or "const NAME"), and
+// decodes the JSON that body passes to out() into result.
+func runViewerJS(t *testing.T, html string, decls []string, body string, result any) {
+	t.Helper()
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node not installed; skipping viewer JavaScript test")
+	}
+
+	script := viewerScript(t, html)
+	var src strings.Builder
+	src.WriteString(viewerJSPrelude)
+	src.WriteString(jsDeclaration(t, script, "const DATA ="))
+	src.WriteString("\n")
+	for _, decl := range decls {
+		src.WriteString(jsDeclaration(t, script, decl))
+		src.WriteString("\n")
+	}
+	src.WriteString(body)
+
+	path := filepath.Join(t.TempDir(), "viewer_test.js")
+	if err := os.WriteFile(path, []byte(src.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(node, path)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	stdout, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("node: %v\n%s", err, stderr.String())
+	}
+	if err := json.Unmarshal(stdout, result); err != nil {
+		t.Fatalf("decode viewer output %q: %v", stdout, err)
+	}
+}
+
+// viewerScript returns the viewer's own script: the last <script> element of
+// the page, after the bundled libraries.
+func viewerScript(t *testing.T, html string) string {
+	t.Helper()
+	start := strings.LastIndex(html, "<script>")
+	end := strings.LastIndex(html, "</script>")
+	if start < 0 || end < start {
+		t.Fatal("no viewer <script> in the page")
+	}
+	return html[start+len("<script>") : end]
+}
+
+// jsDeclaration returns the top-level statement of script that starts a line
+// with prefix: through the closing brace of a function, or through the
+// semicolon of anything else.
+func jsDeclaration(t *testing.T, script, prefix string) string {
+	t.Helper()
+	start := -1
+	for i := 0; i < len(script); {
+		if strings.HasPrefix(script[i:], prefix) && (i == 0 || script[i-1] == '\n') {
+			start = i
+			break
+		}
+		next := strings.IndexByte(script[i:], '\n')
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	if start < 0 {
+		t.Fatalf("viewer script has no %q", prefix)
+	}
+	isFunc := strings.HasPrefix(prefix, "function ")
+	if end := jsStatementEnd(script, start, isFunc); end > 0 {
+		return script[start:end]
+	}
+	t.Fatalf("unterminated %q in viewer script", prefix)
+	return ""
+}
+
+// jsStatementEnd scans from start, skipping strings, template literals,
+// comments and regex literals, and returns the offset just past the
+// statement's end, or -1.
+func jsStatementEnd(s string, start int, isFunc bool) int {
+	depth := 0
+	prev := byte(0) // Last significant character, to tell regex from division
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '/' && i+1 < len(s) && s[i+1] == '/':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+			continue
+		case c == '/' && i+1 < len(s) && s[i+1] == '*':
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return -1
+			}
+			i += end + 3
+			continue
+		case c == '\'' || c == '"' || c == '`':
+			for i++; i < len(s) && s[i] != c; i++ {
+				if s[i] == '\\' {
+					i++
+				}
+			}
+		case c == '/' && (prev == 0 || strings.IndexByte("(,=:[!&|?{};+-*%<>~^", prev) >= 0):
+			inClass := false
+			for i++; i < len(s); i++ {
+				if s[i] == '\\' {
+					i++
+				} else if s[i] == '[' {
+					inClass = true
+				} else if s[i] == ']' {
+					inClass = false
+				} else if s[i] == '/' && !inClass {
+					break
+				}
+			}
+		case c == '{' || c == '(' || c == '[':
+			depth++
+		case c == '}' || c == ')' || c == ']':
+			depth--
+			if isFunc && c == '}' && depth == 0 {
+				return i + 1
+			}
+		case c == ';' && depth == 0 && !isFunc:
+			return i + 1
+		}
+		if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+			prev = c
+		}
+	}
+	return -1
+}
+
+// jsLiteral renders v as a JavaScript literal for a test body
+func jsLiteral(t *testing.T, v any) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,192 @@
+package loader
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// discoverSkipDirs are never searched for nested .beads directories, ignored
+// or not. Hidden directories (.git, .bv, ...) are skipped as well.
+var discoverSkipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// FindBeadsDirUp returns the .beads directory in dir or its nearest ancestor
+// that has one, the way git finds .git. ok is false when there is none.
+func FindBeadsDirUp(dir string) (beadsDir string, ok bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		candidate := filepath.Join(dir, ".beads")
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return candidate, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// BeadsProject is a directory with its own .beads, found by DiscoverBeadsDirs.
+type BeadsProject struct {
+	// Path is the project directory relative to the search root, with forward
+	// slashes; "." for the root itself
+	Path string `json:"path"`
+
+	// BeadsDir is the absolute path of the project's .beads directory
+	BeadsDir string `json:"beads_dir"`
+}
+
+// DiscoverBeadsDirs finds root's .beads and every .beads directory below it,
+// for monorepos that track beads per subproject. Like git it does not descend
+// into directories matched by a .gitignore along the way, nor into hidden,
+// node_modules or vendor directories. Only .gitignore files at or below root
+// are read; a parent repository's .gitignore, .git/info/exclude and the global
+// excludes file are not. Projects are sorted by path.
+func DiscoverBeadsDirs(root string) ([]BeadsProject, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", root, err)
+	}
+	var projects []BeadsProject
+	var rules []gitignoreRule
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root {
+				return err
+			}
+			return nil // Unreadable subdirectory: skip it
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, p)
+		rel = filepath.ToSlash(rel)
+		if p != root {
+			name := d.Name()
+			if strings.HasPrefix(name, ".") || discoverSkipDirs[name] || gitignored(rules, rel) {
+				return filepath.SkipDir
+			}
+		}
+		rules = append(rules, readGitignore(p, rel)...)
+		if info, err := os.Stat(filepath.Join(p, ".beads")); err == nil && info.IsDir() {
+			projects = append(projects, BeadsProject{Path: rel, BeadsDir: filepath.Join(p, ".beads")})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("search %s for .beads: %w", root, err)
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Path < projects[j].Path })
+	return projects, nil
+}
+
+// gitignoreRule is one pattern line of a .gitignore, applied to directories
+// at or below base (the .gitignore's directory relative to the search root).
+type gitignoreRule struct {
+	base     string
+	pattern  string
+	negate   bool
+	anchored bool // Pattern contains a slash: matched against the path from base
+}
+
+// readGitignore parses dir/.gitignore. Directory-only patterns ("build/")
+// are kept as plain patterns since only directories are matched; "**"
+// segments are kept and handled by matchPathPattern.
+func readGitignore(dir, rel string) []gitignoreRule {
+	f, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var rules []gitignoreRule
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		r := gitignoreRule{base: rel}
+		if strings.HasPrefix(line, "!") {
+			r.negate, line = true, line[1:]
+		}
+		line = strings.TrimSuffix(line, "/")
+		if strings.Contains(line, "/") {
+			r.anchored, line = true, strings.TrimPrefix(line, "/")
+		}
+		if line != "" {
+			r.pattern = line
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// gitignored reports whether the directory rel (relative to the search root)
+// is ignored: the last matching rule wins, and "!" rules un-ignore.
+func gitignored(rules []gitignoreRule, rel string) bool {
+	ignored := false
+	for _, r := range rules {
+		sub := rel
+		if r.base != "." {
+			if !strings.HasPrefix(rel, r.base+"/") {
+				continue
+			}
+			sub = strings.TrimPrefix(rel, r.base+"/")
+		}
+		matched := false
+		if r.anchored {
+			matched = matchPathPattern(r.pattern, sub)
+		} else {
+			matched, _ = path.Match(r.pattern, path.Base(sub))
+		}
+		if matched {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// matchPathPattern matches a slash-separated gitignore pattern against rel
+// segment by segment. A "**" segment matches any number of directories
+// ("a/**/b" matches a/b and a/x/y/b); a trailing "**" matches everything
+// inside, but not the directory itself.
+func matchPathPattern(pattern, rel string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(rel, "/"))
+}
+
+func matchSegments(pattern, segs []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			if len(pattern) == 1 {
+				return len(segs) > 0
+			}
+			for i := range len(segs) + 1 {
+				if matchSegments(pattern[1:], segs[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segs) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segs[0]); !ok {
+			return false
+		}
+		pattern, segs = pattern[1:], segs[1:]
+	}
+	return len(segs) == 0
+}
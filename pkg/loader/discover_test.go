@@ -0,0 +1,113 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeNestedBeads creates a .beads/issues.jsonl holding jsonl under root/rel.
+func writeNestedBeads(t *testing.T, root, rel, jsonl string) {
+	t.Helper()
+	dir := filepath.Join(root, rel, ".beads")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "issues.jsonl"), []byte(jsonl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscoverBeadsDirs(t *testing.T) {
+	root := t.TempDir()
+	writeNestedBeads(t, root, ".", `{"id":"root-1","title":"Root","status":"open","issue_type":"task"}`+"\n")
+	writeNestedBeads(t, root, "services/api", `{"id":"api-1","title":"API","status":"open","issue_type":"task"}`+"\n")
+	writeNestedBeads(t, root, "services/web", `{"id":"web-1","title":"Web","status":"open","issue_type":"task","dependencies":[{"issue_id":"web-1","depends_on_id":"api-1","type":"blocks"}]}`+"\n"+
+		`{"id":"api-1","title":"Clash","status":"open","issue_type":"task"}`+"\n")
+	writeNestedBeads(t, root, "generated/tmp", `{"id":"gen-1","title":"Ignored","status":"open","issue_type":"task"}`+"\n")
+	writeNestedBeads(t, root, "services/node_modules/pkg", `{"id":"nm-1","title":"Vendored","status":"open","issue_type":"task"}`+"\n")
+	writeNestedBeads(t, root, "services/.cache/x", `{"id":"hid-1","title":"Hidden","status":"open","issue_type":"task"}`+"\n")
+	if err := os.MkdirAll(filepath.Join(root, "services/empty/.beads"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte("# build output\ngenerated/\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	projects, err := DiscoverBeadsDirs(root)
+	if err != nil {
+		t.Fatalf("DiscoverBeadsDirs: %v", err)
+	}
+	var paths []string
+	for _, p := range projects {
+		paths = append(paths, p.Path)
+	}
+	if got := strings.Join(paths, ","); got != ".,services/api,services/empty,services/web" {
+		t.Errorf("discovered %s, want .,services/api,services/empty,services/web", got)
+	}
+}
+
+func TestGetBeadsDirFindsParentBeads(t *testing.T) {
+	t.Setenv(BeadsDirEnvVar, "")
+	root := t.TempDir()
+	writeNestedBeads(t, root, "app", `{"id":"a-1","title":"A","status":"open","issue_type":"task"}`+"\n")
+	deep := filepath.Join(root, "app", "src", "pkg")
+	if err := os.MkdirAll(deep, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := GetBeadsDir(deep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(root, "app", ".beads"); got != want {
+		t.Errorf("GetBeadsDir(%s) = %s, want %s", deep, got, want)
+	}
+	issues, err := LoadIssues(deep)
+	if err != nil || len(issues) != 1 || issues[0].SourceRepo != "" {
+		t.Errorf("LoadIssues from a subdirectory = %v, %v; want the one untagged bead", issues, err)
+	}
+
+	// A sibling without .beads anywhere above it keeps the old default
+	if got, _ := GetBeadsDir(root); got != filepath.Join(root, ".beads") {
+		t.Errorf("GetBeadsDir(%s) = %s, want %s/.beads", root, got, root)
+	}
+}
+
+func TestGitignoredDirs(t *testing.T) {
+	rules := []gitignoreRule{
+		{base: ".", pattern: "build"},
+		{base: ".", pattern: "out/*", anchored: true},
+		{base: ".", pattern: "keep", negate: true},
+		{base: "apps", pattern: "tmp*"},
+		{base: ".", pattern: "docs/keep", anchored: true},
+		{base: ".", pattern: "docs/keep", anchored: true, negate: true},
+		{base: ".", pattern: "gen/**/cache", anchored: true},
+		{base: ".", pattern: "**/fixtures", anchored: true},
+		{base: "libs", pattern: "vendored/**", anchored: true},
+	}
+	for rel, want := range map[string]bool{
+		"build":             true,
+		"apps/build":        true,
+		"out/a":             true,
+		"x/out/a":           false,
+		"keep":              false,
+		"apps/tmp1":         true,
+		"tmp1":              false,
+		"docs/keep":         false,
+		"services/ok":       false,
+		"gen/cache":         true,
+		"gen/a/b/cache":     true,
+		"gen/a/b/cache2":    false,
+		"x/gen/cache":       false,
+		"fixtures":          true,
+		"a/b/fixtures":      true,
+		"libs/vendored":     false,
+		"libs/vendored/pkg": true,
+	} {
+		if got := gitignored(rules, rel); got != want {
+			t.Errorf("gitignored(%q) = %v, want %v", rel, got, want)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+//go:build windows
+
+package loader
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+func lockFile(f *os.File) error {
+	handle := windows.Handle(f.Fd())
+	var ol windows.Overlapped
+	return windows.LockFileEx(handle, windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, &ol)
+}
+
+func unlockFile(f *os.File) error {
+	handle := windows.Handle(f.Fd())
+	var ol windows.Overlapped
+	return windows.UnlockFileEx(handle, 0, 1, 0, &ol)
+}
@@ -80,6 +80,36 @@ func (g *GitLoader) LoadAt(revision string) ([]model.Issue, error) {
 	return issues, nil
 }
 
+// LoadAtWithOptions is LoadAt parsing with opts, so strict mode, warning
+// handlers and type default priorities apply to history too. Its results are
+// not cached, since the options shape them.
+func (g *GitLoader) LoadAtWithOptions(revision string, opts ParseOptions) ([]model.Issue, error) {
+	sha, err := g.resolveRevision(revision)
+	if err != nil {
+		return nil, fmt.Errorf("resolving revision %q: %w", revision, err)
+	}
+	return g.loadFromGitWithOptions(sha, opts)
+}
+
+// LoadAtRef loads issues from a git ref for comparison with the working copy.
+// Unlike LoadAt, a ref without any beads file (e.g. a branch from before beads
+// were introduced) yields an empty slice rather than an error; found reports
+// whether a beads file existed. An unknown ref is still an error.
+func (g *GitLoader) LoadAtRef(ref string) (issues []model.Issue, found bool, err error) {
+	found, err = g.HasBeadsAtRevision(ref)
+	if err != nil {
+		return nil, false, fmt.Errorf("resolving revision %q: %w", ref, err)
+	}
+	if !found {
+		return []model.Issue{}, false, nil
+	}
+	issues, err = g.LoadAt(ref)
+	if err != nil {
+		return nil, true, err
+	}
+	return issues, true, nil
+}
+
 // LoadAtDate loads issues from the state at a specific date/time
 // Uses git rev-list to find the commit at or before the given time
 func (g *GitLoader) LoadAtDate(t time.Time) ([]model.Issue, error) {
@@ -92,6 +122,26 @@ func (g *GitLoader) ResolveRevision(revision string) (string, error) {
 	return g.resolveRevision(revision)
 }
 
+// RevisionTime returns the moment a revision stands for: the date itself when
+// revision is a date (as ResolveRevision accepts), otherwise the commit time
+// of the commit it names.
+func (g *GitLoader) RevisionTime(revision string) (time.Time, error) {
+	if t, ok := parseDateString(revision); ok {
+		return t, nil
+	}
+	sha, err := g.resolveRevision(revision)
+	if err != nil {
+		return time.Time{}, err
+	}
+	cmd := exec.Command("git", "show", "-s", "--format=%cI", sha)
+	cmd.Dir = g.repoPath
+	out, err := cmd.Output()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("reading commit time of %s: %w", revision, err)
+	}
+	return time.Parse(time.RFC3339, strings.TrimSpace(string(out)))
+}
+
 // ListRevisions returns commits that modified beads files
 func (g *GitLoader) ListRevisions(limit int) ([]RevisionInfo, error) {
 	args := []string{
@@ -204,6 +254,13 @@ func parseDateString(s string) (time.Time, bool) {
 
 // loadFromGit loads issues from a specific commit SHA
 func (g *GitLoader) loadFromGit(sha string) ([]model.Issue, error) {
+	return g.loadFromGitWithOptions(sha, ParseOptions{})
+}
+
+// loadFromGitWithOptions parses the first beads file found at sha with opts.
+// Only a missing file moves on to the next name; a parse error (e.g. a
+// duplicate ID in strict mode) is returned as is.
+func (g *GitLoader) loadFromGitWithOptions(sha string, opts ParseOptions) ([]model.Issue, error) {
 	// Try known beads file paths in order, matching loader.go precedence
 	var paths []string
 	for _, name := range PreferredJSONLNames {
@@ -212,9 +269,9 @@ func (g *GitLoader) loadFromGit(sha string) ([]model.Issue, error) {
 
 	var lastErr error
 	for _, path := range paths {
-		issues, err := g.loadFileFromGit(sha, path)
+		out, err := g.showFile(sha, path)
 		if err == nil {
-			return issues, nil
+			return ParseIssuesWithOptions(bytes.NewReader(out), opts)
 		}
 		lastErr = err
 	}
@@ -222,8 +279,8 @@ func (g *GitLoader) loadFromGit(sha string) ([]model.Issue, error) {
 	return nil, fmt.Errorf("no beads file found at %s: %w", sha, lastErr)
 }
 
-// loadFileFromGit loads a specific file from git at a commit
-func (g *GitLoader) loadFileFromGit(sha, path string) ([]model.Issue, error) {
+// showFile reads a specific file from git at a commit
+func (g *GitLoader) showFile(sha, path string) ([]byte, error) {
 	cmd := exec.Command("git", "show", fmt.Sprintf("%s:%s", sha, path))
 	cmd.Dir = g.repoPath
 
@@ -231,8 +288,7 @@ func (g *GitLoader) loadFileFromGit(sha, path string) ([]model.Issue, error) {
 	if err != nil {
 		return nil, fmt.Errorf("git show %s:%s failed: %w", sha, path, err)
 	}
-
-	return ParseIssues(bytes.NewReader(out))
+	return out, nil
 }
 
 // Cache methods
@@ -371,13 +427,8 @@ func (g *GitLoader) HasBeadsAtRevision(revision string) (bool, error) {
 		return false, err
 	}
 
-	paths := []string{
-		".beads/beads.jsonl",
-		".beads/beads.base.jsonl",
-		".beads/issues.jsonl",
-	}
-
-	for _, path := range paths {
+	for _, name := range PreferredJSONLNames {
+		path := ".beads/" + name
 		cmd := exec.Command("git", "cat-file", "-e", fmt.Sprintf("%s:%s", sha, path))
 		cmd.Dir = g.repoPath
 		if err := cmd.Run(); err == nil {
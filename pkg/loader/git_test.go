@@ -128,6 +128,28 @@ func TestGitLoader_LoadAt_HEAD(t *testing.T) {
 	}
 }
 
+func TestGitLoader_LoadAtWithOptions(t *testing.T) {
+	repoDir, cleanup := setupTestGitRepo(t)
+	defer cleanup()
+	beadsFile := filepath.Join(repoDir, ".beads", "beads.base.jsonl")
+	content := `{"id":"ISSUE-1","title":"First issue","status":"open","issue_type":"bug"}
+{"id":"ISSUE-1","title":"Duplicate","status":"open","issue_type":"bug"}
+`
+	if err := os.WriteFile(beadsFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repoDir, "commit", "-am", "Duplicate an ID")
+
+	loader := NewGitLoader(repoDir)
+	issues, err := loader.LoadAtWithOptions("HEAD", ParseOptions{WarningHandler: func(string) {}, DefaultPriorities: map[string]int{"bug": 1}})
+	if err != nil || len(issues) != 1 || issues[0].Priority != 1 {
+		t.Fatalf("LoadAtWithOptions = %+v, %v; want one bug at the default P1", issues, err)
+	}
+	if _, err := loader.LoadAtWithOptions("HEAD", ParseOptions{Strict: true}); err == nil || strings.Contains(err.Error(), "no beads file") {
+		t.Errorf("expected the strict duplicate error itself, got %v", err)
+	}
+}
+
 func TestGitLoader_LoadAt_OlderCommit(t *testing.T) {
 	repoDir, cleanup := setupTestGitRepo(t)
 	defer cleanup()
@@ -184,6 +206,31 @@ func TestGitLoader_ResolveRevision_DateString(t *testing.T) {
 	}
 }
 
+func TestGitLoader_RevisionTime(t *testing.T) {
+	repoDir, cleanup := setupTestGitRepo(t)
+	defer cleanup()
+
+	loader := NewGitLoader(repoDir)
+
+	want, err := time.Parse(time.RFC3339, strings.TrimSpace(runGitOutput(t, repoDir, "log", "--format=%cI", "-n1", "HEAD~1")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := loader.RevisionTime("HEAD~1")
+	if err != nil {
+		t.Fatalf("RevisionTime(HEAD~1) failed: %v", err)
+	}
+	if !got.Equal(want) {
+		t.Errorf("RevisionTime(HEAD~1) = %v, want its commit time %v", got, want)
+	}
+
+	// A date stands for itself, not for the commit it resolves to
+	got, err = loader.RevisionTime("2025-01-02T03:04:05Z")
+	if err != nil || !got.Equal(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("RevisionTime(date) = %v, %v", got, err)
+	}
+}
+
 func TestParseDateStringUsesLocalForDateOnly(t *testing.T) {
 	dateStr := "2025-01-02"
 	tm, ok := parseDateString(dateStr)
@@ -425,3 +472,41 @@ func TestCacheExpiry(t *testing.T) {
 		t.Errorf("expected 0 valid entries after expiry, got %d", stats.ValidEntries)
 	}
 }
+
+func TestGitLoader_LoadAtRef_MissingFileIsEmpty(t *testing.T) {
+	repoDir, cleanup := setupTestGitRepo(t)
+	defer cleanup()
+
+	beadsHead := strings.TrimSpace(runGitOutput(t, repoDir, "rev-parse", "HEAD"))
+
+	// Add a commit on an orphan branch without any beads file.
+	runGit(t, repoDir, "checkout", "--orphan", "empty")
+	runGit(t, repoDir, "rm", "-rf", "--cached", ".")
+	if err := os.WriteFile(filepath.Join(repoDir, "README.md"), []byte("no beads\n"), 0644); err != nil {
+		t.Fatalf("failed to write README: %v", err)
+	}
+	runGit(t, repoDir, "add", "README.md")
+	runGit(t, repoDir, "commit", "-m", "No beads here")
+
+	loader := NewGitLoader(repoDir)
+
+	issues, found, err := loader.LoadAtRef("empty")
+	if err != nil {
+		t.Fatalf("LoadAtRef failed: %v", err)
+	}
+	if found || len(issues) != 0 {
+		t.Errorf("expected no beads at orphan ref, got found=%v issues=%d", found, len(issues))
+	}
+
+	issues, found, err = loader.LoadAtRef(beadsHead)
+	if err != nil {
+		t.Fatalf("LoadAtRef failed: %v", err)
+	}
+	if !found || len(issues) != 3 {
+		t.Errorf("expected 3 issues at the beads commit, got found=%v issues=%d", found, len(issues))
+	}
+
+	if _, _, err := loader.LoadAtRef("nonexistent-branch"); err == nil {
+		t.Error("expected error for unknown ref")
+	}
+}
@@ -4,24 +4,40 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/Dicklesworthstone/beads_viewer/pkg/logging"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 )
 
 // BeadsDirEnvVar is the name of the environment variable for custom beads directory
 const BeadsDirEnvVar = "BEADS_DIR"
 
+// ErrNoBeadsFile is wrapped by FindJSONLPath when the beads directory holds
+// no usable JSONL file.
+var ErrNoBeadsFile = errors.New("no beads JSONL file found")
+
+// IsNoBeadsData reports whether err means the project simply has no beads
+// yet: the beads directory is missing or has no JSONL file in it.
+func IsNoBeadsData(err error) bool {
+	return errors.Is(err, ErrNoBeadsFile) || errors.Is(err, fs.ErrNotExist)
+}
+
 // PreferredJSONLNames defines the priority order for looking up beads data files.
 var PreferredJSONLNames = []string{"issues.jsonl", "beads.jsonl", "beads.base.jsonl"}
 
 // GetBeadsDir returns the beads directory path, respecting BEADS_DIR env var.
 // If BEADS_DIR is set, it is used directly.
-// Otherwise, falls back to .beads in the given repoPath (or cwd if empty).
+// Otherwise, uses .beads in the given repoPath (or cwd if empty), or in its
+// nearest parent that has one, so bv works from inside a subdirectory.
+// When no .beads exists anywhere up the tree, returns repoPath/.beads.
 func GetBeadsDir(repoPath string) (string, error) {
 	// Check BEADS_DIR environment variable first
 	if envDir := os.Getenv(BeadsDirEnvVar); envDir != "" {
@@ -37,7 +53,14 @@ func GetBeadsDir(repoPath string) (string, error) {
 		}
 	}
 
-	return filepath.Join(repoPath, ".beads"), nil
+	local := filepath.Join(repoPath, ".beads")
+	if _, err := os.Stat(local); err == nil {
+		return local, nil
+	}
+	if found, ok := FindBeadsDirUp(repoPath); ok {
+		return found, nil
+	}
+	return local, nil
 }
 
 // FindJSONLPath locates the beads JSONL file in the given directory.
@@ -93,7 +116,7 @@ func FindJSONLPathWithWarnings(beadsDir string, warnFunc func(msg string)) (stri
 	}
 
 	if len(candidates) == 0 {
-		return "", fmt.Errorf("no beads JSONL file found in %s", beadsDir)
+		return "", fmt.Errorf("%w in %s", ErrNoBeadsFile, beadsDir)
 	}
 
 	// Priority order for beads files per beads upstream:
@@ -129,19 +152,76 @@ func FindJSONLPathWithWarnings(beadsDir string, warnFunc func(msg string)) (stri
 
 // LoadIssues reads issues from the beads directory.
 // Respects BEADS_DIR environment variable, otherwise uses .beads in repoPath.
-// Automatically finds the correct JSONL file (issues.jsonl preferred, beads.jsonl fallback).
+// Automatically finds the correct JSONL file (issues.jsonl preferred, beads.jsonl fallback)
+// and reads .beads/beads.db instead when there is no JSONL export.
 func LoadIssues(repoPath string) ([]model.Issue, error) {
 	beadsDir, err := GetBeadsDir(repoPath)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkExplicitBeadsDir(beadsDir); err != nil {
+		return nil, err
+	}
 
-	jsonlPath, err := FindJSONLPath(beadsDir)
+	return LoadBeadsDir(beadsDir, ParseOptions{})
+}
+
+// LoadIssuesWithOptions is LoadIssues with custom parse options.
+func LoadIssuesWithOptions(repoPath string, opts ParseOptions) ([]model.Issue, error) {
+	beadsDir, err := GetBeadsDir(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkExplicitBeadsDir(beadsDir); err != nil {
+		return nil, err
+	}
+
+	return LoadBeadsDir(beadsDir, opts)
+}
+
+// checkExplicitBeadsDir rejects a BEADS_DIR that names a missing directory.
+// A project without .beads is just empty, but a BEADS_DIR pointing nowhere
+// is a misconfiguration, so this error is deliberately not IsNoBeadsData.
+func checkExplicitBeadsDir(beadsDir string) error {
+	if envDir := os.Getenv(BeadsDirEnvVar); envDir == "" || envDir != beadsDir {
+		return nil
+	}
+	if _, err := os.Stat(beadsDir); errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%s=%s does not exist", BeadsDirEnvVar, beadsDir)
+	}
+	return nil
+}
+
+// LoadBeadsDir loads the beads data FindBeadsSource picks in beadsDir,
+// ignoring BEADS_DIR.
+func LoadBeadsDir(beadsDir string, opts ParseOptions) ([]model.Issue, error) {
+	path, isDB, err := FindBeadsSource(beadsDir)
 	if err != nil {
 		return nil, err
 	}
+	if isDB {
+		return LoadIssuesFromDB(path, opts)
+	}
+	return LoadIssuesFromFileWithOptions(path, opts)
+}
 
-	return LoadIssuesFromFile(jsonlPath)
+// FindBeadsSource picks the file bv reads from beadsDir: the JSONL export
+// found by FindJSONLPath, or the bd SQLite database (isDB) when there is no
+// JSONL export or it is empty.
+func FindBeadsSource(beadsDir string) (path string, isDB bool, err error) {
+	jsonlPath, err := FindJSONLPath(beadsDir)
+	if err != nil {
+		if dbPath := FindDBPath(beadsDir); dbPath != "" && errors.Is(err, ErrNoBeadsFile) {
+			return dbPath, true, nil
+		}
+		return "", false, err
+	}
+	if info, statErr := os.Stat(jsonlPath); statErr == nil && info.Size() == 0 {
+		if dbPath := FindDBPath(beadsDir); dbPath != "" {
+			return dbPath, true, nil
+		}
+	}
+	return jsonlPath, false, nil
 }
 
 // DefaultMaxBufferSize is the default buffer size for the scanner (10MB).
@@ -157,6 +237,33 @@ type ParseOptions struct {
 	// Lines longer than this are skipped with a warning.
 	// If 0, uses DefaultMaxBufferSize (10MB).
 	BufferSize int
+
+	// Strict makes duplicate bead IDs an error instead of a warning.
+	Strict bool
+
+	// DuplicateHandler, if set, receives the duplicated IDs (sorted by ID)
+	// after the last-wins dedupe, in addition to the warning.
+	DuplicateHandler func([]DuplicateID)
+
+	// DefaultPriorities maps a lowercase issue type to the priority given to
+	// beads of that type that have none: a JSONL line without "priority", or
+	// a NULL or missing priority column in SQLite.
+	DefaultPriorities map[string]int
+}
+
+// DuplicateID is a bead ID that appeared on more than one line.
+type DuplicateID struct {
+	ID    string `json:"id"`
+	Count int    `json:"count"` // Lines carrying the ID; the last one wins
+}
+
+// FormatDuplicateIDs renders duplicates as "a (2 lines), b (3 lines)".
+func FormatDuplicateIDs(dups []DuplicateID) string {
+	parts := make([]string, len(dups))
+	for i, d := range dups {
+		parts[i] = fmt.Sprintf("%s (%d lines)", d.ID, d.Count)
+	}
+	return strings.Join(parts, ", ")
 }
 
 // LoadIssuesFromFileWithOptions reads issues from a file with custom options.
@@ -266,11 +373,14 @@ func parseIssuesWithOptions(r io.Reader, opts ParseOptions, usePool bool) ([]mod
 			warn = func(string) {}
 		} else {
 			warn = func(msg string) {
-				fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+				logging.Warn(msg)
 			}
 		}
 	}
 
+	// blocks shorthand by bead ID; applied once every bead is loaded
+	blocksShorthand := map[string][]string{}
+
 	lineNum := 0
 	for {
 		lineNum++
@@ -332,6 +442,8 @@ func parseIssuesWithOptions(r io.Reader, opts ParseOptions, usePool bool) ([]mod
 				continue
 			}
 
+			applyDefaultPriority(issue, hasJSONKey(line, "priority"), opts.DefaultPriorities)
+			applyDepShorthand(issue, line, lineNum, blocksShorthand, warn)
 			issues = append(issues, *issue)
 			poolRefs = append(poolRefs, issue)
 		} else {
@@ -349,13 +461,187 @@ func parseIssuesWithOptions(r io.Reader, opts ParseOptions, usePool bool) ([]mod
 				continue
 			}
 
+			applyDefaultPriority(&issue, hasJSONKey(line, "priority"), opts.DefaultPriorities)
+			applyDepShorthand(&issue, line, lineNum, blocksShorthand, warn)
 			issues = append(issues, issue)
 		}
 	}
 
+	issues, poolRefs, dups := dedupeIssues(issues, poolRefs)
+	if len(dups) > 0 {
+		if opts.Strict {
+			if usePool {
+				ReturnIssuePtrsToPool(poolRefs)
+			}
+			return nil, nil, fmt.Errorf("duplicate bead IDs: %s", FormatDuplicateIDs(dups))
+		}
+		warn("duplicate bead IDs (last occurrence kept): " + FormatDuplicateIDs(dups))
+		if opts.DuplicateHandler != nil {
+			opts.DuplicateHandler(dups)
+		}
+	}
+	if len(blocksShorthand) > 0 {
+		applyBlocksShorthand(issues, blocksShorthand, warn)
+	}
+
 	return issues, poolRefs, nil
 }
 
+// depShorthand is the hand-written alternative to a "dependencies" array:
+// blocked_by lists the beads this one waits on, blocks the beads waiting on it.
+type depShorthand struct {
+	BlockedBy []string `json:"blocked_by"`
+	Blocks    []string `json:"blocks"`
+}
+
+// applyDepShorthand turns the line's blocked_by list into blocking
+// dependencies on issue and records its blocks list, which needs the other
+// beads, in blocksByID. Lines without either key are only byte-scanned.
+func applyDepShorthand(issue *model.Issue, line []byte, lineNum int, blocksByID map[string][]string, warn func(string)) {
+	if !bytes.Contains(line, []byte(`"blocked_by"`)) && !hasJSONKey(line, "blocks") {
+		if len(blocksByID) > 0 {
+			delete(blocksByID, issue.ID) // a later duplicate replaces the earlier line
+		}
+		return
+	}
+	var sh depShorthand
+	if err := json.Unmarshal(line, &sh); err != nil {
+		warn(fmt.Sprintf("ignoring blocked_by/blocks on line %d: %v", lineNum, err))
+		return
+	}
+	for _, id := range sh.BlockedBy {
+		addBlockingDep(issue, strings.TrimSpace(id), "blocked_by", warn)
+	}
+	if len(sh.Blocks) > 0 {
+		blocksByID[issue.ID] = sh.Blocks
+	} else {
+		delete(blocksByID, issue.ID)
+	}
+}
+
+// applyBlocksShorthand adds, for every bead X listing Y in blocks, the
+// dependency "Y depends on X" to bead Y.
+func applyBlocksShorthand(issues []model.Issue, blocksByID map[string][]string, warn func(string)) {
+	index := make(map[string]int, len(issues))
+	for i := range issues {
+		index[issues[i].ID] = i
+	}
+	for i := range issues {
+		blocker := issues[i].ID
+		for _, id := range blocksByID[blocker] {
+			id = strings.TrimSpace(id)
+			j, ok := index[id]
+			if !ok {
+				if id != "" {
+					warn(fmt.Sprintf("bead %s: blocks unknown bead %q; ignored", blocker, id))
+				}
+				continue
+			}
+			if hasBlockingDep(&issues[i], id) {
+				warn(fmt.Sprintf("bead %s both blocks and is blocked by %s; keeping both edges", blocker, id))
+			}
+			addBlockingDep(&issues[j], blocker, blocker+"'s blocks list", warn)
+		}
+	}
+}
+
+// addBlockingDep merges a shorthand edge "issue depends on dependsOn" into
+// issue.Dependencies. An existing blocking edge makes it a no-op; an existing
+// edge of another type is a contradiction, reported and kept alongside it.
+func addBlockingDep(issue *model.Issue, dependsOn, source string, warn func(string)) {
+	if dependsOn == "" {
+		return
+	}
+	if dependsOn == issue.ID {
+		warn(fmt.Sprintf("bead %s: %s names the bead itself; ignored", issue.ID, source))
+		return
+	}
+	if hasBlockingDep(issue, dependsOn) {
+		return
+	}
+	for _, dep := range issue.Dependencies {
+		if dep != nil && dep.DependsOnID == dependsOn {
+			warn(fmt.Sprintf("bead %s: %s marks %s as a blocker but dependencies declare it %q; keeping both", issue.ID, source, dependsOn, dep.Type))
+			break
+		}
+	}
+	issue.Dependencies = append(issue.Dependencies, &model.Dependency{
+		IssueID:     issue.ID,
+		DependsOnID: dependsOn,
+		Type:        model.DepBlocks,
+		CreatedAt:   issue.CreatedAt,
+	})
+}
+
+// hasBlockingDep reports whether issue already has a blocking edge to id.
+func hasBlockingDep(issue *model.Issue, id string) bool {
+	for _, dep := range issue.Dependencies {
+		if dep != nil && dep.DependsOnID == id && dep.Type.IsBlocking() {
+			return true
+		}
+	}
+	return false
+}
+
+// hasJSONKey reports whether line contains "key" used as an object key
+// (followed by a colon), as opposed to a string value like "type":"blocks".
+func hasJSONKey(line []byte, key string) bool {
+	quoted := []byte(`"` + key + `"`)
+	for rest := line; ; {
+		i := bytes.Index(rest, quoted)
+		if i < 0 {
+			return false
+		}
+		rest = bytes.TrimLeft(rest[i+len(quoted):], " \t")
+		if len(rest) > 0 && rest[0] == ':' {
+			return true
+		}
+	}
+}
+
+// dedupeIssues keeps the last occurrence of every ID, in that occurrence's
+// position, and reports the IDs that appeared more than once. poolRefs (when
+// pooling) is kept aligned and dropped issues go back to the pool.
+func dedupeIssues(issues []model.Issue, poolRefs []*model.Issue) ([]model.Issue, []*model.Issue, []DuplicateID) {
+	counts := make(map[string]int, len(issues))
+	for i := range issues {
+		counts[issues[i].ID]++
+	}
+	if len(counts) == len(issues) {
+		return issues, poolRefs, nil
+	}
+
+	var dups []DuplicateID
+	for id, n := range counts {
+		if n > 1 {
+			dups = append(dups, DuplicateID{ID: id, Count: n})
+		}
+	}
+	sort.Slice(dups, func(i, j int) bool { return dups[i].ID < dups[j].ID })
+
+	// Walk in order, skipping occurrences that a later line overrides
+	kept := issues[:0]
+	var keptRefs []*model.Issue
+	if poolRefs != nil {
+		keptRefs = poolRefs[:0]
+	}
+	for i := range issues {
+		id := issues[i].ID
+		counts[id]--
+		if counts[id] > 0 {
+			if poolRefs != nil {
+				PutIssue(poolRefs[i])
+			}
+			continue
+		}
+		kept = append(kept, issues[i])
+		if poolRefs != nil {
+			keptRefs = append(keptRefs, poolRefs[i])
+		}
+	}
+	return kept, keptRefs, dups
+}
+
 // stripBOM removes the UTF-8 Byte Order Mark if present
 func stripBOM(b []byte) []byte {
 	if bytes.HasPrefix(b, []byte{0xEF, 0xBB, 0xBF}) {
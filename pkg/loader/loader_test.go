@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 )
 
 // =============================================================================
@@ -23,6 +24,9 @@ func TestFindJSONLPath_NonExistentDirectory(t *testing.T) {
 	if !strings.Contains(err.Error(), "failed to read beads directory") {
 		t.Errorf("Expected 'failed to read beads directory' error, got: %v", err)
 	}
+	if !loader.IsNoBeadsData(err) {
+		t.Errorf("Expected a missing directory to count as no beads data, got: %v", err)
+	}
 }
 
 func TestFindJSONLPath_EmptyDirectory(t *testing.T) {
@@ -34,6 +38,9 @@ func TestFindJSONLPath_EmptyDirectory(t *testing.T) {
 	if !strings.Contains(err.Error(), "no beads JSONL file found") {
 		t.Errorf("Expected 'no beads JSONL file found' error, got: %v", err)
 	}
+	if !loader.IsNoBeadsData(err) {
+		t.Errorf("Expected an empty directory to count as no beads data, got: %v", err)
+	}
 }
 
 func TestFindJSONLPath_NoJSONLFiles(t *testing.T) {
@@ -765,6 +772,25 @@ func TestGetBeadsDir_EnvVarOverridesRepoPath(t *testing.T) {
 	}
 }
 
+func TestLoadIssues_MissingExplicitBeadsDirIsAnError(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "nope")
+	t.Setenv(loader.BeadsDirEnvVar, missing)
+
+	_, err := loader.LoadIssuesWithOptions(t.TempDir(), loader.ParseOptions{})
+	if err == nil {
+		t.Fatal("expected an error for a BEADS_DIR that does not exist")
+	}
+	if loader.IsNoBeadsData(err) {
+		t.Errorf("a missing BEADS_DIR must not be treated as an empty project: %v", err)
+	}
+
+	// A missing default .beads is still just an empty project.
+	t.Setenv(loader.BeadsDirEnvVar, "")
+	if _, err := loader.LoadIssuesWithOptions(t.TempDir(), loader.ParseOptions{}); !loader.IsNoBeadsData(err) {
+		t.Errorf("expected no-beads-data for a project without .beads, got %v", err)
+	}
+}
+
 func TestGetBeadsDir_FallsBackToBeadsDir(t *testing.T) {
 	// Unset environment variable
 	oldVal := os.Getenv(loader.BeadsDirEnvVar)
@@ -796,6 +822,8 @@ func TestGetBeadsDir_EmptyRepoPath_UsesCwd(t *testing.T) {
 			os.Setenv(loader.BeadsDirEnvVar, oldVal)
 		}
 	}()
+	// Away from this repo's own .beads, which would be found upward
+	t.Chdir(t.TempDir())
 	
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -835,3 +863,142 @@ func TestGetBeadsDir_EnvVarEmpty_FallsBack(t *testing.T) {
 		t.Errorf("Empty BEADS_DIR should fallback: got %s, want %s", result, expected)
 	}
 }
+
+// =============================================================================
+// Duplicate ID Tests
+// =============================================================================
+
+func TestParseIssues_DuplicateIDsLastWins(t *testing.T) {
+	content := `{"id":"a","title":"First","status":"open","issue_type":"task"}
+{"id":"b","title":"Other","status":"open","issue_type":"task"}
+{"id":"a","title":"Second","status":"open","issue_type":"task"}
+{"id":"a","title":"Third","status":"closed","issue_type":"task"}
+`
+	for _, pooled := range []bool{false, true} {
+		var warnings []string
+		var dups []loader.DuplicateID
+		opts := loader.ParseOptions{
+			WarningHandler:   func(msg string) { warnings = append(warnings, msg) },
+			DuplicateHandler: func(d []loader.DuplicateID) { dups = d },
+		}
+		var issues []model.Issue
+		var err error
+		if pooled {
+			var loaded loader.PooledIssues
+			loaded, err = loader.ParseIssuesWithOptionsPooled(strings.NewReader(content), opts)
+			issues = loaded.Issues
+		} else {
+			issues, err = loader.ParseIssuesWithOptions(strings.NewReader(content), opts)
+		}
+		if err != nil {
+			t.Fatalf("pooled=%v: unexpected error: %v", pooled, err)
+		}
+		if len(issues) != 2 || issues[0].ID != "b" || issues[1].ID != "a" || issues[1].Title != "Third" {
+			t.Errorf("pooled=%v: expected b then the last a, got %+v", pooled, issues)
+		}
+		if len(dups) != 1 || dups[0] != (loader.DuplicateID{ID: "a", Count: 3}) {
+			t.Errorf("pooled=%v: unexpected duplicates %+v", pooled, dups)
+		}
+		if len(warnings) != 1 || !strings.Contains(warnings[0], "a (3 lines)") {
+			t.Errorf("pooled=%v: expected a duplicate warning, got %v", pooled, warnings)
+		}
+	}
+
+	_, err := loader.ParseIssuesWithOptions(strings.NewReader(content), loader.ParseOptions{Strict: true})
+	if err == nil || !strings.Contains(err.Error(), "duplicate bead IDs: a (3 lines)") {
+		t.Errorf("expected strict mode to fail on duplicates, got %v", err)
+	}
+}
+
+// =============================================================================
+// blocked_by / blocks Shorthand Tests
+// =============================================================================
+
+// blockingDeps lists issue's blocking dependency targets, in order.
+func blockingDeps(issue model.Issue) []string {
+	var ids []string
+	for _, dep := range issue.Dependencies {
+		if dep.Type.IsBlocking() {
+			ids = append(ids, dep.DependsOnID)
+		}
+	}
+	return ids
+}
+
+func TestParseIssues_DependencyShorthandOnly(t *testing.T) {
+	content := `{"id":"api","title":"API","status":"open","issue_type":"task","blocked_by":["db","auth"]}
+{"id":"db","title":"DB","status":"open","issue_type":"task","blocks" : ["ui"]}
+{"id":"auth","title":"Auth","status":"open","issue_type":"task"}
+{"id":"ui","title":"UI","status":"open","issue_type":"task"}
+`
+	var warnings []string
+	issues, err := loader.ParseIssuesWithOptions(strings.NewReader(content), loader.ParseOptions{
+		WarningHandler: func(msg string) { warnings = append(warnings, msg) },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	byID := map[string]model.Issue{}
+	for _, iss := range issues {
+		byID[iss.ID] = iss
+	}
+
+	if got := blockingDeps(byID["api"]); strings.Join(got, ",") != "db,auth" {
+		t.Errorf("api: expected blocked by db,auth, got %v", got)
+	}
+	if got := blockingDeps(byID["ui"]); strings.Join(got, ",") != "db" {
+		t.Errorf("ui: expected blocked by db via db's blocks list, got %v", got)
+	}
+	if dep := byID["api"].Dependencies[0]; dep.IssueID != "api" || dep.Type != model.DepBlocks {
+		t.Errorf("shorthand edge not normalized: %+v", dep)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+}
+
+func TestParseIssues_DependencyShorthandMergesWithDependencies(t *testing.T) {
+	content := `{"id":"a","title":"A","status":"open","issue_type":"task","blocked_by":["b","c"],"blocks":["d","ghost"],"dependencies":[{"issue_id":"a","depends_on_id":"b","type":"blocks"},{"issue_id":"a","depends_on_id":"c","type":"related"}]}
+{"id":"b","title":"B","status":"open","issue_type":"task"}
+{"id":"c","title":"C","status":"open","issue_type":"task"}
+{"id":"d","title":"D","status":"open","issue_type":"task","blocks":["a"],"dependencies":[{"issue_id":"d","depends_on_id":"a","type":"blocks"}]}
+`
+	for _, pooled := range []bool{false, true} {
+		var warnings []string
+		opts := loader.ParseOptions{WarningHandler: func(msg string) { warnings = append(warnings, msg) }}
+		var issues []model.Issue
+		var err error
+		if pooled {
+			var loaded loader.PooledIssues
+			loaded, err = loader.ParseIssuesWithOptionsPooled(strings.NewReader(content), opts)
+			issues = loaded.Issues
+		} else {
+			issues, err = loader.ParseIssuesWithOptions(strings.NewReader(content), opts)
+		}
+		if err != nil {
+			t.Fatalf("pooled=%v: unexpected error: %v", pooled, err)
+		}
+		byID := map[string]model.Issue{}
+		for _, iss := range issues {
+			byID[iss.ID] = iss
+		}
+
+		// b was already a blocker (no duplicate); c was "related" and gains a blocks edge
+		if got := blockingDeps(byID["a"]); strings.Join(got, ",") != "b,c,d" {
+			t.Errorf("pooled=%v: a: expected blockers b,c,d, got %v", pooled, got)
+		}
+		if len(byID["a"].Dependencies) != 4 {
+			t.Errorf("pooled=%v: a: expected the related edge to be kept, got %d deps", pooled, len(byID["a"].Dependencies))
+		}
+		if got := blockingDeps(byID["d"]); strings.Join(got, ",") != "a" {
+			t.Errorf("pooled=%v: d: expected only the existing edge to a, got %v", pooled, got)
+		}
+
+		joined := strings.Join(warnings, "\n")
+		for _, want := range []string{`declare it "related"`, `unknown bead "ghost"`, "both blocks and is blocked by"} {
+			if !strings.Contains(joined, want) {
+				t.Errorf("pooled=%v: expected a warning containing %q, got %v", pooled, want, warnings)
+			}
+		}
+	}
+}
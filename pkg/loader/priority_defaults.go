@@ -0,0 +1,77 @@
+package loader
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// PriorityDeviationThreshold is how many levels a bead's priority may differ
+// from its type's configured default before CheckTypePriorities warns, so a
+// P4 bug is flagged when bugs default to P1 but a P2 bug is not.
+const PriorityDeviationThreshold = 3
+
+// maxDeviationIDs caps how many bead IDs one CheckTypePriorities warning
+// lists.
+const maxDeviationIDs = 5
+
+// applyDefaultPriority sets the configured default for the bead's type when
+// its source gave no priority: a JSONL line without a "priority" key (which
+// would otherwise decode as P0) or a NULL or missing SQLite column. Both
+// loaders call it, so the defaults mean the same thing for either source.
+func applyDefaultPriority(issue *model.Issue, hasPriority bool, defaults map[string]int) {
+	if len(defaults) == 0 || hasPriority {
+		return
+	}
+	if p, ok := defaults[strings.ToLower(string(issue.IssueType))]; ok {
+		issue.Priority = p
+	}
+}
+
+// CheckTypePriorities returns one warning per type whose open beads include
+// some at least PriorityDeviationThreshold levels away from the type's
+// configured default, e.g. a P4 bug when bugs default to P1. Each warning
+// counts the beads and names the first few by ID. Closed beads are skipped.
+func CheckTypePriorities(issues []model.Issue, defaults map[string]int) []string {
+	if len(defaults) == 0 {
+		return nil
+	}
+	deviating := make(map[string][]string) // Type -> deviating bead IDs
+	for _, issue := range issues {
+		if issue.Status.IsClosed() || issue.Status.IsTombstone() {
+			continue
+		}
+		typ := strings.ToLower(string(issue.IssueType))
+		def, ok := defaults[typ]
+		if !ok {
+			continue
+		}
+		diff := issue.Priority - def
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff < PriorityDeviationThreshold {
+			continue
+		}
+		deviating[typ] = append(deviating[typ], fmt.Sprintf("%s (P%d)", issue.ID, issue.Priority))
+	}
+
+	warnings := make([]string, 0, len(deviating))
+	for typ, beads := range deviating {
+		sort.Strings(beads)
+		listed := strings.Join(beads[:min(len(beads), maxDeviationIDs)], ", ")
+		if len(beads) > maxDeviationIDs {
+			listed += fmt.Sprintf(" and %d more", len(beads)-maxDeviationIDs)
+		}
+		noun := "beads"
+		if len(beads) == 1 {
+			noun = "bead"
+		}
+		warnings = append(warnings, fmt.Sprintf("%d open %s %s %d+ levels from the P%d default for %s: %s",
+			len(beads), typ, noun, PriorityDeviationThreshold, defaults[typ], typ, listed))
+	}
+	sort.Strings(warnings)
+	return warnings
+}
@@ -0,0 +1,58 @@
+package loader
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+func TestParseIssues_AppliesTypeDefaultPriorities(t *testing.T) {
+	data := `{"id":"bug-1","title":"Crash","status":"open","issue_type":"bug"}
+{"id":"bug-2","title":"Explicit P0","status":"open","issue_type":"bug","priority":0}
+{"id":"doc-1","title":"Typo","status":"open","issue_type":"docs"}
+{"id":"task-1","title":"No default","status":"open","issue_type":"task"}
+{"id":"bug-3","title":"Mislabeled","status":"open","issue_type":"bug","priority":4}
+`
+	defaults := map[string]int{"bug": 1, "docs": 3}
+	issues, err := ParseIssuesWithOptions(strings.NewReader(data), ParseOptions{DefaultPriorities: defaults})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]int{}
+	for _, issue := range issues {
+		got[issue.ID] = issue.Priority
+	}
+	want := map[string]int{"bug-1": 1, "bug-2": 0, "doc-1": 3, "task-1": 0, "bug-3": 4}
+	for id, p := range want {
+		if got[id] != p {
+			t.Errorf("%s: priority = %d, want %d", id, got[id], p)
+		}
+	}
+
+	warnings := CheckTypePriorities(issues, defaults)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "bug-3 (P4)") {
+		t.Fatalf("expected one warning for the P4 bug, got %v", warnings)
+	}
+}
+
+func TestCheckTypePriorities_AggregatesPerType(t *testing.T) {
+	var issues []model.Issue
+	for i := 0; i < 8; i++ {
+		issues = append(issues, model.Issue{ID: fmt.Sprintf("bug-%d", i), Status: model.StatusOpen, IssueType: model.TypeBug, Priority: 4})
+	}
+	issues = append(issues, model.Issue{ID: "doc-1", Status: model.StatusOpen, IssueType: "docs", Priority: 0})
+
+	warnings := CheckTypePriorities(issues, map[string]int{"bug": 1, "docs": 3})
+	if len(warnings) != 2 {
+		t.Fatalf("expected one warning per type, got %v", warnings)
+	}
+	if !strings.HasPrefix(warnings[0], "1 open docs bead ") || !strings.HasSuffix(warnings[0], ": doc-1 (P0)") {
+		t.Errorf("docs warning = %q", warnings[0])
+	}
+	if !strings.HasPrefix(warnings[1], "8 open bug beads ") || !strings.HasSuffix(warnings[1], "bug-4 (P4) and 3 more") {
+		t.Errorf("bug warning = %q", warnings[1])
+	}
+}
@@ -0,0 +1,205 @@
+package loader
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// ErrBeadsFileChanged is returned by UpdatePriorities when the JSONL file was
+// modified by someone else between reading it and replacing it.
+var ErrBeadsFileChanged = errors.New("beads file changed while updating; re-run to apply against the new contents")
+
+// PriorityUpdate is one rewritten bead line.
+type PriorityUpdate struct {
+	ID      string `json:"id"`
+	From    int    `json:"from"`
+	To      int    `json:"to"`
+	Line    int    `json:"line"` // 1-based line number in the JSONL file
+	OldLine string `json:"-"`
+	NewLine string `json:"-"`
+}
+
+// UpdatePriorities sets the priority (and bumps updated_at to now) of the beads
+// in priorities, rewriting only their lines of the JSONL file at path; every
+// other line and every other field is kept byte for byte. Beads already at the
+// requested priority and IDs not in the file are skipped.
+//
+// With dryRun the updates are computed but nothing is written. Otherwise the
+// file is replaced atomically (temp file + rename) while holding a lock on it,
+// and only if its contents are still what was read, so a concurrent write is
+// never clobbered; in that case ErrBeadsFileChanged is returned and nothing is
+// written.
+func UpdatePriorities(path string, priorities map[string]int, now time.Time, dryRun bool) ([]PriorityUpdate, error) {
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read beads file: %w", err)
+	}
+
+	updatedAt, err := json.Marshal(now.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return nil, err
+	}
+
+	var updates []PriorityUpdate
+	lines := bytes.SplitAfter(original, []byte("\n"))
+	for i, raw := range lines {
+		line := bytes.TrimRight(raw, "\r\n")
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var head struct {
+			ID       string `json:"id"`
+			Priority int    `json:"priority"`
+		}
+		if err := json.Unmarshal(stripBOM(line), &head); err != nil {
+			continue // Malformed lines are the loader's business; leave them alone
+		}
+		to, ok := priorities[head.ID]
+		if !ok || to == head.Priority {
+			continue
+		}
+
+		bom := line[:len(line)-len(stripBOM(line))]
+		rewritten := setJSONField(line[len(bom):], "priority", []byte(strconv.Itoa(to)))
+		rewritten = setJSONField(rewritten, "updated_at", updatedAt)
+		if rewritten == nil {
+			return nil, fmt.Errorf("line %d (%s): not a JSON object", i+1, head.ID)
+		}
+		rewritten = append(append([]byte{}, bom...), rewritten...)
+		updates = append(updates, PriorityUpdate{
+			ID:      head.ID,
+			From:    head.Priority,
+			To:      to,
+			Line:    i + 1,
+			OldLine: string(line),
+			NewLine: string(rewritten),
+		})
+		lines[i] = append(rewritten, raw[len(line):]...)
+	}
+
+	if dryRun || len(updates) == 0 {
+		return updates, nil
+	}
+	if err := replaceIfUnchanged(path, original, bytes.Join(lines, nil)); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+// replaceIfUnchanged writes content to a temp file next to path and renames it
+// over path, provided path still holds original. The check and the rename
+// happen under an exclusive lock on path, so concurrent bv writers are
+// serialised; a writer that got the lock after the file was replaced sees
+// that path is no longer the file it locked and gives up.
+func replaceIfUnchanged(path string, original, content []byte) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open beads file: %w", err)
+	}
+	defer f.Close()
+	if err := lockFile(f); err != nil {
+		return fmt.Errorf("failed to lock beads file: %w", err)
+	}
+	defer func() { _ = unlockFile(f) }()
+
+	locked, err := checkUnchanged(f, path, original)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+	_ = os.Chmod(tmpName, locked.Mode().Perm())
+
+	if err := os.Rename(tmpName, path); err != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return nil
+}
+
+// checkUnchanged returns the stat of the open (and locked) file f, or
+// ErrBeadsFileChanged if path no longer names f or f no longer holds original.
+func checkUnchanged(f *os.File, path string, original []byte) (os.FileInfo, error) {
+	locked, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat beads file: %w", err)
+	}
+	if info, err := os.Stat(path); err != nil || !os.SameFile(info, locked) {
+		return nil, ErrBeadsFileChanged
+	}
+	current, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-read beads file: %w", err)
+	}
+	if !bytes.Equal(current, original) {
+		return nil, ErrBeadsFileChanged
+	}
+	return locked, nil
+}
+
+// setJSONField replaces the value of a top-level key in the JSON object obj,
+// or appends the key when it is missing, without re-encoding anything else.
+// Returns nil if obj is not a JSON object.
+func setJSONField(obj []byte, key string, value []byte) []byte {
+	if obj == nil {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(obj))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil
+	}
+	fields := 0
+	for ; dec.More(); fields++ {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+		var val json.RawMessage
+		if err := dec.Decode(&val); err != nil {
+			return nil
+		}
+		if name, _ := tok.(string); name == key {
+			end := int(dec.InputOffset())
+			start := end - len(val)
+			out := make([]byte, 0, len(obj)-len(val)+len(value))
+			out = append(out, obj[:start]...)
+			out = append(out, value...)
+			return append(out, obj[end:]...)
+		}
+	}
+
+	closing := bytes.LastIndexByte(obj, '}')
+	if closing < 0 {
+		return nil
+	}
+	field, _ := json.Marshal(key)
+	out := make([]byte, 0, len(obj)+len(field)+len(value)+2)
+	out = append(out, bytes.TrimRight(obj[:closing], " \t")...)
+	if fields > 0 {
+		out = append(out, ',')
+	}
+	out = append(out, field...)
+	out = append(out, ':')
+	out = append(out, value...)
+	return append(out, obj[closing:]...)
+}
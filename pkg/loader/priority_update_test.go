@@ -0,0 +1,102 @@
+package loader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUpdatePriorities_RewritesOnlyTargetLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "issues.jsonl")
+	original := `{"id":"A","title":"Keep","priority":2,"custom":{"x":1},"updated_at":"2024-01-01T00:00:00Z"}
+{"id":"B","title":"Change", "priority": 3 ,"status":"open","updated_at":"2024-01-01T00:00:00Z"}
+{"id":"C","title":"No priority yet"}
+`
+	if err := os.WriteFile(path, []byte(original), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	updates, err := UpdatePriorities(path, map[string]int{"A": 2, "B": 1, "C": 2, "missing": 0}, now, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(updates) != 2 || updates[0].ID != "B" || updates[0].From != 3 || updates[0].To != 1 || updates[0].Line != 2 {
+		t.Fatalf("unexpected updates: %+v", updates)
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Fatal("dry run must not write")
+	}
+
+	if _, err := UpdatePriorities(path, map[string]int{"A": 2, "B": 1, "C": 2}, now, false); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	want := `{"id":"A","title":"Keep","priority":2,"custom":{"x":1},"updated_at":"2024-01-01T00:00:00Z"}
+{"id":"B","title":"Change", "priority": 1 ,"status":"open","updated_at":"2025-06-01T12:00:00Z"}
+{"id":"C","title":"No priority yet","priority":2,"updated_at":"2025-06-01T12:00:00Z"}
+`
+	if string(data) != want {
+		t.Fatalf("rewritten file:\n%s\nwant:\n%s", data, want)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("file mode changed to %v", info.Mode().Perm())
+	}
+}
+
+func TestReplaceIfUnchanged_RefusesConcurrentChange(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "issues.jsonl")
+	if err := os.WriteFile(path, []byte("{\"id\":\"A\",\"priority\":1}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := replaceIfUnchanged(path, []byte("{\"id\":\"A\",\"priority\":2}\n"), []byte("{\"id\":\"A\",\"priority\":0}\n"))
+	if !errors.Is(err, ErrBeadsFileChanged) {
+		t.Fatalf("expected ErrBeadsFileChanged, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "{\"id\":\"A\",\"priority\":1}\n" {
+		t.Fatalf("file was modified: %s", data)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Fatalf("temp file left behind: %v", entries)
+	}
+}
+
+func TestCheckUnchanged_DetectsReplacedFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "issues.jsonl")
+	original := []byte("{\"id\":\"A\",\"priority\":1}\n")
+	if err := os.WriteFile(path, original, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// Another writer renamed identical bytes over path after f was opened:
+	// f is a stale inode, so writing over path now could drop its changes
+	replacement := filepath.Join(dir, "other")
+	if err := os.WriteFile(replacement, original, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(replacement, path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := checkUnchanged(f, path, original); !errors.Is(err, ErrBeadsFileChanged) {
+		t.Fatalf("expected ErrBeadsFileChanged for a replaced file, got %v", err)
+	}
+
+	g, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+	if _, err := checkUnchanged(g, path, original); err != nil {
+		t.Fatalf("unchanged file rejected: %v", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/Dicklesworthstone/beads_viewer/pkg/logging"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 )
 
@@ -52,7 +53,7 @@ func ParseSprints(r io.Reader) ([]model.Sprint, error) {
 	var sprints []model.Sprint
 
 	warn := func(msg string) {
-		fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+		logging.Warn(msg)
 	}
 	if os.Getenv("BV_ROBOT") == "1" {
 		warn = func(string) {}
@@ -0,0 +1,335 @@
+package loader
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/logging"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	_ "modernc.org/sqlite"
+)
+
+// BeadsDBName is the SQLite database bd keeps in the beads directory.
+const BeadsDBName = "beads.db"
+
+// FindDBPath returns the path of the beads SQLite database in beadsDir, or ""
+// if there is none.
+func FindDBPath(beadsDir string) string {
+	path := filepath.Join(beadsDir, BeadsDBName)
+	if info, err := os.Stat(path); err == nil && !info.IsDir() && info.Size() > 0 {
+		return path
+	}
+	return ""
+}
+
+// LoadIssuesFromDB reads issues from a bd SQLite database: the issues table
+// plus dependencies, labels and comments when those tables exist. Columns
+// are matched by name, so databases from older or newer bd versions load as
+// long as they have id, title and status. Invalid issues are skipped with a
+// warning, as in the JSONL loader. The database is opened read-only.
+func LoadIssuesFromDB(path string, opts ParseOptions) ([]model.Issue, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("open beads database: %w", err)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("open beads database: %w", err)
+	}
+	dsn := (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs), RawQuery: "mode=ro"}).String()
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open beads database: %w", err)
+	}
+	defer db.Close()
+
+	warn := opts.WarningHandler
+	if warn == nil {
+		if os.Getenv("BV_ROBOT") == "1" {
+			warn = func(string) {}
+		} else {
+			warn = func(msg string) {
+				logging.Warn(msg)
+			}
+		}
+	}
+
+	issues, err := readDBIssues(db, opts.DefaultPriorities, warn)
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]*model.Issue, len(issues))
+	for i := range issues {
+		index[issues[i].ID] = &issues[i]
+	}
+	if err := readDBDependencies(db, index); err != nil {
+		return nil, err
+	}
+	if err := readDBLabels(db, index); err != nil {
+		return nil, err
+	}
+	if err := readDBComments(db, index); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+// dbColumns returns the column names of table, or nil if it does not exist.
+func dbColumns(db *sql.DB, table string) (map[string]bool, error) {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return nil, fmt.Errorf("inspect %s table: %w", table, err)
+	}
+	defer rows.Close()
+	cols := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("inspect %s table: %w", table, err)
+		}
+		cols[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("inspect %s table: %w", table, err)
+	}
+	if len(cols) == 0 {
+		return nil, nil
+	}
+	return cols, nil
+}
+
+// dbRows runs SELECT over the wanted columns of table that exist and calls fn
+// with each row as column name -> value. Missing columns are absent from the
+// map. It does nothing when the table does not exist or lacks a required column.
+func dbRows(db *sql.DB, table string, wanted, required []string, fn func(map[string]any) error) (bool, error) {
+	cols, err := dbColumns(db, table)
+	if err != nil || cols == nil {
+		return false, err
+	}
+	for _, c := range required {
+		if !cols[c] {
+			return false, nil
+		}
+	}
+	var selected []string
+	for _, c := range wanted {
+		if cols[c] {
+			selected = append(selected, c)
+		}
+	}
+
+	query := "SELECT " + strings.Join(selected, ", ") + " FROM " + table
+	if cols["id"] {
+		query += " ORDER BY id"
+	}
+	rows, err := db.Query(query)
+	if err != nil {
+		return false, fmt.Errorf("read %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	values := make([]any, len(selected))
+	ptrs := make([]any, len(selected))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return false, fmt.Errorf("read %s: %w", table, err)
+		}
+		row := make(map[string]any, len(selected))
+		for i, c := range selected {
+			row[c] = values[i]
+		}
+		if err := fn(row); err != nil {
+			return false, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("read %s: %w", table, err)
+	}
+	return true, nil
+}
+
+func readDBIssues(db *sql.DB, defaultPriorities map[string]int, warn func(string)) ([]model.Issue, error) {
+	wanted := []string{
+		"id", "content_hash", "title", "description", "design", "acceptance_criteria", "notes",
+		"status", "priority", "issue_type", "assignee", "estimated_minutes",
+		"created_at", "updated_at", "due_date", "closed_at", "external_ref",
+		"compaction_level", "compacted_at", "compacted_at_commit", "original_size", "source_repo",
+	}
+	var issues []model.Issue
+	found, err := dbRows(db, "issues", wanted, []string{"id", "title", "status"}, func(row map[string]any) error {
+		issue := model.Issue{
+			ID:                 dbString(row["id"]),
+			ContentHash:        dbString(row["content_hash"]),
+			Title:              dbString(row["title"]),
+			Description:        dbString(row["description"]),
+			Design:             dbString(row["design"]),
+			AcceptanceCriteria: dbString(row["acceptance_criteria"]),
+			Notes:              dbString(row["notes"]),
+			Status:             model.Status(dbString(row["status"])),
+			Priority:           int(dbInt(row["priority"])),
+			IssueType:          model.IssueType(dbString(row["issue_type"])),
+			Assignee:           dbString(row["assignee"]),
+			CompactionLevel:    int(dbInt(row["compaction_level"])),
+			OriginalSize:       int(dbInt(row["original_size"])),
+			SourceRepo:         dbString(row["source_repo"]),
+		}
+		if issue.IssueType == "" {
+			issue.IssueType = model.TypeTask
+		}
+		applyDefaultPriority(&issue, row["priority"] != nil, defaultPriorities)
+		if row["estimated_minutes"] != nil {
+			v := int(dbInt(row["estimated_minutes"]))
+			issue.EstimatedMinutes = &v
+		}
+		if s := dbString(row["external_ref"]); s != "" {
+			issue.ExternalRef = &s
+		}
+		if s := dbString(row["compacted_at_commit"]); s != "" {
+			issue.CompactedAtCommit = &s
+		}
+		issue.CreatedAt, _ = dbTime(row["created_at"])
+		issue.UpdatedAt, _ = dbTime(row["updated_at"])
+		if t, ok := dbTime(row["due_date"]); ok {
+			issue.DueDate = &t
+		}
+		if t, ok := dbTime(row["closed_at"]); ok {
+			issue.ClosedAt = &t
+		}
+		if t, ok := dbTime(row["compacted_at"]); ok {
+			issue.CompactedAt = &t
+		}
+
+		if err := issue.Validate(); err != nil {
+			warn(fmt.Sprintf("skipping invalid issue %q in database: %v", issue.ID, err))
+			return nil
+		}
+		issues = append(issues, issue)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("beads database has no issues table with id, title and status columns")
+	}
+	return issues, nil
+}
+
+func readDBDependencies(db *sql.DB, index map[string]*model.Issue) error {
+	_, err := dbRows(db, "dependencies", []string{"issue_id", "depends_on_id", "type", "created_at", "created_by"},
+		[]string{"issue_id", "depends_on_id"}, func(row map[string]any) error {
+			issue := index[dbString(row["issue_id"])]
+			if issue == nil {
+				return nil
+			}
+			dep := &model.Dependency{
+				IssueID:     issue.ID,
+				DependsOnID: dbString(row["depends_on_id"]),
+				Type:        model.DependencyType(dbString(row["type"])),
+				CreatedBy:   dbString(row["created_by"]),
+			}
+			dep.CreatedAt, _ = dbTime(row["created_at"])
+			issue.Dependencies = append(issue.Dependencies, dep)
+			return nil
+		})
+	return err
+}
+
+func readDBLabels(db *sql.DB, index map[string]*model.Issue) error {
+	_, err := dbRows(db, "labels", []string{"issue_id", "label"}, []string{"issue_id", "label"}, func(row map[string]any) error {
+		if issue := index[dbString(row["issue_id"])]; issue != nil {
+			issue.Labels = append(issue.Labels, dbString(row["label"]))
+		}
+		return nil
+	})
+	return err
+}
+
+func readDBComments(db *sql.DB, index map[string]*model.Issue) error {
+	_, err := dbRows(db, "comments", []string{"id", "issue_id", "author", "text", "created_at"},
+		[]string{"issue_id", "text"}, func(row map[string]any) error {
+			issue := index[dbString(row["issue_id"])]
+			if issue == nil {
+				return nil
+			}
+			comment := &model.Comment{
+				ID:      dbInt(row["id"]),
+				IssueID: issue.ID,
+				Author:  dbString(row["author"]),
+				Text:    dbString(row["text"]),
+			}
+			comment.CreatedAt, _ = dbTime(row["created_at"])
+			issue.Comments = append(issue.Comments, comment)
+			return nil
+		})
+	return err
+}
+
+func dbString(v any) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case []byte:
+		return string(x)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+func dbInt(v any) int64 {
+	switch x := v.(type) {
+	case int64:
+		return x
+	case float64:
+		return int64(x)
+	case string:
+		var n int64
+		_, _ = fmt.Sscan(x, &n)
+		return n
+	case []byte:
+		var n int64
+		_, _ = fmt.Sscan(string(x), &n)
+		return n
+	default:
+		return 0
+	}
+}
+
+// dbTimeLayouts covers the timestamp formats SQLite and bd write.
+var dbTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+func dbTime(v any) (time.Time, bool) {
+	switch x := v.(type) {
+	case time.Time:
+		return x, !x.IsZero()
+	case int64:
+		return time.Unix(x, 0).UTC(), x != 0
+	}
+	s := strings.TrimSpace(dbString(v))
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range dbTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,230 @@
+package loader_test
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteFixture mirrors the parts of the bd schema bv reads.
+const sqliteFixture = `
+CREATE TABLE issues (
+	id TEXT PRIMARY KEY,
+	content_hash TEXT,
+	title TEXT NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	design TEXT NOT NULL DEFAULT '',
+	acceptance_criteria TEXT NOT NULL DEFAULT '',
+	notes TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT 'open',
+	priority INTEGER NOT NULL DEFAULT 2,
+	issue_type TEXT NOT NULL DEFAULT 'task',
+	assignee TEXT,
+	estimated_minutes INTEGER,
+	created_at DATETIME NOT NULL,
+	updated_at DATETIME NOT NULL,
+	closed_at DATETIME,
+	external_ref TEXT
+);
+CREATE TABLE dependencies (
+	issue_id TEXT NOT NULL,
+	depends_on_id TEXT NOT NULL,
+	type TEXT NOT NULL DEFAULT 'blocks',
+	created_at DATETIME NOT NULL,
+	created_by TEXT NOT NULL
+);
+CREATE TABLE labels (issue_id TEXT NOT NULL, label TEXT NOT NULL);
+CREATE TABLE comments (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	issue_id TEXT NOT NULL,
+	author TEXT NOT NULL,
+	text TEXT NOT NULL,
+	created_at DATETIME NOT NULL
+);
+INSERT INTO issues (id, title, description, status, priority, issue_type, assignee, estimated_minutes, created_at, updated_at, closed_at, external_ref) VALUES
+	('bv-1', 'Schema', 'Design tables', 'closed', 1, 'feature', 'alice', 90, '2025-01-01 10:00:00', '2025-01-02T11:00:00Z', '2025-01-02 11:00:00', 'gh-7'),
+	('bv-2', 'API', '', 'open', 0, 'task', NULL, NULL, '2025-01-03 09:30:00', '2025-01-03 09:30:00', NULL, NULL),
+	('bv-3', '', 'missing title', 'open', 2, 'bug', NULL, NULL, '2025-01-03 09:30:00', '2025-01-03 09:30:00', NULL, NULL);
+INSERT INTO dependencies VALUES ('bv-2', 'bv-1', 'blocks', '2025-01-03 09:31:00', 'alice');
+INSERT INTO labels VALUES ('bv-2', 'backend'), ('bv-2', 'api');
+INSERT INTO comments (issue_id, author, text, created_at) VALUES ('bv-1', 'bob', 'Looks good', '2025-01-02 10:00:00');
+`
+
+func writeSQLiteFixture(t *testing.T, path string) {
+	t.Helper()
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open fixture db: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(sqliteFixture); err != nil {
+		t.Fatalf("create fixture db: %v", err)
+	}
+}
+
+func TestLoadIssuesFromDB(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "beads.db")
+	writeSQLiteFixture(t, path)
+
+	var warnings []string
+	issues, err := loader.LoadIssuesFromDB(path, loader.ParseOptions{
+		WarningHandler: func(msg string) { warnings = append(warnings, msg) },
+	})
+	if err != nil {
+		t.Fatalf("LoadIssuesFromDB: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 valid issues, got %d", len(issues))
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "bv-3") {
+		t.Errorf("expected a warning for the invalid bv-3, got %v", warnings)
+	}
+
+	first := issues[0]
+	if first.ID != "bv-1" || first.Status != model.StatusClosed || first.Priority != 1 || first.IssueType != model.TypeFeature {
+		t.Errorf("bv-1 mapped wrongly: %+v", first)
+	}
+	if first.Assignee != "alice" || first.EstimatedMinutes == nil || *first.EstimatedMinutes != 90 {
+		t.Errorf("bv-1 assignee/estimate mapped wrongly: %q %v", first.Assignee, first.EstimatedMinutes)
+	}
+	if first.ExternalRef == nil || *first.ExternalRef != "gh-7" {
+		t.Errorf("bv-1 external_ref = %v, want gh-7", first.ExternalRef)
+	}
+	if want := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC); !first.CreatedAt.Equal(want) {
+		t.Errorf("bv-1 created_at = %v, want %v", first.CreatedAt, want)
+	}
+	if first.ClosedAt == nil {
+		t.Error("bv-1 closed_at not mapped")
+	}
+	if len(first.Comments) != 1 || first.Comments[0].Author != "bob" || first.Comments[0].Text != "Looks good" {
+		t.Errorf("bv-1 comments mapped wrongly: %+v", first.Comments)
+	}
+
+	second := issues[1]
+	if second.Assignee != "" || second.EstimatedMinutes != nil || second.ClosedAt != nil || second.ExternalRef != nil {
+		t.Errorf("NULL columns should map to zero values: %+v", second)
+	}
+	if len(second.Labels) != 2 {
+		t.Errorf("bv-2 labels = %v, want 2", second.Labels)
+	}
+	if len(second.Dependencies) != 1 {
+		t.Fatalf("bv-2 dependencies = %d, want 1", len(second.Dependencies))
+	}
+	dep := second.Dependencies[0]
+	if dep.IssueID != "bv-2" || dep.DependsOnID != "bv-1" || dep.Type != model.DepBlocks || dep.CreatedBy != "alice" {
+		t.Errorf("dependency mapped wrongly: %+v", dep)
+	}
+}
+
+func TestLoadIssuesFromDB_DataHashTracksRows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "beads.db")
+	writeSQLiteFixture(t, path)
+	opts := loader.ParseOptions{WarningHandler: func(string) {}}
+
+	issues, err := loader.LoadIssuesFromDB(path, opts)
+	if err != nil {
+		t.Fatalf("LoadIssuesFromDB: %v", err)
+	}
+	before := analysis.ComputeDataHash(issues)
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open fixture db: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO labels VALUES ('bv-1', 'db')`); err != nil {
+		t.Fatalf("update fixture db: %v", err)
+	}
+	db.Close()
+
+	issues, err = loader.LoadIssuesFromDB(path, opts)
+	if err != nil {
+		t.Fatalf("LoadIssuesFromDB: %v", err)
+	}
+	if after := analysis.ComputeDataHash(issues); after == before {
+		t.Error("data_hash did not change after a labels row was added")
+	}
+}
+
+func TestLoadIssuesFallsBackToDB(t *testing.T) {
+	repo := t.TempDir()
+	beadsDir := filepath.Join(repo, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeSQLiteFixture(t, filepath.Join(beadsDir, loader.BeadsDBName))
+
+	path, isDB, err := loader.FindBeadsSource(beadsDir)
+	if err != nil || !isDB || filepath.Base(path) != loader.BeadsDBName {
+		t.Fatalf("FindBeadsSource = %q, %v, %v; want the database", path, isDB, err)
+	}
+	issues, err := loader.LoadIssuesWithOptions(repo, loader.ParseOptions{WarningHandler: func(string) {}})
+	if err != nil {
+		t.Fatalf("LoadIssuesWithOptions: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues from beads.db, got %d", len(issues))
+	}
+
+	// A non-empty JSONL export takes precedence over the database.
+	jsonl := `{"id":"j-1","title":"From JSONL","status":"open","issue_type":"task","priority":1}` + "\n"
+	if err := os.WriteFile(filepath.Join(beadsDir, "issues.jsonl"), []byte(jsonl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	issues, err = loader.LoadIssuesWithOptions(repo, loader.ParseOptions{WarningHandler: func(string) {}})
+	if err != nil {
+		t.Fatalf("LoadIssuesWithOptions: %v", err)
+	}
+	if len(issues) != 1 || issues[0].ID != "j-1" {
+		t.Errorf("expected the JSONL issue, got %+v", issues)
+	}
+}
+
+func TestLoadIssuesFromDB_MissingFile(t *testing.T) {
+	_, err := loader.LoadIssuesFromDB(filepath.Join(t.TempDir(), "nope.db"), loader.ParseOptions{})
+	if err == nil {
+		t.Fatal("expected an error for a missing database")
+	}
+}
+
+func TestLoadIssuesFromDB_AppliesTypeDefaultPriorities(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "beads.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open fixture db: %v", err)
+	}
+	_, err = db.Exec(`
+CREATE TABLE issues (id TEXT PRIMARY KEY, title TEXT NOT NULL, status TEXT NOT NULL, priority INTEGER, issue_type TEXT);
+INSERT INTO issues VALUES
+	('bug-1', 'No priority', 'open', NULL, 'bug'),
+	('bug-2', 'Explicit P0', 'open', 0, 'bug'),
+	('task-1', 'No default', 'open', NULL, 'task');
+`)
+	db.Close()
+	if err != nil {
+		t.Fatalf("create fixture db: %v", err)
+	}
+
+	issues, err := loader.LoadIssuesFromDB(path, loader.ParseOptions{DefaultPriorities: map[string]int{"bug": 1}})
+	if err != nil {
+		t.Fatalf("LoadIssuesFromDB: %v", err)
+	}
+	got := map[string]int{}
+	for _, issue := range issues {
+		got[issue.ID] = issue.Priority
+	}
+	want := map[string]int{"bug-1": 1, "bug-2": 0, "task-1": 0}
+	for id, p := range want {
+		if got[id] != p {
+			t.Errorf("%s: priority = %d, want %d", id, got[id], p)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+// Package logging is bv's leveled logger. Logs always go to stderr so robot
+// commands keep stdout for data.
+//
+// BV_LOG_LEVEL selects the minimum level (debug, info, warn, error, or off;
+// default warn). BV_LOG_FORMAT=json writes one JSON object per line for
+// machine consumption; the default text format reads like bv's other
+// messages ("Warning: ...").
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Environment variables read by FromEnv.
+const (
+	LevelEnvVar  = "BV_LOG_LEVEL"
+	FormatEnvVar = "BV_LOG_FORMAT"
+)
+
+// LevelOff is above every level bv logs at, silencing the logger.
+const LevelOff = slog.LevelError + 4
+
+// DefaultLevel is used when BV_LOG_LEVEL is unset or invalid.
+const DefaultLevel = slog.LevelWarn
+
+// ParseLevel parses a BV_LOG_LEVEL value. ok is false for unknown values.
+func ParseLevel(raw string) (level slog.Level, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "debug":
+		return slog.LevelDebug, true
+	case "info":
+		return slog.LevelInfo, true
+	case "warn", "warning":
+		return slog.LevelWarn, true
+	case "error":
+		return slog.LevelError, true
+	case "off", "none":
+		return LevelOff, true
+	}
+	return DefaultLevel, false
+}
+
+// New returns a logger writing records at or above level to w, as JSON lines
+// when jsonFormat is set and as "Warning: msg key=value" text otherwise.
+func New(w io.Writer, level slog.Leveler, jsonFormat bool) *slog.Logger {
+	if jsonFormat {
+		return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+	}
+	return slog.New(&textHandler{w: w, mu: &sync.Mutex{}, level: level})
+}
+
+// FromEnv returns a logger writing to w, configured by BV_LOG_LEVEL and
+// BV_LOG_FORMAT. An invalid level falls back to DefaultLevel with a warning.
+func FromEnv(w io.Writer) *slog.Logger {
+	raw := os.Getenv(LevelEnvVar)
+	level, ok := ParseLevel(raw)
+	format := strings.ToLower(strings.TrimSpace(os.Getenv(FormatEnvVar)))
+	logger := New(w, level, format == "json")
+	if raw != "" && !ok {
+		logger.Warn("unknown "+LevelEnvVar+"; using warn", "value", raw)
+	}
+	if format != "" && format != "json" && format != "text" {
+		logger.Warn("unknown "+FormatEnvVar+"; using text", "value", format)
+	}
+	return logger
+}
+
+var (
+	mu  sync.Mutex
+	std *slog.Logger
+)
+
+// Default returns the process-wide logger, built from the environment
+// (writing to stderr) on first use.
+func Default() *slog.Logger {
+	mu.Lock()
+	defer mu.Unlock()
+	if std == nil {
+		std = FromEnv(os.Stderr)
+	}
+	return std
+}
+
+// SetDefault replaces the process-wide logger and returns the previous one
+// (nil if it was never used), e.g. for tests to capture output.
+func SetDefault(l *slog.Logger) *slog.Logger {
+	mu.Lock()
+	defer mu.Unlock()
+	prev := std
+	std = l
+	return prev
+}
+
+// Debug logs at debug level on the default logger.
+func Debug(msg string, args ...any) { Default().Debug(msg, args...) }
+
+// Info logs at info level on the default logger.
+func Info(msg string, args ...any) { Default().Info(msg, args...) }
+
+// Warn logs at warn level on the default logger.
+func Warn(msg string, args ...any) { Default().Warn(msg, args...) }
+
+// Error logs at error level on the default logger.
+func Error(msg string, args ...any) { Default().Error(msg, args...) }
+
+// textHandler formats records as one human-readable line:
+// "Warning: msg key=value ...". Timestamps are left out.
+type textHandler struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	level  slog.Leveler
+	attrs  []slog.Attr
+	prefix string // Group prefix for attribute keys, e.g. "worker."
+}
+
+func (h *textHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *textHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	b.WriteString(levelLabel(r.Level))
+	b.WriteString(": ")
+	b.WriteString(r.Message)
+	for _, a := range h.attrs {
+		writeAttr(&b, "", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		writeAttr(&b, h.prefix, a)
+		return true
+	})
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+func (h *textHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append([]slog.Attr(nil), h.attrs...)
+	for _, a := range attrs {
+		a.Key = h.prefix + a.Key
+		clone.attrs = append(clone.attrs, a)
+	}
+	return &clone
+}
+
+func (h *textHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+func levelLabel(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return "Error"
+	case level >= slog.LevelWarn:
+		return "Warning"
+	case level >= slog.LevelInfo:
+		return "Info"
+	default:
+		return "Debug"
+	}
+}
+
+func writeAttr(b *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		for _, ga := range a.Value.Group() {
+			writeAttr(b, prefix+a.Key+".", ga)
+		}
+		return
+	}
+	value := a.Value.String()
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		value = strconv.Quote(value)
+	}
+	fmt.Fprintf(b, " %s%s=%s", prefix, a.Key, value)
+}
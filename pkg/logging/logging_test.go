@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	for raw, want := range map[string]slog.Level{
+		"debug":    slog.LevelDebug,
+		" INFO ":   slog.LevelInfo,
+		"warn":     slog.LevelWarn,
+		"warning":  slog.LevelWarn,
+		"error":    slog.LevelError,
+		"off":      LevelOff,
+		"verbose?": DefaultLevel,
+	} {
+		if got, _ := ParseLevel(raw); got != want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", raw, got, want)
+		}
+	}
+	if _, ok := ParseLevel("loud"); ok {
+		t.Error("ParseLevel(loud) should not be ok")
+	}
+}
+
+func TestFromEnvLevelsAndText(t *testing.T) {
+	t.Setenv(FormatEnvVar, "")
+	for level, want := range map[string]string{
+		"":      "Warning: low weight w=0.05\nError: failed path=\"a b\"\n",
+		"debug": "Debug: starting\nInfo: loaded count=3\nWarning: low weight w=0.05\nError: failed path=\"a b\"\n",
+		"error": "Error: failed path=\"a b\"\n",
+		"off":   "",
+	} {
+		t.Setenv(LevelEnvVar, level)
+		var buf bytes.Buffer
+		l := FromEnv(&buf)
+		l.Debug("starting")
+		l.Info("loaded", "count", 3)
+		l.Warn("low weight", "w", 0.05)
+		l.Error("failed", "path", "a b")
+		if buf.String() != want {
+			t.Errorf("BV_LOG_LEVEL=%q wrote %q, want %q", level, buf.String(), want)
+		}
+	}
+
+	t.Setenv(LevelEnvVar, "loud")
+	var buf bytes.Buffer
+	FromEnv(&buf).With("component", "x").WithGroup("g").Warn("hi", "k", "v")
+	if want := "Warning: unknown BV_LOG_LEVEL; using warn value=loud\nWarning: hi component=x g.k=v\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestFromEnvJSON(t *testing.T) {
+	t.Setenv(LevelEnvVar, "info")
+	t.Setenv(FormatEnvVar, "json")
+	var buf bytes.Buffer
+	l := FromEnv(&buf)
+	l.Debug("hidden")
+	l.Info("loaded", "count", 3)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("want one JSON line, got %q", buf.String())
+	}
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatalf("invalid JSON %q: %v", lines[0], err)
+	}
+	if rec["level"] != "INFO" || rec["msg"] != "loaded" || rec["count"] != float64(3) || rec["time"] == nil {
+		t.Errorf("record = %v", rec)
+	}
+}
+
+func TestSetDefaultRoutesPackageFuncs(t *testing.T) {
+	var buf bytes.Buffer
+	prev := SetDefault(New(&buf, slog.LevelInfo, false))
+	t.Cleanup(func() { SetDefault(prev) })
+
+	Debug("hidden")
+	Info("shown")
+	if buf.String() != "Info: shown\n" {
+		t.Errorf("got %q", buf.String())
+	}
+}
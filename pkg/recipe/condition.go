@@ -0,0 +1,304 @@
+package recipe
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ConditionVars are the graph-state variables a step's `when:` condition may
+// reference. Booleans are 1 or 0.
+var ConditionVars = map[string]string{
+	"count":             "issues left in the recipe before this step",
+	"issue_count":       "all loaded issues",
+	"open_count":        "issues with status open",
+	"in_progress_count": "issues with status in_progress",
+	"closed_count":      "closed issues",
+	"blocked_count":     "non-closed issues waiting on an open blocker",
+	"actionable_count":  "non-closed issues with no open blockers",
+	"cycle_beads":       "issues that sit in a dependency cycle",
+	"has_cycles":        "1 when the dependency graph has a cycle",
+}
+
+// Condition is a parsed `when:` expression. The language is deliberately
+// tiny: variable names from ConditionVars, integer or decimal literals, the
+// comparisons == != < <= > >=, and !/not, &&/and, ||/or with parentheses.
+// A bare variable is true when non-zero.
+type Condition struct {
+	src  string
+	root condNode
+}
+
+// String returns the expression as written
+func (c *Condition) String() string {
+	return c.src
+}
+
+// Eval evaluates the condition against a variable state
+func (c *Condition) Eval(state map[string]float64) bool {
+	return c.root.eval(state) != 0
+}
+
+// ParseCondition parses a `when:` expression, rejecting unknown variables
+func ParseCondition(src string) (*Condition, error) {
+	toks, err := lexCondition(src)
+	if err != nil {
+		return nil, fmt.Errorf("condition %q: %w", src, err)
+	}
+	if len(toks) == 0 {
+		return nil, fmt.Errorf("condition is empty")
+	}
+	p := &condParser{toks: toks}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("condition %q: %w", src, err)
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("condition %q: unexpected %q", src, p.toks[p.pos].text)
+	}
+	return &Condition{src: src, root: root}, nil
+}
+
+type condNode interface {
+	eval(state map[string]float64) float64
+}
+
+type condVar string
+
+func (v condVar) eval(state map[string]float64) float64 { return state[string(v)] }
+
+type condNum float64
+
+func (n condNum) eval(map[string]float64) float64 { return float64(n) }
+
+type condNot struct{ x condNode }
+
+func (n condNot) eval(state map[string]float64) float64 { return boolNum(n.x.eval(state) == 0) }
+
+type condBinary struct {
+	op   string
+	l, r condNode
+}
+
+func (b condBinary) eval(state map[string]float64) float64 {
+	l := b.l.eval(state)
+	switch b.op {
+	case "&&":
+		return boolNum(l != 0 && b.r.eval(state) != 0)
+	case "||":
+		return boolNum(l != 0 || b.r.eval(state) != 0)
+	}
+	r := b.r.eval(state)
+	switch b.op {
+	case "==":
+		return boolNum(l == r)
+	case "!=":
+		return boolNum(l != r)
+	case "<":
+		return boolNum(l < r)
+	case "<=":
+		return boolNum(l <= r)
+	case ">":
+		return boolNum(l > r)
+	default: // ">="
+		return boolNum(l >= r)
+	}
+}
+
+func boolNum(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+type condTokKind int
+
+const (
+	tokIdent condTokKind = iota
+	tokNum
+	tokOp
+)
+
+type condTok struct {
+	kind condTokKind
+	text string
+}
+
+// lexCondition splits a condition into identifiers, numbers, and operators
+func lexCondition(src string) ([]condTok, error) {
+	var toks []condTok
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '_' || unicode.IsLetter(c):
+			j := i
+			for j < len(src) && (src[j] == '_' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			word := strings.ToLower(src[i:j])
+			switch word {
+			case "and":
+				toks = append(toks, condTok{tokOp, "&&"})
+			case "or":
+				toks = append(toks, condTok{tokOp, "||"})
+			case "not":
+				toks = append(toks, condTok{tokOp, "!"})
+			default:
+				toks = append(toks, condTok{tokIdent, word})
+			}
+			i = j
+		case unicode.IsDigit(c) || c == '.':
+			j := i
+			for j < len(src) && (unicode.IsDigit(rune(src[j])) || src[j] == '.') {
+				j++
+			}
+			toks = append(toks, condTok{tokNum, src[i:j]})
+			i = j
+		default:
+			op := ""
+			for _, candidate := range []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")"} {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q", c)
+			}
+			toks = append(toks, condTok{tokOp, op})
+			i += len(op)
+		}
+	}
+	return toks, nil
+}
+
+// condParser is a recursive-descent parser:
+//
+//	or   := and ("||" and)*
+//	and  := unary ("&&" unary)*
+//	unary:= "!" unary | cmp
+//	cmp  := atom (("=="|"!="|"<"|"<="|">"|">=") atom)?
+//	atom := ident | number | "(" or ")"
+type condParser struct {
+	toks []condTok
+	pos  int
+}
+
+func (p *condParser) peekOp(ops ...string) string {
+	if p.pos >= len(p.toks) || p.toks[p.pos].kind != tokOp {
+		return ""
+	}
+	for _, op := range ops {
+		if p.toks[p.pos].text == op {
+			return op
+		}
+	}
+	return ""
+}
+
+func (p *condParser) parseOr() (condNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOp("||") != "" {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = condBinary{op: "||", l: left, r: right}
+	}
+	return left, nil
+}
+
+func (p *condParser) parseAnd() (condNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOp("&&") != "" {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = condBinary{op: "&&", l: left, r: right}
+	}
+	return left, nil
+}
+
+func (p *condParser) parseUnary() (condNode, error) {
+	if p.peekOp("!") != "" {
+		p.pos++
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return condNot{x: x}, nil
+	}
+	return p.parseCmp()
+}
+
+func (p *condParser) parseCmp() (condNode, error) {
+	left, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+	if op := p.peekOp("==", "!=", "<=", ">=", "<", ">"); op != "" {
+		p.pos++
+		right, err := p.parseAtom()
+		if err != nil {
+			return nil, err
+		}
+		return condBinary{op: op, l: left, r: right}, nil
+	}
+	return left, nil
+}
+
+func (p *condParser) parseAtom() (condNode, error) {
+	if p.pos >= len(p.toks) {
+		return nil, fmt.Errorf("unexpected end")
+	}
+	tok := p.toks[p.pos]
+	p.pos++
+	switch tok.kind {
+	case tokIdent:
+		if _, ok := ConditionVars[tok.text]; !ok {
+			return nil, fmt.Errorf("unknown variable %q (valid: %s)", tok.text, strings.Join(conditionVarNames(), ", "))
+		}
+		return condVar(tok.text), nil
+	case tokNum:
+		n, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", tok.text)
+		}
+		return condNum(n), nil
+	}
+	if tok.text == "(" {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peekOp(")") == "" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return inner, nil
+	}
+	return nil, fmt.Errorf("unexpected %q", tok.text)
+}
+
+func conditionVarNames() []string {
+	names := make([]string, 0, len(ConditionVars))
+	for name := range ConditionVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
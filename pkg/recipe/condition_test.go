@@ -0,0 +1,67 @@
+package recipe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
+)
+
+func TestConditionEval(t *testing.T) {
+	state := map[string]float64{"blocked_count": 3, "open_count": 10, "has_cycles": 0}
+	cases := map[string]bool{
+		"blocked_count > 0":                       true,
+		"blocked_count >= 4":                      false,
+		"has_cycles":                              false,
+		"!has_cycles":                             true,
+		"not has_cycles and open_count == 10":     true,
+		"has_cycles || blocked_count != 3":        false,
+		"(has_cycles or open_count > 5) && count": false, // count is unset, so 0
+	}
+	for src, want := range cases {
+		cond, err := recipe.ParseCondition(src)
+		if err != nil {
+			t.Errorf("ParseCondition(%q): %v", src, err)
+			continue
+		}
+		if got := cond.Eval(state); got != want {
+			t.Errorf("%q = %v, want %v", src, got, want)
+		}
+	}
+}
+
+func TestParseConditionErrors(t *testing.T) {
+	for src, wantErr := range map[string]string{
+		"":                      "empty",
+		"blocked_count >":       "unexpected end",
+		"blocked > 0":           "unknown variable",
+		"blocked_count * 2":     "unexpected character",
+		"(has_cycles":           "parenthesis",
+		"open_count 3":          "unexpected",
+		"os.Exit(1)":            "unknown variable",
+		"blocked_count > 1.2.3": "invalid number",
+	} {
+		if _, err := recipe.ParseCondition(src); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("ParseCondition(%q) error = %v, want it to mention %q", src, err, wantErr)
+		}
+	}
+}
+
+func TestRecipeValidateRejectsBadCondition(t *testing.T) {
+	r := &recipe.Recipe{
+		Name: "sprint",
+		Steps: []recipe.Step{
+			{Name: "only-blocked", When: "blocked_count > 0"},
+			{When: "blocked_count >> 0"},
+		},
+	}
+	err := r.Validate()
+	if err == nil || !strings.Contains(err.Error(), `recipe "sprint" step 2`) {
+		t.Fatalf("Validate() = %v, want an error naming step 2", err)
+	}
+
+	r.Steps = r.Steps[:1]
+	if err := r.Validate(); err != nil {
+		t.Errorf("Validate() on a good recipe: %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package recipe
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Run and step outcomes recorded in the history
+const (
+	OutcomeOK      = "ok"
+	OutcomeFailed  = "failed"
+	OutcomeSkipped = "skipped"
+)
+
+// RunStep records one stage of a recipe run
+type RunStep struct {
+	Name    string `json:"name"`             // filter, sort
+	Outcome string `json:"outcome"`          // ok, failed, skipped
+	Input   int    `json:"input"`            // Issues entering the step
+	Output  int    `json:"output"`           // Issues leaving the step
+	Error   string `json:"error,omitempty"`  // Why the step failed
+	Detail  string `json:"detail,omitempty"` // Extra context, e.g. why it was skipped
+}
+
+// RunRecord is one line of the recipe run history
+type RunRecord struct {
+	Recipe     string            `json:"recipe"`
+	Source     string            `json:"source,omitempty"` // builtin, user, project
+	StartedAt  time.Time         `json:"started_at"`
+	DurationMs float64           `json:"duration_ms"`
+	Params     map[string]string `json:"params,omitempty"` // Filters and sort with relative times resolved
+	Steps      []RunStep         `json:"steps"`
+	Vars       map[string]string `json:"vars,omitempty"` // Values bound by analyze steps
+	Outcome    string            `json:"outcome"`        // ok when every step succeeded, otherwise failed
+}
+
+// Finish sets the duration and derives the run outcome from its steps
+func (r *RunRecord) Finish(end time.Time) {
+	r.DurationMs = float64(end.Sub(r.StartedAt).Microseconds()) / 1000
+	r.Outcome = OutcomeOK
+	for _, step := range r.Steps {
+		if step.Outcome == OutcomeFailed {
+			r.Outcome = OutcomeFailed
+			return
+		}
+	}
+}
+
+// MaxHistoryBytes caps the run history: once the file reaches it, the next
+// append rotates it to <path>.1, replacing the previous rotation, so at most
+// about twice this much is kept.
+const MaxHistoryBytes = 1 << 20
+
+// DefaultHistoryPath returns bv/recipe-runs.jsonl under the user config
+// directory ($XDG_CONFIG_HOME, usually ~/.config), or "" when it is unknown
+func DefaultHistoryPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil || dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "bv", "recipe-runs.jsonl")
+}
+
+// AppendRun appends a record to the JSONL history at path, creating the
+// file and its directory as needed and rotating a full history first
+func AppendRun(path string, rec RunRecord) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating history directory: %w", err)
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() >= MaxHistoryBytes {
+		if err := os.Rename(path, path+".1"); err != nil {
+			return fmt.Errorf("rotating run history: %w", err)
+		}
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encoding run record: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening run history: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("writing run history: %w", err)
+	}
+	return f.Close()
+}
+
+// LoadRuns reads the history at path, and its rotation at <path>.1, newest
+// first. An empty name returns runs of every recipe; limit <= 0 returns all
+// matches. A missing file is an empty history, and lines that fail to parse
+// are skipped.
+func LoadRuns(path, name string, limit int) ([]RunRecord, error) {
+	var runs []RunRecord
+	for _, p := range []string{path + ".1", path} {
+		var err error
+		if runs, err = readRuns(p, name, runs); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, j := 0, len(runs)-1; i < j; i, j = i+1, j-1 {
+		runs[i], runs[j] = runs[j], runs[i]
+	}
+	if limit > 0 && len(runs) > limit {
+		runs = runs[:limit]
+	}
+	return runs, nil
+}
+
+// readRuns appends the runs of the named recipe in the history file at path,
+// oldest first, to runs
+func readRuns(path, name string, runs []RunRecord) ([]RunRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return runs, nil
+		}
+		return nil, fmt.Errorf("opening run history: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var rec RunRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil || rec.Recipe == "" {
+			continue
+		}
+		if name != "" && rec.Recipe != name {
+			continue
+		}
+		runs = append(runs, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading run history: %w", err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,102 @@
+package recipe_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
+)
+
+func TestLoadRunsNewestFirstFilteredAndLimited(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recipe-runs.jsonl")
+	start := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	for i, name := range []string{"stale", "triage", "stale", "stale"} {
+		rec := recipe.RunRecord{Recipe: name, StartedAt: start.Add(time.Duration(i) * time.Minute)}
+		rec.Finish(rec.StartedAt)
+		if err := recipe.AppendRun(path, rec); err != nil {
+			t.Fatalf("AppendRun: %v", err)
+		}
+	}
+
+	// A corrupt line must not hide the rest of the history
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("{not json\n")
+	f.Close()
+
+	runs, err := recipe.LoadRuns(path, "stale", 2)
+	if err != nil {
+		t.Fatalf("LoadRuns: %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("expected 2 runs, got %d", len(runs))
+	}
+	if !runs[0].StartedAt.Equal(start.Add(3*time.Minute)) || !runs[1].StartedAt.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("runs not newest first: %v, %v", runs[0].StartedAt, runs[1].StartedAt)
+	}
+
+	all, err := recipe.LoadRuns(path, "", 0)
+	if err != nil || len(all) != 4 {
+		t.Errorf("LoadRuns all = %d runs, err %v; want 4", len(all), err)
+	}
+}
+
+func TestLoadRunsMissingFile(t *testing.T) {
+	runs, err := recipe.LoadRuns(filepath.Join(t.TempDir(), "none.jsonl"), "", 0)
+	if err != nil || runs != nil {
+		t.Errorf("expected empty history, got %v, %v", runs, err)
+	}
+}
+
+func TestAppendRunRotatesFullHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recipe-runs.jsonl")
+	start := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	old := recipe.RunRecord{Recipe: "old", StartedAt: start}
+	if err := recipe.AppendRun(path, old); err != nil {
+		t.Fatalf("AppendRun: %v", err)
+	}
+	// Pad the history past the cap with lines LoadRuns skips
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pad := strings.Repeat("x", 1023) + "\n"
+	for written := 0; written < recipe.MaxHistoryBytes; written += len(pad) {
+		_, _ = f.WriteString(pad)
+	}
+	f.Close()
+
+	if err := recipe.AppendRun(path, recipe.RunRecord{Recipe: "new", StartedAt: start.Add(time.Minute)}); err != nil {
+		t.Fatalf("AppendRun: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Size() >= 1024 {
+		t.Fatalf("expected a fresh history after rotation, got %v (err %v)", info.Size(), err)
+	}
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Fatalf("expected the full history rotated to .1: %v", err)
+	}
+
+	// The rotated runs are still listed, after the newer ones
+	runs, err := recipe.LoadRuns(path, "", 0)
+	if err != nil || len(runs) != 2 || runs[0].Recipe != "new" || runs[1].Recipe != "old" {
+		t.Errorf("LoadRuns = %+v, err %v; want new then old", runs, err)
+	}
+}
+
+func TestDefaultHistoryPathUsesConfigDir(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("XDG_CONFIG_HOME only applies on Linux")
+	}
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	if got, want := recipe.DefaultHistoryPath(), filepath.Join(dir, "bv", "recipe-runs.jsonl"); got != want {
+		t.Errorf("DefaultHistoryPath = %q, want %q", got, want)
+	}
+}
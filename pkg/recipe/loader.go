@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -25,13 +27,23 @@ type RecipeSummary struct {
 	Source      string `json:"source"` // "builtin", "user", "project"
 }
 
+// RecipeLayer is one definition of a recipe in one source. Later layers
+// override earlier ones.
+type RecipeLayer struct {
+	Source string  // "builtin", "user", "project"
+	Path   string  // File the definition came from; empty for builtin
+	Recipe *Recipe // nil when the source disables the recipe with an explicit null
+}
+
 // Loader handles loading and merging recipes from multiple sources
 type Loader struct {
 	recipes    map[string]Recipe
-	sources    map[string]string // recipe name -> source
+	sources    map[string]string        // recipe name -> source
+	layers     map[string][]RecipeLayer // recipe name -> every definition, in load order
 	userPath   string
 	projectDir string
 	warnings   []string
+	shadows    []string // Project recipes that reshape a builtin
 }
 
 // LoaderOption configures the loader
@@ -56,6 +68,7 @@ func NewLoader(opts ...LoaderOption) *Loader {
 	l := &Loader{
 		recipes: make(map[string]Recipe),
 		sources: make(map[string]string),
+		layers:  make(map[string][]RecipeLayer),
 	}
 
 	for _, opt := range opts {
@@ -126,6 +139,7 @@ func (l *Loader) loadBuiltin() error {
 		recipe.Name = name
 		l.recipes[name] = *recipe
 		l.sources[name] = "builtin"
+		l.layers[name] = append(l.layers[name], RecipeLayer{Source: "builtin", Recipe: recipe})
 	}
 
 	return nil
@@ -144,6 +158,7 @@ func (l *Loader) loadFromFile(path, source string) error {
 	}
 
 	for name, recipe := range file.Recipes {
+		l.layers[name] = append(l.layers[name], RecipeLayer{Source: source, Path: path, Recipe: recipe})
 		if recipe == nil {
 			// Explicit null means "disable this recipe"
 			delete(l.recipes, name)
@@ -151,6 +166,13 @@ func (l *Loader) loadFromFile(path, source string) error {
 			continue
 		}
 		recipe.Name = name
+		if source == "project" {
+			if builtin := l.builtinLayer(name); builtin != nil {
+				if diff := substantiveDiff(builtin, recipe); len(diff) > 0 {
+					l.shadows = append(l.shadows, fmt.Sprintf("project recipe %q shadows the builtin and differs in %s (see --recipe-explain %s)", name, strings.Join(diff, ", "), name))
+				}
+			}
+		}
 		l.recipes[name] = *recipe
 		l.sources[name] = source
 	}
@@ -215,11 +237,92 @@ func (l *Loader) Warnings() []string {
 	return l.warnings
 }
 
+// Shadows describes each project recipe that shadows a builtin and differs
+// in what it selects or how it orders, sorted. Unlike Warnings these are
+// deliberate choices, so callers may report each one only once.
+func (l *Loader) Shadows() []string {
+	sort.Strings(l.shadows)
+	return l.shadows
+}
+
 // Source returns the source of a recipe ("builtin", "user", "project")
 func (l *Loader) Source(name string) string {
 	return l.sources[name]
 }
 
+// Layers returns every definition of a recipe in load order (builtin, user,
+// project); the last one decides what Get returns
+func (l *Loader) Layers(name string) []RecipeLayer {
+	return l.layers[name]
+}
+
+// Explain describes how a recipe was layered: each source that defines it,
+// which one won, and how the winner differs from the builtin. It returns
+// false when no source defines the recipe.
+func (l *Loader) Explain(name string) (string, bool) {
+	layers := l.layers[name]
+	if len(layers) == 0 {
+		return "", false
+	}
+
+	var b strings.Builder
+	last := layers[len(layers)-1]
+	if last.Recipe == nil {
+		fmt.Fprintf(&b, "Recipe %q is disabled by the %s config\n", name, last.Source)
+	} else {
+		fmt.Fprintf(&b, "Recipe %q comes from %s\n", name, last.Source)
+	}
+	for i, layer := range layers {
+		where := layer.Path
+		if layer.Source == "builtin" {
+			where = "embedded defaults"
+		}
+		state := "shadowed"
+		switch {
+		case layer.Recipe == nil:
+			state = "disables"
+		case i == len(layers)-1:
+			state = "active"
+		}
+		fmt.Fprintf(&b, "  %-8s %-9s %s\n", layer.Source, state, where)
+	}
+
+	if builtin := l.builtinLayer(name); builtin != nil && last.Recipe != nil && last.Source != "builtin" {
+		if diff := substantiveDiff(builtin, last.Recipe); len(diff) > 0 {
+			fmt.Fprintf(&b, "Differs from builtin in: %s\n", strings.Join(diff, ", "))
+		} else {
+			fmt.Fprintln(&b, "Selects and orders issues like the builtin")
+		}
+	}
+	return b.String(), true
+}
+
+// builtinLayer returns the embedded definition of a recipe, if any
+func (l *Loader) builtinLayer(name string) *Recipe {
+	for _, layer := range l.layers[name] {
+		if layer.Source == "builtin" {
+			return layer.Recipe
+		}
+	}
+	return nil
+}
+
+// substantiveDiff names the parts of two recipes that change which issues
+// are shown or their order; description and display tweaks don't count
+func substantiveDiff(a, b *Recipe) []string {
+	var diff []string
+	if !reflect.DeepEqual(a.Filters, b.Filters) {
+		diff = append(diff, "filters")
+	}
+	if !reflect.DeepEqual(a.Sort, b.Sort) {
+		diff = append(diff, "sort")
+	}
+	if !reflect.DeepEqual(a.Steps, b.Steps) {
+		diff = append(diff, "steps")
+	}
+	return diff
+}
+
 // LoadDefault creates a loader and loads with default settings
 func LoadDefault() (*Loader, error) {
 	loader := NewLoader()
@@ -3,6 +3,7 @@ package recipe_test
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
@@ -316,3 +317,66 @@ func TestLoaderList(t *testing.T) {
 		t.Error("Expected non-empty list")
 	}
 }
+
+func TestLoaderExplainShadowedBuiltin(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, ".bv")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	projectConfig := `
+recipes:
+  stale:
+    description: "Our idea of stale"
+    filters:
+      status: [open]
+      updated_before: "90d"
+  actionable:
+    description: "Same selection, different wording"
+    filters:
+      status: [open, in_progress]
+      actionable: true
+    sort:
+      field: priority
+      direction: asc
+`
+	if err := os.WriteFile(filepath.Join(projectDir, "recipes.yaml"), []byte(projectConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := recipe.NewLoader(
+		recipe.WithUserPath(""),
+		recipe.WithProjectDir(tmpDir),
+	)
+	if err := loader.Load(); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+
+	out, ok := loader.Explain("stale")
+	if !ok {
+		t.Fatal("Expected an explanation for stale")
+	}
+	for _, want := range []string{
+		`Recipe "stale" comes from project`,
+		"builtin  shadowed  embedded defaults",
+		"project  active    " + filepath.Join(projectDir, "recipes.yaml"),
+		"Differs from builtin in: filters",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Explain output missing %q:\n%s", want, out)
+		}
+	}
+
+	// Only the substantively different override is warned about
+	shadows := loader.Shadows()
+	if len(shadows) != 1 || !strings.Contains(shadows[0], `"stale" shadows the builtin`) {
+		t.Errorf("Expected one shadowing notice for stale, got %v", shadows)
+	}
+	if len(loader.Warnings()) != 0 {
+		t.Errorf("Shadowing is not a load warning, got %v", loader.Warnings())
+	}
+
+	if _, ok := loader.Explain("no-such-recipe"); ok {
+		t.Error("Expected no explanation for an undefined recipe")
+	}
+}
@@ -1,7 +1,10 @@
 package recipe
 
 import (
+	"fmt"
+	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -16,6 +19,92 @@ type Recipe struct {
 	View        ViewConfig   `yaml:"view,omitempty" json:"view,omitempty"`
 	Export      ExportConfig `yaml:"export,omitempty" json:"export,omitempty"`
 	Metrics     []string     `yaml:"metrics,omitempty" json:"metrics,omitempty"` // Which metrics to show
+	Steps       []Step       `yaml:"steps,omitempty" json:"steps,omitempty"`     // Extra passes after filters and sort
+}
+
+// Step is an extra pass run, in order, after the recipe's own filters and
+// sort. A step either narrows/re-sorts the issues (filters, sort) or, with
+// analyze, runs a robot analysis and binds fields of its JSON to variables
+// that later steps' filters reference as ${name}.
+type Step struct {
+	Name    string            `yaml:"name,omitempty" json:"name,omitempty"`
+	When    string            `yaml:"when,omitempty" json:"when,omitempty"` // Condition over graph state, e.g. "blocked_count > 0"; the step is skipped when false
+	Filters FilterConfig      `yaml:"filters,omitempty" json:"filters,omitempty"`
+	Sort    SortConfig        `yaml:"sort,omitempty" json:"sort,omitempty"`
+	Analyze string            `yaml:"analyze,omitempty" json:"analyze,omitempty"` // triage, insights, or plan
+	Bind    map[string]string `yaml:"bind,omitempty" json:"bind,omitempty"`       // Variable -> JSON path, e.g. top_bead: quick_ref.top_picks.0.id
+}
+
+// BindNames returns the step's bound variable names, sorted
+func (s Step) BindNames() []string {
+	names := make([]string, 0, len(s.Bind))
+	for name := range s.Bind {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StepName returns the step's name, or "step N" (1-based) when unnamed
+func (r *Recipe) StepName(i int) string {
+	if r.Steps[i].Name != "" {
+		return r.Steps[i].Name
+	}
+	return "step " + strconv.Itoa(i+1)
+}
+
+// Validate checks the parts of a recipe that can be wrong before it runs:
+// every step condition must parse, analyze steps must name a known analysis
+// and only bind variables, and every ${name} must be bound by an earlier step
+func (r *Recipe) Validate() error {
+	if refs := r.Filters.VarRefs(); len(refs) > 0 {
+		return fmt.Errorf("recipe %q filters reference ${%s}; only steps can use variables", r.Name, refs[0])
+	}
+	bound := make(map[string]bool)
+	for i, step := range r.Steps {
+		name := r.StepName(i)
+		if step.When != "" {
+			if _, err := ParseCondition(step.When); err != nil {
+				return fmt.Errorf("recipe %q %s: %w", r.Name, name, err)
+			}
+		}
+		if step.Analyze == "" {
+			if len(step.Bind) > 0 {
+				return fmt.Errorf("recipe %q %s: bind needs an analyze step", r.Name, name)
+			}
+			for _, ref := range step.Filters.VarRefs() {
+				if !bound[ref] {
+					return fmt.Errorf("recipe %q %s: ${%s} is not bound by an earlier step", r.Name, name, ref)
+				}
+			}
+			continue
+		}
+
+		known := false
+		for _, a := range AnalysisNames {
+			known = known || step.Analyze == a
+		}
+		if !known {
+			return fmt.Errorf("recipe %q %s: unknown analysis %q (valid: %s)", r.Name, name, step.Analyze, strings.Join(AnalysisNames, ", "))
+		}
+		if !reflect.DeepEqual(step.Filters, FilterConfig{}) || !reflect.DeepEqual(step.Sort, SortConfig{}) {
+			return fmt.Errorf("recipe %q %s: analyze steps only bind variables; put filters and sort in a later step", r.Name, name)
+		}
+		if len(step.Bind) == 0 {
+			return fmt.Errorf("recipe %q %s: analyze step binds nothing", r.Name, name)
+		}
+		for _, v := range step.BindNames() {
+			path := step.Bind[v]
+			if !varNamePattern.MatchString(v) {
+				return fmt.Errorf("recipe %q %s: invalid variable name %q", r.Name, name, v)
+			}
+			if strings.TrimSpace(path) == "" {
+				return fmt.Errorf("recipe %q %s: variable %q has an empty path", r.Name, name, v)
+			}
+			bound[v] = true
+		}
+	}
+	return nil
 }
 
 // FilterConfig defines which issues to include
@@ -32,6 +121,7 @@ type FilterConfig struct {
 	Actionable    *bool    `yaml:"actionable,omitempty" json:"actionable,omitempty"`         // true = no open blockers
 	TitleContains string   `yaml:"title_contains,omitempty" json:"title_contains,omitempty"` // Substring match
 	IDPrefix      string   `yaml:"id_prefix,omitempty" json:"id_prefix,omitempty"`           // e.g., "bv-" for project filtering
+	IDs           []string `yaml:"ids,omitempty" json:"ids,omitempty"`                       // Exact bead IDs
 }
 
 // SortConfig defines how to order issues
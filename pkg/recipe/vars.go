@@ -0,0 +1,118 @@
+package recipe
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// AnalysisNames are the robot analyses an `analyze:` step can run
+var AnalysisNames = []string{"insights", "plan", "triage"}
+
+// varRefPattern matches ${name} references in step filter values
+var varRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// varNamePattern is what a bind key must look like to be referenced as ${name}
+var varNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// LookupPath walks decoded JSON (maps, slices, scalars) along a dotted path
+// such as "quick_ref.top_picks.0.id"; "top_picks[0].id" is accepted too.
+// It returns the scalar found there as a string, or false when the path is
+// missing, runs past the end of a list, or ends on an object, list, or null.
+func LookupPath(doc any, path string) (string, bool) {
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+	cur := doc
+	for _, part := range strings.Split(path, ".") {
+		if part == "" {
+			continue
+		}
+		switch node := cur.(type) {
+		case map[string]any:
+			v, ok := node[part]
+			if !ok {
+				return "", false
+			}
+			cur = v
+		case []any:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(node) {
+				return "", false
+			}
+			cur = node[i]
+		default:
+			return "", false
+		}
+	}
+	switch v := cur.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
+
+// VarRefs returns the variable names referenced as ${name} in the filters,
+// in order of first use
+func (f FilterConfig) VarRefs() []string {
+	var refs []string
+	seen := make(map[string]bool)
+	for _, s := range f.stringValues() {
+		for _, m := range varRefPattern.FindAllStringSubmatch(s, -1) {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				refs = append(refs, m[1])
+			}
+		}
+	}
+	return refs
+}
+
+// Expand returns a copy of the filters with ${name} references replaced by
+// their values. Callers should check VarRefs against vars first; unknown
+// references are left as written.
+func (f FilterConfig) Expand(vars map[string]string) FilterConfig {
+	expand := func(s string) string {
+		return varRefPattern.ReplaceAllStringFunc(s, func(ref string) string {
+			if v, ok := vars[ref[2:len(ref)-1]]; ok {
+				return v
+			}
+			return ref
+		})
+	}
+	expandAll := func(ss []string) []string {
+		if ss == nil {
+			return nil
+		}
+		out := make([]string, len(ss))
+		for i, s := range ss {
+			out[i] = expand(s)
+		}
+		return out
+	}
+
+	out := f
+	out.Status = expandAll(f.Status)
+	out.Tags = expandAll(f.Tags)
+	out.ExcludeTags = expandAll(f.ExcludeTags)
+	out.CreatedAfter = expand(f.CreatedAfter)
+	out.CreatedBefore = expand(f.CreatedBefore)
+	out.UpdatedAfter = expand(f.UpdatedAfter)
+	out.UpdatedBefore = expand(f.UpdatedBefore)
+	out.TitleContains = expand(f.TitleContains)
+	out.IDPrefix = expand(f.IDPrefix)
+	out.IDs = expandAll(f.IDs)
+	return out
+}
+
+// stringValues lists every string-valued filter, the ones that may hold
+// ${name} references
+func (f FilterConfig) stringValues() []string {
+	values := []string{f.CreatedAfter, f.CreatedBefore, f.UpdatedAfter, f.UpdatedBefore, f.TitleContains, f.IDPrefix}
+	values = append(values, f.Status...)
+	values = append(values, f.Tags...)
+	values = append(values, f.IDs...)
+	return append(values, f.ExcludeTags...)
+}
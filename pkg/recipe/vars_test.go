@@ -0,0 +1,75 @@
+package recipe_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
+)
+
+func TestLookupPath(t *testing.T) {
+	var doc any
+	if err := json.Unmarshal([]byte(`{"quick_ref":{"top_picks":[{"id":"bv-1","score":0.75,"ready":true}],"empty":[]},"meta":null}`), &doc); err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]string{
+		"quick_ref.top_picks.0.id":    "bv-1",
+		"quick_ref.top_picks[0].id":   "bv-1",
+		"quick_ref.top_picks.0.score": "0.75",
+		"quick_ref.top_picks.0.ready": "true",
+	} {
+		if got, ok := recipe.LookupPath(doc, path); !ok || got != want {
+			t.Errorf("LookupPath(%q) = %q, %v; want %q", path, got, ok, want)
+		}
+	}
+	for _, path := range []string{"quick_ref.empty.0.id", "quick_ref.top_picks.1.id", "quick_ref.nope", "quick_ref.top_picks", "meta", "quick_ref.top_picks.x"} {
+		if got, ok := recipe.LookupPath(doc, path); ok {
+			t.Errorf("LookupPath(%q) = %q, want no value", path, got)
+		}
+	}
+}
+
+func TestFilterExpand(t *testing.T) {
+	f := recipe.FilterConfig{IDPrefix: "${top}", Tags: []string{"${area}", "urgent"}, TitleContains: "${unknown}"}
+	if refs := f.VarRefs(); strings.Join(refs, ",") != "unknown,top,area" {
+		t.Errorf("VarRefs = %v", refs)
+	}
+	got := f.Expand(map[string]string{"top": "bv-1", "area": "api"})
+	if got.IDPrefix != "bv-1" || got.Tags[0] != "api" || got.Tags[1] != "urgent" || got.TitleContains != "${unknown}" {
+		t.Errorf("Expand = %+v", got)
+	}
+	if f.Tags[0] != "${area}" {
+		t.Error("Expand modified the original filters")
+	}
+}
+
+func TestRecipeValidateAnalyzeSteps(t *testing.T) {
+	pick := recipe.Step{Analyze: "triage", Bind: map[string]string{"top_bead": "quick_ref.top_picks.0.id"}}
+	use := recipe.Step{Filters: recipe.FilterConfig{IDPrefix: "${top_bead}"}}
+
+	ok := &recipe.Recipe{Name: "r", Steps: []recipe.Step{pick, use}}
+	if err := ok.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	for wantErr, steps := range map[string][]recipe.Step{
+		"not bound by an earlier step": {use, pick},
+		"unknown analysis":             {{Analyze: "forecast", Bind: map[string]string{"x": "a"}}},
+		"bind needs an analyze step":   {{Bind: map[string]string{"x": "a"}}},
+		"only bind variables":          {{Analyze: "plan", Bind: map[string]string{"x": "a"}, Sort: recipe.SortConfig{Field: "id"}}},
+		"binds nothing":                {{Analyze: "plan"}},
+		"invalid variable name":        {{Analyze: "plan", Bind: map[string]string{"top-bead": "a"}}},
+		"empty path":                   {{Analyze: "plan", Bind: map[string]string{"x": " "}}},
+	} {
+		r := &recipe.Recipe{Name: "r", Steps: steps}
+		if err := r.Validate(); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("Validate() = %v, want it to mention %q", err, wantErr)
+		}
+	}
+
+	base := &recipe.Recipe{Name: "r", Filters: recipe.FilterConfig{IDPrefix: "${top_bead}"}, Steps: []recipe.Step{pick}}
+	if err := base.Validate(); err == nil || !strings.Contains(err.Error(), "only steps") {
+		t.Errorf("Validate() = %v, want variables rejected in recipe filters", err)
+	}
+}
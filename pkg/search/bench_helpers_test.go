@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"testing"
 	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 )
 
 type benchmarkMetricsLoader struct {
@@ -32,6 +34,37 @@ func buildBenchmarkMetricsCache(tb testing.TB, size int) MetricsCache {
 	return cache
 }
 
+// buildBenchmarkAnalyzerMetricsCache builds a cache over a real analyzer loader,
+// whose freshness check hashes the full issue set.
+func buildBenchmarkAnalyzerMetricsCache(tb testing.TB, size int) MetricsCache {
+	tb.Helper()
+	base := time.Now().Add(-90 * 24 * time.Hour)
+	issues := make([]model.Issue, size)
+	for i := 0; i < size; i++ {
+		issues[i] = model.Issue{
+			ID:        fmt.Sprintf("issue-%d", i),
+			Title:     fmt.Sprintf("Benchmark issue %d", i),
+			Status:    model.StatusOpen,
+			IssueType: model.TypeTask,
+			Priority:  i % 5,
+			CreatedAt: base,
+			UpdatedAt: base.Add(time.Duration(i%90) * 24 * time.Hour),
+		}
+		if i > 0 {
+			issues[i].Dependencies = []*model.Dependency{{
+				IssueID:     issues[i].ID,
+				DependsOnID: fmt.Sprintf("issue-%d", i/2),
+				Type:        model.DepBlocks,
+			}}
+		}
+	}
+	cache := NewMetricsCache(NewAnalyzerMetricsLoader(issues))
+	if err := cache.Refresh(); err != nil {
+		tb.Fatalf("Refresh metrics cache: %v", err)
+	}
+	return cache
+}
+
 func buildBenchmarkMetrics(size int) map[string]IssueMetrics {
 	metrics := make(map[string]IssueMetrics, size)
 	statuses := []string{"open", "in_progress", "blocked", "closed"}
@@ -3,28 +3,42 @@ package search
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // EmbeddingConfigFromEnv reads semantic embedding configuration from environment variables.
 //
 // Supported variables:
-//   - BV_SEMANTIC_EMBEDDER: embedding provider (default: "hash")
+//   - BV_SEMANTIC_EMBEDDER: embedding provider (default: "hash"); BV_EMBEDDER
+//     is accepted as an alias when it is unset
 //   - BV_SEMANTIC_MODEL: model identifier (provider-specific, optional)
 //   - BV_SEMANTIC_DIM: embedding dimension (default: DefaultEmbeddingDim)
+//   - BV_SEMANTIC_MAX_AGE: re-embed index entries older than this Go duration,
+//     e.g. "168h" (default: DefaultIndexMaxAge; "0" disables)
 func EmbeddingConfigFromEnv() EmbeddingConfig {
 	provider := strings.ToLower(strings.TrimSpace(os.Getenv(EnvSemanticEmbedder)))
+	if provider == "" {
+		provider = strings.ToLower(strings.TrimSpace(os.Getenv(EnvEmbedder)))
+	}
 	cfg := EmbeddingConfig{
 		Provider: Provider(provider),
 		Model:    strings.TrimSpace(os.Getenv(EnvSemanticModel)),
+		MaxAge:   DefaultIndexMaxAge,
 	}
 	if dimStr := os.Getenv(EnvSemanticDim); dimStr != "" {
 		if dim, err := strconv.Atoi(dimStr); err == nil {
 			cfg.Dim = dim
 		}
 	}
+	if ageStr := strings.TrimSpace(os.Getenv(EnvSemanticMaxAge)); ageStr != "" {
+		if age, err := time.ParseDuration(ageStr); err == nil && age >= 0 {
+			cfg.MaxAge = age
+		}
+	}
 	if cfg.Provider == "" {
 		cfg.Provider = ProviderHash
 	}
@@ -32,20 +46,41 @@ func EmbeddingConfigFromEnv() EmbeddingConfig {
 }
 
 // NewEmbedderFromConfig constructs an Embedder for the given configuration.
+// Network-backed providers are wrapped by wrapNetworkEmbedder.
 func NewEmbedderFromConfig(cfg EmbeddingConfig) (Embedder, error) {
 	cfg = cfg.Normalized()
 	switch cfg.Provider {
 	case "", ProviderHash:
 		return NewHashEmbedder(cfg.Dim), nil
+	case ProviderPythonSentenceTransformers, ProviderOpenAI:
+		inner, err := newNetworkEmbedder(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return wrapNetworkEmbedder(inner), nil
+	default:
+		return nil, fmt.Errorf("unknown semantic embedder %q; expected %q", cfg.Provider, ProviderHash)
+	}
+}
+
+// newNetworkEmbedder builds the client for a hosted or subprocess provider.
+// It is a variable so tests can substitute a fake client.
+var newNetworkEmbedder = func(cfg EmbeddingConfig) (Embedder, error) {
+	switch cfg.Provider {
 	case ProviderPythonSentenceTransformers:
 		return nil, fmt.Errorf("semantic embedder %q not implemented (mvp placeholder); set %s=%q for deterministic fallback", cfg.Provider, EnvSemanticEmbedder, ProviderHash)
-	case ProviderOpenAI:
-		return nil, fmt.Errorf("semantic embedder %q not implemented (placeholder); set %s=%q for deterministic fallback", cfg.Provider, EnvSemanticEmbedder, ProviderHash)
 	default:
-		return nil, fmt.Errorf("unknown semantic embedder %q; expected %q", cfg.Provider, ProviderHash)
+		return nil, fmt.Errorf("semantic embedder %q not implemented (placeholder); set %s=%q for deterministic fallback", cfg.Provider, EnvSemanticEmbedder, ProviderHash)
 	}
 }
 
+// wrapNetworkEmbedder holds a network client to the BV_SEMANTIC_RPM/TPM
+// limits (see RateLimitedEmbedder) and adds retries with backoff and the
+// hash fallback breaker (see RetryingEmbedder) around it.
+func wrapNetworkEmbedder(inner Embedder) Embedder {
+	return NewRetryingEmbedder(NewRateLimitedEmbedder(inner, RateLimitFromEnv()), nil, RetryPolicy{})
+}
+
 // SearchMode defines the search ranking mode.
 type SearchMode string
 
@@ -63,16 +98,18 @@ const (
 // SearchConfig captures hybrid search configuration from env or flags.
 type SearchConfig struct {
 	Mode       SearchMode
+	Fusion     FusionMode
 	Preset     PresetName
 	Weights    Weights
 	HasWeights bool
 }
 
 // SearchConfigFromEnv reads hybrid search configuration from environment variables.
-// Defaults: mode=text, preset=default.
+// Defaults: mode=text, fusion=score, preset=default.
 func SearchConfigFromEnv() (SearchConfig, error) {
 	cfg := SearchConfig{
 		Mode:   SearchModeText,
+		Fusion: FusionScore,
 		Preset: PresetDefault,
 	}
 
@@ -85,10 +122,18 @@ func SearchConfigFromEnv() (SearchConfig, error) {
 		}
 	}
 
+	if raw := os.Getenv(EnvSearchFusion); raw != "" {
+		fusion, err := ParseFusionMode(raw)
+		if err != nil {
+			return SearchConfig{}, fmt.Errorf("invalid %s: %w", EnvSearchFusion, err)
+		}
+		cfg.Fusion = fusion
+	}
+
 	if preset := strings.TrimSpace(os.Getenv(EnvSearchPreset)); preset != "" {
-		name := PresetName(strings.ToLower(preset))
-		if _, err := GetPreset(name); err != nil {
-			return SearchConfig{}, err
+		name, err := ResolvePreset(preset)
+		if err != nil {
+			return SearchConfig{}, fmt.Errorf("invalid %s: %w", EnvSearchPreset, err)
 		}
 		cfg.Preset = name
 	}
@@ -120,6 +165,9 @@ func ParseWeightsJSON(raw string) (Weights, error) {
 	}
 	for key := range payload {
 		if !isWeightKey(key) {
+			if closest := closestName(key, weightKeys); closest != "" {
+				return Weights{}, fmt.Errorf("weights JSON has unknown key %q (did you mean %q?)", key, closest)
+			}
 			return Weights{}, fmt.Errorf("weights JSON has unknown key %q", key)
 		}
 	}
@@ -140,6 +188,78 @@ func ParseWeightsJSON(raw string) (Weights, error) {
 	return weights, nil
 }
 
+// ParseWeightsSpec parses a comma-separated key=value weight list such as
+// "text=0.5,pagerank=0.2,status=0.1". Unspecified components default to 0.
+// Each component must be in [0, 1]; the result is normalized to sum to 1.0 and
+// validated. Range failures are reported as *WeightValidationError.
+func ParseWeightsSpec(raw string) (Weights, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return Weights{}, fmt.Errorf("weights spec is empty")
+	}
+
+	payload := make(map[string]float64)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Weights{}, fmt.Errorf("weights spec entry %q must be key=value", part)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !isWeightKey(key) {
+			if closest := closestName(key, weightKeys); closest != "" {
+				return Weights{}, fmt.Errorf("weights spec has unknown key %q (did you mean %q? valid: %s)", key, closest, strings.Join(weightKeys, ", "))
+			}
+			return Weights{}, fmt.Errorf("weights spec has unknown key %q (valid: %s)", key, strings.Join(weightKeys, ", "))
+		}
+		if _, dup := payload[key]; dup {
+			return Weights{}, fmt.Errorf("weights spec has duplicate key %q", key)
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return Weights{}, fmt.Errorf("weights spec value for %q is not a number: %q", key, value)
+		}
+		// ParseFloat accepts "NaN" and "Inf", which would slip past the range
+		// checks below (NaN compares false with everything).
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return Weights{}, &WeightValidationError{Field: key, Value: v, Reason: WeightReasonNotFinite}
+		}
+		payload[key] = v
+	}
+
+	weights := Weights{
+		TextRelevance: payload["text"],
+		PageRank:      payload["pagerank"],
+		Status:        payload["status"],
+		Impact:        payload["impact"],
+		Priority:      payload["priority"],
+		Recency:       payload["recency"],
+	}
+	for _, c := range weights.components() {
+		if c.value < 0 {
+			return Weights{}, &WeightValidationError{Field: c.key, Value: c.value, Reason: WeightReasonNegative}
+		}
+		if c.value > 1 {
+			return Weights{}, &WeightValidationError{Field: c.key, Value: c.value, Reason: WeightReasonOutOfRange}
+		}
+	}
+	if weights.sum() == 0 {
+		return Weights{}, &WeightValidationError{Field: WeightSumField, Value: 0, Reason: WeightReasonAllZero}
+	}
+
+	weights = weights.Normalize()
+	if err := weights.Validate(); err != nil {
+		return Weights{}, err
+	}
+	return weights, nil
+}
+
+// weightKeys lists the weight names accepted by --weights and weights JSON.
+var weightKeys = []string{"text", "pagerank", "status", "impact", "priority", "recency"}
+
 func isWeightKey(key string) bool {
 	switch key {
 	case "text", "pagerank", "status", "impact", "priority", "recency":
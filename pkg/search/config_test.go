@@ -1,6 +1,8 @@
 package search
 
 import (
+	"errors"
+	"math"
 	"os"
 	"strings"
 	"testing"
@@ -175,6 +177,19 @@ func TestEmbeddingConfigFromEnv(t *testing.T) {
 	}
 }
 
+func TestEmbeddingConfigFromEnv_EmbedderAlias(t *testing.T) {
+	t.Setenv(EnvSemanticEmbedder, "")
+	t.Setenv(EnvEmbedder, "openai")
+	if cfg := EmbeddingConfigFromEnv(); cfg.Provider != ProviderOpenAI {
+		t.Errorf("Provider = %q, want %q from %s", cfg.Provider, ProviderOpenAI, EnvEmbedder)
+	}
+
+	t.Setenv(EnvSemanticEmbedder, "hash")
+	if cfg := EmbeddingConfigFromEnv(); cfg.Provider != ProviderHash {
+		t.Errorf("Provider = %q, want %s to take precedence", cfg.Provider, EnvSemanticEmbedder)
+	}
+}
+
 // =============================================================================
 // SearchConfigFromEnv Tests
 // =============================================================================
@@ -276,6 +291,63 @@ func TestParseWeightsJSON(t *testing.T) {
 	}
 }
 
+func TestParseWeightsSpec(t *testing.T) {
+	weights, err := ParseWeightsSpec("text=0.5,pagerank=0.2,status=0.1,impact=0.1,priority=0.05,recency=0.05")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(weights.TextRelevance-0.5) > 1e-9 || math.Abs(weights.Recency-0.05) > 1e-9 {
+		t.Fatalf("unexpected weights: %+v", weights)
+	}
+
+	// Unspecified components default to 0 and the rest are normalized.
+	weights, err = ParseWeightsSpec(" text = 0.6 , pagerank=0.2 ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if weights.Status != 0 || weights.Impact != 0 || weights.Priority != 0 || weights.Recency != 0 {
+		t.Fatalf("expected unspecified components to be 0, got %+v", weights)
+	}
+	if math.Abs(weights.TextRelevance-0.75) > 1e-9 || math.Abs(weights.PageRank-0.25) > 1e-9 {
+		t.Fatalf("expected normalized text=0.75 pagerank=0.25, got %+v", weights)
+	}
+
+	for _, bad := range []string{"", "text", "text=abc", "bogus=0.5", "text=0.5,text=0.5", "text=0,pagerank=0"} {
+		if _, err := ParseWeightsSpec(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+
+	if _, err := ParseWeightsSpec("text=0.5,pagernak=0.5"); err == nil || !strings.Contains(err.Error(), `did you mean "pagerank"?`) {
+		t.Fatalf("expected typo'd key to suggest pagerank, got %v", err)
+	}
+}
+
+func TestParseWeightsSpec_ReportsComponent(t *testing.T) {
+	tests := []struct {
+		spec   string
+		field  string
+		reason WeightValidationReason
+	}{
+		{"text=0.5,pagerank=-0.2", "pagerank", WeightReasonNegative},
+		{"text=0.5,impact=1.5", "impact", WeightReasonOutOfRange},
+		{"text=0.5,impact=NaN", "impact", WeightReasonNotFinite},
+		{"text=0.5,impact=Inf", "impact", WeightReasonNotFinite},
+		{"text=0.5,impact=-Inf", "impact", WeightReasonNotFinite},
+		{"text=0,pagerank=0", WeightSumField, WeightReasonAllZero},
+	}
+	for _, tt := range tests {
+		_, err := ParseWeightsSpec(tt.spec)
+		var wErr *WeightValidationError
+		if !errors.As(err, &wErr) {
+			t.Fatalf("%q: expected *WeightValidationError, got %v", tt.spec, err)
+		}
+		if wErr.Field != tt.field || wErr.Reason != tt.reason {
+			t.Fatalf("%q: expected %s/%s, got %+v", tt.spec, tt.field, tt.reason, wErr)
+		}
+	}
+}
+
 func saveSearchEnv() func() {
 	mode := os.Getenv(EnvSearchMode)
 	preset := os.Getenv(EnvSearchPreset)
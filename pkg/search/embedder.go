@@ -1,6 +1,10 @@
 package search
 
-import "context"
+import (
+	"context"
+	"strconv"
+	"time"
+)
 
 // Provider identifies an embedding backend.
 type Provider string
@@ -20,10 +24,19 @@ const (
 
 const DefaultEmbeddingDim = 384
 
+// DefaultIndexMaxAge is how long an embedded vector is trusted before
+// SyncVectorIndexWithOptions re-embeds it, when read from the environment.
+const DefaultIndexMaxAge = 30 * 24 * time.Hour
+
 const (
 	EnvSemanticEmbedder = "BV_SEMANTIC_EMBEDDER"
 	EnvSemanticModel    = "BV_SEMANTIC_MODEL"
 	EnvSemanticDim      = "BV_SEMANTIC_DIM"
+	EnvSemanticMaxAge   = "BV_SEMANTIC_MAX_AGE"
+
+	// EnvEmbedder is a shorter alias for EnvSemanticEmbedder, used when the
+	// latter is unset (e.g. BV_EMBEDDER=hash in CI).
+	EnvEmbedder = "BV_EMBEDDER"
 )
 
 // EmbeddingConfig captures embedder selection/configuration.
@@ -32,6 +45,24 @@ type EmbeddingConfig struct {
 	Provider Provider
 	Model    string
 	Dim      int
+	// MaxAge re-embeds index entries older than this; 0 means no limit.
+	MaxAge time.Duration
+}
+
+// ModelID identifies the embeddings this configuration produces, e.g. "hash@384"
+// or "openai:text-embedding-3-small@1536". Indexes record it so a change of
+// provider or model triggers re-embedding.
+func (c EmbeddingConfig) ModelID() string {
+	c = c.Normalized()
+	provider := c.Provider
+	if provider == "" {
+		provider = ProviderHash
+	}
+	id := string(provider)
+	if c.Model != "" {
+		id += ":" + c.Model
+	}
+	return id + "@" + strconv.Itoa(c.Dim)
 }
 
 func (c EmbeddingConfig) Normalized() EmbeddingConfig {
@@ -0,0 +1,141 @@
+package search
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// FusionMode selects how the lexical and semantic rankings are combined.
+type FusionMode string
+
+const (
+	// FusionScore ranks by semantic similarity, nudged by the short-query
+	// literal-match boost (the original behaviour).
+	FusionScore FusionMode = "score"
+	// FusionRRF combines the lexical (BM25) and semantic rankings by
+	// reciprocal rank, ignoring their raw score scales.
+	FusionRRF FusionMode = "rrf"
+)
+
+const EnvSearchFusion = "BV_SEARCH_FUSION"
+
+// DefaultRRFK is the rank offset k in 1/(k+rank); 60 is the customary value
+// and damps the influence of the very top ranks.
+const DefaultRRFK = 60
+
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// ParseFusionMode validates a fusion mode name ("" means FusionScore).
+func ParseFusionMode(raw string) (FusionMode, error) {
+	switch mode := FusionMode(strings.ToLower(strings.TrimSpace(raw))); mode {
+	case "":
+		return FusionScore, nil
+	case FusionScore, FusionRRF:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid fusion mode %q (expected score|rrf)", raw)
+	}
+}
+
+// LexicalSearch ranks docs against query with BM25 over lowercase words.
+// Documents that share no word with the query are omitted. Ties are broken by
+// issue ID so the ranking is deterministic.
+func LexicalSearch(query string, docs map[string]string) []SearchResult {
+	terms := queryWords(query)
+	if len(terms) == 0 || len(docs) == 0 {
+		return nil
+	}
+
+	type docStats struct {
+		freq   map[string]int
+		length int
+	}
+	stats := make(map[string]docStats, len(docs))
+	docFreq := make(map[string]int, len(terms))
+	totalLen := 0
+	for id, doc := range docs {
+		words := queryWords(doc)
+		freq := make(map[string]int)
+		for _, w := range words {
+			freq[w]++
+		}
+		stats[id] = docStats{freq: freq, length: len(words)}
+		totalLen += len(words)
+	}
+	unique := make(map[string]struct{}, len(terms))
+	for _, term := range terms {
+		unique[term] = struct{}{}
+	}
+	for term := range unique {
+		for _, s := range stats {
+			if s.freq[term] > 0 {
+				docFreq[term]++
+			}
+		}
+	}
+
+	n := float64(len(docs))
+	avgLen := float64(totalLen) / n
+	if avgLen == 0 {
+		avgLen = 1
+	}
+	results := make([]SearchResult, 0)
+	for id, s := range stats {
+		score := 0.0
+		for term := range unique {
+			tf := float64(s.freq[term])
+			if tf == 0 {
+				continue
+			}
+			df := float64(docFreq[term])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*float64(s.length)/avgLen))
+		}
+		if score > 0 {
+			results = append(results, SearchResult{IssueID: id, Score: score})
+		}
+	}
+	sortResults(results)
+	return results
+}
+
+// FuseRRF combines rankings by reciprocal rank fusion: each ID scores the sum of
+// 1/(k+rank) over the rankings it appears in (rank is 1-based). Scores are
+// scaled so an ID ranked first in every list scores 1, keeping them comparable
+// to similarity scores for hybrid re-ranking. k <= 0 uses DefaultRRFK.
+func FuseRRF(k int, rankings ...[]SearchResult) []SearchResult {
+	if k <= 0 {
+		k = DefaultRRFK
+	}
+	fused := make(map[string]float64)
+	for _, ranking := range rankings {
+		for i, r := range ranking {
+			fused[r.IssueID] += 1 / float64(k+i+1)
+		}
+	}
+	if len(fused) == 0 {
+		return nil
+	}
+
+	best := float64(len(rankings)) / float64(k+1)
+	results := make([]SearchResult, 0, len(fused))
+	for id, score := range fused {
+		results = append(results, SearchResult{IssueID: id, Score: score / best})
+	}
+	sortResults(results)
+	return results
+}
+
+func sortResults(results []SearchResult) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score == results[j].Score {
+			return results[i].IssueID < results[j].IssueID
+		}
+		return results[i].Score > results[j].Score
+	})
+}
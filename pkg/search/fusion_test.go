@@ -0,0 +1,61 @@
+package search
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFuseRRF_SurfacesConsensusCandidate(t *testing.T) {
+	// M is only third in each list, but it is the one candidate both agree on.
+	lexical := []SearchResult{{IssueID: "A", Score: 9.1}, {IssueID: "C", Score: 7.5}, {IssueID: "M", Score: 3.2}, {IssueID: "D", Score: 1.0}}
+	semantic := []SearchResult{{IssueID: "B", Score: 0.91}, {IssueID: "E", Score: 0.88}, {IssueID: "M", Score: 0.87}, {IssueID: "F", Score: 0.5}}
+
+	fused := FuseRRF(0, lexical, semantic)
+	if len(fused) != 7 {
+		t.Fatalf("expected 7 fused results, got %d", len(fused))
+	}
+	if fused[0].IssueID != "M" {
+		t.Fatalf("expected M to win by fused rank, got %+v", fused)
+	}
+	want := (2.0 / 63) / (2.0 / 61)
+	if math.Abs(fused[0].Score-want) > 1e-9 {
+		t.Fatalf("M score = %v, want %v", fused[0].Score, want)
+	}
+	// Top-of-one-list candidates tie; ties break by ID.
+	if fused[1].IssueID != "A" || fused[2].IssueID != "B" {
+		t.Fatalf("unexpected tie order: %+v", fused[:3])
+	}
+}
+
+func TestLexicalSearch_RanksByBM25(t *testing.T) {
+	docs := map[string]string{
+		"A": "token refresh\ntoken refresh on expiry",
+		"B": "login page\nrefresh button styling",
+		"C": "update docs",
+	}
+	results := LexicalSearch("token refresh", docs)
+	if len(results) != 2 || results[0].IssueID != "A" || results[1].IssueID != "B" {
+		t.Fatalf("unexpected lexical ranking: %+v", results)
+	}
+	if LexicalSearch("   ", docs) != nil {
+		t.Fatal("expected no results for an empty query")
+	}
+}
+
+func TestParseFusionMode(t *testing.T) {
+	if m, err := ParseFusionMode(""); err != nil || m != FusionScore {
+		t.Fatalf("ParseFusionMode(\"\") = %q, %v", m, err)
+	}
+	if m, err := ParseFusionMode(" RRF "); err != nil || m != FusionRRF {
+		t.Fatalf("ParseFusionMode(RRF) = %q, %v", m, err)
+	}
+	if _, err := ParseFusionMode("blend"); err == nil {
+		t.Fatal("expected an error for an unknown fusion mode")
+	}
+
+	t.Setenv(EnvSearchFusion, "rrf")
+	cfg, err := SearchConfigFromEnv()
+	if err != nil || cfg.Fusion != FusionRRF {
+		t.Fatalf("SearchConfigFromEnv fusion = %q, %v", cfg.Fusion, err)
+	}
+}
@@ -69,3 +69,35 @@ func cosine(a, b []float32) float64 {
 	}
 	return dot / (math.Sqrt(na) * math.Sqrt(nb))
 }
+
+func TestHashEmbedder_StableAcrossConstructions(t *testing.T) {
+	texts := []string{"Memory leak in parser", "Ünïcode titel mit Umlauten", ""}
+	a, err := NewHashEmbedder(128).Embed(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	b, err := NewHashEmbedder(128).Embed(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	for i := range texts {
+		for j := range a[i] {
+			if a[i][j] != b[i][j] {
+				t.Fatalf("text %d differs at %d across constructions: %v vs %v", i, j, a[i][j], b[i][j])
+			}
+		}
+	}
+
+	// Pinned output guards against accidental changes to tokenization or
+	// hashing, which would silently invalidate every persisted hash index.
+	got, err := NewHashEmbedder(8).Embed(context.Background(), []string{"Memory leak in parser"})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	want := []float32{0, 0, -0.31622776, 0, 0, 0, 0.94868326, 0}
+	for i := range want {
+		if math.Abs(float64(got[0][i]-want[i])) > 1e-6 {
+			t.Fatalf("golden vector mismatch: got %v, want %v", got[0], want)
+		}
+	}
+}
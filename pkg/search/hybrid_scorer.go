@@ -6,6 +6,10 @@ type HybridScorer interface {
 	// Returns the final score and component breakdown.
 	Score(issueID string, textScore float64) (HybridScore, error)
 
+	// ScoreBatch computes hybrid scores for many candidates, fetching metrics
+	// in a single batch instead of per issue. Output order matches the input.
+	ScoreBatch(results []SearchResult) ([]HybridScore, error)
+
 	// Configure sets the weights for hybrid scoring.
 	Configure(weights Weights) error
 
@@ -37,17 +37,51 @@ func (s *hybridScorer) Score(issueID string, textScore float64) (HybridScore, er
 	}
 
 	metrics, found := s.cache.Get(issueID)
+	return s.scoreWithMetrics(issueID, textScore, metrics, found, s.cache.MaxBlockerCount()), nil
+}
+
+func (s *hybridScorer) ScoreBatch(results []SearchResult) ([]HybridScore, error) {
+	out := make([]HybridScore, 0, len(results))
+	if len(results) == 0 {
+		return out, nil
+	}
+
+	ids := make([]string, 0, len(results))
+	for _, r := range results {
+		if r.IssueID == "" {
+			return nil, fmt.Errorf("issueID is required")
+		}
+		ids = append(ids, r.IssueID)
+	}
+
+	if s.cache == nil {
+		for _, r := range results {
+			out = append(out, HybridScore{IssueID: r.IssueID, FinalScore: r.Score, TextScore: r.Score})
+		}
+		return out, nil
+	}
+
+	batch := s.cache.GetBatch(ids)
+	maxBlocker := s.cache.MaxBlockerCount()
+	for _, r := range results {
+		metrics, found := batch[r.IssueID]
+		out = append(out, s.scoreWithMetrics(r.IssueID, r.Score, metrics, found, maxBlocker))
+	}
+	return out, nil
+}
+
+func (s *hybridScorer) scoreWithMetrics(issueID string, textScore float64, metrics IssueMetrics, found bool, maxBlocker int) HybridScore {
 	if !found {
 		return HybridScore{
 			IssueID:    issueID,
 			FinalScore: textScore,
 			TextScore:  textScore,
-		}, nil
+		}
 	}
 
 	statusScore := normalizeStatus(metrics.Status)
 	priorityScore := normalizePriority(metrics.Priority)
-	impactScore := normalizeImpact(metrics.BlockerCount, s.cache.MaxBlockerCount())
+	impactScore := normalizeImpact(metrics.BlockerCount, maxBlocker)
 	recencyScore := normalizeRecency(metrics.UpdatedAt)
 
 	final := s.weights.TextRelevance*textScore +
@@ -68,7 +102,7 @@ func (s *hybridScorer) Score(issueID string, textScore float64) (HybridScore, er
 			"priority": priorityScore,
 			"recency":  recencyScore,
 		},
-	}, nil
+	}
 }
 
 func (s *hybridScorer) Configure(weights Weights) error {
@@ -1,6 +1,7 @@
 package search
 
 import (
+	"errors"
 	"math"
 	"testing"
 	"time"
@@ -107,13 +108,62 @@ func TestHybridScorer_Score_TextOnlyOnMissingMetrics(t *testing.T) {
 	}
 }
 
+func TestHybridScorer_ScoreBatch_MatchesScore(t *testing.T) {
+	now := time.Now()
+	cache := &stubMetricsCache{
+		metrics: map[string]IssueMetrics{
+			"A": {IssueID: "A", PageRank: 0.8, Status: "open", Priority: 0, BlockerCount: 4, UpdatedAt: now},
+			"B": {IssueID: "B", PageRank: 0.2, Status: "closed", Priority: 3, BlockerCount: 1, UpdatedAt: now},
+		},
+		maxBlockerCount: 4,
+	}
+	weights, err := GetPreset(PresetDefault)
+	if err != nil {
+		t.Fatalf("GetPreset: %v", err)
+	}
+	scorer := NewHybridScorer(weights, cache)
+
+	input := []SearchResult{{IssueID: "B", Score: 0.5}, {IssueID: "A", Score: 0.7}, {IssueID: "C", Score: 0.9}}
+	batch, err := scorer.ScoreBatch(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(batch) != len(input) {
+		t.Fatalf("expected %d scores, got %d", len(input), len(batch))
+	}
+	for i, r := range input {
+		single, err := scorer.Score(r.IssueID, r.Score)
+		if err != nil {
+			t.Fatalf("Score(%s): %v", r.IssueID, err)
+		}
+		if batch[i].IssueID != r.IssueID {
+			t.Fatalf("expected order preserved at %d: got %s", i, batch[i].IssueID)
+		}
+		if math.Abs(batch[i].FinalScore-single.FinalScore) > 1e-12 {
+			t.Fatalf("batch score for %s = %f, single = %f", r.IssueID, batch[i].FinalScore, single.FinalScore)
+		}
+	}
+	if batch[2].FinalScore != 0.9 || len(batch[2].ComponentScores) != 0 {
+		t.Fatalf("expected text-only score for missing metrics, got %+v", batch[2])
+	}
+
+	if _, err := scorer.ScoreBatch([]SearchResult{{IssueID: ""}}); err == nil {
+		t.Fatal("expected error for empty issue ID")
+	}
+}
+
 func TestHybridScorer_Configure(t *testing.T) {
 	cache := &stubMetricsCache{}
 	scorer := NewHybridScorer(Weights{TextRelevance: 1.0}, cache).(*hybridScorer)
 
-	if err := scorer.Configure(Weights{TextRelevance: -1}); err == nil {
+	err := scorer.Configure(Weights{TextRelevance: -1})
+	if err == nil {
 		t.Fatal("expected error for invalid weights")
 	}
+	var wErr *WeightValidationError
+	if !errors.As(err, &wErr) || wErr.Field != "text" {
+		t.Fatalf("expected WeightValidationError for text, got %v", err)
+	}
 
 	if scorer.weights.TextRelevance != 1.0 {
 		t.Fatalf("expected weights unchanged after invalid configure")
@@ -2,12 +2,15 @@ package search
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/logging"
 )
 
 // DefaultIndexPath returns the default semantic index path under the given project directory.
@@ -22,6 +25,11 @@ func DefaultIndexPath(projectDir string, cfg EmbeddingConfig) string {
 	return filepath.Join(projectDir, ".bv", "semantic", fmt.Sprintf("index-%s-%d.bvvi", safeProvider, cfg.Dim))
 }
 
+// ErrEmbedderDegraded is returned by SyncVectorIndexWithOptions when a
+// RetryingEmbedder has switched to its fallback: those vectors are not
+// comparable with the configured model's, so they are not stored.
+var ErrEmbedderDegraded = errors.New("embedder degraded to its fallback; index not updated")
+
 type IndexSyncStats struct {
 	Total    int `json:"total"`
 	Added    int `json:"added"`
@@ -29,17 +37,45 @@ type IndexSyncStats struct {
 	Removed  int `json:"removed"`
 	Skipped  int `json:"skipped"`
 	Embedded int `json:"embedded"`
+	// Reembedded counts unchanged documents embedded again because the model
+	// changed or their vectors exceeded the maximum age.
+	Reembedded int `json:"reembedded"`
+	// ThrottleWaits and ThrottleWaitMs report how often and how long a
+	// rate-limited embedder (see ThrottleReporter) held requests back.
+	ThrottleWaits  int   `json:"throttle_waits,omitempty"`
+	ThrottleWaitMs int64 `json:"throttle_wait_ms,omitempty"`
 }
 
 func (s IndexSyncStats) Changed() bool {
-	return s.Added+s.Updated+s.Removed > 0
+	return s.Added+s.Updated+s.Removed+s.Reembedded > 0
+}
+
+// IndexSyncOptions configures SyncVectorIndexWithOptions.
+type IndexSyncOptions struct {
+	// BatchSize is the number of documents per Embed call (default 32).
+	BatchSize int
+	// ModelID identifies the embedder (EmbeddingConfig.ModelID). When the index
+	// records a different one, every document is re-embedded. An index without
+	// a recorded id adopts this one as is.
+	ModelID string
+	// MaxAge re-embeds entries embedded longer ago than this; 0 means no limit.
+	MaxAge time.Duration
+	// Progress, when non-nil, is called with the documents embedded so far and
+	// the number needing embedding: once before the first batch and after each.
+	Progress func(done, total int)
 }
 
 // LoadOrNewVectorIndex loads an existing vector index if present, otherwise creates a new one.
 // If loading fails due to corruption, it backs up the corrupt file and returns a new empty index.
+// An index whose dimension differs from dim (the embedder changed) is discarded the same way:
+// a notice is logged and a new empty index is returned, so callers rebuild it from scratch.
 func LoadOrNewVectorIndex(path string, dim int) (*VectorIndex, bool, error) {
 	idx, err := LoadVectorIndex(path)
 	if err == nil {
+		if dim > 0 && idx.Dim != dim {
+			logging.Warn("semantic index dimension differs from the embedder's; rebuilding", "path", path, "index_dim", idx.Dim, "embedder_dim", dim)
+			return NewVectorIndex(dim), false, nil
+		}
 		return idx, true, nil
 	}
 	
@@ -68,7 +104,20 @@ func LoadOrNewVectorIndex(path string, dim int) (*VectorIndex, bool, error) {
 // This is intended for offline, deterministic embedding providers. Callers should persist idx
 // with (*VectorIndex).Save when desired.
 func SyncVectorIndex(ctx context.Context, idx *VectorIndex, embedder Embedder, docs map[string]string, batchSize int) (IndexSyncStats, error) {
-	var stats IndexSyncStats
+	return SyncVectorIndexWithOptions(ctx, idx, embedder, docs, IndexSyncOptions{BatchSize: batchSize})
+}
+
+// SyncVectorIndexWithProgress is SyncVectorIndex that calls progress (when non-nil) with the
+// number of documents embedded so far and the number needing embedding: once before the first
+// batch and after each batch. Unchanged documents are not counted.
+func SyncVectorIndexWithProgress(ctx context.Context, idx *VectorIndex, embedder Embedder, docs map[string]string, batchSize int, progress func(done, total int)) (IndexSyncStats, error) {
+	return SyncVectorIndexWithOptions(ctx, idx, embedder, docs, IndexSyncOptions{BatchSize: batchSize, Progress: progress})
+}
+
+// SyncVectorIndexWithOptions is SyncVectorIndex with a re-embed policy (model id, maximum age)
+// and progress reporting.
+func SyncVectorIndexWithOptions(ctx context.Context, idx *VectorIndex, embedder Embedder, docs map[string]string, opts IndexSyncOptions) (stats IndexSyncStats, err error) {
+	batchSize, progress := opts.BatchSize, opts.Progress
 	if idx == nil {
 		return stats, fmt.Errorf("index cannot be nil")
 	}
@@ -84,6 +133,15 @@ func SyncVectorIndex(ctx context.Context, idx *VectorIndex, embedder Embedder, d
 
 	stats.Total = len(docs)
 
+	now := time.Now().UTC()
+	modelChanged := opts.ModelID != "" && idx.ModelID != "" && idx.ModelID != opts.ModelID
+	if modelChanged {
+		idx.BuiltAt = now
+	}
+	if opts.ModelID != "" {
+		idx.ModelID = opts.ModelID
+	}
+
 	// Remove stale IDs.
 	docIDs := make(map[string]struct{}, len(docs))
 	for id := range docs {
@@ -115,10 +173,13 @@ func SyncVectorIndex(ctx context.Context, idx *VectorIndex, embedder Embedder, d
 		ch := ComputeContentHash(text)
 		existing, ok := idx.Get(id)
 		if ok && existing.ContentHash == ch {
-			stats.Skipped++
-			continue
-		}
-		if ok {
+			stale := opts.MaxAge > 0 && now.Sub(existing.EmbeddedAt) > opts.MaxAge
+			if !modelChanged && !stale {
+				stats.Skipped++
+				continue
+			}
+			stats.Reembedded++
+		} else if ok {
 			stats.Updated++
 		} else {
 			stats.Added++
@@ -128,6 +189,19 @@ func SyncVectorIndex(ctx context.Context, idx *VectorIndex, embedder Embedder, d
 		toEmbedHashes = append(toEmbedHashes, ch)
 	}
 
+	if progress != nil {
+		progress(0, len(toEmbedTexts))
+	}
+
+	if reporter, ok := embedder.(ThrottleReporter); ok {
+		before := reporter.ThrottleStats()
+		defer func() {
+			after := reporter.ThrottleStats()
+			stats.ThrottleWaits = after.Waits - before.Waits
+			stats.ThrottleWaitMs = (after.Waited - before.Waited).Milliseconds()
+		}()
+	}
+
 	// Embed in batches.
 	for start := 0; start < len(toEmbedTexts); start += batchSize {
 		if err := ctx.Err(); err != nil {
@@ -141,6 +215,9 @@ func SyncVectorIndex(ctx context.Context, idx *VectorIndex, embedder Embedder, d
 		if err != nil {
 			return stats, err
 		}
+		if degraded, ok := embedder.(interface{ Degraded() bool }); ok && degraded.Degraded() {
+			return stats, ErrEmbedderDegraded
+		}
 		if len(vecs) != end-start {
 			return stats, fmt.Errorf("embedder returned %d vectors for %d texts", len(vecs), end-start)
 		}
@@ -150,6 +227,9 @@ func SyncVectorIndex(ctx context.Context, idx *VectorIndex, embedder Embedder, d
 			}
 			stats.Embedded++
 		}
+		if progress != nil {
+			progress(end, len(toEmbedTexts))
+		}
 	}
 
 	return stats, nil
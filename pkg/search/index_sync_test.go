@@ -4,6 +4,7 @@ import (
 	"context"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestSyncVectorIndex_IncrementalUpdates(t *testing.T) {
@@ -94,3 +95,143 @@ func TestLoadOrNewVectorIndex(t *testing.T) {
 		t.Fatalf("expected 1 entry, got %d", loadedIdx.Size())
 	}
 }
+
+func TestSyncVectorIndexWithProgress_ReportsBatches(t *testing.T) {
+	embedder, err := NewEmbedderFromConfig(EmbeddingConfig{Provider: ProviderHash, Dim: 16})
+	if err != nil {
+		t.Fatalf("NewEmbedderFromConfig: %v", err)
+	}
+	idx := NewVectorIndex(embedder.Dim())
+	docs := map[string]string{"A": "one", "B": "two", "C": "three", "D": "four", "E": "five"}
+
+	var calls [][2]int
+	progress := func(done, total int) { calls = append(calls, [2]int{done, total}) }
+	if _, err := SyncVectorIndexWithProgress(context.Background(), idx, embedder, docs, 2, progress); err != nil {
+		t.Fatalf("SyncVectorIndexWithProgress: %v", err)
+	}
+	want := [][2]int{{0, 5}, {2, 5}, {4, 5}, {5, 5}}
+	if len(calls) != len(want) {
+		t.Fatalf("progress calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("progress calls = %v, want %v", calls, want)
+		}
+	}
+
+	// Nothing to embed: a single 0/0 report.
+	calls = nil
+	if _, err := SyncVectorIndexWithProgress(context.Background(), idx, embedder, docs, 2, progress); err != nil {
+		t.Fatalf("SyncVectorIndexWithProgress: %v", err)
+	}
+	if len(calls) != 1 || calls[0] != [2]int{0, 0} {
+		t.Fatalf("progress calls on an up-to-date index = %v, want [[0 0]]", calls)
+	}
+}
+
+func TestLoadOrNewVectorIndex_RebuildsOnDimMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "semantic", "index.bvvi")
+	old := NewVectorIndex(4)
+	if err := old.Upsert("A", ComputeContentHash("a"), []float32{1, 0, 0, 0}); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if err := old.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	embedder := NewHashEmbedder(8)
+	idx, loaded, err := LoadOrNewVectorIndex(path, embedder.Dim())
+	if err != nil {
+		t.Fatalf("LoadOrNewVectorIndex: %v", err)
+	}
+	if loaded {
+		t.Fatal("expected loaded=false so callers rebuild and save the index")
+	}
+	if idx.Dim != 8 || idx.Size() != 0 {
+		t.Fatalf("expected an empty dim-8 index, got dim %d with %d entries", idx.Dim, idx.Size())
+	}
+
+	stats, err := SyncVectorIndex(context.Background(), idx, embedder, map[string]string{"A": "a"}, 0)
+	if err != nil {
+		t.Fatalf("SyncVectorIndex: %v", err)
+	}
+	if stats.Added != 1 || stats.Embedded != 1 {
+		t.Fatalf("expected A to be re-embedded, got %+v", stats)
+	}
+	if err := idx.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	reloaded, loaded, err := LoadOrNewVectorIndex(path, embedder.Dim())
+	if err != nil || !loaded || reloaded.Dim != 8 {
+		t.Fatalf("expected the rebuilt dim-8 index to load, got loaded=%v err=%v", loaded, err)
+	}
+}
+
+func TestSyncVectorIndexWithOptions_ModelChangeForcesReembed(t *testing.T) {
+	embedder := NewHashEmbedder(8)
+	docs := map[string]string{"A": "alpha", "B": "beta"}
+	idx := NewVectorIndex(embedder.Dim())
+
+	opts := IndexSyncOptions{ModelID: "hash@8"}
+	if _, err := SyncVectorIndexWithOptions(context.Background(), idx, embedder, docs, opts); err != nil {
+		t.Fatalf("SyncVectorIndexWithOptions: %v", err)
+	}
+	if idx.ModelID != "hash@8" {
+		t.Fatalf("ModelID = %q, want hash@8", idx.ModelID)
+	}
+
+	// Same model, same text: nothing to do.
+	stats, err := SyncVectorIndexWithOptions(context.Background(), idx, embedder, docs, opts)
+	if err != nil {
+		t.Fatalf("SyncVectorIndexWithOptions: %v", err)
+	}
+	if stats.Changed() || stats.Skipped != 2 {
+		t.Fatalf("unexpected stats with unchanged model: %+v", stats)
+	}
+
+	// Upgraded model: every entry is re-embedded even though the text is unchanged.
+	builtBefore := idx.BuiltAt
+	stats, err = SyncVectorIndexWithOptions(context.Background(), idx, embedder, docs, IndexSyncOptions{ModelID: "hash:v2@8"})
+	if err != nil {
+		t.Fatalf("SyncVectorIndexWithOptions: %v", err)
+	}
+	if stats.Reembedded != 2 || stats.Embedded != 2 || stats.Skipped != 0 || !stats.Changed() {
+		t.Fatalf("expected 2 re-embeds after a model change, got %+v", stats)
+	}
+	if idx.ModelID != "hash:v2@8" || idx.BuiltAt.Before(builtBefore) {
+		t.Fatalf("index not restamped: model %q built %v", idx.ModelID, idx.BuiltAt)
+	}
+}
+
+func TestSyncVectorIndexWithOptions_MaxAge(t *testing.T) {
+	embedder := NewHashEmbedder(8)
+	idx := NewVectorIndex(embedder.Dim())
+	vecs, err := embedder.Embed(context.Background(), []string{"alpha", "beta"})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	_ = idx.upsertAt("A", ComputeContentHash("alpha"), vecs[0], old)
+	_ = idx.Upsert("B", ComputeContentHash("beta"), vecs[1])
+
+	docs := map[string]string{"A": "alpha", "B": "beta"}
+	stats, err := SyncVectorIndexWithOptions(context.Background(), idx, embedder, docs, IndexSyncOptions{MaxAge: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("SyncVectorIndexWithOptions: %v", err)
+	}
+	if stats.Reembedded != 1 || stats.Skipped != 1 {
+		t.Fatalf("expected only the 48h-old entry to be re-embedded, got %+v", stats)
+	}
+	if a, _ := idx.Get("A"); !a.EmbeddedAt.After(old) {
+		t.Fatalf("A was not restamped: %v", a.EmbeddedAt)
+	}
+}
+
+func TestEmbeddingConfigModelID(t *testing.T) {
+	if got := (EmbeddingConfig{Provider: ProviderHash}).ModelID(); got != "hash@384" {
+		t.Errorf("ModelID = %q, want hash@384", got)
+	}
+	if got := (EmbeddingConfig{Provider: ProviderOpenAI, Model: "text-embedding-3-small", Dim: 1536}).ModelID(); got != "openai:text-embedding-3-small@1536" {
+		t.Errorf("ModelID = %q", got)
+	}
+}
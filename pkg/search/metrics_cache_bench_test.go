@@ -17,7 +17,26 @@ func BenchmarkMetricsCacheGet(b *testing.B) {
 }
 
 func BenchmarkMetricsCacheGetBatch(b *testing.B) {
-	cache := buildBenchmarkMetricsCache(b, 1000)
+	b.Run("static_loader", func(b *testing.B) {
+		benchmarkGetBatch(b, buildBenchmarkMetricsCache(b, 1000))
+	})
+	b.Run("analyzer_loader", func(b *testing.B) {
+		benchmarkGetBatch(b, buildBenchmarkAnalyzerMetricsCache(b, 1000))
+	})
+}
+
+// BenchmarkMetricsCacheGetLoop is the per-ID baseline that GetBatch replaces:
+// every Get re-validates the data hash, which is costly for real loaders.
+func BenchmarkMetricsCacheGetLoop(b *testing.B) {
+	b.Run("static_loader", func(b *testing.B) {
+		benchmarkGetLoop(b, buildBenchmarkMetricsCache(b, 1000))
+	})
+	b.Run("analyzer_loader", func(b *testing.B) {
+		benchmarkGetLoop(b, buildBenchmarkAnalyzerMetricsCache(b, 1000))
+	})
+}
+
+func benchmarkGetBatch(b *testing.B, cache MetricsCache) {
 	ids := buildBenchmarkIssueIDs(100)
 
 	b.ReportAllocs()
@@ -27,6 +46,21 @@ func BenchmarkMetricsCacheGetBatch(b *testing.B) {
 	}
 }
 
+func benchmarkGetLoop(b *testing.B, cache MetricsCache) {
+	ids := buildBenchmarkIssueIDs(100)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		out := make(map[string]IssueMetrics, len(ids))
+		for _, id := range ids {
+			if metric, ok := cache.Get(id); ok {
+				out[id] = metric
+			}
+		}
+	}
+}
+
 func BenchmarkMetricsCacheMemory(b *testing.B) {
 	runtime.GC()
 	var m runtime.MemStats
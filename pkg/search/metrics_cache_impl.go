@@ -2,14 +2,21 @@ package search
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 
 	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 )
 
-const defaultPageRank = 0.5
+const (
+	defaultPageRank = 0.5
+
+	metricsDiskCacheVersion = 1
+)
 
 // metricsCache is the default MetricsCache implementation.
 type metricsCache struct {
@@ -18,6 +25,7 @@ type metricsCache struct {
 	dataHash        string
 	maxBlockerCount int
 	loader          MetricsLoader
+	cacheDir        string // Optional on-disk cache directory ("" = memory only)
 }
 
 // NewMetricsCache creates a MetricsCache backed by the provided loader.
@@ -28,6 +36,34 @@ func NewMetricsCache(loader MetricsLoader) MetricsCache {
 	}
 }
 
+// NewPersistentMetricsCache creates a MetricsCache that also persists computed
+// metrics to cacheDir as metrics-<hash>.json. On Refresh, a file matching the
+// current data hash is reused instead of recomputing, so consecutive processes
+// (e.g. a sequence of robot commands) only pay for graph analysis once.
+func NewPersistentMetricsCache(loader MetricsLoader, cacheDir string) MetricsCache {
+	return &metricsCache{
+		metrics:  make(map[string]IssueMetrics),
+		loader:   loader,
+		cacheDir: cacheDir,
+	}
+}
+
+// DefaultMetricsCacheDir returns the on-disk metrics cache directory for a beads directory.
+func DefaultMetricsCacheDir(beadsDir string) string {
+	return filepath.Join(beadsDir, ".cache")
+}
+
+// MetricsCachePath returns the cache file path for a given data hash.
+func MetricsCachePath(cacheDir, dataHash string) string {
+	return filepath.Join(cacheDir, fmt.Sprintf("metrics-%s.json", dataHash))
+}
+
+type metricsDiskCacheFile struct {
+	Version  int                     `json:"version"`
+	DataHash string                  `json:"data_hash"`
+	Metrics  map[string]IssueMetrics `json:"metrics"`
+}
+
 // AnalyzerMetricsLoader loads metrics from the analysis engine.
 type AnalyzerMetricsLoader struct {
 	issues []model.Issue
@@ -112,29 +148,26 @@ func (c *metricsCache) Get(issueID string) (IssueMetrics, bool) {
 }
 
 // GetBatch returns metrics for multiple issues efficiently.
+// Freshness is checked (and any Refresh performed) once for the whole batch
+// rather than per ID. Only IDs with known metrics are included in the result.
 func (c *metricsCache) GetBatch(issueIDs []string) map[string]IssueMetrics {
-	results := make(map[string]IssueMetrics, len(issueIDs))
 	if len(issueIDs) == 0 {
-		return results
+		return map[string]IssueMetrics{}
 	}
 
 	if err := c.ensureFresh(); err != nil {
-		for _, id := range issueIDs {
-			results[id] = defaultIssueMetrics(id)
-		}
-		return results
+		return map[string]IssueMetrics{}
 	}
 
 	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	results := make(map[string]IssueMetrics, min(len(issueIDs), len(c.metrics)))
 	for _, id := range issueIDs {
-		metric, ok := c.metrics[id]
-		if !ok {
-			metric = defaultIssueMetrics(id)
+		if metric, ok := c.metrics[id]; ok {
+			results[id] = metric
 		}
-		results[id] = metric
 	}
-	c.mu.RUnlock()
-
 	return results
 }
 
@@ -144,14 +177,35 @@ func (c *metricsCache) Refresh() error {
 		return fmt.Errorf("metrics loader is nil")
 	}
 
-	metrics, err := c.loader.LoadMetrics()
-	if err != nil {
-		return err
-	}
-
-	hash, err := c.loader.ComputeDataHash()
-	if err != nil {
-		return err
+	var (
+		metrics map[string]IssueMetrics
+		hash    string
+		err     error
+	)
+	if c.cacheDir != "" {
+		hash, err = c.loader.ComputeDataHash()
+		if err != nil {
+			return err
+		}
+		if cached, ok := readMetricsDiskCache(c.cacheDir, hash); ok {
+			metrics = cached
+		} else {
+			metrics, err = c.loader.LoadMetrics()
+			if err != nil {
+				return err
+			}
+			// Best-effort: a failed write only costs a recompute next time.
+			_ = writeMetricsDiskCache(c.cacheDir, hash, metrics)
+		}
+	} else {
+		metrics, err = c.loader.LoadMetrics()
+		if err != nil {
+			return err
+		}
+		hash, err = c.loader.ComputeDataHash()
+		if err != nil {
+			return err
+		}
 	}
 
 	copied := make(map[string]IssueMetrics, len(metrics))
@@ -207,6 +261,88 @@ func (c *metricsCache) ensureFresh() error {
 	return c.Refresh()
 }
 
+func readMetricsDiskCache(cacheDir, hash string) (map[string]IssueMetrics, bool) {
+	if hash == "" {
+		return nil, false
+	}
+	data, err := os.ReadFile(MetricsCachePath(cacheDir, hash))
+	if err != nil {
+		return nil, false
+	}
+	var cf metricsDiskCacheFile
+	if err := json.Unmarshal(data, &cf); err != nil {
+		return nil, false
+	}
+	if cf.Version != metricsDiskCacheVersion || cf.DataHash != hash || cf.Metrics == nil {
+		return nil, false
+	}
+	return cf.Metrics, true
+}
+
+func writeMetricsDiskCache(cacheDir, hash string, metrics map[string]IssueMetrics) error {
+	if hash == "" {
+		return fmt.Errorf("data hash is empty")
+	}
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return fmt.Errorf("mkdir %s: %w", cacheDir, err)
+	}
+	// .beads is committed; keep derived cache files out of git.
+	ignorePath := filepath.Join(cacheDir, ".gitignore")
+	if _, err := os.Stat(ignorePath); os.IsNotExist(err) {
+		_ = os.WriteFile(ignorePath, []byte("*\n"), 0o644)
+	}
+
+	data, err := json.Marshal(metricsDiskCacheFile{
+		Version:  metricsDiskCacheVersion,
+		DataHash: hash,
+		Metrics:  metrics,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal metrics: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(cacheDir, "metrics-*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		return fmt.Errorf("write temp: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp: %w", err)
+	}
+	path := MetricsCachePath(cacheDir, hash)
+	if err := os.Rename(tmpPath, path); err != nil {
+		// Windows can't rename over an existing file; the content is keyed by
+		// hash, so an existing file is already equivalent.
+		if _, statErr := os.Stat(path); statErr != nil {
+			return fmt.Errorf("rename: %w", err)
+		}
+	}
+	pruneMetricsDiskCache(cacheDir, path)
+	return nil
+}
+
+// pruneMetricsDiskCache removes metrics files for other data hashes. Each data
+// change produces a new file, so without pruning the cache grows unbounded.
+func pruneMetricsDiskCache(cacheDir, keep string) {
+	matches, err := filepath.Glob(filepath.Join(cacheDir, "metrics-*.json"))
+	if err != nil {
+		return
+	}
+	for _, m := range matches {
+		if m != keep {
+			_ = os.Remove(m)
+		}
+	}
+}
+
 func defaultIssueMetrics(issueID string) IssueMetrics {
 	return IssueMetrics{
 		IssueID:      issueID,
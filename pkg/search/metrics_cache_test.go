@@ -2,6 +2,7 @@ package search
 
 import (
 	"errors"
+	"os"
 	"testing"
 	"time"
 
@@ -80,7 +81,7 @@ func TestMetricsCache_Get_RefreshesOnHashChange(t *testing.T) {
 	}
 }
 
-func TestMetricsCache_GetBatch_DefaultsForMissing(t *testing.T) {
+func TestMetricsCache_GetBatch_OmitsMissing(t *testing.T) {
 	loader := &stubMetricsLoader{
 		hash: "hash1",
 		metrics: map[string]IssueMetrics{
@@ -90,14 +91,47 @@ func TestMetricsCache_GetBatch_DefaultsForMissing(t *testing.T) {
 	cache := NewMetricsCache(loader)
 
 	results := cache.GetBatch([]string{"A", "B"})
-	if len(results) != 2 {
-		t.Fatalf("expected 2 results, got %d", len(results))
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
 	}
 	if results["A"].PageRank != 0.3 {
 		t.Fatalf("expected A PageRank 0.3, got %f", results["A"].PageRank)
 	}
-	if results["B"].PageRank != defaultPageRank {
-		t.Fatalf("expected B default PageRank %f, got %f", defaultPageRank, results["B"].PageRank)
+	if _, ok := results["B"]; ok {
+		t.Fatal("expected missing ID B to be omitted")
+	}
+}
+
+func TestMetricsCache_GetBatch_RefreshesOnce(t *testing.T) {
+	loader := &stubMetricsLoader{
+		hash: "hash1",
+		metrics: map[string]IssueMetrics{
+			"A": {IssueID: "A", PageRank: 0.1},
+			"B": {IssueID: "B", PageRank: 0.2},
+			"C": {IssueID: "C", PageRank: 0.3},
+		},
+	}
+	cache := NewMetricsCache(loader)
+
+	results := cache.GetBatch([]string{"A", "B", "C", "missing"})
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if loader.loadCalls != 1 {
+		t.Fatalf("expected a single load for the batch, got %d", loader.loadCalls)
+	}
+
+	loader.hash = "hash2"
+	_ = cache.GetBatch([]string{"A", "B", "C"})
+	if loader.loadCalls != 2 {
+		t.Fatalf("expected one reload after hash change, got %d", loader.loadCalls)
+	}
+}
+
+func TestMetricsCache_GetBatch_EmptyOnError(t *testing.T) {
+	cache := NewMetricsCache(&stubMetricsLoader{hashErr: errors.New("boom")})
+	if results := cache.GetBatch([]string{"A"}); len(results) != 0 {
+		t.Fatalf("expected empty batch on loader error, got %v", results)
 	}
 }
 
@@ -178,3 +212,98 @@ func TestAnalyzerMetricsLoader_LoadMetrics(t *testing.T) {
 		t.Fatalf("expected hash %q, got %q", expectedHash, hash)
 	}
 }
+
+func TestPersistentMetricsCache_ReusesFileForSameHash(t *testing.T) {
+	dir := t.TempDir()
+	loader := &stubMetricsLoader{
+		hash: "hash1",
+		metrics: map[string]IssueMetrics{
+			"A": {IssueID: "A", PageRank: 0.4, BlockerCount: 3},
+		},
+	}
+
+	first := NewPersistentMetricsCache(loader, dir)
+	if err := first.Refresh(); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if loader.loadCalls != 1 {
+		t.Fatalf("expected 1 load call, got %d", loader.loadCalls)
+	}
+	if _, err := os.Stat(MetricsCachePath(dir, "hash1")); err != nil {
+		t.Fatalf("expected cache file to be written: %v", err)
+	}
+
+	// A fresh cache (simulating a new process) should load from disk.
+	second := NewPersistentMetricsCache(loader, dir)
+	metric, ok := second.Get("A")
+	if !ok {
+		t.Fatal("expected metric to be found")
+	}
+	if metric.PageRank != 0.4 || metric.BlockerCount != 3 {
+		t.Fatalf("unexpected metric from disk cache: %+v", metric)
+	}
+	if second.MaxBlockerCount() != 3 {
+		t.Fatalf("expected max blocker count 3, got %d", second.MaxBlockerCount())
+	}
+	if second.DataHash() != "hash1" {
+		t.Fatalf("expected data hash hash1, got %q", second.DataHash())
+	}
+	if loader.loadCalls != 1 {
+		t.Fatalf("expected disk hit to skip LoadMetrics, got %d load calls", loader.loadCalls)
+	}
+}
+
+func TestPersistentMetricsCache_RecomputesOnHashChange(t *testing.T) {
+	dir := t.TempDir()
+	loader := &stubMetricsLoader{
+		hash: "hash1",
+		metrics: map[string]IssueMetrics{
+			"A": {IssueID: "A", PageRank: 0.1},
+		},
+	}
+	if err := NewPersistentMetricsCache(loader, dir).Refresh(); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	loader.hash = "hash2"
+	loader.metrics = map[string]IssueMetrics{
+		"A": {IssueID: "A", PageRank: 0.9},
+	}
+	cache := NewPersistentMetricsCache(loader, dir)
+	metric, ok := cache.Get("A")
+	if !ok {
+		t.Fatal("expected metric to be found")
+	}
+	if metric.PageRank != 0.9 {
+		t.Fatalf("expected recomputed PageRank 0.9, got %f", metric.PageRank)
+	}
+	if loader.loadCalls != 2 {
+		t.Fatalf("expected 2 load calls, got %d", loader.loadCalls)
+	}
+	if _, err := os.Stat(MetricsCachePath(dir, "hash2")); err != nil {
+		t.Fatalf("expected new cache file to be written: %v", err)
+	}
+	if _, err := os.Stat(MetricsCachePath(dir, "hash1")); !os.IsNotExist(err) {
+		t.Fatalf("expected stale cache file to be pruned, stat err=%v", err)
+	}
+}
+
+func TestPersistentMetricsCache_IgnoresCorruptFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(MetricsCachePath(dir, "hash1"), []byte("{not json"), 0o644); err != nil {
+		t.Fatalf("write corrupt file: %v", err)
+	}
+	loader := &stubMetricsLoader{
+		hash: "hash1",
+		metrics: map[string]IssueMetrics{
+			"A": {IssueID: "A", PageRank: 0.2},
+		},
+	}
+	cache := NewPersistentMetricsCache(loader, dir)
+	if metric, ok := cache.Get("A"); !ok || metric.PageRank != 0.2 {
+		t.Fatalf("expected recomputed metric, got %+v ok=%v", metric, ok)
+	}
+	if loader.loadCalls != 1 {
+		t.Fatalf("expected 1 load call, got %d", loader.loadCalls)
+	}
+}
@@ -1,6 +1,9 @@
 package search
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // PresetName identifies a named weight configuration.
 type PresetName string
@@ -56,13 +59,52 @@ var presets = map[PresetName]Weights{
 	},
 }
 
-// GetPreset returns the weights for a named preset.
+// presetAliases maps common shorthands to their preset.
+var presetAliases = map[string]PresetName{
+	"impact":   PresetImpactFirst,
+	"bugs":     PresetBugHunting,
+	"bug":      PresetBugHunting,
+	"sprint":   PresetSprintPlanning,
+	"planning": PresetSprintPlanning,
+	"text":     PresetTextOnly,
+}
+
+// ResolvePreset returns the preset a user-supplied name refers to. Matching
+// ignores case and treats underscores and spaces as hyphens, and aliases such
+// as "impact" (impact-first) are accepted. Unknown names are an error that
+// suggests the closest preset.
+func ResolvePreset(name string) (PresetName, error) {
+	key := strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '-' || r == '_' || r == ' '
+	}), "-")
+	if _, ok := presets[PresetName(key)]; ok {
+		return PresetName(key), nil
+	}
+	if preset, ok := presetAliases[key]; ok {
+		return preset, nil
+	}
+
+	valid := make([]string, 0, len(presets))
+	for _, p := range ListPresets() {
+		valid = append(valid, string(p))
+	}
+	if closest := closestName(key, valid); closest != "" {
+		return "", fmt.Errorf("unknown preset %q (did you mean %q? valid: %s)", name, closest, strings.Join(valid, ", "))
+	}
+	return "", fmt.Errorf("unknown preset %q (valid: %s)", name, strings.Join(valid, ", "))
+}
+
+// GetPreset returns the weights for a named preset, resolving aliases and
+// suggesting the closest preset for unknown names (see ResolvePreset).
 func GetPreset(name PresetName) (Weights, error) {
-	weights, ok := presets[name]
-	if !ok {
-		return Weights{}, fmt.Errorf("unknown preset %q", name)
+	if weights, ok := presets[name]; ok {
+		return weights, nil
 	}
-	return weights, nil
+	resolved, err := ResolvePreset(string(name))
+	if err != nil {
+		return Weights{}, err
+	}
+	return presets[resolved], nil
 }
 
 // ListPresets returns all available preset names.
@@ -75,3 +117,41 @@ func ListPresets() []PresetName {
 		PresetTextOnly,
 	}
 }
+
+// closestName returns the candidate nearest to name by Levenshtein distance
+// (ties go to the earlier candidate), or "" when even the nearest differs in
+// more than half its characters and would be a misleading suggestion.
+func closestName(name string, candidates []string) string {
+	best, bestDist := "", -1
+	for _, c := range candidates {
+		if d := levenshtein(name, c); bestDist < 0 || d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	if best == "" || bestDist > len([]rune(best))/2 {
+		return ""
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
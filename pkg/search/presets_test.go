@@ -174,3 +174,42 @@ func TestPresetsMatchJavaScript_ParseGuard(t *testing.T) {
 		t.Fatalf("expected JS presets file: %v", err)
 	}
 }
+
+func TestGetPresetSuggestsClosest(t *testing.T) {
+	_, err := GetPreset("impcat-first")
+	if err == nil {
+		t.Fatal("expected error for typo'd preset")
+	}
+	if !strings.Contains(err.Error(), `did you mean "impact-first"?`) {
+		t.Fatalf("expected suggestion of impact-first, got %v", err)
+	}
+
+	_, err = GetPreset("zzz")
+	if err == nil || strings.Contains(err.Error(), "did you mean") || !strings.Contains(err.Error(), "valid: default, bug-hunting") {
+		t.Fatalf("expected unknown preset error listing presets without a suggestion, got %v", err)
+	}
+}
+
+func TestResolvePresetAliases(t *testing.T) {
+	for input, want := range map[string]PresetName{
+		"impact":          PresetImpactFirst,
+		"Impact_First":    PresetImpactFirst,
+		"sprint planning": PresetSprintPlanning,
+		"bugs":            PresetBugHunting,
+		"text":            PresetTextOnly,
+		"DEFAULT":         PresetDefault,
+	} {
+		got, err := ResolvePreset(input)
+		if err != nil || got != want {
+			t.Errorf("ResolvePreset(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+
+	aliased, err := GetPreset("impact")
+	if err != nil {
+		t.Fatalf("GetPreset(alias): %v", err)
+	}
+	if aliased != presets[PresetImpactFirst] {
+		t.Errorf("alias weights = %+v, want impact-first", aliased)
+	}
+}
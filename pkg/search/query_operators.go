@@ -0,0 +1,151 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// QueryOperators holds the exact-match operators parsed from a search query.
+//
+// Supported syntax:
+//   - "token refresh": the words must appear contiguously, in that order
+//   - +term: the document must contain term
+//   - -term: documents containing term are dropped
+//
+// Matching is case-insensitive and works on whole words; phrases do not span
+// document lines, so a phrase cannot straddle the title and the description.
+type QueryOperators struct {
+	// Text is the query with operator syntax removed; this is what gets embedded.
+	// Phrases and +terms stay in it, -terms do not.
+	Text     string   `json:"text"`
+	Phrases  []string `json:"phrases,omitempty"`
+	Required []string `json:"required,omitempty"`
+	Excluded []string `json:"excluded,omitempty"`
+}
+
+// ParseQueryOperators splits a raw query into free text and exact-match operators.
+// An unterminated quote is treated as a phrase running to the end of the query.
+func ParseQueryOperators(query string) QueryOperators {
+	var ops QueryOperators
+	var text []string
+
+	rest := strings.TrimSpace(query)
+	for rest != "" {
+		if rest[0] == '"' {
+			phrase := rest[1:]
+			end := strings.IndexByte(phrase, '"')
+			if end >= 0 {
+				rest = phrase[end+1:]
+				phrase = phrase[:end]
+			} else {
+				rest = ""
+			}
+			if words := queryWords(phrase); len(words) > 0 {
+				ops.Phrases = append(ops.Phrases, strings.Join(words, " "))
+				text = append(text, strings.TrimSpace(phrase))
+			}
+			rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+			continue
+		}
+
+		token := rest
+		if i := strings.IndexFunc(rest, unicode.IsSpace); i >= 0 {
+			token, rest = rest[:i], strings.TrimLeftFunc(rest[i:], unicode.IsSpace)
+		} else {
+			rest = ""
+		}
+
+		switch {
+		case len(token) > 1 && token[0] == '+':
+			if words := queryWords(token[1:]); len(words) > 0 {
+				ops.Required = append(ops.Required, strings.Join(words, " "))
+				text = append(text, token[1:])
+			}
+		case len(token) > 1 && token[0] == '-':
+			if words := queryWords(token[1:]); len(words) > 0 {
+				ops.Excluded = append(ops.Excluded, strings.Join(words, " "))
+			}
+		default:
+			text = append(text, token)
+		}
+	}
+
+	ops.Text = strings.Join(text, " ")
+	return ops
+}
+
+// HasOperators reports whether the query used any phrase, +term or -term syntax.
+func (q QueryOperators) HasOperators() bool {
+	return len(q.Phrases)+len(q.Required)+len(q.Excluded) > 0
+}
+
+// Matches reports whether doc satisfies every phrase and +term and contains no -term.
+func (q QueryOperators) Matches(doc string) bool {
+	if !q.HasOperators() {
+		return true
+	}
+	var lines [][]string
+	for _, line := range strings.Split(doc, "\n") {
+		if words := queryWords(line); len(words) > 0 {
+			lines = append(lines, words)
+		}
+	}
+	for _, term := range q.Excluded {
+		if containsWords(lines, strings.Fields(term)) {
+			return false
+		}
+	}
+	for _, term := range q.Required {
+		if !containsWords(lines, strings.Fields(term)) {
+			return false
+		}
+	}
+	for _, phrase := range q.Phrases {
+		if !containsWords(lines, strings.Fields(phrase)) {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterResults drops results whose document does not satisfy the operators,
+// keeping the original order. Results without a document are dropped too.
+func (q QueryOperators) FilterResults(results []SearchResult, docs map[string]string) []SearchResult {
+	if !q.HasOperators() {
+		return results
+	}
+	filtered := results[:0]
+	for _, r := range results {
+		doc, ok := docs[r.IssueID]
+		if ok && q.Matches(doc) {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// queryWords lowercases s and splits it into letter/digit words.
+func queryWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// containsWords reports whether any line contains seq as a contiguous run of words.
+func containsWords(lines [][]string, seq []string) bool {
+	if len(seq) == 0 {
+		return true
+	}
+	for _, words := range lines {
+	outer:
+		for i := 0; i+len(seq) <= len(words); i++ {
+			for j := range seq {
+				if words[i+j] != seq[j] {
+					continue outer
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
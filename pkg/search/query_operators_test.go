@@ -0,0 +1,56 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseQueryOperators(t *testing.T) {
+	got := ParseQueryOperators(`"Token Refresh" +oauth -legacy login`)
+	want := QueryOperators{
+		Text:     "Token Refresh oauth login",
+		Phrases:  []string{"token refresh"},
+		Required: []string{"oauth"},
+		Excluded: []string{"legacy"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseQueryOperators = %+v, want %+v", got, want)
+	}
+
+	plain := ParseQueryOperators("bv-123 fix")
+	if plain.HasOperators() || plain.Text != "bv-123 fix" {
+		t.Fatalf("plain query parsed as %+v", plain)
+	}
+}
+
+func TestQueryOperators_PhraseRequiresContiguousOrder(t *testing.T) {
+	ops := ParseQueryOperators(`"token refresh"`)
+	if !ops.Matches("Handle token refresh on expiry") {
+		t.Fatal("expected the exact phrase to match")
+	}
+	if ops.Matches("Refresh the token cache") {
+		t.Fatal("reordered words must not match a phrase")
+	}
+	if ops.Matches("Rotate token\nrefresh docs") {
+		t.Fatal("a phrase must not span document lines")
+	}
+}
+
+func TestQueryOperators_ExclusionFiltersResults(t *testing.T) {
+	docs := map[string]string{
+		"A": "Fix login flow\nOAuth redirect handling",
+		"B": "Fix login flow\nLegacy session cookies",
+		"C": "Update docs",
+	}
+	results := []SearchResult{{IssueID: "B", Score: 0.9}, {IssueID: "A", Score: 0.8}, {IssueID: "C", Score: 0.1}}
+
+	filtered := ParseQueryOperators("login -legacy").FilterResults(results, docs)
+	if len(filtered) != 2 || filtered[0].IssueID != "A" || filtered[1].IssueID != "C" {
+		t.Fatalf("expected B to be excluded, got %+v", filtered)
+	}
+
+	required := ParseQueryOperators("+oauth login").FilterResults([]SearchResult{{IssueID: "A"}, {IssueID: "B"}}, docs)
+	if len(required) != 1 || required[0].IssueID != "A" {
+		t.Fatalf("expected only A to contain +oauth, got %+v", required)
+	}
+}
@@ -0,0 +1,225 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	EnvSemanticRequestsPerMinute = "BV_SEMANTIC_RPM"
+	EnvSemanticTokensPerMinute   = "BV_SEMANTIC_TPM"
+)
+
+// RateLimit caps the request and token throughput of a network embedder.
+// Zero fields are unlimited.
+type RateLimit struct {
+	RequestsPerMinute int
+	TokensPerMinute   int
+}
+
+// RateLimitFromEnv reads BV_SEMANTIC_RPM and BV_SEMANTIC_TPM; invalid values
+// are ignored.
+func RateLimitFromEnv() RateLimit {
+	var rl RateLimit
+	if n, err := strconv.Atoi(strings.TrimSpace(os.Getenv(EnvSemanticRequestsPerMinute))); err == nil && n > 0 {
+		rl.RequestsPerMinute = n
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(os.Getenv(EnvSemanticTokensPerMinute))); err == nil && n > 0 {
+		rl.TokensPerMinute = n
+	}
+	return rl
+}
+
+// ThrottleStats reports how long an embedder held requests back to stay
+// within its rate limits.
+type ThrottleStats struct {
+	Waits   int           // Requests that had to wait for the limiter
+	Waited  time.Duration // Total time spent waiting
+	Shrinks int           // Times the batch size was halved after a 429
+}
+
+// ThrottleReporter is implemented by embedders that rate-limit their calls;
+// SyncVectorIndexWithOptions copies the stats into IndexSyncStats.
+type ThrottleReporter interface {
+	ThrottleStats() ThrottleStats
+}
+
+// EstimateTokens approximates the token count of text for rate limiting,
+// using the common four-characters-per-token rule of thumb.
+func EstimateTokens(text string) int {
+	return len(text)/4 + 1
+}
+
+// tokenBucket refills at rate units per second up to capacity. Reservations
+// may drive the balance negative; the caller then waits for it to recover,
+// so work is delayed rather than dropped.
+type tokenBucket struct {
+	rate     float64
+	capacity float64
+	balance  float64
+	last     time.Time
+}
+
+func newTokenBucket(perMinute int, now time.Time) *tokenBucket {
+	if perMinute <= 0 {
+		return nil
+	}
+	rate := float64(perMinute) / 60
+	capacity := rate // one second of burst
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &tokenBucket{rate: rate, capacity: capacity, balance: capacity, last: now}
+}
+
+// reserve takes n units and returns how long to wait before using them.
+func (b *tokenBucket) reserve(n float64, now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.balance += elapsed * b.rate
+		if b.balance > b.capacity {
+			b.balance = b.capacity
+		}
+		b.last = now
+	}
+	b.balance -= n
+	if b.balance >= 0 {
+		return 0
+	}
+	return time.Duration(-b.balance / b.rate * float64(time.Second))
+}
+
+// RateLimitedEmbedder spaces out calls to a network embedder to stay under
+// requests-per-minute and tokens-per-minute limits. Each Embed call is split
+// into sub-batches that fit the token burst; when the provider still answers
+// 429 the batch size is halved and the same texts are retried after an
+// exponential backoff, so no document is dropped.
+type RateLimitedEmbedder struct {
+	inner Embedder
+
+	mu       sync.Mutex
+	requests *tokenBucket
+	tokens   *tokenBucket
+	maxBatch int // Adaptive cap on texts per request; 0 means no cap yet
+	stats    ThrottleStats
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewRateLimitedEmbedder wraps inner with limit. A zero limit only adds the
+// adaptive batch sizing on 429 responses.
+func NewRateLimitedEmbedder(inner Embedder, limit RateLimit) *RateLimitedEmbedder {
+	now := time.Now()
+	return &RateLimitedEmbedder{
+		inner:    inner,
+		requests: newTokenBucket(limit.RequestsPerMinute, now),
+		tokens:   newTokenBucket(limit.TokensPerMinute, now),
+		now:      time.Now,
+		sleep:    sleepContext,
+	}
+}
+
+func (r *RateLimitedEmbedder) Provider() Provider { return r.inner.Provider() }
+func (r *RateLimitedEmbedder) Dim() int           { return r.inner.Dim() }
+
+func (r *RateLimitedEmbedder) ThrottleStats() ThrottleStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stats
+}
+
+func (r *RateLimitedEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, 0, len(texts))
+	throttled := 0 // Consecutive 429s; drives the backoff before the retry
+	for start := 0; start < len(texts); {
+		end := r.batchEnd(texts, start)
+		if err := r.wait(ctx, texts[start:end]); err != nil {
+			return nil, err
+		}
+		vecs, err := r.inner.Embed(ctx, texts[start:end])
+		if err != nil {
+			var status *HTTPStatusError
+			if errors.As(err, &status) && status.StatusCode == http.StatusTooManyRequests && end-start > 1 {
+				throttled++
+				if err := r.backoff(ctx, end-start, throttled); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			return nil, err
+		}
+		throttled = 0
+		out = append(out, vecs...)
+		start = end
+	}
+	return out, nil
+}
+
+// backoff halves the batch size after the n-th consecutive 429 and waits
+// DefaultEmbedRetryBaseDelay, doubled per further 429 (capped at
+// DefaultEmbedRetryMaxDelay), so the provider's window can drain.
+func (r *RateLimitedEmbedder) backoff(ctx context.Context, batch, n int) error {
+	wait := RetryPolicy{}.Normalized().delay(n)
+	r.mu.Lock()
+	r.maxBatch = batch / 2
+	r.stats.Shrinks++
+	r.stats.Waits++
+	r.stats.Waited += wait
+	r.mu.Unlock()
+	return r.sleep(ctx, wait)
+}
+
+// batchEnd picks the end of the next sub-batch: at most maxBatch texts and,
+// when tokens are limited, no more than one second of token budget (always
+// at least one text).
+func (r *RateLimitedEmbedder) batchEnd(texts []string, start int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	end := len(texts)
+	if r.maxBatch > 0 && start+r.maxBatch < end {
+		end = start + r.maxBatch
+	}
+	if r.tokens != nil {
+		budget := 0
+		for i := start; i < end; i++ {
+			budget += EstimateTokens(texts[i])
+			if i > start && float64(budget) > r.tokens.capacity {
+				return i
+			}
+		}
+	}
+	return end
+}
+
+// wait reserves one request and the batch's estimated tokens, then blocks
+// until both limits allow the call.
+func (r *RateLimitedEmbedder) wait(ctx context.Context, batch []string) error {
+	tokens := 0
+	for _, t := range batch {
+		tokens += EstimateTokens(t)
+	}
+	r.mu.Lock()
+	now := r.now()
+	d := r.requests.reserve(1, now)
+	if td := r.tokens.reserve(float64(tokens), now); td > d {
+		d = td
+	}
+	if d > 0 {
+		r.stats.Waits++
+		r.stats.Waited += d
+	}
+	r.mu.Unlock()
+	if d <= 0 {
+		return nil
+	}
+	return r.sleep(ctx, d)
+}
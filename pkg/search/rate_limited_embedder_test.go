@@ -0,0 +1,140 @@
+package search
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordingEmbedder records each batch it is asked to embed and can answer
+// 429 for batches larger than maxOK.
+type recordingEmbedder struct {
+	dim     int
+	maxOK   int
+	batches [][]string
+	at      []time.Time
+	clock   *time.Time
+}
+
+func (e *recordingEmbedder) Provider() Provider { return Provider("recording") }
+func (e *recordingEmbedder) Dim() int           { return e.dim }
+
+func (e *recordingEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	e.batches = append(e.batches, append([]string(nil), texts...))
+	if e.clock != nil {
+		e.at = append(e.at, *e.clock)
+	}
+	if e.maxOK > 0 && len(texts) > e.maxOK {
+		return nil, &HTTPStatusError{StatusCode: http.StatusTooManyRequests}
+	}
+	out := make([][]float32, len(texts))
+	for i := range out {
+		out[i] = make([]float32, e.dim)
+	}
+	return out, nil
+}
+
+func fakeClock(emb *RateLimitedEmbedder, clock *time.Time) {
+	emb.now = func() time.Time { return *clock }
+	emb.sleep = func(_ context.Context, d time.Duration) error {
+		*clock = clock.Add(d)
+		return nil
+	}
+}
+
+func TestRateLimitedEmbedder_SpacesRequestsUnderTightRate(t *testing.T) {
+	clock := time.Unix(0, 0)
+	inner := &recordingEmbedder{dim: 4, clock: &clock}
+	emb := NewRateLimitedEmbedder(inner, RateLimit{RequestsPerMinute: 60})
+	fakeClock(emb, &clock)
+	emb.requests.last = clock
+
+	for i := 0; i < 4; i++ {
+		if _, err := emb.Embed(context.Background(), []string{"doc"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 60 requests/minute allows one per second after the initial burst of one.
+	for i := 1; i < len(inner.at); i++ {
+		if gap := inner.at[i].Sub(inner.at[i-1]); gap < 990*time.Millisecond {
+			t.Errorf("request %d followed the previous after %v, want ~1s", i, gap)
+		}
+	}
+	stats := emb.ThrottleStats()
+	if stats.Waits != 3 || stats.Waited < 2900*time.Millisecond {
+		t.Errorf("unexpected throttle stats: %+v", stats)
+	}
+}
+
+func TestRateLimitedEmbedder_SplitsBatchesByTokenBudget(t *testing.T) {
+	clock := time.Unix(0, 0)
+	inner := &recordingEmbedder{dim: 4}
+	// 600 tokens/minute = 10 tokens of burst; each 36-char text is ~10 tokens.
+	emb := NewRateLimitedEmbedder(inner, RateLimit{TokensPerMinute: 600})
+	fakeClock(emb, &clock)
+	emb.tokens.last = clock
+
+	text := strings.Repeat("abcd", 9)
+	vecs, err := emb.Embed(context.Background(), []string{text, text, text})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vecs) != 3 || len(inner.batches) != 3 {
+		t.Fatalf("expected 3 vectors in 3 requests, got %d vectors in %d requests", len(vecs), len(inner.batches))
+	}
+	if clock.Sub(time.Unix(0, 0)) < 2*time.Second {
+		t.Errorf("token limit should have delayed the requests, elapsed %v", clock.Sub(time.Unix(0, 0)))
+	}
+}
+
+func TestRateLimitedEmbedder_ShrinksBatchOn429WithoutDropping(t *testing.T) {
+	clock := time.Unix(0, 0)
+	inner := &recordingEmbedder{dim: 4, maxOK: 2}
+	emb := NewRateLimitedEmbedder(inner, RateLimit{})
+	fakeClock(emb, &clock)
+
+	texts := []string{"a", "b", "c", "d", "e", "f", "g"}
+	vecs, err := emb.Embed(context.Background(), texts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vecs) != len(texts) {
+		t.Fatalf("expected %d vectors, got %d", len(texts), len(vecs))
+	}
+	var embedded []string
+	for _, b := range inner.batches {
+		if len(b) <= inner.maxOK {
+			embedded = append(embedded, b...)
+		}
+	}
+	if strings.Join(embedded, "") != "abcdefg" {
+		t.Errorf("documents embedded out of order or dropped: %v", inner.batches)
+	}
+	stats := emb.ThrottleStats()
+	if stats.Shrinks == 0 {
+		t.Error("expected the batch size to shrink after a 429")
+	}
+	// 7 -> 429, 3 -> 429: backoffs of 500ms then 1s before the retries.
+	if stats.Waits != 2 || clock.Sub(time.Unix(0, 0)) != 1500*time.Millisecond {
+		t.Errorf("expected exponential backoff after each 429, got %+v after %v", stats, clock.Sub(time.Unix(0, 0)))
+	}
+}
+
+func TestSyncVectorIndex_ReportsThrottleWaits(t *testing.T) {
+	clock := time.Unix(0, 0)
+	emb := NewRateLimitedEmbedder(NewHashEmbedder(8), RateLimit{RequestsPerMinute: 60})
+	fakeClock(emb, &clock)
+	emb.requests.last = clock
+
+	docs := map[string]string{"a": "alpha", "b": "beta", "c": "gamma"}
+	stats, err := SyncVectorIndex(context.Background(), NewVectorIndex(8), emb, docs, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.ThrottleWaits != 2 || stats.ThrottleWaitMs < 1900 {
+		t.Errorf("expected 2 throttle waits of ~1s each, got %+v", stats)
+	}
+}
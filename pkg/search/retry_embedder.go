@@ -0,0 +1,234 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Retry defaults for network embedders; see RetryPolicy.
+const (
+	DefaultEmbedRetryAttempts    = 3
+	DefaultEmbedRetryBaseDelay   = 500 * time.Millisecond
+	DefaultEmbedRetryMaxDelay    = 10 * time.Second
+	DefaultEmbedRetryJitter      = 0.2
+	DefaultEmbedBreakerThreshold = 3
+	DefaultEmbedFallbackTTL      = 10 * time.Minute
+)
+
+// RetryPolicy configures RetryingEmbedder. Zero fields use the defaults above.
+type RetryPolicy struct {
+	Attempts  int           // Tries per Embed call, including the first
+	BaseDelay time.Duration // Delay before the first retry; doubles on each further retry
+	MaxDelay  time.Duration // Upper bound for a single delay
+	Jitter    float64       // Fraction (0-1) of each delay that is randomized
+
+	// BreakerThreshold consecutive calls that exhaust their retries trip the
+	// circuit breaker; the fallback embedder then serves every call for
+	// FallbackTTL before the primary is tried again.
+	BreakerThreshold int
+	FallbackTTL      time.Duration
+}
+
+// Normalized fills unset fields with their defaults.
+func (p RetryPolicy) Normalized() RetryPolicy {
+	if p.Attempts <= 0 {
+		p.Attempts = DefaultEmbedRetryAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = DefaultEmbedRetryBaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = DefaultEmbedRetryMaxDelay
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		p.Jitter = DefaultEmbedRetryJitter
+	}
+	if p.BreakerThreshold <= 0 {
+		p.BreakerThreshold = DefaultEmbedBreakerThreshold
+	}
+	if p.FallbackTTL <= 0 {
+		p.FallbackTTL = DefaultEmbedFallbackTTL
+	}
+	return p
+}
+
+// delay returns the backoff before retry number n (1-based).
+func (p RetryPolicy) delay(n int) time.Duration {
+	d := p.BaseDelay << (n - 1)
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		spread := float64(d) * p.Jitter
+		d = time.Duration(float64(d) - spread + rand.Float64()*2*spread)
+	}
+	return d
+}
+
+// HTTPStatusError reports a non-2xx response from an embedding API, so the
+// retry logic can tell throttling and outages from bad requests and auth
+// failures.
+type HTTPStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPStatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("embedding API returned %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("embedding API returned %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+}
+
+type retryableError struct{ err error }
+
+func (e retryableError) Error() string { return e.err.Error() }
+func (e retryableError) Unwrap() error { return e.err }
+
+// MarkRetryable wraps err so IsRetryableEmbedError treats it as transient.
+func MarkRetryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return retryableError{err}
+}
+
+// IsRetryableEmbedError classifies an embedding failure. Throttling (429),
+// timeouts (408), server errors (5xx), network errors and truncated responses
+// are retryable; other HTTP statuses such as 400, 401 and 403 are fatal, as
+// are cancellation and unknown errors.
+func IsRetryableEmbedError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var marked retryableError
+	if errors.As(err, &marked) {
+		return true
+	}
+	var status *HTTPStatusError
+	if errors.As(err, &status) {
+		switch {
+		case status.StatusCode == http.StatusRequestTimeout,
+			status.StatusCode == http.StatusTooEarly,
+			status.StatusCode == http.StatusTooManyRequests,
+			status.StatusCode >= 500:
+			return true
+		default:
+			return false
+		}
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// RetryingEmbedder wraps a network-backed embedder with retries, exponential
+// backoff with jitter, and a circuit breaker that switches to a local
+// fallback after repeated transient failures. Fatal errors (e.g. bad
+// credentials) are returned immediately and never trip the breaker.
+//
+// Vectors from the fallback are not comparable with the primary's; callers
+// that persist embeddings should check Degraded before saving.
+type RetryingEmbedder struct {
+	primary  Embedder
+	fallback Embedder
+	policy   RetryPolicy
+
+	mu           sync.Mutex
+	failures     int       // Consecutive calls that exhausted their retries
+	trippedUntil time.Time // Fallback serves calls until then
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewRetryingEmbedder wraps primary. A nil fallback uses a HashEmbedder of
+// the primary's dimension.
+func NewRetryingEmbedder(primary, fallback Embedder, policy RetryPolicy) *RetryingEmbedder {
+	if fallback == nil {
+		fallback = NewHashEmbedder(primary.Dim())
+	}
+	return &RetryingEmbedder{
+		primary:  primary,
+		fallback: fallback,
+		policy:   policy.Normalized(),
+		now:      time.Now,
+		sleep:    sleepContext,
+	}
+}
+
+func (r *RetryingEmbedder) Provider() Provider { return r.primary.Provider() }
+func (r *RetryingEmbedder) Dim() int           { return r.primary.Dim() }
+
+// ThrottleStats forwards the primary's rate-limiting stats, if it has any.
+func (r *RetryingEmbedder) ThrottleStats() ThrottleStats {
+	if reporter, ok := r.primary.(ThrottleReporter); ok {
+		return reporter.ThrottleStats()
+	}
+	return ThrottleStats{}
+}
+
+// Degraded reports whether the breaker is open and calls go to the fallback.
+func (r *RetryingEmbedder) Degraded() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.now().Before(r.trippedUntil)
+}
+
+func (r *RetryingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if r.Degraded() {
+		return r.fallback.Embed(ctx, texts)
+	}
+
+	var err error
+	for attempt := 1; attempt <= r.policy.Attempts; attempt++ {
+		var vecs [][]float32
+		if vecs, err = r.primary.Embed(ctx, texts); err == nil {
+			r.mu.Lock()
+			r.failures = 0
+			r.mu.Unlock()
+			return vecs, nil
+		}
+		if !IsRetryableEmbedError(err) || ctx.Err() != nil {
+			return nil, err
+		}
+		if attempt < r.policy.Attempts {
+			if serr := r.sleep(ctx, r.policy.delay(attempt)); serr != nil {
+				return nil, serr
+			}
+		}
+	}
+
+	r.mu.Lock()
+	r.failures++
+	tripped := r.failures >= r.policy.BreakerThreshold
+	if tripped {
+		r.failures = 0
+		r.trippedUntil = r.now().Add(r.policy.FallbackTTL)
+	}
+	r.mu.Unlock()
+	if tripped {
+		return r.fallback.Embed(ctx, texts)
+	}
+	return nil, fmt.Errorf("embedding failed after %d attempts: %w", r.policy.Attempts, err)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,212 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// httpStubEmbedder posts texts to a test server and decodes {"vectors": [...]}.
+type httpStubEmbedder struct {
+	url string
+	dim int
+}
+
+func (e httpStubEmbedder) Provider() Provider { return Provider("stub") }
+func (e httpStubEmbedder) Dim() int           { return e.dim }
+
+func (e httpStubEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &HTTPStatusError{StatusCode: resp.StatusCode}
+	}
+	var body struct {
+		Vectors [][]float32 `json:"vectors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return body.Vectors, nil
+}
+
+func flakyEmbedServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1)) - 1
+		status := http.StatusOK
+		if n < len(statuses) {
+			status = statuses[n]
+		} else if len(statuses) > 0 && statuses[len(statuses)-1] != http.StatusOK {
+			status = statuses[len(statuses)-1]
+		}
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"vectors": [][]float32{{1, 0, 0, 0}}})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+var fastRetryPolicy = RetryPolicy{Attempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, BreakerThreshold: 2, FallbackTTL: time.Hour}
+
+func TestRetryingEmbedder_SucceedsOnSecondAttempt(t *testing.T) {
+	srv, calls := flakyEmbedServer(t, http.StatusServiceUnavailable, http.StatusOK)
+	emb := NewRetryingEmbedder(httpStubEmbedder{url: srv.URL, dim: 4}, nil, fastRetryPolicy)
+
+	vecs, err := emb.Embed(context.Background(), []string{"hello"})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if len(vecs) != 1 || vecs[0][0] != 1 {
+		t.Fatalf("unexpected vectors: %v", vecs)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected 2 requests, got %d", got)
+	}
+	if emb.Degraded() {
+		t.Error("a recovered call should not trip the breaker")
+	}
+}
+
+func TestRetryingEmbedder_AuthErrorsAreNotRetried(t *testing.T) {
+	srv, calls := flakyEmbedServer(t, http.StatusUnauthorized)
+	emb := NewRetryingEmbedder(httpStubEmbedder{url: srv.URL, dim: 4}, nil, fastRetryPolicy)
+
+	for i := 0; i < 3; i++ {
+		if _, err := emb.Embed(context.Background(), []string{"hello"}); err == nil {
+			t.Fatal("expected an auth error")
+		}
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("expected one request per call, got %d", got)
+	}
+	if emb.Degraded() {
+		t.Error("fatal errors must not fall back silently")
+	}
+}
+
+func TestRetryingEmbedder_BreakerTripsToFallback(t *testing.T) {
+	srv, calls := flakyEmbedServer(t, http.StatusServiceUnavailable)
+	emb := NewRetryingEmbedder(httpStubEmbedder{url: srv.URL, dim: 4}, nil, fastRetryPolicy)
+	now := time.Now()
+	emb.now = func() time.Time { return now }
+
+	if _, err := emb.Embed(context.Background(), []string{"hello"}); err == nil {
+		t.Fatal("first exhausted call should surface the error")
+	}
+	vecs, err := emb.Embed(context.Background(), []string{"hello"})
+	if err != nil {
+		t.Fatalf("tripping call should be served by the fallback: %v", err)
+	}
+	if len(vecs) != 1 || len(vecs[0]) != 4 {
+		t.Fatalf("unexpected fallback vectors: %v", vecs)
+	}
+	if !emb.Degraded() {
+		t.Fatal("breaker should be open")
+	}
+
+	before := calls.Load()
+	if _, err := emb.Embed(context.Background(), []string{"again"}); err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != before {
+		t.Error("open breaker should not contact the primary")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if emb.Degraded() {
+		t.Error("breaker should close after the fallback TTL")
+	}
+}
+
+func TestIsRetryableEmbedError(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{&HTTPStatusError{StatusCode: 429}, true},
+		{&HTTPStatusError{StatusCode: 502}, true},
+		{&HTTPStatusError{StatusCode: 401}, false},
+		{&HTTPStatusError{StatusCode: 400}, false},
+		{fmt.Errorf("wrapped: %w", &HTTPStatusError{StatusCode: 503}), true},
+		{context.Canceled, false},
+		{MarkRetryable(fmt.Errorf("flaky")), true},
+		{fmt.Errorf("unknown"), false},
+	}
+	for _, tc := range cases {
+		if got := IsRetryableEmbedError(tc.err); got != tc.want {
+			t.Errorf("IsRetryableEmbedError(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestNewEmbedderFromConfig_WrapsNetworkProviders(t *testing.T) {
+	srv, _ := flakyEmbedServer(t, http.StatusOK)
+	orig := newNetworkEmbedder
+	newNetworkEmbedder = func(cfg EmbeddingConfig) (Embedder, error) {
+		return httpStubEmbedder{url: srv.URL, dim: cfg.Dim}, nil
+	}
+	t.Cleanup(func() { newNetworkEmbedder = orig })
+
+	emb, err := NewEmbedderFromConfig(EmbeddingConfig{Provider: ProviderOpenAI, Dim: 4})
+	if err != nil {
+		t.Fatalf("NewEmbedderFromConfig: %v", err)
+	}
+	if _, ok := emb.(*RetryingEmbedder); !ok {
+		t.Fatalf("network embedder should be wrapped in RetryingEmbedder, got %T", emb)
+	}
+	hash, err := NewEmbedderFromConfig(EmbeddingConfig{Provider: ProviderHash, Dim: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := hash.(*HashEmbedder); !ok {
+		t.Errorf("hash embedder should not be wrapped, got %T", hash)
+	}
+}
+
+func TestSyncVectorIndex_RefusesDegradedEmbedder(t *testing.T) {
+	srv, _ := flakyEmbedServer(t, http.StatusServiceUnavailable)
+	policy := fastRetryPolicy
+	policy.BreakerThreshold = 1
+	emb := NewRetryingEmbedder(httpStubEmbedder{url: srv.URL, dim: 4}, nil, policy)
+
+	idx := NewVectorIndex(4)
+	_, err := SyncVectorIndexWithOptions(context.Background(), idx, emb, map[string]string{"a": "hello"}, IndexSyncOptions{})
+	if err != ErrEmbedderDegraded {
+		t.Fatalf("expected ErrEmbedderDegraded, got %v", err)
+	}
+	if idx.Size() != 0 {
+		t.Errorf("fallback vectors must not be stored, index has %d entries", idx.Size())
+	}
+}
+
+func TestWrapNetworkEmbedder_AppliesRateLimitFromEnv(t *testing.T) {
+	t.Setenv(EnvSemanticRequestsPerMinute, "60")
+	emb, ok := wrapNetworkEmbedder(NewHashEmbedder(4)).(*RetryingEmbedder)
+	if !ok {
+		t.Fatal("expected a RetryingEmbedder")
+	}
+	limited, ok := emb.primary.(*RateLimitedEmbedder)
+	if !ok {
+		t.Fatalf("expected the primary to be rate limited, got %T", emb.primary)
+	}
+	if limited.requests == nil || limited.requests.rate != 1 {
+		t.Errorf("BV_SEMANTIC_RPM=60 should allow one request per second, got %+v", limited.requests)
+	}
+}
@@ -13,11 +13,15 @@ import (
 	"runtime"
 	"sort"
 	"sync"
+	"time"
 )
 
 const (
-	vectorIndexMagic   = "BVVI"
-	vectorIndexVersion = uint16(1)
+	vectorIndexMagic = "BVVI"
+	// Version 2 adds the model id, build time and per-entry embed times.
+	// Version 1 files still load; their times come from the file's mtime.
+	vectorIndexVersion   = uint16(2)
+	vectorIndexVersionV1 = uint16(1)
 )
 
 type ContentHash [32]byte
@@ -46,10 +50,16 @@ func ParseContentHashHex(s string) (ContentHash, error) {
 type VectorEntry struct {
 	ContentHash ContentHash
 	Vector      []float32
+	EmbeddedAt  time.Time
 }
 
 type VectorIndex struct {
 	Dim int
+	// ModelID identifies the embedder that produced the vectors (see EmbeddingConfig.ModelID);
+	// empty for indexes written before it was recorded.
+	ModelID string
+	// BuiltAt is when the index was created or last fully re-embedded.
+	BuiltAt time.Time
 
 	mu       sync.RWMutex
 	entries  map[string]VectorEntry
@@ -63,6 +73,7 @@ func NewVectorIndex(dim int) *VectorIndex {
 	}
 	return &VectorIndex{
 		Dim:      dim,
+		BuiltAt:  time.Now().UTC(),
 		entries:  make(map[string]VectorEntry),
 		idsDirty: true,
 	}
@@ -89,7 +100,7 @@ func LoadVectorIndex(path string) (*VectorIndex, error) {
 	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
 		return nil, fmt.Errorf("read version: %w", err)
 	}
-	if version != vectorIndexVersion {
+	if version != vectorIndexVersion && version != vectorIndexVersionV1 {
 		return nil, fmt.Errorf("unsupported version %d", version)
 	}
 
@@ -112,6 +123,28 @@ func LoadVectorIndex(path string) (*VectorIndex, error) {
 	}
 
 	idx := NewVectorIndex(int(dimU32))
+	if version == vectorIndexVersionV1 {
+		info, err := f.Stat()
+		if err != nil {
+			return nil, fmt.Errorf("stat index: %w", err)
+		}
+		idx.BuiltAt = info.ModTime().UTC()
+	} else {
+		var modelLen uint16
+		if err := binary.Read(r, binary.LittleEndian, &modelLen); err != nil {
+			return nil, fmt.Errorf("read model id len: %w", err)
+		}
+		modelID := make([]byte, modelLen)
+		if _, err := io.ReadFull(r, modelID); err != nil {
+			return nil, fmt.Errorf("read model id: %w", err)
+		}
+		idx.ModelID = string(modelID)
+		var builtAt int64
+		if err := binary.Read(r, binary.LittleEndian, &builtAt); err != nil {
+			return nil, fmt.Errorf("read build time: %w", err)
+		}
+		idx.BuiltAt = time.Unix(0, builtAt).UTC()
+	}
 	for i := uint32(0); i < count; i++ {
 		var idLen uint16
 		if err := binary.Read(r, binary.LittleEndian, &idLen); err != nil {
@@ -132,6 +165,15 @@ func LoadVectorIndex(path string) (*VectorIndex, error) {
 			return nil, fmt.Errorf("read content hash: %w", err)
 		}
 
+		embeddedAt := idx.BuiltAt
+		if version != vectorIndexVersionV1 {
+			var at int64
+			if err := binary.Read(r, binary.LittleEndian, &at); err != nil {
+				return nil, fmt.Errorf("read embed time: %w", err)
+			}
+			embeddedAt = time.Unix(0, at).UTC()
+		}
+
 		vec := make([]float32, idx.Dim)
 		for j := 0; j < idx.Dim; j++ {
 			var bits uint32
@@ -141,7 +183,7 @@ func LoadVectorIndex(path string) (*VectorIndex, error) {
 			vec[j] = math.Float32frombits(bits)
 		}
 
-		if err := idx.Upsert(issueID, ch, vec); err != nil {
+		if err := idx.upsertAt(issueID, ch, vec, embeddedAt); err != nil {
 			return nil, err
 		}
 	}
@@ -189,6 +231,18 @@ func (idx *VectorIndex) Save(path string) error {
 	if err := binary.Write(w, binary.LittleEndian, uint32(len(ids))); err != nil {
 		return fmt.Errorf("write count: %w", err)
 	}
+	if len(idx.ModelID) > math.MaxUint16 {
+		return fmt.Errorf("model id too long: %d", len(idx.ModelID))
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint16(len(idx.ModelID))); err != nil {
+		return fmt.Errorf("write model id len: %w", err)
+	}
+	if _, err := w.WriteString(idx.ModelID); err != nil {
+		return fmt.Errorf("write model id: %w", err)
+	}
+	if err := binary.Write(w, binary.LittleEndian, idx.BuiltAt.UnixNano()); err != nil {
+		return fmt.Errorf("write build time: %w", err)
+	}
 
 	for _, issueID := range ids {
 		entry, ok := idx.entries[issueID]
@@ -208,6 +262,9 @@ func (idx *VectorIndex) Save(path string) error {
 		if _, err := w.Write(entry.ContentHash[:]); err != nil {
 			return fmt.Errorf("write content hash: %w", err)
 		}
+		if err := binary.Write(w, binary.LittleEndian, entry.EmbeddedAt.UnixNano()); err != nil {
+			return fmt.Errorf("write embed time: %w", err)
+		}
 		if len(entry.Vector) != idx.Dim {
 			return fmt.Errorf("vector dim mismatch for %s: %d != %d", issueID, len(entry.Vector), idx.Dim)
 		}
@@ -245,7 +302,12 @@ func (idx *VectorIndex) Save(path string) error {
 	return nil
 }
 
+// Upsert stores the vector for issueID, stamped as embedded now.
 func (idx *VectorIndex) Upsert(issueID string, hash ContentHash, vec []float32) error {
+	return idx.upsertAt(issueID, hash, vec, time.Now().UTC())
+}
+
+func (idx *VectorIndex) upsertAt(issueID string, hash ContentHash, vec []float32, embeddedAt time.Time) error {
 	if issueID == "" {
 		return fmt.Errorf("issue id cannot be empty")
 	}
@@ -262,6 +324,7 @@ func (idx *VectorIndex) Upsert(issueID string, hash ContentHash, vec []float32)
 	idx.entries[issueID] = VectorEntry{
 		ContentHash: hash,
 		Vector:      cp,
+		EmbeddedAt:  embeddedAt,
 	}
 	if !exists {
 		idx.idsDirty = true
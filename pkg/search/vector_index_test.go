@@ -1,8 +1,13 @@
 package search
 
 import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestVectorIndex_SaveLoad_RoundTrip(t *testing.T) {
@@ -91,3 +96,63 @@ func TestContentHash_HexRoundTrip(t *testing.T) {
 		t.Fatalf("Hash round-trip mismatch")
 	}
 }
+
+func TestVectorIndex_SaveLoad_KeepsModelAndTimes(t *testing.T) {
+	idx := NewVectorIndex(2)
+	idx.ModelID = "hash@2"
+	idx.BuiltAt = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	embedded := time.Date(2025, 2, 3, 4, 5, 6, 0, time.UTC)
+	if err := idx.upsertAt("A", ComputeContentHash("a"), []float32{1, 0}, embedded); err != nil {
+		t.Fatalf("upsertAt failed: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "index.bvvi")
+	if err := idx.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := LoadVectorIndex(path)
+	if err != nil {
+		t.Fatalf("LoadVectorIndex failed: %v", err)
+	}
+	if loaded.ModelID != "hash@2" || !loaded.BuiltAt.Equal(idx.BuiltAt) {
+		t.Fatalf("model/build time not kept: %q %v", loaded.ModelID, loaded.BuiltAt)
+	}
+	if a, _ := loaded.Get("A"); !a.EmbeddedAt.Equal(embedded) {
+		t.Fatalf("EmbeddedAt = %v, want %v", a.EmbeddedAt, embedded)
+	}
+}
+
+func TestVectorIndex_LoadsVersion1(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString(vectorIndexMagic)
+	_ = binary.Write(&buf, binary.LittleEndian, vectorIndexVersionV1)
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(0))
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(2)) // dim
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(1)) // count
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(1))
+	buf.WriteString("A")
+	ch := ComputeContentHash("a")
+	buf.Write(ch[:])
+	_ = binary.Write(&buf, binary.LittleEndian, math.Float32bits(1))
+	_ = binary.Write(&buf, binary.LittleEndian, math.Float32bits(0))
+
+	path := filepath.Join(t.TempDir(), "v1.bvvi")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err := LoadVectorIndex(path)
+	if err != nil {
+		t.Fatalf("LoadVectorIndex(v1) failed: %v", err)
+	}
+	if idx.ModelID != "" || !idx.BuiltAt.Equal(mtime) {
+		t.Fatalf("v1 index: model %q built %v, want empty model and mtime", idx.ModelID, idx.BuiltAt)
+	}
+	if a, ok := idx.Get("A"); !ok || !a.EmbeddedAt.Equal(mtime) || a.Vector[0] != 1 {
+		t.Fatalf("v1 entry not loaded correctly: %+v", a)
+	}
+}
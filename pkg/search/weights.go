@@ -2,8 +2,9 @@ package search
 
 import (
 	"fmt"
-	"log"
 	"math"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/logging"
 )
 
 const weightSumTolerance = 0.001
@@ -25,26 +26,95 @@ type Weights struct {
 	Recency       float64 `json:"recency"`  // Temporal decay
 }
 
+// WeightValidationReason identifies why a weight configuration was rejected.
+type WeightValidationReason string
+
+const (
+	// WeightReasonNegative means a single component was below zero.
+	WeightReasonNegative WeightValidationReason = "negative"
+	// WeightReasonOutOfRange means a single component was above 1.0.
+	WeightReasonOutOfRange WeightValidationReason = "out_of_range"
+	// WeightReasonSumOutOfTolerance means the components do not sum to ~1.0.
+	WeightReasonSumOutOfTolerance WeightValidationReason = "sum_out_of_tolerance"
+	// WeightReasonNotFinite means a single component was NaN or infinite.
+	WeightReasonNotFinite WeightValidationReason = "not_finite"
+	// WeightReasonAllZero means every component was zero, so there is nothing
+	// to normalize.
+	WeightReasonAllZero WeightValidationReason = "all_zero"
+)
+
+// WeightSumField is the Field value reported when the total, rather than a
+// single component, is invalid.
+const WeightSumField = "sum"
+
+// WeightValidationError describes an invalid weight configuration.
+// Field uses the JSON component key (text, pagerank, status, impact, priority,
+// recency) or WeightSumField.
+type WeightValidationError struct {
+	Field  string
+	Value  float64
+	Reason WeightValidationReason
+}
+
+func (e *WeightValidationError) Error() string {
+	switch e.Reason {
+	case WeightReasonNegative:
+		return fmt.Sprintf("weight %q must be non-negative, got %.3f", e.Field, e.Value)
+	case WeightReasonOutOfRange:
+		return fmt.Sprintf("weight %q must be at most 1.0, got %.3f", e.Field, e.Value)
+	case WeightReasonSumOutOfTolerance:
+		return fmt.Sprintf("weights must sum to 1.0, got %.3f", e.Value)
+	case WeightReasonNotFinite:
+		return fmt.Sprintf("weight %q must be a finite number, got %v", e.Field, e.Value)
+	case WeightReasonAllZero:
+		return "weights are all zero; set at least one component above 0"
+	default:
+		return fmt.Sprintf("invalid weight %q: %.3f", e.Field, e.Value)
+	}
+}
+
 // Validate checks that weights are valid (non-negative, sum to ~1.0).
+// Failures are reported as *WeightValidationError.
 // It logs a warning when text relevance is very low.
 func (w Weights) Validate() error {
-	if w.TextRelevance < 0 || w.PageRank < 0 || w.Status < 0 ||
-		w.Impact < 0 || w.Priority < 0 || w.Recency < 0 {
-		return fmt.Errorf("weights must be non-negative")
+	for _, c := range w.components() {
+		if math.IsNaN(c.value) || math.IsInf(c.value, 0) {
+			return &WeightValidationError{Field: c.key, Value: c.value, Reason: WeightReasonNotFinite}
+		}
+		if c.value < 0 {
+			return &WeightValidationError{Field: c.key, Value: c.value, Reason: WeightReasonNegative}
+		}
 	}
 
 	if w.TextRelevance < 0.1 {
-		log.Printf("WARNING: text weight %.2f is very low; results may not match query", w.TextRelevance)
+		logging.Warn("text weight is very low; results may not match query", "text_weight", w.TextRelevance)
 	}
 
 	sum := w.sum()
 	if math.Abs(sum-1.0) > weightSumTolerance {
-		return fmt.Errorf("weights must sum to 1.0, got %.3f", sum)
+		return &WeightValidationError{Field: WeightSumField, Value: sum, Reason: WeightReasonSumOutOfTolerance}
 	}
 
 	return nil
 }
 
+type weightComponent struct {
+	key   string
+	value float64
+}
+
+// components returns the weights in canonical order, keyed by their JSON names.
+func (w Weights) components() []weightComponent {
+	return []weightComponent{
+		{"text", w.TextRelevance},
+		{"pagerank", w.PageRank},
+		{"status", w.Status},
+		{"impact", w.Impact},
+		{"priority", w.Priority},
+		{"recency", w.Recency},
+	}
+}
+
 // Normalize scales weights to sum to 1.0.
 func (w Weights) Normalize() Weights {
 	sum := w.sum()
@@ -2,10 +2,12 @@ package search
 
 import (
 	"bytes"
-	"log"
+	"errors"
 	"math"
 	"strings"
 	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/logging"
 )
 
 func TestWeightsValidate_Presets(t *testing.T) {
@@ -29,9 +31,17 @@ func TestWeightsValidate_Negative(t *testing.T) {
 		Priority:      0.2,
 		Recency:       0.1,
 	}
-	if err := weights.Validate(); err == nil {
+	err := weights.Validate()
+	if err == nil {
 		t.Fatal("expected error for negative weights")
 	}
+	var wErr *WeightValidationError
+	if !errors.As(err, &wErr) {
+		t.Fatalf("expected *WeightValidationError, got %T", err)
+	}
+	if wErr.Field != "text" || wErr.Reason != WeightReasonNegative || wErr.Value != -0.1 {
+		t.Fatalf("unexpected validation error: %+v", wErr)
+	}
 }
 
 func TestWeightsValidate_SumTolerance(t *testing.T) {
@@ -43,35 +53,68 @@ func TestWeightsValidate_SumTolerance(t *testing.T) {
 		Priority:      0.2,
 		Recency:       0.2,
 	}
-	if err := weights.Validate(); err == nil {
+	err := weights.Validate()
+	if err == nil {
 		t.Fatal("expected error for weights summing above tolerance")
 	}
+	var wErr *WeightValidationError
+	if !errors.As(err, &wErr) {
+		t.Fatalf("expected *WeightValidationError, got %T", err)
+	}
+	if wErr.Field != WeightSumField || wErr.Reason != WeightReasonSumOutOfTolerance {
+		t.Fatalf("unexpected validation error: %+v", wErr)
+	}
+	if math.Abs(wErr.Value-1.2) > 1e-9 {
+		t.Fatalf("expected sum 1.2 in error, got %f", wErr.Value)
+	}
+	if !strings.Contains(err.Error(), "weights must sum to 1.0, got 1.200") {
+		t.Fatalf("unexpected message: %q", err.Error())
+	}
 }
 
-func TestWeightsValidate_LowTextWarns(t *testing.T) {
+// captureLogs routes the default logger, configured from BV_LOG_LEVEL=level,
+// into a buffer for the rest of the test.
+func captureLogs(t *testing.T, level string) *bytes.Buffer {
+	t.Helper()
+	t.Setenv(logging.LevelEnvVar, level)
+	t.Setenv(logging.FormatEnvVar, "")
 	var buf bytes.Buffer
-	orig := log.Writer()
-	log.SetOutput(&buf)
+	prev := logging.SetDefault(logging.FromEnv(&buf))
 	t.Cleanup(func() {
-		log.SetOutput(orig)
+		logging.SetDefault(prev)
 	})
+	return &buf
+}
 
-	weights := Weights{
-		TextRelevance: 0.05,
-		PageRank:      0.20,
-		Status:        0.20,
-		Impact:        0.20,
-		Priority:      0.20,
-		Recency:       0.15,
-	}
-	if err := weights.Validate(); err != nil {
+var lowTextWeights = Weights{
+	TextRelevance: 0.05,
+	PageRank:      0.20,
+	Status:        0.20,
+	Impact:        0.20,
+	Priority:      0.20,
+	Recency:       0.15,
+}
+
+func TestWeightsValidate_LowTextWarns(t *testing.T) {
+	buf := captureLogs(t, "")
+	if err := lowTextWeights.Validate(); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(buf.String(), "WARNING: text weight") {
+	if !strings.Contains(buf.String(), "Warning: text weight is very low") || !strings.Contains(buf.String(), "text_weight=0.05") {
 		t.Fatalf("expected warning log for low text weight, got %q", buf.String())
 	}
 }
 
+func TestWeightsValidate_LowTextWarningSuppressedAtErrorLevel(t *testing.T) {
+	buf := captureLogs(t, "error")
+	if err := lowTextWeights.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("BV_LOG_LEVEL=error should suppress the low text weight warning, got %q", buf.String())
+	}
+}
+
 func TestWeightsNormalize(t *testing.T) {
 	weights := Weights{
 		TextRelevance: 1,
@@ -7,7 +7,6 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
-	"log"
 	"os"
 	"runtime"
 	"runtime/debug"
@@ -21,6 +20,7 @@ import (
 
 	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/logging"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/watcher"
@@ -229,6 +229,10 @@ type BackgroundWorker struct {
 	loopCtx    context.Context
 	loopCancel context.CancelFunc
 	done       chan struct{}
+
+	// analysisCancel aborts the in-flight Phase 2 analysis when a newer
+	// snapshot starts (guarded by mu).
+	analysisCancel context.CancelFunc
 }
 
 type IdleGCConfig struct {
@@ -468,12 +472,13 @@ func (w *BackgroundWorker) logEvent(level WorkerLogLevel, event string, fields m
 	}
 	b, err := json.Marshal(payload)
 	if err != nil {
-		log.Printf("background worker: failed to marshal log event %s: %v", event, err)
+		logging.Error("background worker: failed to marshal log event", "event", event, "error", err)
 		return
 	}
 
+	// BV_WORKER_LOG_LEVEL gates these on its own; the lines are already JSON
 	if w.logLevel != LogLevelNone && level <= w.logLevel {
-		log.Printf("%s", b)
+		fmt.Fprintln(os.Stderr, string(b))
 	}
 	if w.traceFile != nil {
 		w.traceMu.Lock()
@@ -1260,6 +1265,7 @@ func (w *BackgroundWorker) buildSnapshot() *DataSnapshot {
 	var issues []model.Issue
 	var pooledRefs []*model.Issue
 	var loadWarnings []string
+	var duplicateIDs []loader.DuplicateID
 	loadErr := w.safeCompute("load", func() error {
 		var err error
 		var loaded loader.PooledIssues
@@ -1267,6 +1273,9 @@ func (w *BackgroundWorker) buildSnapshot() *DataSnapshot {
 			WarningHandler: func(msg string) {
 				loadWarnings = append(loadWarnings, msg)
 			},
+			DuplicateHandler: func(dups []loader.DuplicateID) {
+				duplicateIDs = dups
+			},
 			BufferSize: envMaxLineSizeBytes(),
 		})
 		if err == nil {
@@ -1323,11 +1332,21 @@ func (w *BackgroundWorker) buildSnapshot() *DataSnapshot {
 		return nil
 	}
 
+	// A newer snapshot supersedes any Phase 2 analysis still running for the
+	// previous one, so cancel it rather than letting it compete for CPU.
+	analysisCtx, analysisCancel := context.WithCancel(w.ctx)
+	w.mu.Lock()
+	if w.analysisCancel != nil {
+		w.analysisCancel()
+	}
+	w.analysisCancel = analysisCancel
+	w.mu.Unlock()
+
 	// Build snapshot (includes Phase 1 analysis) with panic recovery
 	var snapshot *DataSnapshot
 	analyzeStart := time.Now()
 	analyzeErr := w.safeCompute("analyze_phase1", func() error {
-		builder := NewSnapshotBuilder(issues).WithRecipe(currentRecipe)
+		builder := NewSnapshotBuilder(issues).WithRecipe(currentRecipe).WithContext(analysisCtx)
 		snapshot = builder.Build()
 		return nil
 	})
@@ -1364,6 +1383,7 @@ func (w *BackgroundWorker) buildSnapshot() *DataSnapshot {
 	if snapshot != nil {
 		snapshot.DataHash = hash
 		snapshot.LoadWarningCount = len(loadWarnings)
+		snapshot.DuplicateIDs = duplicateIDs
 		snapshot.RecipeName = recipeID
 		snapshot.RecipeHash = recipeHash
 		snapshot.pooledIssues = pooledRefs
@@ -1483,6 +1503,13 @@ func (w *BackgroundWorker) runPhase2Analysis(stats *analysis.GraphStats, dataHas
 		w.metrics.lastPhase2Ns.Store(phase2Duration.Nanoseconds())
 	}
 
+	if stats.Cancelled() {
+		w.logEvent(LogLevelDebug, "phase2_cancelled", map[string]any{
+			"hash": hashPrefix(dataHash),
+		})
+		return
+	}
+
 	// Check if this Phase 2 completion still corresponds to the active snapshot.
 	w.mu.RLock()
 	stopped := w.state == WorkerStopped
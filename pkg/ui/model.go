@@ -343,6 +343,9 @@ type Model struct {
 	backgroundWorker *BackgroundWorker
 	workerSpinnerIdx int // Spinner frame for background worker activity (bv-9nfy)
 	lastForceRefresh time.Time
+	// analysisCancel aborts the in-flight Phase 2 analysis started by the
+	// legacy (non-worker) reload path when the next reload begins.
+	analysisCancel context.CancelFunc
 
 	// UI Components
 	list               list.Model
@@ -407,6 +410,7 @@ type Model struct {
 	sortMode               SortMode // bv-3ita: current sort mode
 	semanticSearchEnabled  bool
 	semanticIndexBuilding  bool
+	semanticIndexBuild     *SemanticIndexBuild // In-flight background build, canceled when search is switched off
 	semanticSearch         *SemanticSearch
 	semanticHybridEnabled  bool
 	semanticHybridPreset   search.PresetName
@@ -1143,14 +1147,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.labelDashboard.SetSize(m.width, m.height-1)
 		}
 
+	case SemanticIndexProgressMsg:
+		if msg.build == nil || msg.build != m.semanticIndexBuild {
+			break // Canceled or superseded build
+		}
+		m.statusMsg = semanticIndexProgressText(msg.Done, msg.Total)
+		m.statusIsError = false
+		cmds = append(cmds, WaitForSemanticIndexMsgCmd(m.semanticIndexBuild))
+
 	case SemanticIndexReadyMsg:
+		if msg.build != nil && msg.build != m.semanticIndexBuild {
+			break // Canceled or superseded build
+		}
 		m.semanticIndexBuilding = false
+		m.semanticIndexBuild = nil
 		if msg.Error != nil {
 			// If indexing fails, revert to fuzzy mode for predictable behavior.
 			m.semanticSearchEnabled = false
 			m.list.Filter = list.DefaultFilter
-			m.statusMsg = fmt.Sprintf("Semantic search unavailable: %v", msg.Error)
+			m.statusMsg = fmt.Sprintf("Semantic search unavailable: %v (using fuzzy search)", msg.Error)
 			m.statusIsError = true
+			m.refreshListFilter()
 			break
 		}
 		if m.semanticSearch != nil {
@@ -1160,19 +1177,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.statusMsg = fmt.Sprintf("Semantic index built (%d embedded)", msg.Stats.Embedded)
 		} else if msg.Stats.Changed() {
 			m.statusMsg = fmt.Sprintf("Semantic index updated (+%d ~%d -%d)", msg.Stats.Added, msg.Stats.Updated, msg.Stats.Removed)
+			if msg.Stats.Reembedded > 0 {
+				m.statusMsg += fmt.Sprintf(", %d re-embedded", msg.Stats.Reembedded)
+			}
 		} else {
 			m.statusMsg = "Semantic index up to date"
 		}
 		m.statusIsError = false
 
 		// Refresh current filter view if the user is actively searching.
-		if m.semanticSearchEnabled && m.list.FilterState() != list.Unfiltered {
-			prevState := m.list.FilterState()
-			filterText := m.list.FilterInput.Value()
-			m.list.SetFilterText(filterText)
-			if prevState == list.Filtering {
-				m.list.SetFilterState(list.Filtering)
-			}
+		if m.semanticSearchEnabled {
+			m.refreshListFilter()
 		}
 
 	case HybridMetricsReadyMsg:
@@ -1669,8 +1684,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Keep semantic index current when enabled.
 		if m.semanticSearchEnabled && !m.semanticIndexBuilding {
-			m.semanticIndexBuilding = true
-			cmds = append(cmds, BuildSemanticIndexCmd(m.issuesForAsync()))
+			cmds = append(cmds, m.startSemanticIndexBuild())
 		}
 
 		// Reload sprints (bv-161)
@@ -1710,6 +1724,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.statusMsg = fmt.Sprintf("Reloaded %d issues", len(m.issues))
 		}
 		m.statusIsError = false
+		if len(msg.Snapshot.DuplicateIDs) > 0 {
+			m.statusMsg += " ⚠ duplicate IDs (last kept): " + loader.FormatDuplicateIDs(msg.Snapshot.DuplicateIDs)
+			m.statusIsError = true
+		}
 
 		// Wait for Phase 2 if not ready
 		if msg.Snapshot.Analysis != nil {
@@ -1774,10 +1792,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Reload issues from disk
 		// Use custom warning handler to prevent stderr pollution during TUI render (bv-fix)
 		var reloadWarnings []string
+		var reloadDuplicates []loader.DuplicateID
 		newIssues, err := loader.LoadIssuesFromFileWithOptions(m.beadsPath, loader.ParseOptions{
 			WarningHandler: func(msg string) {
 				reloadWarnings = append(reloadWarnings, msg)
 			},
+			DuplicateHandler: func(dups []loader.DuplicateID) {
+				reloadDuplicates = dups
+			},
 		})
 		if err != nil {
 			m.statusMsg = fmt.Sprintf("Reload error: %v", err)
@@ -1812,9 +1834,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Recompute analysis (async Phase 1/Phase 2) with caching
 		m.issues = newIssues
+		// A newer reload aborts Phase 2 still running for the previous data.
+		if m.analysisCancel != nil {
+			m.analysisCancel()
+		}
+		var analysisCtx context.Context
+		analysisCtx, m.analysisCancel = context.WithCancel(context.Background())
 		cachedAnalyzer := analysis.NewCachedAnalyzer(newIssues, nil)
 		m.analyzer = cachedAnalyzer.Analyzer
-		m.analysis = cachedAnalyzer.AnalyzeAsync(context.Background())
+		m.analysis = cachedAnalyzer.AnalyzeAsync(analysisCtx)
 		cacheHit := cachedAnalyzer.WasCacheHit()
 		m.labelHealthCached = false
 		m.attentionCached = false
@@ -1939,8 +1967,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Keep semantic index current when enabled.
 		if m.semanticSearchEnabled && !m.semanticIndexBuilding {
-			m.semanticIndexBuilding = true
-			cmds = append(cmds, BuildSemanticIndexCmd(m.issuesForAsync()))
+			cmds = append(cmds, m.startSemanticIndexBuild())
 		}
 
 		if cacheHit {
@@ -1952,6 +1979,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.statusMsg += fmt.Sprintf(" (%d warnings)", len(reloadWarnings))
 		}
 		m.statusIsError = false
+		if len(reloadDuplicates) > 0 {
+			m.statusMsg += " ⚠ duplicate IDs (last kept): " + loader.FormatDuplicateIDs(reloadDuplicates)
+			m.statusIsError = true
+		}
 		// Invalidate label-derived caches
 		m.labelHealthCached = false
 		m.labelDrilldownCache = make(map[string][]model.Issue)
@@ -2386,9 +2417,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.semanticSearch != nil {
 					m.list.Filter = m.semanticSearch.Filter
 					if !m.semanticSearch.Snapshot().Ready && !m.semanticIndexBuilding {
-						m.semanticIndexBuilding = true
 						m.statusMsg = "Semantic search: building index…"
-						cmds = append(cmds, BuildSemanticIndexCmd(m.issuesForAsync()))
+						cmds = append(cmds, m.startSemanticIndexBuild())
 					} else if !m.semanticSearch.Snapshot().Ready && m.semanticIndexBuilding {
 						m.statusMsg = "Semantic search: indexing…"
 					} else {
@@ -2408,6 +2438,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.list.Filter = list.DefaultFilter
 				m.statusMsg = "Fuzzy search enabled"
 				m.clearSemanticScores()
+				m.cancelSemanticIndexBuild()
 			}
 
 			// Refresh the current list filter results immediately.
@@ -4151,6 +4182,38 @@ func (m Model) renderLoadingScreen() string {
 	return lipgloss.Place(m.width, m.height-1, lipgloss.Center, lipgloss.Center, content)
 }
 
+// renderEmptyState is shown instead of the list when the project has no
+// beads yet, with the commands to create some.
+func (m Model) renderEmptyState() string {
+	titleStyle := lipgloss.NewStyle().Foreground(ColorText).Bold(true)
+	subStyle := lipgloss.NewStyle().Foreground(ColorMuted)
+	cmdStyle := lipgloss.NewStyle().Foreground(ColorInfo)
+
+	lines := []string{titleStyle.Render("No beads yet"), ""}
+	if m.beadsPath == "" {
+		lines = append(lines,
+			subStyle.Render("This directory has no .beads data. Start tracking work with:"),
+			"",
+			cmdStyle.Render("bd init"),
+			cmdStyle.Render(`bd create --title="First task" --type=task`),
+			"",
+			subStyle.Render("Then run bv again (or set BEADS_DIR to an existing beads directory)."),
+		)
+	} else {
+		lines = append(lines,
+			subStyle.Render("Create your first bead in another terminal:"),
+			"",
+			cmdStyle.Render(`bd create --title="First task" --type=task`),
+			"",
+			subStyle.Render("It shows up here automatically. Watching "+m.beadsPath),
+		)
+	}
+	lines = append(lines, "", subStyle.Render("? help • q quit"))
+
+	content := lipgloss.JoinVertical(lipgloss.Center, lines...)
+	return lipgloss.Place(m.width, m.height-1, lipgloss.Center, lipgloss.Center, content)
+}
+
 func (m Model) View() string {
 	if !m.ready {
 		return "Initializing..."
@@ -4222,7 +4285,9 @@ func (m Model) View() string {
 		body = m.labelDashboard.View()
 	} else {
 		// Mobile view
-		if m.showDetails {
+		if len(m.issues) == 0 && !m.workspaceMode {
+			body = m.renderEmptyState()
+		} else if m.showDetails {
 			body = m.viewport.View()
 		} else {
 			body = m.renderListWithHeader()
@@ -6511,6 +6576,16 @@ func (m *Model) EnableWorkspaceMode(info WorkspaceInfo) {
 	m.updateListDelegate()
 }
 
+// ShowLoadWarnings puts data problems found while loading (e.g. duplicate
+// bead IDs) in the status bar, since stderr is hidden behind the TUI.
+func (m *Model) ShowLoadWarnings(warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+	m.statusMsg = "⚠ " + strings.Join(warnings, "; ")
+	m.statusIsError = true
+}
+
 // IsWorkspaceMode returns whether workspace mode is active
 func (m Model) IsWorkspaceMode() bool {
 	return m.workspaceMode
@@ -7090,6 +7165,7 @@ func (m *Model) openInEditor() {
 // Stop cleans up resources (file watcher, instance lock, background worker, etc.)
 // Should be called when the program exits
 func (m *Model) Stop() {
+	m.cancelSemanticIndexBuild()
 	if m.backgroundWorker != nil {
 		m.backgroundWorker.Stop()
 	}
@@ -7101,6 +7177,53 @@ func (m *Model) Stop() {
 	}
 }
 
+// startSemanticIndexBuild starts a background semantic index build for the
+// current issues and returns the command that relays its progress.
+func (m *Model) startSemanticIndexBuild() tea.Cmd {
+	m.cancelSemanticIndexBuild()
+	m.semanticIndexBuilding = true
+	m.semanticIndexBuild = StartSemanticIndexBuild(m.issuesForAsync())
+	return WaitForSemanticIndexMsgCmd(m.semanticIndexBuild)
+}
+
+// cancelSemanticIndexBuild stops an in-flight semantic index build, if any.
+func (m *Model) cancelSemanticIndexBuild() {
+	if m.semanticIndexBuild == nil {
+		return
+	}
+	m.semanticIndexBuild.Cancel()
+	m.semanticIndexBuild = nil
+	m.semanticIndexBuilding = false
+}
+
+// refreshListFilter re-runs the list filter so results follow a filter
+// function or index change while the user is searching.
+func (m *Model) refreshListFilter() {
+	if m.list.FilterState() == list.Unfiltered {
+		return
+	}
+	prevState := m.list.FilterState()
+	m.list.SetFilterText(m.list.FilterInput.Value())
+	if prevState == list.Filtering {
+		m.list.SetFilterState(list.Filtering)
+	}
+}
+
+// semanticIndexProgressText renders a semantic index build's progress for the
+// status bar, e.g. "Semantic index [██████░░░░░░░░░░] 96/256 embedded".
+func semanticIndexProgressText(done, total int) string {
+	if total == 0 {
+		return "Semantic search: building index…"
+	}
+	const width = 16
+	filled := done * width / total
+	if filled > width {
+		filled = width
+	}
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+	return fmt.Sprintf("Semantic index [%s] %d/%d embedded", bar, done, total)
+}
+
 // clearAttentionOverlay hides the attention overlay and clears its rendered text.
 func (m *Model) clearAttentionOverlay() {
 	if m.showAttentionView {
@@ -1,6 +1,7 @@
 package ui_test
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -522,3 +523,16 @@ func keyMsg(key string) tea.KeyMsg {
 		Runes: []rune(key),
 	}
 }
+
+func TestViewEmptyProjectShowsGuidance(t *testing.T) {
+	m := ui.NewModel(nil, nil, "")
+	defer m.Stop()
+	newM, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	view := newM.(ui.Model).View()
+
+	for _, want := range []string{"No beads yet", "bd init", "bd create"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected empty state to mention %q, got:\n%s", want, view)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -427,6 +428,17 @@ type SemanticIndexReadyMsg struct {
 	Loaded    bool
 	Stats     search.IndexSyncStats
 	Error     error
+
+	build *SemanticIndexBuild // Set for background builds; lets the model drop stale results
+}
+
+// SemanticIndexProgressMsg reports how many documents a background build has
+// embedded out of those that need embedding.
+type SemanticIndexProgressMsg struct {
+	Done  int
+	Total int
+
+	build *SemanticIndexBuild
 }
 
 // SemanticFilterResultMsg is emitted when async semantic filter results are ready.
@@ -476,48 +488,120 @@ func BuildHybridMetricsCmd(issues []model.Issue) tea.Cmd {
 	}
 }
 
-// BuildSemanticIndexCmd builds or updates the semantic index for the given issues.
-func BuildSemanticIndexCmd(issues []model.Issue) tea.Cmd {
-	return func() tea.Msg {
-		cfg := search.EmbeddingConfigFromEnv()
-		embedder, err := search.NewEmbedderFromConfig(cfg)
-		if err != nil {
-			return SemanticIndexReadyMsg{Error: err}
-		}
+// semanticIndexBuildTimeout caps a background build. Builds report progress
+// and can be canceled, so the cap is generous.
+const semanticIndexBuildTimeout = 5 * time.Minute
 
-		projectDir, err := os.Getwd()
-		if err != nil {
-			return SemanticIndexReadyMsg{Error: err}
-		}
+// SemanticIndexBuild is a cancelable background index build. It delivers
+// SemanticIndexProgressMsg updates and a final SemanticIndexReadyMsg through
+// WaitForSemanticIndexMsgCmd.
+type SemanticIndexBuild struct {
+	msgs       chan tea.Msg
+	done       chan struct{} // Closed by Cancel
+	cancelOnce sync.Once
+	cancel     context.CancelFunc
+}
 
-		indexPath := search.DefaultIndexPath(projectDir, cfg)
-		idx, loaded, err := search.LoadOrNewVectorIndex(indexPath, embedder.Dim())
-		if err != nil {
-			return SemanticIndexReadyMsg{Error: err}
+// StartSemanticIndexBuild starts building the semantic index in the background.
+func StartSemanticIndexBuild(issues []model.Issue) *SemanticIndexBuild {
+	ctx, cancel := context.WithTimeout(context.Background(), semanticIndexBuildTimeout)
+	b := &SemanticIndexBuild{msgs: make(chan tea.Msg, 1), done: make(chan struct{}), cancel: cancel}
+	go func() {
+		defer cancel()
+		msg := buildSemanticIndex(ctx, issues, b.sendProgress)
+		msg.build = b
+		select {
+		case b.msgs <- msg:
+		case <-b.done:
+		}
+	}()
+	return b
+}
+
+// sendProgress queues a progress update, replacing one the model has not read
+// yet so a slow UI never stalls embedding.
+func (b *SemanticIndexBuild) sendProgress(done, total int) {
+	msg := SemanticIndexProgressMsg{Done: done, Total: total, build: b}
+	for {
+		select {
+		case b.msgs <- msg:
+			return
+		default:
 		}
+		select {
+		case <-b.msgs:
+		default:
+		}
+	}
+}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+// Cancel stops the build; no further messages are delivered.
+func (b *SemanticIndexBuild) Cancel() {
+	if b == nil {
+		return
+	}
+	b.cancelOnce.Do(func() {
+		close(b.done)
+		b.cancel()
+	})
+}
 
-		docs := search.DocumentsFromIssues(issues)
-		stats, err := search.SyncVectorIndex(ctx, idx, embedder, docs, 64)
-		if err != nil {
-			return SemanticIndexReadyMsg{Error: err}
+// WaitForSemanticIndexMsgCmd waits for the next message from a background build.
+func WaitForSemanticIndexMsgCmd(b *SemanticIndexBuild) tea.Cmd {
+	return func() tea.Msg {
+		if b == nil {
+			return nil
 		}
-		if !loaded || stats.Changed() {
-			if err := idx.Save(indexPath); err != nil {
-				return SemanticIndexReadyMsg{Error: fmt.Errorf("save semantic index: %w", err)}
-			}
+		select {
+		case msg := <-b.msgs:
+			return msg
+		case <-b.done:
+			return nil
 		}
+	}
+}
+
+func buildSemanticIndex(ctx context.Context, issues []model.Issue, progress func(done, total int)) SemanticIndexReadyMsg {
+	cfg := search.EmbeddingConfigFromEnv()
+	embedder, err := search.NewEmbedderFromConfig(cfg)
+	if err != nil {
+		return SemanticIndexReadyMsg{Error: err}
+	}
+
+	projectDir, err := os.Getwd()
+	if err != nil {
+		return SemanticIndexReadyMsg{Error: err}
+	}
+
+	indexPath := search.DefaultIndexPath(projectDir, cfg)
+	idx, loaded, err := search.LoadOrNewVectorIndex(indexPath, embedder.Dim())
+	if err != nil {
+		return SemanticIndexReadyMsg{Error: err}
+	}
 
-		return SemanticIndexReadyMsg{
-			Embedder:  embedder,
-			Index:     idx,
-			IndexPath: indexPath,
-			Loaded:    loaded,
-			Stats:     stats,
+	docs := search.DocumentsFromIssues(issues)
+	stats, err := search.SyncVectorIndexWithOptions(ctx, idx, embedder, docs, search.IndexSyncOptions{
+		BatchSize: 64,
+		ModelID:   cfg.ModelID(),
+		MaxAge:    cfg.MaxAge,
+		Progress:  progress,
+	})
+	if err != nil {
+		return SemanticIndexReadyMsg{Error: err}
+	}
+	if !loaded || stats.Changed() {
+		if err := idx.Save(indexPath); err != nil {
+			return SemanticIndexReadyMsg{Error: fmt.Errorf("save semantic index: %w", err)}
 		}
 	}
+
+	return SemanticIndexReadyMsg{
+		Embedder:  embedder,
+		Index:     idx,
+		IndexPath: indexPath,
+		Loaded:    loaded,
+		Stats:     stats,
+	}
 }
 
 func dotFloat32(a, b []float32) float64 {
@@ -2,9 +2,14 @@ package ui
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/search"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 // =============================================================================
@@ -622,6 +627,97 @@ func TestSemanticIndexReadyMsgWithError(t *testing.T) {
 	}
 }
 
+func TestStartSemanticIndexBuildReportsProgress(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv(search.EnvSemanticEmbedder, "")
+
+	issues := make([]model.Issue, 150)
+	for i := range issues {
+		issues[i] = model.Issue{ID: fmt.Sprintf("bv-%d", i), Title: fmt.Sprintf("Issue %d", i), Status: model.StatusOpen}
+	}
+
+	build := StartSemanticIndexBuild(issues)
+	defer build.Cancel()
+	var progress []SemanticIndexProgressMsg
+	for {
+		msg := WaitForSemanticIndexMsgCmd(build)()
+		if p, ok := msg.(SemanticIndexProgressMsg); ok {
+			progress = append(progress, p)
+			continue
+		}
+		ready, ok := msg.(SemanticIndexReadyMsg)
+		if !ok {
+			t.Fatalf("unexpected message %T", msg)
+		}
+		if ready.Error != nil {
+			t.Fatalf("build failed: %v", ready.Error)
+		}
+		if ready.Index.Size() != len(issues) || ready.Stats.Embedded != len(issues) {
+			t.Fatalf("index size %d, embedded %d; want %d", ready.Index.Size(), ready.Stats.Embedded, len(issues))
+		}
+		break
+	}
+	if len(progress) == 0 {
+		t.Fatal("expected progress messages before the ready message")
+	}
+	for _, p := range progress {
+		if p.Total != len(issues) || p.Done > p.Total {
+			t.Errorf("bad progress %d/%d", p.Done, p.Total)
+		}
+	}
+}
+
+func TestSemanticIndexBuildCancelStopsMessages(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv(search.EnvSemanticEmbedder, "")
+
+	build := StartSemanticIndexBuild([]model.Issue{{ID: "a", Title: "A", Status: model.StatusOpen}})
+	build.Cancel()
+	build.Cancel() // Idempotent
+	// Messages may already be queued, but the wait ends once canceled.
+	for i := 0; i < 3; i++ {
+		if WaitForSemanticIndexMsgCmd(build)() == nil {
+			return
+		}
+	}
+	t.Fatal("expected waiting on a canceled build to return nil")
+}
+
+func TestModelSemanticIndexBuildLifecycle(t *testing.T) {
+	m := NewModel([]model.Issue{{ID: "a", Title: "A", Status: model.StatusOpen}}, nil, "")
+	m.semanticSearchEnabled = true
+	m.semanticIndexBuilding = true
+	build := &SemanticIndexBuild{msgs: make(chan tea.Msg, 1), done: make(chan struct{}), cancel: func() {}}
+	m.semanticIndexBuild = build
+
+	updated, cmd := m.Update(SemanticIndexProgressMsg{Done: 64, Total: 128, build: build})
+	m = updated.(Model)
+	if !strings.Contains(m.statusMsg, "64/128") {
+		t.Errorf("status = %q, want progress 64/128", m.statusMsg)
+	}
+	if cmd == nil {
+		t.Error("expected a command waiting for the next build message")
+	}
+
+	// Messages from a superseded build are ignored.
+	stale := &SemanticIndexBuild{}
+	updated, _ = m.Update(SemanticIndexReadyMsg{Error: context.Canceled, build: stale})
+	m = updated.(Model)
+	if !m.semanticSearchEnabled || !m.semanticIndexBuilding {
+		t.Fatal("stale build result should not change search state")
+	}
+
+	// A failed build falls back to fuzzy filtering with a visible notice.
+	updated, _ = m.Update(SemanticIndexReadyMsg{Error: context.DeadlineExceeded, build: build})
+	m = updated.(Model)
+	if m.semanticSearchEnabled || m.semanticIndexBuilding || m.semanticIndexBuild != nil {
+		t.Error("failed build should disable semantic search and clear the build")
+	}
+	if !m.statusIsError || !strings.Contains(m.statusMsg, "fuzzy") {
+		t.Errorf("status = %q (error=%v), want a fuzzy-search fallback notice", m.statusMsg, m.statusIsError)
+	}
+}
+
 // =============================================================================
 // Integration Tests
 // =============================================================================
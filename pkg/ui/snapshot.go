@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/Dicklesworthstone/beads_viewer/pkg/analysis"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/recipe"
 )
@@ -67,6 +68,8 @@ type DataSnapshot struct {
 	// LoadWarningCount is the number of non-fatal parse warnings encountered while loading.
 	// In TUI mode, warnings must not be printed to stderr during render.
 	LoadWarningCount int
+	// DuplicateIDs lists bead IDs that appeared on several lines (last one kept).
+	DuplicateIDs []loader.DuplicateID
 
 	// Phase 2 analysis status
 	// Phase2Ready is true when expensive metrics (PageRank, Betweenness, etc.) are computed
@@ -147,6 +150,7 @@ type SnapshotBuilder struct {
 	analyzer *analysis.Analyzer
 	analysis *analysis.GraphStats
 	recipe   *recipe.Recipe
+	ctx      context.Context
 }
 
 // NewSnapshotBuilder creates a builder for constructing a DataSnapshot.
@@ -168,6 +172,13 @@ func (b *SnapshotBuilder) WithRecipe(r *recipe.Recipe) *SnapshotBuilder {
 	return b
 }
 
+// WithContext sets the context used for background Phase 2 analysis, so a
+// newer reload can abort an in-flight computation.
+func (b *SnapshotBuilder) WithContext(ctx context.Context) *SnapshotBuilder {
+	b.ctx = ctx
+	return b
+}
+
 // Build constructs the final immutable DataSnapshot.
 // This performs all necessary computations that should happen in the background.
 // Uses AnalyzeAsync() so Phase 2 metrics compute in background - check Phase2Ready
@@ -193,7 +204,11 @@ func (b *SnapshotBuilder) Build() *DataSnapshot {
 	// Use AnalyzeAsync to allow Phase 2 to run in background
 	graphStats := b.analysis
 	if graphStats == nil {
-		graphStats = b.analyzer.AnalyzeAsync(context.Background())
+		ctx := b.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		graphStats = b.analyzer.AnalyzeAsync(ctx)
 	}
 
 	// Build lookup map
@@ -4,12 +4,12 @@ package ui
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
+	"github.com/Dicklesworthstone/beads_viewer/pkg/logging"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
@@ -105,19 +105,19 @@ func (t *TreeModel) saveState() {
 	// Write to file
 	data, err := json.MarshalIndent(state, "", "  ")
 	if err != nil {
-		log.Printf("warning: failed to marshal tree state: %v", err)
+		logging.Warn("failed to marshal tree state", "error", err)
 		return
 	}
 
 	path := TreeStatePath(t.beadsDir)
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		log.Printf("warning: failed to create state directory %s: %v", dir, err)
+		logging.Warn("failed to create state directory", "dir", dir, "error", err)
 		return
 	}
 
 	if err := os.WriteFile(path, data, 0644); err != nil {
-		log.Printf("warning: failed to write tree state to %s: %v", path, err)
+		logging.Warn("failed to write tree state", "path", path, "error", err)
 		return
 	}
 }
@@ -134,7 +134,7 @@ func (t *TreeModel) loadState() {
 
 	var state TreeState
 	if err := json.Unmarshal(data, &state); err != nil {
-		log.Printf("warning: invalid tree state file, using defaults: %v", err)
+		logging.Warn("invalid tree state file, using defaults", "error", err)
 		return
 	}
 
@@ -17,6 +17,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Dicklesworthstone/beads_viewer/pkg/logging"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/version"
 )
 
@@ -637,7 +638,7 @@ func PerformUpdate(release *Release, skipConfirm bool) (*UpdateResult, error) {
 	// Ensure executable permissions
 	if err := os.Chmod(binaryPath, 0755); err != nil {
 		// Not fatal, but log it
-		fmt.Fprintf(os.Stderr, "Warning: could not set permissions: %v\n", err)
+		logging.Warn("could not set permissions", "path", binaryPath, "error", err)
 	}
 
 	result.Success = true
@@ -1,7 +1,60 @@
 package version
 
+import "runtime/debug"
+
 // Version is the current application version.
 // This is a var (not const) so it can be overridden at build time via:
 //
 //	go build -ldflags "-X github.com/Dicklesworthstone/beads_viewer/pkg/version.Version=v1.2.3"
 var Version = "v0.12.1"
+
+// Commit and BuildDate identify the exact build and are set the same way:
+//
+//	go build -ldflags "-X github.com/Dicklesworthstone/beads_viewer/pkg/version.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/Dicklesworthstone/beads_viewer/pkg/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// When Commit is left empty, the VCS revision the Go toolchain embeds is used
+// instead. BuildDate has no such fallback: the embedded vcs.time is when the
+// commit was made, not when the binary was built.
+var (
+	Commit    = ""
+	BuildDate = ""
+)
+
+// BuildInfo describes the running binary.
+type BuildInfo struct {
+	Version   string `json:"bv_version"`
+	Commit    string `json:"bv_commit"`
+	BuildDate string `json:"bv_build_date"`
+}
+
+// Info returns the version, commit and build date of the running binary.
+// Commit is "unknown" when neither ldflags nor the embedded VCS stamp provide
+// it, and BuildDate when ldflags do not set it.
+func Info() BuildInfo {
+	info := BuildInfo{Version: Version, Commit: Commit, BuildDate: BuildDate}
+	if bi, ok := debug.ReadBuildInfo(); ok && info.Commit == "" {
+		for _, s := range bi.Settings {
+			if s.Key == "vcs.revision" {
+				info.Commit = s.Value
+			}
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildDate == "" {
+		info.BuildDate = "unknown"
+	}
+	return info
+}
+
+// String formats the build info for humans, e.g.
+// "bv v0.12.1 (commit 1a2b3c4, built 2025-01-02T03:04:05Z)".
+func (b BuildInfo) String() string {
+	commit := b.Commit
+	if len(commit) > 12 {
+		commit = commit[:12]
+	}
+	return "bv " + b.Version + " (commit " + commit + ", built " + b.BuildDate + ")"
+}
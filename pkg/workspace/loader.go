@@ -3,13 +3,13 @@ package workspace
 import (
 	"context"
 	"fmt"
-	"io"
-	"log"
 	"path/filepath"
+	"sync"
 
 	"golang.org/x/sync/errgroup"
 
 	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/logging"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 )
 
@@ -32,7 +32,8 @@ type LoadResult struct {
 type AggregateLoader struct {
 	config        *Config
 	workspaceRoot string
-	logger        *log.Logger
+	opts          loader.ParseOptions
+	handlerMu     sync.Mutex // Serializes opts' handlers across the parallel loads
 }
 
 // NewAggregateLoader creates a new aggregate loader for the given workspace config
@@ -40,16 +41,14 @@ func NewAggregateLoader(config *Config, workspaceRoot string) *AggregateLoader {
 	return &AggregateLoader{
 		config:        config,
 		workspaceRoot: workspaceRoot,
-		// Silence by default. Callers can opt-in via SetLogger.
-		// This avoids polluting stderr (e.g., breaking robot JSON consumers that
-		// capture combined stdout/stderr).
-		logger: log.New(io.Discard, "", 0),
 	}
 }
 
-// SetLogger sets a custom logger for error reporting
-func (l *AggregateLoader) SetLogger(logger *log.Logger) {
-	l.logger = logger
+// SetParseOptions sets the options every repo's beads are parsed with. The
+// handlers are called one at a time, each repo's warnings prefixed with its
+// name and its duplicate IDs namespaced like its beads.
+func (l *AggregateLoader) SetParseOptions(opts loader.ParseOptions) {
+	l.opts = opts
 }
 
 // LoadAll loads issues from all enabled repositories in the workspace.
@@ -138,9 +137,7 @@ func (l *AggregateLoader) loadReposParallel(ctx context.Context, repos []RepoCon
 		return results, err
 	}
 
-	if l.logger != nil {
-		l.logger.Printf("Finished parallel loading of %d repos", len(repos))
-	}
+	logging.Debug("finished parallel loading", "repos", len(repos))
 
 	return results, nil
 }
@@ -159,7 +156,7 @@ func (l *AggregateLoader) loadSingleRepo(repo RepoConfig) ([]model.Issue, error)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load issues from %s: %w", repo.GetName(), err)
 	}
-	issues, err := loader.LoadIssuesFromFile(jsonlPath)
+	issues, err := loader.LoadIssuesFromFileWithOptions(jsonlPath, l.repoParseOptions(repo))
 	if err != nil {
 		return nil, fmt.Errorf("failed to load issues from %s: %w", repo.GetName(), err)
 	}
@@ -170,40 +167,64 @@ func (l *AggregateLoader) loadSingleRepo(repo RepoConfig) ([]model.Issue, error)
 		localIDs[issue.ID] = true
 	}
 
-	// Apply namespacing to all IDs
+	// Apply namespacing to all IDs; references with another repo's prefix
+	// are already qualified, anything else is assumed local
 	prefix := repo.GetPrefix()
-	namespacedIssues := l.namespaceIssues(issues, prefix, localIDs)
+	namespaceIssues(issues, prefix, localIDs, func(ref string) string {
+		if l.hasKnownPrefix(ref) {
+			return ref
+		}
+		return QualifyID(ref, prefix)
+	})
+
+	return issues, nil
+}
 
-	return namespacedIssues, nil
+// repoParseOptions adapts the loader's options to repo, serializing the
+// handlers and labelling what they receive with the repo.
+func (l *AggregateLoader) repoParseOptions(repo RepoConfig) loader.ParseOptions {
+	opts := l.opts
+	if warn := l.opts.WarningHandler; warn != nil {
+		opts.WarningHandler = func(msg string) {
+			l.handlerMu.Lock()
+			defer l.handlerMu.Unlock()
+			warn(repo.GetName() + ": " + msg)
+		}
+	}
+	if onDups := l.opts.DuplicateHandler; onDups != nil {
+		opts.DuplicateHandler = func(dups []loader.DuplicateID) {
+			qualified := make([]loader.DuplicateID, len(dups))
+			for i, d := range dups {
+				qualified[i] = loader.DuplicateID{ID: QualifyID(d.ID, repo.GetPrefix()), Count: d.Count}
+			}
+			l.handlerMu.Lock()
+			defer l.handlerMu.Unlock()
+			onDups(qualified)
+		}
+	}
+	return opts
 }
 
-// namespaceIssues adds the prefix to all issue IDs and dependency references
-// It mutates the issues slice in place to reduce allocations.
-func (l *AggregateLoader) namespaceIssues(issues []model.Issue, prefix string, localIDs map[string]bool) []model.Issue {
+// namespaceIssues adds the prefix to all issue IDs and comment references,
+// mutating the issues in place to reduce allocations. A dependency on one of
+// localIDs gets the prefix too; resolveRef qualifies every other reference.
+func namespaceIssues(issues []model.Issue, prefix string, localIDs map[string]bool, resolveRef func(string) string) {
 	for i := range issues {
-		// Mutate issue in place
 		issue := &issues[i]
 		issue.ID = QualifyID(issue.ID, prefix)
 
-		// Namespace dependency references in place
 		for _, dep := range issue.Dependencies {
 			if dep == nil {
 				continue
 			}
 			dep.IssueID = QualifyID(dep.IssueID, prefix)
-			
-			// Resolve DependsOnID
 			if localIDs[dep.DependsOnID] {
 				dep.DependsOnID = QualifyID(dep.DependsOnID, prefix)
-			} else if l.hasKnownPrefix(dep.DependsOnID) {
-				// External reference, keep as is
 			} else {
-				// Assume local
-				dep.DependsOnID = QualifyID(dep.DependsOnID, prefix)
+				dep.DependsOnID = resolveRef(dep.DependsOnID)
 			}
 		}
 
-		// Namespace comment issue references in place
 		for _, comment := range issue.Comments {
 			if comment == nil {
 				continue
@@ -211,8 +232,6 @@ func (l *AggregateLoader) namespaceIssues(issues []model.Issue, prefix string, l
 			comment.IssueID = QualifyID(comment.IssueID, prefix)
 		}
 	}
-
-	return issues
 }
 
 // hasKnownPrefix checks if an ID already has a known namespace prefix
@@ -226,15 +245,20 @@ func (l *AggregateLoader) hasKnownPrefix(id string) bool {
 	return false
 }
 
-// logRepoError logs an error for a repo that failed to load
+// logRepoError logs an error for a repo that failed to load. It is a debug
+// message: callers report failed repos from the LoadResults.
 func (l *AggregateLoader) logRepoError(repoName string, err error) {
-	if l.logger != nil {
-		l.logger.Printf("WARNING: Failed to load repo %q: %v", repoName, err)
-	}
+	logging.Debug("failed to load repo", "repo", repoName, "error", err)
 }
 
 // LoadAllFromConfig is a convenience function that loads a workspace config and all its repos
 func LoadAllFromConfig(ctx context.Context, configPath string) ([]model.Issue, []LoadResult, error) {
+	return LoadAllFromConfigWithOptions(ctx, configPath, loader.ParseOptions{})
+}
+
+// LoadAllFromConfigWithOptions is LoadAllFromConfig parsing every repo with
+// opts (see SetParseOptions).
+func LoadAllFromConfigWithOptions(ctx context.Context, configPath string, opts loader.ParseOptions) ([]model.Issue, []LoadResult, error) {
 	config, err := LoadConfig(configPath)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to load workspace config: %w", err)
@@ -242,6 +266,7 @@ func LoadAllFromConfig(ctx context.Context, configPath string) ([]model.Issue, [
 
 	workspaceRoot := filepath.Dir(filepath.Dir(configPath)) // .bv/workspace.yaml -> workspace root
 	loader := NewAggregateLoader(config, workspaceRoot)
+	loader.SetParseOptions(opts)
 
 	return loader.LoadAll(ctx)
 }
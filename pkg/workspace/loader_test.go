@@ -5,9 +5,13 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
+	bvloader "github.com/Dicklesworthstone/beads_viewer/pkg/loader"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
 	"github.com/Dicklesworthstone/beads_viewer/pkg/workspace"
 )
@@ -161,6 +165,67 @@ func TestAggregateLoaderPartialFailure(t *testing.T) {
 	}
 }
 
+func TestAggregateLoaderAppliesParseOptions(t *testing.T) {
+	tmpDir := t.TempDir()
+	dupes := `{"id":"AUTH-1","title":"First","status":"open","issue_type":"bug"}
+{"id":"AUTH-1","title":"Second","status":"open","issue_type":"bug"}
+`
+	for _, repo := range []string{"api", "web"} {
+		beadsDir := filepath.Join(tmpDir, repo, ".beads")
+		if err := os.MkdirAll(beadsDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(beadsDir, "beads.jsonl"), []byte(dupes), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	config := &workspace.Config{
+		Repos: []workspace.RepoConfig{
+			{Name: "api", Path: "api", Prefix: "api-"},
+			{Name: "web", Path: "web", Prefix: "web-"},
+		},
+	}
+
+	var warnings []string
+	var dupIDs []string
+	loader := workspace.NewAggregateLoader(config, tmpDir)
+	loader.SetParseOptions(bvloader.ParseOptions{
+		WarningHandler: func(msg string) { warnings = append(warnings, msg) },
+		DuplicateHandler: func(dups []bvloader.DuplicateID) {
+			for _, d := range dups {
+				dupIDs = append(dupIDs, d.ID)
+			}
+		},
+		DefaultPriorities: map[string]int{"bug": 1},
+	})
+	issues, _, err := loader.LoadAll(context.Background())
+	if err != nil {
+		t.Fatalf("LoadAll: %v", err)
+	}
+	if len(issues) != 2 || issues[0].Priority != 1 || issues[1].Priority != 1 {
+		t.Errorf("expected one bug per repo at the default P1, got %+v", issues)
+	}
+	sort.Strings(warnings)
+	if len(warnings) != 2 || !strings.HasPrefix(warnings[0], "api: ") || !strings.HasPrefix(warnings[1], "web: ") {
+		t.Errorf("expected a warning per repo, prefixed with its name, got %v", warnings)
+	}
+	sort.Strings(dupIDs)
+	if !reflect.DeepEqual(dupIDs, []string{"api-AUTH-1", "web-AUTH-1"}) {
+		t.Errorf("duplicate IDs = %v, want them namespaced per repo", dupIDs)
+	}
+
+	loader.SetParseOptions(bvloader.ParseOptions{Strict: true})
+	_, results, err := loader.LoadAll(context.Background())
+	if err != nil {
+		t.Fatalf("LoadAll: %v", err)
+	}
+	for _, r := range results {
+		if r.Error == nil {
+			t.Errorf("%s: expected --strict to reject the duplicate IDs", r.RepoName)
+		}
+	}
+}
+
 func TestAggregateLoaderNamespacesDependencies(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -0,0 +1,104 @@
+package workspace
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+)
+
+// LoadRecursive loads the beads of every project loader.DiscoverBeadsDirs
+// finds under root as an implicit workspace, and sets each issue's SourceRepo
+// to its project's Path. Projects without beads data yet are left out of the
+// result.
+//
+// Bead IDs are namespaced the way workspace repos are, so two projects' bv-1
+// stay distinct: services/api's bv-1 becomes api-bv-1 (see ProjectPrefixes),
+// while beads in root's own .beads keep their IDs. Dependencies and comments
+// are rewritten to match. A dependency on an ID its project does not have
+// points at the one other project that does, if exactly one does; references
+// that are already qualified are kept.
+func LoadRecursive(root string, opts loader.ParseOptions) ([]model.Issue, []loader.BeadsProject, error) {
+	projects, err := loader.DiscoverBeadsDirs(root)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var loaded []loader.BeadsProject
+	var perProject [][]model.Issue
+	for _, p := range projects {
+		projectIssues, err := loader.LoadBeadsDir(p.BeadsDir, opts)
+		if loader.IsNoBeadsData(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("load beads for %s: %w", p.Path, err)
+		}
+		loaded = append(loaded, p)
+		perProject = append(perProject, projectIssues)
+	}
+
+	prefixes := ProjectPrefixes(loaded)
+	owners := make(map[string][]string) // Local ID -> prefixes of the projects that have it
+	qualified := make(map[string]bool)
+	for i, p := range loaded {
+		for _, iss := range perProject[i] {
+			owners[iss.ID] = append(owners[iss.ID], prefixes[p.Path])
+			qualified[QualifyID(iss.ID, prefixes[p.Path])] = true
+		}
+	}
+
+	var issues []model.Issue
+	for i, p := range loaded {
+		prefix := prefixes[p.Path]
+		localIDs := make(map[string]bool, len(perProject[i]))
+		for _, iss := range perProject[i] {
+			localIDs[iss.ID] = true
+		}
+		namespaceIssues(perProject[i], prefix, localIDs, func(ref string) string {
+			if qualified[ref] {
+				return ref
+			}
+			if len(owners[ref]) == 1 {
+				return QualifyID(ref, owners[ref][0])
+			}
+			// Missing or ambiguous: keep it inside this project, where it stays unresolved
+			return QualifyID(ref, prefix)
+		})
+		for j := range perProject[i] {
+			perProject[i][j].SourceRepo = p.Path
+		}
+		issues = append(issues, perProject[i]...)
+	}
+	return issues, loaded, nil
+}
+
+// ProjectPrefixes returns the ID prefix of each project, keyed by Path. The
+// root project (".") has none. Others get their directory's name, as a
+// workspace repo would (services/api -> "api-"); projects whose names clash
+// use their whole path instead (services/api -> "services-api-").
+func ProjectPrefixes(projects []loader.BeadsProject) map[string]string {
+	namePrefix := func(p loader.BeadsProject) string {
+		repo := RepoConfig{Path: p.Path}
+		return repo.GetPrefix()
+	}
+	byName := make(map[string]int)
+	for _, p := range projects {
+		if p.Path != "." {
+			byName[namePrefix(p)]++
+		}
+	}
+	prefixes := make(map[string]string, len(projects))
+	for _, p := range projects {
+		switch prefix := namePrefix(p); {
+		case p.Path == ".":
+			prefixes[p.Path] = ""
+		case byName[prefix] > 1:
+			prefixes[p.Path] = strings.ToLower(strings.ReplaceAll(p.Path, "/", "-")) + "-"
+		default:
+			prefixes[p.Path] = prefix
+		}
+	}
+	return prefixes
+}
@@ -0,0 +1,135 @@
+package workspace_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/beads_viewer/pkg/loader"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/model"
+	"github.com/Dicklesworthstone/beads_viewer/pkg/workspace"
+)
+
+// writeNestedBeads creates a .beads/issues.jsonl holding jsonl under root/rel.
+func writeNestedBeads(t *testing.T, root, rel, jsonl string) {
+	t.Helper()
+	dir := filepath.Join(root, rel, ".beads")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "issues.jsonl"), []byte(jsonl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadRecursiveNamespacesProjects(t *testing.T) {
+	root := t.TempDir()
+	writeNestedBeads(t, root, ".", `{"id":"root-1","title":"Root","status":"open","issue_type":"task"}`+"\n")
+	writeNestedBeads(t, root, "services/api", `{"id":"api-1","title":"API","status":"open","issue_type":"task"}`+"\n")
+	writeNestedBeads(t, root, "services/web", `{"id":"web-1","title":"Web","status":"open","issue_type":"task","dependencies":[{"issue_id":"web-1","depends_on_id":"api-1","type":"blocks"}]}`+"\n"+
+		`{"id":"api-1","title":"Clash","status":"open","issue_type":"task"}`+"\n")
+	if err := os.MkdirAll(filepath.Join(root, "services/empty/.beads"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, loaded, err := workspace.LoadRecursive(root, loader.ParseOptions{})
+	if err != nil {
+		t.Fatalf("LoadRecursive: %v", err)
+	}
+	if len(loaded) != 3 {
+		t.Errorf("loaded %d projects, want 3 (services/empty has no beads)", len(loaded))
+	}
+	tags := make(map[string]string)
+	for _, iss := range issues {
+		tags[iss.ID] = iss.SourceRepo
+	}
+	// api-1 already carries services/api's prefix, so it is left alone
+	want := map[string]string{
+		"root-1":    ".",
+		"api-1":     "services/api",
+		"web-1":     "services/web",
+		"web-api-1": "services/web",
+	}
+	if len(tags) != len(want) || len(issues) != len(want) {
+		t.Errorf("loaded beads %v, want %v", tags, want)
+	}
+	for id, project := range want {
+		if tags[id] != project {
+			t.Errorf("%s tagged %q, want %q", id, tags[id], project)
+		}
+	}
+	// web-1's api-1 is its own project's, not services/api's
+	for _, iss := range issues {
+		if iss.ID == "web-1" {
+			if dep := iss.Dependencies[0]; dep.IssueID != iss.ID || dep.DependsOnID != "web-api-1" {
+				t.Errorf("web-1 dependency = %s -> %s, want web-1 -> web-api-1", dep.IssueID, dep.DependsOnID)
+			}
+		}
+	}
+}
+
+func TestLoadRecursiveKeepsCollidingIDsDistinct(t *testing.T) {
+	root := t.TempDir()
+	writeNestedBeads(t, root, "proj-a", `{"id":"bv-1","title":"A one","status":"open","issue_type":"task"}
+{"id":"bv-2","title":"A two","status":"open","issue_type":"task","dependencies":[{"issue_id":"bv-2","depends_on_id":"bv-1","type":"blocks"}]}
+`)
+	writeNestedBeads(t, root, "proj-b", `{"id":"bv-1","title":"B one","status":"open","issue_type":"task","comments":[{"id":1,"issue_id":"bv-1","text":"hi"}]}
+{"id":"b-2","title":"B two","status":"open","issue_type":"task","dependencies":[{"issue_id":"b-2","depends_on_id":"bv-2","type":"blocks"},{"issue_id":"b-2","depends_on_id":"proj-a-bv-1","type":"related"}]}
+`)
+
+	issues, _, err := workspace.LoadRecursive(root, loader.ParseOptions{})
+	if err != nil {
+		t.Fatalf("LoadRecursive: %v", err)
+	}
+	byID := make(map[string]model.Issue, len(issues))
+	for _, iss := range issues {
+		byID[iss.ID] = iss
+	}
+	if len(byID) != 4 || byID["proj-a-bv-1"].Title != "A one" || byID["proj-b-bv-1"].Title != "B one" {
+		t.Fatalf("want proj-a-bv-1 and proj-b-bv-1 as distinct beads, got %v", byID)
+	}
+	deps := func(id string) string {
+		var out []string
+		for _, d := range byID[id].Dependencies {
+			out = append(out, d.IssueID+"->"+d.DependsOnID)
+		}
+		return strings.Join(out, ",")
+	}
+	// Local references stay local; bv-2 exists only in proj-a; qualified ones are kept
+	if got := deps("proj-a-bv-2"); got != "proj-a-bv-2->proj-a-bv-1" {
+		t.Errorf("proj-a-bv-2 deps = %s", got)
+	}
+	if got := deps("proj-b-b-2"); got != "proj-b-b-2->proj-a-bv-2,proj-b-b-2->proj-a-bv-1" {
+		t.Errorf("proj-b-b-2 deps = %s", got)
+	}
+	if c := byID["proj-b-bv-1"].Comments; len(c) != 1 || c[0].IssueID != "proj-b-bv-1" {
+		t.Errorf("comment not rewritten: %+v", c)
+	}
+
+	// Single-project loading is unchanged
+	single, err := loader.LoadIssues(filepath.Join(root, "proj-a"))
+	if err != nil || len(single) != 2 || single[0].ID != "bv-1" || single[0].SourceRepo != "" {
+		t.Errorf("LoadIssues(proj-a) = %v, %v; want plain bv-1 and bv-2", single, err)
+	}
+}
+
+func TestProjectPrefixesUsePathOnNameClash(t *testing.T) {
+	got := workspace.ProjectPrefixes([]loader.BeadsProject{
+		{Path: "."},
+		{Path: "services/API"},
+		{Path: "libs/api"},
+		{Path: "web"},
+	})
+	want := map[string]string{
+		".":            "",
+		"services/API": "services-api-",
+		"libs/api":     "libs-api-",
+		"web":          "web-",
+	}
+	for path, prefix := range want {
+		if got[path] != prefix {
+			t.Errorf("prefix of %s = %q, want %q", path, got[path], prefix)
+		}
+	}
+}
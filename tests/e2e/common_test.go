@@ -25,13 +25,45 @@ func TestMain(m *testing.M) {
 		os.Exit(1)
 	}
 
+	// Keep bv away from the real user config (recipe run history, prefs)
+	home, err := isolateHome()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to isolate HOME: %v\n", err)
+		os.Exit(1)
+	}
+
 	code := m.Run()
 	if bvBinaryDir != "" {
 		_ = os.RemoveAll(bvBinaryDir)
 	}
+	_ = os.RemoveAll(home)
 	os.Exit(code)
 }
 
+// isolateHome points HOME and XDG_CONFIG_HOME at a fresh directory for every
+// bv the tests run, pinning the Go caches first so tests that build bv
+// themselves still reuse them. It returns the directory.
+func isolateHome() (string, error) {
+	out, err := exec.Command("go", "env", "GOCACHE", "GOMODCACHE", "GOPATH").Output()
+	if err != nil {
+		return "", fmt.Errorf("go env: %w", err)
+	}
+	vals := strings.Split(strings.TrimSpace(string(out)), "\n")
+	for i, key := range []string{"GOCACHE", "GOMODCACHE", "GOPATH"} {
+		if i < len(vals) && vals[i] != "" {
+			os.Setenv(key, vals[i])
+		}
+	}
+
+	home, err := os.MkdirTemp("", "bv-e2e-home-*")
+	if err != nil {
+		return "", err
+	}
+	os.Setenv("HOME", home)
+	os.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	return home, nil
+}
+
 func buildBvOnce() error {
 	tempDir, err := os.MkdirTemp("", "bv-e2e-build-*")
 	if err != nil {
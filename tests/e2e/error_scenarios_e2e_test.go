@@ -356,6 +356,48 @@ func TestError_InvalidGitRevision(t *testing.T) {
 	}
 }
 
+// TestError_DBWithOtherSources tests that --db is rejected, not ignored,
+// alongside the sources it cannot apply to.
+func TestError_DBWithOtherSources(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+
+	for _, other := range [][]string{{"--as-of", "HEAD"}, {"--workspace", "ws.yaml"}, {"--recursive"}} {
+		cmd := exec.Command(bv, append([]string{"--robot-triage", "--db", "beads.db"}, other...)...)
+		cmd.Dir = env
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err == nil {
+			t.Errorf("--db with %s succeeded, want an error", other[0])
+			continue
+		}
+		if want := "--db cannot be combined with " + other[0]; !strings.Contains(stderr.String(), want) {
+			t.Errorf("stderr = %q, want it to contain %q", stderr.String(), want)
+		}
+	}
+}
+
+// TestError_ClusterByWithoutDOT tests that --cluster-by is rejected, not
+// ignored, with graph formats that have no clusters.
+func TestError_ClusterByWithoutDOT(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+
+	for _, format := range []string{"json", "mermaid", "dsm", "prometheus"} {
+		cmd := exec.Command(bv, "--robot-graph", "--graph-format="+format, "--cluster-by=status")
+		cmd.Dir = env
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err == nil {
+			t.Errorf("--cluster-by with --graph-format=%s succeeded, want an error", format)
+			continue
+		}
+		if !strings.Contains(stderr.String(), "--cluster-by only applies to --graph-format=dot") {
+			t.Errorf("stderr = %q, want the --cluster-by error", stderr.String())
+		}
+	}
+}
+
 // =============================================================================
 // 4. Analysis Errors
 // =============================================================================
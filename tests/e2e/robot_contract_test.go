@@ -5,6 +5,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -513,3 +515,69 @@ func TestRobotUsageHintsPresent(t *testing.T) {
 		})
 	}
 }
+
+func TestRobotInsightsContractNoBeads(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir() // no .beads at all
+
+	var payload map[string]any
+	runRobotJSON(t, bv, env, "--robot-insights", &payload)
+
+	if payload["data_hash"] != "empty" {
+		t.Fatalf("expected data_hash of empty input, got %v", payload["data_hash"])
+	}
+	stats, ok := payload["Stats"].(map[string]any)
+	if !ok {
+		t.Fatalf("insights missing Stats: %v", payload)
+	}
+	if stats["NodeCount"] != float64(0) || stats["EdgeCount"] != float64(0) {
+		t.Fatalf("expected zero nodes/edges, got %v/%v", stats["NodeCount"], stats["EdgeCount"])
+	}
+	for _, key := range []string{"Bottlenecks", "Keystones", "Articulation", "Slack", "Cycles", "Orphans"} {
+		list, ok := payload[key].([]any)
+		if !ok || len(list) != 0 {
+			t.Fatalf("expected %s to be an empty list, got %v", key, payload[key])
+		}
+	}
+	hints, ok := payload["usage_hints"].([]any)
+	if !ok {
+		t.Fatalf("insights missing usage_hints: %v", payload["usage_hints"])
+	}
+	foundInit := false
+	for _, h := range hints {
+		if s, _ := h.(string); strings.Contains(s, "bd init") {
+			foundInit = true
+		}
+	}
+	if !foundInit {
+		t.Fatalf("expected usage_hints to explain how to create beads, got %v", hints)
+	}
+	if _, err := os.Stat(filepath.Join(env, ".gitignore")); !os.IsNotExist(err) {
+		t.Fatalf("expected no .gitignore to be written outside a beads project (err=%v)", err)
+	}
+}
+
+func TestRobotReadyMatchesRobotNext(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+	writeBeads(t, env, `{"id":"A","title":"Unblocker","status":"open","priority":1,"issue_type":"task"}
+{"id":"B","title":"Blocked","status":"open","priority":2,"issue_type":"task","dependencies":[{"issue_id":"B","depends_on_id":"A","type":"blocks"}]}`)
+
+	var next, ready map[string]any
+	runRobotJSON(t, bv, env, "--robot-next", &next)
+	runRobotJSON(t, bv, env, "--robot-ready", &ready)
+
+	// generated_at is a wall-clock timestamp; everything else must match.
+	delete(next, "generated_at")
+	delete(ready, "generated_at")
+	if !reflect.DeepEqual(next, ready) {
+		t.Fatalf("--robot-ready differs from --robot-next:\nnext:  %v\nready: %v", next, ready)
+	}
+	if ready["id"] != "A" {
+		t.Fatalf("expected --robot-ready to pick A, got %v", ready["id"])
+	}
+	hints, _ := ready["usage_hints"].([]any)
+	if len(hints) == 0 || !strings.HasPrefix(hints[0].(string), "--robot-ready - Alias for --robot-next") {
+		t.Fatalf("expected usage_hints to name the --robot-ready alias, got %v", ready["usage_hints"])
+	}
+}
@@ -245,3 +245,101 @@ func TestRobotOutputsShareDataHashAndStatus(t *testing.T) {
 		}
 	}
 }
+
+func TestRobotDiffGitRefAgainstWorkingCopy(t *testing.T) {
+	bv := buildBvBinary(t)
+	repoDir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test",
+			"GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test",
+			"GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+
+	// Commit 1 predates beads; commit 2 adds A and B.
+	if err := os.WriteFile(filepath.Join(repoDir, "README.md"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("init")
+	git("add", "README.md")
+	git("commit", "-m", "initial")
+
+	beadsDir := filepath.Join(repoDir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	committed := `{"id":"A","title":"Alpha","status":"open","priority":1,"issue_type":"task"}
+{"id":"B","title":"Beta","status":"open","priority":2,"issue_type":"task"}`
+	if err := os.WriteFile(filepath.Join(beadsDir, "beads.jsonl"), []byte(committed), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", ".beads/beads.jsonl")
+	git("commit", "-m", "add beads")
+
+	// Uncommitted working copy: A closed, C added.
+	working := `{"id":"A","title":"Alpha","status":"closed","priority":1,"issue_type":"task"}
+{"id":"B","title":"Beta","status":"open","priority":2,"issue_type":"task"}
+{"id":"C","title":"Gamma","status":"open","priority":2,"issue_type":"task"}`
+	if err := os.WriteFile(filepath.Join(beadsDir, "beads.jsonl"), []byte(working), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	type diffPayload struct {
+		GitRef      string `json:"git_ref"`
+		FromMissing bool   `json:"from_missing"`
+		Diff        struct {
+			NewIssues []struct {
+				ID string `json:"id"`
+			} `json:"new_issues"`
+			ClosedIssues []struct {
+				ID string `json:"id"`
+			} `json:"closed_issues"`
+		} `json:"diff"`
+	}
+	run := func(ref string) diffPayload {
+		cmd := exec.Command(bv, "--robot-diff", "--git-ref", ref)
+		cmd.Dir = repoDir
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("--git-ref %s failed: %v\n%s", ref, err, out)
+		}
+		var p diffPayload
+		if err := json.Unmarshal(out, &p); err != nil {
+			t.Fatalf("json decode: %v\nout=%s", err, out)
+		}
+		return p
+	}
+
+	head := run("HEAD")
+	if head.GitRef != "HEAD" || head.FromMissing {
+		t.Fatalf("unexpected ref metadata: %+v", head)
+	}
+	if len(head.Diff.NewIssues) != 1 || head.Diff.NewIssues[0].ID != "C" {
+		t.Fatalf("expected new issue C against HEAD, got %+v", head.Diff.NewIssues)
+	}
+	if len(head.Diff.ClosedIssues) != 1 || head.Diff.ClosedIssues[0].ID != "A" {
+		t.Fatalf("expected closed issue A against HEAD, got %+v", head.Diff.ClosedIssues)
+	}
+
+	// HEAD~1 has no beads file: everything in the working copy is new.
+	before := run("HEAD~1")
+	if !before.FromMissing {
+		t.Fatal("expected from_missing for a ref without beads")
+	}
+	if len(before.Diff.NewIssues) != 3 {
+		t.Fatalf("expected all 3 issues new against a ref without beads, got %+v", before.Diff.NewIssues)
+	}
+
+	cmd := exec.Command(bv, "--robot-diff", "--git-ref", "no-such-branch")
+	cmd.Dir = repoDir
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Fatalf("expected an error for an unknown ref, got:\n%s", out)
+	}
+}
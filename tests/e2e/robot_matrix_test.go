@@ -3,8 +3,10 @@ package main_test
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"os/exec"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -267,6 +269,72 @@ func TestRobotDeterminismAcrossCommands(t *testing.T) {
 	}
 }
 
+// robotVolatileFields matches values that legitimately change between runs
+// (wall-clock timestamps and ages measured from now). robotTimingField matches
+// phase timings, which are omitted when they round to zero.
+var (
+	robotVolatileFields = regexp.MustCompile(`"(generated_at|computed_at|detected_at|eta_date\w*|earliest_eta|latest_eta|avg_days_since_update|cutoff)": [^,\n}]+`)
+	robotTimingField    = regexp.MustCompile(`,\s*"ms": [0-9.e+-]+`)
+)
+
+// TestRobotOutputsByteIdenticalAcrossRuns runs each robot command twice on a
+// fixture full of ties (equal priorities, scores and blocker counts) and
+// requires identical output apart from volatile fields, so every list sort
+// needs a tie-breaker rather than relying on map iteration order.
+func TestRobotOutputsByteIdenticalAcrossRuns(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+
+	var lines []string
+	stamp := `"created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"`
+	for h := 0; h < 4; h++ {
+		hub := fmt.Sprintf("t-h%d", h)
+		lines = append(lines, fmt.Sprintf(`{"id":%q,"title":"Hub %d","status":"open","priority":2,"issue_type":"task","labels":["core","api"],%s}`, hub, h, stamp))
+		for l := 0; l < 3; l++ {
+			leaf := fmt.Sprintf("%s-l%d", hub, l)
+			lines = append(lines, fmt.Sprintf(`{"id":%q,"title":"Leaf %d%d","status":"open","priority":2,"issue_type":"task","labels":["core","api"],%s,"dependencies":[{"issue_id":%q,"depends_on_id":%q,"type":"blocks"}]}`,
+				leaf, h, l, stamp, leaf, hub))
+		}
+	}
+	for i := 0; i < 6; i++ {
+		lines = append(lines, fmt.Sprintf(`{"id":"t-s%d","title":"Solo %d","status":"open","priority":2,"issue_type":"task","labels":["ui"],%s}`, i, i, stamp))
+	}
+	writeBeads(t, env, strings.Join(lines, "\n"))
+
+	commands := []string{
+		"--robot-triage",
+		"--robot-next",
+		"--robot-plan",
+		"--robot-insights",
+		"--robot-priority",
+		"--robot-triage-by-track",
+		"--robot-triage-by-label",
+		"--robot-label-health",
+		"--robot-label-flow",
+		"--robot-label-attention",
+		"--robot-suggest",
+		"--robot-alerts",
+		"--robot-graph",
+		"--robot-forecast=all",
+	}
+	run := func(flag string) string {
+		cmd := exec.Command(bv, flag)
+		cmd.Dir = env
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("%s failed: %v\n%s", flag, err, out)
+		}
+		normalized := robotTimingField.ReplaceAllString(string(out), "")
+		return robotVolatileFields.ReplaceAllString(normalized, `"$1": "<volatile>"`)
+	}
+	for _, flag := range commands {
+		first, second := run(flag), run(flag)
+		if first != second {
+			t.Errorf("%s output differs between runs:\nfirst:\n%s\nsecond:\n%s", flag, first, second)
+		}
+	}
+}
+
 // TestRobotOutputContainsUsageHints verifies all commands include hints.
 func TestRobotOutputContainsUsageHints(t *testing.T) {
 	bv := buildBvBinary(t)
@@ -81,3 +81,54 @@ func TestRobotSearchContract(t *testing.T) {
 		t.Fatalf("expected usage_hints")
 	}
 }
+
+// TestRobotSearch_OfflineEmbedderAlias exercises the semantic path with only
+// BV_EMBEDDER=hash set, so CI needs no embedding service.
+func TestRobotSearch_OfflineEmbedderAlias(t *testing.T) {
+	bv := buildBvBinary(t)
+	env := t.TempDir()
+	writeBeads(t, env, `{"id":"A","title":"Quokka migration","description":"quokka quokka quokka habitat","status":"open","priority":1,"issue_type":"task"}
+{"id":"B","title":"Unrelated docs","description":"readme changelog docs","status":"open","priority":2,"issue_type":"task"}`)
+
+	run := func() []byte {
+		cmd := exec.Command(bv, "--search", "quokka", "--robot-search")
+		cmd.Dir = env
+		cmd.Env = append(os.Environ(), "BV_SEMANTIC_EMBEDDER=", "BV_EMBEDDER=hash")
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("robot-search failed: %v\n%s", err, out)
+		}
+		return out
+	}
+
+	var payload struct {
+		Provider string `json:"provider"`
+		Results  []struct {
+			IssueID string  `json:"issue_id"`
+			Score   float64 `json:"score"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(run(), &payload); err != nil {
+		t.Fatalf("robot-search json decode: %v", err)
+	}
+	if payload.Provider != "hash" {
+		t.Fatalf("unexpected provider: %q", payload.Provider)
+	}
+	if len(payload.Results) == 0 || payload.Results[0].IssueID != "A" {
+		t.Fatalf("expected top match A, got %+v", payload.Results)
+	}
+
+	// A second run reuses the persisted index and must score identically.
+	var again struct {
+		Results []struct {
+			IssueID string  `json:"issue_id"`
+			Score   float64 `json:"score"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(run(), &again); err != nil {
+		t.Fatalf("robot-search json decode: %v", err)
+	}
+	if len(again.Results) != len(payload.Results) || again.Results[0].Score != payload.Results[0].Score {
+		t.Fatalf("hash embedder results changed between runs: %+v vs %+v", payload.Results, again.Results)
+	}
+}